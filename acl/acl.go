@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	iradix "github.com/hashicorp/go-immutable-radix"
+	glob "github.com/ryanuber/go-glob"
 )
 
 // ManagementACL is a singleton used for management tokens
@@ -44,6 +45,14 @@ type ACL struct {
 	// namespaces maps a namespace to a capabilitySet
 	namespaces *iradix.Tree
 
+	// variables maps a namespace to the capabilitySet of each variable path
+	// spec granted in that namespace
+	variables map[string]map[string]capabilitySet
+
+	// nodeClasses maps a node class to the policy granted on its nodes, in
+	// addition to the node policy
+	nodeClasses map[string]string
+
 	agent    string
 	node     string
 	operator string
@@ -73,12 +82,19 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	}
 
 	// Create the ACL object
-	acl := &ACL{}
+	acl := &ACL{
+		variables:   make(map[string]map[string]capabilitySet),
+		nodeClasses: make(map[string]string),
+	}
 	nsTxn := iradix.New().Txn()
 
 	for _, policy := range policies {
 	NAMESPACES:
 		for _, ns := range policy.Namespaces {
+			// Variable capabilities are tracked separately from the
+			// namespace capabilities
+			acl.addVariables(ns)
+
 			// Check for existing capabilities
 			var capabilities capabilitySet
 			raw, ok := nsTxn.Get([]byte(ns.Name))
@@ -103,6 +119,9 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 					continue NAMESPACES
 				}
 				capabilities.Set(cap)
+				for _, implied := range impliedNamespaceCapabilities[cap] {
+					capabilities.Set(implied)
+				}
 			}
 		}
 
@@ -112,6 +131,9 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 		}
 		if policy.Node != nil {
 			acl.node = maxPrivilege(acl.node, policy.Node.Policy)
+			for _, class := range policy.Node.Classes {
+				acl.nodeClasses[class.Name] = maxPrivilege(acl.nodeClasses[class.Name], class.Policy)
+			}
 		}
 		if policy.Operator != nil {
 			acl.operator = maxPrivilege(acl.operator, policy.Operator.Policy)
@@ -126,6 +148,46 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	return acl, nil
 }
 
+// addVariables merges the variable path capabilities of the namespace policy,
+// including those implied by its short hand policy, into the ACL.
+func (a *ACL) addVariables(ns *NamespacePolicy) {
+	paths, ok := a.variables[ns.Name]
+	if !ok {
+		paths = make(map[string]capabilitySet)
+		a.variables[ns.Name] = paths
+	}
+
+	add := func(pathSpec string, caps []string) {
+		capabilities, ok := paths[pathSpec]
+		if !ok {
+			capabilities = make(capabilitySet)
+			paths[pathSpec] = capabilities
+		}
+
+		// Deny always takes precedence
+		if capabilities.Check(VariablesCapabilityDeny) {
+			return
+		}
+		for _, cap := range caps {
+			if cap == VariablesCapabilityDeny {
+				capabilities.Clear()
+				capabilities.Set(VariablesCapabilityDeny)
+				return
+			}
+			capabilities.Set(cap)
+		}
+	}
+
+	if ns.Policy != "" {
+		add("*", expandVariablesPolicy(ns.Policy))
+	}
+	if ns.Variables != nil {
+		for _, path := range ns.Variables.Paths {
+			add(path.PathSpec, path.Capabilities)
+		}
+	}
+}
+
 // AllowNsOp is shorthand for AllowNamespaceOperation
 func (a *ACL) AllowNsOp(ns string, op string) bool {
 	return a.AllowNamespaceOperation(ns, op)
@@ -149,6 +211,53 @@ func (a *ACL) AllowNamespaceOperation(ns string, op string) bool {
 	return capabilities.Check(op)
 }
 
+// AllowVariableOperation checks if the given operation is allowed on the
+// variable at the given path. When several path specs match, the longest one
+// is used so that more specific rules override broader ones.
+func (a *ACL) AllowVariableOperation(ns, path, op string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	paths, ok := a.variables[ns]
+	if !ok {
+		return false
+	}
+
+	var match capabilitySet
+	var matchLen int
+	for pathSpec, capabilities := range paths {
+		if !glob.Glob(pathSpec, path) {
+			continue
+		}
+		if match == nil || len(pathSpec) > matchLen {
+			match = capabilities
+			matchLen = len(pathSpec)
+		}
+	}
+	if match == nil || match.Check(VariablesCapabilityDeny) {
+		return false
+	}
+	return match.Check(op)
+}
+
+// AllowVariableList checks if any variables in the namespace may be listed.
+// Individual paths must still be checked with AllowVariableOperation.
+func (a *ACL) AllowVariableList(ns string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	for _, capabilities := range a.variables[ns] {
+		if capabilities.Check(VariablesCapabilityList) {
+			return true
+		}
+	}
+	return false
+}
+
 // AllowNamespace checks if any operations are allowed for a namespace
 func (a *ACL) AllowNamespace(ns string) bool {
 	// Hot path management tokens
@@ -223,6 +332,65 @@ func (a *ACL) AllowNodeWrite() bool {
 	}
 }
 
+// nodeClassPolicy returns the policy granted on the nodes of the class. A
+// deny in either the node or the class policy takes precedence.
+func (a *ACL) nodeClassPolicy(class string) string {
+	return maxPrivilege(a.node, a.nodeClasses[class])
+}
+
+// AllowNodeClassRead checks if read operations are allowed for the nodes of
+// the given node class
+func (a *ACL) AllowNodeClassRead(class string) bool {
+	switch {
+	case a.management:
+		return true
+	default:
+		policy := a.nodeClassPolicy(class)
+		return policy == PolicyWrite || policy == PolicyRead
+	}
+}
+
+// AllowNodeClassWrite checks if write operations are allowed for the nodes of
+// the given node class
+func (a *ACL) AllowNodeClassWrite(class string) bool {
+	switch {
+	case a.management:
+		return true
+	default:
+		return a.nodeClassPolicy(class) == PolicyWrite
+	}
+}
+
+// AllowAnyNodeRead checks if read operations are allowed for the nodes of at
+// least one node class. Individual nodes must still be checked with
+// AllowNodeClassRead.
+func (a *ACL) AllowAnyNodeRead() bool {
+	if a.AllowNodeRead() {
+		return true
+	}
+	for class := range a.nodeClasses {
+		if a.AllowNodeClassRead(class) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowAnyNodeWrite checks if write operations are allowed for the nodes of
+// at least one node class. Individual nodes must still be checked with
+// AllowNodeClassWrite.
+func (a *ACL) AllowAnyNodeWrite() bool {
+	if a.AllowNodeWrite() {
+		return true
+	}
+	for class := range a.nodeClasses {
+		if a.AllowNodeClassWrite(class) {
+			return true
+		}
+	}
+	return false
+}
+
 // AllowOperatorRead checks if read operations are allowed for a operator
 func (a *ACL) AllowOperatorRead() bool {
 	switch {
@@ -262,3 +262,141 @@ func TestAllowNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestAllowVariableOperation(t *testing.T) {
+	policy := `
+namespace "default" {
+	policy = "read"
+	variables {
+		path "app/*" {
+			capabilities = ["write", "read", "list"]
+		}
+		path "app/secret" {
+			capabilities = ["deny"]
+		}
+	}
+}
+namespace "other" {
+	variables {
+		path "nomad/jobs/*" {
+			capabilities = ["list"]
+		}
+	}
+}
+`
+	tests := []struct {
+		Namespace string
+		Path      string
+		Op        string
+		Allow     bool
+	}{
+		{"default", "foo", VariablesCapabilityRead, true},
+		{"default", "foo", VariablesCapabilityWrite, false},
+		{"default", "app/db", VariablesCapabilityWrite, true},
+		{"default", "app/db", VariablesCapabilityDestroy, false},
+		{"default", "app/secret", VariablesCapabilityRead, false},
+		{"other", "nomad/jobs/example", VariablesCapabilityList, true},
+		{"other", "nomad/jobs/example", VariablesCapabilityRead, false},
+		{"other", "app/db", VariablesCapabilityList, false},
+		{"missing", "app/db", VariablesCapabilityRead, false},
+	}
+
+	p, err := Parse(policy)
+	assert.Nil(t, err)
+	acl, err := NewACL(false, []*Policy{p})
+	assert.Nil(t, err)
+
+	for _, tc := range tests {
+		t.Run(tc.Namespace+"/"+tc.Path+"/"+tc.Op, func(t *testing.T) {
+			assert.Equal(t, tc.Allow, acl.AllowVariableOperation(tc.Namespace, tc.Path, tc.Op))
+		})
+	}
+
+	assert.True(t, acl.AllowVariableList("default"))
+	assert.True(t, acl.AllowVariableList("other"))
+	assert.False(t, acl.AllowVariableList("missing"))
+	assert.True(t, ManagementACL.AllowVariableOperation("default", "app/secret", VariablesCapabilityRead))
+}
+
+func TestAllowNodeClass(t *testing.T) {
+	tests := []struct {
+		Policy     string
+		Class      string
+		Read       bool
+		Write      bool
+		AnyRead    bool
+		AnyWrite   bool
+		GlobalRead bool
+	}{
+		{
+			Policy: `node { policy = "read" }`,
+			Class:  "batch",
+			Read:   true, AnyRead: true, GlobalRead: true,
+		},
+		{
+			Policy: `node { class "batch" { policy = "write" } }`,
+			Class:  "batch",
+			Read:   true, Write: true, AnyRead: true, AnyWrite: true,
+		},
+		{
+			Policy:  `node { class "batch" { policy = "write" } }`,
+			Class:   "system",
+			AnyRead: true, AnyWrite: true,
+		},
+		{
+			Policy: `node {
+				policy = "read"
+				class "batch" { policy = "write" }
+			}`,
+			Class: "batch",
+			Read:  true, Write: true, AnyRead: true, AnyWrite: true, GlobalRead: true,
+		},
+		{
+			Policy: `node {
+				policy = "write"
+				class "system" { policy = "deny" }
+			}`,
+			Class:   "system",
+			AnyRead: true, AnyWrite: true, GlobalRead: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Policy+"/"+tc.Class, func(t *testing.T) {
+			assert := assert.New(t)
+
+			policy, err := Parse(tc.Policy)
+			assert.Nil(err)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			assert.Nil(err)
+
+			assert.Equal(tc.Read, acl.AllowNodeClassRead(tc.Class))
+			assert.Equal(tc.Write, acl.AllowNodeClassWrite(tc.Class))
+			assert.Equal(tc.AnyRead, acl.AllowAnyNodeRead())
+			assert.Equal(tc.AnyWrite, acl.AllowAnyNodeWrite())
+			assert.Equal(tc.GlobalRead, acl.AllowNodeRead())
+		})
+	}
+}
+
+func TestImpliedNamespaceCapabilities(t *testing.T) {
+	assert := assert.New(t)
+
+	policy, err := Parse(`namespace "default" { capabilities = ["read-job"] }`)
+	assert.Nil(err)
+	acl, err := NewACL(false, []*Policy{policy})
+	assert.Nil(err)
+
+	// Reading a job includes reading its status
+	assert.True(acl.AllowNsOp("default", NamespaceCapabilityReadJob))
+	assert.True(acl.AllowNsOp("default", NamespaceCapabilityReadJobStatus))
+
+	// The job status doesn't include the job
+	policy, err = Parse(`namespace "default" { capabilities = ["read-job-status"] }`)
+	assert.Nil(err)
+	acl, err = NewACL(false, []*Policy{policy})
+	assert.Nil(err)
+	assert.True(acl.AllowNsOp("default", NamespaceCapabilityReadJobStatus))
+	assert.False(acl.AllowNsOp("default", NamespaceCapabilityReadJob))
+}
@@ -24,13 +24,39 @@ const (
 	NamespaceCapabilityDeny             = "deny"
 	NamespaceCapabilityListJobs         = "list-jobs"
 	NamespaceCapabilityReadJob          = "read-job"
+	NamespaceCapabilityReadJobStatus    = "read-job-status"
 	NamespaceCapabilitySubmitJob        = "submit-job"
 	NamespaceCapabilityDispatchJob      = "dispatch-job"
 	NamespaceCapabilityReadLogs         = "read-logs"
 	NamespaceCapabilityReadFS           = "read-fs"
+	NamespaceCapabilityAllocLifecycle   = "alloc-lifecycle"
+	NamespaceCapabilityAllocSignal      = "alloc-signal"
+	NamespaceCapabilityAllocExec        = "alloc-exec"
+	NamespaceCapabilityCSIListVolume    = "csi-list-volume"
+	NamespaceCapabilityCSIReadVolume    = "csi-read-volume"
+	NamespaceCapabilityCSIWriteVolume   = "csi-write-volume"
+	NamespaceCapabilityCSIMountVolume   = "csi-mount-volume"
 	NamespaceCapabilitySentinelOverride = "sentinel-override"
 )
 
+// impliedNamespaceCapabilities are the capabilities granted along with a
+// capability whose operations are a superset of theirs. Reading a job
+// includes reading its status.
+var impliedNamespaceCapabilities = map[string][]string{
+	NamespaceCapabilityReadJob: {NamespaceCapabilityReadJobStatus},
+}
+
+const (
+	// The following are the capabilities that can be granted on variable
+	// paths within a namespace. A path policy with the deny capability takes
+	// precedence over all other capabilities on that path.
+	VariablesCapabilityList    = "list"
+	VariablesCapabilityRead    = "read"
+	VariablesCapabilityWrite   = "write"
+	VariablesCapabilityDestroy = "destroy"
+	VariablesCapabilityDeny    = "deny"
+)
+
 var (
 	validNamespace = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
 )
@@ -60,13 +86,35 @@ type NamespacePolicy struct {
 	Name         string `hcl:",key"`
 	Policy       string
 	Capabilities []string
+	Variables    *VariablesPolicy `hcl:"variables"`
+}
+
+// VariablesPolicy is the policy for the variables of a namespace
+type VariablesPolicy struct {
+	Paths []*VariablesPathPolicy `hcl:"path,expand"`
+}
+
+// VariablesPathPolicy grants capabilities on the variables whose path matches
+// the PathSpec glob
+type VariablesPathPolicy struct {
+	PathSpec     string `hcl:",key"`
+	Capabilities []string
 }
 
 type AgentPolicy struct {
 	Policy string
 }
 
+// NodePolicy is the policy for the client nodes. The class policies grant
+// additional privileges on the nodes of the given node class.
 type NodePolicy struct {
+	Policy  string
+	Classes []*NodeClassPolicy `hcl:"class,expand"`
+}
+
+// NodeClassPolicy is the policy for the client nodes of a node class
+type NodeClassPolicy struct {
+	Name   string `hcl:",key"`
 	Policy string
 }
 
@@ -92,8 +140,10 @@ func isPolicyValid(policy string) bool {
 func isNamespaceCapabilityValid(cap string) bool {
 	switch cap {
 	case NamespaceCapabilityDeny, NamespaceCapabilityListJobs, NamespaceCapabilityReadJob,
-		NamespaceCapabilitySubmitJob, NamespaceCapabilityDispatchJob, NamespaceCapabilityReadLogs,
-		NamespaceCapabilityReadFS:
+		NamespaceCapabilityReadJobStatus, NamespaceCapabilitySubmitJob, NamespaceCapabilityDispatchJob,
+		NamespaceCapabilityReadLogs, NamespaceCapabilityReadFS, NamespaceCapabilityAllocLifecycle,
+		NamespaceCapabilityAllocSignal, NamespaceCapabilityAllocExec, NamespaceCapabilityCSIListVolume, NamespaceCapabilityCSIReadVolume,
+		NamespaceCapabilityCSIWriteVolume, NamespaceCapabilityCSIMountVolume:
 		return true
 	// Separate the enterprise-only capabilities
 	case NamespaceCapabilitySentinelOverride:
@@ -103,6 +153,41 @@ func isNamespaceCapabilityValid(cap string) bool {
 	}
 }
 
+// isVariablesCapabilityValid ensures the given capability is valid for a
+// variables path policy
+func isVariablesCapabilityValid(cap string) bool {
+	switch cap {
+	case VariablesCapabilityList, VariablesCapabilityRead, VariablesCapabilityWrite,
+		VariablesCapabilityDestroy, VariablesCapabilityDeny:
+		return true
+	default:
+		return false
+	}
+}
+
+// expandVariablesPolicy provides the equivalent set of variables
+// capabilities for a namespace policy. They are granted on all paths.
+func expandVariablesPolicy(policy string) []string {
+	switch policy {
+	case PolicyDeny:
+		return []string{VariablesCapabilityDeny}
+	case PolicyRead:
+		return []string{
+			VariablesCapabilityList,
+			VariablesCapabilityRead,
+		}
+	case PolicyWrite:
+		return []string{
+			VariablesCapabilityList,
+			VariablesCapabilityRead,
+			VariablesCapabilityWrite,
+			VariablesCapabilityDestroy,
+		}
+	default:
+		return nil
+	}
+}
+
 // expandNamespacePolicy provides the equivalent set of capabilities for
 // a namespace policy
 func expandNamespacePolicy(policy string) []string {
@@ -113,15 +198,26 @@ func expandNamespacePolicy(policy string) []string {
 		return []string{
 			NamespaceCapabilityListJobs,
 			NamespaceCapabilityReadJob,
+			NamespaceCapabilityReadJobStatus,
+			NamespaceCapabilityCSIListVolume,
+			NamespaceCapabilityCSIReadVolume,
 		}
 	case PolicyWrite:
 		return []string{
 			NamespaceCapabilityListJobs,
 			NamespaceCapabilityReadJob,
+			NamespaceCapabilityReadJobStatus,
 			NamespaceCapabilitySubmitJob,
 			NamespaceCapabilityDispatchJob,
 			NamespaceCapabilityReadLogs,
 			NamespaceCapabilityReadFS,
+			NamespaceCapabilityAllocLifecycle,
+			NamespaceCapabilityAllocSignal,
+			NamespaceCapabilityAllocExec,
+			NamespaceCapabilityCSIListVolume,
+			NamespaceCapabilityCSIReadVolume,
+			NamespaceCapabilityCSIWriteVolume,
+			NamespaceCapabilityCSIMountVolume,
 		}
 	default:
 		return nil
@@ -164,6 +260,19 @@ func Parse(rules string) (*Policy, error) {
 			}
 		}
 
+		if ns.Variables != nil {
+			for _, path := range ns.Variables.Paths {
+				if path.PathSpec == "" {
+					return nil, fmt.Errorf("Invalid variable path: %#v", ns)
+				}
+				for _, cap := range path.Capabilities {
+					if !isVariablesCapabilityValid(cap) {
+						return nil, fmt.Errorf("Invalid variable capability '%s': %#v", cap, path)
+					}
+				}
+			}
+		}
+
 		// Expand the short hand policy to the capabilities and
 		// add to any existing capabilities
 		if ns.Policy != "" {
@@ -176,8 +285,22 @@ func Parse(rules string) (*Policy, error) {
 		return nil, fmt.Errorf("Invalid agent policy: %#v", p.Agent)
 	}
 
-	if p.Node != nil && !isPolicyValid(p.Node.Policy) {
-		return nil, fmt.Errorf("Invalid node policy: %#v", p.Node)
+	if p.Node != nil {
+		// The node policy may be omitted if only class policies are given
+		if p.Node.Policy != "" && !isPolicyValid(p.Node.Policy) {
+			return nil, fmt.Errorf("Invalid node policy: %#v", p.Node)
+		}
+		if p.Node.Policy == "" && len(p.Node.Classes) == 0 {
+			return nil, fmt.Errorf("Invalid node policy: %#v", p.Node)
+		}
+		for _, class := range p.Node.Classes {
+			if class.Name == "" {
+				return nil, fmt.Errorf("Invalid node class name: %#v", class)
+			}
+			if !isPolicyValid(class.Policy) {
+				return nil, fmt.Errorf("Invalid node class policy: %#v", class)
+			}
+		}
 	}
 
 	if p.Operator != nil && !isPolicyValid(p.Operator.Policy) {
@@ -30,6 +30,9 @@ func TestParse(t *testing.T) {
 						Capabilities: []string{
 							NamespaceCapabilityListJobs,
 							NamespaceCapabilityReadJob,
+							NamespaceCapabilityReadJobStatus,
+							NamespaceCapabilityCSIListVolume,
+							NamespaceCapabilityCSIReadVolume,
 						},
 					},
 				},
@@ -68,6 +71,9 @@ func TestParse(t *testing.T) {
 						Capabilities: []string{
 							NamespaceCapabilityListJobs,
 							NamespaceCapabilityReadJob,
+							NamespaceCapabilityReadJobStatus,
+							NamespaceCapabilityCSIListVolume,
+							NamespaceCapabilityCSIReadVolume,
 						},
 					},
 					{
@@ -76,10 +82,18 @@ func TestParse(t *testing.T) {
 						Capabilities: []string{
 							NamespaceCapabilityListJobs,
 							NamespaceCapabilityReadJob,
+							NamespaceCapabilityReadJobStatus,
 							NamespaceCapabilitySubmitJob,
 							NamespaceCapabilityDispatchJob,
 							NamespaceCapabilityReadLogs,
 							NamespaceCapabilityReadFS,
+							NamespaceCapabilityAllocLifecycle,
+							NamespaceCapabilityAllocSignal,
+							NamespaceCapabilityAllocExec,
+							NamespaceCapabilityCSIListVolume,
+							NamespaceCapabilityCSIReadVolume,
+							NamespaceCapabilityCSIWriteVolume,
+							NamespaceCapabilityCSIMountVolume,
 						},
 					},
 					{
@@ -140,6 +154,58 @@ func TestParse(t *testing.T) {
 			"Invalid node policy",
 			nil,
 		},
+		{
+			`
+			namespace "default" {
+				capabilities = ["read-job-status", "alloc-lifecycle", "alloc-signal", "alloc-exec"]
+			}
+			node {
+				class "batch" {
+					policy = "write"
+				}
+				class "system" {
+					policy = "deny"
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Capabilities: []string{
+							NamespaceCapabilityReadJobStatus,
+							NamespaceCapabilityAllocLifecycle,
+							NamespaceCapabilityAllocSignal,
+							NamespaceCapabilityAllocExec,
+						},
+					},
+				},
+				Node: &NodePolicy{
+					Classes: []*NodeClassPolicy{
+						{
+							Name:   "batch",
+							Policy: PolicyWrite,
+						},
+						{
+							Name:   "system",
+							Policy: PolicyDeny,
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			node {
+				class "batch" {
+					policy = "foo"
+				}
+			}
+			`,
+			"Invalid node class policy",
+			nil,
+		},
 		{
 			`
 			operator {
@@ -197,6 +263,58 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "app/*" {
+						capabilities = ["read", "list"]
+					}
+					path "app/secret" {
+						capabilities = ["deny"]
+					}
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Variables: &VariablesPolicy{
+							Paths: []*VariablesPathPolicy{
+								{
+									PathSpec: "app/*",
+									Capabilities: []string{
+										VariablesCapabilityRead,
+										VariablesCapabilityList,
+									},
+								},
+								{
+									PathSpec: "app/secret",
+									Capabilities: []string{
+										VariablesCapabilityDeny,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "app/*" {
+						capabilities = ["read", "submit-job"]
+					}
+				}
+			}
+			`,
+			"Invalid variable capability",
+			nil,
+		},
 	}
 
 	for idx, tc := range tcases {
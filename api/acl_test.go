@@ -2,6 +2,7 @@ package api
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -109,6 +110,182 @@ func TestACLPolicies_Info(t *testing.T) {
 	assert.Equal(t, policy.Name, out.Name)
 }
 
+func TestACLRoles_ListUpsert(t *testing.T) {
+	t.Parallel()
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	ar := c.ACLRoles()
+
+	// Listing when nothing exists returns empty
+	result, qm, err := ar.List(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if qm.LastIndex != 1 {
+		t.Fatalf("bad index: %d", qm.LastIndex)
+	}
+	if n := len(result); n != 0 {
+		t.Fatalf("expected 0 roles, got: %d", n)
+	}
+
+	// Register a role
+	role := &ACLRole{
+		Name:        "test",
+		Description: "test",
+		Policies:    []string{"foo", "bar"},
+	}
+	wm, err := ar.Upsert(role, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	// Check the list again
+	result, qm, err = ar.List(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	assertQueryMeta(t, qm)
+	if len(result) != 1 {
+		t.Fatalf("expected role, got: %#v", result)
+	}
+	assert.Equal(t, role.Policies, result[0].Policies)
+}
+
+func TestACLRoles_InfoDelete(t *testing.T) {
+	t.Parallel()
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	ar := c.ACLRoles()
+
+	// Register a role
+	role := &ACLRole{
+		Name:     "test",
+		Policies: []string{"foo"},
+	}
+	wm, err := ar.Upsert(role, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	// Query the role
+	out, qm, err := ar.Info(role.Name, nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Equal(t, role.Name, out.Name)
+
+	// Delete the role
+	wm, err = ar.Delete(role.Name, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	// Check the list again
+	result, _, err := ar.List(nil)
+	assert.Nil(t, err)
+	assert.Len(t, result, 0)
+}
+
+func TestACLAuthMethods_CRUD(t *testing.T) {
+	t.Parallel()
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	am := c.ACLAuthMethods()
+
+	// Register an auth method
+	method := &ACLAuthMethod{
+		Name:        "example",
+		Type:        ACLAuthMethodTypeOIDC,
+		MaxTokenTTL: time.Hour,
+		Config: &ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "https://example.com",
+			OIDCClientID:        "nomad",
+			OIDCClientSecret:    "secret",
+			AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+		},
+	}
+	wm, err := am.Upsert(method, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	// Check the list
+	result, qm, err := am.List(nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Len(t, result, 1)
+	assert.Equal(t, ACLAuthMethodTypeOIDC, result[0].Type)
+
+	// Query the auth method
+	out, qm, err := am.Info(method.Name, nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Equal(t, ACLAuthMethodTokenLocalityLocal, out.TokenLocality)
+	assert.Equal(t, method.Config.OIDCClientID, out.Config.OIDCClientID)
+
+	// Delete the auth method
+	wm, err = am.Delete(method.Name, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	result, _, err = am.List(nil)
+	assert.Nil(t, err)
+	assert.Len(t, result, 0)
+}
+
+func TestACLBindingRules_CRUD(t *testing.T) {
+	t.Parallel()
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	br := c.ACLBindingRules()
+
+	method := &ACLAuthMethod{
+		Name:        "example",
+		Type:        ACLAuthMethodTypeJWT,
+		MaxTokenTTL: time.Hour,
+		Config: &ACLAuthMethodConfig{
+			JWKSURL: "https://example.com/keys",
+		},
+	}
+	_, err := c.ACLAuthMethods().Upsert(method, nil)
+	assert.Nil(t, err)
+
+	// Create a binding rule
+	rule := &ACLBindingRule{
+		AuthMethod: method.Name,
+		Selector:   `"engineering" in list.groups`,
+		BindType:   ACLBindingRuleBindTypeRole,
+		BindName:   "engineering",
+	}
+	out, wm, err := br.Create(rule, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+	assert.NotEmpty(t, out.ID)
+
+	// Update it
+	out.Description = "engineers"
+	out, wm, err = br.Update(out, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+	assert.Equal(t, "engineers", out.Description)
+
+	// List the rules of the auth method
+	result, qm, err := br.ListByAuthMethod(method.Name, nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Len(t, result, 1)
+
+	// Query the rule
+	info, qm, err := br.Info(out.ID, nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Equal(t, rule.Selector, info.Selector)
+
+	// Delete the rule
+	wm, err = br.Delete(out.ID, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	result, _, err = br.List(nil)
+	assert.Nil(t, err)
+	assert.Len(t, result, 0)
+}
+
 func TestACLTokens_List(t *testing.T) {
 	t.Parallel()
 	c, s, _ := makeACLClient(t, nil, nil)
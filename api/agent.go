@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"time"
 )
 
 // Agent encapsulates an API client which talks to Nomad's
@@ -252,9 +253,23 @@ type ServerMembers struct {
 }
 
 type AgentSelf struct {
-	Config map[string]interface{}       `json:"config"`
-	Member AgentMember                  `json:"member"`
-	Stats  map[string]map[string]string `json:"stats"`
+	Config  map[string]interface{}       `json:"config"`
+	Member  AgentMember                  `json:"member"`
+	Stats   map[string]map[string]string `json:"stats"`
+	Plugins []*AgentPluginStatus         `json:"plugins"`
+}
+
+// AgentPluginStatus is the status of a plugin launched by a client agent
+type AgentPluginStatus struct {
+	Name              string
+	Type              string
+	PluginVersion     string
+	ApiVersion        string
+	Internal          bool
+	Healthy           bool
+	HealthDescription string
+	Restarts          int
+	UpdateTime        time.Time
 }
 
 // AgentMember represents a cluster member known to the agent
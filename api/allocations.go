@@ -65,6 +65,60 @@ func (a *Allocations) GC(alloc *Allocation, q *QueryOptions) error {
 	return err
 }
 
+// Restart restarts the tasks of an allocation. If taskName is empty, all
+// running tasks of the allocation are restarted.
+func (a *Allocations) Restart(alloc *Allocation, taskName string, w *WriteOptions) error {
+	req := AllocationRestartRequest{
+		Task: taskName,
+	}
+
+	_, err := a.client.write("/v1/client/allocation/"+alloc.ID+"/restart", &req, nil, w)
+	return err
+}
+
+// Stop stops an allocation. The allocation is migrated and replaced by the
+// scheduler.
+func (a *Allocations) Stop(alloc *Allocation, w *WriteOptions) (*AllocStopResponse, error) {
+	var resp AllocStopResponse
+	wm, err := a.client.write("/v1/allocation/"+alloc.ID+"/stop", nil, &resp, w)
+	if err != nil {
+		return nil, err
+	}
+	resp.WriteMeta = *wm
+	return &resp, nil
+}
+
+// Signal sends a signal to the tasks of an allocation. If task is empty, all
+// running tasks of the allocation are signaled.
+func (a *Allocations) Signal(alloc *Allocation, task, signal string, w *WriteOptions) error {
+	req := AllocSignalRequest{
+		Signal: signal,
+		Task:   task,
+	}
+
+	_, err := a.client.write("/v1/client/allocation/"+alloc.ID+"/signal", &req, nil, w)
+	return err
+}
+
+// AllocationRestartRequest is used to restart the tasks of an allocation.
+type AllocationRestartRequest struct {
+	Task string
+}
+
+// AllocSignalRequest is used to signal the tasks of an allocation.
+type AllocSignalRequest struct {
+	Task   string
+	Signal string
+}
+
+// AllocStopResponse is the response to stopping an allocation.
+type AllocStopResponse struct {
+	// EvalID is the id of the follow up evaluation for the rescheduled alloc.
+	EvalID string
+
+	WriteMeta
+}
+
 // Allocation is used for serialization of allocations.
 type Allocation struct {
 	ID                 string
@@ -220,6 +274,9 @@ type DesiredTransition struct {
 	// Reschedule is used to indicate that this allocation is eligible to be
 	// rescheduled.
 	Reschedule *bool
+
+	// Reason is a human readable reason for the transition.
+	Reason string
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
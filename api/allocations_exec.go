@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// ExecStreamingIOOperation is a chunk of the stdin, stdout or stderr of an
+// interactive command. Close marks the end of the stream.
+type ExecStreamingIOOperation struct {
+	Data  []byte `json:"data,omitempty"`
+	Close bool   `json:"close,omitempty"`
+}
+
+// TerminalSize is the size of the terminal of an interactive command
+type TerminalSize struct {
+	Height int `json:"height"`
+	Width  int `json:"width"`
+}
+
+// ExecStreamingInput is sent to an interactive command. It carries either
+// input or a new terminal size.
+type ExecStreamingInput struct {
+	Stdin   *ExecStreamingIOOperation `json:"stdin,omitempty"`
+	TTYSize *TerminalSize             `json:"tty_size,omitempty"`
+}
+
+// ExecStreamingExitResult is the result of an interactive command
+type ExecStreamingExitResult struct {
+	ExitCode int `json:"exit_code"`
+}
+
+// ExecStreamingOutput is sent by an interactive command. It carries either
+// output or, once the command exited, its result.
+type ExecStreamingOutput struct {
+	Stdout *ExecStreamingIOOperation `json:"stdout,omitempty"`
+	Stderr *ExecStreamingIOOperation `json:"stderr,omitempty"`
+
+	Exited bool                     `json:"exited,omitempty"`
+	Result *ExecStreamingExitResult `json:"result,omitempty"`
+}
+
+// Exec is used to run an interactive command inside a task of an allocation.
+// Stdin is forwarded to the command until it returns EOF and new terminal
+// sizes received on terminalSizeCh are applied to the terminal of the
+// command. Exec blocks until the command exits and returns its exit code.
+func (a *Allocations) Exec(ctx context.Context,
+	alloc *Allocation, task string, tty bool, command []string,
+	stdin io.Reader, stdout, stderr io.Writer,
+	terminalSizeCh <-chan TerminalSize, q *QueryOptions) (exitCode int, err error) {
+
+	commandBytes, err := json.Marshal(command)
+	if err != nil {
+		return -1, fmt.Errorf("failed to marshal command: %v", err)
+	}
+
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+	q.Params["task"] = task
+	q.Params["tty"] = fmt.Sprintf("%v", tty)
+	q.Params["command"] = string(commandBytes)
+
+	path := fmt.Sprintf("/v1/client/allocation/%s/exec", url.PathEscape(alloc.ID))
+	conn, _, err := a.client.websocket(path, q)
+	if err != nil {
+		return -1, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Close the connection to unblock reading once the context is done
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	// The connection doesn't support concurrent writers
+	var sendLock sync.Mutex
+	send := func(v *ExecStreamingInput) error {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		return conn.WriteJSON(v)
+	}
+
+	errCh := make(chan error, 2)
+
+	// Forward stdin
+	go func() {
+		buf := make([]byte, 2048)
+		for {
+			n, err := stdin.Read(buf)
+			if n > 0 {
+				input := &ExecStreamingInput{
+					Stdin: &ExecStreamingIOOperation{Data: buf[:n]},
+				}
+				if err := send(input); err != nil {
+					return
+				}
+			}
+
+			if err == io.EOF {
+				send(&ExecStreamingInput{Stdin: &ExecStreamingIOOperation{Close: true}})
+				return
+			} else if err != nil {
+				errCh <- fmt.Errorf("failed to read stdin: %v", err)
+				cancel()
+				return
+			}
+		}
+	}()
+
+	// Forward terminal resizes
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case size, ok := <-terminalSizeCh:
+				if !ok {
+					return
+				}
+				if err := send(&ExecStreamingInput{TTYSize: &size}); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		var frame ExecStreamingOutput
+		if err := conn.ReadJSON(&frame); err != nil {
+			select {
+			case err := <-errCh:
+				return -1, err
+			default:
+			}
+
+			if ctx.Err() != nil {
+				return -1, ctx.Err()
+			}
+			if closeErr, ok := err.(*websocket.CloseError); ok {
+				if closeErr.Text != "" {
+					return -1, errors.New(closeErr.Text)
+				}
+				return -1, errors.New("connection closed before the command exited")
+			}
+			return -1, err
+		}
+
+		switch {
+		case frame.Stdout != nil:
+			if len(frame.Stdout.Data) != 0 {
+				if _, err := stdout.Write(frame.Stdout.Data); err != nil {
+					return -1, err
+				}
+			}
+		case frame.Stderr != nil:
+			if len(frame.Stderr.Data) != 0 {
+				if _, err := stderr.Write(frame.Stderr.Data); err != nil {
+					return -1, err
+				}
+			}
+		case frame.Exited && frame.Result != nil:
+			return frame.Result.ExitCode, nil
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
@@ -15,6 +16,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/hashicorp/go-cleanhttp"
 	rootcerts "github.com/hashicorp/go-rootcerts"
 )
@@ -24,6 +26,16 @@ var (
 	// client directly before switching to a connection through the Nomad
 	// server.
 	ClientConnTimeout = 1 * time.Second
+
+	// RateLimitMaxRetries is the number of times a request rejected by the
+	// rate limits of the servers is retried before the error is returned.
+	RateLimitMaxRetries = 5
+
+	// RateLimitBaseBackoff is the time waited before retrying a rate limited
+	// request for the first time. The wait doubles with each retry, up to
+	// RateLimitMaxBackoff.
+	RateLimitBaseBackoff = 250 * time.Millisecond
+	RateLimitMaxBackoff  = 5 * time.Second
 )
 
 // QueryOptions are used to parameterize a query
@@ -604,8 +616,48 @@ func (m *multiCloser) Read(p []byte) (int, error) {
 	return m.reader.Read(p)
 }
 
-// doRequest runs a request with our client
+// doRequest runs a request with our client, retrying it with backoff while
+// the servers are rate limiting it.
 func (c *Client) doRequest(r *request) (time.Duration, *http.Response, error) {
+	// Bodies given as a reader can not be replayed
+	retryable := r.body == nil
+
+	var elapsed time.Duration
+	for attempt := 0; ; attempt++ {
+		diff, resp, err := c.doRequestOnce(r)
+		elapsed += diff
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests ||
+			!retryable || attempt >= RateLimitMaxRetries {
+			return elapsed, resp, err
+		}
+
+		wait := rateLimitBackoff(attempt, resp.Header.Get("Retry-After"))
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		time.Sleep(wait)
+		elapsed += wait
+
+		// Encode the body again for the next attempt
+		r.body = nil
+	}
+}
+
+// rateLimitBackoff returns the time to wait before retrying a rate limited
+// request, honouring the Retry-After header if the server sent one.
+func rateLimitBackoff(attempt int, retryAfter string) time.Duration {
+	if secs, err := strconv.Atoi(retryAfter); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+
+	wait := RateLimitBaseBackoff << uint(attempt)
+	if wait <= 0 || wait > RateLimitMaxBackoff {
+		wait = RateLimitMaxBackoff
+	}
+	return wait
+}
+
+// doRequestOnce runs a single attempt of a request
+func (c *Client) doRequestOnce(r *request) (time.Duration, *http.Response, error) {
 	req, err := r.toHTTP()
 	if err != nil {
 		return 0, nil, err
@@ -655,6 +707,54 @@ func (c *Client) rawQuery(endpoint string, q *QueryOptions) (io.ReadCloser, erro
 	return resp.Body, nil
 }
 
+// websocket makes a websocket request to the specific endpoint
+func (c *Client) websocket(endpoint string, q *QueryOptions) (*websocket.Conn, *http.Response, error) {
+	transport, ok := c.config.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported transport")
+	}
+	dialer := websocket.Dialer{
+		ReadBufferSize:   4096,
+		WriteBufferSize:  4096,
+		HandshakeTimeout: c.config.httpClient.Timeout,
+		Proxy:            transport.Proxy,
+		TLSClientConfig:  transport.TLSClientConfig,
+	}
+
+	r, err := c.newRequest("GET", endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.setQueryOptions(q)
+
+	rhttp, err := r.toHTTP()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The dialer sets its own encoding related headers
+	rhttp.Header.Del("Accept-Encoding")
+
+	switch rhttp.URL.Scheme {
+	case "https":
+		rhttp.URL.Scheme = "wss"
+	default:
+		rhttp.URL.Scheme = "ws"
+	}
+
+	conn, resp, err := dialer.Dial(rhttp.URL.String(), rhttp.Header)
+
+	// Surface the error returned by the agent rather than the failed handshake
+	if err == websocket.ErrBadHandshake && resp != nil {
+		var buf bytes.Buffer
+		io.Copy(&buf, resp.Body)
+		resp.Body.Close()
+		err = fmt.Errorf("Unexpected response code: %d (%s)", resp.StatusCode, buf.Bytes())
+	}
+
+	return conn, resp, err
+}
+
 // query is used to do a GET request against an endpoint
 // and deserialize the response into an interface using
 // standard Nomad conventions.
@@ -435,3 +435,63 @@ func TestClient_NodeClient(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_RateLimitRetry(t *testing.T) {
+	oldBackoff := RateLimitBaseBackoff
+	RateLimitBaseBackoff = 10 * time.Millisecond
+	defer func() { RateLimitBaseBackoff = oldBackoff }()
+
+	// A server that rate limits the first two attempts of each request
+	var attempts int
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body["Name"])
+		if attempts%3 != 0 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte("RPC rate limit exceeded"))
+			return
+		}
+		w.Header().Set("X-Nomad-Index", "10")
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	conf := DefaultConfig()
+	conf.Address = ts.URL
+	client, err := NewClient(conf)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Writes are retried with their body
+	var out struct{}
+	wm, err := client.write("/v1/test", map[string]string{"Name": "foo"}, &out, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if wm.LastIndex != 10 {
+		t.Fatalf("bad index: %d", wm.LastIndex)
+	}
+	assert.Equal(t, []string{"foo", "foo", "foo"}, bodies)
+
+	// Requests are failed once the retries are exhausted
+	attempts = 1
+	oldRetries := RateLimitMaxRetries
+	RateLimitMaxRetries = 0
+	defer func() { RateLimitMaxRetries = oldRetries }()
+	_, err = client.query("/v1/test", &out, nil)
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected rate limit error, got: %v", err)
+	}
+}
+
+func TestRateLimitBackoff(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(RateLimitBaseBackoff, rateLimitBackoff(0, ""))
+	assert.Equal(2*RateLimitBaseBackoff, rateLimitBackoff(1, ""))
+	assert.Equal(RateLimitMaxBackoff, rateLimitBackoff(30, ""))
+	assert.Equal(3*time.Second, rateLimitBackoff(0, "3"))
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// CSIPluginTypeController, CSIPluginTypeNode and CSIPluginTypeMonolith
+	// are the types of CSI plugins tasks can run.
+	CSIPluginTypeController = "controller"
+	CSIPluginTypeNode       = "node"
+	CSIPluginTypeMonolith   = "monolith"
+
+	// CSIVolumeAccessMode* are the modes volumes can be claimed with.
+	CSIVolumeAccessModeSingleNodeReader      = "single-node-reader-only"
+	CSIVolumeAccessModeSingleNodeWriter      = "single-node-writer"
+	CSIVolumeAccessModeMultiNodeReader       = "multi-node-reader-only"
+	CSIVolumeAccessModeMultiNodeSingleWriter = "multi-node-single-writer"
+	CSIVolumeAccessModeMultiNodeMultiWriter  = "multi-node-multi-writer"
+
+	// CSIVolumeAttachmentMode* are the ways volumes are attached to tasks.
+	CSIVolumeAttachmentModeBlockDevice = "block-device"
+	CSIVolumeAttachmentModeFilesystem  = "file-system"
+)
+
+// CSIVolumes is used to query the CSI volume endpoints.
+type CSIVolumes struct {
+	client *Client
+}
+
+// CSIVolumes returns a handle on the CSI volumes.
+func (c *Client) CSIVolumes() *CSIVolumes {
+	return &CSIVolumes{client: c}
+}
+
+// List is used to list the volumes of the namespace.
+func (v *CSIVolumes) List(q *QueryOptions) ([]*CSIVolumeListStub, *QueryMeta, error) {
+	var resp []*CSIVolumeListStub
+	qm, err := v.client.query("/v1/volumes?type=csi", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Sort(CSIVolumeIndexSort(resp))
+	return resp, qm, nil
+}
+
+// PluginList is used to list the volumes provided by a plugin.
+func (v *CSIVolumes) PluginList(pluginID string) ([]*CSIVolumeListStub, *QueryMeta, error) {
+	return v.List(&QueryOptions{Params: map[string]string{"plugin_id": pluginID}})
+}
+
+// Info is used to query a single volume by its ID.
+func (v *CSIVolumes) Info(id string, q *QueryOptions) (*CSIVolume, *QueryMeta, error) {
+	var resp CSIVolume
+	qm, err := v.client.query("/v1/volume/csi/"+id, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Register is used to register a volume provided by a CSI plugin.
+func (v *CSIVolumes) Register(vol *CSIVolume, w *WriteOptions) (*WriteMeta, error) {
+	req := CSIVolumeRegisterRequest{
+		Volumes: []*CSIVolume{vol},
+	}
+	return v.client.write("/v1/volume/csi/"+vol.ID, req, nil, w)
+}
+
+// Deregister is used to remove a volume that is no longer claimed.
+func (v *CSIVolumes) Deregister(id string, w *WriteOptions) (*WriteMeta, error) {
+	return v.client.delete("/v1/volume/csi/"+id, nil, w)
+}
+
+// CSIMountOptions are the options used to mount file system volumes.
+type CSIMountOptions struct {
+	FSType     string   `mapstructure:"fs_type"`
+	MountFlags []string `mapstructure:"mount_flags"`
+}
+
+// CSIVolumeClaim is the claim of an allocation on a volume.
+type CSIVolumeClaim struct {
+	AllocationID string
+	NodeID       string
+}
+
+// CSIVolume is a volume provided by a CSI plugin.
+type CSIVolume struct {
+	ID             string
+	Name           string
+	ExternalID     string `mapstructure:"external_id"`
+	Namespace      string
+	AccessMode     string           `mapstructure:"access_mode"`
+	AttachmentMode string           `mapstructure:"attachment_mode"`
+	MountOptions   *CSIMountOptions `mapstructure:"mount_options"`
+	Context        map[string]string
+
+	// ReadClaims and WriteClaims are the claims of allocations on the
+	// volume, by allocation ID.
+	ReadClaims  map[string]*CSIVolumeClaim
+	WriteClaims map[string]*CSIVolumeClaim
+
+	Schedulable        bool
+	PluginID           string `mapstructure:"plugin_id"`
+	Provider           string
+	ControllerRequired bool
+	ControllersHealthy int
+	NodesHealthy       int
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// CSIVolumeListStub is a summary of a volume.
+type CSIVolumeListStub struct {
+	ID                 string
+	Namespace          string
+	Name               string
+	ExternalID         string
+	AccessMode         string
+	AttachmentMode     string
+	CurrentReaders     int
+	CurrentWriters     int
+	Schedulable        bool
+	PluginID           string
+	Provider           string
+	ControllersHealthy int
+	NodesHealthy       int
+	CreateIndex        uint64
+	ModifyIndex        uint64
+}
+
+// CSIVolumeIndexSort is a wrapper to sort volumes by CreateIndex. We reverse
+// the test so that we get the highest index first.
+type CSIVolumeIndexSort []*CSIVolumeListStub
+
+func (v CSIVolumeIndexSort) Len() int {
+	return len(v)
+}
+
+func (v CSIVolumeIndexSort) Less(i, j int) bool {
+	return v[i].CreateIndex > v[j].CreateIndex
+}
+
+func (v CSIVolumeIndexSort) Swap(i, j int) {
+	v[i], v[j] = v[j], v[i]
+}
+
+// CSIVolumeRegisterRequest is used to register volumes.
+type CSIVolumeRegisterRequest struct {
+	Volumes []*CSIVolume
+	WriteRequest
+}
+
+// CSIPlugins is used to query the CSI plugin endpoints.
+type CSIPlugins struct {
+	client *Client
+}
+
+// CSIPlugins returns a handle on the CSI plugins.
+func (c *Client) CSIPlugins() *CSIPlugins {
+	return &CSIPlugins{client: c}
+}
+
+// List is used to list the CSI plugins running on the clients.
+func (p *CSIPlugins) List(q *QueryOptions) ([]*CSIPluginListStub, *QueryMeta, error) {
+	var resp []*CSIPluginListStub
+	qm, err := p.client.query("/v1/plugins?type=csi", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Sort(CSIPluginIndexSort(resp))
+	return resp, qm, nil
+}
+
+// Info is used to query a single plugin by its ID.
+func (p *CSIPlugins) Info(id string, q *QueryOptions) (*CSIPlugin, *QueryMeta, error) {
+	var resp CSIPlugin
+	qm, err := p.client.query("/v1/plugin/csi/"+id, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// TaskCSIPluginConfig configures a task to run a CSI plugin.
+type TaskCSIPluginConfig struct {
+	ID       string
+	Type     string
+	MountDir string `mapstructure:"mount_dir"`
+}
+
+// CSIControllerInfo is the fingerprint of a controller plugin.
+type CSIControllerInfo struct {
+	SupportsAttachDetach bool
+}
+
+// CSINodeInfo is the fingerprint of a node plugin.
+type CSINodeInfo struct {
+	ID                      string
+	MaxVolumes              int64
+	RequiresNodeStageVolume bool
+}
+
+// CSIInfo is the fingerprint of a CSI plugin running on a node.
+type CSIInfo struct {
+	PluginID                 string
+	AllocID                  string
+	Healthy                  bool
+	HealthDescription        string
+	UpdateTime               time.Time
+	Provider                 string
+	ProviderVersion          string
+	RequiresControllerPlugin bool
+	ControllerInfo           *CSIControllerInfo
+	NodeInfo                 *CSINodeInfo
+}
+
+// CSIPlugin is a CSI plugin and its fingerprints on the nodes running it.
+type CSIPlugin struct {
+	ID                 string
+	Provider           string
+	Version            string
+	ControllerRequired bool
+
+	// Controllers and Nodes map node IDs to the plugin's fingerprints
+	Controllers map[string]*CSIInfo
+	Nodes       map[string]*CSIInfo
+
+	ControllersHealthy int
+	NodesHealthy       int
+	CreateIndex        uint64
+	ModifyIndex        uint64
+}
+
+// CSIPluginListStub is a summary of a CSI plugin.
+type CSIPluginListStub struct {
+	ID                  string
+	Provider            string
+	ControllerRequired  bool
+	ControllersHealthy  int
+	ControllersExpected int
+	NodesHealthy        int
+	NodesExpected       int
+	CreateIndex         uint64
+	ModifyIndex         uint64
+}
+
+// CSIPluginIndexSort is a wrapper to sort plugins by CreateIndex. We reverse
+// the test so that we get the highest index first.
+type CSIPluginIndexSort []*CSIPluginListStub
+
+func (p CSIPluginIndexSort) Len() int {
+	return len(p)
+}
+
+func (p CSIPluginIndexSort) Less(i, j int) bool {
+	return p[i].CreateIndex > p[j].CreateIndex
+}
+
+func (p CSIPluginIndexSort) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"sort"
+)
+
+const (
+	// DrainPlanStatusRunning marks a plan that is starting node drains.
+	DrainPlanStatusRunning = "running"
+
+	// DrainPlanStatusPaused marks a plan that will not start further drains.
+	DrainPlanStatusPaused = "paused"
+
+	// DrainPlanStatusComplete marks a plan whose nodes have all been drained.
+	DrainPlanStatusComplete = "complete"
+
+	// DrainPlanStatusCancelled marks a plan that was stopped by an operator.
+	DrainPlanStatusCancelled = "cancelled"
+)
+
+// DrainPlans is used to query the drain plan endpoints.
+type DrainPlans struct {
+	client *Client
+}
+
+// DrainPlans returns a new handle on the drain plans.
+func (c *Client) DrainPlans() *DrainPlans {
+	return &DrainPlans{client: c}
+}
+
+// List is used to dump all of the drain plans.
+func (d *DrainPlans) List(q *QueryOptions) ([]*DrainPlanListStub, *QueryMeta, error) {
+	var resp []*DrainPlanListStub
+	qm, err := d.client.query("/v1/drain-plans", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Sort(DrainPlanIndexSort(resp))
+	return resp, qm, nil
+}
+
+// PrefixList is used to list the drain plans whose ID has the given prefix.
+func (d *DrainPlans) PrefixList(prefix string) ([]*DrainPlanListStub, *QueryMeta, error) {
+	return d.List(&QueryOptions{Prefix: prefix})
+}
+
+// Info is used to query a single drain plan by its ID.
+func (d *DrainPlans) Info(planID string, q *QueryOptions) (*DrainPlan, *QueryMeta, error) {
+	var resp DrainPlan
+	qm, err := d.client.query("/v1/drain-plan/"+planID, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Create is used to create a drain plan. The nodes of the plan are selected
+// by the servers using the plan's filter.
+func (d *DrainPlans) Create(plan *DrainPlan, q *WriteOptions) (*DrainPlanUpdateResponse, *WriteMeta, error) {
+	var resp DrainPlanUpdateResponse
+	req := &DrainPlanCreateRequest{
+		Plan: plan,
+	}
+	wm, err := d.client.write("/v1/drain-plans", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Pause is used to stop the drain plan from draining further nodes.
+func (d *DrainPlans) Pause(planID string, q *WriteOptions) (*DrainPlanUpdateResponse, *WriteMeta, error) {
+	return d.updateStatus("pause", planID, q)
+}
+
+// Resume is used to resume a paused drain plan.
+func (d *DrainPlans) Resume(planID string, q *WriteOptions) (*DrainPlanUpdateResponse, *WriteMeta, error) {
+	return d.updateStatus("resume", planID, q)
+}
+
+// Cancel is used to cancel a drain plan. Node drains already started by the
+// plan are not stopped.
+func (d *DrainPlans) Cancel(planID string, q *WriteOptions) (*DrainPlanUpdateResponse, *WriteMeta, error) {
+	return d.updateStatus("cancel", planID, q)
+}
+
+func (d *DrainPlans) updateStatus(action, planID string, q *WriteOptions) (*DrainPlanUpdateResponse, *WriteMeta, error) {
+	var resp DrainPlanUpdateResponse
+	wm, err := d.client.write("/v1/drain-plan/"+action+"/"+planID, nil, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// DrainPlanFilter selects the nodes targeted by a drain plan. A node must
+// match every field that is set.
+type DrainPlanFilter struct {
+	Datacenter string
+	NodeClass  string
+	Meta       map[string]string
+}
+
+// DrainPlanNode is the progress of a single node of a drain plan.
+type DrainPlanNode struct {
+	NodeID     string
+	Name       string
+	Status     string
+	DrainIndex uint64
+}
+
+// DrainPlan drains a group of nodes in batches of at most MaxParallel nodes.
+type DrainPlan struct {
+	ID                string
+	Filter            *DrainPlanFilter
+	DrainSpec         DrainSpec
+	MaxParallel       int
+	Nodes             []*DrainPlanNode
+	Status            string
+	StatusDescription string
+	CreateIndex       uint64
+	ModifyIndex       uint64
+}
+
+// DrainPlanListStub is a summary of a drain plan.
+type DrainPlanListStub struct {
+	ID                string
+	Filter            *DrainPlanFilter
+	MaxParallel       int
+	Status            string
+	StatusDescription string
+	TotalNodes        int
+	PendingNodes      int
+	DrainingNodes     int
+	CompleteNodes     int
+	CreateIndex       uint64
+	ModifyIndex       uint64
+}
+
+// DrainPlanIndexSort is a wrapper to sort drain plans by CreateIndex. We
+// reverse the test so that we get the highest index first.
+type DrainPlanIndexSort []*DrainPlanListStub
+
+func (d DrainPlanIndexSort) Len() int {
+	return len(d)
+}
+
+func (d DrainPlanIndexSort) Less(i, j int) bool {
+	return d[i].CreateIndex > d[j].CreateIndex
+}
+
+func (d DrainPlanIndexSort) Swap(i, j int) {
+	d[i], d[j] = d[j], d[i]
+}
+
+// DrainPlanCreateRequest is used to create a drain plan.
+type DrainPlanCreateRequest struct {
+	Plan *DrainPlan
+	WriteRequest
+}
+
+// DrainPlanUpdateResponse is used to respond to a drain plan write.
+type DrainPlanUpdateResponse struct {
+	PlanID          string
+	PlanModifyIndex uint64
+	WriteMeta
+}
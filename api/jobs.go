@@ -79,6 +79,10 @@ type RegisterOptions struct {
 	EnforceIndex   bool
 	ModifyIndex    uint64
 	PolicyOverride bool
+
+	// Submission is the original source of the job, stored alongside the
+	// job version created by the registration
+	Submission *JobSubmission
 }
 
 // Register is used to register a new job. It returns the ID
@@ -108,6 +112,7 @@ func (j *Jobs) RegisterOpts(job *Job, opts *RegisterOptions, q *WriteOptions) (*
 		if opts.PolicyOverride {
 			req.PolicyOverride = true
 		}
+		req.Submission = opts.Submission
 	}
 
 	var resp JobRegisterResponse
@@ -156,6 +161,16 @@ func (j *Jobs) Versions(jobID string, diffs bool, q *QueryOptions) ([]*Job, []*J
 	return resp.Versions, resp.Diffs, qm, nil
 }
 
+// Submission is used to retrieve the original source of a job version.
+func (j *Jobs) Submission(jobID string, version uint64, q *QueryOptions) (*JobSubmission, *QueryMeta, error) {
+	var resp JobSubmission
+	qm, err := j.client.query(fmt.Sprintf("/v1/job/%s/submission?version=%d", jobID, version), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Allocations is used to return the allocs for a given job ID.
 func (j *Jobs) Allocations(jobID string, allAllocs bool, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
 	var resp []*AllocationListStub
@@ -301,6 +316,17 @@ func (j *Jobs) Summary(jobID string, q *QueryOptions) (*JobSummary, *QueryMeta,
 	return &resp, qm, nil
 }
 
+// PlacementStatus is used to explain why the task groups of a job could not
+// be placed.
+func (j *Jobs) PlacementStatus(jobID string, q *QueryOptions) (*JobPlacementStatus, *QueryMeta, error) {
+	var resp JobPlacementStatus
+	qm, err := j.client.query("/v1/job/"+jobID+"/placement-status", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 func (j *Jobs) Dispatch(jobID string, meta map[string]string,
 	payload []byte, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
 	var resp JobDispatchResponse
@@ -907,15 +933,71 @@ type JobRegisterRequest struct {
 	JobModifyIndex uint64
 	PolicyOverride bool
 
+	// Submission is the optional original source of the job
+	Submission *JobSubmission
+
 	WriteRequest
 }
 
 // RegisterJobRequest is used to serialize a job registration
 type RegisterJobRequest struct {
 	Job            *Job
-	EnforceIndex   bool   `json:",omitempty"`
-	JobModifyIndex uint64 `json:",omitempty"`
-	PolicyOverride bool   `json:",omitempty"`
+	EnforceIndex   bool           `json:",omitempty"`
+	JobModifyIndex uint64         `json:",omitempty"`
+	PolicyOverride bool           `json:",omitempty"`
+	Submission     *JobSubmission `json:",omitempty"`
+}
+
+const (
+	// JobSubmissionFormatHCL and JobSubmissionFormatJSON are the formats of
+	// a job submission source.
+	JobSubmissionFormatHCL  = "hcl"
+	JobSubmissionFormatJSON = "json"
+)
+
+// JobPlacementStatus explains why task groups of a job could not be placed
+type JobPlacementStatus struct {
+	Namespace            string
+	JobID                string
+	EvalID               string
+	BlockedEvalID        string
+	WaitingClasses       []string
+	EscapedComputedClass bool
+	QuotaLimitReached    string
+	TaskGroups           map[string]*TaskGroupPlacementStatus
+}
+
+// TaskGroupPlacementStatus explains why a task group could not be placed
+type TaskGroupPlacementStatus struct {
+	Queued               int
+	Metrics              *AllocationMetric
+	ExhaustedDimensions  []*PlacementReason
+	FilteringConstraints []*PlacementReason
+	ExhaustedClasses     []*PlacementReason
+	FilteredClasses      []*PlacementReason
+}
+
+// PlacementReason is the number of nodes that could not be used for the
+// given reason.
+type PlacementReason struct {
+	Reason string
+	Nodes  int
+}
+
+// JobSubmission is the original source of a job version
+type JobSubmission struct {
+	// Source is the raw job specification
+	Source string
+
+	// Format is the format of the source, either "hcl" or "json"
+	Format string
+
+	// Namespace, JobID and Version identify the job version the source
+	// created. They are set by the server.
+	Namespace      string
+	JobID          string
+	Version        uint64
+	JobModifyIndex uint64
 }
 
 // JobRegisterResponse is used to respond to a job registration
@@ -846,6 +846,39 @@ func TestJobs_Versions(t *testing.T) {
 	}
 }
 
+func TestJobs_Submission(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	jobs := c.Jobs()
+
+	// Register the job with its source
+	job := testJob()
+	opts := &RegisterOptions{
+		Submission: &JobSubmission{
+			Source: "# job1\njob \"job1\" {}",
+			Format: JobSubmissionFormatHCL,
+		},
+	}
+	_, wm, err := jobs.RegisterOpts(job, opts, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+
+	// Retrieve the source
+	sub, qm, err := jobs.Submission(*job.ID, 0, nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Equal(opts.Submission.Source, sub.Source)
+	require.Equal(JobSubmissionFormatHCL, sub.Format)
+	require.Equal(*job.ID, sub.JobID)
+
+	// Retrieving a missing version returns an error
+	_, _, err = jobs.Submission(*job.ID, 1, nil)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+}
+
 func TestJobs_PrefixList(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t, nil, nil)
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+)
+
+// NodeMeta is used to read and update the meta of client nodes.
+type NodeMeta struct {
+	client *Client
+}
+
+// NodeMeta returns a handle on the node meta endpoints.
+func (c *Client) NodeMeta() *NodeMeta {
+	return &NodeMeta{client: c}
+}
+
+// NodeMetaApplyRequest contains the changes to apply to a node's dynamic meta.
+type NodeMetaApplyRequest struct {
+	// NodeID is the node to update. If empty, the node of the agent the
+	// request is sent to is updated.
+	NodeID string
+
+	// Meta is the set of meta changes to apply. A nil value unsets the key.
+	Meta map[string]*string
+}
+
+// NodeMetaResponse contains the meta of a node.
+type NodeMetaResponse struct {
+	// Meta is the node's effective meta, as used for scheduling.
+	Meta map[string]string
+
+	// Dynamic is the meta set through the API. Nil values are unset keys.
+	Dynamic map[string]*string
+
+	// Static is the meta set in the client's configuration.
+	Static map[string]string
+}
+
+// Apply updates the dynamic meta of a node. The changes are persisted by the
+// client and trigger the re-evaluation of the node's allocations.
+func (n *NodeMeta) Apply(meta *NodeMetaApplyRequest, q *WriteOptions) (*NodeMetaResponse, error) {
+	var resp NodeMetaResponse
+	if _, err := n.client.write("/v1/client/metadata", meta, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Read returns the meta of a node. If the node ID is empty, the meta of the
+// node of the agent the request is sent to is returned.
+func (n *NodeMeta) Read(nodeID string, q *QueryOptions) (*NodeMetaResponse, error) {
+	path := "/v1/client/metadata"
+	if nodeID != "" {
+		path = fmt.Sprintf("%s?node_id=%s", path, nodeID)
+	}
+
+	var resp NodeMetaResponse
+	if _, err := n.client.query(path, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -457,10 +457,19 @@ type Node struct {
 	StatusUpdatedAt       int64
 	Events                []*NodeEvent
 	Drivers               map[string]*DriverInfo
+	HostVolumes           map[string]*HostVolumeInfo
+	CSIControllerPlugins  map[string]*CSIInfo
+	CSINodePlugins        map[string]*CSIInfo
 	CreateIndex           uint64
 	ModifyIndex           uint64
 }
 
+// HostVolumeInfo is used to return metadata about a given HostVolume.
+type HostVolumeInfo struct {
+	Path     string
+	ReadOnly bool
+}
+
 // DrainStrategy describes a Node's drain behavior.
 type DrainStrategy struct {
 	// DrainSpec is the user declared drain specification
@@ -529,6 +538,7 @@ type HostStats struct {
 	Memory           *HostMemoryStats
 	CPU              []*HostCPUStats
 	DiskStats        []*HostDiskStats
+	DeviceStats      []*DeviceGroupStats
 	Uptime           uint64
 	CPUTicksConsumed float64
 }
@@ -557,6 +567,40 @@ type HostDiskStats struct {
 	InodesUsedPercent float64
 }
 
+// DeviceGroupStats contains the statistics of the devices of a device group,
+// keyed by device ID
+type DeviceGroupStats struct {
+	Vendor        string
+	Type          string
+	Name          string
+	InstanceStats map[string]*DeviceStats
+}
+
+// DeviceStats is the statistics of a single device
+type DeviceStats struct {
+	Summary   *StatValue
+	Stats     *StatObject
+	Timestamp time.Time
+}
+
+// StatObject is a collection of nested statistics
+type StatObject struct {
+	Nested     map[string]*StatObject
+	Attributes map[string]*StatValue
+}
+
+// StatValue is the typed value of a statistic
+type StatValue struct {
+	FloatNumeratorVal   *float64
+	FloatDenominatorVal *float64
+	IntNumeratorVal     *int64
+	IntDenominatorVal   *int64
+	StringVal           *string
+	BoolVal             *bool
+	Unit                string
+	Desc                string
+}
+
 // NodeListStub is a subset of information returned during
 // node list operations.
 type NodeListStub struct {
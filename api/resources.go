@@ -10,6 +10,11 @@ type Resources struct {
 	DiskMB   *int `mapstructure:"disk"`
 	IOPS     *int
 	Networks []*NetworkResource
+
+	// MemoryMaxMB is the hard memory limit of the task. MemoryMB is the
+	// reservation used for scheduling. It is only used if memory
+	// oversubscription is enabled on the servers.
+	MemoryMaxMB *int `mapstructure:"memory_max"`
 }
 
 // Canonicalize will supply missing values in the cases
@@ -66,6 +71,9 @@ func (r *Resources) Merge(other *Resources) {
 	if other.MemoryMB != nil {
 		r.MemoryMB = other.MemoryMB
 	}
+	if other.MemoryMaxMB != nil {
+		r.MemoryMaxMB = other.MemoryMaxMB
+	}
 	if other.DiskMB != nil {
 		r.DiskMB = other.DiskMB
 	}
@@ -16,6 +16,7 @@ type MemoryStats struct {
 	RSS            uint64
 	Cache          uint64
 	Swap           uint64
+	Usage          uint64
 	MaxUsage       uint64
 	KernelUsage    uint64
 	KernelMaxUsage uint64
@@ -52,6 +53,7 @@ type TaskResourceUsage struct {
 type AllocResourceUsage struct {
 	ResourceUsage *ResourceUsage
 	Tasks         map[string]*TaskResourceUsage
+	DeviceStats   []*DeviceGroupStats
 	Timestamp     int64
 }
 
@@ -327,6 +329,21 @@ type EphemeralDisk struct {
 	SizeMB  *int `mapstructure:"size"`
 }
 
+// VolumeRequest is a request for a volume made by a task group
+type VolumeRequest struct {
+	Name     string
+	Type     string
+	Source   string
+	ReadOnly bool `mapstructure:"read_only"`
+}
+
+// VolumeMount mounts a volume of the task group into a task
+type VolumeMount struct {
+	Volume      string
+	Destination string
+	ReadOnly    bool `mapstructure:"read_only"`
+}
+
 func DefaultEphemeralDisk() *EphemeralDisk {
 	return &EphemeralDisk{
 		Sticky:  helper.BoolToPtr(false),
@@ -420,6 +437,7 @@ type TaskGroup struct {
 	Update           *UpdateStrategy
 	Migrate          *MigrateStrategy
 	Meta             map[string]string
+	Volumes          map[string]*VolumeRequest
 }
 
 // NewTaskGroup creates a new TaskGroup.
@@ -551,14 +569,19 @@ func (g *TaskGroup) RequireDisk(disk *EphemeralDisk) *TaskGroup {
 
 // LogConfig provides configuration for log rotation
 type LogConfig struct {
-	MaxFiles      *int `mapstructure:"max_files"`
-	MaxFileSizeMB *int `mapstructure:"max_file_size"`
+	MaxFiles       *int           `mapstructure:"max_files"`
+	MaxFileSizeMB  *int           `mapstructure:"max_file_size"`
+	RotateInterval *time.Duration `mapstructure:"rotate_interval"`
+	Compress       *bool          `mapstructure:"compress"`
+	Sinks          []*LogSink     `mapstructure:"log_sink"`
 }
 
 func DefaultLogConfig() *LogConfig {
 	return &LogConfig{
-		MaxFiles:      helper.IntToPtr(10),
-		MaxFileSizeMB: helper.IntToPtr(10),
+		MaxFiles:       helper.IntToPtr(10),
+		MaxFileSizeMB:  helper.IntToPtr(10),
+		RotateInterval: helper.TimeToPtr(0),
+		Compress:       helper.BoolToPtr(false),
 	}
 }
 
@@ -569,6 +592,46 @@ func (l *LogConfig) Canonicalize() {
 	if l.MaxFileSizeMB == nil {
 		l.MaxFileSizeMB = helper.IntToPtr(10)
 	}
+	if l.RotateInterval == nil {
+		l.RotateInterval = helper.TimeToPtr(0)
+	}
+	if l.Compress == nil {
+		l.Compress = helper.BoolToPtr(false)
+	}
+	for _, s := range l.Sinks {
+		s.Canonicalize()
+	}
+}
+
+// LogSink configures a destination task logs are shipped to
+type LogSink struct {
+	Type       *string        `mapstructure:"type"`
+	Address    *string        `mapstructure:"address"`
+	Tag        *string        `mapstructure:"tag"`
+	BatchSize  *int           `mapstructure:"batch_size"`
+	BatchWait  *time.Duration `mapstructure:"batch_wait"`
+	BufferSize *int           `mapstructure:"buffer_size"`
+}
+
+func (s *LogSink) Canonicalize() {
+	if s.Type == nil {
+		s.Type = helper.StringToPtr("")
+	}
+	if s.Address == nil {
+		s.Address = helper.StringToPtr("")
+	}
+	if s.Tag == nil {
+		s.Tag = helper.StringToPtr("")
+	}
+	if s.BatchSize == nil {
+		s.BatchSize = helper.IntToPtr(100)
+	}
+	if s.BatchWait == nil {
+		s.BatchWait = helper.TimeToPtr(1 * time.Second)
+	}
+	if s.BufferSize == nil {
+		s.BufferSize = helper.IntToPtr(10000)
+	}
 }
 
 // DispatchPayloadConfig configures how a task gets its input from a job dispatch
@@ -576,6 +639,13 @@ type DispatchPayloadConfig struct {
 	File string
 }
 
+// WorkloadIdentity configures how the signed workload identity of a task is
+// exposed to it
+type WorkloadIdentity struct {
+	Env  bool
+	File bool
+}
+
 // Task is a single process in a task group.
 type Task struct {
 	Name            string
@@ -593,9 +663,12 @@ type Task struct {
 	Vault           *Vault
 	Templates       []*Template
 	DispatchPayload *DispatchPayloadConfig
+	Identity        *WorkloadIdentity
 	Leader          bool
 	ShutdownDelay   time.Duration `mapstructure:"shutdown_delay"`
 	KillSignal      string        `mapstructure:"kill_signal"`
+	VolumeMounts    []*VolumeMount
+	CSIPluginConfig *TaskCSIPluginConfig `mapstructure:"csi_plugin"`
 }
 
 func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
@@ -623,6 +696,9 @@ func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
 	for _, s := range t.Services {
 		s.Canonicalize(t, tg, job)
 	}
+	if t.CSIPluginConfig != nil && t.CSIPluginConfig.MountDir == "" {
+		t.CSIPluginConfig.MountDir = "/csi"
+	}
 }
 
 // TaskArtifact is used to download artifacts before running a task.
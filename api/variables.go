@@ -0,0 +1,180 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Variables is used to query the variables endpoints.
+type Variables struct {
+	client *Client
+}
+
+// Variables returns a new handle on the variables.
+func (c *Client) Variables() *Variables {
+	return &Variables{client: c}
+}
+
+// Variable is a set of secret key/value items stored at a path
+type Variable struct {
+	Namespace   string
+	Path        string
+	CreateIndex uint64
+	ModifyIndex uint64
+	CreateTime  int64
+	ModifyTime  int64
+	Items       VariableItems
+}
+
+// VariableItems are the key/value pairs of a variable
+type VariableItems map[string]string
+
+// VariableMetadata is the non-secret portion of a variable returned when
+// listing variables
+type VariableMetadata struct {
+	Namespace   string
+	Path        string
+	CreateIndex uint64
+	ModifyIndex uint64
+	CreateTime  int64
+	ModifyTime  int64
+}
+
+// ErrCASConflict is returned when a check-and-set operation fails because the
+// variable's modify index does not match the check index.
+type ErrCASConflict struct {
+	CheckIndex uint64
+
+	// Conflict is the current state of the variable. Its items are only set
+	// if the token may read the variable.
+	Conflict *Variable
+}
+
+func (e ErrCASConflict) Error() string {
+	return fmt.Sprintf("cas conflict: expected ModifyIndex %v; found %v", e.CheckIndex, e.Conflict.ModifyIndex)
+}
+
+// List is used to list the variables, optionally filtered by the Prefix of
+// the query options.
+func (v *Variables) List(q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	var resp []*VariableMetadata
+	qm, err := v.client.query("/v1/vars", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to list the variables whose path starts with the prefix
+func (v *Variables) PrefixList(prefix string, q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	q.Prefix = prefix
+	return v.List(q)
+}
+
+// Read is used to read the variable at the given path
+func (v *Variables) Read(path string, q *QueryOptions) (*Variable, *QueryMeta, error) {
+	if path == "" {
+		return nil, nil, fmt.Errorf("missing variable path")
+	}
+	var resp Variable
+	qm, err := v.client.query("/v1/var/"+path, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Upsert is used to create or overwrite a variable
+func (v *Variables) Upsert(sv *Variable, q *WriteOptions) (*Variable, *WriteMeta, error) {
+	if sv == nil || sv.Path == "" {
+		return nil, nil, fmt.Errorf("missing variable path")
+	}
+	var resp Variable
+	wm, err := v.client.write("/v1/var/"+sv.Path, sv, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// CheckedUpsert is used to write a variable only if its current modify index
+// matches the given check index. A check index of zero requires that the
+// variable does not exist. An ErrCASConflict is returned if the check fails.
+func (v *Variables) CheckedUpsert(sv *Variable, checkIndex uint64, q *WriteOptions) (*Variable, *WriteMeta, error) {
+	if sv == nil || sv.Path == "" {
+		return nil, nil, fmt.Errorf("missing variable path")
+	}
+	var resp Variable
+	wm, err := v.checkedWrite("PUT", sv.Path, checkIndex, sv, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to delete the variable at the given path
+func (v *Variables) Delete(path string, q *WriteOptions) (*WriteMeta, error) {
+	if path == "" {
+		return nil, fmt.Errorf("missing variable path")
+	}
+	wm, err := v.client.delete("/v1/var/"+path, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// CheckedDelete is used to delete a variable only if its current modify index
+// matches the given check index. An ErrCASConflict is returned if the check
+// fails.
+func (v *Variables) CheckedDelete(path string, checkIndex uint64, q *WriteOptions) (*WriteMeta, error) {
+	if path == "" {
+		return nil, fmt.Errorf("missing variable path")
+	}
+	return v.checkedWrite("DELETE", path, checkIndex, nil, nil, q)
+}
+
+// checkedWrite performs a check-and-set request, decoding the conflicting
+// variable into an ErrCASConflict when the check fails
+func (v *Variables) checkedWrite(method, path string, checkIndex uint64, in, out interface{}, q *WriteOptions) (*WriteMeta, error) {
+	r, err := v.client.newRequest(method, "/v1/var/"+path)
+	if err != nil {
+		return nil, err
+	}
+	r.setWriteOptions(q)
+	r.params.Set("cas", strconv.FormatUint(checkIndex, 10))
+	r.obj = in
+
+	rtt, resp, err := v.client.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusConflict {
+		defer resp.Body.Close()
+		conflict := new(Variable)
+		if err := decodeBody(resp, conflict); err != nil {
+			return nil, err
+		}
+		return nil, ErrCASConflict{CheckIndex: checkIndex, Conflict: conflict}
+	}
+
+	rtt, resp, err = requireOK(rtt, resp, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	wm := &WriteMeta{RequestTime: rtt}
+	parseWriteMeta(resp, wm)
+
+	if out != nil {
+		if err := decodeBody(resp, out); err != nil {
+			return nil, err
+		}
+	}
+	return wm, nil
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariables_CRUD(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	vars := c.Variables()
+
+	// Write a variable
+	sv := &Variable{
+		Path:  "app/db",
+		Items: VariableItems{"user": "admin", "password": "hunter2"},
+	}
+	out, wm, err := vars.Upsert(sv, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+	require.Equal("app/db", out.Path)
+	require.NotZero(out.ModifyIndex)
+
+	// Read it back
+	read, qm, err := vars.Read("app/db", nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Equal(sv.Items, read.Items)
+
+	// List it
+	list, _, err := vars.PrefixList("app", nil)
+	require.NoError(err)
+	require.Len(list, 1)
+	require.Equal("app/db", list[0].Path)
+
+	// A stale check-and-set fails
+	_, _, err = vars.CheckedUpsert(sv, 1, nil)
+	require.Error(err)
+	conflict, ok := err.(ErrCASConflict)
+	require.True(ok)
+	require.Equal(read.ModifyIndex, conflict.Conflict.ModifyIndex)
+
+	// A current check-and-set delete succeeds
+	_, err = vars.CheckedDelete("app/db", read.ModifyIndex, nil)
+	require.NoError(err)
+
+	list, _, err = vars.List(nil)
+	require.NoError(err)
+	require.Len(list, 0)
+}
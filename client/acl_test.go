@@ -2,6 +2,7 @@ package client
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/config"
@@ -165,3 +166,70 @@ func TestClient_ACL_ResolveToken(t *testing.T) {
 	assert.Equal(t, structs.ErrTokenNotFound, err)
 	assert.Nil(t, out4)
 }
+
+func TestClient_ACL_ResolveToken_Roles(t *testing.T) {
+	s1, _, _ := testACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	c1 := TestClient(t, func(c *config.Config) {
+		c.RPCHandler = s1
+		c.ACLEnabled = true
+	})
+	defer c1.Shutdown()
+
+	// Create a role granting a policy and a token only tied to the role
+	policy := mock.ACLPolicy()
+	role := mock.ACLRole()
+	role.Policies = []string{policy.Name}
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{role.Name}
+	assert.Nil(t, s1.State().UpsertACLPolicies(100, []*structs.ACLPolicy{policy}))
+	assert.Nil(t, s1.State().UpsertACLRoles(110, []*structs.ACLRole{role}))
+	assert.Nil(t, s1.State().UpsertACLTokens(120, []*structs.ACLToken{token}))
+
+	// The token is granted the policy of the role
+	out, err := c1.ResolveToken(token.SecretID)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+	assert.True(t, out.AllowNamespaceOperation("default", acl.NamespaceCapabilitySubmitJob))
+
+	// The role is cached
+	roles, err := c1.resolveRoles(token.SecretID, token.Roles)
+	assert.Nil(t, err)
+	assert.Len(t, roles, 1)
+	assert.Equal(t, role, roles[0])
+}
+
+func TestClient_ACL_expandRolePolicies(t *testing.T) {
+	roles := []*structs.ACLRole{
+		{Name: "ops", Policies: []string{"node-read", "job-write"}},
+		{Name: "dev", Policies: []string{"job-write", "job-read"}},
+	}
+	out := expandRolePolicies([]string{"node-read"}, roles)
+	assert.Equal(t, []string{"node-read", "job-write", "job-read"}, out)
+}
+
+func TestClient_ACL_ResolveToken_Expired(t *testing.T) {
+	s1, _, _ := testACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	c1 := TestClient(t, func(c *config.Config) {
+		c.RPCHandler = s1
+		c.ACLEnabled = true
+	})
+	defer c1.Shutdown()
+
+	// Create an expired token
+	past := time.Now().UTC().Add(-time.Minute)
+	token := mock.ACLToken()
+	token.ExpirationTime = &past
+	assert.Nil(t, s1.State().UpsertACLTokens(110, []*structs.ACLToken{token}))
+
+	// The token is rejected
+	out, err := c1.ResolveToken(token.SecretID)
+	assert.Equal(t, structs.ErrTokenExpired, err)
+	assert.Nil(t, out)
+}
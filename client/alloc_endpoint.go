@@ -1,12 +1,26 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/consul-template/signals"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/allocrunner"
+	dstructs "github.com/hashicorp/nomad/client/driver/structs"
 	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
 	nstructs "github.com/hashicorp/nomad/nomad/structs"
+	"github.com/ugorji/go/codec"
+)
+
+var (
+	cmdNotPresentErr = fmt.Errorf("must provide a command")
 )
 
 // Allocations endpoint is used for interacting with client allocations
@@ -14,6 +28,14 @@ type Allocations struct {
 	c *Client
 }
 
+// NewAllocationsEndpoint returns a new Allocations endpoint and registers its
+// streaming RPCs.
+func NewAllocationsEndpoint(c *Client) *Allocations {
+	a := &Allocations{c}
+	a.c.streamingRpcs.Register("Allocations.Exec", a.exec)
+	return a
+}
+
 // GarbageCollectAll is used to garbage collect all allocations on a client.
 func (a *Allocations) GarbageCollectAll(args *nstructs.NodeSpecificRequest, reply *nstructs.GenericResponse) error {
 	defer metrics.MeasureSince([]string{"client", "allocations", "garbage_collect_all"}, time.Now())
@@ -21,7 +43,7 @@ func (a *Allocations) GarbageCollectAll(args *nstructs.NodeSpecificRequest, repl
 	// Check node write permissions
 	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+	} else if aclObj != nil && !aclObj.AllowNodeClassWrite(a.c.Node().NodeClass) {
 		return nstructs.ErrPermissionDenied
 	}
 
@@ -33,10 +55,12 @@ func (a *Allocations) GarbageCollectAll(args *nstructs.NodeSpecificRequest, repl
 func (a *Allocations) GarbageCollect(args *nstructs.AllocSpecificRequest, reply *nstructs.GenericResponse) error {
 	defer metrics.MeasureSince([]string{"client", "allocations", "garbage_collect"}, time.Now())
 
-	// Check submit job permissions
+	// Check submit-job or alloc-lifecycle permissions
 	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil &&
+		!aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilitySubmitJob) &&
+		!aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilityAllocLifecycle) {
 		return nstructs.ErrPermissionDenied
 	}
 
@@ -70,6 +94,170 @@ func (a *Allocations) Stats(args *cstructs.AllocStatsRequest, reply *cstructs.Al
 		return err
 	}
 
+	// Include the statistics of the devices reserved for the allocation
+	if args.Task == "" && a.c.devicemanager != nil {
+		stats.DeviceStats = a.c.devicemanager.AllocStats(args.AllocID)
+	}
+
 	reply.Stats = stats
 	return nil
 }
+
+// Restart is used to restart the tasks of an allocation on a client.
+func (a *Allocations) Restart(args *cstructs.AllocRestartRequest, reply *nstructs.GenericResponse) error {
+	defer metrics.MeasureSince([]string{"client", "allocations", "restart"}, time.Now())
+
+	ar, err := a.authorizedAllocRunner(args.AllocID, &args.QueryOptions, acl.NamespaceCapabilityAllocLifecycle)
+	if err != nil {
+		return err
+	}
+
+	reason := lifecycleReason("restart", a.c.resolveAccessorID(args.AuthToken))
+	return ar.Restart(args.Task, "User", reason)
+}
+
+// Signal is used to send a signal to the tasks of an allocation on a client.
+func (a *Allocations) Signal(args *cstructs.AllocSignalRequest, reply *nstructs.GenericResponse) error {
+	defer metrics.MeasureSince([]string{"client", "allocations", "signal"}, time.Now())
+
+	ar, err := a.authorizedAllocRunner(args.AllocID, &args.QueryOptions, acl.NamespaceCapabilityAllocSignal)
+	if err != nil {
+		return err
+	}
+
+	sig, ok := signals.SignalLookup[strings.ToUpper(args.Signal)]
+	if !ok || sig == nil {
+		return fmt.Errorf("invalid signal %q", args.Signal)
+	}
+
+	reason := lifecycleReason("signal", a.c.resolveAccessorID(args.AuthToken))
+	return ar.Signal(args.Task, "User", reason, sig)
+}
+
+// authorizedAllocRunner returns the runner of the given allocation after
+// checking that the token of the request has the capability in both the
+// namespace of the request and the namespace of the allocation. The token is
+// checked before the allocation is looked up so that callers without the
+// capability can not probe for the existence of allocations.
+func (a *Allocations) authorizedAllocRunner(allocID string, q *nstructs.QueryOptions, capability string) (*allocrunner.AllocRunner, error) {
+	aclObj, err := a.c.ResolveToken(q.AuthToken)
+	if err != nil {
+		return nil, err
+	} else if aclObj != nil && !aclObj.AllowNsOp(q.Namespace, capability) {
+		return nil, nstructs.ErrPermissionDenied
+	}
+
+	ar, err := a.getAllocRunner(allocID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ns := ar.Alloc().Namespace; aclObj != nil && ns != q.Namespace && !aclObj.AllowNsOp(ns, capability) {
+		return nil, nstructs.ErrPermissionDenied
+	}
+	return ar, nil
+}
+
+// getAllocRunner returns the runner of the given allocation
+func (a *Allocations) getAllocRunner(allocID string) (*allocrunner.AllocRunner, error) {
+	a.c.allocLock.RLock()
+	defer a.c.allocLock.RUnlock()
+
+	ar, ok := a.c.allocs[allocID]
+	if !ok {
+		return nil, nstructs.NewErrUnknownAllocation(allocID)
+	}
+	return ar, nil
+}
+
+// lifecycleReason returns the reason recorded in the task events of a
+// lifecycle operation requested by the given token accessor.
+func lifecycleReason(op, accessor string) string {
+	if accessor == "" {
+		return fmt.Sprintf("%s requested", op)
+	}
+	return fmt.Sprintf("%s requested by accessor %s", op, accessor)
+}
+
+// exec is used to run an interactive command inside a task of an allocation.
+// After the initial request, the caller sends ExecStreamingRequestMsgs and
+// receives StreamErrWrappers whose payload is a JSON encoded
+// ExecStreamingResponseMsg.
+func (a *Allocations) exec(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "allocations", "exec"}, time.Now())
+	defer conn.Close()
+
+	// Decode the arguments
+	var req cstructs.AllocExecRequest
+	decoder := codec.NewDecoder(conn, nstructs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, nstructs.MsgpackHandle)
+
+	if err := decoder.Decode(&req); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Validate the arguments
+	if req.AllocID == "" {
+		handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	// Check alloc-exec permissions before revealing whether the allocation
+	// exists
+	ar, err := a.authorizedAllocRunner(req.AllocID, &req.QueryOptions, acl.NamespaceCapabilityAllocExec)
+	if err != nil {
+		var code *int64
+		if nstructs.IsErrUnknownAllocation(err) {
+			code = helper.Int64ToPtr(404)
+		}
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	if req.Task == "" {
+		handleStreamResultError(taskNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+	if len(req.Cmd) == 0 {
+		handleStreamResultError(cmdNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &execStream{decoder: decoder, encoder: encoder}
+	result, err := ar.ExecStreaming(ctx, req.Task, req.Cmd, req.Tty, stream)
+	if err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	if err := stream.Send(&dstructs.ExecStreamingResponseMsg{Exited: true, Result: result}); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+	}
+}
+
+// execStream adapts a streaming RPC connection to the stream of an
+// interactive command.
+type execStream struct {
+	decoder *codec.Decoder
+	encoder *codec.Encoder
+}
+
+func (s *execStream) Send(m *dstructs.ExecStreamingResponseMsg) error {
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return s.encoder.Encode(&cstructs.StreamErrWrapper{Payload: payload})
+}
+
+func (s *execStream) Recv() (*dstructs.ExecStreamingRequestMsg, error) {
+	var m dstructs.ExecStreamingRequestMsg
+	if err := s.decoder.Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -1,16 +1,25 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/config"
+	dstructs "github.com/hashicorp/nomad/client/driver/structs"
 	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad"
 	"github.com/hashicorp/nomad/nomad/mock"
 	nstructs "github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
 )
 
 func TestAllocations_GarbageCollectAll(t *testing.T) {
@@ -173,6 +182,209 @@ func TestAllocations_GarbageCollect_ACL(t *testing.T) {
 	}
 }
 
+func TestAllocations_Restart_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr, root := testACLServer(t, nil)
+	defer server.Shutdown()
+
+	client := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer client.Shutdown()
+
+	a := testRunAlloc(t, server, client)
+
+	// Try request without a token and expect failure
+	{
+		req := &cstructs.AllocRestartRequest{AllocID: a.ID}
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with an invalid token and expect failure
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1005, "invalid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+		req := &cstructs.AllocRestartRequest{AllocID: a.ID}
+		req.AuthToken = token.SecretID
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request for an unknown allocation with an invalid token and expect
+	// failure rather than an unknown allocation error
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1008, "invalid-unknown",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+		req := &cstructs.AllocRestartRequest{AllocID: uuid.Generate()}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a token for another namespace and expect failure
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1006, "other",
+			mock.NamespacePolicy("other", "", []string{acl.NamespaceCapabilityAllocLifecycle}))
+		req := &cstructs.AllocRestartRequest{AllocID: a.ID}
+		req.AuthToken = token.SecretID
+		req.Namespace = "other"
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a valid token
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1007, "test-valid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocLifecycle}))
+		req := &cstructs.AllocRestartRequest{AllocID: uuid.Generate()}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+
+	// Try request with a management token
+	{
+		req := &cstructs.AllocRestartRequest{AllocID: uuid.Generate()}
+		req.AuthToken = root.SecretID
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+}
+
+func TestAllocations_Signal_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr, root := testACLServer(t, nil)
+	defer server.Shutdown()
+
+	client := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer client.Shutdown()
+
+	a := testRunAlloc(t, server, client)
+
+	// Try request without a token and expect failure
+	{
+		req := &cstructs.AllocSignalRequest{AllocID: a.ID, Signal: "SIGUSR1"}
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with an invalid token and expect failure
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1005, "invalid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+		req := &cstructs.AllocSignalRequest{AllocID: a.ID, Signal: "SIGUSR1"}
+		req.AuthToken = token.SecretID
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request for an unknown allocation with an invalid token and expect
+	// failure rather than an unknown allocation error
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1008, "invalid-unknown",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+		req := &cstructs.AllocSignalRequest{AllocID: uuid.Generate(), Signal: "SIGUSR1"}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a token for another namespace and expect failure
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1006, "other",
+			mock.NamespacePolicy("other", "", []string{acl.NamespaceCapabilityAllocSignal}))
+		req := &cstructs.AllocSignalRequest{AllocID: a.ID, Signal: "SIGUSR1"}
+		req.AuthToken = token.SecretID
+		req.Namespace = "other"
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a valid token
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1007, "test-valid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocSignal}))
+		req := &cstructs.AllocSignalRequest{AllocID: uuid.Generate(), Signal: "SIGUSR1"}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+
+	// Try request with a management token
+	{
+		req := &cstructs.AllocSignalRequest{AllocID: uuid.Generate(), Signal: "SIGUSR1"}
+		req.AuthToken = root.SecretID
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+}
+
+func TestAllocations_Signal_InvalidSignal(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client := TestClient(t, nil)
+	defer client.Shutdown()
+
+	a := mock.Alloc()
+	require.Nil(client.addAlloc(a, ""))
+
+	req := &cstructs.AllocSignalRequest{
+		AllocID: a.ID,
+		Signal:  "SIGFOO",
+	}
+	var resp nstructs.GenericResponse
+	err := client.ClientRPC("Allocations.Signal", &req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "invalid signal")
+}
+
 func TestAllocations_Stats(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -262,3 +474,322 @@ func TestAllocations_Stats_ACL(t *testing.T) {
 		require.True(nstructs.IsErrUnknownAllocation(err))
 	}
 }
+
+func TestAllocations_Exec_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server
+	s, root := nomad.TestACLServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer client.Shutdown()
+
+	// Run an allocation on the client
+	a := testRunAlloc(t, s, client)
+
+	// Create a bad token
+	policyBad := mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadLogs})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	// Create a token allowed to exec in another namespace
+	policyOther := mock.NamespacePolicy("other", "", []string{acl.NamespaceCapabilityAllocExec})
+	tokenOther := mock.CreatePolicyAndToken(t, s.State(), 1007, "other", policyOther)
+
+	policyGood := mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocExec})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid", policyGood)
+
+	cases := []struct {
+		Name          string
+		Token         string
+		Namespace     string
+		AllocID       string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			Namespace:     nstructs.DefaultNamespace,
+			AllocID:       a.ID,
+			ExpectedError: nstructs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "bad token unknown allocation",
+			Token:         tokenBad.SecretID,
+			Namespace:     nstructs.DefaultNamespace,
+			AllocID:       uuid.Generate(),
+			ExpectedError: nstructs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "other namespace token",
+			Token:         tokenOther.SecretID,
+			Namespace:     "other",
+			AllocID:       a.ID,
+			ExpectedError: nstructs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			Namespace:     nstructs.DefaultNamespace,
+			AllocID:       a.ID,
+			ExpectedError: "has no task",
+		},
+		{
+			Name:          "good token unknown allocation",
+			Token:         tokenGood.SecretID,
+			Namespace:     nstructs.DefaultNamespace,
+			AllocID:       uuid.Generate(),
+			ExpectedError: nstructs.ErrUnknownAllocationPrefix,
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			Namespace:     nstructs.DefaultNamespace,
+			AllocID:       uuid.Generate(),
+			ExpectedError: nstructs.ErrUnknownAllocationPrefix,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			// Make the request with a task the allocation doesn't run
+			req := &cstructs.AllocExecRequest{
+				AllocID: c.AllocID,
+				Task:    "foo",
+				Cmd:     []string{"/bin/sh"},
+				QueryOptions: nstructs.QueryOptions{
+					Namespace: c.Namespace,
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+
+			msgs, errCh, _, closeFn := testExecStream(t, client, req)
+			defer closeFn()
+
+			select {
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout")
+			case err := <-errCh:
+				t.Fatal(err)
+			case msg := <-msgs:
+				require.NotNil(msg.Error)
+				require.Contains(msg.Error.Error(), c.ExpectedError)
+			}
+		})
+	}
+}
+
+func TestAllocations_Exec(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s := nomad.TestServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer c.Shutdown()
+
+	a := mock.Alloc()
+	a.NodeID = c.NodeID()
+	a.Job.TaskGroups[0].Count = 1
+	a.Job.TaskGroups[0].Tasks[0] = &nstructs.Task{
+		Name:   "web",
+		Driver: "mock_driver",
+		Config: map[string]interface{}{
+			"run_for": "20s",
+		},
+		LogConfig: nstructs.DefaultLogConfig(),
+		Resources: &nstructs.Resources{
+			CPU:      500,
+			MemoryMB: 256,
+		},
+	}
+
+	// Wait for the client to connect
+	testutil.WaitForResult(func() (bool, error) {
+		node, err := s.State().NodeByID(nil, c.NodeID())
+		if err != nil {
+			return false, err
+		}
+		if node == nil {
+			return false, fmt.Errorf("unknown node")
+		}
+
+		return node.Status == nstructs.NodeStatusReady, fmt.Errorf("bad node status")
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// Upsert the allocation
+	state := s.State()
+	require.Nil(state.UpsertJob(999, a.Job))
+	require.Nil(state.UpsertAllocs(1003, []*nstructs.Allocation{a}))
+
+	// Wait for the client to run the allocation
+	testutil.WaitForResult(func() (bool, error) {
+		alloc, err := state.AllocByID(nil, a.ID)
+		if err != nil {
+			return false, err
+		}
+		if alloc == nil {
+			return false, fmt.Errorf("unknown alloc")
+		}
+		if alloc.ClientStatus != nstructs.AllocClientStatusRunning {
+			return false, fmt.Errorf("alloc client status: %v", alloc.ClientStatus)
+		}
+
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("Alloc on node %q not running: %v", c.NodeID(), err)
+	})
+
+	// Make the request
+	req := &cstructs.AllocExecRequest{
+		AllocID:      a.ID,
+		Task:         "web",
+		Cmd:          []string{"/bin/sh", "-c", "cat"},
+		QueryOptions: nstructs.QueryOptions{Region: "global"},
+	}
+
+	msgs, errCh, encoder, closeFn := testExecStream(t, c, req)
+	defer closeFn()
+
+	// Send input and close it
+	require.Nil(encoder.Encode(&dstructs.ExecStreamingRequestMsg{
+		Stdin: &dstructs.ExecStreamingIOOperation{Data: []byte("hello\n")},
+	}))
+	require.Nil(encoder.Encode(&dstructs.ExecStreamingRequestMsg{
+		Stdin: &dstructs.ExecStreamingIOOperation{Close: true},
+	}))
+
+	var stdout string
+	timeout := time.After(5 * time.Second)
+OUTER:
+	for {
+		select {
+		case <-timeout:
+			t.Fatalf("timeout; received %q", stdout)
+		case err := <-errCh:
+			t.Fatal(err)
+		case msg := <-msgs:
+			require.Nil(msg.Error)
+
+			var resp dstructs.ExecStreamingResponseMsg
+			require.Nil(json.Unmarshal(msg.Payload, &resp))
+			if resp.Stdout != nil {
+				stdout += string(resp.Stdout.Data)
+			}
+			if resp.Exited {
+				require.NotNil(resp.Result)
+				require.Equal(0, resp.Result.ExitCode)
+				break OUTER
+			}
+		}
+	}
+
+	require.Equal("ExecStreaming([\"/bin/sh\" \"-c\" \"cat\"])\nhello\n", stdout)
+}
+
+// testRunAlloc runs a long running mock_driver allocation on the client and
+// waits for the client to start it.
+func testRunAlloc(t *testing.T, s *nomad.Server, c *Client) *nstructs.Allocation {
+	require := require.New(t)
+
+	a := mock.Alloc()
+	a.NodeID = c.NodeID()
+	a.Job.TaskGroups[0].Count = 1
+	a.Job.TaskGroups[0].Tasks[0] = &nstructs.Task{
+		Name:   "web",
+		Driver: "mock_driver",
+		Config: map[string]interface{}{
+			"run_for": "20s",
+		},
+		LogConfig: nstructs.DefaultLogConfig(),
+		Resources: &nstructs.Resources{
+			CPU:      500,
+			MemoryMB: 256,
+		},
+	}
+
+	testutil.WaitForResult(func() (bool, error) {
+		node, err := s.State().NodeByID(nil, c.NodeID())
+		if err != nil {
+			return false, err
+		}
+		if node == nil {
+			return false, fmt.Errorf("unknown node")
+		}
+
+		return node.Status == nstructs.NodeStatusReady, fmt.Errorf("bad node status")
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	state := s.State()
+	require.Nil(state.UpsertJob(999, a.Job))
+	require.Nil(state.UpsertAllocs(1003, []*nstructs.Allocation{a}))
+
+	testutil.WaitForResult(func() (bool, error) {
+		_, ok := c.getAllocRunners()[a.ID]
+		return ok, fmt.Errorf("alloc %q not running", a.ID)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	return a
+}
+
+// testExecStream starts an Allocations.Exec stream on the client and sends the
+// request. It returns the received messages and an encoder to send input.
+func testExecStream(t *testing.T, c *Client, req *cstructs.AllocExecRequest) (<-chan *cstructs.StreamErrWrapper,
+	<-chan error, *codec.Encoder, func()) {
+
+	// Get the handler
+	handler, err := c.StreamingRpcHandler("Allocations.Exec")
+	require.Nil(t, err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+
+	// Buffer the messages so the handler isn't blocked while input is sent
+	errCh := make(chan error, 1)
+	streamMsg := make(chan *cstructs.StreamErrWrapper, 16)
+
+	// Start the handler
+	go handler(p2)
+
+	// Start the decoder
+	go func() {
+		decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+		for {
+			var msg cstructs.StreamErrWrapper
+			if err := decoder.Decode(&msg); err != nil {
+				if err == io.EOF || strings.Contains(err.Error(), "closed") {
+					return
+				}
+				errCh <- fmt.Errorf("error decoding: %v", err)
+				return
+			}
+
+			streamMsg <- &msg
+		}
+	}()
+
+	// Send the request
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	require.Nil(t, encoder.Encode(req))
+
+	return streamMsg, errCh, encoder, func() {
+		p1.Close()
+		p2.Close()
+	}
+}
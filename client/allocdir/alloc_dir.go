@@ -247,6 +247,14 @@ func (d *AllocDir) Move(other *AllocDir, tasks []*structs.Task) error {
 // Tears down previously build directory structure.
 func (d *AllocDir) Destroy() error {
 
+	// Never remove the alloc dir while host volumes may still be mounted as
+	// their contents would be deleted.
+	for _, dir := range d.TaskDirs {
+		if err := dir.unmountHostVolumes(); err != nil {
+			return fmt.Errorf("failed to unmount host volumes of %q: %v", dir.Dir, err)
+		}
+	}
+
 	// Unmount all mounted shared alloc dirs.
 	var mErr multierror.Error
 	if err := d.UnmountAll(); err != nil {
@@ -266,6 +274,11 @@ func (d *AllocDir) Destroy() error {
 func (d *AllocDir) UnmountAll() error {
 	var mErr multierror.Error
 	for _, dir := range d.TaskDirs {
+		// Unmount host volumes.
+		if err := dir.unmountHostVolumes(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+
 		// Check if the directory has the shared alloc mounted.
 		if pathExists(dir.SharedTaskDir) {
 			if err := unlinkDir(dir.SharedTaskDir); err != nil {
@@ -37,9 +37,36 @@ type TaskDir struct {
 	// <task_dir>/secrets/
 	SecretsDir string
 
+	// HostVolumes are the host volumes mounted into the task directory.
+	HostVolumes []*HostVolumeMount
+
 	logger *log.Logger
 }
 
+// HostVolumeMount is a host volume to be mounted into a task.
+type HostVolumeMount struct {
+	// Source is the path of the volume on the host
+	Source string
+
+	// Destination is the path the volume is mounted at, relative to the
+	// task's root.
+	Destination string
+
+	// ReadOnly mounts the volume read only
+	ReadOnly bool
+}
+
+// Copy returns a copy of the host volume mount.
+func (m *HostVolumeMount) Copy() *HostVolumeMount {
+	if m == nil {
+		return nil
+	}
+
+	nm := new(HostVolumeMount)
+	*nm = *m
+	return nm
+}
+
 // newTaskDir creates a TaskDir struct with paths set. Call Build() to
 // create paths on disk.
 //
@@ -59,9 +86,15 @@ func newTaskDir(logger *log.Logger, allocDir, taskName string) *TaskDir {
 
 // Copy a TaskDir. Panics if TaskDir is nil as TaskDirs should never be nil.
 func (t *TaskDir) Copy() *TaskDir {
-	// No nested structures other than the logger which is safe to share,
-	// so just copy the struct
+	// The logger is safe to share, so just copy the struct and the host
+	// volumes
 	tcopy := *t
+	if t.HostVolumes != nil {
+		tcopy.HostVolumes = make([]*HostVolumeMount, len(t.HostVolumes))
+		for i, m := range t.HostVolumes {
+			tcopy.HostVolumes[i] = m.Copy()
+		}
+	}
 	return &tcopy
 }
 
@@ -127,11 +160,22 @@ func (t *TaskDir) Build(chrootCreated bool, chroot map[string]string, fsi cstruc
 		if err := t.buildChroot(chrootCreated, chroot); err != nil {
 			return err
 		}
+
+		// Image based isolation will bind the host volumes in the driver.
+		if err := t.mountHostVolumes(); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// hostVolumePath returns the path on the host at which the host volume is
+// mounted into the task directory.
+func (t *TaskDir) hostVolumePath(m *HostVolumeMount) string {
+	return filepath.Join(t.Dir, filepath.Clean("/"+m.Destination))
+}
+
 // buildChroot takes a mapping of absolute directory or file paths on the host
 // to their intended, relative location within the task directory. This
 // attempts hardlink and then defaults to copying. If the path exists on the
@@ -49,6 +49,73 @@ func (t *TaskDir) mountSpecialDirs() error {
 	return nil
 }
 
+// mountHostVolumes bind mounts the task's host volumes into the chroot.
+// Volumes that are already mounted, eg when a task is restarted, are skipped.
+func (t *TaskDir) mountHostVolumes() error {
+	for _, m := range t.HostVolumes {
+		dst := t.hostVolumePath(m)
+		if !pathExists(dst) {
+			if err := os.MkdirAll(dst, 0777); err != nil {
+				return fmt.Errorf("Mkdir(%v) failed: %v", dst, err)
+			}
+		}
+		if sameFile(m.Source, dst) {
+			continue
+		}
+
+		if err := syscall.Mount(m.Source, dst, "", syscall.MS_BIND, ""); err != nil {
+			return fmt.Errorf("Couldn't mount host volume %q to %v: %v", m.Source, dst, err)
+		}
+
+		// Bind mounts can only be made read only by remounting them
+		if m.ReadOnly {
+			flags := uintptr(syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY)
+			if err := syscall.Mount("", dst, "", flags, ""); err != nil {
+				unlinkDir(dst)
+				return fmt.Errorf("Couldn't remount host volume %v read only: %v", dst, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sameFile returns whether both paths refer to the same file on the same
+// device, which is the case once a directory is bind mounted.
+func sameFile(a, b string) bool {
+	var sa, sb syscall.Stat_t
+	if err := syscall.Stat(a, &sa); err != nil {
+		return false
+	}
+	if err := syscall.Stat(b, &sb); err != nil {
+		return false
+	}
+	return sa.Dev == sb.Dev && sa.Ino == sb.Ino
+}
+
+// unmountHostVolumes unmounts the task's host volumes and removes the mount
+// points. A mount point is only removed once the volume is no longer mounted
+// so the contents of a host volume are never deleted.
+func (t *TaskDir) unmountHostVolumes() error {
+	errs := new(multierror.Error)
+	for _, m := range t.HostVolumes {
+		dst := t.hostVolumePath(m)
+		if !pathExists(dst) {
+			continue
+		}
+
+		if err := unlinkDir(dst); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("Failed to unmount host volume %q: %v", dst, err))
+		} else if sameFile(m.Source, dst) {
+			errs = multierror.Append(errs, fmt.Errorf("Host volume %q is still mounted", dst))
+		} else if err := os.RemoveAll(dst); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("Failed to delete host volume mount point %q: %v", dst, err))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
 // unmountSpecialDirs unmounts the dev and proc file system from the chroot. No
 // error is returned if the directories do not exist or have already been
 // unmounted.
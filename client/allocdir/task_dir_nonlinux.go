@@ -2,6 +2,8 @@
 
 package allocdir
 
+import "fmt"
+
 // currently a noop on non-Linux platforms
 func (d *TaskDir) mountSpecialDirs() error {
 	return nil
@@ -11,3 +13,16 @@ func (d *TaskDir) mountSpecialDirs() error {
 func (d *TaskDir) unmountSpecialDirs() error {
 	return nil
 }
+
+// host volumes can only be mounted into a chroot on Linux
+func (d *TaskDir) mountHostVolumes() error {
+	if len(d.HostVolumes) != 0 {
+		return fmt.Errorf("host volumes are not supported with chroot isolation on this platform")
+	}
+	return nil
+}
+
+// currently a noop on non-Linux platforms
+func (d *TaskDir) unmountHostVolumes() error {
+	return nil
+}
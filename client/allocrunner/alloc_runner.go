@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"path/filepath"
 	"sync"
 	"time"
@@ -15,11 +16,13 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner"
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
+	"github.com/hashicorp/nomad/client/csimanager"
 	"github.com/hashicorp/nomad/client/state"
 	"github.com/hashicorp/nomad/client/vaultclient"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
 
+	dstructs "github.com/hashicorp/nomad/client/driver/structs"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 )
 
@@ -74,6 +77,18 @@ type AllocRunner struct {
 	vaultClient  vaultclient.VaultClient
 	consulClient consulApi.ConsulServiceAPI
 
+	// variablesFetcher is passed to the task runners to retrieve the
+	// variables their templates may read
+	variablesFetcher taskrunner.VariablesFetcher
+
+	// identitySigner is passed to the task runners to retrieve their signed
+	// workload identities
+	identitySigner taskrunner.IdentitySigner
+
+	// csiManager mounts the CSI volumes of the allocation and watches the
+	// CSI plugins run by its tasks
+	csiManager csimanager.Manager
+
 	// prevAlloc allows for Waiting until a previous allocation exits and
 	// the migrates it data. If sticky volumes aren't used and there's no
 	// previous allocation a noop implementation is used so it always safe
@@ -91,7 +106,7 @@ type AllocRunner struct {
 
 	// State related fields
 	// stateDB is used to store the alloc runners state
-	stateDB        *bolt.DB
+	stateDB        state.StateDB
 	allocStateLock sync.Mutex
 
 	// persistedEval is the last persisted evaluation ID. Since evaluation
@@ -132,9 +147,10 @@ type allocRunnerMutableState struct {
 }
 
 // NewAllocRunner is used to create a new allocation context
-func NewAllocRunner(logger *log.Logger, config *config.Config, stateDB *bolt.DB, updater AllocStateUpdater,
+func NewAllocRunner(logger *log.Logger, config *config.Config, stateDB state.StateDB, updater AllocStateUpdater,
 	alloc *structs.Allocation, vaultClient vaultclient.VaultClient, consulClient consulApi.ConsulServiceAPI,
-	prevAlloc prevAllocWatcher) *AllocRunner {
+	prevAlloc prevAllocWatcher, variablesFetcher taskrunner.VariablesFetcher,
+	identitySigner taskrunner.IdentitySigner, csiManager csimanager.Manager) *AllocRunner {
 
 	ar := &AllocRunner{
 		config:         config,
@@ -154,6 +170,10 @@ func NewAllocRunner(logger *log.Logger, config *config.Config, stateDB *bolt.DB,
 		waitCh:         make(chan struct{}),
 		vaultClient:    vaultClient,
 		consulClient:   consulClient,
+
+		variablesFetcher: variablesFetcher,
+		identitySigner:   identitySigner,
+		csiManager:       csiManager,
 	}
 
 	// TODO Should be passed a context
@@ -187,16 +207,6 @@ func (r *AllocRunner) setBaseLabels() {
 	}
 }
 
-// pre060StateFilePath returns the path to our state file that would have been
-// written pre v0.6.0
-// COMPAT: Remove in 0.7.0
-func (r *AllocRunner) pre060StateFilePath() string {
-	r.allocLock.Lock()
-	defer r.allocLock.Unlock()
-	path := filepath.Join(r.config.StateDir, "alloc", r.allocID, "state.json")
-	return path
-}
-
 // RestoreState is used to restore the state of the alloc runner
 func (r *AllocRunner) RestoreState() error {
 	err := r.stateDB.View(func(tx *bolt.Tx) error {
@@ -278,6 +288,12 @@ func (r *AllocRunner) RestoreState() error {
 			// client may save state and exit before all task dirs
 			// are created
 			td = r.allocDir.NewTaskDir(name)
+			mounts, err := taskHostVolumes(tg, task, r.config.HostVolumes, nil)
+			if err != nil {
+				mErr.Errors = append(mErr.Errors, err)
+				continue
+			}
+			td.HostVolumes = mounts
 		}
 
 		// Skip tasks in terminal states.
@@ -285,7 +301,7 @@ func (r *AllocRunner) RestoreState() error {
 			continue
 		}
 
-		tr := taskrunner.NewTaskRunner(r.logger, r.config, r.stateDB, r.setTaskState, td, r.Alloc(), task, r.vaultClient, r.consulClient)
+		tr := taskrunner.NewTaskRunner(r.logger, r.config, r.stateDB, r.setTaskState, td, r.Alloc(), task, r.vaultClient, r.consulClient, r.variablesFetcher, r.identitySigner)
 		r.tasks[name] = tr
 
 		if restartReason, err := tr.RestoreState(); err != nil {
@@ -838,6 +854,36 @@ func (r *AllocRunner) Run() {
 		return
 	}
 
+	// Mount the CSI volumes claimed by the allocation
+	csiVolumes, err := r.mountCSIVolumes(tg)
+	if err != nil {
+		if err == context.Canceled {
+			r.unmountCSIVolumes()
+			return
+		}
+		r.logger.Printf("[ERR] client: alloc %q failed to mount CSI volumes: %v", r.allocID, err)
+		r.setStatus(structs.AllocClientStatusFailed, fmt.Sprintf("failed to mount CSI volumes: %v", err))
+		r.handleDestroy()
+		return
+	}
+
+	// Resolve the host volumes mounted by the tasks before starting any of
+	// them.
+	hostVolumes := make(map[string][]*allocdir.HostVolumeMount, len(tg.Tasks))
+	for _, task := range tg.Tasks {
+		mounts, err := taskHostVolumes(tg, task, r.config.HostVolumes, csiVolumes)
+		if err == nil {
+			mounts, err = r.csiPluginMount(task, mounts)
+		}
+		if err != nil {
+			r.logger.Printf("[ERR] client: alloc %q failed to setup host volumes: %v", r.allocID, err)
+			r.setStatus(structs.AllocClientStatusFailed, fmt.Sprintf("failed to setup host volumes for task %q: %v", task.Name, err))
+			r.handleDestroy()
+			return
+		}
+		hostVolumes[task.Name] = mounts
+	}
+
 	// Increment alloc runner start counter. Incr'd even when restoring existing tasks so 1 start != 1 task execution
 	if !r.config.DisableTaggedMetrics {
 		metrics.IncrCounterWithLabels([]string{"client", "allocs", "start"},
@@ -861,9 +907,10 @@ func (r *AllocRunner) Run() {
 
 		r.allocDirLock.Lock()
 		taskdir := r.allocDir.NewTaskDir(task.Name)
+		taskdir.HostVolumes = hostVolumes[task.Name]
 		r.allocDirLock.Unlock()
 
-		tr := taskrunner.NewTaskRunner(r.logger, r.config, r.stateDB, r.setTaskState, taskdir, r.Alloc(), task.Copy(), r.vaultClient, r.consulClient)
+		tr := taskrunner.NewTaskRunner(r.logger, r.config, r.stateDB, r.setTaskState, taskdir, r.Alloc(), task.Copy(), r.vaultClient, r.consulClient, r.variablesFetcher, r.identitySigner)
 		r.tasks[task.Name] = tr
 		tr.MarkReceived()
 
@@ -900,6 +947,11 @@ OUTER:
 			// Check if we're in a terminal status
 			if update.TerminalStatus() {
 				taskDestroyEvent = structs.NewTaskEvent(structs.TaskKilled)
+
+				// Record why the allocation was stopped if it was requested
+				if reason := update.DesiredTransition.Reason; reason != "" {
+					taskDestroyEvent = structs.NewTaskEvent(structs.TaskKilling).SetKillReason(reason)
+				}
 				break OUTER
 			}
 
@@ -1006,6 +1058,9 @@ func (r *AllocRunner) handleDestroy() {
 		r.logger.Printf("[ERR] client: alloc %q unable unmount task directories: %v", r.allocID, err)
 	}
 
+	// Release the CSI volumes now that the tasks no longer use them
+	r.unmountCSIVolumes()
+
 	// Update the server with the alloc's status -- also marks the alloc as
 	// being eligible for GC, so from this point on the alloc can be gc'd
 	// at any time.
@@ -1110,6 +1165,79 @@ func (r *AllocRunner) LatestAllocStats(taskFilter string) (*cstructs.AllocResour
 	return astat, nil
 }
 
+// ExecStreaming runs an interactive command in the context of a running task
+// of the allocation.
+func (r *AllocRunner) ExecStreaming(ctx context.Context, task string, cmd []string, tty bool,
+	stream dstructs.ExecStream) (*dstructs.ExecStreamingExitResult, error) {
+	r.taskLock.RLock()
+	tr, ok := r.tasks[task]
+	r.taskLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("allocation %q has no task %q", r.allocID, task)
+	}
+	return tr.ExecStreaming(ctx, cmd, tty, stream)
+}
+
+// Restart restarts the given task of the allocation, or all of its running
+// tasks if task is empty.
+func (r *AllocRunner) Restart(task, source, reason string) error {
+	runners, err := r.lifecycleTaskRunners(task)
+	if err != nil {
+		return err
+	}
+
+	for _, tr := range runners {
+		tr.Restart(source, reason, false)
+	}
+	return nil
+}
+
+// Signal sends a signal to the given task of the allocation, or all of its
+// running tasks if task is empty.
+func (r *AllocRunner) Signal(task, source, reason string, s os.Signal) error {
+	runners, err := r.lifecycleTaskRunners(task)
+	if err != nil {
+		return err
+	}
+
+	var mErr multierror.Error
+	for name, tr := range runners {
+		if err := tr.Signal(source, reason, s); err != nil {
+			multierror.Append(&mErr, fmt.Errorf("failed to signal task %q: %v", name, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// lifecycleTaskRunners returns the running task runners targeted by a
+// lifecycle operation. All running tasks are returned if task is empty.
+func (r *AllocRunner) lifecycleTaskRunners(task string) (map[string]*taskrunner.TaskRunner, error) {
+	r.taskLock.RLock()
+	defer r.taskLock.RUnlock()
+
+	if task != "" {
+		tr, ok := r.tasks[task]
+		if !ok {
+			return nil, fmt.Errorf("allocation %q has no task %q", r.allocID, task)
+		}
+		if !tr.IsRunning() {
+			return nil, fmt.Errorf("task %q is not running", task)
+		}
+		return map[string]*taskrunner.TaskRunner{task: tr}, nil
+	}
+
+	runners := make(map[string]*taskrunner.TaskRunner, len(r.tasks))
+	for name, tr := range r.tasks {
+		if tr.IsRunning() {
+			runners[name] = tr
+		}
+	}
+	if len(runners) == 0 {
+		return nil, fmt.Errorf("allocation %q has no running tasks", r.allocID)
+	}
+	return runners, nil
+}
+
 // sumTaskResourceUsage takes a set of task resources and sums their resources
 func sumTaskResourceUsage(usages []*cstructs.TaskResourceUsage) *cstructs.ResourceUsage {
 	summed := &cstructs.ResourceUsage{
@@ -0,0 +1,83 @@
+package allocrunner
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// mountCSIVolumes claims and mounts the CSI volumes requested by the task
+// group and returns their host paths indexed by volume name. Volumes mounted
+// before an error are unmounted when the allocation is destroyed.
+func (r *AllocRunner) mountCSIVolumes(tg *structs.TaskGroup) (map[string]string, error) {
+	paths := make(map[string]string)
+	for name, req := range tg.Volumes {
+		if req.Type != structs.VolumeTypeCSI {
+			continue
+		}
+		if r.csiManager == nil {
+			return nil, fmt.Errorf("CSI volumes are not supported")
+		}
+
+		path, err := r.csiManager.MountVolume(r.ctx, r.Alloc(), req)
+		if err != nil {
+			if r.ctx.Err() != nil {
+				return nil, r.ctx.Err()
+			}
+			return nil, err
+		}
+		paths[name] = path
+	}
+
+	return paths, nil
+}
+
+// unmountCSIVolumes unmounts the CSI volumes of the allocation and releases
+// its claims on them.
+func (r *AllocRunner) unmountCSIVolumes() {
+	if r.csiManager == nil {
+		return
+	}
+
+	alloc := r.Alloc()
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return
+	}
+
+	for _, req := range tg.Volumes {
+		if req.Type != structs.VolumeTypeCSI {
+			continue
+		}
+		if err := r.csiManager.UnmountVolume(alloc, req); err != nil {
+			r.logger.Printf("[ERR] client: alloc %q failed to unmount CSI volume %q: %v", r.allocID, req.Source, err)
+		}
+	}
+}
+
+// csiPluginMount adds the plugin directory to the mounts of tasks running a
+// CSI plugin, and starts fingerprinting the plugin. The plugin is removed
+// from the node once the alloc runner exits.
+func (r *AllocRunner) csiPluginMount(task *structs.Task, mounts []*allocdir.HostVolumeMount) ([]*allocdir.HostVolumeMount, error) {
+	cfg := task.CSIPluginConfig
+	if cfg == nil {
+		return mounts, nil
+	}
+	if r.csiManager == nil {
+		return nil, fmt.Errorf("CSI plugins are not supported")
+	}
+
+	dir := r.csiManager.PluginDir(cfg)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create CSI plugin dir: %v", err)
+	}
+
+	go r.csiManager.WatchPlugin(r.ctx, r.allocID, cfg)
+
+	return append(mounts, &allocdir.HostVolumeMount{
+		Source:      dir,
+		Destination: cfg.MountDir,
+	}), nil
+}
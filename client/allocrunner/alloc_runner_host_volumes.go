@@ -0,0 +1,61 @@
+package allocrunner
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// taskHostVolumes resolves the volume mounts of a task against the host
+// volumes configured on the client and the host paths of the CSI volumes
+// mounted for the allocation, indexed by volume name.
+func taskHostVolumes(tg *structs.TaskGroup, task *structs.Task,
+	hostVolumes map[string]*structs.ClientHostVolumeConfig, csiVolumes map[string]string) ([]*allocdir.HostVolumeMount, error) {
+
+	if len(task.VolumeMounts) == 0 {
+		return nil, nil
+	}
+
+	mounts := make([]*allocdir.HostVolumeMount, 0, len(task.VolumeMounts))
+	for _, vm := range task.VolumeMounts {
+		req, ok := tg.Volumes[vm.Volume]
+		if !ok {
+			return nil, fmt.Errorf("task %q mounts unknown volume %q", task.Name, vm.Volume)
+		}
+		if req.Type == structs.VolumeTypeCSI {
+			path, ok := csiVolumes[req.Name]
+			if !ok {
+				return nil, fmt.Errorf("CSI volume %q is not mounted", req.Source)
+			}
+
+			mounts = append(mounts, &allocdir.HostVolumeMount{
+				Source:      path,
+				Destination: vm.Destination,
+				ReadOnly:    req.ReadOnly || vm.ReadOnly,
+			})
+			continue
+		}
+		if req.Type != structs.VolumeTypeHost {
+			return nil, fmt.Errorf("volume %q has unsupported type %q", req.Name, req.Type)
+		}
+
+		hv, ok := hostVolumes[req.Source]
+		if !ok {
+			return nil, fmt.Errorf("host volume %q not found", req.Source)
+		}
+
+		readOnly := hv.ReadOnly || req.ReadOnly || vm.ReadOnly
+		if hv.ReadOnly && !req.ReadOnly {
+			return nil, fmt.Errorf("host volume %q is read only", req.Source)
+		}
+
+		mounts = append(mounts, &allocdir.HostVolumeMount{
+			Source:      hv.Path,
+			Destination: vm.Destination,
+			ReadOnly:    readOnly,
+		})
+	}
+
+	return mounts, nil
+}
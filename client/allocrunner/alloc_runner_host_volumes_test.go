@@ -0,0 +1,85 @@
+package allocrunner
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskHostVolumes(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	hostVolumes := map[string]*structs.ClientHostVolumeConfig{
+		"shared_data": {Name: "shared_data", Path: "/srv/data"},
+		"certs":       {Name: "certs", Path: "/etc/ssl/certs", ReadOnly: true},
+	}
+	tg := &structs.TaskGroup{
+		Volumes: map[string]*structs.VolumeRequest{
+			"data":  {Name: "data", Type: structs.VolumeTypeHost, Source: "shared_data"},
+			"certs": {Name: "certs", Type: structs.VolumeTypeHost, Source: "certs", ReadOnly: true},
+		},
+	}
+	task := &structs.Task{
+		Name: "web",
+		VolumeMounts: []*structs.VolumeMount{
+			{Volume: "data", Destination: "/data"},
+			{Volume: "certs", Destination: "/certs"},
+		},
+	}
+
+	mounts, err := taskHostVolumes(tg, task, hostVolumes, nil)
+	require.NoError(err)
+	require.Equal([]*allocdir.HostVolumeMount{
+		{Source: "/srv/data", Destination: "/data"},
+		{Source: "/etc/ssl/certs", Destination: "/certs", ReadOnly: true},
+	}, mounts)
+
+	// Tasks without mounts don't need any host volumes
+	mounts, err = taskHostVolumes(tg, &structs.Task{Name: "sidecar"}, nil, nil)
+	require.NoError(err)
+	require.Nil(mounts)
+
+	// Missing host volumes fail
+	_, err = taskHostVolumes(tg, task, map[string]*structs.ClientHostVolumeConfig{}, nil)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+
+	// Writable volumes can't use read only host volumes
+	tg.Volumes["certs"].ReadOnly = false
+	_, err = taskHostVolumes(tg, task, hostVolumes, nil)
+	require.Error(err)
+	require.Contains(err.Error(), "read only")
+}
+
+func TestTaskHostVolumes_CSI(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	tg := &structs.TaskGroup{
+		Volumes: map[string]*structs.VolumeRequest{
+			"data": {Name: "data", Type: structs.VolumeTypeCSI, Source: "ebs-1", ReadOnly: true},
+		},
+	}
+	task := &structs.Task{
+		Name: "web",
+		VolumeMounts: []*structs.VolumeMount{
+			{Volume: "data", Destination: "/data"},
+		},
+	}
+
+	// CSI volumes are mounted from the path they were published at
+	csiVolumes := map[string]string{"data": "/var/nomad/csi/node/ebs/per-alloc/a/ebs-1"}
+	mounts, err := taskHostVolumes(tg, task, nil, csiVolumes)
+	require.NoError(err)
+	require.Equal([]*allocdir.HostVolumeMount{
+		{Source: "/var/nomad/csi/node/ebs/per-alloc/a/ebs-1", Destination: "/data", ReadOnly: true},
+	}, mounts)
+
+	// Unmounted CSI volumes fail
+	_, err = taskHostVolumes(tg, task, nil, nil)
+	require.Error(err)
+	require.Contains(err.Error(), "not mounted")
+}
@@ -827,7 +827,7 @@ func TestAllocRunner_SaveRestoreState(t *testing.T) {
 	alloc2 := &structs.Allocation{ID: ar.alloc.ID}
 	prevAlloc := NewAllocWatcher(alloc2, ar, nil, ar.config, l2, "")
 	ar2 := NewAllocRunner(l2, ar.config, ar.stateDB, upd.Update,
-		alloc2, ar.vaultClient, ar.consulClient, prevAlloc)
+		alloc2, ar.vaultClient, ar.consulClient, prevAlloc, nil, nil, nil)
 	err = ar2.RestoreState()
 	if err != nil {
 		t.Fatalf("err: %v", err)
@@ -922,7 +922,7 @@ func TestAllocRunner_SaveRestoreState_TerminalAlloc(t *testing.T) {
 	alloc2 := &structs.Allocation{ID: ar.alloc.ID}
 	prevAlloc := NewAllocWatcher(alloc2, ar, nil, ar.config, l2, "")
 	ar2 := NewAllocRunner(l2, ar.config, ar.stateDB, upd.Update,
-		alloc2, ar.vaultClient, ar.consulClient, prevAlloc)
+		alloc2, ar.vaultClient, ar.consulClient, prevAlloc, nil, nil, nil)
 	err = ar2.RestoreState()
 	if err != nil {
 		t.Fatalf("err: %v", err)
@@ -1259,10 +1259,10 @@ func TestAllocRunner_TaskLeader_StopRestoredTG(t *testing.T) {
 	ar.tasks = map[string]*taskrunner.TaskRunner{
 		"leader": taskrunner.NewTaskRunner(ar.logger, ar.config, ar.stateDB, ar.setTaskState,
 			ar.allocDir.NewTaskDir(task2.Name), ar.Alloc(), task2.Copy(),
-			ar.vaultClient, ar.consulClient),
+			ar.vaultClient, ar.consulClient, nil, nil),
 		"follower1": taskrunner.NewTaskRunner(ar.logger, ar.config, ar.stateDB, ar.setTaskState,
 			ar.allocDir.NewTaskDir(task.Name), ar.Alloc(), task.Copy(),
-			ar.vaultClient, ar.consulClient),
+			ar.vaultClient, ar.consulClient, nil, nil),
 	}
 	ar.taskStates = map[string]*structs.TaskState{
 		"leader":    {State: structs.TaskStateDead},
@@ -1275,7 +1275,7 @@ func TestAllocRunner_TaskLeader_StopRestoredTG(t *testing.T) {
 	// Create a new AllocRunner to test RestoreState and Run
 	upd2 := &MockAllocStateUpdater{}
 	ar2 := NewAllocRunner(ar.logger, ar.config, ar.stateDB, upd2.Update, ar.alloc,
-		ar.vaultClient, ar.consulClient, ar.prevAlloc)
+		ar.vaultClient, ar.consulClient, ar.prevAlloc, nil, nil, nil)
 	defer ar2.Destroy()
 
 	if err := ar2.RestoreState(); err != nil {
@@ -2,6 +2,7 @@ package taskrunner
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -38,6 +39,10 @@ const (
 	// DefaultMaxTemplateEventRate is the default maximum rate at which a
 	// template event should be fired.
 	DefaultMaxTemplateEventRate = 3 * time.Second
+
+	// variableEnvPrefix is the prefix of the keys the items of variables are
+	// exposed to templates under
+	variableEnvPrefix = "nomad_var:"
 )
 
 // TaskHooks is an interface which provides hooks into the tasks life-cycle
@@ -105,6 +110,15 @@ type TaskTemplateManagerConfig struct {
 	// EnvBuilder is the environment variable builder for the task.
 	EnvBuilder *env.Builder
 
+	// Variables are the variables the templates may read using the env
+	// function. See VariableEnvKey.
+	Variables []*structs.VariableDecrypted
+
+	// Rerender marks that the manager replaces one that already rendered the
+	// templates of the task. The change mode of the templates whose content
+	// changed on the first render is applied as if they were re-rendered.
+	Rerender bool
+
 	// MaxTemplateEventRate is the maximum rate at which we should emit events.
 	MaxTemplateEventRate time.Duration
 
@@ -228,8 +242,13 @@ func (tm *TaskTemplateManager) run() {
 		return
 	}
 
-	// handle all subsequent render events.
-	tm.handleTemplateRerenders(time.Now())
+	// handle all subsequent render events. When replacing a manager, the
+	// first render is handled as a re-render.
+	allRenderedTime := time.Now()
+	if tm.config.Rerender {
+		allRenderedTime = time.Time{}
+	}
+	tm.handleTemplateRerenders(allRenderedTime)
 }
 
 // handleFirstRender blocks till all templates have been rendered
@@ -350,6 +369,15 @@ func (tm *TaskTemplateManager) handleTemplateRerenders(allRenderedTime time.Time
 	// A lookup for the last time the template was handled
 	handledRenders := make(map[string]time.Time, len(tm.config.Templates))
 
+	// If the first render is handled as a re-render, the render events are
+	// checked right away since handleFirstRender consumed the notification.
+	renderedCh := tm.runner.TemplateRenderedCh()
+	if allRenderedTime.IsZero() {
+		replayCh := make(chan struct{}, 1)
+		replayCh <- struct{}{}
+		renderedCh = replayCh
+	}
+
 	for {
 		select {
 		case <-tm.shutdownCh:
@@ -360,7 +388,9 @@ func (tm *TaskTemplateManager) handleTemplateRerenders(allRenderedTime time.Time
 			}
 
 			tm.config.Hooks.Kill(consulTemplateSourceName, err.Error(), true)
-		case <-tm.runner.TemplateRenderedCh():
+		case <-renderedCh:
+			renderedCh = tm.runner.TemplateRenderedCh()
+
 			// A template has been rendered, figure out what to do
 			var handling []string
 			signals := make(map[string]struct{})
@@ -468,6 +498,40 @@ func (tm *TaskTemplateManager) allTemplatesNoop() bool {
 	return true
 }
 
+// VariableEnvKey returns the key under which the item of a variable is
+// exposed to templates. For example the "password" item of the variable at
+// "nomad/jobs/example" is read with:
+//
+//	{{ env "nomad_var:nomad/jobs/example:password" }}
+func VariableEnvKey(path, key string) string {
+	return fmt.Sprintf("%s%s:%s", variableEnvPrefix, path, key)
+}
+
+// templatesReadVariables returns whether any of the templates reads
+// variables. Templates with a relative source path are read from the task
+// directory; templates with an absolute source path are assumed to read
+// variables.
+func templatesReadVariables(templates []*structs.Template, taskDir string, taskEnv *env.TaskEnv) bool {
+	for _, tmpl := range templates {
+		contents := tmpl.EmbeddedTmpl
+		if tmpl.SourcePath != "" {
+			if filepath.IsAbs(tmpl.SourcePath) {
+				return true
+			}
+			buf, err := ioutil.ReadFile(filepath.Join(taskDir, taskEnv.ReplaceEnv(tmpl.SourcePath)))
+			if err != nil {
+				// The error is reported when the templates are parsed
+				return true
+			}
+			contents = string(buf)
+		}
+		if strings.Contains(contents, variableEnvPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // templateRunner returns a consul-template runner for the given templates and a
 // lookup by destination to the template. If no templates are in the config, a
 // nil template runner and lookup is returned.
@@ -495,8 +559,15 @@ func templateRunner(config *TaskTemplateManagerConfig) (
 		return nil, nil, err
 	}
 
-	// Set Nomad's environment variables
+	// Set Nomad's environment variables along with the items of the
+	// variables. The items are only visible to the templates and are not part
+	// of the task's environment.
 	runner.Env = config.EnvBuilder.Build().All()
+	for _, sv := range config.Variables {
+		for k, v := range sv.Items {
+			runner.Env[VariableEnvKey(sv.Path, k)] = v
+		}
+	}
 
 	// Build the lookup
 	idMap := runner.TemplateConfigMapping()
@@ -110,6 +110,8 @@ type testHarness struct {
 	node       *structs.Node
 	config     *config.Config
 	vaultToken string
+	variables  []*structs.VariableDecrypted
+	rerender   bool
 	taskDir    string
 	vault      *testutil.TestVault
 	consul     *ctestutil.TestServer
@@ -175,6 +177,8 @@ func (h *testHarness) startWithErr() error {
 		VaultToken:           h.vaultToken,
 		TaskDir:              h.taskDir,
 		EnvBuilder:           h.envBuilder,
+		Variables:            h.variables,
+		Rerender:             h.rerender,
 		MaxTemplateEventRate: h.emitRate,
 		retryRate:            10 * time.Millisecond,
 	})
@@ -475,6 +479,160 @@ func TestTaskTemplateManager_Unblock_Static_NomadEnv(t *testing.T) {
 	}
 }
 
+func TestTaskTemplateManager_Unblock_Static_Variables(t *testing.T) {
+	t.Parallel()
+	// Make a template that will render immediately
+	content := `password: {{env "nomad_var:nomad/jobs/example:password"}}`
+	expected := "password: hunter2"
+	file := "my.tmpl"
+	template := &structs.Template{
+		EmbeddedTmpl: content,
+		DestPath:     file,
+		ChangeMode:   structs.TemplateChangeModeNoop,
+	}
+
+	harness := newTestHarness(t, []*structs.Template{template}, false, false)
+	harness.variables = []*structs.VariableDecrypted{
+		{
+			VariableMetadata: structs.VariableMetadata{Path: "nomad/jobs/example"},
+			Items:            structs.VariableItems{"password": "hunter2"},
+		},
+	}
+	harness.start(t)
+	defer harness.stop()
+
+	// Wait for the unblock
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	// Check the file is there
+	path := filepath.Join(harness.taskDir, file)
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read rendered template from %q: %v", path, err)
+	}
+
+	if s := string(raw); s != expected {
+		t.Fatalf("Unexpected template data; got %q, want %q", s, expected)
+	}
+
+	// The items must not leak into the task's environment
+	if _, ok := harness.envBuilder.Build().Map()[VariableEnvKey("nomad/jobs/example", "password")]; ok {
+		t.Fatalf("variable item found in task environment")
+	}
+}
+
+func TestTaskTemplateManager_Rerender_Variables(t *testing.T) {
+	t.Parallel()
+	// Make a template reading a variable that has changed since the
+	// template was rendered by the replaced manager
+	content := `password: {{env "nomad_var:nomad/jobs/example:password"}}`
+	expected := "password: hunter3"
+	file := "my.tmpl"
+	template := &structs.Template{
+		EmbeddedTmpl: content,
+		DestPath:     file,
+		ChangeMode:   structs.TemplateChangeModeRestart,
+	}
+
+	harness := newTestHarness(t, []*structs.Template{template}, false, false)
+	harness.variables = []*structs.VariableDecrypted{
+		{
+			VariableMetadata: structs.VariableMetadata{Path: "nomad/jobs/example"},
+			Items:            structs.VariableItems{"password": "hunter3"},
+		},
+	}
+	harness.rerender = true
+
+	// Write the contents rendered with the old variable
+	path := filepath.Join(harness.taskDir, file)
+	if err := ioutil.WriteFile(path, []byte("password: hunter2"), 0644); err != nil {
+		t.Fatalf("Failed to write data: %v", err)
+	}
+
+	harness.start(t)
+	defer harness.stop()
+
+	// The change mode must be applied
+	select {
+	case <-harness.mockHooks.RestartCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task restart should have been called")
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read rendered template from %q: %v", path, err)
+	}
+	if s := string(raw); s != expected {
+		t.Fatalf("Unexpected template data; got %q, want %q", s, expected)
+	}
+}
+
+func TestTaskTemplateManager_TemplatesReadVariables(t *testing.T) {
+	t.Parallel()
+	taskDir, err := ioutil.TempDir("", "ct_test")
+	if err != nil {
+		t.Fatalf("Failed to make tmpdir: %v", err)
+	}
+	defer os.RemoveAll(taskDir)
+
+	if err := ioutil.WriteFile(filepath.Join(taskDir, "vars.tmpl"), []byte(`{{env "nomad_var:nomad/jobs/example:a"}}`), 0644); err != nil {
+		t.Fatalf("Failed to write data: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(taskDir, "plain.tmpl"), []byte(`{{env "NOMAD_TASK_NAME"}}`), 0644); err != nil {
+		t.Fatalf("Failed to write data: %v", err)
+	}
+
+	a := mock.Alloc()
+	taskEnv := env.NewBuilder(mock.Node(), a, a.Job.TaskGroups[0].Tasks[0], "global").Build()
+
+	cases := []struct {
+		name      string
+		templates []*structs.Template
+		expected  bool
+	}{
+		{
+			name:     "no templates",
+			expected: false,
+		},
+		{
+			name:      "embedded without variables",
+			templates: []*structs.Template{{EmbeddedTmpl: "hello"}},
+			expected:  false,
+		},
+		{
+			name:      "embedded with variables",
+			templates: []*structs.Template{{EmbeddedTmpl: `{{env "nomad_var:nomad/jobs/example:a"}}`}},
+			expected:  true,
+		},
+		{
+			name:      "source without variables",
+			templates: []*structs.Template{{SourcePath: "plain.tmpl"}},
+			expected:  false,
+		},
+		{
+			name:      "source with variables",
+			templates: []*structs.Template{{SourcePath: "plain.tmpl"}, {SourcePath: "vars.tmpl"}},
+			expected:  true,
+		},
+		{
+			name:      "missing source",
+			templates: []*structs.Template{{SourcePath: "missing.tmpl"}},
+			expected:  true,
+		},
+	}
+
+	for _, c := range cases {
+		if act := templatesReadVariables(c.templates, taskDir, taskEnv); act != c.expected {
+			t.Fatalf("%s: got %v; want %v", c.name, act, c.expected)
+		}
+	}
+}
+
 func TestTaskTemplateManager_Unblock_Static_AlreadyRendered(t *testing.T) {
 	t.Parallel()
 	// Make a template that will render immediately
@@ -2,8 +2,8 @@ package taskrunner
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
-	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -58,9 +58,29 @@ const (
 	// to retrieve a Vault token
 	vaultBackoffLimit = 3 * time.Minute
 
+	// variablesBackoffBaseline is the baseline time for exponential backoff
+	// when watching the variables read by the task's templates fails
+	variablesBackoffBaseline = 5 * time.Second
+
+	// variablesBackoffLimit is the limit of the exponential backoff when
+	// watching the variables read by the task's templates fails
+	variablesBackoffLimit = 3 * time.Minute
+
+	// identityBackoffBaseline is the baseline time for exponential backoff
+	// when renewing the workload identity of the task fails
+	identityBackoffBaseline = 5 * time.Second
+
+	// identityBackoffLimit is the limit of the exponential backoff when
+	// renewing the workload identity of the task fails
+	identityBackoffLimit = 3 * time.Minute
+
 	// vaultTokenFile is the name of the file holding the Vault token inside the
 	// task's secret directory
 	vaultTokenFile = "vault_token"
+
+	// workloadTokenFile is the name of the file holding the workload identity
+	// inside the task's secret directory
+	workloadTokenFile = "nomad_token"
 )
 
 var (
@@ -88,7 +108,7 @@ func newTaskRestartEvent(reason string, failure bool) *taskRestartEvent {
 
 // TaskRunner is used to wrap a task within an allocation and provide the execution context.
 type TaskRunner struct {
-	stateDB        *bolt.DB
+	stateDB        state.StateDB
 	config         *config.Config
 	updater        TaskStateUpdater
 	logger         *log.Logger
@@ -148,8 +168,29 @@ type TaskRunner struct {
 	// vaultClient is used to retrieve and renew any needed Vault token
 	vaultClient vaultclient.VaultClient
 
-	// templateManager is used to manage any consul-templates this task may have
-	templateManager *TaskTemplateManager
+	// variablesFetcher is used to retrieve the variables rendered by the
+	// task's templates. It may be nil if variables are not available.
+	variablesFetcher VariablesFetcher
+
+	// variables are the variables last fetched for the task's templates.
+	// Once fetched they are watched for changes.
+	variables         []*structs.VariableDecrypted
+	watchingVariables bool
+	variablesLock     sync.Mutex
+
+	// identitySigner is used to retrieve the signed workload identity of the
+	// task. It may be nil if identities are not available.
+	identitySigner IdentitySigner
+
+	// workloadToken is the signed workload identity of the task. It is only
+	// retrieved if the task's identity is exposed to it.
+	workloadToken     string
+	workloadTokenLock sync.Mutex
+
+	// templateManager is used to manage any consul-templates this task may
+	// have. The lock serializes replacing it.
+	templateManager     *TaskTemplateManager
+	templateManagerLock sync.Mutex
 
 	// startCh is used to trigger the start of the task
 	startCh chan struct{}
@@ -217,6 +258,15 @@ func (s *taskRunnerState) Hash() []byte {
 // is set the event won't be immediately pushed to the server.
 type TaskStateUpdater func(taskName, state string, event *structs.TaskEvent, lazySync bool)
 
+// VariablesFetcher is used to retrieve the variables the allocation's tasks
+// may read from their templates. It blocks until the variables change after
+// the given index and returns the index they were read at.
+type VariablesFetcher func(alloc *structs.Allocation, minIndex uint64) ([]*structs.VariableDecrypted, uint64, error)
+
+// IdentitySigner is used to retrieve the signed workload identity of a task
+// of the allocation and the time at which it expires
+type IdentitySigner func(alloc *structs.Allocation, task string) (string, time.Time, error)
+
 // SignalEvent is a tuple of the signal and the event generating it
 type SignalEvent struct {
 	// s is the signal to be sent
@@ -231,9 +281,10 @@ type SignalEvent struct {
 
 // NewTaskRunner is used to create a new task context
 func NewTaskRunner(logger *log.Logger, config *config.Config,
-	stateDB *bolt.DB, updater TaskStateUpdater, taskDir *allocdir.TaskDir,
+	stateDB state.StateDB, updater TaskStateUpdater, taskDir *allocdir.TaskDir,
 	alloc *structs.Allocation, task *structs.Task,
-	vaultClient vaultclient.VaultClient, consulClient consulApi.ConsulServiceAPI) *TaskRunner {
+	vaultClient vaultclient.VaultClient, consulClient consulApi.ConsulServiceAPI,
+	variablesFetcher VariablesFetcher, identitySigner IdentitySigner) *TaskRunner {
 
 	// Merge in the task resources
 	task.Resources = alloc.TaskResources[task.Name]
@@ -262,6 +313,8 @@ func NewTaskRunner(logger *log.Logger, config *config.Config,
 		createdResources: driver.NewCreatedResources(),
 		consul:           consulClient,
 		vaultClient:      vaultClient,
+		variablesFetcher: variablesFetcher,
+		identitySigner:   identitySigner,
 		vaultFuture:      NewTokenFuture().Set(""),
 		updateCh:         make(chan *structs.Allocation, 64),
 		destroyCh:        make(chan struct{}),
@@ -334,19 +387,6 @@ func (r *TaskRunner) getHandle() driver.DriverHandle {
 	return h
 }
 
-// pre060StateFilePath returns the path to our state file that would have been
-// written pre v0.6.0
-// COMPAT: Remove in 0.7.0
-func (r *TaskRunner) pre060StateFilePath() string {
-	// Get the MD5 of the task name
-	hashVal := md5.Sum([]byte(r.task.Name))
-	hashHex := hex.EncodeToString(hashVal[:])
-	dirName := fmt.Sprintf("task-%s", hashHex)
-
-	// Generate the path
-	return filepath.Join(r.config.StateDir, "alloc", r.alloc.ID, dirName, "state.json")
-}
-
 // RestoreState is used to restore our state. If a non-empty string is returned
 // the task is restarted with the string as the reason. This is useful for
 // backwards incompatible upgrades that need to restart tasks with a new
@@ -902,40 +942,229 @@ func (r *TaskRunner) writeToken(token string) error {
 	return nil
 }
 
-// updatedTokenHandler is called when a new Vault token is retrieved. Things
-// that rely on the token should be updated here.
-func (r *TaskRunner) updatedTokenHandler() {
+// writeWorkloadToken writes the given workload identity to disk
+func (r *TaskRunner) writeWorkloadToken(token string) error {
+	tokenPath := filepath.Join(r.taskDir.SecretsDir, workloadTokenFile)
+	if err := ioutil.WriteFile(tokenPath, []byte(token), 0600); err != nil {
+		return fmt.Errorf("failed to save workload identity to secret dir for task %q in alloc %q: %v", r.task.Name, r.alloc.ID, err)
+	}
 
-	// Update the tasks environment
-	r.envBuilder.SetVaultToken(r.vaultFuture.Get(), r.task.Vault.Env)
+	return nil
+}
 
-	if r.templateManager != nil {
-		r.templateManager.Stop()
+// setWorkloadToken exposes the given workload identity to the task as
+// configured by its identity block. An updated identity in the environment
+// takes effect when the task is next started.
+func (r *TaskRunner) setWorkloadToken(token string) error {
+	r.workloadTokenLock.Lock()
+	defer r.workloadTokenLock.Unlock()
 
-		// Create a new templateManager
-		var err error
-		r.templateManager, err = NewTaskTemplateManager(&TaskTemplateManagerConfig{
-			Hooks:                r,
-			Templates:            r.task.Templates,
-			ClientConfig:         r.config,
-			VaultToken:           r.vaultFuture.Get(),
-			TaskDir:              r.taskDir.Dir,
-			EnvBuilder:           r.envBuilder,
-			MaxTemplateEventRate: DefaultMaxTemplateEventRate,
-		})
+	identity := r.task.Identity
+	if !identity.Exposed() {
+		return nil
+	}
+	if identity.File {
+		if err := r.writeWorkloadToken(token); err != nil {
+			return err
+		}
+	}
+	if identity.Env {
+		r.envBuilder.SetWorkloadToken(token)
+	}
+	r.workloadToken = token
+	return nil
+}
 
+// renewWorkloadToken renews the workload identity of the task halfway to its
+// expiry until the task exits.
+func (r *TaskRunner) renewWorkloadToken(expiry time.Time) {
+	renewAt := time.Now().Add(expiry.Sub(time.Now()) / 2)
+	attempts := 0
+	for {
+		select {
+		case <-r.waitCh:
+			return
+		case <-time.After(renewAt.Sub(time.Now())):
+		}
+
+		token, newExpiry, err := r.identitySigner(r.alloc, r.task.Name)
+		if err == nil {
+			err = r.setWorkloadToken(token)
+		}
 		if err != nil {
-			err := fmt.Errorf("failed to build task's template manager: %v", err)
-			r.setState(structs.TaskStateDead,
-				structs.NewTaskEvent(structs.TaskSetupFailure).SetSetupError(err).SetFailsTask(),
-				false)
-			r.logger.Printf("[ERR] client: alloc %q, task %q %v", r.alloc.ID, r.task.Name, err)
-			r.Kill("vault", err.Error(), true)
+			backoff := (1 << (2 * uint64(attempts))) * identityBackoffBaseline
+			if backoff > identityBackoffLimit {
+				backoff = identityBackoffLimit
+			} else {
+				attempts++
+			}
+			r.logger.Printf("[WARN] client: failed to renew workload identity of task %v on alloc %q: %v; backing off for %v",
+				r.task.Name, r.alloc.ID, err, backoff)
+			renewAt = time.Now().Add(backoff)
+			continue
+		}
+
+		attempts = 0
+		renewAt = time.Now().Add(newExpiry.Sub(time.Now()) / 2)
+	}
+}
+
+// fetchVariables returns the variables the task's templates read and starts
+// watching them for changes. Nothing is fetched if no template reads
+// variables.
+func (r *TaskRunner) fetchVariables() ([]*structs.VariableDecrypted, error) {
+	if r.variablesFetcher == nil ||
+		!templatesReadVariables(r.task.Templates, r.taskDir.Dir, r.envBuilder.Build()) {
+		return nil, nil
+	}
+
+	r.variablesLock.Lock()
+	defer r.variablesLock.Unlock()
+	if r.watchingVariables {
+		return r.variables, nil
+	}
+
+	variables, index, err := r.variablesFetcher(r.alloc, 0)
+	if err != nil {
+		// Servers that predate variables don't have the RPC, in which case
+		// there are no variables for the templates to read
+		if structs.IsErrUnknownRPCMethod(err) {
+			r.logger.Printf("[WARN] client: alloc %q, task %q: templates read variables but the servers don't support them", r.alloc.ID, r.task.Name)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch variables: %v", err)
+	}
+
+	r.variables = variables
+	r.watchingVariables = true
+	go r.watchVariables(index)
+	return variables, nil
+}
+
+// watchVariables blocks on changes to the variables read by the task's
+// templates and re-renders the templates when they change.
+func (r *TaskRunner) watchVariables(index uint64) {
+	attempts := 0
+	for {
+		variables, newIndex, err := r.variablesFetcher(r.alloc, index)
+
+		select {
+		case <-r.waitCh:
 			return
+		default:
+		}
+
+		if err != nil {
+			backoff := (1 << (2 * uint64(attempts))) * variablesBackoffBaseline
+			if backoff > variablesBackoffLimit {
+				backoff = variablesBackoffLimit
+			} else {
+				attempts++
+			}
+			r.logger.Printf("[WARN] client: failed to watch variables for task %v on alloc %q: %v; backing off for %v",
+				r.task.Name, r.alloc.ID, err, backoff)
+
+			select {
+			case <-r.waitCh:
+				return
+			case <-time.After(backoff):
+			}
+			continue
+		}
+		attempts = 0
+
+		if newIndex <= index {
+			continue
+		}
+		index = newIndex
+
+		r.variablesLock.Lock()
+		changed := !variablesEqual(r.variables, variables)
+		r.variables = variables
+		r.variablesLock.Unlock()
+
+		if changed {
+			r.updatedVariablesHandler()
 		}
 	}
 }
 
+// variablesEqual returns whether the two sets of variables are the same
+// versions of the same variables.
+func variablesEqual(a, b []*structs.VariableDecrypted) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Path != b[i].Path || a[i].ModifyIndex != b[i].ModifyIndex {
+			return false
+		}
+	}
+	return true
+}
+
+// newTemplateManager returns a template manager for the task's templates.
+// Rerender marks that it replaces a manager that already rendered them.
+func (r *TaskRunner) newTemplateManager(rerender bool) (*TaskTemplateManager, error) {
+	variables, err := r.fetchVariables()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTaskTemplateManager(&TaskTemplateManagerConfig{
+		Hooks:                r,
+		Templates:            r.task.Templates,
+		ClientConfig:         r.config,
+		VaultToken:           r.vaultFuture.Get(),
+		TaskDir:              r.taskDir.Dir,
+		EnvBuilder:           r.envBuilder,
+		Variables:            variables,
+		Rerender:             rerender,
+		MaxTemplateEventRate: DefaultMaxTemplateEventRate,
+	})
+}
+
+// replaceTemplateManager replaces the running template manager with a new
+// one. The task is killed if the new manager can't be built.
+func (r *TaskRunner) replaceTemplateManager(source string, rerender bool) {
+	r.templateManagerLock.Lock()
+	defer r.templateManagerLock.Unlock()
+
+	if r.templateManager == nil {
+		return
+	}
+	r.templateManager.Stop()
+
+	var err error
+	r.templateManager, err = r.newTemplateManager(rerender)
+	if err != nil {
+		err := fmt.Errorf("failed to build task's template manager: %v", err)
+		r.setState(structs.TaskStateDead,
+			structs.NewTaskEvent(structs.TaskSetupFailure).SetSetupError(err).SetFailsTask(),
+			false)
+		r.logger.Printf("[ERR] client: alloc %q, task %q %v", r.alloc.ID, r.task.Name, err)
+		r.Kill(source, err.Error(), true)
+	}
+}
+
+// updatedTokenHandler is called when a new Vault token is retrieved. Things
+// that rely on the token should be updated here.
+func (r *TaskRunner) updatedTokenHandler() {
+
+	// Update the tasks environment
+	r.envBuilder.SetVaultToken(r.vaultFuture.Get(), r.task.Vault.Env)
+
+	// Create a new templateManager
+	r.replaceTemplateManager("vault", false)
+}
+
+// updatedVariablesHandler is called when the variables read by the task's
+// templates change. The templates are re-rendered with the new variables and
+// the change mode of the templates whose content changed is applied.
+func (r *TaskRunner) updatedVariablesHandler() {
+	r.replaceTemplateManager("variables", true)
+}
+
 // prestart handles life-cycle tasks that occur before the task has started.
 // Since it's run asynchronously with the main Run() loop the alloc & task are
 // passed in to avoid racing with updates.
@@ -995,6 +1224,26 @@ func (r *TaskRunner) prestart(alloc *structs.Allocation, task *structs.Task, res
 		downloaded := r.artifactsDownloaded
 		r.persistLock.Unlock()
 
+		// Retrieve the workload identity of the task if it is exposed to it
+		r.workloadTokenLock.Lock()
+		retrieved := r.workloadToken != ""
+		r.workloadTokenLock.Unlock()
+		if r.identitySigner != nil && task.Identity.Exposed() && !retrieved {
+			token, expiry, err := r.identitySigner(alloc, task.Name)
+			if err == nil {
+				err = r.setWorkloadToken(token)
+			}
+			if err != nil {
+				wrapped := fmt.Errorf("failed to retrieve workload identity: %v", err)
+				r.logger.Printf("[ERR] client: alloc %q, task %q %v", alloc.ID, task.Name, wrapped)
+				r.setState(structs.TaskStatePending,
+					structs.NewTaskEvent(structs.TaskSetupFailure).SetSetupError(wrapped), false)
+				r.restartTracker.SetStartError(structs.NewRecoverableError(wrapped, true))
+				goto RESTART
+			}
+			go r.renewWorkloadToken(expiry)
+		}
+
 		// Download the task's artifacts
 		if !downloaded && len(task.Artifacts) > 0 {
 			r.setState(structs.TaskStatePending, structs.NewTaskEvent(structs.TaskDownloadingArtifacts), false)
@@ -1028,18 +1277,12 @@ func (r *TaskRunner) prestart(alloc *structs.Allocation, task *structs.Task, res
 		}
 
 		// Build the template manager
+		r.templateManagerLock.Lock()
 		if r.templateManager == nil {
 			var err error
-			r.templateManager, err = NewTaskTemplateManager(&TaskTemplateManagerConfig{
-				Hooks:                r,
-				Templates:            r.task.Templates,
-				ClientConfig:         r.config,
-				VaultToken:           r.vaultFuture.Get(),
-				TaskDir:              r.taskDir.Dir,
-				EnvBuilder:           r.envBuilder,
-				MaxTemplateEventRate: DefaultMaxTemplateEventRate,
-			})
+			r.templateManager, err = r.newTemplateManager(false)
 			if err != nil {
+				r.templateManagerLock.Unlock()
 				err := fmt.Errorf("failed to build task's template manager: %v", err)
 				r.setState(structs.TaskStateDead, structs.NewTaskEvent(structs.TaskSetupFailure).SetSetupError(err).SetFailsTask(), false)
 				r.logger.Printf("[ERR] client: alloc %q, task %q %v", alloc.ID, task.Name, err)
@@ -1047,6 +1290,7 @@ func (r *TaskRunner) prestart(alloc *structs.Allocation, task *structs.Task, res
 				return
 			}
 		}
+		r.templateManagerLock.Unlock()
 
 		// Block for consul-template
 		// TODO Hooks should register themselves as blocking and then we can
@@ -1079,9 +1323,12 @@ func (r *TaskRunner) prestart(alloc *structs.Allocation, task *structs.Task, res
 // postrun is used to do any cleanup that is necessary after exiting the runloop
 func (r *TaskRunner) postrun() {
 	// Stop the template manager
+	r.templateManagerLock.Lock()
 	if r.templateManager != nil {
 		r.templateManager.Stop()
+		r.templateManager = nil
 	}
+	r.templateManagerLock.Unlock()
 }
 
 // run is the main run loop that handles starting the application, destroying
@@ -1783,11 +2030,36 @@ func (r *TaskRunner) Signal(source, reason string, s os.Signal) error {
 	select {
 	case r.signalCh <- se:
 	case <-r.waitCh:
+		return fmt.Errorf("task %q is not running", r.task.Name)
 	}
 
 	return <-resCh
 }
 
+// IsRunning returns whether the task is running
+func (r *TaskRunner) IsRunning() bool {
+	r.runningLock.Lock()
+	defer r.runningLock.Unlock()
+	return r.running
+}
+
+// ExecStreaming runs an interactive command in the context of the running
+// task. An error is returned if the task isn't running or its driver can't
+// run interactive commands.
+func (r *TaskRunner) ExecStreaming(ctx context.Context, cmd []string, tty bool,
+	stream dstructs.ExecStream) (*dstructs.ExecStreamingExitResult, error) {
+	handle := r.getHandle()
+	if handle == nil {
+		return nil, fmt.Errorf("task %q is not running", r.task.Name)
+	}
+
+	exec, ok := handle.(driver.StreamingExecutor)
+	if !ok {
+		return nil, fmt.Errorf("driver %q does not support interactive commands", r.task.Driver)
+	}
+	return exec.ExecStreaming(ctx, cmd, tty, stream)
+}
+
 // Kill will kill a task and store the error, no longer restarting the task. If
 // fail is set, the task is marked as having failed.
 func (r *TaskRunner) Kill(source, reason string, fail bool) {
@@ -1889,6 +2161,8 @@ func (r *TaskRunner) setGaugeForMemory(ru *cstructs.TaskResourceUsage) {
 			float32(ru.ResourceUsage.MemoryStats.Cache), r.baseLabels)
 		metrics.SetGaugeWithLabels([]string{"client", "allocs", "memory", "swap"},
 			float32(ru.ResourceUsage.MemoryStats.Swap), r.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "allocs", "memory", "usage"},
+			float32(ru.ResourceUsage.MemoryStats.Usage), r.baseLabels)
 		metrics.SetGaugeWithLabels([]string{"client", "allocs", "memory", "max_usage"},
 			float32(ru.ResourceUsage.MemoryStats.MaxUsage), r.baseLabels)
 		metrics.SetGaugeWithLabels([]string{"client", "allocs", "memory", "kernel_usage"},
@@ -1901,6 +2175,7 @@ func (r *TaskRunner) setGaugeForMemory(ru *cstructs.TaskResourceUsage) {
 		metrics.SetGauge([]string{"client", "allocs", r.alloc.Job.Name, r.alloc.TaskGroup, r.alloc.ID, r.task.Name, "memory", "rss"}, float32(ru.ResourceUsage.MemoryStats.RSS))
 		metrics.SetGauge([]string{"client", "allocs", r.alloc.Job.Name, r.alloc.TaskGroup, r.alloc.ID, r.task.Name, "memory", "cache"}, float32(ru.ResourceUsage.MemoryStats.Cache))
 		metrics.SetGauge([]string{"client", "allocs", r.alloc.Job.Name, r.alloc.TaskGroup, r.alloc.ID, r.task.Name, "memory", "swap"}, float32(ru.ResourceUsage.MemoryStats.Swap))
+		metrics.SetGauge([]string{"client", "allocs", r.alloc.Job.Name, r.alloc.TaskGroup, r.alloc.ID, r.task.Name, "memory", "usage"}, float32(ru.ResourceUsage.MemoryStats.Usage))
 		metrics.SetGauge([]string{"client", "allocs", r.alloc.Job.Name, r.alloc.TaskGroup, r.alloc.ID, r.task.Name, "memory", "max_usage"}, float32(ru.ResourceUsage.MemoryStats.MaxUsage))
 		metrics.SetGauge([]string{"client", "allocs", r.alloc.Job.Name, r.alloc.TaskGroup, r.alloc.ID, r.task.Name, "memory", "kernel_usage"}, float32(ru.ResourceUsage.MemoryStats.KernelUsage))
 		metrics.SetGauge([]string{"client", "allocs", r.alloc.Job.Name, r.alloc.TaskGroup, r.alloc.ID, r.task.Name, "memory", "kernel_max_usage"}, float32(ru.ResourceUsage.MemoryStats.KernelMaxUsage))
@@ -13,13 +13,13 @@ import (
 	"testing"
 	"time"
 
-	"github.com/boltdb/bolt"
 	"github.com/golang/snappy"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/restarts"
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/driver/env"
+	"github.com/hashicorp/nomad/client/state"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/client/vaultclient"
 	"github.com/hashicorp/nomad/command/agent/consul"
@@ -99,11 +99,11 @@ func testTaskRunnerFromAlloc(t *testing.T, restarts bool, alloc *structs.Allocat
 	conf.StateDir = os.TempDir()
 	conf.AllocDir = os.TempDir()
 
-	tmp, err := ioutil.TempFile("", "state-db")
+	tmp, err := ioutil.TempDir("", "state-db")
 	if err != nil {
-		t.Fatalf("error creating state db file: %v", err)
+		t.Fatalf("error creating state db dir: %v", err)
 	}
-	db, err := bolt.Open(tmp.Name(), 0600, nil)
+	db, err := state.NewBoltStateDB(logger, tmp)
 	if err != nil {
 		t.Fatalf("error creating state db: %v", err)
 	}
@@ -136,7 +136,7 @@ func testTaskRunnerFromAlloc(t *testing.T, restarts bool, alloc *structs.Allocat
 	cclient := consul.NewMockAgent()
 	serviceClient := consul.NewServiceClient(cclient, logger, true)
 	go serviceClient.Run()
-	tr := NewTaskRunner(logger, conf, db, upd.Update, taskDir, alloc, task, vclient, serviceClient)
+	tr := NewTaskRunner(logger, conf, db, upd.Update, taskDir, alloc, task, vclient, serviceClient, nil, nil)
 	if !restarts {
 		tr.restartTracker = noRestartsTracker()
 	}
@@ -441,7 +441,7 @@ func TestTaskRunner_SaveRestoreState(t *testing.T) {
 	// Create a new task runner
 	task2 := &structs.Task{Name: ctx.tr.task.Name, Driver: ctx.tr.task.Driver, Vault: ctx.tr.task.Vault}
 	tr2 := NewTaskRunner(ctx.tr.logger, ctx.tr.config, ctx.tr.stateDB, ctx.upd.Update,
-		ctx.tr.taskDir, ctx.tr.alloc, task2, ctx.tr.vaultClient, ctx.tr.consul)
+		ctx.tr.taskDir, ctx.tr.alloc, task2, ctx.tr.vaultClient, ctx.tr.consul, nil, nil)
 	tr2.restartTracker = noRestartsTracker()
 	if _, err := tr2.RestoreState(); err != nil {
 		t.Fatalf("err: %v", err)
@@ -2028,3 +2028,92 @@ func TestTaskRunner_DriverNetwork(t *testing.T) {
 		t.Fatalf("error: %v", err)
 	})
 }
+
+func TestTaskRunner_WorkloadIdentity(t *testing.T) {
+	t.Parallel()
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Driver = "mock_driver"
+	task.Config = map[string]interface{}{
+		"exit_code": "0",
+		"run_for":   "2s",
+	}
+	task.Identity = &structs.WorkloadIdentity{File: true}
+
+	ctx := testTaskRunnerFromAlloc(t, false, alloc)
+	ctx.tr.MarkReceived()
+	defer ctx.tr.Destroy(structs.NewTaskEvent(structs.TaskKilled))
+	defer ctx.allocDir.Destroy()
+
+	// Sign identities that expire quickly so they are renewed while the task
+	// runs
+	signed := make(chan string, 10)
+	var count int
+	ctx.tr.identitySigner = func(a *structs.Allocation, name string) (string, time.Time, error) {
+		count++
+		token := fmt.Sprintf("token-%d", count)
+		signed <- token
+		return token, time.Now().Add(500 * time.Millisecond), nil
+	}
+	go ctx.tr.Run()
+
+	select {
+	case <-ctx.tr.WaitCh():
+	case <-time.After(time.Duration(testutil.TestMultiplier()*15) * time.Second):
+		t.Fatalf("timeout")
+	}
+
+	if ctx.upd.state != structs.TaskStateDead {
+		t.Fatalf("TaskState %v; want %v", ctx.upd.state, structs.TaskStateDead)
+	}
+	if len(signed) < 2 {
+		t.Fatalf("identity should have been renewed; signed %d", len(signed))
+	}
+
+	// The file holds a renewed identity and is only readable by the task
+	path := filepath.Join(ctx.tr.taskDir.SecretsDir, workloadTokenFile)
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat identity file: %v", err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0600 {
+		t.Fatalf("identity file mode %v; want %v", perm, os.FileMode(0600))
+	}
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read identity file: %v", err)
+	}
+	if string(raw) == "token-1" {
+		t.Fatalf("identity file was not updated on renewal")
+	}
+
+	// The identity is not in the environment unless opted in
+	if _, ok := ctx.tr.envBuilder.Build().Map()[env.WorkloadToken]; ok {
+		t.Fatalf("identity should not be in the task environment")
+	}
+}
+
+func TestTaskRunner_WorkloadIdentity_NotExposed(t *testing.T) {
+	t.Parallel()
+	ctx := testTaskRunner(t, false)
+	ctx.tr.MarkReceived()
+	defer ctx.Cleanup()
+
+	ctx.tr.identitySigner = func(a *structs.Allocation, name string) (string, time.Time, error) {
+		return "", time.Time{}, fmt.Errorf("identity should not be retrieved")
+	}
+	go ctx.tr.Run()
+
+	select {
+	case <-ctx.tr.WaitCh():
+	case <-time.After(time.Duration(testutil.TestMultiplier()*15) * time.Second):
+		t.Fatalf("timeout")
+	}
+
+	if ctx.upd.state != structs.TaskStateDead || ctx.upd.failed {
+		t.Fatalf("TaskState %v, failed %v; want %v", ctx.upd.state, ctx.upd.failed, structs.TaskStateDead)
+	}
+	if _, err := os.Stat(filepath.Join(ctx.tr.taskDir.SecretsDir, workloadTokenFile)); !os.IsNotExist(err) {
+		t.Fatalf("identity file should not exist: %v", err)
+	}
+}
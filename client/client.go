@@ -24,6 +24,8 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner"
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
+	"github.com/hashicorp/nomad/client/csimanager"
+	"github.com/hashicorp/nomad/client/devicemanager"
 	"github.com/hashicorp/nomad/client/servers"
 	"github.com/hashicorp/nomad/client/state"
 	"github.com/hashicorp/nomad/client/stats"
@@ -37,6 +39,8 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 	nconfig "github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/plugins/device"
+	"github.com/hashicorp/nomad/plugins/shared/loader"
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/shirou/gopsutil/host"
 )
@@ -105,12 +109,18 @@ type Client struct {
 	start  time.Time
 
 	// stateDB is used to efficiently store client state.
-	stateDB *bolt.DB
+	stateDB state.StateDB
 
 	// configCopy is a copy that should be passed to alloc-runners.
 	configCopy *config.Config
 	configLock sync.RWMutex
 
+	// staticMeta is the node meta set in the client's configuration and
+	// dynamicMeta is the node meta set through the API. Both are protected by
+	// the configLock.
+	staticMeta  map[string]string
+	dynamicMeta map[string]*string
+
 	logger *log.Logger
 
 	connPool *pool.ConnPool
@@ -136,6 +146,10 @@ type Client struct {
 	// update it.
 	triggerNodeUpdate chan struct{}
 
+	// triggerNodeMetaUpdate triggers the client to send the node's meta to
+	// the servers after it is updated through the API.
+	triggerNodeMetaUpdate chan struct{}
+
 	// triggerEmitNodeEvent sends an event and triggers the client to update the
 	// server for the node event
 	triggerEmitNodeEvent chan *structs.NodeEvent
@@ -175,6 +189,23 @@ type Client struct {
 	// in the node automatically
 	garbageCollector *AllocGarbageCollector
 
+	// pluginLoader is the catalog of the plugins found in the plugin
+	// directory
+	pluginLoader loader.PluginCatalog
+
+	// driverPlugins are the driver plugins launched by the client, keyed by
+	// the name of the driver
+	driverPlugins     map[string]*driverPlugin
+	driverPluginsLock sync.Mutex
+
+	// csimanager fingerprints the CSI plugins run by allocations and mounts
+	// the CSI volumes they claim
+	csimanager csimanager.Manager
+
+	// devicemanager runs the device plugins of the plugin catalog and
+	// collects the statistics of their devices
+	devicemanager devicemanager.Manager
+
 	// clientACLResolver holds the ACL resolution state
 	clientACLResolver
 
@@ -212,20 +243,22 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 
 	// Create the client
 	c := &Client{
-		config:               cfg,
-		consulCatalog:        consulCatalog,
-		consulService:        consulService,
-		start:                time.Now(),
-		connPool:             pool.NewPool(cfg.LogOutput, clientRPCCache, clientMaxStreams, tlsWrap),
-		tlsWrap:              tlsWrap,
-		streamingRpcs:        structs.NewStreamingRpcRegistry(),
-		logger:               logger,
-		allocs:               make(map[string]*allocrunner.AllocRunner),
-		allocUpdates:         make(chan *structs.Allocation, 64),
-		shutdownCh:           make(chan struct{}),
-		triggerDiscoveryCh:   make(chan struct{}),
-		triggerNodeUpdate:    make(chan struct{}, 8),
-		triggerEmitNodeEvent: make(chan *structs.NodeEvent, 8),
+		config:                cfg,
+		consulCatalog:         consulCatalog,
+		consulService:         consulService,
+		start:                 time.Now(),
+		connPool:              pool.NewPool(cfg.LogOutput, clientRPCCache, clientMaxStreams, tlsWrap),
+		tlsWrap:               tlsWrap,
+		streamingRpcs:         structs.NewStreamingRpcRegistry(),
+		logger:                logger,
+		allocs:                make(map[string]*allocrunner.AllocRunner),
+		allocUpdates:          make(chan *structs.Allocation, 64),
+		shutdownCh:            make(chan struct{}),
+		triggerDiscoveryCh:    make(chan struct{}),
+		triggerNodeUpdate:     make(chan struct{}, 8),
+		triggerNodeMetaUpdate: make(chan struct{}, 1),
+		triggerEmitNodeEvent:  make(chan *structs.NodeEvent, 8),
+		driverPlugins:         make(map[string]*driverPlugin),
 	}
 
 	// Initialize the server manager
@@ -245,7 +278,7 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 	}
 
 	// Add the stats collector
-	statsCollector := stats.NewHostStatsCollector(logger, c.config.AllocDir)
+	statsCollector := stats.NewHostStatsCollector(logger, c.config.AllocDir, c.deviceStats)
 	c.hostStatsCollector = statsCollector
 
 	// Add the garbage collector
@@ -265,12 +298,42 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 		return nil, fmt.Errorf("node setup failed: %v", err)
 	}
 
+	// Apply the node meta set through the API before the node is registered
+	if err := c.restoreNodeMeta(); err != nil {
+		return nil, fmt.Errorf("failed to restore node meta: %v", err)
+	}
+
+	// Setup the CSI manager before the allocs using CSI volumes are restored
+	c.csimanager = csimanager.New(&csimanager.Config{
+		Logger:            c.logger,
+		PluginsDir:        filepath.Join(cfg.StateDir, "csi"),
+		UpdateNodeCSIInfo: c.updateNodeFromCSI,
+		ClaimVolume:       c.claimCSIVolume,
+	})
+
 	// Store the config copy before restoring state but after it has been
 	// initialized.
 	c.configLock.Lock()
 	c.configCopy = c.config.Copy()
 	c.configLock.Unlock()
 
+	// Load the driver plugins before fingerprinting the drivers
+	if err := c.loadDriverPlugins(); err != nil {
+		return nil, fmt.Errorf("failed to load driver plugins: %v", err)
+	}
+
+	// Launch the device plugins, whose devices are added to the node
+	// attributes as they are fingerprinted
+	c.devicemanager = devicemanager.New(&devicemanager.Config{
+		Logger:        c.logger,
+		Loader:        c.pluginLoader,
+		StatsInterval: c.config.StatsCollectionInterval,
+		UpdateNodeFromFingerprint: func(resp *cstructs.FingerprintResponse) {
+			c.updateNodeFromFingerprint(resp)
+		},
+	})
+	c.devicemanager.Run()
+
 	fingerprintManager := NewFingerprintManager(c.GetConfig, c.configCopy.Node,
 		c.shutdownCh, c.updateNodeFromFingerprint, c.updateNodeFromDriver,
 		c.logger)
@@ -312,7 +375,9 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 		logger.Printf("[ERR] client: Nomad is unable to start due to corrupt state. "+
 			"The safest way to proceed is to manually stop running task processes "+
 			"and remove Nomad's state (%q) and alloc (%q) directories before "+
-			"restarting. Lost allocations will be rescheduled.",
+			"restarting. Lost allocations will be rescheduled. Alternatively "+
+			"the client can be started with recover_state set to quarantine "+
+			"the state of the allocations that fail to be restored.",
 			c.config.StateDir, c.config.AllocDir)
 		logger.Printf("[ERR] client: Corrupt state is often caused by a bug. Please " +
 			"report as much information as possible to " +
@@ -364,11 +429,27 @@ func (c *Client) init() error {
 	}
 	c.logger.Printf("[INFO] client: using state directory %v", c.config.StateDir)
 
-	// Create or open the state database
-	db, err := bolt.Open(filepath.Join(c.config.StateDir, "state.db"), 0600, nil)
+	// Create or open the state database. In recovery mode an unreadable
+	// database is moved aside and replaced by a new one.
+	db, err := state.NewBoltStateDB(c.logger, c.config.StateDir)
+	if err != nil && c.config.RecoverState {
+		c.logger.Printf("[WARN] client: failed to open state database, quarantining it: %v", err)
+		path, qerr := state.QuarantineStateDB(c.config.StateDir)
+		if qerr != nil {
+			return fmt.Errorf("failed to quarantine state database: %v", qerr)
+		}
+		c.logger.Printf("[WARN] client: moved unreadable state database to %q", path)
+		db, err = state.NewBoltStateDB(c.logger, c.config.StateDir)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create state database: %v", err)
 	}
+
+	// Migrate the state to the latest schema before it is read
+	if err := db.Upgrade(); err != nil {
+		db.Close()
+		return fmt.Errorf("failed to upgrade state database: %v", err)
+	}
 	c.stateDB = db
 
 	// Ensure the alloc dir exists if we have one
@@ -534,6 +615,8 @@ func (c *Client) Shutdown() error {
 
 	c.shutdown = true
 	close(c.shutdownCh)
+	c.shutdownDriverPlugins()
+	c.devicemanager.Shutdown()
 	c.connPool.Shutdown()
 	return c.saveState()
 }
@@ -596,6 +679,15 @@ func (c *Client) LatestHostStats() *stats.HostStats {
 	return c.hostStatsCollector.Stats()
 }
 
+// deviceStats returns the latest statistics of the devices of the device
+// plugins. It is called by the host stats collector.
+func (c *Client) deviceStats() []*device.DeviceGroupStats {
+	if c.devicemanager == nil {
+		return nil
+	}
+	return c.devicemanager.AllStats()
+}
+
 // ValidateMigrateToken verifies that a token is for a specific client and
 // allocation, and has been created by a trusted party that has privileged
 // knowledge of the client's secret identifier
@@ -707,41 +799,17 @@ func (c *Client) restoreState() error {
 		return nil
 	}
 
-	// COMPAT: Remove in 0.7.0
-	// 0.6.0 transitioned from individual state files to a single bolt-db.
-	// The upgrade path is to:
-	// Check if old state exists
-	//   If so, restore from that and delete old state
-	// Restore using state database
-
-	// Allocs holds the IDs of the allocations being restored
 	var allocs []string
-
-	// Upgrading tracks whether this is a pre 0.6.0 upgrade path
-	var upgrading bool
-
-	// Scan the directory
-	allocDir := filepath.Join(c.config.StateDir, "alloc")
-	list, err := ioutil.ReadDir(allocDir)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to list alloc state: %v", err)
-	} else if err == nil && len(list) != 0 {
-		upgrading = true
-		for _, entry := range list {
-			allocs = append(allocs, entry.Name())
-		}
-	} else {
-		// Normal path
-		err := c.stateDB.View(func(tx *bolt.Tx) error {
-			allocs, err = state.GetAllAllocationIDs(tx)
-			if err != nil {
-				return fmt.Errorf("failed to list allocations: %v", err)
-			}
-			return nil
-		})
+	err := c.stateDB.View(func(tx *bolt.Tx) error {
+		var err error
+		allocs, err = state.GetAllAllocationIDs(tx)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to list allocations: %v", err)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Load each alloc back
@@ -753,30 +821,37 @@ func (c *Client) restoreState() error {
 		watcher := allocrunner.NoopPrevAlloc{}
 
 		c.configLock.RLock()
-		ar := allocrunner.NewAllocRunner(c.logger, c.configCopy.Copy(), c.stateDB, c.updateAllocStatus, alloc, c.vaultClient, c.consulService, watcher)
+		ar := allocrunner.NewAllocRunner(c.logger, c.configCopy.Copy(), c.stateDB, c.updateAllocStatus, alloc, c.vaultClient, c.consulService, watcher, c.fetchVariables, c.signIdentity, c.csimanager)
 		c.configLock.RUnlock()
 
 		c.allocLock.Lock()
 		c.allocs[id] = ar
 		c.allocLock.Unlock()
 
-		if err := ar.RestoreState(); err != nil {
-			c.logger.Printf("[ERR] client: failed to restore state for alloc %q: %v", id, err)
-			mErr.Errors = append(mErr.Errors, err)
-		} else {
+		err := ar.RestoreState()
+		if err == nil {
 			go ar.Run()
+			continue
+		}
 
-			if upgrading {
-				if err := ar.SaveState(); err != nil {
-					c.logger.Printf("[WARN] client: initial save state for alloc %q failed: %v", id, err)
-				}
-			}
+		if !c.config.RecoverState {
+			c.logger.Printf("[ERR] client: failed to restore state for alloc %q: %v", id, err)
+			mErr.Errors = append(mErr.Errors, err)
+			continue
 		}
-	}
 
-	// Delete all the entries
-	if upgrading {
-		if err := os.RemoveAll(allocDir); err != nil {
+		// In recovery mode the state of the alloc is quarantined so the
+		// client can start. The alloc is run again if the servers still
+		// assign it to the node.
+		c.logger.Printf("[WARN] client: failed to restore state for alloc %q, quarantining it: %v", id, err)
+		c.allocLock.Lock()
+		delete(c.allocs, id)
+		c.allocLock.Unlock()
+
+		if err := c.stateDB.Update(func(tx *bolt.Tx) error {
+			return state.QuarantineAllocationBucket(tx, id)
+		}); err != nil {
+			c.logger.Printf("[ERR] client: failed to quarantine state for alloc %q: %v", id, err)
 			mErr.Errors = append(mErr.Errors, err)
 		}
 	}
@@ -944,6 +1019,15 @@ func (c *Client) setupNode() error {
 	if node.Name == "" {
 		node.Name = node.ID
 	}
+	if node.HostVolumes == nil {
+		node.HostVolumes = structs.CopyMapStringClientHostVolumeConfig(c.config.HostVolumes)
+	}
+	if node.CSIControllerPlugins == nil {
+		node.CSIControllerPlugins = make(map[string]*structs.CSIInfo)
+	}
+	if node.CSINodePlugins == nil {
+		node.CSINodePlugins = make(map[string]*structs.CSIInfo)
+	}
 	node.Status = structs.NodeStatusInit
 	return nil
 }
@@ -1140,6 +1224,40 @@ func (c *Client) updateNodeFromDriver(name string, fingerprint, health *structs.
 	return c.configCopy.Node
 }
 
+// updateNodeFromCSI receives the fingerprint of a CSI plugin run by an
+// allocation and updates the node's controller or node plugins. A nil info
+// removes the plugin.
+func (c *Client) updateNodeFromCSI(pluginType structs.CSIPluginType, name string, info *structs.CSIInfo) {
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	plugins := c.config.Node.CSINodePlugins
+	if pluginType == structs.CSIPluginTypeController {
+		plugins = c.config.Node.CSIControllerPlugins
+	}
+
+	old, ok := plugins[name]
+	if info == nil {
+		if !ok {
+			return
+		}
+		delete(plugins, name)
+	} else {
+		if ok && old.Healthy != info.Healthy && info.HealthDescription != "" {
+			event := &structs.NodeEvent{
+				Subsystem: "CSI",
+				Message:   info.HealthDescription,
+				Timestamp: time.Now(),
+				Details:   map[string]string{"plugin": name, "type": string(pluginType)},
+			}
+			c.triggerNodeEvent(event)
+		}
+		plugins[name] = info
+	}
+
+	c.updateNodeLocked()
+}
+
 // resourcesAreEqual is a temporary function to compare whether resources are
 // equal. We can use this until we change fingerprinters to set pointers on a
 // return type.
@@ -1188,6 +1306,9 @@ func (c *Client) registerAndHeartbeat() {
 	// Start watching changes for node changes
 	go c.watchNodeUpdates()
 
+	// Start watching for node meta updates
+	go c.watchNodeMetaUpdates()
+
 	// Start watching for emitting node events
 	go c.watchNodeEvents()
 
@@ -1884,6 +2005,9 @@ func (c *Client) removeAlloc(alloc *structs.Allocation) {
 	delete(c.allocs, alloc.ID)
 	c.allocLock.Unlock()
 
+	// Release the devices reserved for the alloc
+	c.devicemanager.Free(alloc.ID)
+
 	// Ensure the GC has a reference and then collect. Collecting through the GC
 	// applies rate limiting
 	c.garbageCollector.MarkForCollection(ar)
@@ -1929,7 +2053,7 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 	// Copy the config since the node can be swapped out as it is being updated.
 	// The long term fix is to pass in the config and node separately and then
 	// we don't have to do a copy.
-	ar := allocrunner.NewAllocRunner(c.logger, c.configCopy.Copy(), c.stateDB, c.updateAllocStatus, alloc, c.vaultClient, c.consulService, prevAlloc)
+	ar := allocrunner.NewAllocRunner(c.logger, c.configCopy.Copy(), c.stateDB, c.updateAllocStatus, alloc, c.vaultClient, c.consulService, prevAlloc, c.fetchVariables, c.signIdentity, c.csimanager)
 	c.configLock.RUnlock()
 
 	// Store the alloc runner.
@@ -1963,6 +2087,84 @@ func (c *Client) setupVaultClient() error {
 	return nil
 }
 
+// fetchVariables retrieves the variables the allocation's templates may read,
+// blocking until they change after the given index.
+func (c *Client) fetchVariables(alloc *structs.Allocation, minIndex uint64) ([]*structs.VariableDecrypted, uint64, error) {
+	if alloc == nil {
+		return nil, 0, fmt.Errorf("nil allocation")
+	}
+
+	req := &structs.AllocVariablesRequest{
+		NodeID:   c.NodeID(),
+		SecretID: c.secretNodeID(),
+		AllocID:  alloc.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:        c.Region(),
+			AllowStale:    false,
+			MinQueryIndex: minIndex,
+		},
+	}
+
+	var resp structs.AllocVariablesResponse
+	if err := c.RPC("Variables.ReadAllocVariables", &req, &resp); err != nil {
+		c.logger.Printf("[ERR] client: ReadAllocVariables RPC failed for alloc %q: %v", alloc.ID, err)
+		return nil, 0, fmt.Errorf("ReadAllocVariables RPC failed: %v", err)
+	}
+	return resp.Variables, resp.Index, nil
+}
+
+// signIdentity retrieves the signed workload identity of the given task of the
+// allocation from the servers, along with the time at which it expires.
+func (c *Client) signIdentity(alloc *structs.Allocation, task string) (string, time.Time, error) {
+	if alloc == nil {
+		return "", time.Time{}, fmt.Errorf("nil allocation")
+	}
+
+	req := &structs.AllocIdentityRequest{
+		NodeID:   c.NodeID(),
+		SecretID: c.secretNodeID(),
+		AllocID:  alloc.ID,
+		TaskName: task,
+		QueryOptions: structs.QueryOptions{
+			Region:     c.Region(),
+			AllowStale: false,
+		},
+	}
+
+	var resp structs.AllocIdentityResponse
+	if err := c.RPC("Alloc.SignIdentity", &req, &resp); err != nil {
+		c.logger.Printf("[ERR] client: SignIdentity RPC failed for alloc %q: %v", alloc.ID, err)
+		return "", time.Time{}, fmt.Errorf("SignIdentity RPC failed: %v", err)
+	}
+	return resp.Token, resp.Expiry, nil
+}
+
+// claimCSIVolume claims or releases a CSI volume for the allocation
+func (c *Client) claimCSIVolume(alloc *structs.Allocation, volumeID string, mode structs.CSIVolumeClaimMode) (*structs.CSIVolumeClaimResponse, error) {
+	if alloc == nil {
+		return nil, fmt.Errorf("nil allocation")
+	}
+
+	req := &structs.CSIVolumeClaimRequest{
+		VolumeID:     volumeID,
+		AllocationID: alloc.ID,
+		NodeID:       c.NodeID(),
+		SecretID:     c.secretNodeID(),
+		Claim:        mode,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.Region(),
+			Namespace: alloc.Namespace,
+		},
+	}
+
+	var resp structs.CSIVolumeClaimResponse
+	if err := c.RPC("CSIVolume.Claim", &req, &resp); err != nil {
+		c.logger.Printf("[ERR] client: CSIVolume.Claim RPC failed for alloc %q: %v", alloc.ID, err)
+		return nil, fmt.Errorf("CSIVolume.Claim RPC failed: %v", err)
+	}
+	return &resp, nil
+}
+
 // deriveToken takes in an allocation and a set of tasks and derives vault
 // tokens for each of the tasks, unwraps all of them using the supplied vault
 // client and returns a map of unwrapped tokens, indexed by the task name.
@@ -2399,6 +2601,63 @@ func (c *Client) setGaugeForUptime(hStats *stats.HostStats) {
 	}
 }
 
+// setGaugeForDeviceStats proxies metrics for the statistics of the devices of
+// the device plugins. Devices reserved for an allocation are labeled with the
+// allocation and its job so their usage can be attributed to jobs.
+func (c *Client) setGaugeForDeviceStats(hStats *stats.HostStats) {
+	if c.config.DisableTaggedMetrics {
+		return
+	}
+
+	runners := c.getAllocRunners()
+	for _, group := range hStats.DeviceStats {
+		for id, ds := range group.InstanceStats {
+			labels := append(c.baseLabels,
+				metrics.Label{Name: "device_vendor", Value: group.Vendor},
+				metrics.Label{Name: "device_type", Value: group.Type},
+				metrics.Label{Name: "device_name", Value: group.Name},
+				metrics.Label{Name: "device_id", Value: id},
+			)
+			if allocID, ok := c.devicemanager.DeviceAlloc(group.ID(), id); ok {
+				labels = append(labels, metrics.Label{Name: "alloc_id", Value: allocID})
+				if ar, ok := runners[allocID]; ok {
+					labels = append(labels, metrics.Label{Name: "job", Value: ar.Alloc().JobID})
+				}
+			}
+
+			if v, ok := ds.Summary.Float(); ok {
+				metrics.SetGaugeWithLabels([]string{"client", "host", "device", "summary"}, float32(v), labels)
+			}
+			for name, v := range flattenDeviceStats(ds.Stats) {
+				statLabels := append(labels, metrics.Label{Name: "stat", Value: name})
+				metrics.SetGaugeWithLabels([]string{"client", "host", "device", "stat"}, float32(v), statLabels)
+			}
+		}
+	}
+}
+
+// flattenDeviceStats returns the numeric statistics of a device keyed by
+// their dotted path in the nested statistics.
+func flattenDeviceStats(o *device.StatObject) map[string]float64 {
+	flat := make(map[string]float64)
+	var flatten func(prefix string, o *device.StatObject)
+	flatten = func(prefix string, o *device.StatObject) {
+		if o == nil {
+			return
+		}
+		for name, v := range o.Attributes {
+			if f, ok := v.Float(); ok {
+				flat[prefix+name] = f
+			}
+		}
+		for name, nested := range o.Nested {
+			flatten(prefix+name+".", nested)
+		}
+	}
+	flatten("", o)
+	return flat
+}
+
 // emitHostStats pushes host resource usage stats to remote metrics collection sinks
 func (c *Client) emitHostStats() {
 	nodeID := c.NodeID()
@@ -2408,6 +2667,7 @@ func (c *Client) emitHostStats() {
 	c.setGaugeForUptime(hStats)
 	c.setGaugeForCPUStats(nodeID, hStats)
 	c.setGaugeForDiskStats(nodeID, hStats)
+	c.setGaugeForDeviceStats(hStats)
 }
 
 // emitClientMetrics emits lower volume client metrics
@@ -73,6 +73,13 @@ type Config struct {
 	// AllocDir is where we store data for allocations
 	AllocDir string
 
+	// PluginDir is the directory from which driver plugins are loaded
+	PluginDir string
+
+	// PluginConfigs are the configs of the plugins given by the plugin
+	// blocks of the agent configuration
+	PluginConfigs []*config.PluginConfig
+
 	// LogOutput is the destination for logs
 	LogOutput io.Writer
 
@@ -125,6 +132,9 @@ type Config struct {
 	// task's chroot.
 	ChrootEnv map[string]string
 
+	// HostVolumes is a map of the configured host volumes by name.
+	HostVolumes map[string]*structs.ClientHostVolumeConfig
+
 	// Options provides arbitrary key-value configuration for nomad internals,
 	// like fingerprinters and drivers. The format is:
 	//
@@ -182,6 +192,11 @@ type Config struct {
 	// random UUID.
 	NoHostUUID bool
 
+	// RecoverState quarantines the state of the allocations that fail to be
+	// restored, and the state database if it is unreadable, instead of
+	// failing to start the client.
+	RecoverState bool
+
 	// ACLEnabled controls if ACL enforcement and management is enabled.
 	ACLEnabled bool
 
@@ -216,6 +231,8 @@ func (c *Config) Copy() *Config {
 	nc.GloballyReservedPorts = helper.CopySliceInt(c.GloballyReservedPorts)
 	nc.ConsulConfig = c.ConsulConfig.Copy()
 	nc.VaultConfig = c.VaultConfig.Copy()
+	nc.HostVolumes = structs.CopyMapStringClientHostVolumeConfig(c.HostVolumes)
+	nc.PluginConfigs = config.PluginConfigSetMerge(nil, c.PluginConfigs)
 	return nc
 }
 
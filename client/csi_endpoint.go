@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/client/structs"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+)
+
+// CSI endpoint is used by the servers to attach and detach volumes using the
+// CSI plugins running on the client. The servers check the requests before
+// forwarding them.
+type CSI struct {
+	c *Client
+}
+
+// csiControllerTimeout bounds the calls made to the controller plugins
+const csiControllerTimeout = 2 * time.Minute
+
+// ControllerAttachVolume is used to attach a volume to a node
+func (c *CSI) ControllerAttachVolume(args *structs.ClientCSIControllerAttachVolumeRequest, reply *structs.ClientCSIControllerAttachVolumeResponse) error {
+	defer metrics.MeasureSince([]string{"client", "csi_controller", "attach_volume"}, time.Now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), csiControllerTimeout)
+	defer cancel()
+
+	publishContext, err := c.c.csimanager.ControllerAttachVolume(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	reply.PublishContext = publishContext
+	return nil
+}
+
+// ControllerDetachVolume is used to detach a volume from a node
+func (c *CSI) ControllerDetachVolume(args *structs.ClientCSIControllerDetachVolumeRequest, reply *structs.ClientCSIControllerDetachVolumeResponse) error {
+	defer metrics.MeasureSince([]string{"client", "csi_controller", "detach_volume"}, time.Now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), csiControllerTimeout)
+	defer cancel()
+
+	return c.c.csimanager.ControllerDetachVolume(ctx, args)
+}
+
+// NodeDetachVolume is used to unpublish a volume claimed by an allocation that
+// has stopped without releasing its claim, and to then release the claim.
+func (c *CSI) NodeDetachVolume(args *structs.ClientCSINodeDetachVolumeRequest, reply *structs.ClientCSINodeDetachVolumeResponse) error {
+	defer metrics.MeasureSince([]string{"client", "csi_node", "detach_volume"}, time.Now())
+
+	// Volumes are only detached from allocations that no longer run
+	if ar, ok := c.c.getAllocRunners()[args.AllocID]; ok && !ar.Alloc().ClientTerminalStatus() {
+		return fmt.Errorf("allocation %q is still running", args.AllocID)
+	}
+
+	alloc := &nstructs.Allocation{
+		ID:        args.AllocID,
+		Namespace: args.VolumeNamespace,
+		NodeID:    args.NodeID,
+	}
+	req := &nstructs.VolumeRequest{
+		Type:   nstructs.VolumeTypeCSI,
+		Source: args.VolumeID,
+	}
+	return c.c.csimanager.UnmountVolume(alloc, req)
+}
@@ -0,0 +1,265 @@
+package csimanager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/csi"
+)
+
+const (
+	// fingerprintInterval is the interval at which healthy plugins are
+	// fingerprinted
+	fingerprintInterval = 30 * time.Second
+
+	// fingerprintTimeout bounds the calls made to fingerprint a plugin
+	fingerprintTimeout = 10 * time.Second
+)
+
+// pluginInstance is a plugin run by an allocation on the client
+type pluginInstance struct {
+	manager *manager
+	allocID string
+	cfg     *structs.TaskCSIPluginConfig
+
+	// dir is the host directory mounted at the plugin's mount dir
+	dir string
+
+	client csi.CSIPlugin
+
+	// infos are the last fingerprints sent to the node by plugin type
+	infos map[structs.CSIPluginType]*structs.CSIInfo
+
+	// stage is set if the node plugin requires volumes to be staged
+	stage     bool
+	stageLock sync.RWMutex
+}
+
+func newPluginInstance(m *manager, allocID string, cfg *structs.TaskCSIPluginConfig) *pluginInstance {
+	return &pluginInstance{
+		manager: m,
+		allocID: allocID,
+		cfg:     cfg,
+		dir:     m.PluginDir(cfg),
+		infos:   make(map[structs.CSIPluginType]*structs.CSIInfo),
+	}
+}
+
+// hostPath returns the host path of a path relative to the plugin's dir
+func (p *pluginInstance) hostPath(path string) string {
+	return filepath.Join(p.dir, path)
+}
+
+// pluginPath returns the path of a path relative to the plugin's dir as seen
+// by the plugin
+func (p *pluginInstance) pluginPath(path string) string {
+	return filepath.Join(p.cfg.MountDir, path)
+}
+
+func (p *pluginInstance) requiresStage() bool {
+	p.stageLock.RLock()
+	defer p.stageLock.RUnlock()
+	return p.stage
+}
+
+// run fingerprints the plugin until the context is done, at which point the
+// plugin is removed from the node.
+func (p *pluginInstance) run(ctx context.Context) {
+	defer p.shutdown()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		// Wait for the plugin to come up before fingerprinting it at the
+		// normal interval
+		if p.fingerprint(ctx) {
+			timer.Reset(fingerprintInterval)
+		} else {
+			timer.Reset(pluginWaitInterval)
+		}
+	}
+}
+
+// fingerprint fingerprints the plugin, updates the node and returns whether
+// the plugin is healthy.
+func (p *pluginInstance) fingerprint(ctx context.Context) bool {
+	infos, err := p.buildInfos(ctx)
+	if err != nil {
+		p.manager.logger.Printf("[DEBUG] client.csi: plugin %q of alloc %q is unhealthy: %v", p.cfg.ID, p.allocID, err)
+		infos = make(map[structs.CSIPluginType]*structs.CSIInfo)
+		for _, pluginType := range p.types(nil) {
+			infos[pluginType] = &structs.CSIInfo{
+				PluginID:          p.cfg.ID,
+				AllocID:           p.allocID,
+				HealthDescription: err.Error(),
+			}
+		}
+	}
+
+	for pluginType, info := range infos {
+		if info.Healthy {
+			p.manager.setInstance(pluginType, p.cfg.ID, p)
+		} else {
+			p.manager.removeInstance(pluginType, p.cfg.ID, p)
+		}
+		p.updateInfo(pluginType, info)
+	}
+
+	// A monolith that stopped providing a controller is removed from the
+	// node's controllers
+	for pluginType := range p.infos {
+		if _, ok := infos[pluginType]; !ok {
+			p.manager.removeInstance(pluginType, p.cfg.ID, p)
+			p.updateInfo(pluginType, nil)
+		}
+	}
+
+	return err == nil
+}
+
+// updateInfo sends the plugin's info to the node if it changed
+func (p *pluginInstance) updateInfo(pluginType structs.CSIPluginType, info *structs.CSIInfo) {
+	old, ok := p.infos[pluginType]
+	if info == nil {
+		if !ok {
+			return
+		}
+		delete(p.infos, pluginType)
+	} else {
+		if old != nil {
+			info.UpdateTime = old.UpdateTime
+			if reflect.DeepEqual(old, info) {
+				return
+			}
+		}
+		info.UpdateTime = time.Now()
+		p.infos[pluginType] = info
+	}
+
+	p.manager.config.UpdateNodeCSIInfo(pluginType, p.cfg.ID, info.Copy())
+}
+
+// types returns the plugin types advertised for the plugin
+func (p *pluginInstance) types(caps *csi.PluginCapabilitySet) []structs.CSIPluginType {
+	switch p.cfg.Type {
+	case structs.CSIPluginTypeController:
+		return []structs.CSIPluginType{structs.CSIPluginTypeController}
+	case structs.CSIPluginTypeNode:
+		return []structs.CSIPluginType{structs.CSIPluginTypeNode}
+	}
+
+	if caps != nil && !caps.HasControllerService {
+		return []structs.CSIPluginType{structs.CSIPluginTypeNode}
+	}
+	return []structs.CSIPluginType{structs.CSIPluginTypeController, structs.CSIPluginTypeNode}
+}
+
+// buildInfos connects to the plugin if needed and returns its fingerprint by
+// plugin type.
+func (p *pluginInstance) buildInfos(ctx context.Context) (map[structs.CSIPluginType]*structs.CSIInfo, error) {
+	if p.client == nil {
+		socket := p.hostPath(structs.CSISocketName)
+		if _, err := os.Stat(socket); err != nil {
+			return nil, fmt.Errorf("plugin socket not found: %v", err)
+		}
+
+		client, err := p.manager.config.newClient(socket)
+		if err != nil {
+			return nil, err
+		}
+		p.client = client
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fingerprintTimeout)
+	defer cancel()
+
+	provider, version, err := p.client.PluginGetInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plugin info: %v", err)
+	}
+	ready, err := p.client.PluginProbe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe plugin: %v", err)
+	}
+	if !ready {
+		return nil, fmt.Errorf("plugin is not ready")
+	}
+	caps, err := p.client.PluginGetCapabilities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plugin capabilities: %v", err)
+	}
+
+	infos := make(map[structs.CSIPluginType]*structs.CSIInfo)
+	for _, pluginType := range p.types(caps) {
+		info := &structs.CSIInfo{
+			PluginID:          p.cfg.ID,
+			AllocID:           p.allocID,
+			Healthy:           true,
+			HealthDescription: "healthy",
+			Provider:          provider,
+			ProviderVersion:   version,
+		}
+
+		switch pluginType {
+		case structs.CSIPluginTypeController:
+			controllerCaps, err := p.client.ControllerGetCapabilities(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get controller capabilities: %v", err)
+			}
+			info.RequiresControllerPlugin = controllerCaps.HasPublishUnpublishVolume
+			info.ControllerInfo = &structs.CSIControllerInfo{
+				SupportsAttachDetach: controllerCaps.HasPublishUnpublishVolume,
+			}
+
+		case structs.CSIPluginTypeNode:
+			nodeInfo, err := p.client.NodeGetInfo(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get node info: %v", err)
+			}
+			nodeCaps, err := p.client.NodeGetCapabilities(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get node capabilities: %v", err)
+			}
+
+			p.stageLock.Lock()
+			p.stage = nodeCaps.HasStageUnstageVolume
+			p.stageLock.Unlock()
+
+			info.RequiresControllerPlugin = caps.HasControllerService
+			info.NodeInfo = &structs.CSINodeInfo{
+				ID:                      nodeInfo.NodeID,
+				MaxVolumes:              nodeInfo.MaxVolumes,
+				RequiresNodeStageVolume: nodeCaps.HasStageUnstageVolume,
+			}
+		}
+
+		infos[pluginType] = info
+	}
+
+	return infos, nil
+}
+
+// shutdown removes the plugin from the node and closes its client
+func (p *pluginInstance) shutdown() {
+	for pluginType := range p.infos {
+		p.manager.removeInstance(pluginType, p.cfg.ID, p)
+		p.updateInfo(pluginType, nil)
+	}
+
+	if p.client != nil {
+		p.client.Close()
+	}
+}
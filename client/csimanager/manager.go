@@ -0,0 +1,361 @@
+// csimanager manages the CSI plugins run by the allocations of a client. It
+// fingerprints the plugins to advertise them in the node, and stages and
+// publishes the volumes claimed by allocations through the node plugins.
+package csimanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/csi"
+)
+
+const (
+	// claimTimeout is how long a volume claim is retried before the mount is
+	// failed. Claims are retried as the previous allocation using a volume
+	// may still be releasing it.
+	claimTimeout = 5 * time.Minute
+
+	// claimBackoffBase and claimBackoffLimit bound the wait between claims
+	claimBackoffBase  = 1 * time.Second
+	claimBackoffLimit = 30 * time.Second
+
+	// pluginWaitInterval is the interval at which a volume waits for its
+	// node plugin to be running on the client.
+	pluginWaitInterval = 1 * time.Second
+
+	// stagingDir and perAllocDir are the directories, relative to the
+	// plugin's mount dir, in which volumes are staged and published.
+	stagingDir  = "staging"
+	perAllocDir = "per-alloc"
+)
+
+// UpdateNodeCSIInfoFunc is called when the fingerprint of a plugin changes.
+// The plugin type is either controller or node and a nil info removes the
+// plugin from the node.
+type UpdateNodeCSIInfoFunc func(pluginType structs.CSIPluginType, pluginID string, info *structs.CSIInfo)
+
+// ClaimVolumeFunc claims or releases a volume for an allocation on the
+// servers.
+type ClaimVolumeFunc func(alloc *structs.Allocation, volumeID string, mode structs.CSIVolumeClaimMode) (*structs.CSIVolumeClaimResponse, error)
+
+// Config configures the manager
+type Config struct {
+	Logger *log.Logger
+
+	// PluginsDir is the directory in which the plugins' mount dirs are
+	// created.
+	PluginsDir string
+
+	UpdateNodeCSIInfo UpdateNodeCSIInfoFunc
+	ClaimVolume       ClaimVolumeFunc
+
+	// newClient connects to a plugin's socket. It is overridden in tests.
+	newClient func(addr string) (csi.CSIPlugin, error)
+}
+
+// Manager manages the CSI plugins and volumes of a client
+type Manager interface {
+	// PluginDir returns the host directory mounted in the plugin's task at
+	// its mount dir.
+	PluginDir(cfg *structs.TaskCSIPluginConfig) string
+
+	// WatchPlugin fingerprints the plugin run by the allocation until the
+	// context is cancelled.
+	WatchPlugin(ctx context.Context, allocID string, cfg *structs.TaskCSIPluginConfig)
+
+	// MountVolume claims, stages and publishes the volume requested by the
+	// allocation, and returns the host path the tasks should mount.
+	MountVolume(ctx context.Context, alloc *structs.Allocation, req *structs.VolumeRequest) (string, error)
+
+	// UnmountVolume unpublishes and unstages the volume, and releases the
+	// allocation's claim on it.
+	UnmountVolume(alloc *structs.Allocation, req *structs.VolumeRequest) error
+
+	// ControllerAttachVolume and ControllerDetachVolume use the controller
+	// plugin running on the client to attach volumes to nodes.
+	ControllerAttachVolume(ctx context.Context, req *cstructs.ClientCSIControllerAttachVolumeRequest) (map[string]string, error)
+	ControllerDetachVolume(ctx context.Context, req *cstructs.ClientCSIControllerDetachVolumeRequest) error
+}
+
+// volumeMount tracks a volume published for an allocation
+type volumeMount struct {
+	instance   *pluginInstance
+	externalID string
+	staged     bool
+}
+
+type manager struct {
+	config *Config
+	logger *log.Logger
+
+	// instances are the healthy plugins by type and ID
+	instances map[structs.CSIPluginType]map[string]*pluginInstance
+
+	// mounts are the published volumes by allocation and volume ID
+	mounts map[string]map[string]*volumeMount
+
+	lock sync.Mutex
+}
+
+// New returns a manager for the CSI plugins of a client
+func New(config *Config) Manager {
+	if config.newClient == nil {
+		config.newClient = csi.NewClient
+	}
+
+	return &manager{
+		config: config,
+		logger: config.Logger,
+		instances: map[structs.CSIPluginType]map[string]*pluginInstance{
+			structs.CSIPluginTypeController: {},
+			structs.CSIPluginTypeNode:       {},
+		},
+		mounts: make(map[string]map[string]*volumeMount),
+	}
+}
+
+func (m *manager) PluginDir(cfg *structs.TaskCSIPluginConfig) string {
+	return filepath.Join(m.config.PluginsDir, string(cfg.Type), cfg.ID)
+}
+
+func (m *manager) WatchPlugin(ctx context.Context, allocID string, cfg *structs.TaskCSIPluginConfig) {
+	p := newPluginInstance(m, allocID, cfg)
+	p.run(ctx)
+}
+
+// setInstance records the healthy instance of a plugin
+func (m *manager) setInstance(pluginType structs.CSIPluginType, pluginID string, p *pluginInstance) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.instances[pluginType][pluginID] = p
+}
+
+// removeInstance removes the instance of a plugin if it is still the one
+// recorded, as another allocation may have replaced it.
+func (m *manager) removeInstance(pluginType structs.CSIPluginType, pluginID string, p *pluginInstance) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.instances[pluginType][pluginID] == p {
+		delete(m.instances[pluginType], pluginID)
+	}
+}
+
+// instance returns the healthy instance of a plugin
+func (m *manager) instance(pluginType structs.CSIPluginType, pluginID string) *pluginInstance {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.instances[pluginType][pluginID]
+}
+
+// waitInstance waits for the node plugin to be healthy on the client
+func (m *manager) waitInstance(ctx context.Context, pluginID string) (*pluginInstance, error) {
+	for {
+		if p := m.instance(structs.CSIPluginTypeNode, pluginID); p != nil {
+			return p, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("node plugin %q is not running: %v", pluginID, ctx.Err())
+		case <-time.After(pluginWaitInterval):
+		}
+	}
+}
+
+func (m *manager) MountVolume(ctx context.Context, alloc *structs.Allocation, req *structs.VolumeRequest) (string, error) {
+	mode := structs.CSIVolumeClaimWrite
+	if req.ReadOnly {
+		mode = structs.CSIVolumeClaimRead
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, claimTimeout)
+	defer cancel()
+
+	resp, err := m.claimVolume(ctx, alloc, req.Source, mode)
+	if err != nil {
+		return "", err
+	}
+	vol := resp.Volume
+
+	path, err := m.publishVolume(ctx, alloc, vol, resp.PublishContext, req.ReadOnly)
+	if err != nil {
+		// Release the claim so that it doesn't block other allocations
+		if _, rerr := m.config.ClaimVolume(alloc, req.Source, structs.CSIVolumeClaimRelease); rerr != nil {
+			m.logger.Printf("[ERR] client.csi: failed to release claim of alloc %q on volume %q: %v", alloc.ID, req.Source, rerr)
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// claimVolume claims the volume, retrying until the claim succeeds or the
+// context is done.
+func (m *manager) claimVolume(ctx context.Context, alloc *structs.Allocation, volumeID string, mode structs.CSIVolumeClaimMode) (*structs.CSIVolumeClaimResponse, error) {
+	backoff := claimBackoffBase
+	for {
+		resp, err := m.config.ClaimVolume(alloc, volumeID, mode)
+		if err == nil {
+			if resp.Volume == nil {
+				return nil, fmt.Errorf("claim of volume %q returned no volume", volumeID)
+			}
+			return resp, nil
+		}
+
+		m.logger.Printf("[WARN] client.csi: alloc %q failed to claim volume %q, retrying in %v: %v", alloc.ID, volumeID, backoff, err)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to claim volume %q: %v", volumeID, err)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > claimBackoffLimit {
+			backoff = claimBackoffLimit
+		}
+	}
+}
+
+// publishVolume stages the volume if the plugin requires it and publishes it
+// for the allocation.
+func (m *manager) publishVolume(ctx context.Context, alloc *structs.Allocation, vol *structs.CSIVolume,
+	publishContext map[string]string, readOnly bool) (string, error) {
+
+	p, err := m.waitInstance(ctx, vol.PluginID)
+	if err != nil {
+		return "", err
+	}
+
+	capability, err := csi.VolumeCapabilityFromStructs(vol.AttachmentMode, vol.AccessMode, vol.MountOptions)
+	if err != nil {
+		return "", err
+	}
+
+	// The plugin sees its directory at its mount dir, so paths given to the
+	// plugin differ from the host paths
+	staging := filepath.Join(stagingDir, vol.ID)
+	target := filepath.Join(perAllocDir, alloc.ID, vol.ID)
+	if err := os.MkdirAll(filepath.Dir(p.hostPath(target)), 0700); err != nil {
+		return "", fmt.Errorf("failed to create target dir for volume %q: %v", vol.ID, err)
+	}
+
+	staged := false
+	if p.requiresStage() {
+		if err := os.MkdirAll(p.hostPath(staging), 0700); err != nil {
+			return "", fmt.Errorf("failed to create staging dir for volume %q: %v", vol.ID, err)
+		}
+		if err := p.client.NodeStageVolume(ctx, vol.ExternalID, publishContext, p.pluginPath(staging), capability); err != nil {
+			return "", fmt.Errorf("failed to stage volume %q: %v", vol.ID, err)
+		}
+		staged = true
+	}
+
+	publishReq := &csi.NodePublishVolumeRequest{
+		VolumeID:         vol.ExternalID,
+		PublishContext:   publishContext,
+		TargetPath:       p.pluginPath(target),
+		VolumeCapability: capability,
+		Readonly:         readOnly,
+		VolumeContext:    vol.Context,
+	}
+	if staged {
+		publishReq.StagingTargetPath = p.pluginPath(staging)
+	}
+	if err := p.client.NodePublishVolume(ctx, publishReq); err != nil {
+		return "", fmt.Errorf("failed to publish volume %q: %v", vol.ID, err)
+	}
+
+	m.lock.Lock()
+	if m.mounts[alloc.ID] == nil {
+		m.mounts[alloc.ID] = make(map[string]*volumeMount)
+	}
+	m.mounts[alloc.ID][vol.ID] = &volumeMount{
+		instance:   p,
+		externalID: vol.ExternalID,
+		staged:     staged,
+	}
+	m.lock.Unlock()
+
+	return p.hostPath(target), nil
+}
+
+func (m *manager) UnmountVolume(alloc *structs.Allocation, req *structs.VolumeRequest) error {
+	volumeID := req.Source
+
+	m.lock.Lock()
+	mount := m.mounts[alloc.ID][volumeID]
+	delete(m.mounts[alloc.ID], volumeID)
+	if len(m.mounts[alloc.ID]) == 0 {
+		delete(m.mounts, alloc.ID)
+	}
+
+	// The volume is unstaged once no other allocation uses it
+	unstage := mount != nil && mount.staged
+	for _, mounts := range m.mounts {
+		if _, ok := mounts[volumeID]; ok {
+			unstage = false
+		}
+	}
+	m.lock.Unlock()
+
+	if mount != nil {
+		ctx := context.Background()
+		p := mount.instance
+		target := filepath.Join(perAllocDir, alloc.ID, volumeID)
+		if err := p.client.NodeUnpublishVolume(ctx, mount.externalID, p.pluginPath(target)); err != nil {
+			return fmt.Errorf("failed to unpublish volume %q: %v", volumeID, err)
+		}
+		os.RemoveAll(filepath.Dir(p.hostPath(target)))
+
+		if unstage {
+			staging := filepath.Join(stagingDir, volumeID)
+			if err := p.client.NodeUnstageVolume(ctx, mount.externalID, p.pluginPath(staging)); err != nil {
+				return fmt.Errorf("failed to unstage volume %q: %v", volumeID, err)
+			}
+		}
+	}
+
+	if _, err := m.config.ClaimVolume(alloc, volumeID, structs.CSIVolumeClaimRelease); err != nil {
+		return fmt.Errorf("failed to release claim on volume %q: %v", volumeID, err)
+	}
+	return nil
+}
+
+func (m *manager) ControllerAttachVolume(ctx context.Context, req *cstructs.ClientCSIControllerAttachVolumeRequest) (map[string]string, error) {
+	p := m.instance(structs.CSIPluginTypeController, req.PluginID)
+	if p == nil {
+		return nil, fmt.Errorf("controller plugin %q is not running", req.PluginID)
+	}
+
+	capability, err := csi.VolumeCapabilityFromStructs(req.AttachmentMode, req.AccessMode, req.MountOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.ControllerPublishVolume(ctx, &csi.ControllerPublishVolumeRequest{
+		VolumeID:         req.VolumeID,
+		NodeID:           req.ClientCSINodeID,
+		ReadOnly:         req.ReadOnly,
+		VolumeCapability: capability,
+		VolumeContext:    req.VolumeContext,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.PublishContext, nil
+}
+
+func (m *manager) ControllerDetachVolume(ctx context.Context, req *cstructs.ClientCSIControllerDetachVolumeRequest) error {
+	p := m.instance(structs.CSIPluginTypeController, req.PluginID)
+	if p == nil {
+		return fmt.Errorf("controller plugin %q is not running", req.PluginID)
+	}
+	return p.client.ControllerUnpublishVolume(ctx, req.VolumeID, req.ClientCSINodeID)
+}
@@ -0,0 +1,164 @@
+package csimanager
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/csi"
+	"github.com/hashicorp/nomad/plugins/csi/fake"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testManager records the updates and claims made by a manager
+type testManager struct {
+	*manager
+	client *fake.Client
+
+	infos  map[structs.CSIPluginType]*structs.CSIInfo
+	claims []structs.CSIVolumeClaimMode
+	lock   sync.Mutex
+}
+
+func newTestManager(t *testing.T, vol *structs.CSIVolume) (*testManager, func()) {
+	dir, err := ioutil.TempDir("", "csimanager")
+	require.NoError(t, err)
+
+	tm := &testManager{
+		client: &fake.Client{
+			NextPluginGetInfoName:             "fake",
+			NextPluginGetInfoVer:              "1.0.0",
+			NextPluginProbeResponse:           true,
+			NextPluginGetCapabilitiesResponse: &csi.PluginCapabilitySet{HasControllerService: true},
+			NextControllerGetCapabilitiesResponse: &csi.ControllerCapabilitySet{
+				HasPublishUnpublishVolume: true,
+			},
+			NextNodeGetInfoResponse:         &csi.NodeGetInfoResponse{NodeID: "fake-node"},
+			NextNodeGetCapabilitiesResponse: &csi.NodeCapabilitySet{HasStageUnstageVolume: true},
+		},
+		infos: make(map[structs.CSIPluginType]*structs.CSIInfo),
+	}
+
+	config := &Config{
+		Logger:     log.New(os.Stderr, "", log.LstdFlags),
+		PluginsDir: dir,
+		UpdateNodeCSIInfo: func(pluginType structs.CSIPluginType, pluginID string, info *structs.CSIInfo) {
+			tm.lock.Lock()
+			defer tm.lock.Unlock()
+			if info == nil {
+				delete(tm.infos, pluginType)
+			} else {
+				tm.infos[pluginType] = info
+			}
+		},
+		ClaimVolume: func(alloc *structs.Allocation, volumeID string, mode structs.CSIVolumeClaimMode) (*structs.CSIVolumeClaimResponse, error) {
+			tm.lock.Lock()
+			defer tm.lock.Unlock()
+			tm.claims = append(tm.claims, mode)
+			return &structs.CSIVolumeClaimResponse{Volume: vol}, nil
+		},
+		newClient: func(string) (csi.CSIPlugin, error) {
+			return tm.client, nil
+		},
+	}
+	tm.manager = New(config).(*manager)
+
+	return tm, func() { os.RemoveAll(dir) }
+}
+
+func (tm *testManager) info(pluginType structs.CSIPluginType) *structs.CSIInfo {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+	return tm.infos[pluginType]
+}
+
+// startPlugin creates the plugin's socket and watches it until the returned
+// cancel function is called
+func (tm *testManager) startPlugin(t *testing.T, cfg *structs.TaskCSIPluginConfig) context.CancelFunc {
+	dir := tm.PluginDir(cfg)
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, structs.CSISocketName), nil, 0600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go tm.WatchPlugin(ctx, "alloc", cfg)
+	return cancel
+}
+
+func TestManager_WatchPlugin(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	tm, cleanup := newTestManager(t, nil)
+	defer cleanup()
+
+	cfg := &structs.TaskCSIPluginConfig{ID: "fake", Type: structs.CSIPluginTypeMonolith, MountDir: "/csi"}
+	cancel := tm.startPlugin(t, cfg)
+
+	testutil.WaitForResult(func() (bool, error) {
+		return tm.info(structs.CSIPluginTypeController) != nil && tm.info(structs.CSIPluginTypeNode) != nil, nil
+	}, func(err error) {
+		t.Fatalf("plugin not fingerprinted")
+	})
+
+	node := tm.info(structs.CSIPluginTypeNode)
+	require.True(node.Healthy)
+	require.Equal("fake", node.Provider)
+	require.Equal("alloc", node.AllocID)
+	require.Equal("fake-node", node.NodeInfo.ID)
+	require.True(node.NodeInfo.RequiresNodeStageVolume)
+
+	controller := tm.info(structs.CSIPluginTypeController)
+	require.True(controller.Healthy)
+	require.True(controller.ControllerInfo.SupportsAttachDetach)
+	require.NotNil(tm.instance(structs.CSIPluginTypeController, "fake"))
+
+	// Stopping the plugin removes it from the node
+	cancel()
+	testutil.WaitForResult(func() (bool, error) {
+		return tm.info(structs.CSIPluginTypeController) == nil && tm.info(structs.CSIPluginTypeNode) == nil, nil
+	}, func(err error) {
+		t.Fatalf("plugin not removed")
+	})
+	require.Nil(tm.instance(structs.CSIPluginTypeNode, "fake"))
+}
+
+func TestManager_MountVolume(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	vol := &structs.CSIVolume{
+		ID:             "vol",
+		ExternalID:     "ext-vol",
+		PluginID:       "fake",
+		AccessMode:     structs.CSIVolumeAccessModeSingleNodeWriter,
+		AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+	}
+	tm, cleanup := newTestManager(t, vol)
+	defer cleanup()
+
+	cfg := &structs.TaskCSIPluginConfig{ID: "fake", Type: structs.CSIPluginTypeNode, MountDir: "/csi"}
+	cancel := tm.startPlugin(t, cfg)
+	defer cancel()
+
+	alloc := mock.Alloc()
+	req := &structs.VolumeRequest{Name: "data", Type: structs.VolumeTypeCSI, Source: "vol"}
+	path, err := tm.MountVolume(context.Background(), alloc, req)
+	require.NoError(err)
+	require.Equal(filepath.Join(tm.PluginDir(cfg), "per-alloc", alloc.ID, "vol"), path)
+
+	require.Equal(int64(1), tm.client.NodeStageVolumeCallCount)
+	require.Equal(int64(1), tm.client.NodePublishVolumeCallCount)
+	require.Equal(csi.VolumeAccessModeSingleNodeWriter, tm.client.PrevVolumeCapability.AccessMode)
+	require.Equal([]structs.CSIVolumeClaimMode{structs.CSIVolumeClaimWrite}, tm.claims)
+
+	require.NoError(tm.UnmountVolume(alloc, req))
+	require.Equal(int64(1), tm.client.NodeUnpublishVolumeCallCount)
+	require.Equal(int64(1), tm.client.NodeUnstageVolumeCallCount)
+	require.Equal(structs.CSIVolumeClaimRelease, tm.claims[len(tm.claims)-1])
+}
@@ -0,0 +1,340 @@
+package devicemanager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/device"
+	"github.com/hashicorp/nomad/plugins/shared/loader"
+)
+
+const (
+	// relaunchBackoffBase and relaunchBackoffLimit bound the wait before a
+	// plugin that exited or failed to launch is relaunched.
+	relaunchBackoffBase  = 5 * time.Second
+	relaunchBackoffLimit = 2 * time.Minute
+
+	// exitCheckInterval is the interval at which the manager checks whether a
+	// plugin exited, in case its fingerprint stream doesn't end when it does.
+	exitCheckInterval = 5 * time.Second
+
+	// statsRetryInterval is the wait before collecting the statistics of a
+	// plugin again after its stream failed.
+	statsRetryInterval = 30 * time.Second
+)
+
+// instance runs a device plugin and tracks the devices it detected and their
+// statistics.
+type instance struct {
+	m    *manager
+	info *base.PluginInfoResponse
+
+	lock sync.Mutex
+
+	// plugin is the running plugin, nil while it is being launched
+	plugin loader.PluginInstance
+
+	// groups and stats are the detected device groups and their latest
+	// statistics, by device group ID
+	groups map[string]*device.DeviceGroup
+	stats  map[string]*device.DeviceGroupStats
+
+	// attributes are the node attributes set from the detected devices
+	attributes map[string]string
+
+	healthy           bool
+	healthDescription string
+	restarts          int
+	updateTime        time.Time
+}
+
+func newInstance(m *manager, info *base.PluginInfoResponse) *instance {
+	return &instance{
+		m:                 m,
+		info:              info,
+		groups:            make(map[string]*device.DeviceGroup),
+		stats:             make(map[string]*device.DeviceGroupStats),
+		healthDescription: "plugin is launching",
+		updateTime:        time.Now(),
+	}
+}
+
+// run launches the plugin and fingerprints it, relaunching it whenever it
+// exits or its fingerprint stream ends, until the context is cancelled.
+func (i *instance) run(ctx context.Context) {
+	backoff := relaunchBackoffBase
+	for launched := false; ; launched = true {
+		if launched {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > relaunchBackoffLimit {
+				backoff = relaunchBackoffLimit
+			}
+		}
+
+		p, err := i.m.config.Loader.Dispense(i.info.Name, base.PluginTypeDevice, i.m.logger)
+		if err != nil {
+			i.m.logger.Printf("[ERR] client.device: failed to launch device plugin %q: %v", i.info.Name, err)
+			i.setHealth(false, fmt.Sprintf("failed to launch: %v", err))
+			continue
+		}
+		impl, ok := p.Plugin().(device.DevicePlugin)
+		if !ok {
+			p.Kill()
+			i.m.logger.Printf("[ERR] client.device: unexpected device plugin type %T for plugin %q", p.Plugin(), i.info.Name)
+			return
+		}
+
+		i.lock.Lock()
+		i.plugin = p
+		if launched {
+			i.restarts++
+		}
+		i.lock.Unlock()
+		i.setHealth(true, "")
+
+		start := time.Now()
+		err = i.watch(ctx, p, impl)
+
+		i.lock.Lock()
+		i.plugin = nil
+		i.lock.Unlock()
+		p.Kill()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if p.Exited() {
+			i.m.logger.Printf("[WARN] client.device: device plugin %q exited, relaunching it: %v", i.info.Name, err)
+			i.setHealth(false, "plugin exited")
+		} else {
+			i.m.logger.Printf("[WARN] client.device: device plugin %q stopped fingerprinting, relaunching it: %v", i.info.Name, err)
+			i.setHealth(false, "plugin stopped fingerprinting")
+		}
+		i.setGroups(nil)
+		if !p.Exited() || time.Since(start) > relaunchBackoffLimit {
+			// The plugin didn't crash or ran for a while, so it is not a
+			// crash loop to back off from.
+			backoff = relaunchBackoffBase
+		}
+	}
+}
+
+// watch fingerprints the plugin and collects the statistics of its devices
+// until the plugin exits, the fingerprint stream ends or the context is
+// cancelled.
+func (i *instance) watch(ctx context.Context, p loader.PluginInstance, impl device.DevicePlugin) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch, err := impl.Fingerprint(ctx)
+	if err != nil {
+		return err
+	}
+
+	go i.collectStats(ctx, impl)
+
+	ticker := time.NewTicker(exitCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if p.Exited() {
+				return fmt.Errorf("plugin exited")
+			}
+		case resp, ok := <-ch:
+			if !ok {
+				return fmt.Errorf("fingerprint stream closed")
+			}
+			if resp.Error != nil {
+				return resp.Error
+			}
+			i.updateGroups(resp.Devices)
+		}
+	}
+}
+
+// collectStats collects the statistics of the devices every stats interval
+// until the context is cancelled. Failing to collect statistics is logged
+// and retried but doesn't affect the health of the plugin.
+func (i *instance) collectStats(ctx context.Context, impl device.DevicePlugin) {
+	for {
+		if err := i.streamStats(ctx, impl); err != nil {
+			i.m.logger.Printf("[WARN] client.device: failed to collect statistics of device plugin %q: %v", i.info.Name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(statsRetryInterval):
+		}
+	}
+}
+
+func (i *instance) streamStats(ctx context.Context, impl device.DevicePlugin) error {
+	ch, err := impl.Stats(ctx, i.m.config.StatsInterval)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case resp, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if resp.Error != nil {
+				return resp.Error
+			}
+
+			i.lock.Lock()
+			for _, g := range resp.Groups {
+				i.stats[g.ID()] = g
+			}
+			i.lock.Unlock()
+		}
+	}
+}
+
+// updateGroups records the device groups of a fingerprint response. Plugins
+// send groups one at a time, so groups that are not part of the response are
+// kept.
+func (i *instance) updateGroups(groups []*device.DeviceGroup) {
+	i.lock.Lock()
+	for _, g := range groups {
+		i.groups[g.ID()] = g
+	}
+	all := make([]*device.DeviceGroup, 0, len(i.groups))
+	for _, g := range i.groups {
+		all = append(all, g)
+	}
+	i.lock.Unlock()
+
+	i.setGroups(all)
+}
+
+// setGroups replaces the detected device groups and updates the node
+// attributes derived from them.
+func (i *instance) setGroups(groups []*device.DeviceGroup) {
+	attrs := make(map[string]string)
+	for _, g := range groups {
+		prefix := fmt.Sprintf("device.%s.%s.%s.", g.Vendor, g.Type, g.Name)
+		healthy := 0
+		for _, d := range g.Devices {
+			if d.Healthy {
+				healthy++
+			}
+		}
+		attrs[prefix+"count"] = strconv.Itoa(healthy)
+		for k, v := range g.Attributes {
+			attrs[prefix+strings.TrimPrefix(k, prefix)] = v
+		}
+	}
+
+	i.lock.Lock()
+	i.groups = make(map[string]*device.DeviceGroup, len(groups))
+	for _, g := range groups {
+		i.groups[g.ID()] = g
+	}
+	if len(groups) == 0 {
+		i.stats = make(map[string]*device.DeviceGroupStats)
+	}
+	previous := i.attributes
+	i.attributes = attrs
+	i.lock.Unlock()
+
+	resp := &cstructs.FingerprintResponse{}
+	for k := range previous {
+		if _, ok := attrs[k]; !ok {
+			resp.RemoveAttribute(k)
+		}
+	}
+	changed := len(resp.Attributes) != 0
+	for k, v := range attrs {
+		if previous[k] != v {
+			resp.AddAttribute(k, v)
+			changed = true
+		}
+	}
+	if changed && i.m.config.UpdateNodeFromFingerprint != nil {
+		i.m.config.UpdateNodeFromFingerprint(resp)
+	}
+}
+
+func (i *instance) hasGroup(groupID string) bool {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	_, ok := i.groups[groupID]
+	return ok
+}
+
+func (i *instance) reserve(groupID string, deviceIDs []string) (*device.ContainerReservation, error) {
+	i.lock.Lock()
+	p := i.plugin
+	i.lock.Unlock()
+	if p == nil {
+		return nil, fmt.Errorf("device plugin %q is not running", i.info.Name)
+	}
+
+	return p.Plugin().(device.DevicePlugin).Reserve(deviceIDs)
+}
+
+// latestStats returns the latest statistics of the detected device groups.
+func (i *instance) latestStats() []*device.DeviceGroupStats {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	stats := make([]*device.DeviceGroupStats, 0, len(i.stats))
+	for id, s := range i.stats {
+		if _, ok := i.groups[id]; ok {
+			stats = append(stats, s.Copy())
+		}
+	}
+	return stats
+}
+
+func (i *instance) setHealth(healthy bool, desc string) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if i.healthy == healthy && i.healthDescription == desc {
+		return
+	}
+	i.healthy = healthy
+	i.healthDescription = desc
+	i.updateTime = time.Now()
+}
+
+func (i *instance) status() *cstructs.PluginStatus {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	s := &cstructs.PluginStatus{
+		Name:              i.info.Name,
+		Type:              base.PluginTypeDevice,
+		PluginVersion:     i.info.PluginVersion,
+		Healthy:           i.healthy,
+		HealthDescription: i.healthDescription,
+		Restarts:          i.restarts,
+		UpdateTime:        i.updateTime,
+	}
+	if i.plugin != nil {
+		s.ApiVersion = i.plugin.ApiVersion()
+		s.Internal = i.plugin.Internal()
+	}
+	return s
+}
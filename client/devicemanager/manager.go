@@ -0,0 +1,234 @@
+// devicemanager manages the device plugins of a client. It fingerprints the
+// devices detected by the plugins to advertise them in the node, collects
+// their statistics and reserves devices for allocations.
+package devicemanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/device"
+	"github.com/hashicorp/nomad/plugins/shared/loader"
+)
+
+// UpdateNodeFromFingerprintFunc is called with the node attributes derived
+// from the detected devices. Removed attributes are set to an empty value.
+type UpdateNodeFromFingerprintFunc func(*cstructs.FingerprintResponse)
+
+// Config configures the manager
+type Config struct {
+	Logger *log.Logger
+
+	// Loader is the catalog the device plugins are dispensed from
+	Loader loader.PluginCatalog
+
+	// StatsInterval is the interval at which the statistics of the devices
+	// are collected
+	StatsInterval time.Duration
+
+	UpdateNodeFromFingerprint UpdateNodeFromFingerprintFunc
+}
+
+// Manager manages the device plugins of a client
+type Manager interface {
+	// Run launches the device plugins of the catalog and fingerprints them
+	// until Shutdown is called.
+	Run()
+
+	// Shutdown kills the device plugins.
+	Shutdown()
+
+	// Reserve reserves devices of a device group for an allocation and
+	// returns how to expose them to its tasks. The statistics of the devices
+	// are reported as the allocation's until it is freed.
+	Reserve(allocID, groupID string, deviceIDs []string) (*device.ContainerReservation, error)
+
+	// Free releases the devices reserved for the allocation.
+	Free(allocID string)
+
+	// AllStats returns the latest statistics of all the devices.
+	AllStats() []*device.DeviceGroupStats
+
+	// AllocStats returns the latest statistics of the devices reserved for
+	// the allocation.
+	AllocStats(allocID string) []*device.DeviceGroupStats
+
+	// DeviceAlloc returns the allocation the device is reserved for, if any.
+	DeviceAlloc(groupID, deviceID string) (string, bool)
+
+	// PluginStatuses returns the status of the device plugins.
+	PluginStatuses() []*cstructs.PluginStatus
+}
+
+type manager struct {
+	config *Config
+	logger *log.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// instances are the device plugins by name
+	instances map[string]*instance
+
+	// reservations maps an allocation to its reserved device IDs by device
+	// group ID
+	reservations map[string]map[string][]string
+
+	lock sync.Mutex
+}
+
+// New returns a manager for the device plugins of a client
+func New(config *Config) Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &manager{
+		config:       config,
+		logger:       config.Logger,
+		ctx:          ctx,
+		cancel:       cancel,
+		instances:    make(map[string]*instance),
+		reservations: make(map[string]map[string][]string),
+	}
+}
+
+func (m *manager) Run() {
+	for _, info := range m.config.Loader.Catalog()[base.PluginTypeDevice] {
+		i := newInstance(m, info)
+
+		m.lock.Lock()
+		m.instances[info.Name] = i
+		m.lock.Unlock()
+
+		go i.run(m.ctx)
+	}
+}
+
+func (m *manager) Shutdown() {
+	m.cancel()
+}
+
+// lookupGroup returns the plugin instance detecting the device group.
+func (m *manager) lookupGroup(groupID string) (*instance, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, i := range m.instances {
+		if i.hasGroup(groupID) {
+			return i, true
+		}
+	}
+	return nil, false
+}
+
+func (m *manager) Reserve(allocID, groupID string, deviceIDs []string) (*device.ContainerReservation, error) {
+	i, ok := m.lookupGroup(groupID)
+	if !ok {
+		return nil, fmt.Errorf("unknown device group %q", groupID)
+	}
+
+	res, err := i.reserve(groupID, deviceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	groups, ok := m.reservations[allocID]
+	if !ok {
+		groups = make(map[string][]string)
+		m.reservations[allocID] = groups
+	}
+	groups[groupID] = append(groups[groupID], deviceIDs...)
+	return res, nil
+}
+
+func (m *manager) Free(allocID string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.reservations, allocID)
+}
+
+func (m *manager) AllStats() []*device.DeviceGroupStats {
+	m.lock.Lock()
+	instances := make([]*instance, 0, len(m.instances))
+	for _, i := range m.instances {
+		instances = append(instances, i)
+	}
+	m.lock.Unlock()
+
+	var stats []*device.DeviceGroupStats
+	for _, i := range instances {
+		stats = append(stats, i.latestStats()...)
+	}
+	sortGroupStats(stats)
+	return stats
+}
+
+func (m *manager) AllocStats(allocID string) []*device.DeviceGroupStats {
+	m.lock.Lock()
+	groups := m.reservations[allocID]
+	m.lock.Unlock()
+	if len(groups) == 0 {
+		return nil
+	}
+
+	var stats []*device.DeviceGroupStats
+	for _, g := range m.AllStats() {
+		ids, ok := groups[g.ID()]
+		if !ok {
+			continue
+		}
+
+		filtered := &device.DeviceGroupStats{
+			Vendor:        g.Vendor,
+			Type:          g.Type,
+			Name:          g.Name,
+			InstanceStats: make(map[string]*device.DeviceStats, len(ids)),
+		}
+		for _, id := range ids {
+			if s, ok := g.InstanceStats[id]; ok {
+				filtered.InstanceStats[id] = s
+			}
+		}
+		stats = append(stats, filtered)
+	}
+	return stats
+}
+
+func (m *manager) DeviceAlloc(groupID, deviceID string) (string, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for allocID, groups := range m.reservations {
+		for _, id := range groups[groupID] {
+			if id == deviceID {
+				return allocID, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (m *manager) PluginStatuses() []*cstructs.PluginStatus {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	statuses := make([]*cstructs.PluginStatus, 0, len(m.instances))
+	for _, i := range m.instances {
+		statuses = append(statuses, i.status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// sortGroupStats sorts the device group statistics by ID.
+func sortGroupStats(stats []*device.DeviceGroupStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].ID() < stats[j].ID()
+	})
+}
@@ -0,0 +1,263 @@
+package devicemanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/device"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	"github.com/hashicorp/nomad/plugins/shared/loader"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDeviceFactory returns a factory of mock device plugins detecting two
+// devices, one of them unhealthy.
+func mockDeviceFactory(name string) loader.PluginFactory {
+	return func(*log.Logger) interface{} {
+		return &device.MockDevicePlugin{
+			MockPlugin: base.MockPlugin{
+				PluginInfoF: func() (*base.PluginInfoResponse, error) {
+					return &base.PluginInfoResponse{
+						Type:              base.PluginTypeDevice,
+						PluginApiVersions: []string{device.ApiVersion010},
+						PluginVersion:     "v0.1.0",
+						Name:              name,
+					}, nil
+				},
+				ConfigSchemaF: func() (*hclspec.Spec, error) { return nil, nil },
+				SetConfigF:    func(*base.Config) error { return nil },
+			},
+			FingerprintF: func(ctx context.Context) (<-chan *device.FingerprintResponse, error) {
+				ch := make(chan *device.FingerprintResponse, 1)
+				ch <- &device.FingerprintResponse{
+					Devices: []*device.DeviceGroup{{
+						Vendor: "nvidia",
+						Type:   "gpu",
+						Name:   "1080ti",
+						Devices: []*device.Device{
+							{ID: "1", Healthy: true},
+							{ID: "2", Healthy: false, HealthDesc: "overheating"},
+						},
+						Attributes: map[string]string{"memory": "11GiB"},
+					}},
+				}
+				go func() {
+					<-ctx.Done()
+					close(ch)
+				}()
+				return ch, nil
+			},
+			ReserveF: func(ids []string) (*device.ContainerReservation, error) {
+				return &device.ContainerReservation{
+					Envs: map[string]string{"DEVICES": fmt.Sprint(ids)},
+				}, nil
+			},
+			StatsF: func(ctx context.Context, interval time.Duration) (<-chan *device.StatsResponse, error) {
+				ch := make(chan *device.StatsResponse)
+				go func() {
+					defer close(ch)
+					for {
+						resp := &device.StatsResponse{
+							Groups: []*device.DeviceGroupStats{{
+								Vendor: "nvidia",
+								Type:   "gpu",
+								Name:   "1080ti",
+								InstanceStats: map[string]*device.DeviceStats{
+									"1": {Summary: &device.StatValue{FloatNumeratorVal: helper.Float64ToPtr(42)}},
+									"2": {Summary: &device.StatValue{FloatNumeratorVal: helper.Float64ToPtr(7)}},
+								},
+							}},
+						}
+						select {
+						case <-ctx.Done():
+							return
+						case ch <- resp:
+						}
+						select {
+						case <-ctx.Done():
+							return
+						case <-time.After(interval):
+						}
+					}
+				}()
+				return ch, nil
+			},
+		}
+	}
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	id := loader.PluginID{Name: "mock", PluginType: base.PluginTypeDevice}
+	l, err := loader.NewPluginLoader(&loader.PluginLoaderConfig{
+		Logger: testlog.Logger(t),
+		InternalPlugins: map[loader.PluginID]*loader.InternalPluginConfig{
+			id: {Factory: mockDeviceFactory("mock")},
+		},
+	})
+	require.NoError(err)
+
+	var lock sync.Mutex
+	attrs := make(map[string]string)
+	m := New(&Config{
+		Logger:        testlog.Logger(t),
+		Loader:        l,
+		StatsInterval: 10 * time.Millisecond,
+		UpdateNodeFromFingerprint: func(resp *cstructs.FingerprintResponse) {
+			lock.Lock()
+			defer lock.Unlock()
+			for k, v := range resp.Attributes {
+				attrs[k] = v
+			}
+		},
+	})
+	m.Run()
+	defer m.Shutdown()
+
+	// The devices are advertised as node attributes
+	testutil.WaitForResult(func() (bool, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if attrs["device.nvidia.gpu.1080ti.count"] != "1" {
+			return false, fmt.Errorf("unexpected attributes: %v", attrs)
+		}
+		if attrs["device.nvidia.gpu.1080ti.memory"] != "11GiB" {
+			return false, fmt.Errorf("unexpected attributes: %v", attrs)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// The statistics of all the devices are collected
+	testutil.WaitForResult(func() (bool, error) {
+		stats := m.AllStats()
+		if len(stats) != 1 || len(stats[0].InstanceStats) != 2 {
+			return false, fmt.Errorf("unexpected stats: %v", stats)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// Reserved devices are attributed to the allocation
+	res, err := m.Reserve("alloc", "nvidia/gpu/1080ti", []string{"1"})
+	require.NoError(err)
+	require.Equal("[1]", res.Envs["DEVICES"])
+
+	allocStats := m.AllocStats("alloc")
+	require.Len(allocStats, 1)
+	require.Len(allocStats[0].InstanceStats, 1)
+	v, ok := allocStats[0].InstanceStats["1"].Summary.Float()
+	require.True(ok)
+	require.Equal(42.0, v)
+
+	allocID, ok := m.DeviceAlloc("nvidia/gpu/1080ti", "1")
+	require.True(ok)
+	require.Equal("alloc", allocID)
+	_, ok = m.DeviceAlloc("nvidia/gpu/1080ti", "2")
+	require.False(ok)
+
+	// Unknown device groups can't be reserved
+	_, err = m.Reserve("alloc", "amd/gpu/vega", []string{"1"})
+	require.Error(err)
+
+	// Freeing the allocation releases its devices
+	m.Free("alloc")
+	require.Empty(m.AllocStats("alloc"))
+	_, ok = m.DeviceAlloc("nvidia/gpu/1080ti", "1")
+	require.False(ok)
+
+	// The plugin is reported as healthy
+	statuses := m.PluginStatuses()
+	require.Len(statuses, 1)
+	require.Equal("mock", statuses[0].Name)
+	require.Equal(base.PluginTypeDevice, statuses[0].Type)
+	require.True(statuses[0].Healthy)
+}
+
+// exitingCatalog dispenses plugins that report having exited once exit is set
+type exitingCatalog struct {
+	loader.PluginCatalog
+	exit int32
+}
+
+func (c *exitingCatalog) Dispense(name, pluginType string, logger *log.Logger) (loader.PluginInstance, error) {
+	p, err := c.PluginCatalog.Dispense(name, pluginType, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &exitingInstance{PluginInstance: p, catalog: c}, nil
+}
+
+type exitingInstance struct {
+	loader.PluginInstance
+	catalog *exitingCatalog
+}
+
+func (p *exitingInstance) Exited() bool {
+	return atomic.LoadInt32(&p.catalog.exit) == 1
+}
+
+func TestManager_RelaunchExited(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	id := loader.PluginID{Name: "mock", PluginType: base.PluginTypeDevice}
+	l, err := loader.NewPluginLoader(&loader.PluginLoaderConfig{
+		Logger: testlog.Logger(t),
+		InternalPlugins: map[loader.PluginID]*loader.InternalPluginConfig{
+			id: {Factory: mockDeviceFactory("mock")},
+		},
+	})
+	require.NoError(err)
+
+	catalog := &exitingCatalog{PluginCatalog: l}
+	m := New(&Config{
+		Logger:                    testlog.Logger(t),
+		Loader:                    catalog,
+		StatsInterval:             10 * time.Millisecond,
+		UpdateNodeFromFingerprint: func(*cstructs.FingerprintResponse) {},
+	})
+	m.Run()
+	defer m.Shutdown()
+
+	testutil.WaitForResult(func() (bool, error) {
+		stats := m.AllStats()
+		return len(stats) == 1, fmt.Errorf("unexpected stats: %v", stats)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// The plugin exits while its fingerprint stream is still open
+	atomic.StoreInt32(&catalog.exit, 1)
+	testutil.WaitForResultRetries(2000*testutil.TestMultiplier(), func() (bool, error) {
+		statuses := m.PluginStatuses()
+		return !statuses[0].Healthy && statuses[0].HealthDescription == "plugin exited",
+			fmt.Errorf("unexpected status: %+v", statuses[0])
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// The plugin is relaunched once it runs again
+	atomic.StoreInt32(&catalog.exit, 0)
+	testutil.WaitForResultRetries(2000*testutil.TestMultiplier(), func() (bool, error) {
+		statuses := m.PluginStatuses()
+		return statuses[0].Healthy && statuses[0].Restarts > 0,
+			fmt.Errorf("unexpected status: %+v", statuses[0])
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
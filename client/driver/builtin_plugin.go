@@ -0,0 +1,547 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-template/signals"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/driver/env"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+const (
+	// builtinTaskHandleVersion is the version of the driver state stored in
+	// the handles of the tasks run by built-in drivers.
+	builtinTaskHandleVersion = 1
+)
+
+// BuiltinDriverPlugin implements the driver plugin interface on top of a
+// built-in driver, so built-in drivers can be used wherever driver plugins
+// are, including being served out of process.
+type BuiltinDriverPlugin struct {
+	name    string
+	factory Factory
+	config  *config.Config
+	logger  *log.Logger
+
+	tasks     map[string]*builtinTask
+	tasksLock sync.RWMutex
+}
+
+// builtinTask is the state of a task run by a built-in driver.
+type builtinTask struct {
+	task    *structs.Task
+	ctx     *ExecContext
+	driver  Driver
+	handle  DriverHandle
+	created *CreatedResources
+
+	startedAt   time.Time
+	completedAt time.Time
+	exitResult  *drivers.ExitResult
+	doneCh      chan struct{}
+}
+
+// builtinTaskState is the driver state stored in the task handles.
+type builtinTaskState struct {
+	HandleID         string
+	CreatedResources *CreatedResources
+	StartedAt        time.Time
+}
+
+// NewBuiltinDriverPlugin returns the driver plugin of the named built-in
+// driver.
+func NewBuiltinDriverPlugin(name string, cfg *config.Config, logger *log.Logger) (*BuiltinDriverPlugin, error) {
+	factory, ok := BuiltinDrivers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown driver '%s'", name)
+	}
+
+	return &BuiltinDriverPlugin{
+		name:    name,
+		factory: factory,
+		config:  cfg,
+		logger:  logger,
+		tasks:   make(map[string]*builtinTask),
+	}, nil
+}
+
+// newDriver instantiates the built-in driver for the task.
+func (p *BuiltinDriverPlugin) newDriver(cfg *drivers.TaskConfig) Driver {
+	emitter := func(m string, args ...interface{}) {
+		p.logger.Printf("[DEBUG] driver.%s: task %q: %s", p.name, cfg.ID, fmt.Sprintf(m, args...))
+	}
+	ctx := NewDriverContext(cfg.JobName, cfg.TaskGroupName, cfg.Name, cfg.AllocID,
+		p.config, p.config.Node, p.logger, emitter)
+	return p.factory(ctx)
+}
+
+func (p *BuiltinDriverPlugin) PluginInfo() (*base.PluginInfoResponse, error) {
+	return &base.PluginInfoResponse{
+		Type:              base.PluginTypeDriver,
+		PluginApiVersions: []string{drivers.ApiVersion010},
+		PluginVersion:     p.config.Version.VersionNumber(),
+		Name:              p.name,
+	}, nil
+}
+
+// ConfigSchema returns no schema as built-in drivers are configured using the
+// options of the client.
+func (p *BuiltinDriverPlugin) ConfigSchema() (*hclspec.Spec, error) {
+	return nil, nil
+}
+
+func (p *BuiltinDriverPlugin) SetConfig(c *base.Config) error {
+	return nil
+}
+
+// TaskConfigSchema returns no schema as built-in drivers validate the driver
+// configuration of tasks themselves.
+func (p *BuiltinDriverPlugin) TaskConfigSchema() (*hclspec.Spec, error) {
+	return nil, nil
+}
+
+func (p *BuiltinDriverPlugin) Capabilities() (*drivers.Capabilities, error) {
+	d := p.newDriver(&drivers.TaskConfig{})
+	abilities := d.Abilities()
+
+	caps := &drivers.Capabilities{
+		SendSignals: abilities.SendSignals,
+		Exec:        abilities.Exec,
+		FSIsolation: drivers.FSIsolationNone,
+	}
+	switch d.FSIsolation() {
+	case cstructs.FSIsolationChroot:
+		caps.FSIsolation = drivers.FSIsolationChroot
+	case cstructs.FSIsolationImage:
+		caps.FSIsolation = drivers.FSIsolationImage
+	}
+
+	return caps, nil
+}
+
+func (p *BuiltinDriverPlugin) Fingerprint(ctx context.Context) (<-chan *drivers.Fingerprint, error) {
+	ch := make(chan *drivers.Fingerprint)
+	go p.handleFingerprint(ctx, ch)
+	return ch, nil
+}
+
+// handleFingerprint fingerprints the driver until the context is done. Non
+// periodic drivers are fingerprinted once.
+func (p *BuiltinDriverPlugin) handleFingerprint(ctx context.Context, ch chan *drivers.Fingerprint) {
+	defer close(ch)
+
+	d := p.newDriver(&drivers.TaskConfig{})
+	periodic, period := d.Periodic()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- p.fingerprint(d):
+		}
+
+		if !periodic {
+			<-ctx.Done()
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(period):
+		}
+	}
+}
+
+// fingerprint returns the current fingerprint of the built-in driver.
+func (p *BuiltinDriverPlugin) fingerprint(d Driver) *drivers.Fingerprint {
+	var resp cstructs.FingerprintResponse
+	req := &cstructs.FingerprintRequest{Config: p.config, Node: p.config.Node}
+	if err := d.Fingerprint(req, &resp); err != nil {
+		return &drivers.Fingerprint{
+			Health:            drivers.HealthStateUndetected,
+			HealthDescription: fmt.Sprintf("Failed to fingerprint driver: %v", err),
+		}
+	}
+
+	f := &drivers.Fingerprint{
+		Attributes:        resp.Attributes,
+		Health:            drivers.HealthStateUndetected,
+		HealthDescription: fmt.Sprintf("Driver %s is not detected", p.name),
+	}
+	if !resp.Detected {
+		return f
+	}
+
+	f.Health = drivers.HealthStateHealthy
+	f.HealthDescription = fmt.Sprintf("Driver %s is detected", p.name)
+
+	// Use the health checks of the driver if it has any
+	if hc, ok := d.(interface {
+		HealthCheck(*cstructs.HealthCheckRequest, *cstructs.HealthCheckResponse) error
+	}); ok {
+		var hresp cstructs.HealthCheckResponse
+		err := hc.HealthCheck(&cstructs.HealthCheckRequest{}, &hresp)
+		if info, ok := hresp.Drivers[p.name]; ok && info != nil {
+			f.HealthDescription = info.HealthDescription
+			if !info.Healthy {
+				f.Health = drivers.HealthStateUnhealthy
+			}
+		} else if err != nil {
+			f.Health = drivers.HealthStateUnhealthy
+			f.HealthDescription = err.Error()
+		}
+	}
+
+	return f
+}
+
+// execContext returns the execution context of the task.
+func (p *BuiltinDriverPlugin) execContext(cfg *drivers.TaskConfig) *ExecContext {
+	var nodeAttrs map[string]string
+	if p.config.Node != nil {
+		nodeAttrs = p.config.Node.Attributes
+	}
+
+	return NewExecContext(cfg.TaskDir(), env.NewTaskEnv(cfg.Env, nodeAttrs))
+}
+
+// structsTask returns the task of the task config, as passed to the built-in
+// drivers.
+func (p *BuiltinDriverPlugin) structsTask(cfg *drivers.TaskConfig) (*structs.Task, error) {
+	var driverConfig map[string]interface{}
+	if err := cfg.DecodeDriverConfig(&driverConfig); err != nil {
+		return nil, fmt.Errorf("failed to decode driver config: %v", err)
+	}
+
+	return &structs.Task{
+		Name:      cfg.Name,
+		Driver:    p.name,
+		User:      cfg.User,
+		Config:    driverConfig,
+		Env:       cfg.Env,
+		Resources: cfg.Resources,
+		LogConfig: cfg.LogConfig,
+	}, nil
+}
+
+func (p *BuiltinDriverPlugin) RecoverTask(h *drivers.TaskHandle) error {
+	if h.Config == nil {
+		return fmt.Errorf("handle has no task config")
+	}
+
+	p.tasksLock.RLock()
+	_, ok := p.tasks[h.Config.ID]
+	p.tasksLock.RUnlock()
+	if ok {
+		// The task is already running
+		return nil
+	}
+
+	if h.Version != builtinTaskHandleVersion {
+		return fmt.Errorf("unsupported task handle version %d", h.Version)
+	}
+
+	var state builtinTaskState
+	if err := h.GetDriverState(&state); err != nil {
+		return fmt.Errorf("failed to decode driver state: %v", err)
+	}
+
+	task, err := p.structsTask(h.Config)
+	if err != nil {
+		return err
+	}
+
+	d := p.newDriver(h.Config)
+	ctx := p.execContext(h.Config)
+	handle, err := d.Open(ctx, state.HandleID)
+	if err != nil {
+		return err
+	}
+
+	p.addTask(h.Config.ID, &builtinTask{
+		task:      task,
+		ctx:       ctx,
+		driver:    d,
+		handle:    handle,
+		created:   state.CreatedResources,
+		startedAt: state.StartedAt,
+		doneCh:    make(chan struct{}),
+	})
+	return nil
+}
+
+func (p *BuiltinDriverPlugin) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstructs.DriverNetwork, error) {
+	task, err := p.structsTask(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d := p.newDriver(cfg)
+	if err := d.Validate(task.Config); err != nil {
+		return nil, nil, structs.NewRecoverableError(err, false)
+	}
+
+	ctx := p.execContext(cfg)
+	presp, err := d.Prestart(ctx, task)
+	var created *CreatedResources
+	if presp != nil {
+		created = presp.CreatedResources
+	}
+	if err != nil {
+		if created != nil {
+			if cerr := d.Cleanup(ctx, created); cerr != nil {
+				p.logger.Printf("[ERR] driver.%s: failed to cleanup task %q: %v", p.name, cfg.ID, cerr)
+			}
+		}
+		return nil, nil, err
+	}
+
+	sresp, err := d.Start(ctx, task)
+	if err != nil {
+		if created != nil {
+			if cerr := d.Cleanup(ctx, created); cerr != nil {
+				p.logger.Printf("[ERR] driver.%s: failed to cleanup task %q: %v", p.name, cfg.ID, cerr)
+			}
+		}
+		return nil, nil, err
+	}
+
+	t := &builtinTask{
+		task:      task,
+		ctx:       ctx,
+		driver:    d,
+		handle:    sresp.Handle,
+		created:   created,
+		startedAt: time.Now(),
+		doneCh:    make(chan struct{}),
+	}
+
+	handle := drivers.NewTaskHandle(builtinTaskHandleVersion)
+	handle.Config = cfg
+	handle.State = drivers.TaskStateRunning
+	state := builtinTaskState{
+		HandleID:         sresp.Handle.ID(),
+		CreatedResources: created,
+		StartedAt:        t.startedAt,
+	}
+	if err := handle.SetDriverState(&state); err != nil {
+		sresp.Handle.Kill()
+		return nil, nil, fmt.Errorf("failed to encode driver state: %v", err)
+	}
+
+	p.addTask(cfg.ID, t)
+	return handle, sresp.Network, nil
+}
+
+// addTask stores the task and starts waiting for it to exit.
+func (p *BuiltinDriverPlugin) addTask(id string, t *builtinTask) {
+	p.tasksLock.Lock()
+	p.tasks[id] = t
+	p.tasksLock.Unlock()
+
+	go p.run(t)
+}
+
+// run records the result of the task once it exited.
+func (p *BuiltinDriverPlugin) run(t *builtinTask) {
+	result := &drivers.ExitResult{}
+	if res, ok := <-t.handle.WaitCh(); ok && res != nil {
+		result.ExitCode = res.ExitCode
+		result.Signal = res.Signal
+		result.OOMKilled = res.OOMKilled
+		result.Err = res.Err
+	}
+
+	p.tasksLock.Lock()
+	t.exitResult = result
+	t.completedAt = time.Now()
+	p.tasksLock.Unlock()
+
+	close(t.doneCh)
+}
+
+// getTask returns the task with the given ID.
+func (p *BuiltinDriverPlugin) getTask(id string) (*builtinTask, error) {
+	p.tasksLock.RLock()
+	defer p.tasksLock.RUnlock()
+
+	t, ok := p.tasks[id]
+	if !ok {
+		return nil, fmt.Errorf("task %q not found", id)
+	}
+	return t, nil
+}
+
+func (p *BuiltinDriverPlugin) WaitTask(ctx context.Context, id string) (<-chan *drivers.ExitResult, error) {
+	t, err := p.getTask(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *drivers.ExitResult, 1)
+	go func() {
+		defer close(ch)
+		select {
+		case <-ctx.Done():
+		case <-t.doneCh:
+			p.tasksLock.RLock()
+			result := *t.exitResult
+			p.tasksLock.RUnlock()
+			ch <- &result
+		}
+	}()
+	return ch, nil
+}
+
+func (p *BuiltinDriverPlugin) StopTask(id string, timeout time.Duration, signal string) error {
+	t, err := p.getTask(id)
+	if err != nil {
+		return err
+	}
+
+	// Built-in drivers read the kill timeout and signal from the task
+	task := t.task.Copy()
+	task.KillTimeout = timeout
+	task.KillSignal = signal
+	if err := t.handle.Update(task); err != nil {
+		return err
+	}
+
+	return t.handle.Kill()
+}
+
+func (p *BuiltinDriverPlugin) DestroyTask(id string, force bool) error {
+	t, err := p.getTask(id)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-t.doneCh:
+	default:
+		if !force {
+			return fmt.Errorf("task %q is still running", id)
+		}
+		if err := t.handle.Kill(); err != nil {
+			return err
+		}
+	}
+
+	if t.created != nil {
+		if err := t.driver.Cleanup(t.ctx, t.created); err != nil {
+			return err
+		}
+	}
+
+	p.tasksLock.Lock()
+	delete(p.tasks, id)
+	p.tasksLock.Unlock()
+	return nil
+}
+
+func (p *BuiltinDriverPlugin) InspectTask(id string) (*drivers.TaskStatus, error) {
+	t, err := p.getTask(id)
+	if err != nil {
+		return nil, err
+	}
+
+	p.tasksLock.RLock()
+	defer p.tasksLock.RUnlock()
+
+	status := &drivers.TaskStatus{
+		ID:        id,
+		Name:      t.task.Name,
+		State:     drivers.TaskStateRunning,
+		StartedAt: t.startedAt,
+	}
+	if t.exitResult != nil {
+		status.State = drivers.TaskStateExited
+		status.CompletedAt = t.completedAt
+		status.ExitResult = t.exitResult
+	}
+
+	return status, nil
+}
+
+func (p *BuiltinDriverPlugin) TaskStats(ctx context.Context, id string, interval time.Duration) (<-chan *cstructs.TaskResourceUsage, error) {
+	t, err := p.getTask(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *cstructs.TaskResourceUsage)
+	go func() {
+		defer close(ch)
+		for {
+			usage, err := t.handle.Stats()
+			if err != nil {
+				p.logger.Printf("[DEBUG] driver.%s: failed to collect stats of task %q: %v", p.name, id, err)
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- usage:
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (p *BuiltinDriverPlugin) SignalTask(id string, signal string) error {
+	t, err := p.getTask(id)
+	if err != nil {
+		return err
+	}
+
+	sig := signals.SignalLookup[signal]
+	if sig == nil {
+		return fmt.Errorf("Signal %s is not supported", signal)
+	}
+
+	return t.handle.Signal(sig)
+}
+
+func (p *BuiltinDriverPlugin) ExecTask(id string, cmd []string, timeout time.Duration) (*drivers.ExecTaskResult, error) {
+	t, err := p.getTask(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cmd) == 0 {
+		return nil, fmt.Errorf("command is required")
+	}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	out, code, err := t.handle.Exec(ctx, cmd[0], cmd[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &drivers.ExecTaskResult{
+		Stdout:     out,
+		ExitResult: &drivers.ExitResult{ExitCode: code},
+	}, nil
+}
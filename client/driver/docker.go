@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
@@ -766,8 +767,9 @@ func (d *DockerDriver) Validate(config map[string]interface{}) error {
 
 func (d *DockerDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: true,
-		Exec:        true,
+		SendSignals:   true,
+		Exec:          true,
+		ExecStreaming: true,
 	}
 }
 
@@ -1171,13 +1173,31 @@ func (d *DockerDriver) containerBinds(driverConfig *DockerDriverConfig, ctx *Exe
 		binds = append(binds, strings.Join(parts, ":"))
 	}
 
-	if selinuxLabel := d.config.Read(dockerSELinuxLabelConfigOption); selinuxLabel != "" {
+	selinuxLabel := d.config.Read(dockerSELinuxLabelConfigOption)
+	if selinuxLabel != "" {
 		// Apply SELinux Label to each volume
 		for i := range binds {
 			binds[i] = fmt.Sprintf("%s:%s", binds[i], selinuxLabel)
 		}
 	}
 
+	// Host volumes are resolved by the client and always allowed
+	for _, m := range ctx.TaskDir.HostVolumes {
+		bind := fmt.Sprintf("%s:%s", m.Source, m.Destination)
+
+		var opts []string
+		if m.ReadOnly {
+			opts = append(opts, "ro")
+		}
+		if selinuxLabel != "" {
+			opts = append(opts, selinuxLabel)
+		}
+		if len(opts) != 0 {
+			bind = fmt.Sprintf("%s:%s", bind, strings.Join(opts, ","))
+		}
+		binds = append(binds, bind)
+	}
+
 	return binds, nil
 }
 
@@ -1213,7 +1233,14 @@ func (d *DockerDriver) createContainerConfig(ctx *ExecContext, task *structs.Tas
 		config.WorkingDir = driverConfig.WorkDir
 	}
 
+	// With a memory limit above the reservation, the reservation becomes the
+	// soft limit
 	memLimit := int64(task.Resources.MemoryMB) * 1024 * 1024
+	var memReservation int64
+	if task.Resources.MemoryMaxMB > task.Resources.MemoryMB {
+		memReservation = memLimit
+		memLimit = int64(task.Resources.MemoryMaxMB) * 1024 * 1024
+	}
 
 	if len(driverConfig.Logging) == 0 {
 		if runtime.GOOS == "darwin" {
@@ -1228,7 +1255,8 @@ func (d *DockerDriver) createContainerConfig(ctx *ExecContext, task *structs.Tas
 
 	hostConfig := &docker.HostConfig{
 		// Convert MB to bytes. This is an absolute value.
-		Memory: memLimit,
+		Memory:            memLimit,
+		MemoryReservation: memReservation,
 		// Convert Mhz to shares. This is a relative value.
 		CPUShares: int64(task.Resources.CPU),
 
@@ -1890,6 +1918,115 @@ func (h *DockerHandle) Exec(ctx context.Context, cmd string, args []string) ([]b
 	return output.Bytes(), res.ExitCode, nil
 }
 
+func (h *DockerHandle) ExecStreaming(ctx context.Context, cmd []string, tty bool,
+	stream dstructs.ExecStream) (*dstructs.ExecStreamingExitResult, error) {
+	if len(cmd) == 0 {
+		return nil, fmt.Errorf("missing command")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	createExecOpts := docker.CreateExecOptions{
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          tty,
+		Cmd:          cmd,
+		Container:    h.containerID,
+		Context:      ctx,
+	}
+	exec, err := h.client.CreateExec(createExecOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Forward the input and terminal resizes until the caller goes away
+	stdinR, stdinW := io.Pipe()
+	defer stdinR.Close()
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				cancel()
+				return
+			}
+
+			if msg.TTYSize != nil && tty {
+				if err := client.ResizeExecTTY(exec.ID, msg.TTYSize.Height, msg.TTYSize.Width); err != nil {
+					h.logger.Printf("[DEBUG] driver.docker: failed to resize terminal of exec %s: %v", exec.ID, err)
+				}
+			}
+			if msg.Stdin != nil {
+				if len(msg.Stdin.Data) != 0 {
+					if _, err := stdinW.Write(msg.Stdin.Data); err != nil {
+						return
+					}
+				}
+				if msg.Stdin.Close {
+					stdinW.Close()
+				}
+			}
+		}
+	}()
+
+	var sendLock sync.Mutex
+	send := func(msg *dstructs.ExecStreamingResponseMsg) error {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		return stream.Send(msg)
+	}
+	stdout := &execStreamWriter{send: func(op *dstructs.ExecStreamingIOOperation) error {
+		return send(&dstructs.ExecStreamingResponseMsg{Stdout: op})
+	}}
+	stderr := &execStreamWriter{send: func(op *dstructs.ExecStreamingIOOperation) error {
+		return send(&dstructs.ExecStreamingResponseMsg{Stderr: op})
+	}}
+
+	startOpts := docker.StartExecOptions{
+		Detach:       false,
+		Tty:          tty,
+		RawTerminal:  tty,
+		InputStream:  stdinR,
+		OutputStream: stdout,
+		ErrorStream:  stderr,
+		Context:      ctx,
+	}
+	if err := client.StartExec(exec.ID, startOpts); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	stdout.close()
+	stderr.close()
+
+	res, err := client.InspectExec(exec.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dstructs.ExecStreamingExitResult{ExitCode: res.ExitCode}, nil
+}
+
+// execStreamWriter sends the output written to it as messages of an
+// interactive command
+type execStreamWriter struct {
+	send func(*dstructs.ExecStreamingIOOperation) error
+}
+
+func (w *execStreamWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+	if err := w.send(&dstructs.ExecStreamingIOOperation{Data: data}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *execStreamWriter) close() error {
+	return w.send(&dstructs.ExecStreamingIOOperation{Close: true})
+}
+
 func (h *DockerHandle) Signal(s os.Signal) error {
 	// Convert types
 	sysSig, ok := s.(syscall.Signal)
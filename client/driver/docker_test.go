@@ -2546,3 +2546,25 @@ func TestDockerDriver_CPUCFSPeriod(t *testing.T) {
 	assert.Nil(t, err, "Error inspecting container: %v", err)
 	assert.Equal(t, int64(1000000), container.HostConfig.CPUPeriod, "cpu_cfs_period option incorrectly set")
 }
+
+func TestDockerDriver_MemoryMax(t *testing.T) {
+	if !tu.IsTravis() {
+		t.Parallel()
+	}
+	if !testutil.DockerIsConnected(t) {
+		t.Skip("Docker not connected")
+	}
+
+	task, _, _ := dockerTask(t)
+	task.Resources.MemoryMaxMB = task.Resources.MemoryMB * 2
+
+	client, handle, cleanup := dockerSetup(t, task)
+	defer cleanup()
+
+	waitForExist(t, client, handle)
+
+	container, err := client.InspectContainer(handle.ContainerID())
+	assert.Nil(t, err, "Error inspecting container: %v", err)
+	assert.Equal(t, int64(task.Resources.MemoryMaxMB)*1024*1024, container.HostConfig.Memory, "memory limit incorrectly set")
+	assert.Equal(t, int64(task.Resources.MemoryMB)*1024*1024, container.HostConfig.MemoryReservation, "memory reservation incorrectly set")
+}
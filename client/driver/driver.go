@@ -42,6 +42,10 @@ func NewDriver(name string, ctx *DriverContext) (Driver, error) {
 	// Lookup the factory function
 	factory, ok := BuiltinDrivers[name]
 	if !ok {
+		// Fallback to the drivers implemented by driver plugins
+		if impl, ok := lookupPluginDriver(name); ok {
+			return NewPluginDriver(name, impl, ctx), nil
+		}
 		return nil, fmt.Errorf("unknown driver '%s'", name)
 	}
 
@@ -250,6 +254,11 @@ type DriverAbilities struct {
 	// Exec marks the driver as being able to execute arbitrary commands
 	// such as health checks. Used by the ScriptExecutor interface.
 	Exec bool
+
+	// ExecStreaming marks the driver as being able to run interactive
+	// commands in the task's context. Used by the StreamingExecutor
+	// interface.
+	ExecStreaming bool
 }
 
 // LogEventFn is a callback which allows Drivers to emit task events.
@@ -328,6 +337,14 @@ type ScriptExecutor interface {
 	Exec(ctx context.Context, cmd string, args []string) ([]byte, int, error)
 }
 
+// StreamingExecutor is implemented by the handles of drivers that can run
+// interactive commands in the task's context, with a terminal if tty is set.
+// The input and output of the command are exchanged over the stream until
+// the command exits or the context is done.
+type StreamingExecutor interface {
+	ExecStreaming(ctx context.Context, cmd []string, tty bool, stream dstructs.ExecStream) (*dstructs.ExecStreamingExitResult, error)
+}
+
 // ExecContext is a task's execution context
 type ExecContext struct {
 	// TaskDir contains information about the task directory structure.
@@ -84,6 +84,10 @@ const (
 
 	// VaultToken is the environment variable for passing the Vault token
 	VaultToken = "VAULT_TOKEN"
+
+	// WorkloadToken is the environment variable for passing the signed
+	// workload identity of the task
+	WorkloadToken = "NOMAD_TOKEN"
 )
 
 // The node values that can be interpreted.
@@ -218,6 +222,7 @@ type Builder struct {
 	groupName        string
 	vaultToken       string
 	injectVaultToken bool
+	workloadToken    string
 	jobName          string
 
 	// otherPorts for tasks in the same alloc
@@ -320,6 +325,11 @@ func (b *Builder) Build() *TaskEnv {
 		envMap[VaultToken] = b.vaultToken
 	}
 
+	// Build the workload identity
+	if b.workloadToken != "" {
+		envMap[WorkloadToken] = b.workloadToken
+	}
+
 	// Copy task meta
 	for k, v := range b.taskMeta {
 		envMap[k] = v
@@ -567,6 +577,14 @@ func (b *Builder) SetVaultToken(token string, inject bool) *Builder {
 	return b
 }
 
+// SetWorkloadToken sets the signed workload identity of the task
+func (b *Builder) SetWorkloadToken(token string) *Builder {
+	b.mu.Lock()
+	b.workloadToken = token
+	b.mu.Unlock()
+	return b
+}
+
 // addPort keys and values for other tasks to an env var map
 func addPort(m map[string]string, taskName, ip, portLabel string, port int) {
 	key := fmt.Sprintf("%s%s_%s", AddrPrefix, taskName, portLabel)
@@ -390,3 +390,18 @@ func TestEnvironment_InterpolateEmptyOptionalMeta(t *testing.T) {
 	require.Equal("metaopt1val", env.ReplaceEnv("${NOMAD_META_metaopt1}"))
 	require.Empty(env.ReplaceEnv("${NOMAD_META_metaopt2}"))
 }
+
+func TestEnvironment_WorkloadToken(t *testing.T) {
+	n := mock.Node()
+	a := mock.Alloc()
+	env := NewBuilder(n, a, a.Job.TaskGroups[0].Tasks[0], "global")
+
+	if act := env.Build().All(); act[WorkloadToken] != "" {
+		t.Fatalf("Unexpected environment variables: %s=%q", WorkloadToken, act[WorkloadToken])
+	}
+
+	act := env.SetWorkloadToken("a.b.c").Build().All()
+	if act[WorkloadToken] != "a.b.c" {
+		t.Fatalf("Expected %s=%q; got %q", WorkloadToken, "a.b.c", act[WorkloadToken])
+	}
+}
@@ -31,8 +31,9 @@ type ExecDriver struct {
 }
 
 type ExecDriverConfig struct {
-	Command string   `mapstructure:"command"`
-	Args    []string `mapstructure:"args"`
+	Command   string   `mapstructure:"command"`
+	Args      []string `mapstructure:"args"`
+	PidsLimit int64    `mapstructure:"pids_limit"`
 }
 
 // execHandle is returned from Start/Open as a handle to the PID
@@ -67,6 +68,9 @@ func (d *ExecDriver) Validate(config map[string]interface{}) error {
 			"args": {
 				Type: fields.TypeArray,
 			},
+			"pids_limit": {
+				Type: fields.TypeInt,
+			},
 		},
 	}
 
@@ -74,13 +78,18 @@ func (d *ExecDriver) Validate(config map[string]interface{}) error {
 		return err
 	}
 
+	if fd.Get("pids_limit").(int) < 0 {
+		return fmt.Errorf("pids_limit must be greater than or equal to 0")
+	}
+
 	return nil
 }
 
 func (d *ExecDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: true,
-		Exec:        true,
+		SendSignals:   true,
+		Exec:          true,
+		ExecStreaming: true,
 	}
 }
 
@@ -141,6 +150,7 @@ func (d *ExecDriver) Start(ctx *ExecContext, task *structs.Task) (*StartResponse
 		FSIsolation:    true,
 		ResourceLimits: true,
 		User:           getExecutorUser(task),
+		PidsLimit:      driverConfig.PidsLimit,
 	}
 
 	ps, err := exec.LaunchCmd(execCmd)
@@ -266,6 +276,11 @@ func (h *execHandle) Exec(ctx context.Context, cmd string, args []string) ([]byt
 	return h.executor.Exec(deadline, cmd, args)
 }
 
+func (h *execHandle) ExecStreaming(ctx context.Context, cmd []string, tty bool,
+	stream dstructs.ExecStream) (*dstructs.ExecStreamingExitResult, error) {
+	return h.executor.ExecStreaming(ctx, cmd, tty, stream)
+}
+
 func (h *execHandle) Signal(s os.Signal) error {
 	return h.executor.Signal(s)
 }
@@ -319,7 +334,12 @@ func (h *execHandle) run() {
 	}
 	h.pluginClient.Kill()
 
+	// Report tasks killed for running out of memory
+	if ps.OOMKilled && werr == nil {
+		werr = fmt.Errorf("OOM Killed")
+	}
+
 	// Send the results
-	h.waitCh <- dstructs.NewWaitResult(ps.ExitCode, ps.Signal, werr)
+	h.waitCh <- &dstructs.WaitResult{ExitCode: ps.ExitCode, Signal: ps.Signal, OOMKilled: ps.OOMKilled, Err: werr}
 	close(h.waitCh)
 }
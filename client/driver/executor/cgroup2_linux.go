@@ -0,0 +1,408 @@
+package executor
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	cgroupConfig "github.com/opencontainers/runc/libcontainer/configs"
+)
+
+var (
+	// cgroup2Controllers are the controllers enabled for the task cgroups
+	cgroup2Controllers = []string{"cpu", "io", "memory", "pids"}
+
+	// cgroup2DestroyTimeout is how long killed processes are waited on to
+	// leave the cgroup before it is removed
+	cgroup2DestroyTimeout = 5 * time.Second
+)
+
+// cgroup2Manager manages the cgroup of a task in the unified cgroup v2
+// hierarchy.
+type cgroup2Manager struct {
+	// root is the mount point of the unified hierarchy
+	root string
+
+	// path is the absolute path of the cgroup
+	path string
+}
+
+func newCgroup2Manager(root, path string) *cgroup2Manager {
+	return &cgroup2Manager{root: root, path: path}
+}
+
+// cgroup2Stats is the resource usage of a cgroup
+type cgroup2Stats struct {
+	// Memory usage in bytes. Peak and Swap are zero if the kernel doesn't
+	// report them.
+	Usage  uint64
+	Peak   uint64
+	Anon   uint64
+	File   uint64
+	Kernel uint64
+	Swap   uint64
+
+	// CPU usage in nanoseconds
+	CPUUsage  uint64
+	CPUUser   uint64
+	CPUSystem uint64
+
+	// Throttling of the cgroup, the time being in nanoseconds
+	ThrottledPeriods uint64
+	ThrottledTime    uint64
+}
+
+// create creates the cgroup, enabling the controllers in its ancestors so
+// that limits can be applied.
+func (m *cgroup2Manager) create() error {
+	rel, err := filepath.Rel(m.root, m.path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("cgroup %q is not in the hierarchy mounted at %q", m.path, m.root)
+	}
+
+	dir := m.root
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		if err := enableCgroup2Controllers(dir); err != nil {
+			return err
+		}
+		dir = filepath.Join(dir, name)
+		if err := os.Mkdir(dir, 0755); err != nil && !os.IsExist(err) {
+			return fmt.Errorf("failed to create cgroup %q: %v", dir, err)
+		}
+	}
+	return nil
+}
+
+// enableCgroup2Controllers enables the controllers available in the cgroup
+// for its children.
+func enableCgroup2Controllers(dir string) error {
+	available, err := readCgroup2Fields(filepath.Join(dir, "cgroup.controllers"))
+	if err != nil {
+		return err
+	}
+	enabled, err := readCgroup2Fields(filepath.Join(dir, "cgroup.subtree_control"))
+	if err != nil {
+		return err
+	}
+
+	for _, c := range cgroup2Controllers {
+		if _, ok := available[c]; !ok {
+			continue
+		}
+		if _, ok := enabled[c]; ok {
+			continue
+		}
+		if err := writeCgroup2File(dir, "cgroup.subtree_control", "+"+c); err != nil {
+			return fmt.Errorf("failed to enable %s controller: %v", c, err)
+		}
+	}
+	return nil
+}
+
+// set applies the resource limits to the cgroup.
+func (m *cgroup2Manager) set(r *cgroupConfig.Resources) error {
+	if r == nil {
+		return nil
+	}
+
+	if r.CpuShares != 0 {
+		if err := writeCgroup2File(m.path, "cpu.weight", strconv.FormatUint(cpuSharesToWeight(uint64(r.CpuShares)), 10)); err != nil {
+			return err
+		}
+	}
+
+	if r.Memory != 0 {
+		if err := writeCgroup2File(m.path, "memory.max", strconv.FormatInt(r.Memory, 10)); err != nil {
+			return err
+		}
+
+		// Disable swap, the equivalent of no swappiness. The file only
+		// exists if the kernel accounts swap.
+		if r.MemorySwappiness != nil && *r.MemorySwappiness == 0 && m.exists("memory.swap.max") {
+			if err := writeCgroup2File(m.path, "memory.swap.max", "0"); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Protect the reserved memory from reclaim, the equivalent of the soft
+	// limit of cgroups v1
+	if r.MemoryReservation != 0 {
+		if err := writeCgroup2File(m.path, "memory.low", strconv.FormatInt(r.MemoryReservation, 10)); err != nil {
+			return err
+		}
+	}
+
+	if r.PidsLimit > 0 {
+		if err := writeCgroup2File(m.path, "pids.max", strconv.FormatInt(r.PidsLimit, 10)); err != nil {
+			return err
+		}
+	}
+
+	if r.BlkioWeight != 0 && m.exists("io.weight") {
+		weight := blkioWeightToIOWeight(uint64(r.BlkioWeight))
+		if err := writeCgroup2File(m.path, "io.weight", fmt.Sprintf("default %d", weight)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply moves the process into the cgroup.
+func (m *cgroup2Manager) apply(pid int) error {
+	return writeCgroup2File(m.path, "cgroup.procs", strconv.Itoa(pid))
+}
+
+// pids returns the processes in the cgroup.
+func (m *cgroup2Manager) pids() ([]int, error) {
+	f, err := os.Open(filepath.Join(m.path, "cgroup.procs"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var pids []int
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		pid, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pid %q: %v", line, err)
+		}
+		pids = append(pids, pid)
+	}
+	return pids, s.Err()
+}
+
+// stats returns the resource usage of the cgroup.
+func (m *cgroup2Manager) stats() (*cgroup2Stats, error) {
+	var stats cgroup2Stats
+	var err error
+
+	if stats.Usage, err = readCgroup2Uint(filepath.Join(m.path, "memory.current")); err != nil {
+		return nil, err
+	}
+
+	// memory.peak and memory.swap.current are only available on some
+	// kernels
+	if m.exists("memory.peak") {
+		if stats.Peak, err = readCgroup2Uint(filepath.Join(m.path, "memory.peak")); err != nil {
+			return nil, err
+		}
+	}
+	if m.exists("memory.swap.current") {
+		if stats.Swap, err = readCgroup2Uint(filepath.Join(m.path, "memory.swap.current")); err != nil {
+			return nil, err
+		}
+	}
+
+	memory, err := readCgroup2KeyValues(filepath.Join(m.path, "memory.stat"))
+	if err != nil {
+		return nil, err
+	}
+	stats.Anon = memory["anon"]
+	stats.File = memory["file"]
+	if kernel, ok := memory["kernel"]; ok {
+		stats.Kernel = kernel
+	} else {
+		stats.Kernel = memory["kernel_stack"] + memory["slab"] + memory["sock"] + memory["percpu"]
+	}
+
+	cpu, err := readCgroup2KeyValues(filepath.Join(m.path, "cpu.stat"))
+	if err != nil {
+		return nil, err
+	}
+	stats.CPUUsage = cpu["usage_usec"] * 1000
+	stats.CPUUser = cpu["user_usec"] * 1000
+	stats.CPUSystem = cpu["system_usec"] * 1000
+	stats.ThrottledPeriods = cpu["nr_throttled"]
+	stats.ThrottledTime = cpu["throttled_usec"] * 1000
+	return &stats, nil
+}
+
+// oomKilled returns whether a process of the cgroup was killed for running
+// out of memory.
+func (m *cgroup2Manager) oomKilled() bool {
+	events, err := readCgroup2KeyValues(filepath.Join(m.path, "memory.events"))
+	if err != nil {
+		return false
+	}
+	return events["oom_kill"] > 0
+}
+
+// kill kills all the processes in the cgroup.
+func (m *cgroup2Manager) kill() error {
+	// Kernels since 5.14 kill the whole group atomically
+	if m.exists("cgroup.kill") {
+		return writeCgroup2File(m.path, "cgroup.kill", "1")
+	}
+
+	// Freeze the cgroup so that it can not continue to fork/exec.
+	frozen := m.exists("cgroup.freeze")
+	if frozen {
+		if err := writeCgroup2File(m.path, "cgroup.freeze", "1"); err != nil {
+			return fmt.Errorf("failed to freeze cgroup: %v", err)
+		}
+	}
+
+	mErrs := new(multierror.Error)
+	pids, err := m.pids()
+	if err != nil {
+		multierror.Append(mErrs, fmt.Errorf("error getting pids: %v", err))
+	}
+	for _, pid := range pids {
+		if err := syscall.Kill(pid, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+			multierror.Append(mErrs, fmt.Errorf("error killing process %v: %v", pid, err))
+		}
+	}
+
+	// Unfreeze the cgroup so the killed processes can exit.
+	if frozen {
+		if err := writeCgroup2File(m.path, "cgroup.freeze", "0"); err != nil {
+			multierror.Append(mErrs, fmt.Errorf("failed to unfreeze cgroup: %v", err))
+		}
+	}
+	return mErrs.ErrorOrNil()
+}
+
+// destroy removes the cgroup once its processes exited.
+func (m *cgroup2Manager) destroy() error {
+	deadline := time.Now().Add(cgroup2DestroyTimeout)
+	for {
+		err := os.Remove(m.path)
+		if err == nil || os.IsNotExist(err) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("failed to delete the cgroup directory: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// exists returns whether the cgroup has the interface file.
+func (m *cgroup2Manager) exists(file string) bool {
+	_, err := os.Stat(filepath.Join(m.path, file))
+	return err == nil
+}
+
+// DestroyCgroup2 kills all processes in the cgroup and removes the cgroup
+// from the host. This function is idempotent.
+func DestroyCgroup2(root, path string, executorPid int) error {
+	if path == "" {
+		return fmt.Errorf("Can't destroy: cgroup path empty")
+	}
+
+	m := newCgroup2Manager(root, path)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	// Move the executor into the root cgroup so that the task specific
+	// cgroup can be destroyed. It is the only cgroup besides leaves
+	// processes can be in.
+	err := writeCgroup2File(root, "cgroup.procs", strconv.Itoa(executorPid))
+	if err != nil && !strings.Contains(err.Error(), "no such process") {
+		return fmt.Errorf("failed to remove executor pid %d: %v", executorPid, err)
+	}
+
+	mErrs := new(multierror.Error)
+	if err := m.kill(); err != nil {
+		multierror.Append(mErrs, err)
+	}
+	if err := m.destroy(); err != nil {
+		multierror.Append(mErrs, err)
+	}
+	return mErrs.ErrorOrNil()
+}
+
+// cpuSharesToWeight converts cgroup v1 cpu shares, in [2, 262144], to a
+// cgroup v2 cpu weight, in [1, 10000].
+func cpuSharesToWeight(shares uint64) uint64 {
+	if shares < 2 {
+		shares = 2
+	} else if shares > 262144 {
+		shares = 262144
+	}
+	return 1 + ((shares-2)*9999)/262142
+}
+
+// blkioWeightToIOWeight converts a cgroup v1 blkio weight, in [10, 1000], to
+// a cgroup v2 io weight, in [1, 10000].
+func blkioWeightToIOWeight(weight uint64) uint64 {
+	if weight < 10 {
+		weight = 10
+	} else if weight > 1000 {
+		weight = 1000
+	}
+	return 1 + (weight-10)*9999/990
+}
+
+// writeCgroup2File writes the value to the interface file of the cgroup.
+func writeCgroup2File(dir, file, value string) error {
+	if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(value), 0); err != nil {
+		return fmt.Errorf("failed to write %q to %q: %v", value, filepath.Join(dir, file), err)
+	}
+	return nil
+}
+
+// readCgroup2Uint reads an interface file holding a single value. The value
+// max is returned as zero.
+func readCgroup2Uint(path string) (uint64, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	v := strings.TrimSpace(string(b))
+	if v == "max" {
+		return 0, nil
+	}
+	return strconv.ParseUint(v, 10, 64)
+}
+
+// readCgroup2Fields reads an interface file holding a space separated list.
+func readCgroup2Fields(path string) (map[string]struct{}, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]struct{})
+	for _, f := range strings.Fields(string(b)) {
+		fields[f] = struct{}{}
+	}
+	return fields, nil
+}
+
+// readCgroup2KeyValues reads a flat keyed interface file such as cpu.stat.
+func readCgroup2KeyValues(path string) (map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]uint64)
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		parts := strings.Fields(s.Text())
+		if len(parts) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		values[parts[0]] = v
+	}
+	return values, s.Err()
+}
@@ -0,0 +1,141 @@
+package executor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cgroupConfig "github.com/opencontainers/runc/libcontainer/configs"
+	"github.com/stretchr/testify/require"
+)
+
+// testCgroup2 returns a manager of a cgroup in a fake unified hierarchy with
+// the given interface files. The caller is responsible for removing the root.
+func testCgroup2(t *testing.T, files map[string]string) *cgroup2Manager {
+	root, err := ioutil.TempDir("", "cgroup2")
+	require.NoError(t, err)
+
+	path := filepath.Join(root, "nomad", "task")
+	require.NoError(t, os.MkdirAll(path, 0755))
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(path, name), []byte(content), 0644))
+	}
+	return newCgroup2Manager(root, path)
+}
+
+func readCgroup2File(t *testing.T, m *cgroup2Manager, file string) string {
+	b, err := ioutil.ReadFile(filepath.Join(m.path, file))
+	require.NoError(t, err)
+	return strings.TrimSpace(string(b))
+}
+
+func TestCgroup2Manager_Create(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	root, err := ioutil.TempDir("", "cgroup2")
+	require.NoError(err)
+	defer os.RemoveAll(root)
+
+	// The fake hierarchy doesn't create the interface files of new cgroups
+	// so the parent is created ahead
+	for _, dir := range []string{root, filepath.Join(root, "nomad")} {
+		require.NoError(os.MkdirAll(dir, 0755))
+		require.NoError(ioutil.WriteFile(filepath.Join(dir, "cgroup.controllers"), []byte("cpuset cpu io memory pids\n"), 0644))
+		require.NoError(ioutil.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte("cpu io memory pids\n"), 0644))
+	}
+
+	m := newCgroup2Manager(root, filepath.Join(root, "nomad", "task"))
+	require.NoError(m.create())
+
+	fi, err := os.Stat(m.path)
+	require.NoError(err)
+	require.True(fi.IsDir())
+
+	// Cgroups outside of the hierarchy are rejected
+	m = newCgroup2Manager(root, "/tmp/foo")
+	require.Error(m.create())
+}
+
+func TestCgroup2Manager_Set(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	m := testCgroup2(t, map[string]string{
+		"cpu.weight":      "100",
+		"memory.max":      "max",
+		"memory.swap.max": "max",
+		"memory.low":      "0",
+		"pids.max":        "max",
+		"io.weight":       "default 100",
+	})
+	defer os.RemoveAll(m.root)
+
+	var swappiness int64
+	require.NoError(m.set(&cgroupConfig.Resources{
+		CpuShares:         1024,
+		Memory:            256 * 1024 * 1024,
+		MemoryReservation: 128 * 1024 * 1024,
+		MemorySwappiness:  &swappiness,
+		PidsLimit:         100,
+		BlkioWeight:       500,
+	}))
+
+	require.Equal("39", readCgroup2File(t, m, "cpu.weight"))
+	require.Equal("268435456", readCgroup2File(t, m, "memory.max"))
+	require.Equal("0", readCgroup2File(t, m, "memory.swap.max"))
+	require.Equal("134217728", readCgroup2File(t, m, "memory.low"))
+	require.Equal("100", readCgroup2File(t, m, "pids.max"))
+	require.Equal("default 4950", readCgroup2File(t, m, "io.weight"))
+}
+
+func TestCgroup2Manager_Stats(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	m := testCgroup2(t, map[string]string{
+		"memory.current":      "4096\n",
+		"memory.swap.current": "1024\n",
+		"memory.stat":         "anon 2048\nfile 1024\nkernel_stack 128\nslab 256\nsock 0\npercpu 64\n",
+		"cpu.stat":            "usage_usec 3000\nuser_usec 2000\nsystem_usec 1000\nnr_periods 10\nnr_throttled 2\nthrottled_usec 500\n",
+		"memory.events":       "low 0\nhigh 0\nmax 3\noom 1\noom_kill 0\n",
+		"cgroup.procs":        "12\n34\n",
+	})
+	defer os.RemoveAll(m.root)
+
+	stats, err := m.stats()
+	require.NoError(err)
+	require.Equal(&cgroup2Stats{
+		Usage:            4096,
+		Anon:             2048,
+		File:             1024,
+		Kernel:           448,
+		Swap:             1024,
+		CPUUsage:         3000000,
+		CPUUser:          2000000,
+		CPUSystem:        1000000,
+		ThrottledPeriods: 2,
+		ThrottledTime:    500000,
+	}, stats)
+
+	pids, err := m.pids()
+	require.NoError(err)
+	require.Equal([]int{12, 34}, pids)
+
+	require.False(m.oomKilled())
+	require.NoError(ioutil.WriteFile(filepath.Join(m.path, "memory.events"), []byte("oom 1\noom_kill 1\n"), 0644))
+	require.True(m.oomKilled())
+}
+
+func TestCgroup2_Conversions(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	require.EqualValues(1, cpuSharesToWeight(2))
+	require.EqualValues(39, cpuSharesToWeight(1024))
+	require.EqualValues(10000, cpuSharesToWeight(262144))
+	require.EqualValues(1, blkioWeightToIOWeight(10))
+	require.EqualValues(10000, blkioWeightToIOWeight(1000))
+}
@@ -68,6 +68,7 @@ type Executor interface {
 	Stats() (*cstructs.TaskResourceUsage, error)
 	Signal(s os.Signal) error
 	Exec(deadline time.Time, cmd string, args []string) ([]byte, int, error)
+	ExecStreaming(ctx context.Context, cmd []string, tty bool, stream dstructs.ExecStream) (*dstructs.ExecStreamingExitResult, error)
 }
 
 // ExecutorContext holds context to configure the command user
@@ -123,6 +124,10 @@ type ExecCommand struct {
 	// doesn't enforce resource limits. To enforce limits, set ResourceLimits.
 	// Using the cgroup does allow more precise cleanup of processes.
 	BasicProcessCgroup bool
+
+	// PidsLimit is the maximum number of processes the task can run when
+	// resource limits are enforced. Zero means unlimited.
+	PidsLimit int64
 }
 
 // ProcessState holds information about the state of a user process.
@@ -130,6 +135,7 @@ type ProcessState struct {
 	Pid             int
 	ExitCode        int
 	Signal          int
+	OOMKilled       bool
 	IsolationConfig *dstructs.IsolationConfig
 	Time            time.Time
 }
@@ -174,6 +180,7 @@ type UniversalExecutor struct {
 
 	lre         *logRotatorWrapper
 	lro         *logRotatorWrapper
+	logShipper  *logging.LogShipper
 	rotatorLock sync.Mutex
 
 	syslogServer *logging.SyslogServer
@@ -356,6 +363,14 @@ func (e *UniversalExecutor) configureLoggers() error {
 	e.rotatorLock.Lock()
 	defer e.rotatorLock.Unlock()
 
+	if e.logShipper == nil {
+		shipper, err := logging.NewLogShipper(e.logger, e.sinkContext(), e.ctx.Task.LogConfig.Sinks)
+		if err != nil {
+			return fmt.Errorf("error creating log sinks for %q: %v", e.ctx.Task.Name, err)
+		}
+		e.logShipper = shipper
+	}
+
 	logFileSize := int64(e.ctx.Task.LogConfig.MaxFileSizeMB * 1024 * 1024)
 	if e.lro == nil {
 		lro, err := logging.NewFileRotator(e.ctx.LogDir, fmt.Sprintf("%v.stdout", e.ctx.Task.Name),
@@ -363,8 +378,10 @@ func (e *UniversalExecutor) configureLoggers() error {
 		if err != nil {
 			return fmt.Errorf("error creating new stdout log file for %q: %v", e.ctx.Task.Name, err)
 		}
+		lro.RotateInterval = e.ctx.Task.LogConfig.RotateInterval
+		lro.Compress = e.ctx.Task.LogConfig.Compress
 
-		r, err := newLogRotatorWrapper(e.logger, lro)
+		r, err := newLogRotatorWrapper(e.logger, lro, e.logShipper.Writer(logging.StreamStdout))
 		if err != nil {
 			return err
 		}
@@ -377,8 +394,10 @@ func (e *UniversalExecutor) configureLoggers() error {
 		if err != nil {
 			return fmt.Errorf("error creating new stderr log file for %q: %v", e.ctx.Task.Name, err)
 		}
+		lre.RotateInterval = e.ctx.Task.LogConfig.RotateInterval
+		lre.Compress = e.ctx.Task.LogConfig.Compress
 
-		r, err := newLogRotatorWrapper(e.logger, lre)
+		r, err := newLogRotatorWrapper(e.logger, lre, e.logShipper.Writer(logging.StreamStderr))
 		if err != nil {
 			return err
 		}
@@ -387,6 +406,17 @@ func (e *UniversalExecutor) configureLoggers() error {
 	return nil
 }
 
+// sinkContext returns the identity of the task for the log sinks
+func (e *UniversalExecutor) sinkContext() *logging.SinkContext {
+	ctx := &logging.SinkContext{TaskName: e.ctx.Task.Name}
+	if e.ctx.TaskEnv != nil {
+		ctx.AllocID = e.ctx.TaskEnv.EnvMap[env.AllocID]
+		ctx.JobName = e.ctx.TaskEnv.EnvMap[env.JobName]
+		ctx.TaskGroup = e.ctx.TaskEnv.EnvMap[env.GroupName]
+	}
+	return ctx
+}
+
 // Wait waits until a process has exited and returns it's exitcode and errors
 func (e *UniversalExecutor) Wait() (*ProcessState, error) {
 	<-e.processExited
@@ -402,12 +432,20 @@ func (e *UniversalExecutor) UpdateLogConfig(logConfig *structs.LogConfig) error
 	}
 	e.lro.rotatorWriter.MaxFiles = logConfig.MaxFiles
 	e.lro.rotatorWriter.FileSize = int64(logConfig.MaxFileSizeMB * 1024 * 1024)
+	e.lro.rotatorWriter.RotateInterval = logConfig.RotateInterval
+	e.lro.rotatorWriter.Compress = logConfig.Compress
 
 	if e.lre == nil {
 		return fmt.Errorf("log rotator for stderr doesn't exist")
 	}
 	e.lre.rotatorWriter.MaxFiles = logConfig.MaxFiles
 	e.lre.rotatorWriter.FileSize = int64(logConfig.MaxFileSizeMB * 1024 * 1024)
+	e.lre.rotatorWriter.RotateInterval = logConfig.RotateInterval
+	e.lre.rotatorWriter.Compress = logConfig.Compress
+
+	if e.logShipper != nil {
+		return e.logShipper.UpdateSinks(logConfig.Sinks)
+	}
 	return nil
 }
 
@@ -420,11 +458,19 @@ func (e *UniversalExecutor) UpdateTask(task *structs.Task) error {
 		fileSize := int64(task.LogConfig.MaxFileSizeMB * 1024 * 1024)
 		e.lro.rotatorWriter.MaxFiles = task.LogConfig.MaxFiles
 		e.lro.rotatorWriter.FileSize = fileSize
+		e.lro.rotatorWriter.RotateInterval = task.LogConfig.RotateInterval
+		e.lro.rotatorWriter.Compress = task.LogConfig.Compress
 		e.lre.rotatorWriter.MaxFiles = task.LogConfig.MaxFiles
 		e.lre.rotatorWriter.FileSize = fileSize
+		e.lre.rotatorWriter.RotateInterval = task.LogConfig.RotateInterval
+		e.lre.rotatorWriter.Compress = task.LogConfig.Compress
+	}
+	var err error
+	if e.logShipper != nil {
+		err = e.logShipper.UpdateSinks(task.LogConfig.Sinks)
 	}
 	e.rotatorLock.Unlock()
-	return nil
+	return err
 }
 
 func (e *UniversalExecutor) wait() {
@@ -461,7 +507,14 @@ func (e *UniversalExecutor) wait() {
 		e.logger.Printf("[WARN] executor: unexpected Cmd.Wait() error type: %v", err)
 	}
 
-	e.exitState = &ProcessState{Pid: 0, ExitCode: exitCode, Signal: signal, IsolationConfig: ic, Time: time.Now()}
+	e.exitState = &ProcessState{
+		Pid:             0,
+		ExitCode:        exitCode,
+		Signal:          signal,
+		OOMKilled:       e.resConCtx.oomKilled(),
+		IsolationConfig: ic,
+		Time:            time.Now(),
+	}
 }
 
 var (
@@ -496,6 +549,10 @@ func (e *UniversalExecutor) Exit() error {
 		e.lro.Close()
 	}
 
+	if e.logShipper != nil {
+		e.logShipper.Close()
+	}
+
 	// If the executor did not launch a process, return.
 	if e.command == nil {
 		return nil
@@ -824,7 +881,7 @@ func (e *UniversalExecutor) LaunchSyslogServer() (*SyslogServerState, error) {
 
 	e.syslogServer = logging.NewSyslogServer(l, e.syslogChan, e.logger)
 	go e.syslogServer.Start()
-	go e.collectLogs(e.lre.rotatorWriter, e.lro.rotatorWriter)
+	go e.collectLogs(e.lre.writer, e.lro.writer)
 	syslogAddr := fmt.Sprintf("%s://%s", l.Addr().Network(), l.Addr().String())
 	return &SyslogServerState{Addr: syslogAddr}, nil
 }
@@ -845,18 +902,20 @@ func (e *UniversalExecutor) collectLogs(we io.Writer, wo io.Writer) {
 
 // logRotatorWrapper wraps our log rotator and exposes a pipe that can feed the
 // log rotator data. The processOutWriter should be attached to the process and
-// data will be copied from the reader to the rotator.
+// data will be copied from the reader to the rotator and the log sinks.
 type logRotatorWrapper struct {
 	processOutWriter  *os.File
 	processOutReader  *os.File
 	rotatorWriter     *logging.FileRotator
+	writer            io.Writer
 	hasFinishedCopied chan struct{}
 	logger            *log.Logger
 }
 
-// newLogRotatorWrapper takes a rotator and returns a wrapper that has the
-// processOutWriter to attach to the processes stdout or stderr.
-func newLogRotatorWrapper(logger *log.Logger, rotator *logging.FileRotator) (*logRotatorWrapper, error) {
+// newLogRotatorWrapper takes a rotator and a writer shipping to the log sinks
+// and returns a wrapper that has the processOutWriter to attach to the
+// processes stdout or stderr.
+func newLogRotatorWrapper(logger *log.Logger, rotator *logging.FileRotator, sinkWriter io.Writer) (*logRotatorWrapper, error) {
 	r, w, err := os.Pipe()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create os.Pipe for extracting logs: %v", err)
@@ -866,6 +925,7 @@ func newLogRotatorWrapper(logger *log.Logger, rotator *logging.FileRotator) (*lo
 		processOutWriter:  w,
 		processOutReader:  r,
 		rotatorWriter:     rotator,
+		writer:            io.MultiWriter(rotator, sinkWriter),
 		hasFinishedCopied: make(chan struct{}),
 		logger:            logger,
 	}
@@ -878,7 +938,7 @@ func newLogRotatorWrapper(logger *log.Logger, rotator *logging.FileRotator) (*lo
 func (l *logRotatorWrapper) start() {
 	go func() {
 		defer close(l.hasFinishedCopied)
-		_, err := io.Copy(l.rotatorWriter, l.processOutReader)
+		_, err := io.Copy(l.writer, l.processOutReader)
 		if err != nil {
 			// Close reader to propagate io error across pipe.
 			// Note that this may block until the process exits on
@@ -25,6 +25,10 @@ func (e *UniversalExecutor) applyLimits(pid int) error {
 	return nil
 }
 
+func (e *UniversalExecutor) enterResourceContainer() error {
+	return nil
+}
+
 func (e *UniversalExecutor) configureIsolation() error {
 	return nil
 }
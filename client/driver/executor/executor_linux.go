@@ -15,6 +15,7 @@ import (
 	cgroupFs "github.com/opencontainers/runc/libcontainer/cgroups/fs"
 	cgroupConfig "github.com/opencontainers/runc/libcontainer/configs"
 
+	"github.com/hashicorp/nomad/client/lib/cgutil"
 	"github.com/hashicorp/nomad/client/stats"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -25,6 +26,9 @@ var (
 	// The statistics the executor exposes when using cgroups
 	ExecutorCgroupMeasuredMemStats = []string{"RSS", "Cache", "Swap", "Max Usage", "Kernel Usage", "Kernel Max Usage"}
 	ExecutorCgroupMeasuredCpuStats = []string{"System Mode", "User Mode", "Throttled Periods", "Throttled Time", "Percent"}
+
+	// The memory statistics the executor exposes when using cgroup v2
+	ExecutorCgroupV2MeasuredMemStats = []string{"RSS", "Cache", "Swap", "Usage", "Max Usage", "Kernel Usage"}
 )
 
 // configureIsolation configures chroot and creates cgroups
@@ -39,6 +43,13 @@ func (e *UniversalExecutor) configureIsolation() error {
 		if err := e.configureCgroups(e.ctx.Task.Resources); err != nil {
 			return fmt.Errorf("error creating cgroups: %v", err)
 		}
+
+		// The resources are applied to a single cgroup on hosts that only
+		// mount the unified hierarchy
+		if cgutil.IsCgroup2UnifiedMode() {
+			path := filepath.Join(cgutil.CgroupRoot, e.resConCtx.groups.Path)
+			e.resConCtx.cgroup2 = newCgroup2Manager(cgutil.CgroupRoot, path)
+		}
 	}
 	return nil
 }
@@ -49,6 +60,10 @@ func (e *UniversalExecutor) applyLimits(pid int) error {
 		return nil
 	}
 
+	if e.resConCtx.cgroup2 != nil {
+		return e.applyLimitsCgroup2(pid)
+	}
+
 	// Entering the process in the cgroup
 	manager := getCgroupManager(e.resConCtx.groups, nil)
 	if err := manager.Apply(pid); err != nil {
@@ -120,6 +135,55 @@ func (e *UniversalExecutor) applyLimits(pid int) error {
 	return nil
 }
 
+// enterResourceContainer puts the executor back in the resource container of
+// the task so that the processes it starts for the task, such as interactive
+// commands, are isolated and limited like the task.
+func (e *UniversalExecutor) enterResourceContainer() error {
+	if !(e.command.ResourceLimits || e.command.BasicProcessCgroup) {
+		return nil
+	}
+
+	e.resConCtx.cgLock.Lock()
+	defer e.resConCtx.cgLock.Unlock()
+
+	pid := os.Getpid()
+	if e.resConCtx.cgroup2 != nil {
+		return e.resConCtx.cgroup2.apply(pid)
+	}
+
+	manager := getCgroupManager(e.resConCtx.groups, e.resConCtx.cgPaths)
+	return manager.Apply(pid)
+}
+
+// applyLimitsCgroup2 creates the cgroup of the task in the unified hierarchy,
+// applies the resource limits to it and puts the process in it. Processes in
+// a cgroup without limits are not constrained, so unlike with cgroup v1 the
+// process stays in the cgroup when resource limits are not enforced.
+func (e *UniversalExecutor) applyLimitsCgroup2(pid int) error {
+	cg := e.resConCtx.cgroup2
+	if err := cg.create(); err != nil {
+		e.logger.Printf("[ERR] executor: error creating cgroup: %v", err)
+		return err
+	}
+
+	if err := cg.set(e.resConCtx.groups.Resources); err != nil {
+		e.logger.Printf("[ERR] executor: error setting cgroup config: %v", err)
+		if er := DestroyCgroup2(cg.root, cg.path, os.Getpid()); er != nil {
+			e.logger.Printf("[ERR] executor: error destroying cgroup: %v", er)
+		}
+		return err
+	}
+
+	if err := cg.apply(pid); err != nil {
+		e.logger.Printf("[ERR] executor: error applying pid to cgroup: %v", err)
+		if er := DestroyCgroup2(cg.root, cg.path, os.Getpid()); er != nil {
+			e.logger.Printf("[ERR] executor: error destroying cgroup: %v", er)
+		}
+		return err
+	}
+	return nil
+}
+
 // configureCgroups converts a Nomad Resources specification into the equivalent
 // cgroup configuration. It returns an error if the resources are invalid.
 func (e *UniversalExecutor) configureCgroups(resources *structs.Resources) error {
@@ -139,6 +203,14 @@ func (e *UniversalExecutor) configureCgroups(resources *structs.Resources) error
 	if resources.MemoryMB > 0 {
 		// Total amount of memory allowed to consume
 		e.resConCtx.groups.Resources.Memory = int64(resources.MemoryMB * 1024 * 1024)
+
+		// With a memory limit above the reservation, the reservation becomes
+		// the soft limit the task is reclaimed down to under memory pressure
+		if resources.MemoryMaxMB > resources.MemoryMB {
+			e.resConCtx.groups.Resources.Memory = int64(resources.MemoryMaxMB * 1024 * 1024)
+			e.resConCtx.groups.Resources.MemoryReservation = int64(resources.MemoryMB * 1024 * 1024)
+		}
+
 		// Disable swap to avoid issues on the machine
 		var memSwappiness int64 = 0
 		e.resConCtx.groups.Resources.MemorySwappiness = &memSwappiness
@@ -151,6 +223,9 @@ func (e *UniversalExecutor) configureCgroups(resources *structs.Resources) error
 	// Set the relative CPU shares for this cgroup.
 	e.resConCtx.groups.Resources.CpuShares = int64(resources.CPU)
 
+	// Limit the number of processes
+	e.resConCtx.groups.Resources.PidsLimit = e.command.PidsLimit
+
 	if resources.IOPS != 0 {
 		// Validate it is in an acceptable range.
 		if resources.IOPS < 10 || resources.IOPS > 1000 {
@@ -177,6 +252,10 @@ func (e *UniversalExecutor) Stats() (*cstructs.TaskResourceUsage, error) {
 		}
 		return e.aggregatedResourceUsage(pidStats), nil
 	}
+	if e.resConCtx.cgroup2 != nil {
+		return e.statsCgroup2()
+	}
+
 	ts := time.Now()
 	manager := getCgroupManager(e.resConCtx.groups, e.resConCtx.cgPaths)
 	stats, err := manager.GetStats()
@@ -227,6 +306,48 @@ func (e *UniversalExecutor) Stats() (*cstructs.TaskResourceUsage, error) {
 	return &taskResUsage, nil
 }
 
+// statsCgroup2 reports the resource utilization of the cgroup in the unified
+// hierarchy.
+func (e *UniversalExecutor) statsCgroup2() (*cstructs.TaskResourceUsage, error) {
+	ts := time.Now()
+	stats, err := e.resConCtx.cgroup2.stats()
+	if err != nil {
+		return nil, err
+	}
+
+	ms := &cstructs.MemoryStats{
+		RSS:         stats.Anon,
+		Cache:       stats.File,
+		Swap:        stats.Swap,
+		Usage:       stats.Usage,
+		MaxUsage:    stats.Peak,
+		KernelUsage: stats.Kernel,
+		Measured:    ExecutorCgroupV2MeasuredMemStats,
+	}
+
+	totalPercent := e.totalCpuStats.Percent(float64(stats.CPUUsage))
+	cs := &cstructs.CpuStats{
+		SystemMode:       e.systemCpuStats.Percent(float64(stats.CPUSystem)),
+		UserMode:         e.userCpuStats.Percent(float64(stats.CPUUser)),
+		Percent:          totalPercent,
+		ThrottledPeriods: stats.ThrottledPeriods,
+		ThrottledTime:    stats.ThrottledTime,
+		TotalTicks:       e.systemCpuStats.TicksConsumed(totalPercent),
+		Measured:         ExecutorCgroupMeasuredCpuStats,
+	}
+	taskResUsage := cstructs.TaskResourceUsage{
+		ResourceUsage: &cstructs.ResourceUsage{
+			MemoryStats: ms,
+			CpuStats:    cs,
+		},
+		Timestamp: ts.UTC().UnixNano(),
+	}
+	if pidStats, err := e.pidStats(); err == nil {
+		taskResUsage.Pids = pidStats
+	}
+	return &taskResUsage, nil
+}
+
 // runAs takes a user id as a string and looks up the user, and sets the command
 // to execute as that user.
 func (e *UniversalExecutor) runAs(userid string) error {
@@ -295,8 +416,14 @@ func (e *UniversalExecutor) configureChroot() error {
 // isolation
 func (e *UniversalExecutor) getAllPids() (map[int]*nomadPid, error) {
 	if e.command.ResourceLimits || e.command.BasicProcessCgroup {
-		manager := getCgroupManager(e.resConCtx.groups, e.resConCtx.cgPaths)
-		pids, err := manager.GetAllPids()
+		var pids []int
+		var err error
+		if e.resConCtx.cgroup2 != nil {
+			pids, err = e.resConCtx.cgroup2.pids()
+		} else {
+			manager := getCgroupManager(e.resConCtx.groups, e.resConCtx.cgPaths)
+			pids, err = manager.GetAllPids()
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -1,6 +1,8 @@
 package executor
 
 import (
+	"context"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -12,10 +14,12 @@ import (
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/driver/env"
 	dstructs "github.com/hashicorp/nomad/client/driver/structs"
+	"github.com/hashicorp/nomad/client/lib/cgutil"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/client/testutil"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 // testExecutorContextWithChroot returns an ExecutorContext and AllocDir with
@@ -94,6 +98,9 @@ func TestExecutor_IsolationAndConstraints(t *testing.T) {
 
 	// Check if the resource constraints were applied
 	memLimits := filepath.Join(ps.IsolationConfig.CgroupPaths["memory"], "memory.limit_in_bytes")
+	if cgutil.IsCgroup2UnifiedMode() {
+		memLimits = filepath.Join(ps.IsolationConfig.Cgroup2Path, "memory.max")
+	}
 	data, err := ioutil.ReadFile(memLimits)
 	if err != nil {
 		t.Fatalf("err: %v", err)
@@ -142,6 +149,31 @@ ld.so.conf.d/`
 	}
 }
 
+func TestExecutor_ConfigureCgroups_MemoryMax(t *testing.T) {
+	t.Parallel()
+
+	executor := NewExecutor(testlog.Logger(t)).(*UniversalExecutor)
+	executor.command = &ExecCommand{ResourceLimits: true}
+
+	// Without a memory limit the reservation is the hard limit
+	if err := executor.configureCgroups(&structs.Resources{CPU: 100, MemoryMB: 256}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	res := executor.resConCtx.groups.Resources
+	if res.Memory != 256*1024*1024 || res.MemoryReservation != 0 {
+		t.Fatalf("unexpected memory limits: %d hard, %d soft", res.Memory, res.MemoryReservation)
+	}
+
+	// With a memory limit the reservation becomes the soft limit
+	if err := executor.configureCgroups(&structs.Resources{CPU: 100, MemoryMB: 256, MemoryMaxMB: 512}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	res = executor.resConCtx.groups.Resources
+	if res.Memory != 512*1024*1024 || res.MemoryReservation != 256*1024*1024 {
+		t.Fatalf("unexpected memory limits: %d hard, %d soft", res.Memory, res.MemoryReservation)
+	}
+}
+
 func TestExecutor_ClientCleanup(t *testing.T) {
 	t.Parallel()
 	testutil.ExecCompatible(t)
@@ -193,3 +225,96 @@ func TestExecutor_ClientCleanup(t *testing.T) {
 		t.Fatalf("Expected size: %v, actual: %v", finfo.Size(), finfo1.Size())
 	}
 }
+
+// testExecStream collects the output of an interactive command and ends its
+// input once closed
+type testExecStream struct {
+	outCh  chan *dstructs.ExecStreamingResponseMsg
+	doneCh chan struct{}
+}
+
+func newTestExecStream() *testExecStream {
+	return &testExecStream{
+		outCh:  make(chan *dstructs.ExecStreamingResponseMsg, 100),
+		doneCh: make(chan struct{}),
+	}
+}
+
+func (s *testExecStream) Send(msg *dstructs.ExecStreamingResponseMsg) error {
+	s.outCh <- msg
+	return nil
+}
+
+func (s *testExecStream) Recv() (*dstructs.ExecStreamingRequestMsg, error) {
+	<-s.doneCh
+	return nil, io.EOF
+}
+
+func TestExecutor_ExecStreaming_ResourceContainer(t *testing.T) {
+	t.Parallel()
+	testutil.ExecCompatible(t)
+
+	ctx, allocDir := testExecutorContextWithChroot(t)
+	defer allocDir.Destroy()
+
+	executor := NewExecutor(testlog.Logger(t))
+	if err := executor.SetContext(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	execCmd := ExecCommand{Cmd: "/bin/sleep", Args: []string{"30"}}
+	execCmd.FSIsolation = true
+	execCmd.ResourceLimits = true
+	ps, err := executor.LaunchCmd(&execCmd)
+	if err != nil {
+		t.Fatalf("error in launching command: %v", err)
+	}
+	defer executor.Exit()
+
+	// The binary is looked up in the chroot and the command reports its pid
+	stream := newTestExecStream()
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := executor.ExecStreaming(context.Background(), []string{"bash", "-c", "echo $$; exec sleep 30"}, false, stream)
+		errCh <- err
+	}()
+
+	var pid string
+	select {
+	case msg := <-stream.outCh:
+		if msg.Stdout == nil {
+			t.Fatalf("unexpected message: %+v", msg)
+		}
+		pid = strings.TrimSpace(string(msg.Stdout.Data))
+	case err := <-errCh:
+		t.Fatalf("command exited: %v", err)
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timeout waiting for the pid of the command")
+	}
+
+	// The command runs in the cgroup of the task
+	procs := filepath.Join(ps.IsolationConfig.CgroupPaths["memory"], "cgroup.procs")
+	if cgutil.IsCgroup2UnifiedMode() {
+		procs = filepath.Join(ps.IsolationConfig.Cgroup2Path, "cgroup.procs")
+	}
+	data, err := ioutil.ReadFile(procs)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	found := false
+	for _, p := range strings.Fields(string(data)) {
+		if p == pid {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("pid %q of the command not in the cgroup of the task: %q", pid, string(data))
+	}
+
+	close(stream.doneCh)
+	select {
+	case <-errCh:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timeout waiting for the command to be stopped")
+	}
+}
@@ -0,0 +1,255 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	dstructs "github.com/hashicorp/nomad/client/driver/structs"
+)
+
+const (
+	// execStreamingBufSize is the maximum size of the output chunks sent by
+	// interactive commands
+	execStreamingBufSize = 32 * 1024
+
+	// ttyEOT is written to the terminal of an interactive command when its
+	// input is closed, as closing the terminal would hang up the command
+	ttyEOT = 0x04
+
+	// chrootDefaultPath is searched for the binaries of interactive commands
+	// run in a chroot when the task doesn't set $PATH
+	chrootDefaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+)
+
+// ExecStreaming runs an interactive command in the context of the task,
+// attached to a new terminal if tty is set. Input and output are exchanged
+// over the stream until the command exits or the context is done.
+func (e *UniversalExecutor) ExecStreaming(ctx context.Context, command []string, tty bool,
+	stream dstructs.ExecStream) (*dstructs.ExecStreamingExitResult, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("missing command")
+	}
+	if e.ctx == nil || e.command == nil {
+		return nil, fmt.Errorf("task is not running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	taskEnv := e.ctx.TaskEnv
+	name := taskEnv.ReplaceEnv(command[0])
+	path, err := e.lookupExecBin(name)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, path, taskEnv.ParseAndReplace(command[1:])...)
+	cmd.Args[0] = name
+	cmd.Dir = e.cmd.Dir
+	cmd.Env = taskEnv.List()
+
+	// Run the command with the isolation of the task
+	if e.cmd.SysProcAttr != nil {
+		attrs := *e.cmd.SysProcAttr
+		cmd.SysProcAttr = &attrs
+	}
+
+	// The command inherits the resource container of the executor when it
+	// is started, so the executor enters it again in case it was left
+	if err := e.enterResourceContainer(); err != nil {
+		return nil, fmt.Errorf("failed to enter the resource container of the task: %v", err)
+	}
+
+	var stdin io.Writer
+	var closeStdin func() error
+	var stdout, stderr io.Reader
+	var tm *os.File
+	if tty {
+		tm, err = startTTY(cmd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start command: %v", err)
+		}
+		defer tm.Close()
+
+		stdin, stdout = tm, tm
+		closeStdin = func() error {
+			_, err := tm.Write([]byte{ttyEOT})
+			return err
+		}
+	} else {
+		in, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+		if stdout, err = cmd.StdoutPipe(); err != nil {
+			return nil, err
+		}
+		if stderr, err = cmd.StderrPipe(); err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start command: %v", err)
+		}
+
+		stdin, closeStdin = in, in.Close
+	}
+
+	// Forward the input and terminal resizes until the caller goes away
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				cancel()
+				return
+			}
+
+			if msg.TTYSize != nil && tm != nil {
+				if err := setTTYSize(tm, msg.TTYSize); err != nil {
+					e.logger.Printf("[DEBUG] executor: failed to resize terminal: %v", err)
+				}
+			}
+			if msg.Stdin != nil {
+				if len(msg.Stdin.Data) != 0 {
+					stdin.Write(msg.Stdin.Data)
+				}
+				if msg.Stdin.Close {
+					closeStdin()
+				}
+			}
+		}
+	}()
+
+	// Forward the output until the command closes it
+	var sendLock sync.Mutex
+	send := func(msg *dstructs.ExecStreamingResponseMsg) {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		if err := stream.Send(msg); err != nil {
+			cancel()
+		}
+	}
+
+	var wg sync.WaitGroup
+	forward := func(r io.Reader, isStderr bool) {
+		defer wg.Done()
+		op := func(o *dstructs.ExecStreamingIOOperation) *dstructs.ExecStreamingResponseMsg {
+			if isStderr {
+				return &dstructs.ExecStreamingResponseMsg{Stderr: o}
+			}
+			return &dstructs.ExecStreamingResponseMsg{Stdout: o}
+		}
+
+		buf := make([]byte, execStreamingBufSize)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				data := make([]byte, n)
+				copy(data, buf[:n])
+				send(op(&dstructs.ExecStreamingIOOperation{Data: data}))
+			}
+			if err != nil {
+				break
+			}
+		}
+		send(op(&dstructs.ExecStreamingIOOperation{Close: true}))
+	}
+
+	wg.Add(1)
+	go forward(stdout, false)
+	if stderr != nil {
+		wg.Add(1)
+		go forward(stderr, true)
+	}
+
+	// The output of a terminal ends once every process using it exited.
+	// Processes left in the background by the command are given a
+	// tolerance before the terminal is closed.
+	if tm != nil {
+		waitErr := cmd.Wait()
+
+		doneCh := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(doneCh)
+		}()
+		select {
+		case <-doneCh:
+		case <-time.After(processOutputCloseTolerance):
+			tm.Close()
+			<-doneCh
+		}
+		return execStreamingResult(ctx, waitErr)
+	}
+
+	wg.Wait()
+	return execStreamingResult(ctx, cmd.Wait())
+}
+
+// lookupExecBin returns the path of the binary of an interactive command.
+// Outside of a chroot it is looked up like the binary of the task. In a
+// chroot it is looked up in the task's directories, then in the task's $PATH
+// within the chroot, and the returned path is relative to the chroot.
+func (e *UniversalExecutor) lookupExecBin(bin string) (string, error) {
+	if !e.fsIsolationEnforced {
+		return e.lookupBin(bin)
+	}
+
+	// Paths are resolved by the command within the chroot
+	if strings.Contains(bin, "/") {
+		return bin, nil
+	}
+
+	dirs := []string{filepath.Join("/", allocdir.TaskLocal), "/"}
+	path := e.ctx.TaskEnv.EnvMap["PATH"]
+	if path == "" {
+		path = chrootDefaultPath
+	}
+	dirs = append(dirs, filepath.SplitList(path)...)
+
+	for _, dir := range dirs {
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+
+		p := filepath.Join(dir, bin)
+		fi, err := os.Stat(filepath.Join(e.ctx.TaskDir, p))
+		if err == nil && !fi.IsDir() && fi.Mode()&0111 != 0 {
+			return p, nil
+		}
+	}
+
+	return "", fmt.Errorf("binary %q could not be found in the task's chroot", bin)
+}
+
+// execStreamingResult returns the result of an interactive command given the
+// error returned when waiting for it
+func execStreamingResult(ctx context.Context, err error) (*dstructs.ExecStreamingExitResult, error) {
+	if err == nil {
+		return &dstructs.ExecStreamingExitResult{}, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return nil, err
+	}
+
+	exitCode := 1
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+		exitCode = status.ExitStatus()
+		if status.Signaled() {
+			exitCode = 128 + int(status.Signal())
+		}
+	}
+	return &dstructs.ExecStreamingExitResult{ExitCode: exitCode}, nil
+}
@@ -0,0 +1,46 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package executor
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	dstructs "github.com/hashicorp/nomad/client/driver/structs"
+	"github.com/kr/pty"
+)
+
+// startTTY starts the command attached to a new pseudo terminal and returns
+// the master of the terminal
+func startTTY(cmd *exec.Cmd) (*os.File, error) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer slave.Close()
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+
+	// The command leads a new session controlled by the terminal
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = false
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+
+	if err := cmd.Start(); err != nil {
+		master.Close()
+		return nil, err
+	}
+	return master, nil
+}
+
+// setTTYSize resizes the terminal of the master
+func setTTYSize(master *os.File, size *dstructs.TerminalSize) error {
+	return pty.Setsize(master, &pty.Winsize{
+		Rows: uint16(size.Height),
+		Cols: uint16(size.Width),
+	})
+}
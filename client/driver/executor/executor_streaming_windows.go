@@ -0,0 +1,19 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	dstructs "github.com/hashicorp/nomad/client/driver/structs"
+)
+
+// startTTY is not supported on Windows
+func startTTY(cmd *exec.Cmd) (*os.File, error) {
+	return nil, fmt.Errorf("terminals are not supported on Windows")
+}
+
+// setTTYSize is not supported on Windows
+func setTTYSize(master *os.File, size *dstructs.TerminalSize) error {
+	return nil
+}
@@ -1,10 +1,15 @@
 package executor
 
 import (
+	"encoding/json"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 	"syscall"
 	"testing"
 	"time"
@@ -14,6 +19,7 @@ import (
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 	tu "github.com/hashicorp/nomad/testutil"
 	"github.com/mitchellh/go-ps"
 )
@@ -128,6 +134,71 @@ func TestExecutor_Start_Wait(t *testing.T) {
 	}
 }
 
+func TestExecutor_Start_Wait_LogSink(t *testing.T) {
+	t.Parallel()
+
+	var lock sync.Mutex
+	var messages []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []struct {
+			Message string `json:"message"`
+			AllocID string `json:"alloc_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		for _, r := range batch {
+			messages = append(messages, r.AllocID+": "+r.Message)
+		}
+	}))
+	defer ts.Close()
+
+	execCmd := ExecCommand{Cmd: "/bin/echo", Args: []string{"hello world"}}
+	ctx, allocDir := testExecutorContext(t)
+	defer allocDir.Destroy()
+	ctx.Task.LogConfig.Sinks = []*structs.LogSink{
+		{
+			Type:      structs.LogSinkTypeHTTP,
+			Address:   ts.URL,
+			BatchWait: 10 * time.Millisecond,
+		},
+	}
+	executor := NewExecutor(testlog.Logger(t))
+
+	if err := executor.SetContext(ctx); err != nil {
+		t.Fatalf("Unexpected error")
+	}
+	if _, err := executor.LaunchCmd(&execCmd); err != nil {
+		t.Fatalf("error in launching command: %v", err)
+	}
+	if _, err := executor.Wait(); err != nil {
+		t.Fatalf("error in waiting for command: %v", err)
+	}
+	if err := executor.Exit(); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	// The output is written to the log file and shipped to the sink
+	file := filepath.Join(ctx.LogDir, "web.stdout.0")
+	output, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Couldn't read file %v", file)
+	}
+	if act := strings.TrimSpace(string(output)); act != "hello world" {
+		t.Fatalf("Command output incorrectly: want %v; got %v", "hello world", act)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	expected := []string{ctx.TaskEnv.EnvMap[env.AllocID] + ": hello world"}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Fatalf("Shipped output incorrectly: want %v; got %v", expected, messages)
+	}
+}
+
 func TestExecutor_WaitExitSignal(t *testing.T) {
 	t.Parallel()
 	execCmd := ExecCommand{Cmd: "/bin/sleep", Args: []string{"10000"}}
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	dstructs "github.com/hashicorp/nomad/client/driver/structs"
+	"github.com/hashicorp/nomad/client/lib/cgutil"
 	cgroupConfig "github.com/opencontainers/runc/libcontainer/configs"
 )
 
@@ -14,10 +15,18 @@ type resourceContainerContext struct {
 	groups  *cgroupConfig.Cgroup
 	cgPaths map[string]string
 	cgLock  sync.Mutex
+
+	// cgroup2 manages the cgroup of the task on hosts that only mount the
+	// unified cgroup v2 hierarchy. groups then holds the resources applied
+	// to it.
+	cgroup2 *cgroup2Manager
 }
 
 // clientCleanup removes this host's Cgroup from the Nomad Client's context
 func clientCleanup(ic *dstructs.IsolationConfig, pid int) error {
+	if ic.Cgroup2Path != "" {
+		return DestroyCgroup2(cgutil.CgroupRoot, ic.Cgroup2Path, pid)
+	}
 	if err := DestroyCgroup(ic.Cgroup, ic.CgroupPaths, pid); err != nil {
 		return err
 	}
@@ -28,6 +37,9 @@ func clientCleanup(ic *dstructs.IsolationConfig, pid int) error {
 func (rc *resourceContainerContext) executorCleanup() error {
 	rc.cgLock.Lock()
 	defer rc.cgLock.Unlock()
+	if rc.cgroup2 != nil {
+		return DestroyCgroup2(rc.cgroup2.root, rc.cgroup2.path, os.Getpid())
+	}
 	if err := DestroyCgroup(rc.groups, rc.cgPaths, os.Getpid()); err != nil {
 		return err
 	}
@@ -35,8 +47,21 @@ func (rc *resourceContainerContext) executorCleanup() error {
 }
 
 func (rc *resourceContainerContext) getIsolationConfig() *dstructs.IsolationConfig {
-	return &dstructs.IsolationConfig{
+	ic := &dstructs.IsolationConfig{
 		Cgroup:      rc.groups,
 		CgroupPaths: rc.cgPaths,
 	}
+	if rc.cgroup2 != nil {
+		ic.Cgroup2Path = rc.cgroup2.path
+	}
+	return ic
+}
+
+// oomKilled returns whether a process of the resource container was killed
+// for running out of memory. It is only reported on cgroup v2 hosts.
+func (rc *resourceContainerContext) oomKilled() bool {
+	if rc.cgroup2 == nil {
+		return false
+	}
+	return rc.cgroup2.oomKilled()
 }
@@ -1,15 +1,20 @@
 package driver
 
 import (
+	"context"
 	"encoding/gob"
+	"fmt"
 	"log"
+	"net"
 	"net/rpc"
 	"os"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/client/driver/executor"
+	dstructs "github.com/hashicorp/nomad/client/driver/structs"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -26,6 +31,7 @@ func init() {
 
 type ExecutorRPC struct {
 	client *rpc.Client
+	broker *plugin.MuxBroker
 	logger *log.Logger
 }
 
@@ -45,6 +51,18 @@ type ExecCmdReturn struct {
 	Code   int
 }
 
+// ExecStreamingArgs wraps an interactive command and the ID of the brokered
+// connection used to stream its input and output
+type ExecStreamingArgs struct {
+	StreamID uint32
+	Cmd      []string
+	Tty      bool
+}
+
+type ExecStreamingReturn struct {
+	Result *dstructs.ExecStreamingExitResult
+}
+
 func (e *ExecutorRPC) LaunchCmd(cmd *executor.ExecCommand) (*executor.ProcessState, error) {
 	var ps *executor.ProcessState
 	err := e.client.Call("Plugin.LaunchCmd", LaunchCmdArgs{Cmd: cmd}, &ps)
@@ -117,8 +135,133 @@ func (e *ExecutorRPC) Exec(deadline time.Time, name string, args []string) ([]by
 	return resp.Output, resp.Code, err
 }
 
+// ExecStreaming runs an interactive command in the executor. The input and
+// output of the command are exchanged over a connection brokered with the
+// executor for the duration of the call.
+func (e *ExecutorRPC) ExecStreaming(ctx context.Context, cmd []string, tty bool,
+	stream dstructs.ExecStream) (*dstructs.ExecStreamingExitResult, error) {
+	id := e.broker.NextId()
+	connCh := make(chan net.Conn, 1)
+	acceptErrCh := make(chan error, 1)
+	go func() {
+		conn, err := e.broker.Accept(id)
+		if err != nil {
+			acceptErrCh <- err
+			return
+		}
+		connCh <- conn
+	}()
+
+	args := ExecStreamingArgs{
+		StreamID: id,
+		Cmd:      cmd,
+		Tty:      tty,
+	}
+	var resp ExecStreamingReturn
+	call := e.client.Go("Plugin.ExecStreaming", args, &resp, nil)
+
+	var conn net.Conn
+	select {
+	case conn = <-connCh:
+	case err := <-acceptErrCh:
+		return nil, fmt.Errorf("failed to connect to executor: %v", err)
+	case <-call.Done:
+		if call.Error != nil {
+			return nil, call.Error
+		}
+		return nil, fmt.Errorf("executor did not connect")
+	}
+	defer conn.Close()
+
+	// Forward the output of the command to the stream, and the input of the
+	// stream to the command
+	conns := newExecStreamConn(conn)
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				conn.Close()
+				return
+			}
+			if err := conns.encode(msg); err != nil {
+				return
+			}
+		}
+	}()
+	outputDoneCh := make(chan struct{})
+	go func() {
+		defer close(outputDoneCh)
+		for {
+			var msg dstructs.ExecStreamingResponseMsg
+			if err := conns.decode(&msg); err != nil {
+				return
+			}
+			if err := stream.Send(&msg); err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-call.Done:
+	case <-ctx.Done():
+		conn.Close()
+		<-call.Done
+	}
+
+	// The executor closes the connection once the command exited, wait for
+	// the remaining output to be forwarded
+	<-outputDoneCh
+
+	if call.Error != nil {
+		return nil, call.Error
+	}
+	return resp.Result, nil
+}
+
+// execStreamConn exchanges the messages of an interactive command over a
+// brokered connection
+type execStreamConn struct {
+	enc     *gob.Encoder
+	encLock sync.Mutex
+	dec     *gob.Decoder
+}
+
+func newExecStreamConn(conn net.Conn) *execStreamConn {
+	return &execStreamConn{
+		enc: gob.NewEncoder(conn),
+		dec: gob.NewDecoder(conn),
+	}
+}
+
+func (c *execStreamConn) encode(msg interface{}) error {
+	c.encLock.Lock()
+	defer c.encLock.Unlock()
+	return c.enc.Encode(msg)
+}
+
+func (c *execStreamConn) decode(msg interface{}) error {
+	return c.dec.Decode(msg)
+}
+
+// Send implements dstructs.ExecStream for the executor
+func (c *execStreamConn) Send(msg *dstructs.ExecStreamingResponseMsg) error {
+	return c.encode(msg)
+}
+
+// Recv implements dstructs.ExecStream for the executor
+func (c *execStreamConn) Recv() (*dstructs.ExecStreamingRequestMsg, error) {
+	var msg dstructs.ExecStreamingRequestMsg
+	if err := c.decode(&msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
 type ExecutorRPCServer struct {
 	Impl   executor.Executor
+	broker *plugin.MuxBroker
 	logger *log.Logger
 }
 
@@ -201,18 +344,33 @@ func (e *ExecutorRPCServer) Exec(args ExecCmdArgs, result *ExecCmdReturn) error
 	return err
 }
 
+func (e *ExecutorRPCServer) ExecStreaming(args ExecStreamingArgs, result *ExecStreamingReturn) error {
+	conn, err := e.broker.Dial(args.StreamID)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	res, err := e.Impl.ExecStreaming(context.Background(), args.Cmd, args.Tty, newExecStreamConn(conn))
+	if err != nil {
+		return err
+	}
+	result.Result = res
+	return nil
+}
+
 type ExecutorPlugin struct {
 	logger *log.Logger
 	Impl   *ExecutorRPCServer
 }
 
-func (p *ExecutorPlugin) Server(*plugin.MuxBroker) (interface{}, error) {
+func (p *ExecutorPlugin) Server(b *plugin.MuxBroker) (interface{}, error) {
 	if p.Impl == nil {
-		p.Impl = &ExecutorRPCServer{Impl: executor.NewExecutor(p.logger), logger: p.logger}
+		p.Impl = &ExecutorRPCServer{Impl: executor.NewExecutor(p.logger), broker: b, logger: p.logger}
 	}
 	return p.Impl, nil
 }
 
 func (p *ExecutorPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
-	return &ExecutorRPC{client: c, logger: p.logger}, nil
+	return &ExecutorRPC{client: c, broker: b, logger: p.logger}, nil
 }
@@ -51,6 +51,7 @@ type JavaDriverConfig struct {
 	JarPath   string   `mapstructure:"jar_path"`
 	JvmOpts   []string `mapstructure:"jvm_options"`
 	Args      []string `mapstructure:"args"`
+	PidsLimit int64    `mapstructure:"pids_limit"`
 }
 
 // javaHandle is returned from Start/Open as a handle to the PID
@@ -94,6 +95,9 @@ func (d *JavaDriver) Validate(config map[string]interface{}) error {
 			"args": {
 				Type: fields.TypeArray,
 			},
+			"pids_limit": {
+				Type: fields.TypeInt,
+			},
 		},
 	}
 
@@ -101,13 +105,18 @@ func (d *JavaDriver) Validate(config map[string]interface{}) error {
 		return err
 	}
 
+	if fd.Get("pids_limit").(int) < 0 {
+		return fmt.Errorf("pids_limit must be greater than or equal to 0")
+	}
+
 	return nil
 }
 
 func (d *JavaDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: true,
-		Exec:        true,
+		SendSignals:   true,
+		Exec:          true,
+		ExecStreaming: true,
 	}
 }
 
@@ -278,6 +287,7 @@ func (d *JavaDriver) Start(ctx *ExecContext, task *structs.Task) (*StartResponse
 		ResourceLimits: true,
 		User:           getExecutorUser(task),
 		TaskKillSignal: taskKillSignal,
+		PidsLimit:      driverConfig.PidsLimit,
 	}
 	ps, err := execIntf.LaunchCmd(execCmd)
 	if err != nil {
@@ -404,6 +414,11 @@ func (h *javaHandle) Exec(ctx context.Context, cmd string, args []string) ([]byt
 	return h.executor.Exec(deadline, cmd, args)
 }
 
+func (h *javaHandle) ExecStreaming(ctx context.Context, cmd []string, tty bool,
+	stream dstructs.ExecStream) (*dstructs.ExecStreamingExitResult, error) {
+	return h.executor.ExecStreaming(ctx, cmd, tty, stream)
+}
+
 func (h *javaHandle) Signal(s os.Signal) error {
 	return h.executor.Signal(s)
 }
@@ -454,7 +469,12 @@ func (h *javaHandle) run() {
 	h.executor.Exit()
 	h.pluginClient.Kill()
 
+	// Report tasks killed for running out of memory
+	if ps.OOMKilled && werr == nil {
+		werr = fmt.Errorf("OOM Killed")
+	}
+
 	// Send the results
-	h.waitCh <- &dstructs.WaitResult{ExitCode: ps.ExitCode, Signal: ps.Signal, Err: werr}
+	h.waitCh <- &dstructs.WaitResult{ExitCode: ps.ExitCode, Signal: ps.Signal, OOMKilled: ps.OOMKilled, Err: werr}
 	close(h.waitCh)
 }
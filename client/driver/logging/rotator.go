@@ -3,7 +3,9 @@ package logging
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
@@ -12,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -30,22 +33,30 @@ const (
 
 	// newLineDelimiter is the delimiter used for new lines.
 	newLineDelimiter = '\n'
+
+	// CompressedLogSuffix is the suffix of rotated files that are compressed
+	CompressedLogSuffix = ".gz"
 )
 
-// FileRotator writes bytes to a rotated set of files
+// FileRotator writes bytes to a rotated set of files. Rotated files are
+// optionally compressed, in which case their name has the
+// CompressedLogSuffix.
 type FileRotator struct {
-	MaxFiles int   // MaxFiles is the maximum number of rotated files allowed in a path
-	FileSize int64 // FileSize is the size a rotated file is allowed to grow
-
-	path             string // path is the path on the file system where the rotated set of files are opened
-	baseFileName     string // baseFileName is the base file name of the rotated files
-	logFileIdx       int    // logFileIdx is the current index of the rotated files
-	oldestLogFileIdx int    // oldestLogFileIdx is the index of the oldest log file in a path
-
-	currentFile *os.File // currentFile is the file that is currently getting written
-	currentWr   int64    // currentWr is the number of bytes written to the current file
-	bufw        *bufio.Writer
-	bufLock     sync.Mutex
+	MaxFiles       int           // MaxFiles is the maximum number of rotated files allowed in a path, compressed or not
+	FileSize       int64         // FileSize is the size a rotated file is allowed to grow
+	RotateInterval time.Duration // RotateInterval is the age a file is allowed to reach before being rotated, zero disables it
+	Compress       bool          // Compress enables compressing the files once rotated
+
+	path         string // path is the path on the file system where the rotated set of files are opened
+	baseFileName string // baseFileName is the base file name of the rotated files
+	logFileIdx   int    // logFileIdx is the current index of the rotated files
+	activeIdx    int64  // activeIdx is the index of the file being written, read atomically by the purge go routine
+
+	currentFile   *os.File  // currentFile is the file that is currently getting written
+	currentWr     int64     // currentWr is the number of bytes written to the current file
+	currentOpened time.Time // currentOpened is when the current file was opened
+	bufw          *bufio.Writer
+	bufLock       sync.Mutex
 
 	flushTicker *time.Ticker
 	logger      *log.Logger
@@ -69,7 +80,7 @@ func NewFileRotator(path string, baseFile string, maxFiles int,
 		flushTicker: time.NewTicker(bufferFlushDuration),
 		logger:      logger,
 		purgeCh:     make(chan struct{}, 1),
-		doneCh:      make(chan struct{}, 1),
+		doneCh:      make(chan struct{}),
 	}
 	if err := rotator.lastFile(); err != nil {
 		return nil, err
@@ -80,15 +91,16 @@ func NewFileRotator(path string, baseFile string, maxFiles int,
 }
 
 // Write writes a byte array to a file and rotates the file if it's size becomes
-// equal to the maximum size the user has defined.
+// equal to the maximum size the user has defined, or if it is older than the
+// rotation interval.
 func (f *FileRotator) Write(p []byte) (n int, err error) {
 	n = 0
 	var forceRotate bool
 
 	for n < len(p) {
-		// Check if we still have space in the current file, otherwise close and
-		// open the next file
-		if forceRotate || f.currentWr >= f.FileSize {
+		// Check if we still have space in the current file and that it is
+		// not due for rotation, otherwise close and open the next file
+		if forceRotate || f.currentWr >= f.FileSize || f.intervalElapsed() {
 			forceRotate = false
 			f.flushBuffer()
 			f.currentFile.Close()
@@ -148,8 +160,15 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 	return
 }
 
-// nextFile opens the next file and purges older files if the number of rotated
-// files is larger than the maximum files configured by the user
+// intervalElapsed returns whether the current file has data and is older
+// than the rotation interval
+func (f *FileRotator) intervalElapsed() bool {
+	return f.RotateInterval > 0 && f.currentWr > 0 && time.Since(f.currentOpened) >= f.RotateInterval
+}
+
+// nextFile opens the next file and signals the purge go routine to compress
+// the rotated files and purge older files if the number of rotated files is
+// larger than the maximum files configured by the user
 func (f *FileRotator) nextFile() error {
 	nextFileIdx := f.logFileIdx
 	for {
@@ -160,16 +179,19 @@ func (f *FileRotator) nextFile() error {
 				continue
 			}
 		}
+		if _, err := os.Stat(logFileName + CompressedLogSuffix); err == nil {
+			continue
+		}
 		f.logFileIdx = nextFileIdx
 		if err := f.createFile(); err != nil {
 			return err
 		}
 		break
 	}
-	// Purge old files if we have more files than MaxFiles
+
 	f.closedLock.Lock()
 	defer f.closedLock.Unlock()
-	if f.logFileIdx-f.oldestLogFileIdx >= f.MaxFiles && !f.closed {
+	if !f.closed {
 		select {
 		case f.purgeCh <- struct{}{}:
 		default:
@@ -185,22 +207,24 @@ func (f *FileRotator) lastFile() error {
 		return err
 	}
 
-	prefix := fmt.Sprintf("%s.", f.baseFileName)
+	// The file with the largest index is reopened unless it was compressed
+	compressed := false
 	for _, fi := range finfos {
 		if fi.IsDir() {
 			continue
 		}
-		if strings.HasPrefix(fi.Name(), prefix) {
-			fileIdx := strings.TrimPrefix(fi.Name(), prefix)
-			n, err := strconv.Atoi(fileIdx)
-			if err != nil {
-				continue
-			}
-			if n > f.logFileIdx {
-				f.logFileIdx = n
-			}
+		n, gz, ok := f.fileIndex(fi.Name())
+		if !ok {
+			continue
+		}
+		if n > f.logFileIdx || (n == f.logFileIdx && !gz) {
+			f.logFileIdx = n
+			compressed = gz
 		}
 	}
+	if compressed {
+		f.logFileIdx++
+	}
 	if err := f.createFile(); err != nil {
 		return err
 	}
@@ -220,10 +244,30 @@ func (f *FileRotator) createFile() error {
 		return err
 	}
 	f.currentWr = fi.Size()
+	f.currentOpened = time.Now()
+	atomic.StoreInt64(&f.activeIdx, int64(f.logFileIdx))
 	f.createOrResetBuffer()
 	return nil
 }
 
+// fileIndex returns the index of a rotated file from its name and whether
+// it is compressed. It returns false if the file isn't a rotated file.
+func (f *FileRotator) fileIndex(name string) (int, bool, bool) {
+	prefix := fmt.Sprintf("%s.", f.baseFileName)
+	if !strings.HasPrefix(name, prefix) {
+		return 0, false, false
+	}
+
+	fileIdx := strings.TrimPrefix(name, prefix)
+	compressed := strings.HasSuffix(fileIdx, CompressedLogSuffix)
+	fileIdx = strings.TrimSuffix(fileIdx, CompressedLogSuffix)
+	n, err := strconv.Atoi(fileIdx)
+	if err != nil {
+		return 0, false, false
+	}
+	return n, compressed, true
+}
+
 // flushPeriodically flushes the buffered writer every 100ms to the underlying
 // file
 func (f *FileRotator) flushPeriodically() {
@@ -234,7 +278,6 @@ func (f *FileRotator) flushPeriodically() {
 
 func (f *FileRotator) Close() {
 	f.closedLock.Lock()
-	defer f.closedLock.Unlock()
 
 	// Stop the ticker and flush for one last time
 	f.flushTicker.Stop()
@@ -242,61 +285,129 @@ func (f *FileRotator) Close() {
 
 	// Stop the purge go routine
 	if !f.closed {
-		f.doneCh <- struct{}{}
 		close(f.purgeCh)
 		f.closed = true
 	}
+	f.closedLock.Unlock()
+
+	// Wait for the pending compression of rotated files
+	<-f.doneCh
 }
 
 // purgeOldFiles removes older files and keeps only the last N files rotated for
-// a file
+// a file, compressing the rotated files if enabled
 func (f *FileRotator) purgeOldFiles() {
-	for {
-		select {
-		case <-f.purgeCh:
-			var fIndexes []int
-			files, err := ioutil.ReadDir(f.path)
-			if err != nil {
-				f.logger.Printf("[ERROR] driver.rotator: error getting directory listing: %v", err)
-				return
+	defer close(f.doneCh)
+	for range f.purgeCh {
+		files, err := ioutil.ReadDir(f.path)
+		if err != nil {
+			f.logger.Printf("[ERROR] driver.rotator: error getting directory listing: %v", err)
+			return
+		}
+
+		// Inserting all the rotated files in a slice. A file being
+		// compressed is present twice.
+		var fIndexes []int
+		uncompressed := make(map[int]struct{})
+		seen := make(map[int]struct{})
+		tmpPrefix := fmt.Sprintf(".%s.", f.baseFileName)
+		for _, fi := range files {
+			if strings.HasPrefix(fi.Name(), tmpPrefix) {
+				// Remove the partial output of an interrupted compression
+				os.Remove(filepath.Join(f.path, fi.Name()))
+				continue
 			}
-			// Inserting all the rotated files in a slice
-			for _, fi := range files {
-				if strings.HasPrefix(fi.Name(), f.baseFileName) {
-					fileIdx := strings.TrimPrefix(fi.Name(), fmt.Sprintf("%s.", f.baseFileName))
-					n, err := strconv.Atoi(fileIdx)
-					if err != nil {
-						f.logger.Printf("[ERROR] driver.rotator: error extracting file index: %v", err)
-						continue
-					}
-					fIndexes = append(fIndexes, n)
-				}
+			if !strings.HasPrefix(fi.Name(), f.baseFileName) {
+				continue
 			}
-
-			// Not continuing to delete files if the number of files is not more
-			// than MaxFiles
-			if len(fIndexes) <= f.MaxFiles {
+			n, gz, ok := f.fileIndex(fi.Name())
+			if !ok {
+				f.logger.Printf("[ERROR] driver.rotator: error extracting file index from %q", fi.Name())
 				continue
 			}
+			if !gz {
+				uncompressed[n] = struct{}{}
+			}
+			if _, ok := seen[n]; !ok {
+				seen[n] = struct{}{}
+				fIndexes = append(fIndexes, n)
+			}
+		}
 
-			// Sorting the file indexes so that we can purge the older files and keep
-			// only the number of files as configured by the user
-			sort.Sort(sort.IntSlice(fIndexes))
+		// Sorting the file indexes so that we can purge the older files and keep
+		// only the number of files as configured by the user
+		sort.Sort(sort.IntSlice(fIndexes))
+		if len(fIndexes) > f.MaxFiles {
 			toDelete := fIndexes[0 : len(fIndexes)-f.MaxFiles]
 			for _, fIndex := range toDelete {
 				fname := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, fIndex))
-				err := os.RemoveAll(fname)
-				if err != nil {
+				if err := os.RemoveAll(fname); err != nil {
+					f.logger.Printf("[ERROR] driver.rotator: error removing file: %v", err)
+				}
+				if err := os.RemoveAll(fname + CompressedLogSuffix); err != nil {
 					f.logger.Printf("[ERROR] driver.rotator: error removing file: %v", err)
 				}
 			}
-			f.oldestLogFileIdx = fIndexes[0]
-		case <-f.doneCh:
-			return
+			fIndexes = fIndexes[len(fIndexes)-f.MaxFiles:]
+		}
+
+		if !f.Compress {
+			continue
+		}
+
+		// Compress the rotated files, never the file being written
+		active := int(atomic.LoadInt64(&f.activeIdx))
+		for _, fIndex := range fIndexes {
+			if _, ok := uncompressed[fIndex]; !ok || fIndex >= active {
+				continue
+			}
+			if err := f.compressFile(fIndex); err != nil {
+				f.logger.Printf("[ERROR] driver.rotator: error compressing file: %v", err)
+			}
 		}
 	}
 }
 
+// compressFile replaces the rotated file at the index with a compressed copy.
+// The copy is written to a hidden temporary file first so that a partial copy
+// is never mistaken for a rotated file.
+func (f *FileRotator) compressFile(idx int) error {
+	src := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, idx))
+	tmp := filepath.Join(f.path, fmt.Sprintf(".%s.%d%s.tmp", f.baseFileName, idx, CompressedLogSuffix))
+
+	in, err := os.Open(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if err == nil {
+		err = gz.Close()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, src+CompressedLogSuffix)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to compress %q: %v", src, err)
+	}
+
+	return os.Remove(src)
+}
+
 // flushBuffer flushes the buffer
 func (f *FileRotator) flushBuffer() error {
 	f.bufLock.Lock()
@@ -1,12 +1,14 @@
 package logging
 
 import (
+	"compress/gzip"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/testutil"
@@ -349,6 +351,143 @@ func TestFileRotator_PurgeOldFiles(t *testing.T) {
 	})
 }
 
+func TestFileRotator_RotateInterval(t *testing.T) {
+	t.Parallel()
+	var path string
+	var err error
+	if path, err = ioutil.TempDir("", pathPrefix); err != nil {
+		t.Fatalf("test setup err: %v", err)
+	}
+	defer os.RemoveAll(path)
+
+	fr, err := NewFileRotator(path, baseFileName, 10, 1024, testlog.Logger(t))
+	if err != nil {
+		t.Fatalf("test setup err: %v", err)
+	}
+	defer fr.Close()
+	fr.RotateInterval = 50 * time.Millisecond
+
+	if _, err := fr.Write([]byte("a\n")); err != nil {
+		t.Fatalf("got error while writing: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err := fr.Write([]byte("b\n")); err != nil {
+		t.Fatalf("got error while writing: %v", err)
+	}
+
+	var lastErr error
+	testutil.WaitForResult(func() (bool, error) {
+		for i, expected := range []string{"a\n", "b\n"} {
+			fname := filepath.Join(path, fmt.Sprintf("%s.%d", baseFileName, i))
+			content, err := ioutil.ReadFile(fname)
+			if err != nil {
+				lastErr = fmt.Errorf("error reading file: %v", err)
+				return false, nil
+			}
+			if string(content) != expected {
+				lastErr = fmt.Errorf("expected %q in %v, got %q", expected, fname, content)
+				return false, nil
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("%v", lastErr)
+	})
+}
+
+func TestFileRotator_Compress(t *testing.T) {
+	t.Parallel()
+	var path string
+	var err error
+	if path, err = ioutil.TempDir("", pathPrefix); err != nil {
+		t.Fatalf("test setup err: %v", err)
+	}
+	defer os.RemoveAll(path)
+
+	fr, err := NewFileRotator(path, baseFileName, 3, 2, testlog.Logger(t))
+	if err != nil {
+		t.Fatalf("test setup err: %v", err)
+	}
+	fr.Compress = true
+
+	str := "abcdefghij"
+	if _, err := fr.Write([]byte(str)); err != nil {
+		t.Fatalf("got error while writing: %v", err)
+	}
+
+	// The oldest files are purged, the rotated files are compressed and the
+	// file being written is not
+	expected := map[string]string{
+		"redis.stdout.2.gz": "ef",
+		"redis.stdout.3.gz": "gh",
+		"redis.stdout.4":    "ij",
+	}
+	var lastErr error
+	testutil.WaitForResult(func() (bool, error) {
+		f, err := ioutil.ReadDir(path)
+		if err != nil {
+			lastErr = fmt.Errorf("test error: %v", err)
+			return false, nil
+		}
+		if len(f) != len(expected) {
+			lastErr = fmt.Errorf("expected number of files: %v, got: %v", len(expected), len(f))
+			return false, nil
+		}
+
+		for name, content := range expected {
+			actual, err := readLogFile(filepath.Join(path, name))
+			if err != nil {
+				lastErr = fmt.Errorf("error reading %v: %v", name, err)
+				return false, nil
+			}
+			if actual != content {
+				lastErr = fmt.Errorf("expected %q in %v, got %q", content, name, actual)
+				return false, nil
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("%v", lastErr)
+	})
+	fr.Close()
+
+	// A compressed file is never reopened for writing
+	if err := os.Remove(filepath.Join(path, "redis.stdout.4")); err != nil {
+		t.Fatalf("test setup err: %v", err)
+	}
+	fr, err = NewFileRotator(path, baseFileName, 3, 2, testlog.Logger(t))
+	if err != nil {
+		t.Fatalf("test setup err: %v", err)
+	}
+	defer fr.Close()
+
+	fname := filepath.Join(path, "redis.stdout.4")
+	if fr.currentFile.Name() != fname {
+		t.Fatalf("expected current file: %v, got: %v", fname, fr.currentFile.Name())
+	}
+}
+
+// readLogFile returns the content of a log file, decompressing it if needed
+func readLogFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if filepath.Ext(path) != CompressedLogSuffix {
+		b, err := ioutil.ReadAll(f)
+		return string(b), err
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return "", err
+	}
+	b, err := ioutil.ReadAll(gz)
+	return string(b), err
+}
+
 func BenchmarkRotator(b *testing.B) {
 	kb := 1024
 	for _, inputSize := range []int{kb, 2 * kb, 4 * kb, 8 * kb, 16 * kb, 32 * kb, 64 * kb, 128 * kb, 256 * kb} {
@@ -0,0 +1,456 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// StreamStdout and StreamStderr are the streams a log record is read from
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+
+	// maxLineSize is the size a partial line is allowed to grow before it is
+	// shipped without waiting for its end
+	maxLineSize = 64 * 1024
+
+	// defaultSinkBatchSize, defaultSinkBatchWait and defaultSinkBufferSize
+	// are used for sinks that don't configure their batching
+	defaultSinkBatchSize  = 100
+	defaultSinkBatchWait  = 1 * time.Second
+	defaultSinkBufferSize = 10000
+
+	// sinkRetryMin and sinkRetryMax bound the backoff between attempts to
+	// ship a batch to an unavailable destination
+	sinkRetryMin = 1 * time.Second
+	sinkRetryMax = 30 * time.Second
+
+	// sinkFlushTimeout is how long buffered records are still shipped after
+	// the shipper is closed
+	sinkFlushTimeout = 5 * time.Second
+)
+
+// LogRecord is a line of task output shipped to a sink
+type LogRecord struct {
+	// Time is when the line was read from the task
+	Time time.Time
+
+	// Stream is the stream the line was read from, stdout or stderr
+	Stream string
+
+	// Message is the line without its trailing newline
+	Message string
+}
+
+// SinkContext identifies the task whose logs are shipped
+type SinkContext struct {
+	AllocID   string
+	JobName   string
+	TaskGroup string
+	TaskName  string
+}
+
+// Sink ships batches of log records to a destination
+type Sink interface {
+	// Send ships a batch of records. The batch is retried if an error is
+	// returned, unless the error is a permanent sink error.
+	Send(records []*LogRecord) error
+
+	// Close closes the connection to the destination
+	Close() error
+}
+
+// permanentSinkError is returned by a sink when a batch can never be
+// shipped, so it is dropped rather than retried
+type permanentSinkError struct {
+	err error
+}
+
+func (e *permanentSinkError) Error() string {
+	return e.err.Error()
+}
+
+// retryAfterSinkError is returned by a sink when the destination asked to
+// be retried after a given duration
+type retryAfterSinkError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryAfterSinkError) Error() string {
+	return e.err.Error()
+}
+
+// NewSink returns the sink shipping to the configured destination
+func NewSink(config *structs.LogSink, ctx *SinkContext) (Sink, error) {
+	switch config.Type {
+	case structs.LogSinkTypeSyslog:
+		return newSyslogSink(config, ctx)
+	case structs.LogSinkTypeJournald:
+		return newJournaldSink(config, ctx)
+	case structs.LogSinkTypeFluentd:
+		return newFluentdSink(config, ctx)
+	case structs.LogSinkTypeHTTP:
+		return newHTTPSink(config, ctx)
+	default:
+		return nil, fmt.Errorf("unknown log sink type %q", config.Type)
+	}
+}
+
+// sinkTag returns the tag of the task logs at the destination
+func sinkTag(config *structs.LogSink, ctx *SinkContext) string {
+	if config.Tag != "" {
+		return config.Tag
+	}
+	return ctx.TaskName
+}
+
+// LogShipper ships the lines written to its stream writers to the configured
+// sinks. Each sink has a bounded buffer so a slow or unavailable destination
+// never blocks the task: lines are dropped once the buffer is full. The
+// local log files are written independently of the shipper.
+type LogShipper struct {
+	ctx     *SinkContext
+	configs []*structs.LogSink
+	sinks   []*sinkShipper
+	lock    sync.RWMutex
+
+	logger *log.Logger
+}
+
+// NewLogShipper returns a shipper for the logs of the task to the sinks
+func NewLogShipper(logger *log.Logger, ctx *SinkContext, sinks []*structs.LogSink) (*LogShipper, error) {
+	s := &LogShipper{
+		ctx:    ctx,
+		logger: logger,
+	}
+	if err := s.UpdateSinks(sinks); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Writer returns a writer for the given stream. Complete lines written to it
+// are shipped to the sinks. Writes never fail nor block on the sinks.
+func (s *LogShipper) Writer(stream string) io.Writer {
+	return &lineWriter{shipper: s, stream: stream}
+}
+
+// UpdateSinks replaces the sinks logs are shipped to if they changed.
+// Records buffered for the previous sinks are flushed in the background.
+func (s *LogShipper) UpdateSinks(configs []*structs.LogSink) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if reflect.DeepEqual(s.configs, configs) {
+		return nil
+	}
+
+	sinks := make([]*sinkShipper, 0, len(configs))
+	for _, config := range configs {
+		sink, err := NewSink(config, s.ctx)
+		if err != nil {
+			for _, started := range sinks {
+				go started.Close()
+			}
+			return fmt.Errorf("failed to create %s log sink: %v", config.Type, err)
+		}
+		sinks = append(sinks, newSinkShipper(s.logger, config, sink))
+	}
+
+	for _, old := range s.sinks {
+		go old.Close()
+	}
+	s.configs = configs
+	s.sinks = sinks
+	return nil
+}
+
+// Close stops shipping logs, flushing the buffered records for up to a short
+// timeout.
+func (s *LogShipper) Close() {
+	s.lock.Lock()
+	sinks := s.sinks
+	s.sinks = nil
+	s.configs = nil
+	s.lock.Unlock()
+
+	var wg sync.WaitGroup
+	for _, sink := range sinks {
+		wg.Add(1)
+		go func(sink *sinkShipper) {
+			defer wg.Done()
+			sink.Close()
+		}(sink)
+	}
+	wg.Wait()
+}
+
+// ship queues the record to every sink
+func (s *LogShipper) ship(r *LogRecord) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, sink := range s.sinks {
+		sink.enqueue(r)
+	}
+}
+
+// hasSinks returns whether logs are shipped to any sink
+func (s *LogShipper) hasSinks() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.sinks) != 0
+}
+
+// lineWriter splits the output of a stream into lines and ships them
+type lineWriter struct {
+	shipper *LogShipper
+	stream  string
+	buf     []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	if !w.shipper.hasSinks() {
+		w.buf = w.buf[:0]
+		return len(p), nil
+	}
+
+	n := len(p)
+	now := time.Now()
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.buf = append(w.buf, p...)
+			if len(w.buf) >= maxLineSize {
+				w.flush(now)
+			}
+			break
+		}
+
+		w.buf = append(w.buf, p[:i]...)
+		w.flush(now)
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// flush ships the buffered line
+func (w *lineWriter) flush(now time.Time) {
+	msg := bytes.TrimSuffix(w.buf, []byte{'\r'})
+	w.shipper.ship(&LogRecord{
+		Time:    now,
+		Stream:  w.stream,
+		Message: string(msg),
+	})
+	w.buf = w.buf[:0]
+}
+
+// sinkShipper ships the records queued for a sink in batches
+type sinkShipper struct {
+	// dropped is the number of records dropped since the last report. It
+	// is first to be 64-bit aligned for atomic operations.
+	dropped uint64
+
+	sink      Sink
+	sinkType  string
+	batchSize int
+	batchWait time.Duration
+
+	records chan *LogRecord
+
+	closeCh  chan struct{}
+	doneCh   chan struct{}
+	closeOne sync.Once
+
+	logger *log.Logger
+}
+
+func newSinkShipper(logger *log.Logger, config *structs.LogSink, sink Sink) *sinkShipper {
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSinkBatchSize
+	}
+	bufferSize := config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultSinkBufferSize
+	}
+
+	// Only the HTTP sink holds records to fill a batch, the others ship
+	// whatever is buffered right away
+	var batchWait time.Duration
+	if config.Type == structs.LogSinkTypeHTTP {
+		batchWait = config.BatchWait
+		if batchWait <= 0 {
+			batchWait = defaultSinkBatchWait
+		}
+	}
+
+	s := &sinkShipper{
+		sink:      sink,
+		sinkType:  config.Type,
+		batchSize: batchSize,
+		batchWait: batchWait,
+		records:   make(chan *LogRecord, bufferSize),
+		closeCh:   make(chan struct{}),
+		doneCh:    make(chan struct{}),
+		logger:    logger,
+	}
+	go s.run()
+	return s
+}
+
+// enqueue buffers the record, dropping it if the buffer is full
+func (s *sinkShipper) enqueue(r *LogRecord) {
+	select {
+	case <-s.closeCh:
+	case s.records <- r:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// Close stops the shipper once the buffered records are flushed or the
+// flush timeout is reached.
+func (s *sinkShipper) Close() {
+	s.closeOne.Do(func() { close(s.closeCh) })
+	<-s.doneCh
+}
+
+func (s *sinkShipper) run() {
+	defer close(s.doneCh)
+	defer s.sink.Close()
+
+	var deadline <-chan time.Time
+	for {
+		batch, open := s.nextBatch()
+		if !open && deadline == nil {
+			deadline = time.After(sinkFlushTimeout)
+		}
+		if len(batch) != 0 && !s.send(batch, deadline) {
+			s.reportDropped(len(s.records))
+			return
+		}
+		if !open && len(s.records) == 0 {
+			s.reportDropped(0)
+			return
+		}
+	}
+}
+
+// nextBatch waits for a batch of records. It returns false once the shipper
+// is closed, along with the records left to flush.
+func (s *sinkShipper) nextBatch() ([]*LogRecord, bool) {
+	var batch []*LogRecord
+
+	// Wait for the first record
+	select {
+	case r := <-s.records:
+		batch = append(batch, r)
+	case <-s.closeCh:
+		return s.drain(batch), false
+	}
+
+	// Fill the batch, holding it for up to the batch wait
+	var wait <-chan time.Time
+	if s.batchWait > 0 {
+		timer := time.NewTimer(s.batchWait)
+		defer timer.Stop()
+		wait = timer.C
+	}
+	for len(batch) < s.batchSize {
+		if wait == nil {
+			select {
+			case r := <-s.records:
+				batch = append(batch, r)
+				continue
+			default:
+				return batch, true
+			}
+		}
+
+		select {
+		case r := <-s.records:
+			batch = append(batch, r)
+		case <-wait:
+			return batch, true
+		case <-s.closeCh:
+			return s.drain(batch), false
+		}
+	}
+	return batch, true
+}
+
+// drain fills the batch with the buffered records
+func (s *sinkShipper) drain(batch []*LogRecord) []*LogRecord {
+	for len(batch) < s.batchSize {
+		select {
+		case r := <-s.records:
+			batch = append(batch, r)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// send ships the batch, retrying with a backoff while the destination is
+// unavailable. It returns false if the shipper should stop because the
+// deadline was reached.
+func (s *sinkShipper) send(batch []*LogRecord, deadline <-chan time.Time) bool {
+	backoff := sinkRetryMin
+	for {
+		err := s.sink.Send(batch)
+		if err == nil {
+			s.reportDropped(0)
+			return true
+		}
+
+		switch e := err.(type) {
+		case *permanentSinkError:
+			s.logger.Printf("[WARN] logging.sink: dropping %d log lines rejected by %s sink: %v", len(batch), s.sinkType, err)
+			return true
+		case *retryAfterSinkError:
+			if e.retryAfter > 0 {
+				backoff = e.retryAfter
+			}
+		}
+
+		s.logger.Printf("[WARN] logging.sink: failed to ship logs to %s sink, retrying in %v: %v", s.sinkType, backoff, err)
+
+		// Start the flush deadline if closed while retrying
+		var closeCh <-chan struct{}
+		if deadline == nil {
+			closeCh = s.closeCh
+		}
+		select {
+		case <-time.After(backoff):
+		case <-closeCh:
+			deadline = time.After(sinkFlushTimeout)
+		case <-deadline:
+			atomic.AddUint64(&s.dropped, uint64(len(batch)))
+			return false
+		}
+
+		backoff *= 2
+		if backoff > sinkRetryMax {
+			backoff = sinkRetryMax
+		}
+	}
+}
+
+// reportDropped logs the number of records dropped since the last report
+// along with the given number of records abandoned in the buffer.
+func (s *sinkShipper) reportDropped(abandoned int) {
+	dropped := atomic.SwapUint64(&s.dropped, 0) + uint64(abandoned)
+	if dropped != 0 {
+		s.logger.Printf("[WARN] logging.sink: dropped %d log lines that could not be shipped to %s sink", dropped, s.sinkType)
+	}
+}
@@ -0,0 +1,437 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var testSinkContext = &SinkContext{
+	AllocID:   "1234",
+	JobName:   "example",
+	TaskGroup: "cache",
+	TaskName:  "redis",
+}
+
+// writeLines writes the lines to the stream writers of the shipper, split
+// across writes to exercise the line buffering.
+func writeLines(t *testing.T, s *LogShipper) {
+	stdout := s.Writer(StreamStdout)
+	stderr := s.Writer(StreamStderr)
+
+	_, err := stdout.Write([]byte("hello "))
+	require.NoError(t, err)
+	_, err = stdout.Write([]byte("world\nsecond line\n"))
+	require.NoError(t, err)
+	_, err = stderr.Write([]byte("an error\n"))
+	require.NoError(t, err)
+}
+
+func TestLogShipper_Syslog(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "nomadtest-syslogsink")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer tcp.Close()
+
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer udp.Close()
+
+	unixPath := filepath.Join(dir, "syslog.sock")
+	unix, err := net.Listen("unix", unixPath)
+	require.NoError(t, err)
+	defer unix.Close()
+
+	// Collect the lines received by the stream listeners
+	acceptLines := func(l net.Listener) <-chan string {
+		lines := make(chan string, 10)
+		go func() {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+		}()
+		return lines
+	}
+	tcpLines := acceptLines(tcp)
+	unixLines := acceptLines(unix)
+
+	udpLines := make(chan string, 10)
+	go func() {
+		buf := make([]byte, 64*1024)
+		for {
+			n, _, err := udp.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			udpLines <- strings.TrimSpace(string(buf[:n]))
+		}
+	}()
+
+	s, err := NewLogShipper(testlog.Logger(t), testSinkContext, []*structs.LogSink{
+		{Type: structs.LogSinkTypeSyslog, Address: "tcp://" + tcp.Addr().String()},
+		{Type: structs.LogSinkTypeSyslog, Address: "udp://" + udp.LocalAddr().String(), Tag: "web"},
+		{Type: structs.LogSinkTypeSyslog, Address: "unix://" + unixPath},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	writeLines(t, s)
+
+	for name, lines := range map[string]<-chan string{"tcp": tcpLines, "udp": udpLines, "unix": unixLines} {
+		tag := "redis"
+		if name == "udp" {
+			tag = "web"
+		}
+
+		var received []string
+		for i := 0; i < 3; i++ {
+			select {
+			case line := <-lines:
+				received = append(received, line)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for %s syslog lines, received: %v", name, received)
+			}
+		}
+
+		// Syslog priorities are facility*8 + severity
+		require.Contains(t, received[0], "<14> ")
+		require.Contains(t, received[0], tag+"[")
+		require.True(t, strings.HasSuffix(received[0], ": hello world"), "%s: %q", name, received[0])
+		require.True(t, strings.HasSuffix(received[1], ": second line"), "%s: %q", name, received[1])
+		require.Contains(t, received[2], "<11> ")
+		require.True(t, strings.HasSuffix(received[2], ": an error"), "%s: %q", name, received[2])
+	}
+}
+
+func TestLogShipper_Journald(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "nomadtest-journaldsink")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	s, err := NewLogShipper(testlog.Logger(t), testSinkContext, []*structs.LogSink{
+		{Type: structs.LogSinkTypeJournald, Address: "unixgram://" + path},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	writeLines(t, s)
+
+	var entries []string
+	buf := make([]byte, 64*1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for i := 0; i < 3; i++ {
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+		entries = append(entries, string(buf[:n]))
+	}
+
+	require.Contains(t, entries[0], "MESSAGE=hello world\n")
+	require.Contains(t, entries[0], "PRIORITY=6\n")
+	require.Contains(t, entries[0], "SYSLOG_IDENTIFIER=redis\n")
+	require.Contains(t, entries[0], "NOMAD_ALLOC_ID=1234\n")
+	require.Contains(t, entries[0], "NOMAD_JOB_NAME=example\n")
+	require.Contains(t, entries[1], "MESSAGE=second line\n")
+	require.Contains(t, entries[2], "MESSAGE=an error\n")
+	require.Contains(t, entries[2], "PRIORITY=3\n")
+}
+
+func TestLogShipper_Fluentd(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	type entry struct {
+		tag    string
+		record map[string]interface{}
+	}
+	entries := make(chan entry, 10)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		dec := codec.NewDecoder(conn, &codec.MsgpackHandle{RawToString: true})
+		for {
+			var msg []interface{}
+			if err := dec.Decode(&msg); err != nil {
+				return
+			}
+			tag := msg[0].(string)
+			for _, e := range msg[1].([]interface{}) {
+				record := e.([]interface{})[1].(map[interface{}]interface{})
+				converted := make(map[string]interface{}, len(record))
+				for k, v := range record {
+					converted[k.(string)] = v
+				}
+				entries <- entry{tag: tag, record: converted}
+			}
+		}
+	}()
+
+	s, err := NewLogShipper(testlog.Logger(t), testSinkContext, []*structs.LogSink{
+		{Type: structs.LogSinkTypeFluentd, Address: l.Addr().String(), Tag: "nomad.redis"},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	writeLines(t, s)
+
+	var received []entry
+	for i := 0; i < 3; i++ {
+		select {
+		case e := <-entries:
+			received = append(received, e)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for fluentd records, received: %v", received)
+		}
+	}
+
+	require.Equal(t, "nomad.redis", received[0].tag)
+	require.Equal(t, "hello world", received[0].record["log"])
+	require.Equal(t, "stdout", received[0].record["source"])
+	require.Equal(t, "1234", received[0].record["alloc_id"])
+	require.Equal(t, "redis", received[0].record["task_name"])
+	require.Equal(t, "second line", received[1].record["log"])
+	require.Equal(t, "an error", received[2].record["log"])
+	require.Equal(t, "stderr", received[2].record["source"])
+}
+
+func TestLogShipper_HTTP_Batching(t *testing.T) {
+	t.Parallel()
+
+	var lock sync.Mutex
+	var batches [][]*httpLogRecord
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []*httpLogRecord
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		lock.Lock()
+		batches = append(batches, batch)
+		lock.Unlock()
+	}))
+	defer ts.Close()
+
+	s, err := NewLogShipper(testlog.Logger(t), testSinkContext, []*structs.LogSink{
+		{
+			Type:      structs.LogSinkTypeHTTP,
+			Address:   ts.URL,
+			BatchSize: 2,
+			BatchWait: 100 * time.Millisecond,
+		},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	writeLines(t, s)
+
+	// Three lines are shipped as a full batch and a partial batch sent once
+	// the batch wait elapsed
+	testutil.WaitForResult(func() (bool, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if len(batches) != 2 {
+			return false, fmt.Errorf("expected 2 batches, got %d", len(batches))
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, batches[0], 2)
+	require.Len(t, batches[1], 1)
+	require.Equal(t, "hello world", batches[0][0].Message)
+	require.Equal(t, "stdout", batches[0][0].Stream)
+	require.Equal(t, "redis", batches[0][0].Tag)
+	require.Equal(t, "1234", batches[0][0].AllocID)
+	require.Equal(t, "cache", batches[0][0].GroupName)
+	require.Equal(t, "second line", batches[0][1].Message)
+	require.Equal(t, "an error", batches[1][0].Message)
+	require.Equal(t, "stderr", batches[1][0].Stream)
+}
+
+func TestLogShipper_HTTP_Backpressure(t *testing.T) {
+	t.Parallel()
+
+	// The endpoint asks to be retried until it is unblocked
+	var blocked int32 = 1
+	var received int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&blocked) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		var batch []*httpLogRecord
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		atomic.AddInt32(&received, int32(len(batch)))
+	}))
+	defer ts.Close()
+
+	s, err := NewLogShipper(testlog.Logger(t), testSinkContext, []*structs.LogSink{
+		{
+			Type:       structs.LogSinkTypeHTTP,
+			Address:    ts.URL,
+			BatchSize:  5,
+			BatchWait:  10 * time.Millisecond,
+			BufferSize: 10,
+		},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	// Writing more lines than can be buffered doesn't block
+	w := s.Writer(StreamStdout)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			fmt.Fprintf(w, "line %d\n", i)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("writes blocked on an unavailable sink")
+	}
+
+	// The batch being retried and the buffered lines are shipped once the
+	// endpoint is unblocked, the others were dropped
+	atomic.StoreInt32(&blocked, 0)
+	testutil.WaitForResult(func() (bool, error) {
+		if n := atomic.LoadInt32(&received); n < 10 {
+			return false, fmt.Errorf("expected at least 10 lines, got %d", n)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+	time.Sleep(100 * time.Millisecond)
+	require.True(t, atomic.LoadInt32(&received) <= 15)
+}
+
+func TestLogShipper_HTTP_Rejected(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	s, err := NewLogShipper(testlog.Logger(t), testSinkContext, []*structs.LogSink{
+		{Type: structs.LogSinkTypeHTTP, Address: ts.URL, BatchSize: 1},
+	})
+	require.NoError(t, err)
+
+	writeLines(t, s)
+
+	// Rejected batches are dropped rather than retried
+	testutil.WaitForResult(func() (bool, error) {
+		if n := atomic.LoadInt32(&requests); n != 3 {
+			return false, fmt.Errorf("expected 3 requests, got %d", n)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+	s.Close()
+	require.EqualValues(t, 3, atomic.LoadInt32(&requests))
+}
+
+func TestLogShipper_UpdateSinks(t *testing.T) {
+	t.Parallel()
+
+	newServer := func() (*httptest.Server, *int32) {
+		var received int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var batch []*httpLogRecord
+			if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			atomic.AddInt32(&received, int32(len(batch)))
+		}))
+		return ts, &received
+	}
+	ts1, received1 := newServer()
+	defer ts1.Close()
+	ts2, received2 := newServer()
+	defer ts2.Close()
+
+	// Lines written without sinks aren't shipped
+	s, err := NewLogShipper(testlog.Logger(t), testSinkContext, nil)
+	require.NoError(t, err)
+	defer s.Close()
+	fmt.Fprintf(s.Writer(StreamStdout), "dropped\n")
+
+	sink := &structs.LogSink{Type: structs.LogSinkTypeHTTP, Address: ts1.URL, BatchWait: 10 * time.Millisecond}
+	require.NoError(t, s.UpdateSinks([]*structs.LogSink{sink}))
+	fmt.Fprintf(s.Writer(StreamStdout), "first\n")
+
+	waitReceived := func(received *int32, expected int32) {
+		testutil.WaitForResult(func() (bool, error) {
+			if n := atomic.LoadInt32(received); n != expected {
+				return false, fmt.Errorf("expected %d lines, got %d", expected, n)
+			}
+			return true, nil
+		}, func(err error) {
+			t.Fatalf("err: %v", err)
+		})
+	}
+	waitReceived(received1, 1)
+
+	// Updating with the same sinks is a noop, other sinks replace them
+	require.NoError(t, s.UpdateSinks([]*structs.LogSink{sink.Copy()}))
+	sink2 := &structs.LogSink{Type: structs.LogSinkTypeHTTP, Address: ts2.URL, BatchWait: 10 * time.Millisecond}
+	require.NoError(t, s.UpdateSinks([]*structs.LogSink{sink2}))
+	fmt.Fprintf(s.Writer(StreamStdout), "second\n")
+
+	waitReceived(received2, 1)
+	require.EqualValues(t, 1, atomic.LoadInt32(received1))
+}
@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"bytes"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// fluentdTimeout is the timeout to connect and write to fluentd
+	fluentdTimeout = 10 * time.Second
+)
+
+// fluentdSink ships log records to a fluentd forward input. Batches are sent
+// in the forward mode of the protocol as [tag, [[time, record], ...]].
+type fluentdSink struct {
+	network string
+	addr    string
+	tag     string
+	ctx     *SinkContext
+
+	conn   net.Conn
+	handle *codec.MsgpackHandle
+}
+
+func newFluentdSink(config *structs.LogSink, ctx *SinkContext) (*fluentdSink, error) {
+	network, addr := "tcp", config.Address
+	if strings.Contains(config.Address, "://") {
+		u, err := url.Parse(config.Address)
+		if err != nil {
+			return nil, err
+		}
+		network, addr = u.Scheme, u.Host
+		if u.Scheme == "unix" {
+			addr = u.Path
+		}
+	}
+
+	return &fluentdSink{
+		network: network,
+		addr:    addr,
+		tag:     sinkTag(config, ctx),
+		ctx:     ctx,
+		handle:  &codec.MsgpackHandle{WriteExt: true},
+	}, nil
+}
+
+func (s *fluentdSink) Send(records []*LogRecord) error {
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.addr, fluentdTimeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	entries := make([]interface{}, len(records))
+	for i, r := range records {
+		entries[i] = []interface{}{
+			r.Time.Unix(),
+			map[string]string{
+				"log":        r.Message,
+				"source":     r.Stream,
+				"alloc_id":   s.ctx.AllocID,
+				"job_name":   s.ctx.JobName,
+				"group_name": s.ctx.TaskGroup,
+				"task_name":  s.ctx.TaskName,
+			},
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, s.handle).Encode([]interface{}{s.tag, entries}); err != nil {
+		return &permanentSinkError{err}
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(fluentdTimeout))
+	if _, err := s.conn.Write(buf.Bytes()); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (s *fluentdSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// httpSinkTimeout is the timeout of a request shipping a batch
+	httpSinkTimeout = 10 * time.Second
+)
+
+// httpLogRecord is the JSON encoding of a log record shipped over HTTP
+type httpLogRecord struct {
+	Time      time.Time `json:"time"`
+	Stream    string    `json:"stream"`
+	Message   string    `json:"message"`
+	Tag       string    `json:"tag"`
+	AllocID   string    `json:"alloc_id"`
+	JobName   string    `json:"job_name"`
+	GroupName string    `json:"group_name"`
+	TaskName  string    `json:"task_name"`
+}
+
+// httpSink ships batches of log records as JSON arrays POSTed to an HTTP
+// endpoint. The endpoint applies backpressure by responding with a 429 or
+// 5xx status, optionally with a Retry-After header, and the batch is retried.
+type httpSink struct {
+	addr   string
+	tag    string
+	ctx    *SinkContext
+	client *http.Client
+}
+
+func newHTTPSink(config *structs.LogSink, ctx *SinkContext) (*httpSink, error) {
+	return &httpSink{
+		addr:   config.Address,
+		tag:    sinkTag(config, ctx),
+		ctx:    ctx,
+		client: &http.Client{Timeout: httpSinkTimeout},
+	}, nil
+}
+
+func (s *httpSink) Send(records []*LogRecord) error {
+	batch := make([]*httpLogRecord, len(records))
+	for i, r := range records {
+		batch[i] = &httpLogRecord{
+			Time:      r.Time,
+			Stream:    r.Stream,
+			Message:   r.Message,
+			Tag:       s.tag,
+			AllocID:   s.ctx.AllocID,
+			JobName:   s.ctx.JobName,
+			GroupName: s.ctx.TaskGroup,
+			TaskName:  s.ctx.TaskName,
+		}
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return &permanentSinkError{err}
+	}
+
+	resp, err := s.client.Post(s.addr, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		err := fmt.Errorf("unexpected response code %d", resp.StatusCode)
+		if secs, perr := strconv.Atoi(resp.Header.Get("Retry-After")); perr == nil && secs > 0 {
+			return &retryAfterSinkError{err: err, retryAfter: time.Duration(secs) * time.Second}
+		}
+		return err
+	default:
+		return &permanentSinkError{fmt.Errorf("unexpected response code %d", resp.StatusCode)}
+	}
+}
+
+func (s *httpSink) Close() error {
+	return nil
+}
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// journaldSocket is the socket of the local journal
+	journaldSocket = "/run/systemd/journal/socket"
+
+	// journaldPriorityErr and journaldPriorityInfo are the syslog
+	// priorities of lines read from stderr and stdout
+	journaldPriorityErr  = "3"
+	journaldPriorityInfo = "6"
+)
+
+// journaldSink ships log records to the journal using its native protocol.
+// The task is identified by the SYSLOG_IDENTIFIER field and the NOMAD_*
+// fields of the entries.
+type journaldSink struct {
+	addr   *net.UnixAddr
+	conn   *net.UnixConn
+	fields map[string]string
+}
+
+func newJournaldSink(config *structs.LogSink, ctx *SinkContext) (*journaldSink, error) {
+	path := journaldSocket
+	if config.Address != "" {
+		u, err := url.Parse(config.Address)
+		if err != nil {
+			return nil, err
+		}
+		path = u.Path
+	}
+
+	return &journaldSink{
+		addr: &net.UnixAddr{Name: path, Net: "unixgram"},
+		fields: map[string]string{
+			"SYSLOG_IDENTIFIER": sinkTag(config, ctx),
+			"NOMAD_ALLOC_ID":    ctx.AllocID,
+			"NOMAD_JOB_NAME":    ctx.JobName,
+			"NOMAD_GROUP_NAME":  ctx.TaskGroup,
+			"NOMAD_TASK_NAME":   ctx.TaskName,
+		},
+	}, nil
+}
+
+func (s *journaldSink) Send(records []*LogRecord) error {
+	if s.conn == nil {
+		conn, err := net.DialUnix("unixgram", nil, s.addr)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	for _, r := range records {
+		if _, err := s.conn.Write(s.entry(r)); err != nil {
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+// entry serializes the record as a journal entry
+func (s *journaldSink) entry(r *LogRecord) []byte {
+	var buf bytes.Buffer
+	priority := journaldPriorityInfo
+	if r.Stream == StreamStderr {
+		priority = journaldPriorityErr
+	}
+	appendJournaldField(&buf, "MESSAGE", r.Message)
+	appendJournaldField(&buf, "PRIORITY", priority)
+	for k, v := range s.fields {
+		if v != "" {
+			appendJournaldField(&buf, k, v)
+		}
+	}
+	return buf.Bytes()
+}
+
+// appendJournaldField serializes a field of a journal entry. Values with a
+// newline are serialized along with their length.
+func appendJournaldField(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	if !strings.ContainsRune(value, '\n') {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+func (s *journaldSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
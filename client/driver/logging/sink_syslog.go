@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"net/url"
+
+	syslog "github.com/RackSec/srslog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// syslogSink ships log records to a syslog server. Lines read from stderr
+// are sent with the error severity, the others with the info severity.
+type syslogSink struct {
+	network string
+	addr    string
+	tag     string
+
+	// writer is the connection to the syslog server. It is dialed on the
+	// first send so an unavailable server doesn't fail the task.
+	writer *syslog.Writer
+}
+
+func newSyslogSink(config *structs.LogSink, ctx *SinkContext) (*syslogSink, error) {
+	u, err := url.Parse(config.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := u.Host
+	if u.Scheme == "unix" || u.Scheme == "unixgram" {
+		addr = u.Path
+	}
+
+	return &syslogSink{
+		network: u.Scheme,
+		addr:    addr,
+		tag:     sinkTag(config, ctx),
+	}, nil
+}
+
+func (s *syslogSink) Send(records []*LogRecord) error {
+	if s.writer == nil {
+		w, err := syslog.Dial(s.network, s.addr, syslog.LOG_INFO|syslog.LOG_USER, s.tag)
+		if err != nil {
+			return err
+		}
+		s.writer = w
+	}
+
+	for _, r := range records {
+		var err error
+		if r.Stream == StreamStderr {
+			err = s.writer.Err(r.Message)
+		} else {
+			err = s.writer.Info(r.Message)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syslogSink) Close() error {
+	if s.writer == nil {
+		return nil
+	}
+	return s.writer.Close()
+}
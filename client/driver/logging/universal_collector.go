@@ -22,6 +22,12 @@ type LogCollectorContext struct {
 	// TaskName is the name of the Task
 	TaskName string
 
+	// AllocID, JobName and TaskGroup identify the allocation of the task
+	// whose logs are shipped to the log sinks
+	AllocID   string
+	JobName   string
+	TaskGroup string
+
 	// AllocDir is the handle to do operations on the alloc dir of
 	// the task
 	AllocDir *allocdir.AllocDir
@@ -60,6 +66,7 @@ type SyslogCollector struct {
 
 	lro        *FileRotator
 	lre        *FileRotator
+	shipper    *LogShipper
 	server     *SyslogServer
 	syslogChan chan *SyslogMessage
 	taskDir    string
@@ -98,6 +105,8 @@ func (s *SyslogCollector) LaunchCollector(ctx *LogCollectorContext) (*SyslogColl
 	if err != nil {
 		return nil, err
 	}
+	lro.RotateInterval = ctx.LogConfig.RotateInterval
+	lro.Compress = ctx.LogConfig.Compress
 	s.lro = lro
 
 	lre, err := NewFileRotator(logdir, fmt.Sprintf("%v.stderr", ctx.TaskName),
@@ -105,9 +114,23 @@ func (s *SyslogCollector) LaunchCollector(ctx *LogCollectorContext) (*SyslogColl
 	if err != nil {
 		return nil, err
 	}
+	lre.RotateInterval = ctx.LogConfig.RotateInterval
+	lre.Compress = ctx.LogConfig.Compress
 	s.lre = lre
 
-	go s.collectLogs(lre, lro)
+	shipper, err := NewLogShipper(s.logger, &SinkContext{
+		AllocID:   ctx.AllocID,
+		JobName:   ctx.JobName,
+		TaskGroup: ctx.TaskGroup,
+		TaskName:  ctx.TaskName,
+	}, ctx.LogConfig.Sinks)
+	if err != nil {
+		return nil, err
+	}
+	s.shipper = shipper
+
+	go s.collectLogs(io.MultiWriter(lre, shipper.Writer(StreamStderr)),
+		io.MultiWriter(lro, shipper.Writer(StreamStdout)))
 	syslogAddr := fmt.Sprintf("%s://%s", l.Addr().Network(), l.Addr().String())
 	return &SyslogCollectorState{Addr: syslogAddr}, nil
 }
@@ -117,11 +140,11 @@ func (s *SyslogCollector) collectLogs(we io.Writer, wo io.Writer) {
 		// If the severity of the log line is err then we write to stderr
 		// otherwise all messages go to stdout
 		if logParts.Severity == syslog.LOG_ERR {
-			s.lre.Write(logParts.Message)
-			s.lre.Write([]byte{'\n'})
+			we.Write(logParts.Message)
+			we.Write([]byte{'\n'})
 		} else {
-			s.lro.Write(logParts.Message)
-			s.lro.Write([]byte{'\n'})
+			wo.Write(logParts.Message)
+			wo.Write([]byte{'\n'})
 		}
 	}
 }
@@ -131,6 +154,7 @@ func (s *SyslogCollector) Exit() error {
 	s.server.Shutdown()
 	s.lre.Close()
 	s.lro.Close()
+	s.shipper.Close()
 	return nil
 }
 
@@ -142,13 +166,21 @@ func (s *SyslogCollector) UpdateLogConfig(logConfig *structs.LogConfig) error {
 	}
 	s.lro.MaxFiles = logConfig.MaxFiles
 	s.lro.FileSize = int64(logConfig.MaxFileSizeMB * 1024 * 1024)
+	s.lro.RotateInterval = logConfig.RotateInterval
+	s.lro.Compress = logConfig.Compress
 
 	if s.lre == nil {
 		return fmt.Errorf("log rotator for stderr doesn't exist")
 	}
 	s.lre.MaxFiles = logConfig.MaxFiles
 	s.lre.FileSize = int64(logConfig.MaxFileSizeMB * 1024 * 1024)
-	return nil
+	s.lre.RotateInterval = logConfig.RotateInterval
+	s.lre.Compress = logConfig.Compress
+
+	if s.shipper == nil {
+		return fmt.Errorf("log shipper doesn't exist")
+	}
+	return s.shipper.UpdateSinks(logConfig.Sinks)
 }
 
 // configureTaskDir sets the task dir in the SyslogCollector
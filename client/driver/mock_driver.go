@@ -121,8 +121,9 @@ func NewMockDriver(ctx *DriverContext) Driver {
 
 func (d *MockDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: false,
-		Exec:        true,
+		SendSignals:   false,
+		Exec:          true,
+		ExecStreaming: true,
 	}
 }
 
@@ -366,6 +367,39 @@ func (h *mockDriverHandle) Exec(ctx context.Context, cmd string, args []string)
 	return []byte(fmt.Sprintf("Exec(%q, %q)", cmd, args)), 0, nil
 }
 
+// ExecStreaming echoes the command followed by its input until the input is
+// closed
+func (h *mockDriverHandle) ExecStreaming(ctx context.Context, cmd []string, tty bool,
+	stream dstructs.ExecStream) (*dstructs.ExecStreamingExitResult, error) {
+	h.logger.Printf("[DEBUG] driver.mock: ExecStreaming(%q, %v)", cmd, tty)
+	out := func(data []byte) error {
+		return stream.Send(&dstructs.ExecStreamingResponseMsg{
+			Stdout: &dstructs.ExecStreamingIOOperation{Data: data},
+		})
+	}
+	if err := out([]byte(fmt.Sprintf("ExecStreaming(%q)\n", cmd))); err != nil {
+		return nil, err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if msg.Stdin == nil {
+			continue
+		}
+		if len(msg.Stdin.Data) != 0 {
+			if err := out(msg.Stdin.Data); err != nil {
+				return nil, err
+			}
+		}
+		if msg.Stdin.Close {
+			return &dstructs.ExecStreamingExitResult{}, nil
+		}
+	}
+}
+
 // TODO Implement when we need it.
 func (h *mockDriverHandle) Update(task *structs.Task) error {
 	h.killTimeout = task.KillTimeout
@@ -0,0 +1,524 @@
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-template/signals"
+	"github.com/hashicorp/hcl2/hcl"
+	dstructs "github.com/hashicorp/nomad/client/driver/structs"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/shared"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// pluginDriverFingerprintPeriod is the interval at which driver plugins
+	// are fingerprinted and health checked.
+	pluginDriverFingerprintPeriod = 30 * time.Second
+
+	// pluginDriverFingerprintTimeout is how long to wait for the fingerprint
+	// of a driver plugin.
+	pluginDriverFingerprintTimeout = 10 * time.Second
+
+	// pluginDriverStatsTimeout is how long to wait for the resource usage of
+	// a task run by a driver plugin.
+	pluginDriverStatsTimeout = 5 * time.Second
+
+	// pluginDriverRelaunchTimeout is how long a task waits for its driver
+	// plugin to be relaunched after the plugin exited, before the task is
+	// considered lost.
+	pluginDriverRelaunchTimeout = 2 * time.Minute
+
+	// pluginDriverRelaunchPollInterval is the interval at which a task checks
+	// whether its driver plugin was relaunched.
+	pluginDriverRelaunchPollInterval = time.Second
+)
+
+var (
+	// pluginDrivers contains the drivers implemented by driver plugins,
+	// keyed by the name of the driver.
+	pluginDrivers     = make(map[string]drivers.DriverPlugin)
+	pluginDriversLock sync.RWMutex
+)
+
+// RegisterPluginDriver makes the driver plugin available to run tasks under
+// the given name. Built-in drivers can't be replaced.
+func RegisterPluginDriver(name string, impl drivers.DriverPlugin) error {
+	if _, ok := BuiltinDrivers[name]; ok {
+		return fmt.Errorf("driver %q conflicts with a built-in driver", name)
+	}
+
+	pluginDriversLock.Lock()
+	defer pluginDriversLock.Unlock()
+	if _, ok := pluginDrivers[name]; ok {
+		return fmt.Errorf("driver %q is already registered", name)
+	}
+
+	pluginDrivers[name] = impl
+	return nil
+}
+
+// DeregisterPluginDriver removes the driver plugin registered under the name.
+func DeregisterPluginDriver(name string) {
+	pluginDriversLock.Lock()
+	defer pluginDriversLock.Unlock()
+	delete(pluginDrivers, name)
+}
+
+// PluginDriverNames returns the sorted names of the registered driver
+// plugins.
+func PluginDriverNames() []string {
+	pluginDriversLock.RLock()
+	defer pluginDriversLock.RUnlock()
+
+	names := make([]string, 0, len(pluginDrivers))
+	for name := range pluginDrivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupPluginDriver returns the driver plugin registered under the name.
+func lookupPluginDriver(name string) (drivers.DriverPlugin, bool) {
+	pluginDriversLock.RLock()
+	defer pluginDriversLock.RUnlock()
+	impl, ok := pluginDrivers[name]
+	return impl, ok
+}
+
+// PluginDriver implements the Driver interface on top of a driver plugin, so
+// tasks of driver plugins are run like the ones of built-in drivers.
+type PluginDriver struct {
+	DriverContext
+
+	name string
+	impl drivers.DriverPlugin
+}
+
+// NewPluginDriver returns a Driver running tasks using the driver plugin.
+func NewPluginDriver(name string, impl drivers.DriverPlugin, ctx *DriverContext) Driver {
+	return &PluginDriver{
+		DriverContext: *ctx,
+		name:          name,
+		impl:          impl,
+	}
+}
+
+// fingerprint returns the current fingerprint of the driver plugin.
+func (d *PluginDriver) fingerprint() (*drivers.Fingerprint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pluginDriverFingerprintTimeout)
+	defer cancel()
+
+	ch, err := d.impl.Fingerprint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case f, ok := <-ch:
+		if !ok {
+			return nil, fmt.Errorf("driver %q didn't send a fingerprint", d.name)
+		}
+		if f.Err != nil {
+			return nil, f.Err
+		}
+		return f, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out fingerprinting driver %q", d.name)
+	}
+}
+
+func (d *PluginDriver) Fingerprint(req *cstructs.FingerprintRequest, resp *cstructs.FingerprintResponse) error {
+	f, err := d.fingerprint()
+	if err != nil {
+		return err
+	}
+
+	attr := fmt.Sprintf("driver.%s", d.name)
+	if !f.Detected() {
+		resp.RemoveAttribute(attr)
+		return nil
+	}
+
+	for k, v := range f.Attributes {
+		resp.AddAttribute(k, v)
+	}
+	resp.AddAttribute(attr, "1")
+	resp.Detected = true
+	return nil
+}
+
+func (d *PluginDriver) Periodic() (bool, time.Duration) {
+	return true, pluginDriverFingerprintPeriod
+}
+
+func (d *PluginDriver) HealthCheck(req *cstructs.HealthCheckRequest, resp *cstructs.HealthCheckResponse) error {
+	info := &structs.DriverInfo{
+		UpdateTime: time.Now(),
+	}
+
+	f, err := d.fingerprint()
+	if err != nil {
+		info.HealthDescription = fmt.Sprintf("Failed to fingerprint driver: %v", err)
+		resp.AddDriverInfo(d.name, info)
+		return err
+	}
+
+	info.Healthy = f.Health == drivers.HealthStateHealthy
+	info.HealthDescription = f.HealthDescription
+	resp.AddDriverInfo(d.name, info)
+	return nil
+}
+
+func (d *PluginDriver) GetHealthCheckInterval(req *cstructs.HealthCheckIntervalRequest, resp *cstructs.HealthCheckIntervalResponse) error {
+	resp.Eligible = true
+	resp.Period = pluginDriverFingerprintPeriod
+	return nil
+}
+
+func (d *PluginDriver) Validate(config map[string]interface{}) error {
+	spec, err := d.impl.TaskConfigSchema()
+	if err != nil {
+		return err
+	}
+
+	if spec == nil {
+		return nil
+	}
+
+	_, err = parsePluginDriverConfig(spec, config)
+	return err
+}
+
+// parsePluginDriverConfig validates the driver configuration of a task
+// against the schema of the driver plugin and returns the decoded value.
+func parsePluginDriverConfig(spec *hclspec.Spec, config map[string]interface{}) (val cty.Value, err error) {
+	hclSpec, diag := hclspec.Convert(spec)
+	if diag.HasErrors() {
+		return val, fmt.Errorf("failed to convert the driver config schema: %v", diag)
+	}
+
+	ctx := &hcl.EvalContext{
+		Functions: shared.GetStdlibFuncs(),
+	}
+
+	val, diag = shared.ParseHclInterface(config, hclSpec, ctx)
+	if diag.HasErrors() {
+		return val, fmt.Errorf("invalid driver config: %v", diag)
+	}
+
+	return val, nil
+}
+
+func (d *PluginDriver) Abilities() DriverAbilities {
+	caps, err := d.impl.Capabilities()
+	if err != nil {
+		d.logger.Printf("[ERR] driver.%s: failed to get the capabilities of the driver: %v", d.name, err)
+		return DriverAbilities{}
+	}
+
+	return DriverAbilities{
+		SendSignals: caps.SendSignals,
+		Exec:        caps.Exec,
+	}
+}
+
+func (d *PluginDriver) FSIsolation() cstructs.FSIsolation {
+	caps, err := d.impl.Capabilities()
+	if err != nil {
+		d.logger.Printf("[ERR] driver.%s: failed to get the capabilities of the driver: %v", d.name, err)
+		return cstructs.FSIsolationNone
+	}
+
+	switch caps.FSIsolation {
+	case drivers.FSIsolationChroot:
+		return cstructs.FSIsolationChroot
+	case drivers.FSIsolationImage:
+		return cstructs.FSIsolationImage
+	default:
+		return cstructs.FSIsolationNone
+	}
+}
+
+func (d *PluginDriver) Prestart(*ExecContext, *structs.Task) (*PrestartResponse, error) {
+	return nil, nil
+}
+
+func (d *PluginDriver) Start(ctx *ExecContext, task *structs.Task) (*StartResponse, error) {
+	cfg := &drivers.TaskConfig{
+		ID:            fmt.Sprintf("%s/%s/%s", d.allocID, task.Name, uuid.Generate()[:8]),
+		JobName:       d.jobName,
+		TaskGroupName: d.taskGroupName,
+		Name:          task.Name,
+		AllocID:       d.allocID,
+		Env:           ctx.TaskEnv.Map(),
+		Resources:     task.Resources,
+		User:          task.User,
+		AllocDir:      filepath.Dir(ctx.TaskDir.Dir),
+		LogConfig:     task.LogConfig,
+	}
+
+	spec, err := d.impl.TaskConfigSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	if spec == nil {
+		err = cfg.EncodeConcreteDriverConfig(task.Config)
+	} else {
+		var val cty.Value
+		if val, err = parsePluginDriverConfig(spec, task.Config); err == nil {
+			err = cfg.EncodeDriverConfig(val)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode driver config: %v", err)
+	}
+
+	handle, net, err := d.impl.StartTask(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	maxKill := d.DriverContext.config.MaxKillTimeout
+	h := &pluginDriverHandle{
+		impl:           d.impl,
+		handle:         handle,
+		killTimeout:    GetKillTimeout(task.KillTimeout, maxKill),
+		maxKillTimeout: maxKill,
+		killSignal:     task.KillSignal,
+		logger:         d.logger,
+		name:           d.name,
+		waitCh:         make(chan *dstructs.WaitResult, 1),
+	}
+	go h.run()
+	return &StartResponse{Handle: h, Network: net}, nil
+}
+
+func (d *PluginDriver) Cleanup(*ExecContext, *CreatedResources) error { return nil }
+
+// pluginDriverID is the persisted handle of a task run by a driver plugin.
+type pluginDriverID struct {
+	KillTimeout    time.Duration
+	MaxKillTimeout time.Duration
+	KillSignal     string
+	TaskHandle     []byte
+}
+
+func (d *PluginDriver) Open(ctx *ExecContext, handleID string) (DriverHandle, error) {
+	id := &pluginDriverID{}
+	if err := json.Unmarshal([]byte(handleID), id); err != nil {
+		return nil, fmt.Errorf("Failed to parse handle '%s': %v", handleID, err)
+	}
+
+	handle, err := drivers.DecodeTaskHandle(id.TaskHandle)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode task handle: %v", err)
+	}
+
+	if err := d.impl.RecoverTask(handle); err != nil {
+		return nil, fmt.Errorf("failed to recover task: %v", err)
+	}
+
+	h := &pluginDriverHandle{
+		impl:           d.impl,
+		handle:         handle,
+		killTimeout:    id.KillTimeout,
+		maxKillTimeout: id.MaxKillTimeout,
+		killSignal:     id.KillSignal,
+		logger:         d.logger,
+		name:           d.name,
+		waitCh:         make(chan *dstructs.WaitResult, 1),
+	}
+	go h.run()
+	return h, nil
+}
+
+// pluginDriverHandle is the handle of a task run by a driver plugin. The
+// implementation of the driver is replaced when the task is recovered by a
+// relaunched plugin.
+type pluginDriverHandle struct {
+	impl           drivers.DriverPlugin
+	implLock       sync.Mutex
+	handle         *drivers.TaskHandle
+	killTimeout    time.Duration
+	maxKillTimeout time.Duration
+	killSignal     string
+	logger         *log.Logger
+	name           string
+	waitCh         chan *dstructs.WaitResult
+}
+
+func (h *pluginDriverHandle) taskID() string {
+	return h.handle.Config.ID
+}
+
+// plugin returns the driver plugin running the task.
+func (h *pluginDriverHandle) plugin() drivers.DriverPlugin {
+	h.implLock.Lock()
+	defer h.implLock.Unlock()
+	return h.impl
+}
+
+func (h *pluginDriverHandle) ID() string {
+	handle, err := drivers.EncodeTaskHandle(h.handle)
+	if err != nil {
+		h.logger.Printf("[ERR] driver.%s: failed to encode task handle: %v", h.name, err)
+	}
+
+	id := pluginDriverID{
+		KillTimeout:    h.killTimeout,
+		MaxKillTimeout: h.maxKillTimeout,
+		KillSignal:     h.killSignal,
+		TaskHandle:     handle,
+	}
+
+	data, err := json.Marshal(id)
+	if err != nil {
+		h.logger.Printf("[ERR] driver.%s: failed to marshal ID to JSON: %s", h.name, err)
+	}
+	return string(data)
+}
+
+func (h *pluginDriverHandle) WaitCh() chan *dstructs.WaitResult {
+	return h.waitCh
+}
+
+func (h *pluginDriverHandle) Update(task *structs.Task) error {
+	// Store the updated kill timeout and signal.
+	h.killTimeout = GetKillTimeout(task.KillTimeout, h.maxKillTimeout)
+	h.killSignal = task.KillSignal
+
+	// Update is not possible
+	return nil
+}
+
+func (h *pluginDriverHandle) Exec(ctx context.Context, cmd string, args []string) ([]byte, int, error) {
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+
+	res, err := h.plugin().ExecTask(h.taskID(), append([]string{cmd}, args...), timeout)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	output := append(res.Stdout, res.Stderr...)
+	return output, res.ExitResult.ExitCode, nil
+}
+
+func (h *pluginDriverHandle) Signal(s os.Signal) error {
+	for name, sig := range signals.SignalLookup {
+		if sig == s {
+			return h.plugin().SignalTask(h.taskID(), name)
+		}
+	}
+
+	return fmt.Errorf("signal %v is not supported", s)
+}
+
+func (h *pluginDriverHandle) Kill() error {
+	return h.plugin().StopTask(h.taskID(), h.killTimeout, h.killSignal)
+}
+
+func (h *pluginDriverHandle) Stats() (*cstructs.TaskResourceUsage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pluginDriverStatsTimeout)
+	defer cancel()
+
+	ch, err := h.plugin().TaskStats(ctx, h.taskID(), time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case usage, ok := <-ch:
+		if !ok {
+			return nil, errors.New("driver didn't send the resource usage of the task")
+		}
+		return usage, nil
+	case <-ctx.Done():
+		return nil, errors.New("timed out getting the resource usage of the task")
+	}
+}
+
+func (h *pluginDriverHandle) run() {
+	var result *drivers.ExitResult
+	for {
+		impl := h.plugin()
+		result = h.wait(impl)
+		if status.Code(result.Err) != codes.Unavailable {
+			break
+		}
+
+		// The plugin exited while running the task. Wait for the plugin to
+		// be relaunched and for it to recover the task.
+		h.logger.Printf("[WARN] driver.%s: plugin exited while running task %q, waiting for it to be relaunched", h.name, h.taskID())
+		relaunched, ok := waitPluginDriverRelaunch(h.name, impl, pluginDriverRelaunchTimeout)
+		if !ok {
+			result = &drivers.ExitResult{ExitCode: -1, Err: fmt.Errorf("driver plugin wasn't relaunched: %v", result.Err)}
+			break
+		}
+		if err := relaunched.RecoverTask(h.handle); err != nil {
+			result = &drivers.ExitResult{ExitCode: -1, Err: fmt.Errorf("relaunched driver plugin failed to recover task: %v", err)}
+			break
+		}
+
+		h.implLock.Lock()
+		h.impl = relaunched
+		h.implLock.Unlock()
+		h.logger.Printf("[INFO] driver.%s: task %q recovered by relaunched plugin", h.name, h.taskID())
+	}
+
+	// The task exited so the driver can remove its state
+	if err := h.plugin().DestroyTask(h.taskID(), true); err != nil {
+		h.logger.Printf("[ERR] driver.%s: failed to destroy task %q: %v", h.name, h.taskID(), err)
+	}
+
+	// Send the results
+	h.waitCh <- dstructs.NewWaitResult(result.ExitCode, result.Signal, result.Err)
+	close(h.waitCh)
+}
+
+// wait waits for the task to exit and returns its result.
+func (h *pluginDriverHandle) wait(impl drivers.DriverPlugin) *drivers.ExitResult {
+	ch, err := impl.WaitTask(context.Background(), h.taskID())
+	if err != nil {
+		return &drivers.ExitResult{ExitCode: -1, Err: err}
+	}
+
+	result := <-ch
+	if result == nil {
+		result = &drivers.ExitResult{ExitCode: -1, Err: errors.New("driver didn't send the result of the task")}
+	}
+	return result
+}
+
+// waitPluginDriverRelaunch waits for the driver plugin implementing the named
+// driver to be replaced by a relaunched plugin and returns it.
+func waitPluginDriverRelaunch(name string, exited drivers.DriverPlugin, timeout time.Duration) (drivers.DriverPlugin, bool) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if impl, ok := lookupPluginDriver(name); ok && impl != exited {
+			return impl, true
+		}
+		time.Sleep(pluginDriverRelaunchPollInterval)
+	}
+	return nil, false
+}
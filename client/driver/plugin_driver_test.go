@@ -0,0 +1,114 @@
+package driver
+
+import (
+	"testing"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/stretchr/testify/require"
+)
+
+// testPluginDriver serves the mock driver as a driver plugin over gRPC and
+// registers it under the given name.
+func testPluginDriver(t *testing.T, name string) func() {
+	impl, err := NewBuiltinDriverPlugin("mock_driver", testConfig(t), testlog.Logger(t))
+	require.NoError(t, err)
+
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		base.PluginTypeBase:   &base.PluginBase{Impl: impl},
+		base.PluginTypeDriver: drivers.NewDriverPlugin(impl),
+	})
+
+	raw, err := client.Dispense(base.PluginTypeDriver)
+	require.NoError(t, err)
+	require.NoError(t, RegisterPluginDriver(name, raw.(drivers.DriverPlugin)))
+
+	return func() {
+		DeregisterPluginDriver(name)
+		client.Close()
+		server.Stop()
+	}
+}
+
+func TestPluginDriver_Register(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	cleanup := testPluginDriver(t, "mock_plugin_register")
+	defer cleanup()
+
+	require.Contains(PluginDriverNames(), "mock_plugin_register")
+
+	// Built-in and registered drivers can't be replaced
+	require.Error(RegisterPluginDriver("raw_exec", &drivers.MockDriver{}))
+	require.Error(RegisterPluginDriver("mock_plugin_register", &drivers.MockDriver{}))
+}
+
+func TestPluginDriver_Fingerprint(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	cleanup := testPluginDriver(t, "mock_plugin_fingerprint")
+	defer cleanup()
+
+	d, err := NewDriver("mock_plugin_fingerprint", NewEmptyDriverContext())
+	require.NoError(err)
+
+	var resp cstructs.FingerprintResponse
+	require.NoError(d.Fingerprint(&cstructs.FingerprintRequest{}, &resp))
+	require.True(resp.Detected)
+	require.Equal("1", resp.Attributes["driver.mock_plugin_fingerprint"])
+	require.Equal("1", resp.Attributes["driver.mock_driver"])
+
+	var health cstructs.HealthCheckResponse
+	require.NoError(d.(*PluginDriver).HealthCheck(&cstructs.HealthCheckRequest{}, &health))
+	require.True(health.Drivers["mock_plugin_fingerprint"].Healthy)
+}
+
+func TestPluginDriver_StartOpenWait(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	cleanup := testPluginDriver(t, "mock_plugin_start")
+	defer cleanup()
+
+	task := &structs.Task{
+		Name:   "foo",
+		Driver: "mock_plugin_start",
+		Config: map[string]interface{}{
+			"run_for":   "1s",
+			"exit_code": 3,
+		},
+		LogConfig: &structs.LogConfig{
+			MaxFiles:      10,
+			MaxFileSizeMB: 10,
+		},
+		Resources: structs.DefaultResources(),
+	}
+	ctx := testDriverContexts(t, task)
+	defer ctx.AllocDir.Destroy()
+
+	d, err := NewDriver(task.Driver, ctx.DriverCtx)
+	require.NoError(err)
+	require.NoError(d.Validate(task.Config))
+
+	resp, err := d.Start(ctx.ExecCtx, task)
+	require.NoError(err)
+
+	// Reopen the task using the handle ID
+	handle, err := d.Open(ctx.ExecCtx, resp.Handle.ID())
+	require.NoError(err)
+
+	select {
+	case res := <-handle.WaitCh():
+		require.Equal(3, res.ExitCode)
+		require.False(res.Successful())
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timeout waiting for the task")
+	}
+}
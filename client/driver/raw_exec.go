@@ -93,8 +93,9 @@ func (d *RawExecDriver) Validate(config map[string]interface{}) error {
 
 func (d *RawExecDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: true,
-		Exec:        true,
+		SendSignals:   true,
+		Exec:          true,
+		ExecStreaming: true,
 	}
 }
 
@@ -295,6 +296,11 @@ func (h *rawExecHandle) Exec(ctx context.Context, cmd string, args []string) ([]
 	return executor.ExecScript(ctx, h.taskDir.Dir, h.taskEnv, nil, cmd, args)
 }
 
+func (h *rawExecHandle) ExecStreaming(ctx context.Context, cmd []string, tty bool,
+	stream dstructs.ExecStream) (*dstructs.ExecStreamingExitResult, error) {
+	return h.executor.ExecStreaming(ctx, cmd, tty, stream)
+}
+
 func (h *rawExecHandle) Signal(s os.Signal) error {
 	return h.executor.Signal(s)
 }
@@ -15,9 +15,10 @@ const (
 
 // WaitResult stores the result of a Wait operation.
 type WaitResult struct {
-	ExitCode int
-	Signal   int
-	Err      error
+	ExitCode  int
+	Signal    int
+	OOMKilled bool
+	Err       error
 }
 
 func NewWaitResult(code, signal int, err error) *WaitResult {
@@ -65,3 +66,47 @@ type ExecutorConfig struct {
 	// LogLevel is the level of the logs to putout
 	LogLevel string
 }
+
+// ExecStreamingIOOperation is a chunk of the stdin, stdout or stderr of an
+// interactive command. Close marks the end of the stream.
+type ExecStreamingIOOperation struct {
+	Data  []byte `json:"data,omitempty"`
+	Close bool   `json:"close,omitempty"`
+}
+
+// TerminalSize is the size of the terminal of an interactive command
+type TerminalSize struct {
+	Height int `json:"height"`
+	Width  int `json:"width"`
+}
+
+// ExecStreamingRequestMsg is sent to an interactive command. It carries
+// either input or a new terminal size.
+type ExecStreamingRequestMsg struct {
+	Stdin   *ExecStreamingIOOperation `json:"stdin,omitempty"`
+	TTYSize *TerminalSize             `json:"tty_size,omitempty"`
+}
+
+// ExecStreamingExitResult is the result of an interactive command
+type ExecStreamingExitResult struct {
+	ExitCode int `json:"exit_code"`
+}
+
+// ExecStreamingResponseMsg is sent by an interactive command. It carries
+// either output or, once the command exited, its result.
+type ExecStreamingResponseMsg struct {
+	Stdout *ExecStreamingIOOperation `json:"stdout,omitempty"`
+	Stderr *ExecStreamingIOOperation `json:"stderr,omitempty"`
+	Exited bool                      `json:"exited,omitempty"`
+	Result *ExecStreamingExitResult  `json:"result,omitempty"`
+}
+
+// ExecStream is the stream between an interactive command and its caller
+type ExecStream interface {
+	// Send sends output of the command. Callers must not call it
+	// concurrently.
+	Send(*ExecStreamingResponseMsg) error
+
+	// Recv returns the next input of the command
+	Recv() (*ExecStreamingRequestMsg, error)
+}
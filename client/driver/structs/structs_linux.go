@@ -7,4 +7,8 @@ import cgroupConfig "github.com/opencontainers/runc/libcontainer/configs"
 type IsolationConfig struct {
 	Cgroup      *cgroupConfig.Cgroup
 	CgroupPaths map[string]string
+
+	// Cgroup2Path is the path of the cgroup of the task on hosts that only
+	// mount the unified cgroup v2 hierarchy
+	Cgroup2Path string
 }
@@ -0,0 +1,276 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/hashicorp/nomad/client/driver"
+	"github.com/hashicorp/nomad/client/state"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/shared/loader"
+)
+
+const (
+	// driverPluginMonitorInterval is the interval at which the client checks
+	// whether the driver plugins it launched are still running.
+	driverPluginMonitorInterval = 5 * time.Second
+
+	// driverPluginRelaunchBaseBackoff and driverPluginRelaunchMaxBackoff
+	// bound how long the client waits before relaunching a driver plugin
+	// that failed to launch after it exited.
+	driverPluginRelaunchBaseBackoff = 5 * time.Second
+	driverPluginRelaunchMaxBackoff  = 2 * time.Minute
+)
+
+// driverPlugin is a driver plugin launched by the client.
+type driverPlugin struct {
+	id       loader.PluginID
+	info     *base.PluginInfoResponse
+	instance loader.PluginInstance
+
+	// restarts is the number of times the plugin was relaunched after it
+	// exited and updateTime the time its instance last changed.
+	restarts   int
+	updateTime time.Time
+}
+
+// loadDriverPlugins launches the driver plugins of the plugin catalog, or
+// reattaches to the ones launched by a previous agent, and registers the
+// drivers they implement. Plugins that fail to launch are logged and skipped.
+func (c *Client) loadDriverPlugins() error {
+	l, err := loader.NewPluginLoader(&loader.PluginLoaderConfig{
+		Logger:    c.logger,
+		LogOutput: c.config.LogOutput,
+		LogLevel:  c.config.LogLevel,
+		PluginDir: c.config.PluginDir,
+		Configs:   c.config.PluginConfigs,
+		MinPort:   c.config.ClientMinPort,
+		MaxPort:   c.config.ClientMaxPort,
+	})
+	if err != nil {
+		return err
+	}
+	c.pluginLoader = l
+
+	// Retrieve how to reattach to the plugins launched by a previous agent
+	var reattach map[loader.PluginID]*loader.ReattachConfig
+	err = c.stateDB.View(func(tx *bolt.Tx) error {
+		var err error
+		reattach, err = state.GetPluginReattachConfigs(tx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read plugin reattach configs: %v", err)
+	}
+
+	for _, info := range l.Catalog()[base.PluginTypeDriver] {
+		id := loader.PluginID{
+			Name:       info.Name,
+			PluginType: base.PluginTypeDriver,
+		}
+
+		instance, err := c.launchDriverPlugin(id, reattach[id])
+		delete(reattach, id)
+		if err != nil {
+			c.logger.Printf("[ERR] client.plugin: failed to load driver plugin %s: %v", id, err)
+			continue
+		}
+
+		if err := driver.RegisterPluginDriver(id.Name, instance.Plugin().(drivers.DriverPlugin)); err != nil {
+			c.logger.Printf("[ERR] client.plugin: failed to register driver plugin %s: %v", id, err)
+			c.killDriverPlugin(id, instance)
+			continue
+		}
+
+		p := &driverPlugin{
+			id:         id,
+			info:       info,
+			instance:   instance,
+			updateTime: time.Now(),
+		}
+		c.driverPluginsLock.Lock()
+		c.driverPlugins[id.Name] = p
+		c.driverPluginsLock.Unlock()
+
+		c.logger.Printf("[INFO] client.plugin: loaded driver plugin %s version %s (plugin API %s)",
+			id, info.PluginVersion, instance.ApiVersion())
+		go c.monitorDriverPlugin(p)
+	}
+
+	// Forget the plugins that were launched by a previous agent but are no
+	// longer part of the catalog
+	for id := range reattach {
+		c.logger.Printf("[WARN] client.plugin: plugin %s launched by a previous agent is no longer available", id)
+		if err := c.stateDB.Update(func(tx *bolt.Tx) error {
+			return state.DeletePluginReattachConfig(tx, id)
+		}); err != nil {
+			c.logger.Printf("[ERR] client.plugin: failed to delete reattach config of plugin %s: %v", id, err)
+		}
+	}
+
+	return nil
+}
+
+// launchDriverPlugin reattaches to the driver plugin if it was launched by a
+// previous agent and is still running, and otherwise launches it. The config
+// to reattach to the plugin is persisted in the client state.
+func (c *Client) launchDriverPlugin(id loader.PluginID, reattach *loader.ReattachConfig) (loader.PluginInstance, error) {
+	var instance loader.PluginInstance
+	if reattach != nil {
+		pc, err := reattach.PluginConfig()
+		if err == nil {
+			instance, err = c.pluginLoader.Reattach(id.Name, id.PluginType, pc)
+		}
+		if err != nil {
+			c.logger.Printf("[WARN] client.plugin: failed to reattach to plugin %s, launching it: %v", id, err)
+		} else {
+			c.logger.Printf("[DEBUG] client.plugin: reattached to plugin %s (pid %d)", id, pc.Pid)
+		}
+	}
+
+	if instance == nil {
+		var err error
+		instance, err = c.pluginLoader.Dispense(id.Name, id.PluginType, c.logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := instance.Plugin().(drivers.DriverPlugin); !ok {
+		instance.Kill()
+		return nil, fmt.Errorf("unexpected driver plugin type: %T", instance.Plugin())
+	}
+
+	if rc, internal := instance.ReattachConfig(); !internal {
+		err := c.stateDB.Update(func(tx *bolt.Tx) error {
+			return state.PutPluginReattachConfig(tx, id, loader.NewReattachConfig(rc))
+		})
+		if err != nil {
+			instance.Kill()
+			return nil, fmt.Errorf("failed to persist reattach config: %v", err)
+		}
+	}
+
+	return instance, nil
+}
+
+// killDriverPlugin kills the plugin and deletes its reattach config.
+func (c *Client) killDriverPlugin(id loader.PluginID, instance loader.PluginInstance) {
+	instance.Kill()
+	if err := c.stateDB.Update(func(tx *bolt.Tx) error {
+		return state.DeletePluginReattachConfig(tx, id)
+	}); err != nil {
+		c.logger.Printf("[ERR] client.plugin: failed to delete reattach config of plugin %s: %v", id, err)
+	}
+}
+
+// monitorDriverPlugin relaunches the driver plugin when it exits, until the
+// client shuts down. Tasks run by the plugin are recovered by the relaunched
+// plugin once it replaces the exited one in the driver registry.
+func (c *Client) monitorDriverPlugin(p *driverPlugin) {
+	backoff := driverPluginRelaunchBaseBackoff
+	wait := driverPluginMonitorInterval
+	for {
+		select {
+		case <-c.shutdownCh:
+			return
+		case <-time.After(wait):
+		}
+
+		c.driverPluginsLock.Lock()
+		exited := p.instance
+		c.driverPluginsLock.Unlock()
+		if !exited.Exited() {
+			wait = driverPluginMonitorInterval
+			continue
+		}
+
+		c.logger.Printf("[WARN] client.plugin: driver plugin %s exited, relaunching it", p.id)
+		instance, err := c.launchDriverPlugin(p.id, nil)
+		if err != nil {
+			c.logger.Printf("[ERR] client.plugin: failed to relaunch driver plugin %s, retrying in %v: %v", p.id, backoff, err)
+			wait = backoff
+			if backoff *= 2; backoff > driverPluginRelaunchMaxBackoff {
+				backoff = driverPluginRelaunchMaxBackoff
+			}
+			continue
+		}
+		backoff = driverPluginRelaunchBaseBackoff
+		wait = driverPluginMonitorInterval
+
+		driver.DeregisterPluginDriver(p.id.Name)
+		if err := driver.RegisterPluginDriver(p.id.Name, instance.Plugin().(drivers.DriverPlugin)); err != nil {
+			c.logger.Printf("[ERR] client.plugin: failed to register relaunched driver plugin %s: %v", p.id, err)
+		}
+
+		c.driverPluginsLock.Lock()
+		p.instance = instance
+		p.restarts++
+		p.updateTime = time.Now()
+		c.driverPluginsLock.Unlock()
+		c.logger.Printf("[INFO] client.plugin: relaunched driver plugin %s", p.id)
+	}
+}
+
+// PluginStatuses returns the status of the plugins launched by the client,
+// sorted by name.
+func (c *Client) PluginStatuses() []*cstructs.PluginStatus {
+	node := c.Node()
+
+	c.driverPluginsLock.Lock()
+	defer c.driverPluginsLock.Unlock()
+
+	statuses := make([]*cstructs.PluginStatus, 0, len(c.driverPlugins))
+	for name, p := range c.driverPlugins {
+		s := &cstructs.PluginStatus{
+			Name:          name,
+			Type:          p.id.PluginType,
+			PluginVersion: p.info.PluginVersion,
+			ApiVersion:    p.instance.ApiVersion(),
+			Internal:      p.instance.Internal(),
+			Healthy:       true,
+			Restarts:      p.restarts,
+			UpdateTime:    p.updateTime,
+		}
+
+		if p.instance.Exited() {
+			s.Healthy = false
+			s.HealthDescription = "plugin exited"
+		} else if info, ok := node.Drivers[name]; ok {
+			s.Healthy = info.Healthy
+			s.HealthDescription = info.HealthDescription
+		}
+
+		statuses = append(statuses, s)
+	}
+
+	if c.devicemanager != nil {
+		statuses = append(statuses, c.devicemanager.PluginStatuses()...)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// shutdownDriverPlugins deregisters the drivers of the plugins. The plugin
+// processes are left running so the tasks they run survive the agent being
+// restarted, and are reattached to by the next agent. In dev mode the
+// allocations are destroyed on shutdown, so the plugins are killed.
+func (c *Client) shutdownDriverPlugins() {
+	c.driverPluginsLock.Lock()
+	defer c.driverPluginsLock.Unlock()
+
+	for name, p := range c.driverPlugins {
+		driver.DeregisterPluginDriver(name)
+		if c.config.DevMode {
+			c.killDriverPlugin(p.id, p.instance)
+		}
+	}
+	c.driverPlugins = make(map[string]*driverPlugin)
+}
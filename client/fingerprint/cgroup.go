@@ -4,6 +4,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/hashicorp/nomad/client/lib/cgutil"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 )
 
@@ -23,6 +24,9 @@ type CGroupFingerprint struct {
 // fake mount points to test various code paths
 type MountPointDetector interface {
 	MountPoint() (string, error)
+
+	// Version returns the version of the mounted cgroup hierarchy
+	Version() string
 }
 
 // Implements the interface detector which calls the cgroups library directly
@@ -34,6 +38,14 @@ func (b *DefaultMountPointDetector) MountPoint() (string, error) {
 	return FindCgroupMountpointDir()
 }
 
+// Version returns v2 if the host only mounts the unified hierarchy
+func (b *DefaultMountPointDetector) Version() string {
+	if cgutil.IsCgroup2UnifiedMode() {
+		return cgutil.CgroupV2
+	}
+	return cgutil.CgroupV1
+}
+
 // NewCGroupFingerprint returns a new cgroup fingerprinter
 func NewCGroupFingerprint(logger *log.Logger) Fingerprint {
 	f := &CGroupFingerprint{
@@ -48,6 +60,7 @@ func NewCGroupFingerprint(logger *log.Logger) Fingerprint {
 // have been set in a previous fingerprint run.
 func (f *CGroupFingerprint) clearCGroupAttributes(r *cstructs.FingerprintResponse) {
 	r.RemoveAttribute("unique.cgroup.mountpoint")
+	r.RemoveAttribute("unique.cgroup.version")
 }
 
 // Periodic determines the interval at which the periodic fingerprinter will run.
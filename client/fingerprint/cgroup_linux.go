@@ -5,6 +5,7 @@ package fingerprint
 import (
 	"fmt"
 
+	"github.com/hashicorp/nomad/client/lib/cgutil"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/opencontainers/runc/libcontainer/cgroups"
 )
@@ -14,8 +15,13 @@ const (
 )
 
 // FindCgroupMountpointDir is used to find the cgroup mount point on a Linux
-// system.
+// system. On hosts that only mount the unified hierarchy, it is the mount
+// point of the unified hierarchy.
 func FindCgroupMountpointDir() (string, error) {
+	if cgutil.IsCgroup2UnifiedMode() {
+		return cgutil.CgroupRoot, nil
+	}
+
 	mount, err := cgroups.FindCgroupMountpointDir()
 	if err != nil {
 		switch e := err.(type) {
@@ -51,6 +57,7 @@ func (f *CGroupFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp *
 	}
 
 	resp.AddAttribute("unique.cgroup.mountpoint", mount)
+	resp.AddAttribute("unique.cgroup.version", f.mountPointDetector.Version())
 	resp.Detected = true
 
 	if f.lastState == cgroupUnavailable {
@@ -19,6 +19,10 @@ func (m *MountPointDetectorNoMountPoint) MountPoint() (string, error) {
 	return "", nil
 }
 
+func (m *MountPointDetectorNoMountPoint) Version() string {
+	return "v1"
+}
+
 // A fake mount point detector that returns an error
 type MountPointDetectorMountPointFail struct{}
 
@@ -26,6 +30,10 @@ func (m *MountPointDetectorMountPointFail) MountPoint() (string, error) {
 	return "", fmt.Errorf("cgroup mountpoint discovery failed")
 }
 
+func (m *MountPointDetectorMountPointFail) Version() string {
+	return "v1"
+}
+
 // A fake mount point detector that returns a valid path
 type MountPointDetectorValidMountPoint struct{}
 
@@ -33,6 +41,21 @@ func (m *MountPointDetectorValidMountPoint) MountPoint() (string, error) {
 	return "/sys/fs/cgroup", nil
 }
 
+func (m *MountPointDetectorValidMountPoint) Version() string {
+	return "v1"
+}
+
+// A fake mount point detector that returns the unified hierarchy
+type MountPointDetectorUnifiedMountPoint struct{}
+
+func (m *MountPointDetectorUnifiedMountPoint) MountPoint() (string, error) {
+	return "/sys/fs/cgroup", nil
+}
+
+func (m *MountPointDetectorUnifiedMountPoint) Version() string {
+	return "v2"
+}
+
 // A fake mount point detector that returns an empty path
 type MountPointDetectorEmptyMountPoint struct{}
 
@@ -40,6 +63,10 @@ func (m *MountPointDetectorEmptyMountPoint) MountPoint() (string, error) {
 	return "", nil
 }
 
+func (m *MountPointDetectorEmptyMountPoint) Version() string {
+	return "v1"
+}
+
 func TestCGroupFingerprint(t *testing.T) {
 	{
 		f := &CGroupFingerprint{
@@ -128,4 +155,28 @@ func TestCGroupFingerprint(t *testing.T) {
 			t.Fatalf("expected attribute to be found, %s", a)
 		}
 	}
+	{
+		f := &CGroupFingerprint{
+			logger:             testlog.Logger(t),
+			lastState:          cgroupUnavailable,
+			mountPointDetector: &MountPointDetectorUnifiedMountPoint{},
+		}
+
+		node := &structs.Node{
+			Attributes: make(map[string]string),
+		}
+
+		request := &cstructs.FingerprintRequest{Config: &config.Config{}, Node: node}
+		var response cstructs.FingerprintResponse
+		err := f.Fingerprint(request, &response)
+		if err != nil {
+			t.Fatalf("unexpected error, %s", err)
+		}
+		if a, _ := response.Attributes["unique.cgroup.mountpoint"]; a != "/sys/fs/cgroup" {
+			t.Fatalf("expected attribute to be found, %s", a)
+		}
+		if a, _ := response.Attributes["unique.cgroup.version"]; a != "v2" {
+			t.Fatalf("expected cgroup version v2, got %q", a)
+		}
+	}
 }
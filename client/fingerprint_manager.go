@@ -110,7 +110,13 @@ func (fp *FingerprintManager) Run() error {
 	var availDrivers []string
 	var skippedDrivers []string
 
+	var allDrivers []string
 	for name := range driver.BuiltinDrivers {
+		allDrivers = append(allDrivers, name)
+	}
+	allDrivers = append(allDrivers, driver.PluginDriverNames()...)
+
+	for _, name := range allDrivers {
 		// Skip fingerprinting drivers that are not in the whitelist if it is
 		// enabled.
 		if _, ok := whitelistDrivers[name]; whitelistDriversEnabled && !ok {
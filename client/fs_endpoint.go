@@ -2,9 +2,12 @@ package client
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"os"
 	"path/filepath"
@@ -17,6 +20,7 @@ import (
 	metrics "github.com/armon/go-metrics"
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/driver/logging"
 	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper"
@@ -83,7 +87,7 @@ func NewFileSystemEndpoint(c *Client) *FileSystem {
 // handleStreamResultError is a helper for sending an error with a potential
 // error code. The transmission of the error is ignored if the error has been
 // generated by the closing of the underlying transport.
-func (f *FileSystem) handleStreamResultError(err error, code *int64, encoder *codec.Encoder) {
+func handleStreamResultError(err error, code *int64, encoder *codec.Encoder) {
 	// Nothing to do as the conn is closed
 	if err == io.EOF || strings.Contains(err.Error(), "closed") {
 		return
@@ -154,26 +158,26 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
 
 	if err := decoder.Decode(&req); err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
 		return
 	}
 
 	// Check read permissions
 	if aclObj, err := f.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	} else if aclObj != nil && !aclObj.AllowNsOp(req.Namespace, acl.NamespaceCapabilityReadFS) {
-		f.handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
 	}
 
 	// Validate the arguments
 	if req.AllocID == "" {
-		f.handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
 		return
 	}
 	if req.Path == "" {
-		f.handleStreamResultError(pathNotPresentErr, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(pathNotPresentErr, helper.Int64ToPtr(400), encoder)
 		return
 	}
 	switch req.Origin {
@@ -181,7 +185,7 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	case "":
 		req.Origin = "start"
 	default:
-		f.handleStreamResultError(invalidOrigin, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(invalidOrigin, helper.Int64ToPtr(400), encoder)
 		return
 	}
 
@@ -192,18 +196,18 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 			code = helper.Int64ToPtr(404)
 		}
 
-		f.handleStreamResultError(err, code, encoder)
+		handleStreamResultError(err, code, encoder)
 		return
 	}
 
 	// Calculate the offset
 	fileInfo, err := fs.Stat(req.Path)
 	if err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
 		return
 	}
 	if fileInfo.IsDir {
-		f.handleStreamResultError(
+		handleStreamResultError(
 			fmt.Errorf("file %q is a directory", req.Path),
 			helper.Int64ToPtr(400), encoder)
 		return
@@ -302,7 +306,7 @@ OUTER:
 	}
 
 	if streamErr != nil {
-		f.handleStreamResultError(streamErr, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(streamErr, helper.Int64ToPtr(500), encoder)
 		return
 	}
 }
@@ -318,36 +322,36 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
 
 	if err := decoder.Decode(&req); err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
 		return
 	}
 
 	// Check read permissions
 	if aclObj, err := f.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	} else if aclObj != nil {
 		readfs := aclObj.AllowNsOp(req.QueryOptions.Namespace, acl.NamespaceCapabilityReadFS)
 		logs := aclObj.AllowNsOp(req.QueryOptions.Namespace, acl.NamespaceCapabilityReadLogs)
 		if !readfs && !logs {
-			f.handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+			handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 			return
 		}
 	}
 
 	// Validate the arguments
 	if req.AllocID == "" {
-		f.handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
 		return
 	}
 	if req.Task == "" {
-		f.handleStreamResultError(taskNotPresentErr, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(taskNotPresentErr, helper.Int64ToPtr(400), encoder)
 		return
 	}
 	switch req.LogType {
 	case "stdout", "stderr":
 	default:
-		f.handleStreamResultError(logTypeNotPresentErr, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(logTypeNotPresentErr, helper.Int64ToPtr(400), encoder)
 		return
 	}
 	switch req.Origin {
@@ -355,7 +359,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	case "":
 		req.Origin = "start"
 	default:
-		f.handleStreamResultError(invalidOrigin, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(invalidOrigin, helper.Int64ToPtr(400), encoder)
 		return
 	}
 
@@ -366,7 +370,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 			code = helper.Int64ToPtr(404)
 		}
 
-		f.handleStreamResultError(err, code, encoder)
+		handleStreamResultError(err, code, encoder)
 		return
 	}
 
@@ -377,18 +381,18 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 			code = helper.Int64ToPtr(404)
 		}
 
-		f.handleStreamResultError(err, code, encoder)
+		handleStreamResultError(err, code, encoder)
 		return
 	}
 
 	// Check that the task is there
 	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
 	if tg == nil {
-		f.handleStreamResultError(fmt.Errorf("Failed to lookup task group for allocation"),
+		handleStreamResultError(fmt.Errorf("Failed to lookup task group for allocation"),
 			helper.Int64ToPtr(500), encoder)
 		return
 	} else if taskStruct := tg.LookupTask(req.Task); taskStruct == nil {
-		f.handleStreamResultError(
+		handleStreamResultError(
 			fmt.Errorf("task group %q does not have task with name %q", alloc.TaskGroup, req.Task),
 			helper.Int64ToPtr(400),
 			encoder)
@@ -397,7 +401,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 
 	state, ok := alloc.TaskStates[req.Task]
 	if !ok || state.StartedAt.IsZero() {
-		f.handleStreamResultError(fmt.Errorf("task %q not started yet. No logs available", req.Task),
+		handleStreamResultError(fmt.Errorf("task %q not started yet. No logs available", req.Task),
 			helper.Int64ToPtr(404), encoder)
 		return
 	}
@@ -473,7 +477,7 @@ OUTER:
 	}
 
 	if streamErr != nil {
-		f.handleStreamResultError(streamErr, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(streamErr, helper.Int64ToPtr(500), encoder)
 		return
 	}
 }
@@ -516,6 +520,7 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 		if err != nil {
 			return fmt.Errorf("failed to list entries: %v", err)
 		}
+		entries = uncompressedLogSizes(fs, logPath, entries)
 
 		// If we are not following logs, determine the max index for the logs we are
 		// interested in so we can stop there.
@@ -535,6 +540,7 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 
 		var eofCancelCh chan error
 		exitAfter := false
+		compressed := strings.HasSuffix(logEntry.Name, logging.CompressedLogSuffix)
 		if !follow && idx > maxIndex {
 			// Exceeded what was there initially so return
 			return nil
@@ -543,12 +549,17 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 			eofCancelCh = make(chan error)
 			close(eofCancelCh)
 			exitAfter = true
-		} else {
+		} else if !compressed {
 			eofCancelCh = blockUntilNextLog(ctx, fs, logPath, task, logType, idx+1)
 		}
 
+		// Compressed files are rotated so they are streamed until EOF
 		p := filepath.Join(logPath, logEntry.Name)
-		err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh)
+		if compressed {
+			err = f.streamCompressedFile(ctx, openOffset, p, fs, framer)
+		} else {
+			err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh)
+		}
 
 		// Check if the context is cancelled
 		select {
@@ -719,6 +730,99 @@ OUTER:
 	}
 }
 
+// streamCompressedFile streams the decompressed content of a rotated log file
+// from the uncompressed offset until EOF. If the connection is broken an EPIPE
+// error is returned
+func (f *FileSystem) streamCompressedFile(ctx context.Context, offset int64, path string,
+	fs allocdir.AllocDirFS, framer *sframer.StreamFramer) error {
+
+	// Get the reader
+	file, err := fs.ReadAt(path, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	// Skip to the offset
+	if _, err := io.CopyN(ioutil.Discard, gz, offset); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+
+	data := make([]byte, streamFrameSize)
+	for {
+		// Read up to the max frame size
+		n, readErr := io.ReadFull(gz, data)
+
+		// Update the offset
+		offset += int64(n)
+
+		// Return non-EOF errors
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			return readErr
+		}
+
+		// Send the frame
+		if n != 0 {
+			if err := framer.Send(path, "", data[:n], offset); err != nil {
+				return parseFramerErr(err)
+			}
+		}
+
+		if readErr != nil {
+			return nil
+		}
+
+		select {
+		case <-framer.ExitCh():
+			return nil
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+}
+
+// uncompressedLogSizes returns the entries with the size of the compressed
+// log files replaced by their uncompressed size, read from the gzip trailer,
+// so that offsets are computed across compressed and uncompressed files.
+func uncompressedLogSizes(fs allocdir.AllocDirFS, logPath string, entries []*cstructs.AllocFileInfo) []*cstructs.AllocFileInfo {
+	sized := make([]*cstructs.AllocFileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir || !strings.HasSuffix(entry.Name, logging.CompressedLogSuffix) || entry.Size < 4 {
+			sized = append(sized, entry)
+			continue
+		}
+
+		// The trailer holds the uncompressed size modulo 2^32
+		var trailer [4]byte
+		r, err := fs.ReadAt(filepath.Join(logPath, entry.Name), entry.Size-4)
+		if err != nil {
+			sized = append(sized, entry)
+			continue
+		}
+		_, err = io.ReadFull(r, trailer[:])
+		r.Close()
+		if err != nil {
+			sized = append(sized, entry)
+			continue
+		}
+
+		e := *entry
+		e.Size = int64(binary.LittleEndian.Uint32(trailer[:]))
+		sized = append(sized, &e)
+	}
+	return sized
+}
+
 // blockUntilNextLog returns a channel that will have data sent when the next
 // log index or anything greater is created.
 func blockUntilNextLog(ctx context.Context, fs allocdir.AllocDirFS, logPath, task, logType string, nextIndex int64) chan error {
@@ -791,10 +895,12 @@ func (a indexTupleArray) Less(i, j int) bool { return a[i].idx < a[j].idx }
 func (a indexTupleArray) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 
 // logIndexes takes a set of entries and returns a indexTupleArray of
-// the desired log file entries. If the indexes could not be determined, an
-// error is returned.
+// the desired log file entries. Rotated log files may be compressed, in which
+// case the uncompressed file is used while both exist. If the indexes could
+// not be determined, an error is returned.
 func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexTupleArray, error) {
 	var indexes []indexTuple
+	positions := make(map[int64]int)
 	prefix := fmt.Sprintf("%s.%s.", task, logType)
 	for _, entry := range entries {
 		if entry.IsDir {
@@ -806,6 +912,8 @@ func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexT
 		if idxStr == entry.Name {
 			continue
 		}
+		compressed := strings.HasSuffix(idxStr, logging.CompressedLogSuffix)
+		idxStr = strings.TrimSuffix(idxStr, logging.CompressedLogSuffix)
 
 		// Convert to an int
 		idx, err := strconv.Atoi(idxStr)
@@ -813,6 +921,14 @@ func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexT
 			return nil, fmt.Errorf("failed to convert %q to a log index: %v", idxStr, err)
 		}
 
+		if i, ok := positions[int64(idx)]; ok {
+			if !compressed {
+				indexes[i].entry = entry
+			}
+			continue
+		}
+
+		positions[int64(idx)] = len(indexes)
 		indexes = append(indexes, indexTuple{idx: int64(idx), entry: entry})
 	}
 
@@ -1,6 +1,8 @@
 package client
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -17,6 +19,7 @@ import (
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/driver/logging"
 	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/testlog"
@@ -1980,6 +1983,99 @@ func TestFS_logsImpl_NoFollow(t *testing.T) {
 	}
 }
 
+func TestFS_logsImpl_Compressed(t *testing.T) {
+	t.Parallel()
+
+	c := TestClient(t, nil)
+	defer c.Shutdown()
+
+	// Get a temp alloc dir and create the log dir
+	ad := tempAllocDir(t)
+	defer os.RemoveAll(ad.AllocDir)
+
+	logDir := filepath.Join(ad.SharedDir, allocdir.LogDirName)
+	if err := os.MkdirAll(logDir, 0777); err != nil {
+		t.Fatalf("Failed to make log dir: %v", err)
+	}
+
+	// Create a series of log files in the temp dir, the rotated ones being
+	// compressed. The uncompressed file is used while both exist.
+	task := "foo"
+	logType := "stdout"
+	contents := []string{"01", "23", "45"}
+	for i, content := range contents {
+		logFile := fmt.Sprintf("%s.%s.%d", task, logType, i)
+		logFilePath := filepath.Join(logDir, logFile)
+		if i == len(contents)-1 {
+			if err := ioutil.WriteFile(logFilePath, []byte(content), 0777); err != nil {
+				t.Fatalf("Failed to create file: %v", err)
+			}
+			continue
+		}
+		if i == 1 {
+			if err := ioutil.WriteFile(logFilePath, []byte(content), 0777); err != nil {
+				t.Fatalf("Failed to create file: %v", err)
+			}
+		}
+
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write([]byte(content))
+		gz.Close()
+		if err := ioutil.WriteFile(logFilePath+logging.CompressedLogSuffix, buf.Bytes(), 0777); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	cases := []struct {
+		Name     string
+		Origin   string
+		Offset   int64
+		Expected string
+	}{
+		{
+			Name:     "start",
+			Origin:   OriginStart,
+			Expected: "012345",
+		},
+		{
+			Name:     "start offset",
+			Origin:   OriginStart,
+			Offset:   1,
+			Expected: "12345",
+		},
+		{
+			Name:     "end offset",
+			Origin:   OriginEnd,
+			Offset:   5,
+			Expected: "12345",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			frames := make(chan *sframer.StreamFrame, 4)
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- c.endpoints.FileSystem.logsImpl(
+					context.Background(), false, false, tc.Offset,
+					tc.Origin, task, logType, ad, frames)
+			}()
+
+			// The frames are closed once the logs are streamed
+			var received []byte
+			for frame := range frames {
+				if frame.IsHeartbeat() {
+					continue
+				}
+				received = append(received, frame.Data...)
+			}
+			require.NoError(t, <-errCh)
+			require.Equal(t, tc.Expected, string(received))
+		})
+	}
+}
+
 func TestFS_logsImpl_Follow(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,12 @@
+// Package cgutil detects the cgroup hierarchy mounted on the host.
+package cgutil
+
+const (
+	// CgroupRoot is where the cgroup hierarchies are mounted
+	CgroupRoot = "/sys/fs/cgroup"
+
+	// CgroupV1 and CgroupV2 are the versions of the cgroup hierarchy
+	// reported by the fingerprinter
+	CgroupV1 = "v1"
+	CgroupV2 = "v2"
+)
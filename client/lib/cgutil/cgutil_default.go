@@ -0,0 +1,9 @@
+// +build !linux
+
+package cgutil
+
+// IsCgroup2UnifiedMode returns whether the host only mounts the unified
+// cgroup v2 hierarchy, which is never the case outside of Linux.
+func IsCgroup2UnifiedMode() bool {
+	return false
+}
@@ -0,0 +1,26 @@
+package cgutil
+
+import (
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+var (
+	unifiedOnce sync.Once
+	unified     bool
+)
+
+// IsCgroup2UnifiedMode returns whether the host only mounts the unified
+// cgroup v2 hierarchy. Hosts mounting the v1 hierarchies, including hybrid
+// hosts that also mount the unified hierarchy, are not in unified mode.
+func IsCgroup2UnifiedMode() bool {
+	unifiedOnce.Do(func() {
+		var st unix.Statfs_t
+		if err := unix.Statfs(CgroupRoot, &st); err != nil {
+			return
+		}
+		unified = st.Type == unix.CGROUP2_SUPER_MAGIC
+	})
+	return unified
+}
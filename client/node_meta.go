@@ -0,0 +1,176 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// restoreNodeMeta loads the dynamic node meta persisted in the state database
+// and applies it on top of the meta from the client's configuration.
+func (c *Client) restoreNodeMeta() error {
+	var dynamic map[string]*string
+	err := c.stateDB.View(func(tx *bolt.Tx) error {
+		var err error
+		dynamic, err = state.GetNodeMeta(tx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+	c.staticMeta = helper.CopyMapStringString(c.config.Node.Meta)
+	c.dynamicMeta = dynamic
+	c.config.Node.Meta = mergeNodeMeta(c.staticMeta, c.dynamicMeta)
+	return nil
+}
+
+// NodeMeta returns the node's effective, dynamic and static meta.
+func (c *Client) NodeMeta() (meta map[string]string, dynamic map[string]*string, static map[string]string) {
+	c.configLock.RLock()
+	defer c.configLock.RUnlock()
+	return helper.CopyMapStringString(c.config.Node.Meta),
+		copyNodeMeta(c.dynamicMeta),
+		helper.CopyMapStringString(c.staticMeta)
+}
+
+// ApplyNodeMeta applies the given changes to the dynamic node meta. A nil value
+// unsets the key. The changes are persisted so they survive restarts and the
+// new meta is sent to the servers.
+func (c *Client) ApplyNodeMeta(updates map[string]*string) error {
+	for k := range updates {
+		if err := validateNodeMetaKey(k); err != nil {
+			return err
+		}
+	}
+
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	dynamic := copyNodeMeta(c.dynamicMeta)
+	if dynamic == nil {
+		dynamic = make(map[string]*string, len(updates))
+	}
+	for k, v := range updates {
+		if v == nil {
+			// Keys only set through the API can be forgotten, but unsetting
+			// a key from the configuration has to be remembered.
+			if _, ok := c.staticMeta[k]; !ok {
+				delete(dynamic, k)
+				continue
+			}
+		}
+		dynamic[k] = v
+	}
+
+	err := c.stateDB.Update(func(tx *bolt.Tx) error {
+		return state.PutNodeMeta(tx, dynamic)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist node meta: %v", err)
+	}
+
+	c.dynamicMeta = dynamic
+	c.config.Node.Meta = mergeNodeMeta(c.staticMeta, c.dynamicMeta)
+	c.configCopy.Node = c.config.Node.Copy()
+
+	select {
+	case c.triggerNodeMetaUpdate <- struct{}{}:
+	default:
+		// An update is already pending and will send the latest meta
+	}
+	return nil
+}
+
+// watchNodeMetaUpdates sends the node's meta to the servers whenever it is
+// updated through the API. If that fails the node is re-registered instead,
+// which is retried until it succeeds.
+func (c *Client) watchNodeMetaUpdates() {
+	for {
+		select {
+		case <-c.triggerNodeMetaUpdate:
+			if err := c.updateNodeMeta(); err != nil {
+				c.logger.Printf("[WARN] client: failed to update node meta, re-registering node: %v", err)
+				c.configLock.Lock()
+				c.updateNodeLocked()
+				c.configLock.Unlock()
+			}
+		case <-c.shutdownCh:
+			return
+		}
+	}
+}
+
+// updateNodeMeta updates only the meta of the registered node so that the
+// update can't overwrite other changes made to the node by the servers.
+func (c *Client) updateNodeMeta() error {
+	c.configLock.RLock()
+	meta := helper.CopyMapStringString(c.config.Node.Meta)
+	c.configLock.RUnlock()
+
+	req := structs.NodeUpdateMetaRequest{
+		NodeID:       c.NodeID(),
+		SecretID:     c.secretNodeID(),
+		Meta:         meta,
+		WriteRequest: structs.WriteRequest{Region: c.Region()},
+	}
+	var resp structs.NodeUpdateResponse
+	if err := c.RPC("Node.UpdateMeta", &req, &resp); err != nil {
+		return err
+	}
+
+	c.logger.Printf("[DEBUG] client: node meta updated")
+	if len(resp.EvalIDs) != 0 {
+		c.logger.Printf("[DEBUG] client: %d evaluations triggered by node meta update", len(resp.EvalIDs))
+	}
+	return nil
+}
+
+// mergeNodeMeta returns the static meta overridden by the dynamic meta.
+func mergeNodeMeta(static map[string]string, dynamic map[string]*string) map[string]string {
+	meta := helper.CopyMapStringString(static)
+	if meta == nil {
+		meta = make(map[string]string, len(dynamic))
+	}
+	for k, v := range dynamic {
+		if v == nil {
+			delete(meta, k)
+		} else {
+			meta[k] = *v
+		}
+	}
+	return meta
+}
+
+// copyNodeMeta returns a deep copy of the dynamic node meta.
+func copyNodeMeta(meta map[string]*string) map[string]*string {
+	if meta == nil {
+		return nil
+	}
+
+	c := make(map[string]*string, len(meta))
+	for k, v := range meta {
+		if v != nil {
+			v = helper.StringToPtr(*v)
+		}
+		c[k] = v
+	}
+	return c
+}
+
+// validateNodeMetaKey returns an error if the key can't be used in node meta.
+func validateNodeMetaKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("node meta keys must not be empty")
+	}
+	if strings.ContainsAny(key, " \t\n") {
+		return fmt.Errorf("node meta key %q must not contain whitespace", key)
+	}
+	return nil
+}
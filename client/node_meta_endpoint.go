@@ -0,0 +1,48 @@
+package client
+
+import (
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/client/structs"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NodeMeta endpoint is used for reading and updating the node's meta
+type NodeMeta struct {
+	c *Client
+}
+
+// Apply is used to update the node's dynamic meta.
+func (n *NodeMeta) Apply(args *structs.NodeMetaApplyRequest, reply *structs.NodeMetaResponse) error {
+	defer metrics.MeasureSince([]string{"client", "node_meta", "apply"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeClassWrite(n.c.Node().NodeClass) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	if err := n.c.ApplyNodeMeta(args.Meta); err != nil {
+		return err
+	}
+
+	reply.Meta, reply.Dynamic, reply.Static = n.c.NodeMeta()
+	return nil
+}
+
+// Read is used to retrieve the node's meta.
+func (n *NodeMeta) Read(args *nstructs.NodeSpecificRequest, reply *structs.NodeMetaResponse) error {
+	defer metrics.MeasureSince([]string{"client", "node_meta", "read"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := n.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeClassRead(n.c.Node().NodeClass) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	reply.Meta, reply.Dynamic, reply.Static = n.c.NodeMeta()
+	return nil
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/mock"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMeta_Apply(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client := TestClient(t, func(c *config.Config) {
+		c.Node.Meta = map[string]string{"rack": "r1", "zone": "a"}
+	})
+	defer client.Shutdown()
+
+	req := &structs.NodeMetaApplyRequest{
+		Meta: map[string]*string{
+			"rack": helper.StringToPtr("r2"),
+			"zone": nil,
+			"gpu":  helper.StringToPtr("true"),
+		},
+	}
+	var resp structs.NodeMetaResponse
+	require.Nil(client.ClientRPC("NodeMeta.Apply", &req, &resp))
+	require.Equal(map[string]string{"rack": "r2", "gpu": "true"}, resp.Meta)
+	require.Equal(map[string]string{"rack": "r1", "zone": "a"}, resp.Static)
+	require.Nil(resp.Dynamic["zone"])
+	require.Contains(resp.Dynamic, "zone")
+
+	// The node sent to the servers has the new meta
+	require.Equal(resp.Meta, client.Node().Meta)
+
+	// Unsetting a key only set through the API forgets it
+	req.Meta = map[string]*string{"gpu": nil}
+	require.Nil(client.ClientRPC("NodeMeta.Apply", &req, &resp))
+	require.NotContains(resp.Dynamic, "gpu")
+	require.NotContains(resp.Meta, "gpu")
+
+	// The dynamic meta is persisted
+	err := client.stateDB.View(func(tx *bolt.Tx) error {
+		meta, err := state.GetNodeMeta(tx)
+		require.Nil(err)
+		require.Equal(resp.Dynamic, meta)
+		return nil
+	})
+	require.Nil(err)
+
+	// Restoring reapplies the dynamic meta on top of the configuration
+	client.configLock.Lock()
+	client.config.Node.Meta = map[string]string{"rack": "r1", "zone": "a"}
+	client.configLock.Unlock()
+	require.Nil(client.restoreNodeMeta())
+	meta, _, _ := client.NodeMeta()
+	require.Equal(map[string]string{"rack": "r2"}, meta)
+
+	// Invalid keys are rejected
+	req.Meta = map[string]*string{"": helper.StringToPtr("foo")}
+	require.Error(client.ClientRPC("NodeMeta.Apply", &req, &resp))
+}
+
+func TestNodeMeta_Apply_Server(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr := testServer(t, nil)
+	defer server.Shutdown()
+
+	client := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.Node.Meta = map[string]string{"rack": "r1"}
+	})
+	defer client.Shutdown()
+
+	// Wait for the node to register
+	testutil.WaitForResult(func() (bool, error) {
+		node, err := server.State().NodeByID(nil, client.NodeID())
+		if err != nil {
+			return false, err
+		}
+		if node == nil || node.Status != nstructs.NodeStatusReady {
+			return false, fmt.Errorf("node not ready")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	req := &structs.NodeMetaApplyRequest{
+		Meta: map[string]*string{"rack": helper.StringToPtr("r2")},
+	}
+	var resp structs.NodeMetaResponse
+	require.Nil(client.ClientRPC("NodeMeta.Apply", &req, &resp))
+
+	// The servers receive the new meta
+	testutil.WaitForResult(func() (bool, error) {
+		node, err := server.State().NodeByID(nil, client.NodeID())
+		if err != nil {
+			return false, err
+		}
+		if rack := node.Meta["rack"]; rack != "r2" {
+			return false, fmt.Errorf("expected rack r2, got %q", rack)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+func TestNodeMeta_Read(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client := TestClient(t, func(c *config.Config) {
+		c.Node.Meta = map[string]string{"rack": "r1"}
+	})
+	defer client.Shutdown()
+
+	req := &nstructs.NodeSpecificRequest{}
+	var resp structs.NodeMetaResponse
+	require.Nil(client.ClientRPC("NodeMeta.Read", &req, &resp))
+	require.Equal(map[string]string{"rack": "r1"}, resp.Meta)
+	require.Equal(map[string]string{"rack": "r1"}, resp.Static)
+	require.Empty(resp.Dynamic)
+}
+
+func TestNodeMeta_Apply_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr, root := testACLServer(t, nil)
+	defer server.Shutdown()
+
+	client := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer client.Shutdown()
+
+	newReq := func(token string) *structs.NodeMetaApplyRequest {
+		req := &structs.NodeMetaApplyRequest{
+			Meta: map[string]*string{"rack": helper.StringToPtr("r1")},
+		}
+		req.AuthToken = token
+		return req
+	}
+
+	// Try request without a token and expect failure
+	var resp structs.NodeMetaResponse
+	err := client.ClientRPC("NodeMeta.Apply", newReq(""), &resp)
+	require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+
+	// Try request with a read token and expect failure
+	token := mock.CreatePolicyAndToken(t, server.State(), 1005, "read", mock.NodePolicy(acl.PolicyRead))
+	err = client.ClientRPC("NodeMeta.Apply", newReq(token.SecretID), &resp)
+	require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+
+	// Try request with a write token
+	token = mock.CreatePolicyAndToken(t, server.State(), 1007, "write", mock.NodePolicy(acl.PolicyWrite))
+	require.Nil(client.ClientRPC("NodeMeta.Apply", newReq(token.SecretID), &resp))
+	require.Equal("r1", resp.Meta["rack"])
+
+	// Try request with a management token
+	require.Nil(client.ClientRPC("NodeMeta.Apply", newReq(root.SecretID), &resp))
+}
@@ -23,6 +23,8 @@ type rpcEndpoints struct {
 	ClientStats *ClientStats
 	FileSystem  *FileSystem
 	Allocations *Allocations
+	NodeMeta    *NodeMeta
+	CSI         *CSI
 }
 
 // ClientRPC is used to make a local, client only RPC call
@@ -210,7 +212,9 @@ func (c *Client) setupClientRpc() {
 	// Initialize the RPC handlers
 	c.endpoints.ClientStats = &ClientStats{c}
 	c.endpoints.FileSystem = NewFileSystemEndpoint(c)
-	c.endpoints.Allocations = &Allocations{c}
+	c.endpoints.Allocations = NewAllocationsEndpoint(c)
+	c.endpoints.NodeMeta = &NodeMeta{c}
+	c.endpoints.CSI = &CSI{c}
 
 	// Create the RPC Server
 	c.rpcServer = rpc.NewServer()
@@ -227,6 +231,8 @@ func (c *Client) setupClientRpcServer(server *rpc.Server) {
 	server.Register(c.endpoints.ClientStats)
 	server.Register(c.endpoints.FileSystem)
 	server.Register(c.endpoints.Allocations)
+	server.Register(c.endpoints.NodeMeta)
+	server.Register(c.endpoints.CSI)
 }
 
 // rpcConnListener is a long lived function that listens for new connections
@@ -0,0 +1,284 @@
+package state
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// StateDBFile is the name of the client state database file in the
+	// state directory
+	StateDBFile = "state.db"
+
+	// LatestSchemaVersion is the schema version of the state database
+	// written by this version of the client. Bump it and add a migration
+	// whenever the layout of the state changes.
+	LatestSchemaVersion uint64 = 1
+)
+
+var (
+	// metaBucket is the bucket name containing the metadata of the state
+	// database
+	metaBucket = []byte("meta")
+
+	// schemaVersionKey is the key the schema version is stored at
+	schemaVersionKey = []byte("version")
+)
+
+// StateDB is the persistent state store of a client.
+type StateDB interface {
+	// Name returns the name of the state database implementation.
+	Name() string
+
+	// Version returns the schema version of the state database. Databases
+	// written before the schema was versioned are at version 0.
+	Version() (uint64, error)
+
+	// Upgrade migrates the state database to the latest schema version. The
+	// database is backed up before it is migrated. It must be called before
+	// any state is read.
+	Upgrade() error
+
+	// View runs the function in a read-only transaction.
+	View(fn func(tx *bolt.Tx) error) error
+
+	// Update runs the function in a read-write transaction.
+	Update(fn func(tx *bolt.Tx) error) error
+
+	// Batch runs the function in a read-write transaction which may be
+	// combined with other concurrent batches.
+	Batch(fn func(tx *bolt.Tx) error) error
+
+	// Close closes the state database.
+	Close() error
+}
+
+// migration upgrades the state database schema by one version. Changes
+// outside of the database, which a failed transaction can't roll back, are
+// made by the returned function once the migrations are committed.
+type migration func(db *BoltStateDB, tx *bolt.Tx) (func() error, error)
+
+// migrations upgrade the state database schema. The migration at index i
+// upgrades the schema from version i to version i+1.
+var migrations = []migration{
+	migrateToV1,
+}
+
+// BoltStateDB is a StateDB backed by a boltDB file in the state directory.
+type BoltStateDB struct {
+	db       *bolt.DB
+	stateDir string
+	logger   *log.Logger
+}
+
+// NewBoltStateDB creates or opens the state database in the state directory.
+func NewBoltStateDB(logger *log.Logger, stateDir string) (*BoltStateDB, error) {
+	return newBoltStateDB(logger, stateDir, nil)
+}
+
+// NewReadOnlyBoltStateDB opens the existing state database in the state
+// directory for reading. It fails if the database is in use by an agent.
+func NewReadOnlyBoltStateDB(logger *log.Logger, stateDir string) (*BoltStateDB, error) {
+	path := filepath.Join(stateDir, StateDBFile)
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	return newBoltStateDB(logger, stateDir, &bolt.Options{
+		ReadOnly: true,
+		Timeout:  time.Second,
+	})
+}
+
+func newBoltStateDB(logger *log.Logger, stateDir string, opts *bolt.Options) (*BoltStateDB, error) {
+	db, err := bolt.Open(filepath.Join(stateDir, StateDBFile), 0600, opts)
+	if err != nil {
+		if err == bolt.ErrTimeout {
+			return nil, fmt.Errorf("state database is in use, is the agent running?")
+		}
+		return nil, err
+	}
+
+	return &BoltStateDB{
+		db:       db,
+		stateDir: stateDir,
+		logger:   logger,
+	}, nil
+}
+
+func (s *BoltStateDB) Name() string {
+	return "boltdb"
+}
+
+func (s *BoltStateDB) Version() (uint64, error) {
+	var version uint64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		version, err = getSchemaVersion(tx)
+		return err
+	})
+	return version, err
+}
+
+func (s *BoltStateDB) Upgrade() error {
+	var version uint64
+	var empty bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		version, err = getSchemaVersion(tx)
+		k, _ := tx.Cursor().First()
+		empty = k == nil
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read state schema version: %v", err)
+	}
+
+	switch {
+	case version == LatestSchemaVersion:
+		return nil
+	case version > LatestSchemaVersion:
+		return fmt.Errorf("state schema version %d is newer than the latest version %d supported by this agent",
+			version, LatestSchemaVersion)
+	}
+
+	// Back up the database before migrating it. A new database has nothing
+	// to back up.
+	if !empty {
+		backup := filepath.Join(s.stateDir, fmt.Sprintf("%s.v%d.backup", StateDBFile, version))
+		if err := s.db.View(func(tx *bolt.Tx) error {
+			return tx.CopyFile(backup, 0600)
+		}); err != nil {
+			return fmt.Errorf("failed to back up state database: %v", err)
+		}
+		s.logger.Printf("[INFO] client.state: backed up state database to %q, migrating it from version %d to %d",
+			backup, version, LatestSchemaVersion)
+	}
+
+	// Run the migrations in a single transaction so a failed migration
+	// leaves the database untouched
+	var postCommit []func() error
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		postCommit = nil
+		for v := version; v < LatestSchemaVersion; v++ {
+			fn, err := migrations[v](s, tx)
+			if err != nil {
+				return fmt.Errorf("failed to migrate state to version %d: %v", v+1, err)
+			}
+			if fn != nil {
+				postCommit = append(postCommit, fn)
+			}
+		}
+		return putSchemaVersion(tx, LatestSchemaVersion)
+	})
+	if err != nil {
+		return err
+	}
+
+	// The database is migrated at this point, so failing to change the files
+	// around it is only logged
+	for _, fn := range postCommit {
+		if err := fn(); err != nil {
+			s.logger.Printf("[WARN] client.state: %v", err)
+		}
+	}
+
+	if !empty {
+		s.logger.Printf("[INFO] client.state: migrated state database to version %d", LatestSchemaVersion)
+	}
+	return nil
+}
+
+func (s *BoltStateDB) View(fn func(tx *bolt.Tx) error) error {
+	return s.db.View(fn)
+}
+
+func (s *BoltStateDB) Update(fn func(tx *bolt.Tx) error) error {
+	return s.db.Update(fn)
+}
+
+func (s *BoltStateDB) Batch(fn func(tx *bolt.Tx) error) error {
+	return s.db.Batch(fn)
+}
+
+func (s *BoltStateDB) Close() error {
+	return s.db.Close()
+}
+
+// QuarantineStateDB moves the state database in the state directory aside,
+// so that a new one is created when it is unreadable. The path the database
+// was moved to is returned.
+func QuarantineStateDB(stateDir string) (string, error) {
+	path := filepath.Join(stateDir, StateDBFile)
+	quarantined := fmt.Sprintf("%s.%d.corrupt", path, time.Now().Unix())
+	if err := os.Rename(path, quarantined); err != nil {
+		return "", err
+	}
+	return quarantined, nil
+}
+
+// getSchemaVersion returns the schema version of the state, 0 if it isn't
+// versioned.
+func getSchemaVersion(tx *bolt.Tx) (uint64, error) {
+	bkt := tx.Bucket(metaBucket)
+	if bkt == nil || bkt.Get(schemaVersionKey) == nil {
+		return 0, nil
+	}
+
+	var version uint64
+	if err := GetObject(bkt, schemaVersionKey, &version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// putSchemaVersion persists the schema version of the state.
+func putSchemaVersion(tx *bolt.Tx, version uint64) error {
+	bkt, err := tx.CreateBucketIfNotExists(metaBucket)
+	if err != nil {
+		return err
+	}
+
+	return PutObject(bkt, schemaVersionKey, version)
+}
+
+// migrateToV1 versions the state written before the schema was versioned.
+// Clients prior to 0.6 persisted the state of allocations in per allocation
+// files, which can't be restored since allocations are only restored from
+// the database. They are moved aside rather than failing the restore.
+func migrateToV1(db *BoltStateDB, tx *bolt.Tx) (func() error, error) {
+	legacy := filepath.Join(db.stateDir, "alloc")
+	if _, err := os.Stat(legacy); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return func() error {
+		return db.moveLegacyAllocState(legacy)
+	}, nil
+}
+
+// moveLegacyAllocState moves the pre-0.6 allocation state aside. A backup
+// left by a previous move is kept, so the move can be repeated.
+func (s *BoltStateDB) moveLegacyAllocState(legacy string) error {
+	if _, err := os.Stat(legacy); os.IsNotExist(err) {
+		return nil
+	}
+
+	backup := legacy + ".pre-0.6.backup"
+	if _, err := os.Stat(backup); err == nil {
+		backup = fmt.Sprintf("%s.%d", backup, time.Now().UnixNano())
+	}
+	if err := os.Rename(legacy, backup); err != nil {
+		return fmt.Errorf("failed to move pre-0.6 allocation state: %v", err)
+	}
+	s.logger.Printf("[WARN] client.state: moved unsupported pre-0.6 allocation state to %q", backup)
+	return nil
+}
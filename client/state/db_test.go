@@ -0,0 +1,206 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBoltStateDB(t *testing.T) (*BoltStateDB, string, func()) {
+	dir, err := ioutil.TempDir("", "nomadtest-statedb")
+	require.NoError(t, err)
+
+	db, err := NewBoltStateDB(testlog.Logger(t), dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("error creating state db: %v", err)
+	}
+
+	return db, dir, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestBoltStateDB_Upgrade_New(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	db, dir, cleanup := setupBoltStateDB(t)
+	defer cleanup()
+
+	version, err := db.Version()
+	require.NoError(err)
+	require.Zero(version)
+
+	// A new database is versioned without being backed up
+	require.NoError(db.Upgrade())
+	version, err = db.Version()
+	require.NoError(err)
+	require.Equal(LatestSchemaVersion, version)
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*.backup"))
+	require.NoError(err)
+	require.Empty(matches)
+
+	// Upgrading again is a noop
+	require.NoError(db.Upgrade())
+}
+
+func TestBoltStateDB_Upgrade_Unversioned(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	db, dir, cleanup := setupBoltStateDB(t)
+	defer cleanup()
+
+	// Write state without a schema version along with pre-0.6 state files
+	require.NoError(db.Update(func(tx *bolt.Tx) error {
+		return PutNodeMeta(tx, map[string]*string{})
+	}))
+	legacy := filepath.Join(dir, "alloc", "foo")
+	require.NoError(os.MkdirAll(legacy, 0700))
+
+	require.NoError(db.Upgrade())
+	version, err := db.Version()
+	require.NoError(err)
+	require.Equal(LatestSchemaVersion, version)
+
+	// The unversioned database was backed up
+	backup, err := bolt.Open(filepath.Join(dir, "state.db.v0.backup"), 0600, nil)
+	require.NoError(err)
+	require.NoError(backup.View(func(tx *bolt.Tx) error {
+		v, err := getSchemaVersion(tx)
+		require.NoError(err)
+		require.Zero(v)
+		require.NotNil(tx.Bucket(nodeMetaBucket))
+		return nil
+	}))
+	require.NoError(backup.Close())
+
+	// The pre-0.6 state files were moved aside
+	_, err = os.Stat(filepath.Join(dir, "alloc"))
+	require.True(os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "alloc.pre-0.6.backup", "foo"))
+	require.NoError(err)
+}
+
+func TestBoltStateDB_Upgrade_LegacyBackupExists(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	db, dir, cleanup := setupBoltStateDB(t)
+	defer cleanup()
+
+	require.NoError(db.Update(func(tx *bolt.Tx) error {
+		return PutNodeMeta(tx, map[string]*string{})
+	}))
+	require.NoError(os.MkdirAll(filepath.Join(dir, "alloc", "foo"), 0700))
+
+	// A previous move left a backup behind
+	require.NoError(os.MkdirAll(filepath.Join(dir, "alloc.pre-0.6.backup", "bar"), 0700))
+
+	require.NoError(db.Upgrade())
+	version, err := db.Version()
+	require.NoError(err)
+	require.Equal(LatestSchemaVersion, version)
+
+	// Both the previous backup and the newly moved state are kept
+	_, err = os.Stat(filepath.Join(dir, "alloc"))
+	require.True(os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "alloc.pre-0.6.backup", "bar"))
+	require.NoError(err)
+	moved, err := filepath.Glob(filepath.Join(dir, "alloc.pre-0.6.backup.*", "foo"))
+	require.NoError(err)
+	require.Len(moved, 1)
+}
+
+func TestBoltStateDB_Upgrade_Newer(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	db, _, cleanup := setupBoltStateDB(t)
+	defer cleanup()
+
+	require.NoError(db.Update(func(tx *bolt.Tx) error {
+		return putSchemaVersion(tx, LatestSchemaVersion+1)
+	}))
+
+	err := db.Upgrade()
+	require.Error(err)
+	require.Contains(err.Error(), "newer")
+}
+
+func TestQuarantineAllocationBucket(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	db, _, cleanup := setupBoltStateDB(t)
+	defer cleanup()
+
+	require.NoError(db.Update(func(tx *bolt.Tx) error {
+		bkt, err := GetTaskBucket(tx, "alloc1", "web")
+		if err != nil {
+			return err
+		}
+		if err := PutData(bkt, []byte("key"), []byte("value")); err != nil {
+			return err
+		}
+		_, err = GetAllocationBucket(tx, "alloc2")
+		return err
+	}))
+
+	require.NoError(db.Update(func(tx *bolt.Tx) error {
+		return QuarantineAllocationBucket(tx, "alloc1")
+	}))
+
+	require.NoError(db.View(func(tx *bolt.Tx) error {
+		ids, err := GetAllAllocationIDs(tx)
+		require.NoError(err)
+		require.Equal([]string{"alloc2"}, ids)
+
+		ids, err = GetQuarantinedAllocationIDs(tx)
+		require.NoError(err)
+		require.Equal([]string{"alloc1"}, ids)
+
+		// The nested task bucket was copied
+		task := tx.Bucket(quarantineBucket).Bucket([]byte("alloc1")).Bucket([]byte("web"))
+		require.NotNil(task)
+		require.Equal([]byte("value"), task.Get([]byte("key")))
+		return nil
+	}))
+
+	// Quarantining an unknown allocation is a noop
+	require.NoError(db.Update(func(tx *bolt.Tx) error {
+		return QuarantineAllocationBucket(tx, "alloc3")
+	}))
+}
+
+func TestQuarantineStateDB(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-statedb")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// An unreadable database fails to open
+	path := filepath.Join(dir, StateDBFile)
+	require.NoError(ioutil.WriteFile(path, []byte("corrupt"), 0600))
+	_, err = NewBoltStateDB(testlog.Logger(t), dir)
+	require.Error(err)
+
+	quarantined, err := QuarantineStateDB(dir)
+	require.NoError(err)
+	_, err = os.Stat(quarantined)
+	require.NoError(err)
+
+	db, err := NewBoltStateDB(testlog.Logger(t), dir)
+	require.NoError(err)
+	require.NoError(db.Close())
+}
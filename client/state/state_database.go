@@ -3,26 +3,56 @@ package state
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/boltdb/bolt"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/shared/loader"
 	"github.com/ugorji/go/codec"
 )
 
 /*
-The client has a boltDB backed state store. The schema as of 0.6 looks as follows:
+The client has a boltDB backed state store. The schema as of version 1 looks as
+follows:
+
+meta/ (bucket)
+|--> version (k/v) the schema version of the state, see LatestSchemaVersion
 
 allocations/ (bucket)
 |--> <alloc-id>/ (bucket)
     |--> alloc_runner persisted objects (k/v)
 	|--> <task-name>/ (bucket)
         |--> task_runner persisted objects (k/v)
+
+node_meta/ (bucket)
+|--> dynamic (k/v) the node meta set through the API
+
+plugins/ (bucket)
+|--> <plugin-type>/<plugin-name> (k/v) how to reattach to the launched plugin
+
+quarantine/ (bucket)
+|--> <alloc-id>/ (bucket) allocation bucket that failed to be restored
 */
 
 var (
 	// allocationsBucket is the bucket name containing all allocation related
 	// data
 	allocationsBucket = []byte("allocations")
+
+	// nodeMetaBucket is the bucket name containing the node meta set through
+	// the API
+	nodeMetaBucket = []byte("node_meta")
+
+	// nodeMetaDynamicKey is the key the dynamic node meta is stored at
+	nodeMetaDynamicKey = []byte("dynamic")
+
+	// pluginsBucket is the bucket name containing the reattach configs of
+	// the plugins launched by the client
+	pluginsBucket = []byte("plugins")
+
+	// quarantineBucket is the bucket name containing the allocation buckets
+	// that failed to be restored
+	quarantineBucket = []byte("quarantine")
 )
 
 func PutObject(bkt *bolt.Bucket, key []byte, obj interface{}) error {
@@ -184,6 +214,83 @@ func DeleteTaskBucket(tx *bolt.Tx, allocID, taskName string) error {
 	return alloc.DeleteBucket(key)
 }
 
+// QuarantineAllocationBucket moves an allocation bucket to the quarantine
+// bucket so the allocation is no longer restored while its state is kept for
+// inspection. Quarantining replaces a previously quarantined bucket of the
+// allocation.
+func QuarantineAllocationBucket(tx *bolt.Tx, allocID string) error {
+	if !tx.Writable() {
+		return fmt.Errorf("transaction must be writable")
+	}
+
+	allocations := tx.Bucket(allocationsBucket)
+	if allocations == nil {
+		return nil
+	}
+
+	key := []byte(allocID)
+	alloc := allocations.Bucket(key)
+	if alloc == nil {
+		return nil
+	}
+
+	quarantine, err := tx.CreateBucketIfNotExists(quarantineBucket)
+	if err != nil {
+		return err
+	}
+	if quarantine.Bucket(key) != nil {
+		if err := quarantine.DeleteBucket(key); err != nil {
+			return err
+		}
+	}
+
+	dst, err := quarantine.CreateBucket(key)
+	if err != nil {
+		return err
+	}
+	if err := copyBucket(dst, alloc); err != nil {
+		return fmt.Errorf("failed to copy allocation bucket: %v", err)
+	}
+
+	return allocations.DeleteBucket(key)
+}
+
+// GetQuarantinedAllocationIDs returns the IDs of the quarantined allocations.
+func GetQuarantinedAllocationIDs(tx *bolt.Tx) ([]string, error) {
+	quarantine := tx.Bucket(quarantineBucket)
+	if quarantine == nil {
+		return nil, nil
+	}
+
+	var allocIDs []string
+	c := quarantine.Cursor()
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		allocIDs = append(allocIDs, string(k))
+	}
+
+	return allocIDs, nil
+}
+
+// copyBucket recursively copies the keys and nested buckets of src to dst.
+func copyBucket(dst, src *bolt.Bucket) error {
+	return src.ForEach(func(k, v []byte) error {
+		// The keys and values are only valid until the source is modified,
+		// so they are copied
+		key := append([]byte(nil), k...)
+
+		// Nested buckets have a nil value
+		if v != nil {
+			return dst.Put(key, append([]byte(nil), v...))
+		}
+
+		nested, err := dst.CreateBucket(key)
+		if err != nil {
+			return err
+		}
+		return copyBucket(nested, src.Bucket(k))
+	})
+}
+
 func GetAllAllocationIDs(tx *bolt.Tx) ([]string, error) {
 	allocationsBkt := tx.Bucket(allocationsBucket)
 	if allocationsBkt == nil {
@@ -201,3 +308,89 @@ func GetAllAllocationIDs(tx *bolt.Tx) ([]string, error) {
 
 	return allocIDs, nil
 }
+
+// PutNodeMeta persists the dynamic node meta, replacing any previously stored
+// meta.
+func PutNodeMeta(tx *bolt.Tx, meta map[string]*string) error {
+	bkt, err := tx.CreateBucketIfNotExists(nodeMetaBucket)
+	if err != nil {
+		return err
+	}
+
+	return PutObject(bkt, nodeMetaDynamicKey, meta)
+}
+
+// GetNodeMeta returns the persisted dynamic node meta. If no meta has been
+// persisted, nil is returned.
+func GetNodeMeta(tx *bolt.Tx) (map[string]*string, error) {
+	bkt := tx.Bucket(nodeMetaBucket)
+	if bkt == nil || bkt.Get(nodeMetaDynamicKey) == nil {
+		return nil, nil
+	}
+
+	var meta map[string]*string
+	if err := GetObject(bkt, nodeMetaDynamicKey, &meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// pluginKey returns the key the reattach config of a plugin is stored at.
+func pluginKey(id loader.PluginID) []byte {
+	return []byte(id.PluginType + "/" + id.Name)
+}
+
+// PutPluginReattachConfig persists how to reattach to a launched plugin.
+func PutPluginReattachConfig(tx *bolt.Tx, id loader.PluginID, config *loader.ReattachConfig) error {
+	bkt, err := tx.CreateBucketIfNotExists(pluginsBucket)
+	if err != nil {
+		return err
+	}
+
+	return PutObject(bkt, pluginKey(id), config)
+}
+
+// GetPluginReattachConfigs returns the persisted reattach configs of the
+// launched plugins.
+func GetPluginReattachConfigs(tx *bolt.Tx) (map[loader.PluginID]*loader.ReattachConfig, error) {
+	bkt := tx.Bucket(pluginsBucket)
+	if bkt == nil {
+		return nil, nil
+	}
+
+	configs := make(map[loader.PluginID]*loader.ReattachConfig)
+	err := bkt.ForEach(func(k, v []byte) error {
+		parts := strings.SplitN(string(k), "/", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid plugin key %q", string(k))
+		}
+
+		var config loader.ReattachConfig
+		if err := GetObject(bkt, k, &config); err != nil {
+			return err
+		}
+
+		id := loader.PluginID{
+			PluginType: parts[0],
+			Name:       parts[1],
+		}
+		configs[id] = &config
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+// DeletePluginReattachConfig deletes the reattach config of a plugin if it
+// exists.
+func DeletePluginReattachConfig(tx *bolt.Tx, id loader.PluginID) error {
+	bkt := tx.Bucket(pluginsBucket)
+	if bkt == nil {
+		return nil
+	}
+
+	return bkt.Delete(pluginKey(id))
+}
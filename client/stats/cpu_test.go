@@ -29,7 +29,7 @@ func TestHostStats_CPU(t *testing.T) {
 	logger := testlog.Logger(t)
 	cwd, err := os.Getwd()
 	assert.Nil(err)
-	hs := NewHostStatsCollector(logger, cwd)
+	hs := NewHostStatsCollector(logger, cwd, nil)
 
 	// Collect twice so we can calculate percents we need to generate some work
 	// so that the cpu values change
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hashicorp/nomad/plugins/device"
 	"github.com/shirou/gopsutil/cpu"
 	"github.com/shirou/gopsutil/disk"
 	"github.com/shirou/gopsutil/host"
@@ -20,6 +21,7 @@ type HostStats struct {
 	CPU              []*CPUStats
 	DiskStats        []*DiskStats
 	AllocDirStats    *DiskStats
+	DeviceStats      []*device.DeviceGroupStats
 	Uptime           uint64
 	Timestamp        int64
 	CPUTicksConsumed float64
@@ -69,15 +71,24 @@ type HostStatsCollector struct {
 	hostStatsLock   sync.RWMutex
 	allocDir        string
 
+	// deviceStatsCollector returns the latest statistics of the devices of
+	// the device plugins
+	deviceStatsCollector DeviceStatsCollector
+
 	// badParts is a set of partitions whose usage cannot be read; used to
 	// squelch logspam.
 	badParts map[string]struct{}
 }
 
+// DeviceStatsCollector is used to retrieve the statistics of the devices
+// exposed by the device plugins
+type DeviceStatsCollector func() []*device.DeviceGroupStats
+
 // NewHostStatsCollector returns a HostStatsCollector. The allocDir is passed in
 // so that we can present the disk related statistics for the mountpoint where
-// the allocation directory lives
-func NewHostStatsCollector(logger *log.Logger, allocDir string) *HostStatsCollector {
+// the allocation directory lives. The device statistics are retrieved from the
+// optional deviceStatsCollector.
+func NewHostStatsCollector(logger *log.Logger, allocDir string, deviceStatsCollector DeviceStatsCollector) *HostStatsCollector {
 	numCores := runtime.NumCPU()
 	statsCalculator := make(map[string]*HostCpuStatsCalculator)
 	collector := &HostStatsCollector{
@@ -86,6 +97,8 @@ func NewHostStatsCollector(logger *log.Logger, allocDir string) *HostStatsCollec
 		logger:          logger,
 		allocDir:        allocDir,
 		badParts:        make(map[string]struct{}),
+
+		deviceStatsCollector: deviceStatsCollector,
 	}
 	return collector
 }
@@ -138,6 +151,11 @@ func (h *HostStatsCollector) collectLocked() error {
 	}
 	hs.AllocDirStats = h.toDiskStats(usage, nil)
 
+	// Collect the statistics of the devices
+	if h.deviceStatsCollector != nil {
+		hs.DeviceStats = h.deviceStatsCollector()
+	}
+
 	// Update the collected status object.
 	h.hostStats = hs
 
@@ -0,0 +1,86 @@
+package structs
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ClientCSIControllerAttachVolumeRequest is used to ask the controller plugin
+// running on a client to attach a volume to the node of an allocation.
+type ClientCSIControllerAttachVolumeRequest struct {
+	// ControllerNodeID is the node running the controller plugin.
+	ControllerNodeID string
+
+	// PluginID is the plugin providing the volume.
+	PluginID string
+
+	// VolumeID is the volume's ID in the storage provider.
+	VolumeID string
+
+	// ClientCSINodeID is the identity of the target node as known by the
+	// storage provider.
+	ClientCSINodeID string
+
+	AttachmentMode structs.CSIVolumeAttachmentMode
+	AccessMode     structs.CSIVolumeAccessMode
+	MountOptions   *structs.CSIMountOptions
+	ReadOnly       bool
+
+	// VolumeContext is the context of the volume given at registration.
+	VolumeContext map[string]string
+
+	structs.QueryOptions
+}
+
+// ClientCSIControllerAttachVolumeResponse is the response of an attach.
+type ClientCSIControllerAttachVolumeResponse struct {
+	// PublishContext is passed to the node plugin when the volume is staged
+	// and published.
+	PublishContext map[string]string
+
+	structs.QueryMeta
+}
+
+// ClientCSIControllerDetachVolumeRequest is used to ask the controller plugin
+// running on a client to detach a volume from a node.
+type ClientCSIControllerDetachVolumeRequest struct {
+	// ControllerNodeID is the node running the controller plugin.
+	ControllerNodeID string
+
+	// PluginID is the plugin providing the volume.
+	PluginID string
+
+	// VolumeID is the volume's ID in the storage provider.
+	VolumeID string
+
+	// ClientCSINodeID is the identity of the node to detach the volume from,
+	// as known by the storage provider.
+	ClientCSINodeID string
+
+	structs.QueryOptions
+}
+
+// ClientCSIControllerDetachVolumeResponse is the response of a detach.
+type ClientCSIControllerDetachVolumeResponse struct {
+	structs.QueryMeta
+}
+
+// ClientCSINodeDetachVolumeRequest is used to ask a client to unpublish a
+// volume claimed by an allocation that has stopped, and to release the claim.
+type ClientCSINodeDetachVolumeRequest struct {
+	// NodeID is the node the volume is published on.
+	NodeID string
+
+	// AllocID is the allocation claiming the volume.
+	AllocID string
+
+	// VolumeID and VolumeNamespace identify the volume in Nomad.
+	VolumeID        string
+	VolumeNamespace string
+
+	structs.QueryOptions
+}
+
+// ClientCSINodeDetachVolumeResponse is the response of a node detach.
+type ClientCSINodeDetachVolumeResponse struct {
+	structs.QueryMeta
+}
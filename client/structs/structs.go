@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/stats"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/device"
 )
 
 // RpcError is used for serializing errors with a potential error code
@@ -36,6 +37,32 @@ type ClientStatsResponse struct {
 	structs.QueryMeta
 }
 
+// NodeMetaApplyRequest is used to update the dynamic meta of a node.
+type NodeMetaApplyRequest struct {
+	// NodeID is the node to update.
+	NodeID string
+
+	// Meta is the set of meta changes to apply. A nil value unsets the key,
+	// even if it was set in the client's configuration.
+	Meta map[string]*string
+
+	structs.WriteRequest
+}
+
+// NodeMetaResponse is used to return the meta of a node.
+type NodeMetaResponse struct {
+	// Meta is the node's effective meta, as used for scheduling.
+	Meta map[string]string
+
+	// Dynamic is the meta set through the API. Nil values are unset keys.
+	Dynamic map[string]*string
+
+	// Static is the meta set in the client's configuration.
+	Static map[string]string
+
+	structs.QueryMeta
+}
+
 // AllocFileInfo holds information about a file inside the AllocDir
 type AllocFileInfo struct {
 	Name     string
@@ -165,11 +192,57 @@ type AllocStatsResponse struct {
 	structs.QueryMeta
 }
 
+// AllocRestartRequest is used to restart the tasks of an allocation
+type AllocRestartRequest struct {
+	// AllocID is the allocation to restart
+	AllocID string
+
+	// Task is an optional task to restart. All tasks of the allocation are
+	// restarted if it is empty.
+	Task string
+
+	structs.QueryOptions
+}
+
+// AllocSignalRequest is used to signal the tasks of an allocation
+type AllocSignalRequest struct {
+	// AllocID is the allocation to signal
+	AllocID string
+
+	// Task is an optional task to signal. All tasks of the allocation are
+	// signaled if it is empty.
+	Task string
+
+	// Signal is the name of the signal to send, such as SIGHUP
+	Signal string
+
+	structs.QueryOptions
+}
+
+// AllocExecRequest is the initial request for running an interactive command
+// inside a task of an allocation.
+type AllocExecRequest struct {
+	// AllocID is the allocation to run the command in
+	AllocID string
+
+	// Task is the task to run the command in
+	Task string
+
+	// Tty indicates whether a pseudo-terminal should be allocated
+	Tty bool
+
+	// Cmd is the command and its arguments to run
+	Cmd []string
+
+	structs.QueryOptions
+}
+
 // MemoryStats holds memory usage related stats
 type MemoryStats struct {
 	RSS            uint64
 	Cache          uint64
 	Swap           uint64
+	Usage          uint64
 	MaxUsage       uint64
 	KernelUsage    uint64
 	KernelMaxUsage uint64
@@ -182,6 +255,7 @@ func (ms *MemoryStats) Add(other *MemoryStats) {
 	ms.RSS += other.RSS
 	ms.Cache += other.Cache
 	ms.Swap += other.Swap
+	ms.Usage += other.Usage
 	ms.MaxUsage += other.MaxUsage
 	ms.KernelUsage += other.KernelUsage
 	ms.KernelMaxUsage += other.KernelMaxUsage
@@ -239,6 +313,10 @@ type AllocResourceUsage struct {
 	// Tasks contains the resource usage of each task
 	Tasks map[string]*TaskResourceUsage
 
+	// DeviceStats contains the statistics of the devices reserved for the
+	// allocation
+	DeviceStats []*device.DeviceGroupStats
+
 	// The max timestamp of all the Tasks
 	Timestamp int64
 }
@@ -433,3 +511,30 @@ func (h *HealthCheckResponse) AddDriverInfo(name string, driverInfo *structs.Dri
 
 	h.Drivers[name] = driverInfo
 }
+
+// PluginStatus is the status of a plugin launched by the client
+type PluginStatus struct {
+	// Name and Type identify the plugin
+	Name string
+	Type string
+
+	// PluginVersion is the version of the plugin and ApiVersion the version
+	// of the plugin API negotiated with it
+	PluginVersion string
+	ApiVersion    string
+
+	// Internal is whether the plugin runs in the agent's process
+	Internal bool
+
+	// Healthy is whether the plugin is running and the driver or device it
+	// implements is healthy
+	Healthy           bool
+	HealthDescription string
+
+	// Restarts is the number of times the plugin was relaunched after it
+	// exited
+	Restarts int
+
+	// UpdateTime is the time the status of the plugin last changed
+	UpdateTime time.Time
+}
@@ -0,0 +1,157 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLAuthMethodApplyCommand struct {
+	Meta
+}
+
+func (c *ACLAuthMethodApplyCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method apply [options] <name> <path>
+
+  Apply is used to create or update an ACL auth method. The configuration of
+  the identity provider is read from the JSON file at the given path, or from
+  stdin if the path is "-". Requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Apply Options:
+
+  -type=""
+    Specifies the type of the auth method, either "OIDC" or "JWT".
+
+  -max-token-ttl=""
+    Specifies the lifetime of the tokens minted by the auth method, such as
+    "1h".
+
+  -token-locality="local"
+    Specifies whether the tokens minted by the auth method are "local" to the
+    region or "global".
+
+  -default=false
+    Sets the auth method as the default auth method used for logins that
+    don't specify one.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLAuthMethodApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"type":           complete.PredictSet("OIDC", "JWT"),
+			"max-token-ttl":  complete.PredictAnything,
+			"token-locality": complete.PredictSet("local", "global"),
+			"default":        complete.PredictNothing,
+		})
+}
+
+func (c *ACLAuthMethodApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*.json")
+}
+
+func (c *ACLAuthMethodApplyCommand) Synopsis() string {
+	return "Create or update an ACL auth method"
+}
+
+func (c *ACLAuthMethodApplyCommand) Name() string { return "acl auth-method apply" }
+
+func (c *ACLAuthMethodApplyCommand) Run(args []string) int {
+	var methodType, maxTokenTTL, tokenLocality string
+	var isDefault bool
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&methodType, "type", "", "")
+	flags.StringVar(&maxTokenTTL, "max-token-ttl", "", "")
+	flags.StringVar(&tokenLocality, "token-locality", api.ACLAuthMethodTokenLocalityLocal, "")
+	flags.BoolVar(&isDefault, "default", false, "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got two arguments
+	args = flags.Args()
+	if l := len(args); l != 2 {
+		c.Ui.Error("This command takes two arguments: <name> and <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if methodType == "" {
+		c.Ui.Error("The auth method type must be specified with -type")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	ttl, err := time.ParseDuration(maxTokenTTL)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid -max-token-ttl %q: %v", maxTokenTTL, err))
+		return 1
+	}
+
+	// Get the auth method name
+	methodName := args[0]
+
+	// Read the file contents
+	file := args[1]
+	var rawConfig []byte
+	if file == "-" {
+		rawConfig, err = ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read stdin: %v", err))
+			return 1
+		}
+	} else {
+		rawConfig, err = ioutil.ReadFile(file)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read file: %v", err))
+			return 1
+		}
+	}
+
+	var config api.ACLAuthMethodConfig
+	if err := json.Unmarshal(rawConfig, &config); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse auth method config: %v", err))
+		return 1
+	}
+
+	// Construct the auth method
+	am := &api.ACLAuthMethod{
+		Name:          methodName,
+		Type:          methodType,
+		TokenLocality: tokenLocality,
+		MaxTokenTTL:   ttl,
+		Default:       isDefault,
+		Config:        &config,
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Upsert the auth method
+	_, err = client.ACLAuthMethods().Upsert(am, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing ACL auth method: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully wrote %q ACL auth method!",
+		methodName))
+	return 0
+}
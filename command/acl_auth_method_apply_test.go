@@ -0,0 +1,74 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACLAuthMethodApplyCommand(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	assert.NotNil(token, "failed to bootstrap ACL token")
+
+	// Create a config file
+	f, err := ioutil.TempFile("", "nomad-test")
+	assert.Nil(err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`{
+  "OIDCDiscoveryURL": "https://example.com",
+  "OIDCClientID": "nomad",
+  "OIDCClientSecret": "secret",
+  "AllowedRedirectURIs": ["http://localhost:4649/oidc/callback"],
+  "ListClaimMappings": {"groups": "groups"}
+}`)
+	assert.Nil(err)
+	assert.Nil(f.Close())
+
+	ui := new(cli.MockUi)
+	cmd := &ACLAuthMethodApplyCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Attempt to apply an auth method without a type
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-max-token-ttl=1h", "example", f.Name()})
+	assert.Equal(1, code)
+
+	// Attempt to apply an auth method without a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=foo", "-type=OIDC",
+		"-max-token-ttl=1h", "example", f.Name()})
+	assert.Equal(1, code)
+
+	// Apply an auth method with a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-type=OIDC",
+		"-max-token-ttl=1h", "-default", "example", f.Name()})
+	assert.Equal(0, code)
+
+	// Check the output
+	out := ui.OutputWriter.String()
+	if !strings.Contains(out, "Successfully wrote") {
+		t.Fatalf("bad: %v", out)
+	}
+
+	// Check the auth method
+	method, err := srv.Agent.Server().State().ACLAuthMethodByName(nil, "example")
+	assert.Nil(err)
+	assert.True(method.Default)
+	assert.Equal(structs.ACLAuthMethodTokenLocalityLocal, method.TokenLocality)
+	assert.Equal("nomad", method.Config.OIDCClientID)
+	assert.Equal(map[string]string{"groups": "groups"}, method.Config.ListClaimMappings)
+}
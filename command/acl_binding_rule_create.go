@@ -0,0 +1,106 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLBindingRuleCreateCommand struct {
+	Meta
+}
+
+func (c *ACLBindingRuleCreateCommand) Help() string {
+	helpText := `
+Usage: nomad acl binding-rule create [options]
+
+  Create is used to create a new ACL binding rule. Requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Create Options:
+
+  -auth-method=""
+    Specifies the name of the auth method the binding rule applies to.
+
+  -description=""
+    Specifies a human readable description for the binding rule.
+
+  -selector=""
+    Specifies the expression matched against the claims of the identity, such
+    as 'value.team == "ops" and "admins" in list.groups'. The binding rule
+    applies to all identities if unset.
+
+  -bind-type=""
+    Specifies whether the binding rule grants a "policy" or a "role".
+
+  -bind-name=""
+    Specifies the name of the policy or role granted. It may interpolate the
+    mapped claims, such as "team-${value.team}".
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLBindingRuleCreateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"auth-method": complete.PredictAnything,
+			"description": complete.PredictAnything,
+			"selector":    complete.PredictAnything,
+			"bind-type":   complete.PredictSet("policy", "role"),
+			"bind-name":   complete.PredictAnything,
+		})
+}
+
+func (c *ACLBindingRuleCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLBindingRuleCreateCommand) Synopsis() string {
+	return "Create a new ACL binding rule"
+}
+
+func (c *ACLBindingRuleCreateCommand) Name() string { return "acl binding-rule create" }
+
+func (c *ACLBindingRuleCreateCommand) Run(args []string) int {
+	var rule api.ACLBindingRule
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&rule.AuthMethod, "auth-method", "", "")
+	flags.StringVar(&rule.Description, "description", "", "")
+	flags.StringVar(&rule.Selector, "selector", "", "")
+	flags.StringVar(&rule.BindType, "bind-type", "", "")
+	flags.StringVar(&rule.BindName, "bind-name", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Create the binding rule
+	out, _, err := client.ACLBindingRules().Create(&rule, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating ACL binding rule: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(formatKVBindingRule(out))
+	return 0
+}
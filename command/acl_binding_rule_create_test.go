@@ -0,0 +1,64 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACLBindingRuleCreateCommand(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	assert.NotNil(token, "failed to bootstrap ACL token")
+
+	// Create a test auth method
+	method := mock.ACLAuthMethod()
+	assert.Nil(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLBindingRuleCreateCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Attempt to create a binding rule for a missing auth method
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-auth-method=missing", "-bind-type=policy", "-bind-name=foo"})
+	assert.Equal(1, code)
+
+	// Attempt to create a binding rule without a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=foo",
+		"-auth-method=" + method.Name, "-bind-type=policy", "-bind-name=foo"})
+	assert.Equal(1, code)
+
+	// Create a binding rule with a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-auth-method=" + method.Name, "-selector=value.team == \"ops\"",
+		"-bind-type=role", "-bind-name=team-${value.team}"})
+	assert.Equal(0, code)
+
+	// Check the output
+	out := ui.OutputWriter.String()
+	if !strings.Contains(out, "team-${value.team}") {
+		t.Fatalf("bad: %v", out)
+	}
+
+	// Check the binding rule
+	iter, err := state.ACLBindingRulesByAuthMethod(nil, method.Name)
+	assert.Nil(err)
+	rule := iter.Next().(*structs.ACLBindingRule)
+	assert.Equal(structs.ACLBindingRuleBindTypeRole, rule.BindType)
+	assert.Equal("value.team == \"ops\"", rule.Selector)
+}
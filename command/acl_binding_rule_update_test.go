@@ -0,0 +1,54 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACLBindingRuleUpdateCommand(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	assert.NotNil(token, "failed to bootstrap ACL token")
+
+	// Create a test auth method and binding rule
+	method := mock.ACLAuthMethod()
+	assert.Nil(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	assert.Nil(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLBindingRuleUpdateCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Attempt to update the binding rule without a valid management token
+	code := cmd.Run([]string{"-address=" + url, "-token=foo", "-bind-name=bar", rule.ID})
+	assert.Equal(1, code)
+
+	// Update the binding rule, clearing its selector
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-bind-name=bar", "-selector=", rule.ID})
+	assert.Equal(0, code)
+
+	// Check the binding rule, unset fields are unchanged
+	out, err := state.ACLBindingRuleByID(nil, rule.ID)
+	assert.Nil(err)
+	assert.Equal("bar", out.BindName)
+	assert.Equal("", out.Selector)
+	assert.Equal(rule.Description, out.Description)
+	assert.Equal(rule.BindType, out.BindType)
+}
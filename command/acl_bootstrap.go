@@ -87,6 +87,63 @@ func formatKVPolicy(policy *api.ACLPolicy) string {
 	return formatKV(output)
 }
 
+// formatKVRole returns a K/V formatted ACL role
+func formatKVRole(role *api.ACLRole) string {
+	output := []string{
+		fmt.Sprintf("Name|%s", role.Name),
+		fmt.Sprintf("Description|%s", role.Description),
+		fmt.Sprintf("Policies|%v", role.Policies),
+		fmt.Sprintf("CreateIndex|%v", role.CreateIndex),
+		fmt.Sprintf("ModifyIndex|%v", role.ModifyIndex),
+	}
+	return formatKV(output)
+}
+
+// formatKVAuthMethod returns a K/V formatted ACL auth method
+func formatKVAuthMethod(method *api.ACLAuthMethod) string {
+	output := []string{
+		fmt.Sprintf("Name|%s", method.Name),
+		fmt.Sprintf("Type|%s", method.Type),
+		fmt.Sprintf("Token Locality|%s", method.TokenLocality),
+		fmt.Sprintf("Max Token TTL|%v", method.MaxTokenTTL),
+		fmt.Sprintf("Default|%v", method.Default),
+	}
+	if c := method.Config; c != nil {
+		if c.OIDCDiscoveryURL != "" {
+			output = append(output,
+				fmt.Sprintf("OIDC Discovery URL|%s", c.OIDCDiscoveryURL),
+				fmt.Sprintf("OIDC Client ID|%s", c.OIDCClientID),
+				fmt.Sprintf("OIDC Scopes|%v", c.OIDCScopes),
+				fmt.Sprintf("Allowed Redirect URIs|%v", c.AllowedRedirectURIs))
+		}
+		if c.JWKSURL != "" {
+			output = append(output, fmt.Sprintf("JWKS URL|%s", c.JWKSURL))
+		}
+		output = append(output,
+			fmt.Sprintf("Bound Audiences|%v", c.BoundAudiences),
+			fmt.Sprintf("Bound Issuer|%s", c.BoundIssuer))
+	}
+	output = append(output,
+		fmt.Sprintf("CreateIndex|%v", method.CreateIndex),
+		fmt.Sprintf("ModifyIndex|%v", method.ModifyIndex))
+	return formatKV(output)
+}
+
+// formatKVBindingRule returns a K/V formatted ACL binding rule
+func formatKVBindingRule(rule *api.ACLBindingRule) string {
+	output := []string{
+		fmt.Sprintf("ID|%s", rule.ID),
+		fmt.Sprintf("Description|%s", rule.Description),
+		fmt.Sprintf("Auth Method|%s", rule.AuthMethod),
+		fmt.Sprintf("Selector|%s", rule.Selector),
+		fmt.Sprintf("Bind Type|%s", rule.BindType),
+		fmt.Sprintf("Bind Name|%s", rule.BindName),
+		fmt.Sprintf("CreateIndex|%v", rule.CreateIndex),
+		fmt.Sprintf("ModifyIndex|%v", rule.ModifyIndex),
+	}
+	return formatKV(output)
+}
+
 // formatKVACLToken returns a K/V formatted ACL token
 func formatKVACLToken(token *api.ACLToken) string {
 	// Add the fixed preamble
@@ -104,10 +161,18 @@ func formatKVACLToken(token *api.ACLToken) string {
 	} else {
 		output = append(output, fmt.Sprintf("Policies|%v", token.Policies))
 	}
+	if len(token.Roles) != 0 {
+		output = append(output, fmt.Sprintf("Roles|%v", token.Roles))
+	}
 
 	// Add the generic output
+	expiry := "<none>"
+	if token.ExpirationTime != nil {
+		expiry = token.ExpirationTime.String()
+	}
 	output = append(output,
 		fmt.Sprintf("Create Time|%v", token.CreateTime),
+		fmt.Sprintf("Expiry Time|%s", expiry),
 		fmt.Sprintf("Create Index|%d", token.CreateIndex),
 		fmt.Sprintf("Modify Index|%d", token.ModifyIndex),
 	)
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACLRoleApplyCommand(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	assert.NotNil(token, "failed to bootstrap ACL token")
+
+	ui := new(cli.MockUi)
+	cmd := &ACLRoleApplyCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Attempt to apply a role without a policy
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "test-role"})
+	assert.Equal(1, code)
+
+	// Attempt to apply a role without a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=foo", "-policy=foo", "test-role"})
+	assert.Equal(1, code)
+
+	// Apply a role with a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-policy=foo", "-policy=bar", "test-role"})
+	assert.Equal(0, code)
+
+	// Check the output
+	out := ui.OutputWriter.String()
+	if !strings.Contains(out, "Successfully wrote") {
+		t.Fatalf("bad: %v", out)
+	}
+
+	// Check the role
+	role, err := srv.Agent.Server().State().ACLRoleByName(nil, "test-role")
+	assert.Nil(err)
+	assert.Equal([]string{"foo", "bar"}, role.Policies)
+}
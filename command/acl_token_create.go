@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/posener/complete"
@@ -36,6 +37,16 @@ Create Options:
   -policy=""
     Specifies a policy to associate with the token. Can be specified multiple times,
     but only with client type tokens.
+
+  -role=""
+    Specifies a role to associate with the token. The token is granted the
+    policies of the role. Can be specified multiple times, but only with client
+    type tokens.
+
+  -ttl=""
+    Specifies the time-to-live of the token, such as "8h". The token is
+    rejected once it has expired, and is then garbage collected. Tokens never
+    expire by default.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -47,6 +58,8 @@ func (c *ACLTokenCreateCommand) AutocompleteFlags() complete.Flags {
 			"type":   complete.PredictAnything,
 			"global": complete.PredictNothing,
 			"policy": complete.PredictAnything,
+			"role":   complete.PredictAnything,
+			"ttl":    complete.PredictAnything,
 		})
 }
 
@@ -61,18 +74,23 @@ func (c *ACLTokenCreateCommand) Synopsis() string {
 func (c *ACLTokenCreateCommand) Name() string { return "acl token create" }
 
 func (c *ACLTokenCreateCommand) Run(args []string) int {
-	var name, tokenType string
+	var name, tokenType, ttl string
 	var global bool
-	var policies []string
+	var policies, roles []string
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.StringVar(&name, "name", "", "")
 	flags.StringVar(&tokenType, "type", "client", "")
 	flags.BoolVar(&global, "global", false, "")
+	flags.StringVar(&ttl, "ttl", "", "")
 	flags.Var((funcVar)(func(s string) error {
 		policies = append(policies, s)
 		return nil
 	}), "policy", "")
+	flags.Var((funcVar)(func(s string) error {
+		roles = append(roles, s)
+		return nil
+	}), "role", "")
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -90,9 +108,24 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 		Name:     name,
 		Type:     tokenType,
 		Policies: policies,
+		Roles:    roles,
 		Global:   global,
 	}
 
+	// Parse the expiration TTL
+	if ttl != "" {
+		dur, err := time.ParseDuration(ttl)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error parsing TTL: %s", err))
+			return 1
+		}
+		if dur <= 0 {
+			c.Ui.Error("TTL must be positive")
+			return 1
+		}
+		tk.ExpirationTTL = dur
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
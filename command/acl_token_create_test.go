@@ -2,6 +2,7 @@ package command
 
 import (
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -13,6 +14,7 @@ import (
 func TestACLTokenCreateCommand(t *testing.T) {
 	assert := assert.New(t)
 	t.Parallel()
+	noExpiry := regexp.MustCompile(`Expiry Time\s+= <none>`)
 	config := func(c *agent.Config) {
 		c.ACL.Enabled = true
 	}
@@ -42,4 +44,20 @@ func TestACLTokenCreateCommand(t *testing.T) {
 	if !strings.Contains(out, "[foo]") {
 		t.Fatalf("bad: %v", out)
 	}
+	if !noExpiry.MatchString(out) {
+		t.Fatalf("expected no expiry: %v", out)
+	}
+	ui.OutputWriter.Reset()
+
+	// Create a token with an invalid TTL
+	code = cmd.Run([]string{"-address=" + url, "-policy=foo", "-ttl=bar"})
+	assert.Equal(1, code)
+
+	// Create a token with a TTL
+	code = cmd.Run([]string{"-address=" + url, "-policy=foo", "-ttl=1h"})
+	assert.Equal(0, code)
+	out = ui.OutputWriter.String()
+	if noExpiry.MatchString(out) || !strings.Contains(out, "Expiry Time") {
+		t.Fatalf("expected expiry: %v", out)
+	}
 }
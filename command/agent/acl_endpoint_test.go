@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/nomad/helper/oidc/oidctest"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/assert"
@@ -187,6 +189,457 @@ func TestHTTP_ACLPolicyDelete(t *testing.T) {
 	})
 }
 
+func TestHTTP_ACLRoleList(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		r1 := mock.ACLRole()
+		r2 := mock.ACLRole()
+		args := structs.ACLRoleUpsertRequest{
+			Roles: []*structs.ACLRole{r1, r2},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: s.RootToken.SecretID,
+			},
+		}
+		var resp structs.GenericResponse
+		if err := s.Agent.RPC("ACL.UpsertRoles", &args, &resp); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/acl/roles", nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		// Make the request
+		obj, err := s.Server.ACLRolesRequest(respW, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Check for the index
+		if respW.HeaderMap.Get("X-Nomad-Index") == "" {
+			t.Fatalf("missing index")
+		}
+
+		// Check the output
+		n := obj.([]*structs.ACLRoleListStub)
+		if len(n) != 2 {
+			t.Fatalf("bad: %#v", n)
+		}
+	})
+}
+
+func TestHTTP_ACLRoleQuery(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		r1 := mock.ACLRole()
+		args := structs.ACLRoleUpsertRequest{
+			Roles: []*structs.ACLRole{r1},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: s.RootToken.SecretID,
+			},
+		}
+		var resp structs.GenericResponse
+		if err := s.Agent.RPC("ACL.UpsertRoles", &args, &resp); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/acl/role/"+r1.Name, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		// Make the request
+		obj, err := s.Server.ACLRoleSpecificRequest(respW, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Check for the index
+		if respW.HeaderMap.Get("X-Nomad-Index") == "" {
+			t.Fatalf("missing index")
+		}
+
+		// Check the output
+		n := obj.(*structs.ACLRole)
+		if n.Name != r1.Name {
+			t.Fatalf("bad: %#v", n)
+		}
+
+		// Query a missing role
+		req, err = http.NewRequest("GET", "/v1/acl/role/missing", nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLRoleSpecificRequest(httptest.NewRecorder(), req)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "ACL role not found")
+	})
+}
+
+func TestHTTP_ACLRoleCreate(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		// Make the HTTP request
+		r1 := mock.ACLRole()
+		buf := encodeReq(r1)
+		req, err := http.NewRequest("PUT", "/v1/acl/role/"+r1.Name, buf)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		// Make the request
+		obj, err := s.Server.ACLRoleSpecificRequest(respW, req)
+		assert.Nil(t, err)
+		assert.Nil(t, obj)
+
+		// Check for the index
+		if respW.HeaderMap.Get("X-Nomad-Index") == "" {
+			t.Fatalf("missing index")
+		}
+
+		// Check role was created
+		state := s.Agent.server.State()
+		out, err := state.ACLRoleByName(nil, r1.Name)
+		assert.Nil(t, err)
+		assert.NotNil(t, out)
+
+		r1.CreateIndex, r1.ModifyIndex = out.CreateIndex, out.ModifyIndex
+		assert.Equal(t, r1, out)
+	})
+}
+
+func TestHTTP_ACLRoleDelete(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		r1 := mock.ACLRole()
+		args := structs.ACLRoleUpsertRequest{
+			Roles: []*structs.ACLRole{r1},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: s.RootToken.SecretID,
+			},
+		}
+		var resp structs.GenericResponse
+		if err := s.Agent.RPC("ACL.UpsertRoles", &args, &resp); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Make the HTTP request
+		req, err := http.NewRequest("DELETE", "/v1/acl/role/"+r1.Name, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		// Make the request
+		obj, err := s.Server.ACLRoleSpecificRequest(respW, req)
+		assert.Nil(t, err)
+		assert.Nil(t, obj)
+
+		// Check for the index
+		if respW.HeaderMap.Get("X-Nomad-Index") == "" {
+			t.Fatalf("missing index")
+		}
+
+		// Check role was deleted
+		state := s.Agent.server.State()
+		out, err := state.ACLRoleByName(nil, r1.Name)
+		assert.Nil(t, err)
+		assert.Nil(t, out)
+	})
+}
+
+func TestHTTP_ACLAuthMethodList(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		m1 := mock.ACLAuthMethod()
+		m2 := mock.ACLAuthMethod()
+		args := structs.ACLAuthMethodUpsertRequest{
+			AuthMethods: []*structs.ACLAuthMethod{m1, m2},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: s.RootToken.SecretID,
+			},
+		}
+		var resp structs.GenericResponse
+		if err := s.Agent.RPC("ACL.UpsertAuthMethods", &args, &resp); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Make the HTTP request, listing doesn't require a token
+		req, err := http.NewRequest("GET", "/v1/acl/auth-methods", nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.ACLAuthMethodsRequest(respW, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Check for the index
+		if respW.HeaderMap.Get("X-Nomad-Index") == "" {
+			t.Fatalf("missing index")
+		}
+
+		// Check the output
+		n := obj.([]*structs.ACLAuthMethodListStub)
+		if len(n) != 2 {
+			t.Fatalf("bad: %#v", n)
+		}
+	})
+}
+
+func TestHTTP_ACLAuthMethodCRUD(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		// Create the auth method
+		m1 := mock.ACLAuthMethod()
+		req, err := http.NewRequest("PUT", "/v1/acl/auth-method/"+m1.Name, encodeReq(m1))
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err := s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		assert.Nil(t, err)
+		assert.Nil(t, obj)
+		if respW.HeaderMap.Get("X-Nomad-Index") == "" {
+			t.Fatalf("missing index")
+		}
+
+		// A name mismatch is rejected
+		req, err = http.NewRequest("PUT", "/v1/acl/auth-method/other", encodeReq(m1))
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLAuthMethodSpecificRequest(httptest.NewRecorder(), req)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "does not match request path")
+
+		// Query the auth method
+		req, err = http.NewRequest("GET", "/v1/acl/auth-method/"+m1.Name, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if respW.HeaderMap.Get("X-Nomad-Index") == "" {
+			t.Fatalf("missing index")
+		}
+		out := obj.(*structs.ACLAuthMethod)
+		assert.Equal(t, m1.Name, out.Name)
+		assert.Equal(t, m1.Config.OIDCClientID, out.Config.OIDCClientID)
+
+		// Delete the auth method
+		req, err = http.NewRequest("DELETE", "/v1/acl/auth-method/"+m1.Name, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLAuthMethodSpecificRequest(httptest.NewRecorder(), req)
+		assert.Nil(t, err)
+		assert.Nil(t, obj)
+
+		// Query the deleted auth method
+		req, err = http.NewRequest("GET", "/v1/acl/auth-method/"+m1.Name, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLAuthMethodSpecificRequest(httptest.NewRecorder(), req)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "ACL auth method not found")
+	})
+}
+
+func TestHTTP_ACLBindingRuleCRUD(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		m1 := mock.ACLAuthMethod()
+		args := structs.ACLAuthMethodUpsertRequest{
+			AuthMethods: []*structs.ACLAuthMethod{m1},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: s.RootToken.SecretID,
+			},
+		}
+		var resp structs.GenericResponse
+		if err := s.Agent.RPC("ACL.UpsertAuthMethods", &args, &resp); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Create the binding rule, the ID is generated
+		r1 := mock.ACLBindingRule()
+		r1.ID = ""
+		r1.AuthMethod = m1.Name
+		req, err := http.NewRequest("PUT", "/v1/acl/binding-rule", encodeReq(r1))
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err := s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if respW.HeaderMap.Get("X-Nomad-Index") == "" {
+			t.Fatalf("missing index")
+		}
+		created := obj.(*structs.ACLBindingRule)
+		assert.NotEmpty(t, created.ID)
+		assert.Equal(t, r1.Selector, created.Selector)
+
+		// Update the binding rule
+		created.Description = "updated"
+		req, err = http.NewRequest("PUT", "/v1/acl/binding-rule/"+created.ID, encodeReq(created))
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLBindingRuleSpecificRequest(httptest.NewRecorder(), req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		assert.Equal(t, "updated", obj.(*structs.ACLBindingRule).Description)
+
+		// List the binding rules of the auth method
+		req, err = http.NewRequest("GET", "/v1/acl/binding-rules?auth_method="+m1.Name, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLBindingRulesRequest(httptest.NewRecorder(), req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		assert.Len(t, obj.([]*structs.ACLBindingRuleListStub), 1)
+
+		// Query the binding rule
+		req, err = http.NewRequest("GET", "/v1/acl/binding-rule/"+created.ID, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLBindingRuleSpecificRequest(httptest.NewRecorder(), req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		assert.Equal(t, created.ID, obj.(*structs.ACLBindingRule).ID)
+
+		// Delete the binding rule
+		req, err = http.NewRequest("DELETE", "/v1/acl/binding-rule/"+created.ID, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLBindingRuleSpecificRequest(httptest.NewRecorder(), req)
+		assert.Nil(t, err)
+		assert.Nil(t, obj)
+
+		out, err := s.Agent.server.State().ACLBindingRuleByID(nil, created.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, out)
+	})
+}
+
+func TestHTTP_ACLLogin(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		provider := oidctest.NewProvider(t)
+		defer provider.Close()
+
+		policy := mock.ACLPolicy()
+		method := &structs.ACLAuthMethod{
+			Name:        "jwt",
+			Type:        structs.ACLAuthMethodTypeJWT,
+			MaxTokenTTL: time.Hour,
+			Config: &structs.ACLAuthMethodConfig{
+				JWTValidationPubKeys: []string{provider.PublicKeyPEM()},
+				BoundIssuer:          provider.Issuer(),
+			},
+		}
+		rule := &structs.ACLBindingRule{
+			AuthMethod: method.Name,
+			BindType:   structs.ACLBindingRuleBindTypePolicy,
+			BindName:   policy.Name,
+		}
+		wr := structs.WriteRequest{Region: "global", AuthToken: s.RootToken.SecretID}
+		var resp structs.GenericResponse
+		if err := s.Agent.RPC("ACL.UpsertPolicies", &structs.ACLPolicyUpsertRequest{
+			Policies: []*structs.ACLPolicy{policy}, WriteRequest: wr}, &resp); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if err := s.Agent.RPC("ACL.UpsertAuthMethods", &structs.ACLAuthMethodUpsertRequest{
+			AuthMethods: []*structs.ACLAuthMethod{method}, WriteRequest: wr}, &resp); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		var ruleResp structs.ACLBindingRuleUpsertResponse
+		if err := s.Agent.RPC("ACL.UpsertBindingRules", &structs.ACLBindingRuleUpsertRequest{
+			BindingRules: []*structs.ACLBindingRule{rule}, WriteRequest: wr}, &ruleResp); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Login with a token issued by the provider
+		login := &structs.ACLLoginRequest{
+			AuthMethodName: method.Name,
+			LoginToken:     provider.SignJWT(map[string]interface{}{"sub": "alice"}),
+		}
+		req, err := http.NewRequest("POST", "/v1/acl/login", encodeReq(login))
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.ACLLoginRequest(respW, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if respW.HeaderMap.Get("X-Nomad-Index") == "" {
+			t.Fatalf("missing index")
+		}
+		token := obj.(*structs.ACLToken)
+		assert.Equal(t, []string{policy.Name}, token.Policies)
+		assert.Equal(t, "jwt login: alice", token.Name)
+		assert.NotNil(t, token.ExpirationTime)
+
+		// A token signed by another key is rejected
+		other := oidctest.NewProvider(t)
+		defer other.Close()
+		login.LoginToken = other.SignJWT(map[string]interface{}{"sub": "alice", "iss": provider.Issuer()})
+		req, err = http.NewRequest("POST", "/v1/acl/login", encodeReq(login))
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		_, err = s.Server.ACLLoginRequest(httptest.NewRecorder(), req)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), structs.ErrPermissionDenied.Error())
+	})
+}
+
 func TestHTTP_ACLTokenBootstrap(t *testing.T) {
 	t.Parallel()
 	conf := func(c *Config) {
@@ -273,6 +273,48 @@ func convertServerConfig(agentConfig *Config, logOutput io.Writer) (*nomad.Confi
 		}
 		conf.DeploymentGCThreshold = dur
 	}
+	if gcThreshold := agentConfig.Server.ACLTokenGCThreshold; gcThreshold != "" {
+		dur, err := time.ParseDuration(gcThreshold)
+		if err != nil {
+			return nil, err
+		}
+		conf.ACLTokenGCThreshold = dur
+	}
+	if threshold := agentConfig.Server.RootKeyRotationThreshold; threshold != "" {
+		dur, err := time.ParseDuration(threshold)
+		if err != nil {
+			return nil, err
+		}
+		conf.RootKeyRotationThreshold = dur
+	}
+	conf.OIDCIssuer = agentConfig.Server.OIDCIssuer
+
+	// Set the admission webhooks
+	for _, hook := range agentConfig.Server.AdmissionWebhooks {
+		if err := hook.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid admission_webhook %q: %v", hook.Name, err)
+		}
+		hook = hook.Copy()
+		hook.Canonicalize()
+		conf.AdmissionWebhooks = append(conf.AdmissionWebhooks, hook)
+	}
+
+	// Set the namespace weights of the eval broker
+	for ns, w := range agentConfig.Server.EvalNamespaceWeights {
+		if w < 1 {
+			return nil, fmt.Errorf("eval_namespace_weights: weight of namespace %q must be at least 1", ns)
+		}
+	}
+	conf.EvalNamespaceWeights = agentConfig.Server.EvalNamespaceWeights
+	conf.MemoryOversubscriptionEnabled = agentConfig.Server.MemoryOversubscriptionEnabled
+
+	// Set the RPC rate limits
+	if limit := agentConfig.Server.RPCRateLimit; limit != nil {
+		if err := limit.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid rpc_rate_limit: %v", err)
+		}
+		conf.RPCRateLimit = limit.Copy()
+	}
 
 	if heartbeatGrace := agentConfig.Server.HeartbeatGrace; heartbeatGrace != 0 {
 		conf.HeartbeatGrace = heartbeatGrace
@@ -344,7 +386,12 @@ func (a *Agent) clientConfig() (*clientconfig.Config, error) {
 	if a.config.DataDir != "" {
 		conf.StateDir = filepath.Join(a.config.DataDir, "client")
 		conf.AllocDir = filepath.Join(a.config.DataDir, "alloc")
+		conf.PluginDir = filepath.Join(a.config.DataDir, "plugins")
 	}
+	if a.config.PluginDir != "" {
+		conf.PluginDir = a.config.PluginDir
+	}
+	conf.PluginConfigs = a.config.Plugins
 	if a.config.Client.StateDir != "" {
 		conf.StateDir = a.config.Client.StateDir
 	}
@@ -355,6 +402,18 @@ func (a *Agent) clientConfig() (*clientconfig.Config, error) {
 		conf.NetworkInterface = a.config.Client.NetworkInterface
 	}
 	conf.ChrootEnv = a.config.Client.ChrootEnv
+
+	hvMap := make(map[string]*structs.ClientHostVolumeConfig, len(a.config.Client.HostVolumes))
+	for _, v := range a.config.Client.HostVolumes {
+		if !filepath.IsAbs(v.Path) {
+			return nil, fmt.Errorf("host_volume %q: path %q must be absolute", v.Name, v.Path)
+		}
+		if _, err := os.Stat(v.Path); err != nil {
+			return nil, fmt.Errorf("host_volume %q: failed to stat path %q: %v", v.Name, v.Path, err)
+		}
+		hvMap[v.Name] = v.Copy()
+	}
+	conf.HostVolumes = hvMap
 	conf.Options = a.config.Client.Options
 	// Logging deprecation messages about consul related configuration in client
 	// options
@@ -444,6 +503,7 @@ func (a *Agent) clientConfig() (*clientconfig.Config, error) {
 		// Default no_host_uuid to true
 		conf.NoHostUUID = true
 	}
+	conf.RecoverState = a.config.Client.RecoverState
 
 	// Setup the ACLs
 	conf.ACLEnabled = a.config.ACL.Enabled
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/serf/serf"
 	"github.com/mitchellh/copystructure"
@@ -77,6 +78,9 @@ func (s *HTTPServer) AgentSelfRequest(resp http.ResponseWriter, req *http.Reques
 		Member: nomadMember(member),
 		Stats:  s.agent.Stats(),
 	}
+	if client := s.agent.Client(); client != nil {
+		self.Plugins = client.PluginStatuses()
+	}
 	if ac, err := copystructure.Copy(s.agent.config); err != nil {
 		return nil, CodedError(500, err.Error())
 	} else {
@@ -291,9 +295,10 @@ func (s *HTTPServer) KeyringOperationRequest(resp http.ResponseWriter, req *http
 }
 
 type agentSelf struct {
-	Config *Config                      `json:"config"`
-	Member Member                       `json:"member,omitempty"`
-	Stats  map[string]map[string]string `json:"stats"`
+	Config  *Config                      `json:"config"`
+	Member  Member                       `json:"member,omitempty"`
+	Stats   map[string]map[string]string `json:"stats"`
+	Plugins []*cstructs.PluginStatus     `json:"plugins,omitempty"`
 }
 
 type joinResult struct {
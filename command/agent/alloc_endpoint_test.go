@@ -777,3 +777,112 @@ func TestHTTP_AllocAllGC_ACL(t *testing.T) {
 		}
 	})
 }
+
+func TestHTTP_AllocStop(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		alloc := mock.Alloc()
+		require.Nil(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+		require.Nil(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+		// Stopping requires a write
+		{
+			req, err := http.NewRequest("GET", "/v1/allocation/"+alloc.ID+"/stop", nil)
+			require.Nil(err)
+			respW := httptest.NewRecorder()
+
+			_, err = s.Server.AllocSpecificRequest(respW, req)
+			require.NotNil(err)
+			require.Equal(ErrInvalidMethod, err.Error())
+		}
+
+		// Make the HTTP request
+		req, err := http.NewRequest("PUT", "/v1/allocation/"+alloc.ID+"/stop", nil)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.AllocSpecificRequest(respW, req)
+		require.Nil(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Check the evaluation
+		resp := obj.(*structs.AllocStopResponse)
+		require.NotEmpty(resp.EvalID)
+	})
+}
+
+func TestHTTP_AllocRestart(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	path := fmt.Sprintf("/v1/client/allocation/%s/restart", uuid.Generate())
+	httpTest(t, nil, func(s *TestAgent) {
+		req, err := http.NewRequest("PUT", path, strings.NewReader(`{"Task": "web"}`))
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.True(structs.IsErrUnknownAllocation(err))
+	})
+}
+
+func TestHTTP_AllocSignal(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	path := fmt.Sprintf("/v1/client/allocation/%s/signal", uuid.Generate())
+	httpTest(t, nil, func(s *TestAgent) {
+		// A signal is required
+		{
+			req, err := http.NewRequest("PUT", path, strings.NewReader(`{}`))
+			require.Nil(err)
+			respW := httptest.NewRecorder()
+
+			_, err = s.Server.ClientAllocRequest(respW, req)
+			require.NotNil(err)
+			coded, ok := err.(HTTPCodedError)
+			require.True(ok)
+			require.Equal(400, coded.Code())
+		}
+
+		req, err := http.NewRequest("PUT", path, strings.NewReader(`{"Signal": "SIGUSR1"}`))
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.True(structs.IsErrUnknownAllocation(err))
+	})
+}
+
+func TestHTTP_AllocExec_InvalidRequest(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		cases := []struct {
+			name  string
+			query string
+		}{
+			{"missing task", "command=%5B%22%2Fbin%2Fsh%22%5D"},
+			{"missing command", "task=web"},
+			{"empty command", "task=web&command=%5B%5D"},
+			{"invalid command", "task=web&command=sh"},
+			{"invalid tty", "task=web&command=%5B%22%2Fbin%2Fsh%22%5D&tty=nope"},
+		}
+
+		for _, c := range cases {
+			path := fmt.Sprintf("/v1/client/allocation/%s/exec?%s", uuid.Generate(), c.query)
+			req, err := http.NewRequest("GET", path, nil)
+			require.Nil(err)
+			respW := httptest.NewRecorder()
+
+			_, err = s.Server.ClientAllocRequest(respW, req)
+			require.NotNil(err, c.name)
+
+			coded, ok := err.(HTTPCodedError)
+			require.True(ok, c.name)
+			require.Equal(400, coded.Code(), c.name)
+		}
+	})
+}
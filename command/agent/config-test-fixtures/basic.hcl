@@ -2,6 +2,7 @@ region = "foobar"
 datacenter = "dc2"
 name = "my-web"
 data_dir = "/tmp/nomad"
+plugin_dir = "/tmp/nomad-plugins"
 log_level = "ERR"
 bind_addr = "192.168.0.1"
 enable_debug = true
@@ -66,6 +67,14 @@ client {
 	gc_inode_usage_threshold = 91
 	gc_max_allocs = 50
 	no_host_uuid = false
+	recover_state = true
+	host_volume "tmp" {
+		path = "/tmp"
+	}
+	host_volume "certs" {
+		path = "/etc/ssl/certs"
+		read_only = true
+	}
 }
 server {
 	enabled = true
@@ -80,6 +89,9 @@ server {
 	job_gc_threshold = "12h"
 	eval_gc_threshold = "12h"
 	deployment_gc_threshold = "12h"
+	acl_token_gc_threshold = "12h"
+	root_key_rotation_threshold = "720h"
+	oidc_issuer = "https://nomad.example.com"
 	heartbeat_grace   = "30s"
 	min_heartbeat_ttl = "33s"
 	max_heartbeats_per_second = 11.0
@@ -91,12 +103,31 @@ server {
 	non_voting_server = true
 	redundancy_zone = "foo"
 	upgrade_version = "0.8.0"
+	memory_oversubscription_enabled = true
 	encrypt = "abc"
 	server_join {
 		retry_join = [ "1.1.1.1", "2.2.2.2" ]
 		retry_max = 3
 		retry_interval = "15s"
 	}
+	admission_webhook "owner" {
+		type = "validating"
+		url = "https://admission.example.com/owner"
+		timeout = "5s"
+		failure_policy = "ignore"
+		headers {
+			Authorization = "Bearer foo"
+		}
+	}
+	eval_namespace_weights {
+		default = 1
+		batch = 3
+	}
+	rpc_rate_limit {
+		read_rate = 100
+		read_burst = 200
+		write_rate = 10.5
+	}
 }
 acl {
 	enabled = true
@@ -190,3 +221,17 @@ autopilot {
 	server_stabilization_time = "23057s"
 	enable_custom_upgrades = true
 }
+plugin "docker" {
+	args = ["foo", "bar"]
+	config {
+		foo = "bar"
+		nested {
+			key = "value"
+		}
+	}
+}
+plugin "exec" {
+	config {
+		foo = true
+	}
+}
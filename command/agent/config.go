@@ -19,6 +19,7 @@ import (
 	client "github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/version"
 )
@@ -37,6 +38,10 @@ type Config struct {
 	// DataDir is the directory to store our state in
 	DataDir string `mapstructure:"data_dir"`
 
+	// PluginDir is the directory from which driver plugins are loaded.
+	// Defaults to the plugins directory of the data directory.
+	PluginDir string `mapstructure:"plugin_dir"`
+
 	// LogLevel is the level of the logs to putout
 	LogLevel string `mapstructure:"log_level"`
 
@@ -133,6 +138,9 @@ type Config struct {
 
 	// Autopilot contains the configuration for Autopilot behavior.
 	Autopilot *config.AutopilotConfig `mapstructure:"autopilot"`
+
+	// Plugins is the set of plugins configured by plugin blocks
+	Plugins []*config.PluginConfig `mapstructure:"plugin"`
 }
 
 // ClientConfig is configuration specific to the client mode
@@ -218,8 +226,16 @@ type ClientConfig struct {
 	// random UUID.
 	NoHostUUID *bool `mapstructure:"no_host_uuid"`
 
+	// RecoverState quarantines the state of the allocations that fail to be
+	// restored instead of failing to start the client.
+	RecoverState bool `mapstructure:"recover_state"`
+
 	// ServerJoin contains information that is used to attempt to join servers
 	ServerJoin *ServerJoin `mapstructure:"server_join"`
+
+	// HostVolumes contains information about the volumes an operator has made
+	// available to jobs running on this node.
+	HostVolumes []*structs.ClientHostVolumeConfig `mapstructure:"host_volume"`
 }
 
 // ACLConfig is configuration specific to the ACL system
@@ -298,6 +314,19 @@ type ServerConfig struct {
 	// GCed but the threshold can be used to filter by age.
 	DeploymentGCThreshold string `mapstructure:"deployment_gc_threshold"`
 
+	// ACLTokenGCThreshold controls how long an ACL token must have been
+	// expired to be collected by GC.
+	ACLTokenGCThreshold string `mapstructure:"acl_token_gc_threshold"`
+
+	// RootKeyRotationThreshold controls how "old" the active root key must be
+	// before the leader rotates it.
+	RootKeyRotationThreshold string `mapstructure:"root_key_rotation_threshold"`
+
+	// OIDCIssuer is the issuer of the workload identities. It should be the
+	// externally reachable address of the agents serving the OIDC discovery
+	// endpoints.
+	OIDCIssuer string `mapstructure:"oidc_issuer"`
+
 	// HeartbeatGrace is the grace period beyond the TTL to account for network,
 	// processing delays and clock skew before marking a node as "down".
 	HeartbeatGrace time.Duration `mapstructure:"heartbeat_grace"`
@@ -355,6 +384,22 @@ type ServerConfig struct {
 
 	// ServerJoin contains information that is used to attempt to join servers
 	ServerJoin *ServerJoin `mapstructure:"server_join"`
+
+	// AdmissionWebhooks are the external admission controllers called when
+	// jobs are registered or planned.
+	AdmissionWebhooks []*config.AdmissionWebhookConfig `mapstructure:"admission_webhook"`
+
+	// EvalNamespaceWeights is the relative share of evaluations dequeued for
+	// each namespace within a priority.
+	EvalNamespaceWeights map[string]int `mapstructure:"eval_namespace_weights"`
+
+	// RPCRateLimit is the rate limit applied to the RPCs made with each ACL
+	// token.
+	RPCRateLimit *config.RPCRateLimitConfig `mapstructure:"rpc_rate_limit"`
+
+	// MemoryOversubscriptionEnabled allows tasks to set a memory limit above
+	// the memory they reserve.
+	MemoryOversubscriptionEnabled bool `mapstructure:"memory_oversubscription_enabled"`
 }
 
 // ServerJoin is used in both clients and servers to bootstrap connections to
@@ -734,6 +779,9 @@ func (c *Config) Merge(b *Config) *Config {
 	if b.DataDir != "" {
 		result.DataDir = b.DataDir
 	}
+	if b.PluginDir != "" {
+		result.PluginDir = b.PluginDir
+	}
 	if b.LogLevel != "" {
 		result.LogLevel = b.LogLevel
 	}
@@ -855,6 +903,11 @@ func (c *Config) Merge(b *Config) *Config {
 		result.Autopilot = result.Autopilot.Merge(b.Autopilot)
 	}
 
+	// Merge the plugin configs by plugin name
+	if len(b.Plugins) != 0 {
+		result.Plugins = config.PluginConfigSetMerge(result.Plugins, b.Plugins)
+	}
+
 	// Merge config files lists
 	result.Files = append(result.Files, b.Files...)
 
@@ -1090,6 +1143,15 @@ func (a *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.DeploymentGCThreshold != "" {
 		result.DeploymentGCThreshold = b.DeploymentGCThreshold
 	}
+	if b.ACLTokenGCThreshold != "" {
+		result.ACLTokenGCThreshold = b.ACLTokenGCThreshold
+	}
+	if b.RootKeyRotationThreshold != "" {
+		result.RootKeyRotationThreshold = b.RootKeyRotationThreshold
+	}
+	if b.OIDCIssuer != "" {
+		result.OIDCIssuer = b.OIDCIssuer
+	}
 	if b.HeartbeatGrace != 0 {
 		result.HeartbeatGrace = b.HeartbeatGrace
 	}
@@ -1111,6 +1173,9 @@ func (a *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.NonVotingServer {
 		result.NonVotingServer = true
 	}
+	if b.MemoryOversubscriptionEnabled {
+		result.MemoryOversubscriptionEnabled = true
+	}
 	if b.RedundancyZone != "" {
 		result.RedundancyZone = b.RedundancyZone
 	}
@@ -1127,6 +1192,28 @@ func (a *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
+	// Add the admission webhooks
+	result.AdmissionWebhooks = append(result.AdmissionWebhooks, b.AdmissionWebhooks...)
+
+	// Merge the namespace weights
+	if len(b.EvalNamespaceWeights) != 0 {
+		weights := make(map[string]int, len(a.EvalNamespaceWeights)+len(b.EvalNamespaceWeights))
+		for ns, w := range a.EvalNamespaceWeights {
+			weights[ns] = w
+		}
+		for ns, w := range b.EvalNamespaceWeights {
+			weights[ns] = w
+		}
+		result.EvalNamespaceWeights = weights
+	}
+
+	// Merge the rate limits
+	if result.RPCRateLimit == nil && b.RPCRateLimit != nil {
+		result.RPCRateLimit = b.RPCRateLimit.Copy()
+	} else if b.RPCRateLimit != nil {
+		result.RPCRateLimit = result.RPCRateLimit.Merge(b.RPCRateLimit)
+	}
+
 	// Copy the start join addresses
 	result.StartJoin = make([]string, 0, len(a.StartJoin)+len(b.StartJoin))
 	result.StartJoin = append(result.StartJoin, a.StartJoin...)
@@ -1202,6 +1289,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.NoHostUUID != nil {
 		result.NoHostUUID = b.NoHostUUID
 	}
+	if b.RecoverState {
+		result.RecoverState = true
+	}
 
 	// Add the servers
 	result.Servers = append(result.Servers, b.Servers...)
@@ -1234,6 +1324,21 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.ServerJoin = result.ServerJoin.Merge(b.ServerJoin)
 	}
 
+	// Volumes of the same name are replaced
+	if len(b.HostVolumes) != 0 {
+		volumes := make([]*structs.ClientHostVolumeConfig, 0, len(a.HostVolumes)+len(b.HostVolumes))
+		names := make(map[string]struct{}, len(b.HostVolumes))
+		for _, v := range b.HostVolumes {
+			names[v.Name] = struct{}{}
+		}
+		for _, v := range a.HostVolumes {
+			if _, ok := names[v.Name]; !ok {
+				volumes = append(volumes, v)
+			}
+		}
+		result.HostVolumes = append(volumes, b.HostVolumes...)
+	}
+
 	return &result
 }
 
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/hcl/hcl/ast"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/mitchellh/mapstructure"
 )
@@ -78,6 +79,7 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 		"datacenter",
 		"name",
 		"data_dir",
+		"plugin_dir",
 		"log_level",
 		"bind_addr",
 		"enable_debug",
@@ -101,6 +103,7 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 		"acl",
 		"sentinel",
 		"autopilot",
+		"plugin",
 	}
 	if err := helper.CheckHCLKeys(list, valid); err != nil {
 		return multierror.Prefix(err, "config:")
@@ -125,6 +128,7 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 	delete(m, "acl")
 	delete(m, "sentinel")
 	delete(m, "autopilot")
+	delete(m, "plugin")
 
 	// Decode the rest
 	if err := mapstructure.WeakDecode(m, result); err != nil {
@@ -215,6 +219,13 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse Plugin configs
+	if o := list.Filter("plugin"); len(o.Items) > 0 {
+		if err := parsePlugins(&result.Plugins, o); err != nil {
+			return multierror.Prefix(err, "plugin->")
+		}
+	}
+
 	// Parse out http_api_response_headers fields. These are in HCL as a list so
 	// we need to iterate over them and merge them.
 	if headersO := list.Filter("http_api_response_headers"); len(headersO.Items) > 0 {
@@ -370,7 +381,9 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		"gc_parallel_destroys",
 		"gc_max_allocs",
 		"no_host_uuid",
+		"recover_state",
 		"server_join",
+		"host_volume",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 		return err
@@ -387,6 +400,7 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 	delete(m, "reserved")
 	delete(m, "stats")
 	delete(m, "server_join")
+	delete(m, "host_volume")
 
 	var config ClientConfig
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -457,10 +471,126 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse host volumes
+	if o := listVal.Filter("host_volume"); len(o.Items) > 0 {
+		if err := parseHostVolumes(&config.HostVolumes, o); err != nil {
+			return multierror.Prefix(err, "host_volume ->")
+		}
+	}
+
 	*result = &config
 	return nil
 }
 
+func parseHostVolumes(result *[]*structs.ClientHostVolumeConfig, list *ast.ObjectList) error {
+	list = list.Children()
+
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("host_volume must have exactly one name")
+		}
+		name := item.Keys[0].Token.Value().(string)
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("host_volume %q defined more than once", name)
+		}
+		seen[name] = struct{}{}
+
+		// Value should be an object
+		var listVal *ast.ObjectList
+		if ot, ok := item.Val.(*ast.ObjectType); ok {
+			listVal = ot.List
+		} else {
+			return fmt.Errorf("host_volume %q: should be an object", name)
+		}
+
+		// Check for invalid keys
+		valid := []string{
+			"path",
+			"read_only",
+		}
+		if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("host_volume %q ->", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, listVal); err != nil {
+			return err
+		}
+
+		volume := &structs.ClientHostVolumeConfig{Name: name}
+		if err := mapstructure.WeakDecode(m, volume); err != nil {
+			return err
+		}
+		if volume.Path == "" {
+			return fmt.Errorf("host_volume %q must have a path", name)
+		}
+
+		*result = append(*result, volume)
+	}
+
+	return nil
+}
+
+func parsePlugins(result *[]*config.PluginConfig, list *ast.ObjectList) error {
+	list = list.Children()
+
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("plugin must have exactly one name")
+		}
+		name := item.Keys[0].Token.Value().(string)
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("plugin %q defined more than once", name)
+		}
+		seen[name] = struct{}{}
+
+		// Value should be an object
+		var listVal *ast.ObjectList
+		if ot, ok := item.Val.(*ast.ObjectType); ok {
+			listVal = ot.List
+		} else {
+			return fmt.Errorf("plugin %q: should be an object", name)
+		}
+
+		// Check for invalid keys
+		valid := []string{
+			"args",
+			"config",
+		}
+		if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("plugin %q ->", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, listVal); err != nil {
+			return err
+		}
+		delete(m, "config")
+
+		plugin := &config.PluginConfig{Name: name}
+		if err := mapstructure.WeakDecode(m, plugin); err != nil {
+			return err
+		}
+
+		// The config is decoded as is and validated against the schema of
+		// the plugin when it is loaded.
+		if o := listVal.Filter("config"); len(o.Items) > 0 {
+			if len(o.Items) > 1 {
+				return fmt.Errorf("plugin %q: only one config block is allowed", name)
+			}
+			if err := hcl.DecodeObject(&plugin.Config, o.Items[0].Val); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("plugin %q config ->", name))
+			}
+		}
+
+		*result = append(*result, plugin)
+	}
+
+	return nil
+}
+
 func parseReserved(result **Resources, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -537,6 +667,9 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 		"eval_gc_threshold",
 		"job_gc_threshold",
 		"deployment_gc_threshold",
+		"acl_token_gc_threshold",
+		"root_key_rotation_threshold",
+		"oidc_issuer",
 		"heartbeat_grace",
 		"min_heartbeat_ttl",
 		"max_heartbeats_per_second",
@@ -546,8 +679,12 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 		"non_voting_server",
 		"redundancy_zone",
 		"upgrade_version",
+		"memory_oversubscription_enabled",
 
 		"server_join",
+		"admission_webhook",
+		"eval_namespace_weights",
+		"rpc_rate_limit",
 
 		// For backwards compatibility
 		"start_join",
@@ -565,6 +702,9 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 	}
 
 	delete(m, "server_join")
+	delete(m, "admission_webhook")
+	delete(m, "eval_namespace_weights")
+	delete(m, "rpc_rate_limit")
 
 	var config ServerConfig
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -592,10 +732,113 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse the admission webhooks
+	if o := listVal.Filter("admission_webhook"); len(o.Items) > 0 {
+		if err := parseAdmissionWebhooks(&config.AdmissionWebhooks, o); err != nil {
+			return multierror.Prefix(err, "admission_webhook->")
+		}
+	}
+
+	// Parse the namespace weights. These are in HCL as a list so we need to
+	// iterate over them and merge them.
+	if o := listVal.Filter("eval_namespace_weights"); len(o.Items) > 0 {
+		for _, o := range o.Elem().Items {
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, o.Val); err != nil {
+				return err
+			}
+			if err := mapstructure.WeakDecode(m, &config.EvalNamespaceWeights); err != nil {
+				return multierror.Prefix(err, "eval_namespace_weights->")
+			}
+		}
+	}
+
+	// Parse the RPC rate limits
+	if o := listVal.Filter("rpc_rate_limit"); len(o.Items) > 0 {
+		if err := parseRPCRateLimit(&config.RPCRateLimit, o); err != nil {
+			return multierror.Prefix(err, "rpc_rate_limit->")
+		}
+	}
+
 	*result = &config
 	return nil
 }
 
+func parseAdmissionWebhooks(result *[]*config.AdmissionWebhookConfig, list *ast.ObjectList) error {
+	list = list.Children()
+	if len(list.Items) == 0 {
+		return nil
+	}
+
+	// Go through each object and turn it into an actual result.
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("admission_webhook '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// Check for invalid keys
+		valid := []string{
+			"type",
+			"url",
+			"timeout",
+			"failure_policy",
+			"headers",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+		delete(m, "headers")
+
+		var hook config.AdmissionWebhookConfig
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &hook,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return err
+		}
+		hook.Name = n
+
+		// Parse out the headers. These are in HCL as a list so we need to
+		// iterate over them and merge them.
+		var listVal *ast.ObjectList
+		if ot, ok := item.Val.(*ast.ObjectType); ok {
+			listVal = ot.List
+		} else {
+			return fmt.Errorf("admission_webhook '%s' value: should be an object", n)
+		}
+		if headersO := listVal.Filter("headers"); len(headersO.Items) > 0 {
+			for _, o := range headersO.Elem().Items {
+				var m map[string]interface{}
+				if err := hcl.DecodeObject(&m, o.Val); err != nil {
+					return err
+				}
+				if err := mapstructure.WeakDecode(m, &hook.Headers); err != nil {
+					return err
+				}
+			}
+		}
+
+		*result = append(*result, &hook)
+	}
+
+	return nil
+}
+
 func parseServerJoin(result **ServerJoin, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -638,6 +881,40 @@ func parseServerJoin(result **ServerJoin, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseRPCRateLimit(result **config.RPCRateLimitConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'rpc_rate_limit' block allowed")
+	}
+
+	// Get our object
+	listVal := list.Items[0].Val
+
+	// Check for invalid keys
+	valid := []string{
+		"read_rate",
+		"read_burst",
+		"write_rate",
+		"write_burst",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	var limit config.RPCRateLimitConfig
+	if err := mapstructure.WeakDecode(m, &limit); err != nil {
+		return err
+	}
+
+	*result = &limit
+	return nil
+}
+
 func parseACL(result **ACLConfig, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/stretchr/testify/require"
 )
@@ -24,6 +25,7 @@ func TestConfig_Parse(t *testing.T) {
 				Datacenter:  "dc2",
 				NodeName:    "my-web",
 				DataDir:     "/tmp/nomad",
+				PluginDir:   "/tmp/nomad-plugins",
 				LogLevel:    "ERR",
 				BindAddr:    "192.168.0.1",
 				EnableDebug: true,
@@ -85,37 +87,67 @@ func TestConfig_Parse(t *testing.T) {
 					GCInodeUsageThreshold: 91,
 					GCMaxAllocs:           50,
 					NoHostUUID:            helper.BoolToPtr(false),
+					RecoverState:          true,
+					HostVolumes: []*structs.ClientHostVolumeConfig{
+						{Name: "tmp", Path: "/tmp"},
+						{Name: "certs", Path: "/etc/ssl/certs", ReadOnly: true},
+					},
 				},
 				Server: &ServerConfig{
-					Enabled:                true,
-					AuthoritativeRegion:    "foobar",
-					BootstrapExpect:        5,
-					DataDir:                "/tmp/data",
-					ProtocolVersion:        3,
-					RaftProtocol:           3,
-					NumSchedulers:          helper.IntToPtr(2),
-					EnabledSchedulers:      []string{"test"},
-					NodeGCThreshold:        "12h",
-					EvalGCThreshold:        "12h",
-					JobGCThreshold:         "12h",
-					DeploymentGCThreshold:  "12h",
-					HeartbeatGrace:         30 * time.Second,
-					MinHeartbeatTTL:        33 * time.Second,
-					MaxHeartbeatsPerSecond: 11.0,
-					RetryJoin:              []string{"1.1.1.1", "2.2.2.2"},
-					StartJoin:              []string{"1.1.1.1", "2.2.2.2"},
-					RetryInterval:          15 * time.Second,
-					RejoinAfterLeave:       true,
-					RetryMaxAttempts:       3,
-					NonVotingServer:        true,
-					RedundancyZone:         "foo",
-					UpgradeVersion:         "0.8.0",
-					EncryptKey:             "abc",
+					Enabled:                  true,
+					AuthoritativeRegion:      "foobar",
+					BootstrapExpect:          5,
+					DataDir:                  "/tmp/data",
+					ProtocolVersion:          3,
+					RaftProtocol:             3,
+					NumSchedulers:            helper.IntToPtr(2),
+					EnabledSchedulers:        []string{"test"},
+					NodeGCThreshold:          "12h",
+					EvalGCThreshold:          "12h",
+					JobGCThreshold:           "12h",
+					DeploymentGCThreshold:    "12h",
+					ACLTokenGCThreshold:      "12h",
+					RootKeyRotationThreshold: "720h",
+					OIDCIssuer:               "https://nomad.example.com",
+					HeartbeatGrace:           30 * time.Second,
+					MinHeartbeatTTL:          33 * time.Second,
+					MaxHeartbeatsPerSecond:   11.0,
+					RetryJoin:                []string{"1.1.1.1", "2.2.2.2"},
+					StartJoin:                []string{"1.1.1.1", "2.2.2.2"},
+					RetryInterval:            15 * time.Second,
+					RejoinAfterLeave:         true,
+					RetryMaxAttempts:         3,
+					NonVotingServer:          true,
+					RedundancyZone:           "foo",
+					UpgradeVersion:           "0.8.0",
+					EncryptKey:               "abc",
 					ServerJoin: &ServerJoin{
 						RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 						RetryInterval:    time.Duration(15) * time.Second,
 						RetryMaxAttempts: 3,
 					},
+					AdmissionWebhooks: []*config.AdmissionWebhookConfig{
+						{
+							Name:          "owner",
+							Type:          "validating",
+							URL:           "https://admission.example.com/owner",
+							Timeout:       5 * time.Second,
+							FailurePolicy: "ignore",
+							Headers: map[string]string{
+								"Authorization": "Bearer foo",
+							},
+						},
+					},
+					EvalNamespaceWeights: map[string]int{
+						"default": 1,
+						"batch":   3,
+					},
+					RPCRateLimit: &config.RPCRateLimitConfig{
+						ReadRate:  100,
+						ReadBurst: 200,
+						WriteRate: 10.5,
+					},
+					MemoryOversubscriptionEnabled: true,
 				},
 				ACL: &ACLConfig{
 					Enabled:          true,
@@ -215,6 +247,26 @@ func TestConfig_Parse(t *testing.T) {
 					DisableUpgradeMigration: &trueValue,
 					EnableCustomUpgrades:    &trueValue,
 				},
+				Plugins: []*config.PluginConfig{
+					{
+						Name: "docker",
+						Args: []string{"foo", "bar"},
+						Config: map[string]interface{}{
+							"foo": "bar",
+							"nested": []map[string]interface{}{
+								{
+									"key": "value",
+								},
+							},
+						},
+					},
+					{
+						Name: "exec",
+						Config: map[string]interface{}{
+							"foo": true,
+						},
+					},
+				},
 			},
 			false,
 		},
@@ -264,11 +316,11 @@ func TestConfig_Parse(t *testing.T) {
 				SyslogFacility:            "",
 				DisableUpdateCheck:        nil,
 				DisableAnonymousSignature: false,
-				Consul:                 nil,
-				Vault:                  nil,
-				TLSConfig:              nil,
-				HTTPAPIResponseHeaders: nil,
-				Sentinel:               nil,
+				Consul:                    nil,
+				Vault:                     nil,
+				TLSConfig:                 nil,
+				HTTPAPIResponseHeaders:    nil,
+				Sentinel:                  nil,
 			},
 			false,
 		},
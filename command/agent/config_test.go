@@ -45,6 +45,7 @@ func TestConfig_Merge(t *testing.T) {
 		Datacenter:                "dc1",
 		NodeName:                  "node1",
 		DataDir:                   "/tmp/dir1",
+		PluginDir:                 "/tmp/pluginDir1",
 		LogLevel:                  "INFO",
 		EnableDebug:               false,
 		LeaveOnInt:                false,
@@ -182,6 +183,7 @@ func TestConfig_Merge(t *testing.T) {
 		Datacenter:                "dc2",
 		NodeName:                  "node2",
 		DataDir:                   "/tmp/dir2",
+		PluginDir:                 "/tmp/pluginDir2",
 		LogLevel:                  "DEBUG",
 		EnableDebug:               true,
 		LeaveOnInt:                true,
@@ -269,6 +271,8 @@ func TestConfig_Merge(t *testing.T) {
 			NonVotingServer:        true,
 			RedundancyZone:         "bar",
 			UpgradeVersion:         "bar",
+
+			MemoryOversubscriptionEnabled: true,
 		},
 		ACL: &ACLConfig{
 			Enabled:          true,
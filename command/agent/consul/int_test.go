@@ -7,12 +7,12 @@ import (
 	"testing"
 	"time"
 
-	"github.com/boltdb/bolt"
 	consulapi "github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/testutil"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner"
 	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/state"
 	"github.com/hashicorp/nomad/client/vaultclient"
 	"github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/helper/testlog"
@@ -61,11 +61,12 @@ func TestConsul_Integration(t *testing.T) {
 	}
 	defer os.RemoveAll(conf.AllocDir)
 
-	tmp, err := ioutil.TempFile("", "state-db")
+	tmp, err := ioutil.TempDir("", "state-db")
 	if err != nil {
-		t.Fatalf("error creating state db file: %v", err)
+		t.Fatalf("error creating state db dir: %v", err)
 	}
-	db, err := bolt.Open(tmp.Name(), 0600, nil)
+	defer os.RemoveAll(tmp)
+	db, err := state.NewBoltStateDB(testlog.Logger(t), tmp)
 	if err != nil {
 		t.Fatalf("error creating state db: %v", err)
 	}
@@ -142,7 +143,7 @@ func TestConsul_Integration(t *testing.T) {
 		serviceClient.Run()
 		close(consulRan)
 	}()
-	tr := taskrunner.NewTaskRunner(logger, conf, db, logUpdate, taskDir, alloc, task, vclient, serviceClient)
+	tr := taskrunner.NewTaskRunner(logger, conf, db, logUpdate, taskDir, alloc, task, vclient, serviceClient, nil, nil)
 	tr.MarkReceived()
 	go tr.Run()
 	defer func() {
@@ -0,0 +1,188 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) CSIVolumesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Type filters volume lists to a specific type. When support for non-CSI
+	// volumes is introduced, we'll need to dispatch here
+	query := req.URL.Query()
+	qtype, ok := query["type"]
+	if !ok {
+		return []*structs.CSIVolListStub{}, nil
+	}
+	if qtype[0] != "csi" {
+		return nil, CodedError(400, "Volume type must be csi")
+	}
+
+	args := structs.CSIVolumeListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+	if plugin, ok := query["plugin_id"]; ok {
+		args.PluginID = plugin[0]
+	}
+
+	var out structs.CSIVolumeListResponse
+	if err := s.agent.RPC("CSIVolume.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Volumes == nil {
+		out.Volumes = make([]*structs.CSIVolListStub, 0)
+	}
+	return out.Volumes, nil
+}
+
+// CSIVolumeSpecificRequest dispatches GET, PUT and DELETE requests on a
+// single volume
+func (s *HTTPServer) CSIVolumeSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Tokenize the suffix of the path to get the volume id
+	reqSuffix := strings.TrimPrefix(req.URL.Path, "/v1/volume/csi/")
+	tokens := strings.Split(reqSuffix, "/")
+	if len(tokens) != 1 || tokens[0] == "" {
+		return nil, CodedError(404, resourceNotFoundErr)
+	}
+	id := tokens[0]
+
+	switch req.Method {
+	case "GET":
+		return s.csiVolumeGet(id, resp, req)
+	case "PUT", "POST":
+		return s.csiVolumePut(id, resp, req)
+	case "DELETE":
+		return s.csiVolumeDelete(id, resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) csiVolumeGet(id string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.CSIVolumeGetRequest{
+		ID: id,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.CSIVolumeGetResponse
+	if err := s.agent.RPC("CSIVolume.Get", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Volume == nil {
+		return nil, CodedError(404, "volume not found")
+	}
+	return out.Volume, nil
+}
+
+func (s *HTTPServer) csiVolumePut(id string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.CSIVolumeRegisterRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if len(args.Volumes) != 1 {
+		return nil, CodedError(400, "Exactly one volume must be specified")
+	}
+	if args.Volumes[0].ID != id {
+		return nil, CodedError(400, "Volume ID does not match request path")
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.CSIVolumeRegisterResponse
+	if err := s.agent.RPC("CSIVolume.Register", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) csiVolumeDelete(id string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.CSIVolumeDeregisterRequest{
+		VolumeIDs: []string{id},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.CSIVolumeDeregisterResponse
+	if err := s.agent.RPC("CSIVolume.Deregister", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+// CSIPluginsRequest lists the CSI plugins
+func (s *HTTPServer) CSIPluginsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Type filters plugin lists to a specific type. When support for non-CSI
+	// plugins is introduced, we'll need to dispatch here
+	query := req.URL.Query()
+	qtype, ok := query["type"]
+	if !ok {
+		return []*structs.CSIPluginListStub{}, nil
+	}
+	if qtype[0] != "csi" {
+		return nil, CodedError(400, "Plugin type must be csi")
+	}
+
+	args := structs.CSIPluginListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.CSIPluginListResponse
+	if err := s.agent.RPC("CSIPlugin.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Plugins == nil {
+		out.Plugins = make([]*structs.CSIPluginListStub, 0)
+	}
+	return out.Plugins, nil
+}
+
+// CSIPluginSpecificRequest returns a single CSI plugin
+func (s *HTTPServer) CSIPluginSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Tokenize the suffix of the path to get the plugin id
+	reqSuffix := strings.TrimPrefix(req.URL.Path, "/v1/plugin/csi/")
+	tokens := strings.Split(reqSuffix, "/")
+	if len(tokens) != 1 || tokens[0] == "" {
+		return nil, CodedError(404, resourceNotFoundErr)
+	}
+
+	args := structs.CSIPluginGetRequest{
+		ID: tokens[0],
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.CSIPluginGetResponse
+	if err := s.agent.RPC("CSIPlugin.Get", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Plugin == nil {
+		return nil, CodedError(404, "plugin not found")
+	}
+	return out.Plugin, nil
+}
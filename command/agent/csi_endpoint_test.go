@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_CSIVolumeLifecycle(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Register the volume
+		vol := mock.CSIVolume()
+		args := structs.CSIVolumeRegisterRequest{
+			Volumes: []*structs.CSIVolume{vol},
+		}
+		req, err := http.NewRequest("PUT", "/v1/volume/csi/"+vol.ID, encodeReq(args))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.CSIVolumeSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// List the volumes of the plugin
+		req, err = http.NewRequest("GET", "/v1/volumes?type=csi&plugin_id=minnie", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err := s.Server.CSIVolumesRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		stubs := obj.([]*structs.CSIVolListStub)
+		require.Len(stubs, 1)
+		require.Equal(vol.ID, stubs[0].ID)
+
+		// Other volume types are rejected
+		req, err = http.NewRequest("GET", "/v1/volumes?type=host", nil)
+		require.NoError(err)
+		_, err = s.Server.CSIVolumesRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+
+		// Query the volume
+		req, err = http.NewRequest("GET", "/v1/volume/csi/"+vol.ID, nil)
+		require.NoError(err)
+		obj, err = s.Server.CSIVolumeSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+		out := obj.(*structs.CSIVolume)
+		require.Equal("vol-01", out.ExternalID)
+		require.False(out.Schedulable)
+
+		// Deregister the volume
+		req, err = http.NewRequest("DELETE", "/v1/volume/csi/"+vol.ID, nil)
+		require.NoError(err)
+		_, err = s.Server.CSIVolumeSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+
+		req, err = http.NewRequest("GET", "/v1/volume/csi/"+vol.ID, nil)
+		require.NoError(err)
+		_, err = s.Server.CSIVolumeSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "not found")
+	})
+}
+
+func TestHTTP_CSIPlugins(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		node := mock.CSINode()
+		require.NoError(state.UpsertNode(1000, node))
+
+		req, err := http.NewRequest("GET", "/v1/plugins?type=csi", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.CSIPluginsRequest(respW, req)
+		require.NoError(err)
+		require.Equal("1000", respW.HeaderMap.Get("X-Nomad-Index"))
+		stubs := obj.([]*structs.CSIPluginListStub)
+		require.Len(stubs, 1)
+		require.Equal("minnie", stubs[0].ID)
+		require.Equal(1, stubs[0].NodesHealthy)
+
+		req, err = http.NewRequest("GET", "/v1/plugin/csi/minnie", nil)
+		require.NoError(err)
+		obj, err = s.Server.CSIPluginSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+		plugin := obj.(*structs.CSIPlugin)
+		require.Contains(plugin.Nodes, node.ID)
+		require.Contains(plugin.Controllers, node.ID)
+	})
+}
@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) DrainPlansRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.drainPlanList(resp, req)
+	case "PUT", "POST":
+		return s.drainPlanCreate(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) drainPlanList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.DrainPlanListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.DrainPlanListResponse
+	if err := s.agent.RPC("DrainPlan.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Plans == nil {
+		out.Plans = make([]*structs.DrainPlanListStub, 0)
+	}
+	return out.Plans, nil
+}
+
+func (s *HTTPServer) drainPlanCreate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.DrainPlanCreateRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if args.Plan == nil {
+		return nil, CodedError(400, "Plan must be specified")
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.DrainPlanUpdateResponse
+	if err := s.agent.RPC("DrainPlan.Create", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+func (s *HTTPServer) DrainPlanSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/drain-plan/")
+	switch {
+	case strings.HasPrefix(path, "pause/"):
+		planID := strings.TrimPrefix(path, "pause/")
+		return s.drainPlanUpdateStatus(resp, req, planID, structs.DrainPlanStatusPaused)
+	case strings.HasPrefix(path, "resume/"):
+		planID := strings.TrimPrefix(path, "resume/")
+		return s.drainPlanUpdateStatus(resp, req, planID, structs.DrainPlanStatusRunning)
+	case strings.HasPrefix(path, "cancel/"):
+		planID := strings.TrimPrefix(path, "cancel/")
+		return s.drainPlanUpdateStatus(resp, req, planID, structs.DrainPlanStatusCancelled)
+	default:
+		return s.drainPlanQuery(resp, req, path)
+	}
+}
+
+func (s *HTTPServer) drainPlanUpdateStatus(resp http.ResponseWriter, req *http.Request, planID, status string) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	if planID == "" {
+		return nil, CodedError(400, "Drain plan ID must be specified")
+	}
+
+	args := structs.DrainPlanStatusUpdateRequest{
+		PlanID: planID,
+		Status: status,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.DrainPlanUpdateResponse
+	if err := s.agent.RPC("DrainPlan.UpdateStatus", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+func (s *HTTPServer) drainPlanQuery(resp http.ResponseWriter, req *http.Request, planID string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.DrainPlanSpecificRequest{
+		PlanID: planID,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleDrainPlanResponse
+	if err := s.agent.RPC("DrainPlan.GetPlan", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Plan == nil {
+		return nil, CodedError(404, "drain plan not found")
+	}
+	return out.Plan, nil
+}
@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_DrainPlanCreate(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		node := mock.Node()
+		node.NodeClass = "batch"
+		require.NoError(state.UpsertNode(1000, node))
+
+		args := structs.DrainPlanCreateRequest{
+			Plan: &structs.DrainPlan{
+				Filter:      &structs.DrainPlanFilter{NodeClass: "batch"},
+				MaxParallel: 1,
+			},
+		}
+		req, err := http.NewRequest("PUT", "/v1/drain-plans", encodeReq(args))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.DrainPlansRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		resp := obj.(structs.DrainPlanUpdateResponse)
+		plan, err := state.DrainPlanByID(nil, resp.PlanID)
+		require.NoError(err)
+		require.Len(plan.Nodes, 1)
+		require.Equal(node.ID, plan.Nodes[0].NodeID)
+
+		// A request without a plan is rejected
+		req, err = http.NewRequest("PUT", "/v1/drain-plans", encodeReq(structs.DrainPlanCreateRequest{}))
+		require.NoError(err)
+		_, err = s.Server.DrainPlansRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+	})
+}
+
+func TestHTTP_DrainPlanListQuery(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		plan := mock.DrainPlan()
+		plan.Status = structs.DrainPlanStatusPaused
+		require.NoError(state.UpsertDrainPlan(1000, &structs.DrainPlanUpsertRequest{Plan: plan}))
+
+		// List the plans
+		req, err := http.NewRequest("GET", "/v1/drain-plans", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.DrainPlansRequest(respW, req)
+		require.NoError(err)
+		require.Equal("1000", respW.HeaderMap.Get("X-Nomad-Index"))
+		stubs := obj.([]*structs.DrainPlanListStub)
+		require.Len(stubs, 1)
+		require.Equal(plan.ID, stubs[0].ID)
+
+		// Query the plan
+		req, err = http.NewRequest("GET", "/v1/drain-plan/"+plan.ID, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.DrainPlanSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Equal(plan.ID, obj.(*structs.DrainPlan).ID)
+
+		// Querying a missing plan returns a 404
+		req, err = http.NewRequest("GET", "/v1/drain-plan/"+mock.DrainPlan().ID, nil)
+		require.NoError(err)
+		_, err = s.Server.DrainPlanSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "not found")
+	})
+}
+
+func TestHTTP_DrainPlanUpdateStatus(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		plan := mock.DrainPlan()
+		plan.Status = structs.DrainPlanStatusPaused
+		require.NoError(state.UpsertDrainPlan(1000, &structs.DrainPlanUpsertRequest{Plan: plan}))
+
+		// Cancel the plan
+		req, err := http.NewRequest("PUT", "/v1/drain-plan/cancel/"+plan.ID, nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.DrainPlanSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Equal(plan.ID, obj.(structs.DrainPlanUpdateResponse).PlanID)
+
+		out, err := state.DrainPlanByID(nil, plan.ID)
+		require.NoError(err)
+		require.Equal(structs.DrainPlanStatusCancelled, out.Status)
+
+		// Status updates must be writes
+		req, err = http.NewRequest("GET", "/v1/drain-plan/pause/"+plan.ID, nil)
+		require.NoError(err)
+		_, err = s.Server.DrainPlanSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+	})
+}
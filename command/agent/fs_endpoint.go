@@ -393,7 +393,7 @@ func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
 		}
 	}()
 
-	handler(handlerPipe)
+	handler(structs.NewStreamingRpcRemoteConn(handlerPipe, req.RemoteAddr))
 	cancel()
 	codedErr := <-errCh
 
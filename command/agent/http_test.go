@@ -266,6 +266,40 @@ func TestTokenNotFound(t *testing.T) {
 	assert.Equal(t, resp.Code, 403)
 }
 
+func TestTokenExpired(t *testing.T) {
+	s := makeHTTPServer(t, func(c *Config) {
+		c.ACL.Enabled = true
+	})
+	defer s.Shutdown()
+
+	resp := httptest.NewRecorder()
+	handler := func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+		return nil, structs.ErrTokenExpired
+	}
+
+	urlStr := "/v1/job/foo"
+	req, _ := http.NewRequest("GET", urlStr, nil)
+	s.Server.wrap(handler)(resp, req)
+	assert.Equal(t, resp.Code, 403)
+	assert.Equal(t, structs.ErrTokenExpired.Error(), resp.Body.String())
+}
+
+func TestRateLimited(t *testing.T) {
+	s := makeHTTPServer(t, nil)
+	defer s.Shutdown()
+
+	resp := httptest.NewRecorder()
+	handler := func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+		return nil, fmt.Errorf("rpc error: %v", structs.ErrRateLimited)
+	}
+
+	urlStr := "/v1/job/foo"
+	req, _ := http.NewRequest("PUT", urlStr, nil)
+	s.Server.wrap(handler)(resp, req)
+	assert.Equal(t, resp.Code, 429)
+	assert.Equal(t, structs.ErrRateLimited.Error(), resp.Body.String())
+}
+
 func TestParseWait(t *testing.T) {
 	t.Parallel()
 	resp := httptest.NewRecorder()
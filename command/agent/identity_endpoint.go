@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// JWKS is the JSON Web Key Set used to verify workload identities
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// JWK is the JSON Web Key representation of a public signing key
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+}
+
+// OIDCDiscovery is the OIDC discovery document of the workload identity
+// issuer. Only the fields required to verify identities are included.
+type OIDCDiscovery struct {
+	Issuer          string   `json:"issuer"`
+	JWKSURI         string   `json:"jwks_uri"`
+	SigningAlgs     []string `json:"id_token_signing_alg_values_supported"`
+	ResponseTypes   []string `json:"response_types_supported"`
+	SubjectTypes    []string `json:"subject_types_supported"`
+	SupportedClaims []string `json:"claims_supported"`
+}
+
+// JWKSRequest returns the public keys used to verify workload identities
+func (s *HTTPServer) JWKSRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.GenericRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.KeyringListPublicResponse
+	if err := s.agent.RPC("Keyring.ListPublic", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	jwks := &JWKS{Keys: make([]*JWK, 0, len(out.PublicKeys))}
+	for _, key := range out.PublicKeys {
+		jwks.Keys = append(jwks.Keys, &JWK{
+			KeyType:   "OKP",
+			Curve:     "Ed25519",
+			X:         base64.RawURLEncoding.EncodeToString(key.PublicKey),
+			KeyID:     key.KeyID,
+			Algorithm: key.Algorithm,
+			Use:       "sig",
+		})
+	}
+	return jwks, nil
+}
+
+// OIDCDiscoveryRequest returns the OIDC discovery document that allows third
+// parties to verify workload identities. It is only served when the issuer is
+// configured.
+func (s *HTTPServer) OIDCDiscoveryRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var issuer string
+	if s.agent.config.Server != nil {
+		issuer = s.agent.config.Server.OIDCIssuer
+	}
+	if issuer == "" {
+		return nil, CodedError(404, "OIDC issuer not configured")
+	}
+
+	return &OIDCDiscovery{
+		Issuer:          issuer,
+		JWKSURI:         strings.TrimSuffix(issuer, "/") + "/.well-known/jwks.json",
+		SigningAlgs:     []string{structs.IdentityAlgorithmEdDSA},
+		ResponseTypes:   []string{"id_token"},
+		SubjectTypes:    []string{"public"},
+		SupportedClaims: []string{"sub", "aud", "nomad_namespace", "nomad_job_id", "nomad_task_group", "nomad_task", "nomad_allocation_id"},
+	}, nil
+}
@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_JWKS(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+		testutil.WaitForLeader(t, s.Agent.RPC)
+
+		req, err := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.JWKSRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		jwks := obj.(*JWKS)
+		require.Len(jwks.Keys, 1)
+		require.Equal("OKP", jwks.Keys[0].KeyType)
+		require.Equal("Ed25519", jwks.Keys[0].Curve)
+		require.NotEmpty(jwks.Keys[0].KeyID)
+		require.NotEmpty(jwks.Keys[0].X)
+	})
+}
+
+func TestHTTP_OIDCDiscovery(t *testing.T) {
+	t.Parallel()
+
+	// The discovery document is only served when the issuer is set
+	httpTest(t, nil, func(s *TestAgent) {
+		req, err := http.NewRequest("GET", "/.well-known/openid-configuration", nil)
+		require.NoError(t, err)
+		_, err = s.Server.OIDCDiscoveryRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Equal(t, 404, err.(HTTPCodedError).Code())
+	})
+
+	httpTest(t, func(c *Config) {
+		c.Server.OIDCIssuer = "https://nomad.example.com/"
+	}, func(s *TestAgent) {
+		require := require.New(t)
+		req, err := http.NewRequest("GET", "/.well-known/openid-configuration", nil)
+		require.NoError(err)
+		obj, err := s.Server.OIDCDiscoveryRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+
+		doc := obj.(*OIDCDiscovery)
+		require.Equal("https://nomad.example.com/", doc.Issuer)
+		require.Equal("https://nomad.example.com/.well-known/jwks.json", doc.JWKSURI)
+	})
+}
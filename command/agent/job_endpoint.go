@@ -68,6 +68,12 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/versions"):
 		jobName := strings.TrimSuffix(path, "/versions")
 		return s.jobVersions(resp, req, jobName)
+	case strings.HasSuffix(path, "/placement-status"):
+		jobName := strings.TrimSuffix(path, "/placement-status")
+		return s.jobPlacementStatus(resp, req, jobName)
+	case strings.HasSuffix(path, "/submission"):
+		jobName := strings.TrimSuffix(path, "/submission")
+		return s.jobSubmission(resp, req, jobName)
 	case strings.HasSuffix(path, "/revert"):
 		jobName := strings.TrimSuffix(path, "/revert")
 		return s.jobRevert(resp, req, jobName)
@@ -265,6 +271,30 @@ func (s *HTTPServer) jobEvaluations(resp http.ResponseWriter, req *http.Request,
 	return out.Evaluations, nil
 }
 
+func (s *HTTPServer) jobPlacementStatus(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	args := structs.JobSpecificRequest{
+		JobID: jobName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobPlacementStatusResponse
+	if err := s.agent.RPC("Job.PlacementStatus", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Status == nil {
+		return nil, CodedError(404, "job not found")
+	}
+	return out.Status, nil
+}
+
 func (s *HTTPServer) jobDeployments(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 	if req.Method != "GET" {
@@ -381,6 +411,7 @@ func (s *HTTPServer) jobUpdate(resp http.ResponseWriter, req *http.Request,
 		EnforceIndex:   args.EnforceIndex,
 		JobModifyIndex: args.JobModifyIndex,
 		PolicyOverride: args.PolicyOverride,
+		Submission:     ApiJobSubmissionToStructs(args.Submission),
 		WriteRequest: structs.WriteRequest{
 			Region:    args.WriteRequest.Region,
 			AuthToken: args.WriteRequest.SecretID,
@@ -458,6 +489,39 @@ func (s *HTTPServer) jobVersions(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobSubmission(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	versionStr := req.URL.Query().Get("version")
+	if versionStr == "" {
+		return nil, CodedError(400, "version must be specified")
+	}
+	version, err := strconv.ParseUint(versionStr, 10, 64)
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("Failed to parse value of %q (%v) as a uint64: %v", "version", versionStr, err))
+	}
+
+	args := structs.JobSubmissionRequest{
+		JobID:   jobName,
+		Version: version,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobSubmissionResponse
+	if err := s.agent.RPC("Job.GetJobSubmission", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Submission == nil {
+		return nil, CodedError(404, "job submission not found")
+	}
+
+	return out.Submission, nil
+}
+
 func (s *HTTPServer) jobRevert(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 
@@ -588,6 +652,17 @@ func (s *HTTPServer) JobsParseRequest(resp http.ResponseWriter, req *http.Reques
 	return jobStruct, nil
 }
 
+// ApiJobSubmissionToStructs converts the submitted source of a job
+func ApiJobSubmissionToStructs(sub *api.JobSubmission) *structs.JobSubmission {
+	if sub == nil {
+		return nil
+	}
+	return &structs.JobSubmission{
+		Source: sub.Source,
+		Format: sub.Format,
+	}
+}
+
 func ApiJobToStructJob(job *api.Job) *structs.Job {
 	job.Canonicalize()
 
@@ -702,6 +777,18 @@ func ApiTgToStructsTG(taskGroup *api.TaskGroup, tg *structs.TaskGroup) {
 		}
 	}
 
+	if l := len(taskGroup.Volumes); l != 0 {
+		tg.Volumes = make(map[string]*structs.VolumeRequest, l)
+		for k, v := range taskGroup.Volumes {
+			tg.Volumes[k] = &structs.VolumeRequest{
+				Name:     v.Name,
+				Type:     v.Type,
+				Source:   v.Source,
+				ReadOnly: v.ReadOnly,
+			}
+		}
+	}
+
 	tg.EphemeralDisk = &structs.EphemeralDisk{
 		Sticky:  *taskGroup.EphemeralDisk.Sticky,
 		SizeMB:  *taskGroup.EphemeralDisk.SizeMB,
@@ -754,6 +841,25 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 		}
 	}
 
+	if l := len(apiTask.VolumeMounts); l != 0 {
+		structsTask.VolumeMounts = make([]*structs.VolumeMount, l)
+		for i, mount := range apiTask.VolumeMounts {
+			structsTask.VolumeMounts[i] = &structs.VolumeMount{
+				Volume:      mount.Volume,
+				Destination: mount.Destination,
+				ReadOnly:    mount.ReadOnly,
+			}
+		}
+	}
+
+	if apiTask.CSIPluginConfig != nil {
+		structsTask.CSIPluginConfig = &structs.TaskCSIPluginConfig{
+			ID:       apiTask.CSIPluginConfig.ID,
+			Type:     structs.CSIPluginType(apiTask.CSIPluginConfig.Type),
+			MountDir: apiTask.CSIPluginConfig.MountDir,
+		}
+	}
+
 	if l := len(apiTask.Services); l != 0 {
 		structsTask.Services = make([]*structs.Service, l)
 		for i, service := range apiTask.Services {
@@ -803,6 +909,9 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 		MemoryMB: *apiTask.Resources.MemoryMB,
 		IOPS:     *apiTask.Resources.IOPS,
 	}
+	if apiTask.Resources.MemoryMaxMB != nil {
+		structsTask.Resources.MemoryMaxMB = *apiTask.Resources.MemoryMaxMB
+	}
 
 	if l := len(apiTask.Resources.Networks); l != 0 {
 		structsTask.Resources.Networks = make([]*structs.NetworkResource, l)
@@ -836,8 +945,24 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 	}
 
 	structsTask.LogConfig = &structs.LogConfig{
-		MaxFiles:      *apiTask.LogConfig.MaxFiles,
-		MaxFileSizeMB: *apiTask.LogConfig.MaxFileSizeMB,
+		MaxFiles:       *apiTask.LogConfig.MaxFiles,
+		MaxFileSizeMB:  *apiTask.LogConfig.MaxFileSizeMB,
+		RotateInterval: *apiTask.LogConfig.RotateInterval,
+		Compress:       *apiTask.LogConfig.Compress,
+	}
+
+	if l := len(apiTask.LogConfig.Sinks); l != 0 {
+		structsTask.LogConfig.Sinks = make([]*structs.LogSink, l)
+		for i, sink := range apiTask.LogConfig.Sinks {
+			structsTask.LogConfig.Sinks[i] = &structs.LogSink{
+				Type:       *sink.Type,
+				Address:    *sink.Address,
+				Tag:        *sink.Tag,
+				BatchSize:  *sink.BatchSize,
+				BatchWait:  *sink.BatchWait,
+				BufferSize: *sink.BufferSize,
+			}
+		}
 	}
 
 	if l := len(apiTask.Artifacts); l != 0 {
@@ -885,6 +1010,13 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 			File: apiTask.DispatchPayload.File,
 		}
 	}
+
+	if apiTask.Identity != nil {
+		structsTask.Identity = &structs.WorkloadIdentity{
+			Env:  apiTask.Identity.Env,
+			File: apiTask.Identity.File,
+		}
+	}
 }
 
 func ApiConstraintToStructs(c1 *api.Constraint, c2 *structs.Constraint) {
@@ -938,6 +938,105 @@ func TestHTTP_JobVersions(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobSubmission(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		assert := assert.New(t)
+
+		// Create the job with its source
+		job := mock.Job()
+		args := structs.JobRegisterRequest{
+			Job: job,
+			Submission: &structs.JobSubmission{
+				Source: "# example\njob \"example\" {}",
+				Format: structs.JobSubmissionFormatHCL,
+			},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		assert.Nil(s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/job/"+job.ID+"/submission?version=0", nil)
+		assert.Nil(err)
+		respW := httptest.NewRecorder()
+
+		// Check the response
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		assert.Nil(err)
+		sub := obj.(*structs.JobSubmission)
+		assert.Equal(args.Submission.Source, sub.Source)
+		assert.Equal(structs.JobSubmissionFormatHCL, sub.Format)
+		assert.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// A missing version is not found
+		req, err = http.NewRequest("GET", "/v1/job/"+job.ID+"/submission?version=3", nil)
+		assert.Nil(err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		assert.NotNil(err)
+		assert.Contains(err.Error(), "not found")
+
+		// The version is required
+		req, err = http.NewRequest("GET", "/v1/job/"+job.ID+"/submission", nil)
+		assert.Nil(err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		assert.NotNil(err)
+		assert.Contains(err.Error(), "version must be specified")
+	})
+}
+
+func TestHTTP_JobPlacementStatus(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		assert := assert.New(t)
+		state := s.Agent.server.State()
+
+		// Create a job with a placement failure
+		job := mock.Job()
+		assert.Nil(state.UpsertJob(1000, job))
+		summary := mock.JobSummary(job.ID)
+		summary.Summary["web"] = structs.TaskGroupSummary{Queued: 1}
+		assert.Nil(state.UpsertJobSummary(1001, summary))
+
+		eval := mock.Eval()
+		eval.JobID = job.ID
+		eval.Status = structs.EvalStatusComplete
+		eval.FailedTGAllocs = map[string]*structs.AllocMetric{
+			"web": {
+				NodesEvaluated:     2,
+				NodesFiltered:      2,
+				ConstraintFiltered: map[string]int{"${attr.kernel.name} = windows": 2},
+			},
+		}
+		assert.Nil(state.UpsertEvals(1002, []*structs.Evaluation{eval}))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/job/"+job.ID+"/placement-status", nil)
+		assert.Nil(err)
+		respW := httptest.NewRecorder()
+
+		// Check the response
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		assert.Nil(err)
+		status := obj.(*structs.JobPlacementStatus)
+		assert.Equal(eval.ID, status.EvalID)
+		assert.Len(status.TaskGroups, 1)
+		assert.Equal([]*structs.PlacementReason{{Reason: "${attr.kernel.name} = windows", Nodes: 2}},
+			status.TaskGroups["web"].FilteringConstraints)
+		assert.Equal("1002", respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// A missing job is not found
+		req, err = http.NewRequest("GET", "/v1/job/missing/placement-status", nil)
+		assert.Nil(err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		assert.NotNil(err)
+		assert.Contains(err.Error(), "job not found")
+	})
+}
+
 func TestHTTP_PeriodicForce(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -1284,6 +1383,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				Meta: map[string]string{
 					"key": "value",
 				},
+				Volumes: map[string]*api.VolumeRequest{
+					"data": {
+						Name:   "data",
+						Type:   "host",
+						Source: "shared_data",
+					},
+				},
 				Tasks: []*api.Task{
 					{
 						Name:   "task1",
@@ -1348,8 +1454,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							},
 						},
 						Resources: &api.Resources{
-							CPU:      helper.IntToPtr(100),
-							MemoryMB: helper.IntToPtr(10),
+							CPU:         helper.IntToPtr(100),
+							MemoryMB:    helper.IntToPtr(10),
+							MemoryMaxMB: helper.IntToPtr(20),
 							Networks: []*api.NetworkResource{
 								{
 									IP:    "10.10.11.1",
@@ -1374,9 +1481,31 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						},
 						KillTimeout: helper.TimeToPtr(10 * time.Second),
 						KillSignal:  "SIGQUIT",
+						VolumeMounts: []*api.VolumeMount{
+							{
+								Volume:      "data",
+								Destination: "/srv/data",
+								ReadOnly:    true,
+							},
+						},
+						CSIPluginConfig: &api.TaskCSIPluginConfig{
+							ID:       "ebs",
+							Type:     api.CSIPluginTypeNode,
+							MountDir: "/csi",
+						},
 						LogConfig: &api.LogConfig{
 							MaxFiles:      helper.IntToPtr(10),
 							MaxFileSizeMB: helper.IntToPtr(100),
+							Sinks: []*api.LogSink{
+								{
+									Type:       helper.StringToPtr("http"),
+									Address:    helper.StringToPtr("https://logs.example.com"),
+									Tag:        helper.StringToPtr("web"),
+									BatchSize:  helper.IntToPtr(50),
+									BatchWait:  helper.TimeToPtr(2 * time.Second),
+									BufferSize: helper.IntToPtr(1000),
+								},
+							},
 						},
 						Artifacts: []*api.TaskArtifact{
 							{
@@ -1412,6 +1541,10 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						DispatchPayload: &api.DispatchPayloadConfig{
 							File: "fileA",
 						},
+						Identity: &api.WorkloadIdentity{
+							Env:  true,
+							File: true,
+						},
 					},
 				},
 			},
@@ -1512,6 +1645,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				Meta: map[string]string{
 					"key": "value",
 				},
+				Volumes: map[string]*structs.VolumeRequest{
+					"data": {
+						Name:   "data",
+						Type:   "host",
+						Source: "shared_data",
+					},
+				},
 				Tasks: []*structs.Task{
 					{
 						Name:   "task1",
@@ -1574,8 +1714,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							},
 						},
 						Resources: &structs.Resources{
-							CPU:      100,
-							MemoryMB: 10,
+							CPU:         100,
+							MemoryMB:    10,
+							MemoryMaxMB: 20,
 							Networks: []*structs.NetworkResource{
 								{
 									IP:    "10.10.11.1",
@@ -1600,9 +1741,31 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						},
 						KillTimeout: 10 * time.Second,
 						KillSignal:  "SIGQUIT",
+						VolumeMounts: []*structs.VolumeMount{
+							{
+								Volume:      "data",
+								Destination: "/srv/data",
+								ReadOnly:    true,
+							},
+						},
+						CSIPluginConfig: &structs.TaskCSIPluginConfig{
+							ID:       "ebs",
+							Type:     structs.CSIPluginTypeNode,
+							MountDir: "/csi",
+						},
 						LogConfig: &structs.LogConfig{
 							MaxFiles:      10,
 							MaxFileSizeMB: 100,
+							Sinks: []*structs.LogSink{
+								{
+									Type:       "http",
+									Address:    "https://logs.example.com",
+									Tag:        "web",
+									BatchSize:  50,
+									BatchWait:  2 * time.Second,
+									BufferSize: 1000,
+								},
+							},
 						},
 						Artifacts: []*structs.TaskArtifact{
 							{
@@ -1638,6 +1801,10 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						DispatchPayload: &structs.DispatchPayloadConfig{
 							File: "fileA",
 						},
+						Identity: &structs.WorkloadIdentity{
+							Env:  true,
+							File: true,
+						},
 					},
 				},
 			},
@@ -1715,8 +1882,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							},
 						},
 						Resources: &api.Resources{
-							CPU:      helper.IntToPtr(100),
-							MemoryMB: helper.IntToPtr(10),
+							CPU:         helper.IntToPtr(100),
+							MemoryMB:    helper.IntToPtr(10),
+							MemoryMaxMB: helper.IntToPtr(20),
 							Networks: []*api.NetworkResource{
 								{
 									IP:    "10.10.11.1",
@@ -1833,8 +2001,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							"hello": "world",
 						},
 						Resources: &structs.Resources{
-							CPU:      100,
-							MemoryMB: 10,
+							CPU:         100,
+							MemoryMB:    10,
+							MemoryMaxMB: 20,
 							Networks: []*structs.NetworkResource{
 								{
 									IP:    "10.10.11.1",
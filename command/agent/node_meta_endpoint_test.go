@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_ClientMetadataRequest(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Apply meta to the local node
+		args := cstructs.NodeMetaApplyRequest{
+			Meta: map[string]*string{"rack": helper.StringToPtr("r1")},
+		}
+		req, err := http.NewRequest("PUT", "/v1/client/metadata", encodeReq(args))
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.ClientMetadataRequest(respW, req)
+		require.Nil(err)
+		require.Equal("r1", obj.(cstructs.NodeMetaResponse).Meta["rack"])
+
+		// Read the meta of the local node
+		req, err = http.NewRequest("GET", "/v1/client/metadata", nil)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.ClientMetadataRequest(respW, req)
+		require.Nil(err)
+		meta := obj.(cstructs.NodeMetaResponse)
+		require.Equal("r1", meta.Meta["rack"])
+		require.Equal("r1", *meta.Dynamic["rack"])
+
+		// Requests without meta are rejected
+		req, err = http.NewRequest("PUT", "/v1/client/metadata", encodeReq(cstructs.NodeMetaApplyRequest{}))
+		require.Nil(err)
+		_, err = s.Server.ClientMetadataRequest(httptest.NewRecorder(), req)
+		require.NotNil(err)
+		require.Contains(err.Error(), "Meta must be set")
+
+		// Reading the meta of an unknown node through the server fails
+		c := s.client
+		s.client = nil
+		req, err = http.NewRequest("GET", fmt.Sprintf("/v1/client/metadata?node_id=%s", uuid.Generate()), nil)
+		require.Nil(err)
+		_, err = s.Server.ClientMetadataRequest(httptest.NewRecorder(), req)
+		require.NotNil(err)
+		require.Contains(err.Error(), "Unknown node")
+		s.client = c
+	})
+}
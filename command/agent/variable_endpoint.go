@@ -0,0 +1,164 @@
+package agent
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) VariablesListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.VariablesListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.VariablesListResponse
+	if err := s.agent.RPC("Variables.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Data == nil {
+		out.Data = make([]*structs.VariableMetadata, 0)
+	}
+	return out.Data, nil
+}
+
+func (s *HTTPServer) VariableSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/var/")
+	if len(path) == 0 {
+		return nil, CodedError(400, "Missing variable path")
+	}
+	switch req.Method {
+	case "GET":
+		return s.variableQuery(resp, req, path)
+	case "PUT", "POST":
+		return s.variableUpsert(resp, req, path)
+	case "DELETE":
+		return s.variableDelete(resp, req, path)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	args := structs.VariablesReadRequest{
+		Path: path,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.VariablesReadResponse
+	if err := s.agent.RPC("Variables.Read", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Data == nil {
+		return nil, CodedError(404, "variable not found")
+	}
+	return out.Data, nil
+}
+
+func (s *HTTPServer) variableUpsert(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	// Parse the variable
+	var sv structs.VariableDecrypted
+	if err := decodeBody(req, &sv); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if len(sv.Items) == 0 {
+		return nil, CodedError(400, "variable missing required Items object")
+	}
+	sv.Path = path
+
+	args := structs.VariablesApplyRequest{
+		Op:  structs.VarOpSet,
+		Var: &sv,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+	if err := parseCAS(req, &args); err != nil {
+		return nil, err
+	}
+
+	var out structs.VariablesApplyResponse
+	if err := s.agent.RPC("Variables.Apply", &args, &out); err != nil {
+		return nil, err
+	}
+	if out.IsConflict() {
+		return conflictResponse(resp, out.Conflict), nil
+	}
+
+	setIndex(resp, out.Index)
+	return out.Output, nil
+}
+
+func (s *HTTPServer) variableDelete(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+
+	args := structs.VariablesApplyRequest{
+		Op: structs.VarOpDelete,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Path: path,
+			},
+		},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+	if err := parseCAS(req, &args); err != nil {
+		return nil, err
+	}
+
+	var out structs.VariablesApplyResponse
+	if err := s.agent.RPC("Variables.Apply", &args, &out); err != nil {
+		return nil, err
+	}
+	if out.IsConflict() {
+		return conflictResponse(resp, out.Conflict), nil
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+// parseCAS turns the operation into a check-and-set operation if the cas query
+// parameter is given
+func parseCAS(req *http.Request, args *structs.VariablesApplyRequest) error {
+	raw := req.URL.Query().Get("cas")
+	if raw == "" {
+		return nil
+	}
+
+	index, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return CodedError(400, "Invalid cas index: "+err.Error())
+	}
+
+	args.Var.ModifyIndex = index
+	switch args.Op {
+	case structs.VarOpSet:
+		args.Op = structs.VarOpCAS
+	case structs.VarOpDelete:
+		args.Op = structs.VarOpDeleteCAS
+	}
+	return nil
+}
+
+// conflictResponse sets the status code for a failed check-and-set and returns
+// the conflicting variable to be written as the body
+func conflictResponse(resp http.ResponseWriter, conflict *structs.VariableDecrypted) interface{} {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusConflict)
+	if conflict == nil {
+		conflict = &structs.VariableDecrypted{}
+	}
+	return conflict
+}
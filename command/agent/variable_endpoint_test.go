@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeVariableReq(t *testing.T, sv *structs.VariableDecrypted) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	require.NoError(t, json.NewEncoder(buf).Encode(sv))
+	return buf
+}
+
+func TestHTTP_Variables_CRUD(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		sv := &structs.VariableDecrypted{
+			Items: structs.VariableItems{"user": "admin", "password": "hunter2"},
+		}
+
+		// Create the variable
+		req, err := http.NewRequest("PUT", "/v1/var/app/db", encodeVariableReq(t, sv))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		out := obj.(*structs.VariableDecrypted)
+		require.Equal("app/db", out.Path)
+		require.Equal(structs.DefaultNamespace, out.Namespace)
+		require.NotZero(out.ModifyIndex)
+
+		// Read it back
+		req, err = http.NewRequest("GET", "/v1/var/app/db", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+		read := obj.(*structs.VariableDecrypted)
+		require.Equal(sv.Items, read.Items)
+
+		// List it
+		req, err = http.NewRequest("GET", "/v1/vars?prefix=app", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariablesListRequest(respW, req)
+		require.NoError(err)
+		list := obj.([]*structs.VariableMetadata)
+		require.Len(list, 1)
+		require.Equal("app/db", list[0].Path)
+
+		// A check-and-set with a stale index conflicts
+		req, err = http.NewRequest("PUT", "/v1/var/app/db?cas=1", encodeVariableReq(t, sv))
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Equal(http.StatusConflict, respW.Code)
+		require.Equal(read.ModifyIndex, obj.(*structs.VariableDecrypted).ModifyIndex)
+
+		// Delete it
+		req, err = http.NewRequest("DELETE", "/v1/var/app/db", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+
+		req, err = http.NewRequest("GET", "/v1/var/app/db", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.Error(err)
+		require.Contains(err.Error(), "not found")
+	})
+}
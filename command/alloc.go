@@ -15,7 +15,8 @@ func (f *AllocCommand) Help() string {
 Usage: nomad alloc <subcommand> [options] [args]
 
   This command groups subcommands for interacting with allocations. Users can
-  inspect the status, examine the filesystem or logs of an allocation.
+  inspect the status, examine the filesystem or logs of an allocation, run
+  commands inside of it, or restart, signal and stop it.
 
   Examine an allocations status:
 
@@ -25,6 +26,18 @@ Usage: nomad alloc <subcommand> [options] [args]
 
       $ nomad alloc logs -f <alloc-id> <task>
 
+  Run an interactive shell in a task:
+
+      $ nomad alloc exec -i -t <alloc-id> /bin/sh
+
+  Restart a running allocation:
+
+      $ nomad alloc restart <alloc-id>
+
+  Stop an allocation and reschedule it:
+
+      $ nomad alloc stop <alloc-id>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -0,0 +1,75 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocExecCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &AllocExecCommand{}
+}
+
+func TestAllocExecCommand_Fails(t *testing.T) {
+	t.Parallel()
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	cases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			"misuse",
+			[]string{"bad"},
+			commandErrorText(&AllocExecCommand{}),
+		},
+		{
+			"connection failure",
+			[]string{"-address=nope", "26470238-5CF2-438F-8772-DC67CFB0705C", "/bin/bash"},
+			"Error querying allocation",
+		},
+		{
+			"not found alloc",
+			[]string{"-address=" + url, "26470238-5CF2-438F-8772-DC67CFB0705C", "/bin/bash"},
+			"No allocation(s) with prefix or id",
+		},
+		{
+			"too short alloc id",
+			[]string{"-address=" + url, "2", "/bin/bash"},
+			"must contain at least two characters",
+		},
+		{
+			"missing command",
+			[]string{"-address=" + url, "26470238-5CF2-438F-8772-DC67CFB0705C"},
+			"A command is required",
+		},
+		{
+			"tty without stdin",
+			[]string{"-address=" + url, "-t", "-i=false", "26470238-5CF2-438F-8772-DC67CFB0705C", "/bin/bash"},
+			"-i must be enabled if running with tty",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ui := new(cli.MockUi)
+			cmd := &AllocExecCommand{Meta: Meta{Ui: ui}}
+
+			code := cmd.Run(c.args)
+			require.Equal(t, 1, code)
+
+			require.Contains(t, ui.ErrorWriter.String(), c.expectedError)
+		})
+	}
+}
+
+func TestAllocExecCommand_Help(t *testing.T) {
+	t.Parallel()
+	cmd := &AllocExecCommand{}
+	require.True(t, strings.Contains(cmd.Help(), "nomad alloc exec"))
+}
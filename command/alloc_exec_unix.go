@@ -0,0 +1,14 @@
+// +build !windows
+
+package command
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// setupWindowNotification sets up a signal notification for terminal resizes
+func setupWindowNotification(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGWINCH)
+}
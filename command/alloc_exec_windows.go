@@ -0,0 +1,12 @@
+// +build windows
+
+package command
+
+import (
+	"os"
+)
+
+// setupWindowNotification is a no-op on Windows as terminal resizes are not
+// signaled
+func setupWindowNotification(ch chan<- os.Signal) {
+}
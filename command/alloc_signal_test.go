@@ -0,0 +1,70 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocSignalCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &AllocSignalCommand{}
+}
+
+func TestAllocSignalCommand_Fails(t *testing.T) {
+	t.Parallel()
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	cases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			"misuse",
+			[]string{"a", "b", "c"},
+			commandErrorText(&AllocSignalCommand{}),
+		},
+		{
+			"missing alloc",
+			[]string{"-address=" + url},
+			"This command takes",
+		},
+		{
+			"connection failure",
+			[]string{"-address=nope", "26470238-5CF2-438F-8772-DC67CFB0705C"},
+			"Error querying allocation",
+		},
+		{
+			"not found alloc",
+			[]string{"-address=" + url, "26470238-5CF2-438F-8772-DC67CFB0705C"},
+			"No allocation(s) with prefix or id",
+		},
+		{
+			"too short alloc id",
+			[]string{"-address=" + url, "2"},
+			"must contain at least two characters",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ui := new(cli.MockUi)
+			cmd := &AllocSignalCommand{Meta: Meta{Ui: ui}}
+
+			code := cmd.Run(c.args)
+			require.Equal(t, 1, code)
+
+			require.Contains(t, ui.ErrorWriter.String(), c.expectedError)
+		})
+	}
+}
+
+func TestAllocSignalCommand_Help(t *testing.T) {
+	t.Parallel()
+	cmd := &AllocSignalCommand{}
+	require.True(t, strings.Contains(cmd.Help(), "nomad alloc signal"))
+}
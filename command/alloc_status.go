@@ -508,6 +508,11 @@ func (c *AllocStatusCommand) outputTaskResources(alloc *api.Allocation, task str
 			}
 		}
 	}
+
+	// Show the memory limit when the task may exceed its reservation
+	if max := resource.MemoryMaxMB; max != nil && *max > *resource.MemoryMB {
+		memUsage = fmt.Sprintf("%v (max %v)", memUsage, humanize.IBytes(uint64(*max*bytesPerMegabyte)))
+	}
 	resourcesOutput = append(resourcesOutput, fmt.Sprintf("%v MHz|%v|%v|%v|%v",
 		cpuUsage,
 		memUsage,
@@ -547,6 +552,8 @@ func (c *AllocStatusCommand) outputVerboseResourceUsage(task string, resourceUsa
 				measuredStats = append(measuredStats, humanize.IBytes(memoryStats.Cache))
 			case "Swap":
 				measuredStats = append(measuredStats, humanize.IBytes(memoryStats.Swap))
+			case "Usage":
+				measuredStats = append(measuredStats, humanize.IBytes(memoryStats.Usage))
 			case "Max Usage":
 				measuredStats = append(measuredStats, humanize.IBytes(memoryStats.MaxUsage))
 			case "Kernel Usage":
@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -224,6 +226,26 @@ func TestAllocStatusCommand_RescheduleInfo(t *testing.T) {
 	require.Regexp(regexp.MustCompile(".*Reschedule Attempts\\s*=\\s*1/2"), out)
 }
 
+func TestAllocStatusCommand_TaskResources_MemoryMax(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &AllocStatusCommand{Meta: Meta{Ui: ui}}
+
+	alloc := &api.Allocation{
+		Resources: &api.Resources{DiskMB: helper.IntToPtr(150)},
+		TaskResources: map[string]*api.Resources{
+			"web": {
+				CPU:         helper.IntToPtr(500),
+				MemoryMB:    helper.IntToPtr(256),
+				MemoryMaxMB: helper.IntToPtr(512),
+				IOPS:        helper.IntToPtr(0),
+			},
+		},
+	}
+	cmd.outputTaskResources(alloc, "web", nil, false)
+	require.Contains(t, ui.OutputWriter.String(), "256 MiB (max 512 MiB)")
+}
+
 func TestAllocStatusCommand_AutocompleteArgs(t *testing.T) {
 	assert := assert.New(t)
 	t.Parallel()
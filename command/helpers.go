@@ -382,6 +382,13 @@ type JobGetter struct {
 
 // StructJob returns the Job struct from jobfile.
 func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
+	_, job, err := j.ApiJobWithSubmission(jpath)
+	return job, err
+}
+
+// ApiJobWithSubmission returns the Job struct from jobfile along with the
+// original source of the jobfile.
+func (j *JobGetter) ApiJobWithSubmission(jpath string) (*api.JobSubmission, *api.Job, error) {
 	var jobfile io.Reader
 	switch jpath {
 	case "-":
@@ -392,23 +399,23 @@ func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
 		}
 	default:
 		if len(jpath) == 0 {
-			return nil, fmt.Errorf("Error jobfile path has to be specified.")
+			return nil, nil, fmt.Errorf("Error jobfile path has to be specified.")
 		}
 
 		job, err := ioutil.TempFile("", "jobfile")
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		defer os.Remove(job.Name())
 
 		if err := job.Close(); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// Get the pwd
 		pwd, err := os.Getwd()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		client := &gg.Client{
@@ -418,24 +425,40 @@ func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
 		}
 
 		if err := client.Get(); err != nil {
-			return nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
+			return nil, nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
 		} else {
 			file, err := os.Open(job.Name())
 			defer file.Close()
 			if err != nil {
-				return nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
+				return nil, nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
 			}
 			jobfile = file
 		}
 	}
 
+	// Read the source so it can be submitted along with the job
+	src, err := ioutil.ReadAll(jobfile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
+	}
+
 	// Parse the JobFile
-	jobStruct, err := jobspec.Parse(jobfile)
+	jobStruct, err := jobspec.Parse(bytes.NewReader(src))
 	if err != nil {
-		return nil, fmt.Errorf("Error parsing job file from %s: %v", jpath, err)
+		return nil, nil, fmt.Errorf("Error parsing job file from %s: %v", jpath, err)
+	}
+
+	// HCL files may also be written in JSON
+	format := api.JobSubmissionFormatHCL
+	if bytes.HasPrefix(bytes.TrimSpace(src), []byte("{")) {
+		format = api.JobSubmissionFormatJSON
 	}
 
-	return jobStruct, nil
+	submission := &api.JobSubmission{
+		Source: string(src),
+		Format: format,
+	}
+	return submission, jobStruct, nil
 }
 
 // COMPAT: Remove in 0.7.0
@@ -271,6 +271,35 @@ func TestJobGetter_LocalFile(t *testing.T) {
 	}
 }
 
+// Test ApiJobWithSubmission returns the source of the jobfile
+func TestJobGetter_Submission(t *testing.T) {
+	t.Parallel()
+	fh, err := ioutil.TempFile("", "nomad")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(fh.Name())
+	_, err = fh.WriteString(job)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	j := &JobGetter{}
+	sub, aj, err := j.ApiJobWithSubmission(fh.Name())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(expectedApiJob, aj) {
+		t.Fatalf("got:\n%#v\nwant:\n%#v", aj, expectedApiJob)
+	}
+	if sub.Source != job {
+		t.Fatalf("got source:\n%s\nwant:\n%s", sub.Source, job)
+	}
+	if sub.Format != api.JobSubmissionFormatHCL {
+		t.Fatalf("got format %q; want %q", sub.Format, api.JobSubmissionFormatHCL)
+	}
+}
+
 // Test StructJob with jobfile from HTTP Server
 func TestJobGetter_HTTPServer(t *testing.T) {
 	t.Parallel()
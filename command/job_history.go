@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/posener/complete"
 	"github.com/ryanuber/columnize"
 )
@@ -15,6 +16,10 @@ import (
 type JobHistoryCommand struct {
 	Meta
 	formatter DataFormatter
+
+	// sources are the submitted sources of the job versions by version. It
+	// is only set when the source diffs are displayed.
+	sources map[uint64]*api.JobSubmission
 }
 
 func (c *JobHistoryCommand) Help() string {
@@ -38,6 +43,10 @@ History Options:
   -full
     Display the full job definition for each version.
 
+  -source
+    Display the difference between the submitted source of each job and its
+    predecessor, including comments.
+
   -version <job version>
     Display only the history for the given job version.
 
@@ -58,6 +67,7 @@ func (c *JobHistoryCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-p":       complete.PredictNothing,
+			"-source":  complete.PredictNothing,
 			"-full":    complete.PredictNothing,
 			"-version": complete.PredictAnything,
 			"-json":    complete.PredictNothing,
@@ -83,13 +93,14 @@ func (c *JobHistoryCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobHistoryCommand) Name() string { return "job history" }
 
 func (c *JobHistoryCommand) Run(args []string) int {
-	var json, diff, full bool
+	var json, diff, full, source bool
 	var tmpl, versionStr string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&diff, "p", false, "")
 	flags.BoolVar(&full, "full", false, "")
+	flags.BoolVar(&source, "source", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&versionStr, "version", "", "")
 	flags.StringVar(&tmpl, "t", "", "")
@@ -106,8 +117,8 @@ func (c *JobHistoryCommand) Run(args []string) int {
 		return 1
 	}
 
-	if (json || len(tmpl) != 0) && (diff || full) {
-		c.Ui.Error("-json and -t are exclusive with -p and -full")
+	if (json || len(tmpl) != 0) && (diff || full || source) {
+		c.Ui.Error("-json and -t are exclusive with -p, -full and -source")
 		return 1
 	}
 
@@ -149,6 +160,23 @@ func (c *JobHistoryCommand) Run(args []string) int {
 	}
 	c.formatter = f
 
+	// Retrieve the submitted sources. Versions submitted without their source
+	// are skipped.
+	if source {
+		c.sources = make(map[uint64]*api.JobSubmission, len(versions))
+		for _, v := range versions {
+			sub, _, err := client.Jobs().Submission(*v.ID, *v.Version, nil)
+			if err != nil {
+				if strings.Contains(err.Error(), "404") {
+					continue
+				}
+				c.Ui.Error(fmt.Sprintf("Error retrieving job source: %s", err))
+				return 1
+			}
+			c.sources[*v.Version] = sub
+		}
+	}
+
 	if versionStr != "" {
 		version, _, err := parseVersion(versionStr)
 		if err != nil {
@@ -156,9 +184,8 @@ func (c *JobHistoryCommand) Run(args []string) int {
 			return 1
 		}
 
-		var job *api.Job
+		var job, prev *api.Job
 		var diff *api.JobDiff
-		var nextVersion uint64
 		for i, v := range versions {
 			if *v.Version != version {
 				continue
@@ -167,7 +194,9 @@ func (c *JobHistoryCommand) Run(args []string) int {
 			job = v
 			if i+1 <= len(diffs) {
 				diff = diffs[i]
-				nextVersion = *versions[i+1].Version
+			}
+			if i+1 < len(versions) {
+				prev = versions[i+1]
 			}
 		}
 
@@ -182,7 +211,7 @@ func (c *JobHistoryCommand) Run(args []string) int {
 			return 0
 		}
 
-		if err := c.formatJobVersion(job, diff, nextVersion, full); err != nil {
+		if err := c.formatJobVersion(job, diff, prev, full); err != nil {
 			c.Ui.Error(err.Error())
 			return 1
 		}
@@ -228,13 +257,15 @@ func (c *JobHistoryCommand) formatJobVersions(versions []*api.Job, diffs []*api.
 
 	for i, version := range versions {
 		var diff *api.JobDiff
-		var nextVersion uint64
+		var prev *api.Job
 		if i+1 <= dLen {
 			diff = diffs[i]
-			nextVersion = *versions[i+1].Version
+		}
+		if i+1 < vLen {
+			prev = versions[i+1]
 		}
 
-		if err := c.formatJobVersion(version, diff, nextVersion, full); err != nil {
+		if err := c.formatJobVersion(version, diff, prev, full); err != nil {
 			return err
 		}
 
@@ -247,7 +278,9 @@ func (c *JobHistoryCommand) formatJobVersions(versions []*api.Job, diffs []*api.
 	return nil
 }
 
-func (c *JobHistoryCommand) formatJobVersion(job *api.Job, diff *api.JobDiff, nextVersion uint64, full bool) error {
+// formatJobVersion displays a job version. The diff and the source diff are
+// displayed relative to the previous version, prev, if one exists.
+func (c *JobHistoryCommand) formatJobVersion(job *api.Job, diff *api.JobDiff, prev *api.Job, full bool) error {
 	if job == nil {
 		return fmt.Errorf("Error printing job history for non-existing job or job version")
 	}
@@ -259,10 +292,13 @@ func (c *JobHistoryCommand) formatJobVersion(job *api.Job, diff *api.JobDiff, ne
 	}
 
 	if diff != nil {
-		//diffStr := fmt.Sprintf("Difference between version %d and %d:", *job.Version, nextVersion)
 		basic = append(basic, fmt.Sprintf("Diff|\n%s", strings.TrimSpace(formatJobDiff(diff, false))))
 	}
 
+	if c.sources != nil && prev != nil {
+		basic = append(basic, fmt.Sprintf("Source Diff|\n%s", c.formatSourceDiff(prev, job)))
+	}
+
 	if full {
 		out, err := c.formatter.TransformData(job)
 		if err != nil {
@@ -280,3 +316,39 @@ func (c *JobHistoryCommand) formatJobVersion(job *api.Job, diff *api.JobDiff, ne
 	c.Ui.Output(c.Colorize().Color(output))
 	return nil
 }
+
+// formatSourceDiff returns a unified diff of the submitted sources of two job
+// versions.
+func (c *JobHistoryCommand) formatSourceDiff(prev, job *api.Job) string {
+	old, new := c.sources[*prev.Version], c.sources[*job.Version]
+	if old == nil || new == nil {
+		return "<source not available>"
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(old.Source),
+		B:        difflib.SplitLines(new.Source),
+		FromFile: fmt.Sprintf("version %d", *prev.Version),
+		ToFile:   fmt.Sprintf("version %d", *job.Version),
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Sprintf("<failed to diff source: %v>", err)
+	}
+	if diff == "" {
+		return "<no changes>"
+	}
+
+	// Color the added and removed lines
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			lines[i] = fmt.Sprintf("[green]%s[reset]", line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = fmt.Sprintf("[red]%s[reset]", line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
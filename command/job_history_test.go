@@ -1,13 +1,17 @@
 package command
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestJobHistoryCommand_Implements(t *testing.T) {
@@ -61,3 +65,41 @@ func TestJobHistoryCommand_AutocompleteArgs(t *testing.T) {
 	assert.Equal(1, len(res))
 	assert.Equal(j.ID, res[0])
 }
+
+func TestJobHistoryCommand_Source(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Register two versions of the job along with their sources
+	for i, count := range []int{1, 2} {
+		job := testJob("job1")
+		job.TaskGroups[0].Count = helper.IntToPtr(count)
+		opts := &api.RegisterOptions{
+			Submission: &api.JobSubmission{
+				Source: fmt.Sprintf("job \"job1\" {\n  version = %d\n  count = %d\n}\n", i, count),
+				Format: api.JobSubmissionFormatHCL,
+			},
+		}
+		_, _, err := client.Jobs().RegisterOpts(job, opts, nil)
+		require.NoError(err)
+	}
+
+	ui := new(cli.MockUi)
+	cmd := &JobHistoryCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-source", "job1"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Source Diff")
+	require.Contains(out, "-  count = 1")
+	require.Contains(out, "+  count = 2")
+
+	// -source may not be combined with -json
+	ui.ErrorWriter.Reset()
+	code = cmd.Run([]string{"-address=" + url, "-source", "-json", "job1"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "exclusive")
+}
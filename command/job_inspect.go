@@ -32,6 +32,10 @@ Inspect Options:
   -json
     Output the job in its JSON format.
 
+  -source
+    Display the original source the job was submitted with, including
+    comments. Only available for jobs submitted with the source.
+
   -t
     Format and display job using a Go template.
 `
@@ -47,6 +51,7 @@ func (c *JobInspectCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-version": complete.PredictAnything,
 			"-json":    complete.PredictNothing,
+			"-source":  complete.PredictNothing,
 			"-t":       complete.PredictAnything,
 		})
 }
@@ -69,12 +74,13 @@ func (c *JobInspectCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobInspectCommand) Name() string { return "job inspect" }
 
 func (c *JobInspectCommand) Run(args []string) int {
-	var json bool
+	var json, source bool
 	var tmpl, versionStr string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&source, "source", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
 	flags.StringVar(&versionStr, "version", "", "")
 
@@ -149,6 +155,18 @@ func (c *JobInspectCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Print the original source of the job
+	if source {
+		sub, _, err := client.Jobs().Submission(*job.ID, *job.Version, nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error retrieving job source: %s", err))
+			return 1
+		}
+
+		c.Ui.Output(strings.TrimSuffix(sub.Source, "\n"))
+		return 0
+	}
+
 	// If output format is specified, format and output the data
 	if json || len(tmpl) > 0 {
 		out, err := Format(json, tmpl, job)
@@ -4,10 +4,12 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInspectCommand_Implements(t *testing.T) {
@@ -81,3 +83,28 @@ func TestInspectCommand_AutocompleteArgs(t *testing.T) {
 	assert.Equal(1, len(res))
 	assert.Equal(j.ID, res[0])
 }
+
+func TestInspectCommand_Source(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Register a job along with its source
+	source := "# The job1 job\njob \"job1\" {}\n"
+	opts := &api.RegisterOptions{
+		Submission: &api.JobSubmission{
+			Source: source,
+			Format: api.JobSubmissionFormatHCL,
+		},
+	}
+	_, _, err := client.Jobs().RegisterOpts(testJob("job1"), opts, nil)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &JobInspectCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-source", "job1"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Equal(source, ui.OutputWriter.String())
+}
@@ -24,6 +24,7 @@ type JobStatusCommand struct {
 	evals     bool
 	allAllocs bool
 	verbose   bool
+	explain   bool
 }
 
 func (c *JobStatusCommand) Help() string {
@@ -50,6 +51,11 @@ Status Options:
     Display all allocations matching the job ID, including those from an older
     instance of the job.
 
+  -explain
+    Explain why task groups of the job could not be placed, including the
+    resources that were exhausted, the constraints that filtered the most
+    nodes and the node classes the job is waiting on.
+
   -verbose
     Display full information.
 `
@@ -65,6 +71,7 @@ func (c *JobStatusCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-all-allocs": complete.PredictNothing,
 			"-evals":      complete.PredictNothing,
+			"-explain":    complete.PredictNothing,
 			"-short":      complete.PredictNothing,
 			"-verbose":    complete.PredictNothing,
 		})
@@ -96,6 +103,7 @@ func (c *JobStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.evals, "evals", false, "")
 	flags.BoolVar(&c.allAllocs, "all-allocs", false, "")
 	flags.BoolVar(&c.verbose, "verbose", false, "")
+	flags.BoolVar(&c.explain, "explain", false, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -372,7 +380,11 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 		c.Ui.Output(formatList(evals))
 	}
 
-	if blockedEval && latestFailedPlacement != nil {
+	if c.explain {
+		if err := c.outputPlacementStatus(client, job); err != nil {
+			return err
+		}
+	} else if blockedEval && latestFailedPlacement != nil {
 		c.outputFailedPlacements(latestFailedPlacement)
 	}
 
@@ -630,6 +642,99 @@ func (c *JobStatusCommand) outputFailedPlacements(failedEval *api.Evaluation) {
 	}
 }
 
+// outputPlacementStatus prints why the task groups of the job could not be
+// placed. If the request fails, an error is returned.
+func (c *JobStatusCommand) outputPlacementStatus(client *api.Client, job *api.Job) error {
+	status, _, err := client.Jobs().PlacementStatus(*job.ID, nil)
+	if err != nil {
+		return fmt.Errorf("Error querying job placement status: %s", err)
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Placement Status[reset]"))
+	if len(status.TaskGroups) == 0 {
+		c.Ui.Output("All task groups are placed")
+		return nil
+	}
+
+	waiting := "<none>"
+	switch {
+	case status.EscapedComputedClass:
+		waiting = "<any>"
+	case len(status.WaitingClasses) != 0:
+		classes := make([]string, len(status.WaitingClasses))
+		for i, class := range status.WaitingClasses {
+			if class == "" {
+				class = "<none>"
+			}
+			classes[i] = class
+		}
+		waiting = strings.Join(classes, ", ")
+	}
+
+	quota := "<none>"
+	if status.QuotaLimitReached != "" {
+		quota = status.QuotaLimitReached
+	}
+
+	blocked := "<none>"
+	if status.BlockedEvalID != "" {
+		blocked = limit(status.BlockedEvalID, c.length)
+	}
+
+	basic := []string{
+		fmt.Sprintf("Evaluation ID|%s", limit(status.EvalID, c.length)),
+		fmt.Sprintf("Blocked Evaluation ID|%s", blocked),
+		fmt.Sprintf("Waiting On Node Classes|%s", waiting),
+		fmt.Sprintf("Quota Limit Reached|%s", quota),
+	}
+	c.Ui.Output(formatKV(basic))
+
+	tgs := make([]string, 0, len(status.TaskGroups))
+	for tg := range status.TaskGroups {
+		tgs = append(tgs, tg)
+	}
+	sort.Strings(tgs)
+
+	for _, tg := range tgs {
+		tgStatus := status.TaskGroups[tg]
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("\n[bold]Task Group %q[reset]", tg)))
+
+		metrics := tgStatus.Metrics
+		c.Ui.Output(formatKV([]string{
+			fmt.Sprintf("Queued|%d", tgStatus.Queued),
+			fmt.Sprintf("Nodes Evaluated|%d", metrics.NodesEvaluated),
+			fmt.Sprintf("Nodes Filtered|%d", metrics.NodesFiltered),
+			fmt.Sprintf("Nodes Exhausted|%d", metrics.NodesExhausted),
+		}))
+
+		c.outputPlacementReasons("Exhausted Dimensions", "Dimension", tgStatus.ExhaustedDimensions)
+		c.outputPlacementReasons("Filtering Constraints", "Constraint", tgStatus.FilteringConstraints)
+		c.outputPlacementReasons("Exhausted Node Classes", "Class", tgStatus.ExhaustedClasses)
+		c.outputPlacementReasons("Filtered Node Classes", "Class", tgStatus.FilteredClasses)
+
+		for _, dim := range metrics.QuotaExhausted {
+			c.Ui.Output(fmt.Sprintf("\nQuota limit hit %q", dim))
+		}
+	}
+	return nil
+}
+
+// outputPlacementReasons prints a table of the number of nodes that could not
+// be used per reason.
+func (c *JobStatusCommand) outputPlacementReasons(title, header string, reasons []*api.PlacementReason) {
+	if len(reasons) == 0 {
+		return
+	}
+
+	out := make([]string, len(reasons)+1)
+	out[0] = fmt.Sprintf("%s|Nodes", header)
+	for i, r := range reasons {
+		out[i+1] = fmt.Sprintf("%s|%d", r.Reason, r.Nodes)
+	}
+	c.Ui.Output(fmt.Sprintf("\n%s", title))
+	c.Ui.Output(formatList(out))
+}
+
 // list general information about a list of jobs
 func createStatusListOutput(jobs []*api.JobListStub) string {
 	out := make([]string, len(jobs)+1)
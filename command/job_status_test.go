@@ -334,6 +334,54 @@ func TestJobStatusCommand_RescheduleEvals(t *testing.T) {
 	require.Contains(out, e.ID[:8])
 }
 
+func TestJobStatusCommand_Explain(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &JobStatusCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	state := srv.Agent.Server().State()
+
+	// Create a job that is blocked on memory
+	j := mock.Job()
+	require.Nil(state.UpsertJob(900, j))
+	summary := mock.JobSummary(j.ID)
+	summary.Summary["web"] = structs.TaskGroupSummary{Queued: 3}
+	require.Nil(state.UpsertJobSummary(901, summary))
+
+	failed := mock.Eval()
+	failed.JobID = j.ID
+	failed.Status = structs.EvalStatusComplete
+	failed.FailedTGAllocs = map[string]*structs.AllocMetric{
+		"web": {
+			NodesEvaluated:     2,
+			NodesExhausted:     1,
+			NodesFiltered:      1,
+			DimensionExhausted: map[string]int{"memory": 1},
+			ConstraintFiltered: map[string]int{"${attr.kernel.name} = windows": 1},
+		},
+	}
+	blocked := mock.Eval()
+	blocked.JobID = j.ID
+	blocked.Status = structs.EvalStatusBlocked
+	blocked.EscapedComputedClass = true
+	require.Nil(state.UpsertEvals(902, []*structs.Evaluation{failed, blocked}))
+
+	code := cmd.Run([]string{"-address=" + url, "-explain", j.ID})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Placement Status")
+	require.Contains(out, blocked.ID[:8])
+	require.Contains(out, "<any>")
+	require.Contains(out, `Task Group "web"`)
+	require.Contains(out, "Exhausted Dimensions")
+	require.Contains(out, "memory")
+	require.Contains(out, "${attr.kernel.name} = windows")
+}
+
 func waitForSuccess(ui cli.Ui, client *api.Client, length int, t *testing.T, evalId string) int {
 	mon := newMonitor(ui, client, length)
 	monErr := mon.monitor(evalId, false)
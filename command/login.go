@@ -0,0 +1,270 @@
+package command
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/posener/complete"
+)
+
+const (
+	// defaultOIDCCallbackAddr is the address the login command listens on
+	// for the redirect of the OIDC provider
+	defaultOIDCCallbackAddr = "localhost:4649"
+
+	// oidcCallbackPath is the path of the redirect URI
+	oidcCallbackPath = "/oidc/callback"
+)
+
+type LoginCommand struct {
+	Meta
+
+	// openURL is called with the URL the user authenticates at, in addition
+	// to the URL being printed. It is set by tests to drive the flow.
+	openURL func(string) error
+}
+
+func (c *LoginCommand) Help() string {
+	helpText := `
+Usage: nomad login [options]
+
+  Login is used to exchange the identity of a user or workload, as asserted by
+  the identity provider of an ACL auth method, for a Nomad ACL token.
+
+  For OIDC auth methods, the command prints the URL at which the user
+  authenticates with the OIDC provider and waits for the provider to redirect
+  the browser back to the callback address. For JWT auth methods, the JWT is
+  passed with -login-token.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Login Options:
+
+  -method=""
+    Specifies the name of the auth method to login with. Defaults to the
+    default auth method of the cluster.
+
+  -login-token=""
+    Specifies the JWT to login with when using a JWT auth method.
+
+  -oidc-callback-addr="localhost:4649"
+    Specifies the address the command listens on for the redirect of the OIDC
+    provider. The redirect URI "http://<addr>/oidc/callback" must be allowed
+    by the auth method.
+
+  -json
+    Output the ACL token in a JSON format.
+
+  -t
+    Format and display the ACL token using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *LoginCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-method":             complete.PredictAnything,
+			"-login-token":        complete.PredictAnything,
+			"-oidc-callback-addr": complete.PredictAnything,
+			"-json":               complete.PredictNothing,
+			"-t":                  complete.PredictAnything,
+		})
+}
+
+func (c *LoginCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *LoginCommand) Synopsis() string {
+	return "Login to Nomad using an auth method"
+}
+
+func (c *LoginCommand) Name() string { return "login" }
+
+func (c *LoginCommand) Run(args []string) int {
+	var methodName, loginToken, callbackAddr, tmpl string
+	var json bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&methodName, "method", "", "")
+	flags.StringVar(&loginToken, "login-token", "", "")
+	flags.StringVar(&callbackAddr, "oidc-callback-addr", defaultOIDCCallbackAddr, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Find the auth method, listing doesn't require a token
+	methods, _, err := client.ACLAuthMethods().List(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing ACL auth methods: %s", err))
+		return 1
+	}
+	var method *api.ACLAuthMethodListStub
+	for _, m := range methods {
+		if (methodName == "" && m.Default) || (methodName != "" && m.Name == methodName) {
+			method = m
+			break
+		}
+	}
+	if method == nil {
+		if methodName == "" {
+			c.Ui.Error("No default auth method found, specify one with -method")
+		} else {
+			c.Ui.Error(fmt.Sprintf("ACL auth method %q not found", methodName))
+		}
+		return 1
+	}
+
+	var token *api.ACLToken
+	switch method.Type {
+	case api.ACLAuthMethodTypeJWT:
+		if loginToken == "" {
+			c.Ui.Error("A JWT must be specified with -login-token")
+			return 1
+		}
+		token, _, err = client.ACLAuth().Login(&api.ACLLoginRequest{
+			AuthMethodName: method.Name,
+			LoginToken:     loginToken,
+		}, nil)
+	case api.ACLAuthMethodTypeOIDC:
+		token, err = c.oidcLogin(client, method.Name, callbackAddr)
+	default:
+		c.Ui.Error(fmt.Sprintf("Unsupported auth method type %q", method.Type))
+		return 1
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error logging in: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, token)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully logged in via %s", method.Name))
+	c.Ui.Output("")
+	c.Ui.Output(formatKVACLToken(token))
+	return 0
+}
+
+// oidcCallback holds the parameters the OIDC provider redirects with
+type oidcCallback struct {
+	code  string
+	state string
+	err   error
+}
+
+// oidcLogin completes the OIDC authorization code flow, listening on the
+// callback address for the redirect of the provider.
+func (c *LoginCommand) oidcLogin(client *api.Client, methodName, callbackAddr string) (*api.ACLToken, error) {
+	ln, err := net.Listen("tcp", callbackAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on callback address: %v", err)
+	}
+	defer ln.Close()
+
+	redirectURI := "http://" + callbackAddr + oidcCallbackPath
+	clientNonce := uuid.Generate()
+
+	resp, _, err := client.ACLAuth().GetAuthURL(&api.ACLOIDCAuthURLRequest{
+		AuthMethodName: methodName,
+		RedirectURI:    redirectURI,
+		ClientNonce:    clientNonce,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for the provider to redirect the browser back
+	callbackCh := make(chan *oidcCallback, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcCallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		cb := &oidcCallback{code: q.Get("code"), state: q.Get("state")}
+		if e := q.Get("error"); e != "" {
+			cb.err = fmt.Errorf("provider returned error %q: %s", e, q.Get("error_description"))
+		} else if cb.code == "" {
+			cb.err = fmt.Errorf("provider didn't return an authorization code")
+		}
+
+		if cb.err != nil {
+			http.Error(w, cb.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Signed in via your OIDC provider, you can close this window.")
+		}
+
+		select {
+		case callbackCh <- cb:
+		default:
+		}
+	})
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	c.Ui.Output("Complete the login via your OIDC provider at the following URL:")
+	c.Ui.Output("")
+	c.Ui.Output("    " + resp.AuthURL)
+	c.Ui.Output("")
+	c.Ui.Output("Waiting for the OIDC provider to redirect to " + redirectURI)
+	if c.openURL != nil {
+		go c.openURL(resp.AuthURL)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+
+	var cb *oidcCallback
+	select {
+	case cb = <-callbackCh:
+	case <-sigCh:
+		return nil, fmt.Errorf("interrupted")
+	}
+	if cb.err != nil {
+		return nil, cb.err
+	}
+
+	token, _, err := client.ACLAuth().CompleteAuth(&api.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: methodName,
+		ClientNonce:    clientNonce,
+		State:          cb.state,
+		Code:           cb.code,
+		RedirectURI:    redirectURI,
+	}, nil)
+	return token, err
+}
@@ -0,0 +1,150 @@
+package command
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/lib/freeport"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper/oidc/oidctest"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginCommand_OIDC(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	provider := oidctest.NewProvider(t)
+	defer provider.Close()
+	provider.SetClaims(map[string]interface{}{
+		"sub":    "alice",
+		"groups": []string{"engineering"},
+	})
+
+	// Create an OIDC auth method granting a policy to the engineers
+	callbackAddr := fmt.Sprintf("127.0.0.1:%d", freeport.GetT(t, 1)[0])
+	policy := mock.ACLPolicy()
+	method := &structs.ACLAuthMethod{
+		Name:          "oidc",
+		Type:          structs.ACLAuthMethodTypeOIDC,
+		TokenLocality: structs.ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   time.Hour,
+		Default:       true,
+		Config: &structs.ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    provider.Issuer(),
+			OIDCClientID:        oidctest.ClientID,
+			OIDCClientSecret:    oidctest.ClientSecret,
+			AllowedRedirectURIs: []string{"http://" + callbackAddr + "/oidc/callback"},
+			ListClaimMappings:   map[string]string{"groups": "groups"},
+		},
+	}
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	rule.BindName = policy.Name
+	assert.Nil(state.UpsertACLPolicies(1000, []*structs.ACLPolicy{policy}))
+	assert.Nil(state.UpsertACLAuthMethods(1001, []*structs.ACLAuthMethod{method}))
+	assert.Nil(state.UpsertACLBindingRules(1002, []*structs.ACLBindingRule{rule}))
+
+	// Authenticate with the provider as soon as the URL is printed
+	ui := new(cli.MockUi)
+	cmd := &LoginCommand{
+		Meta: Meta{Ui: ui, flagAddress: url},
+		openURL: func(u string) error {
+			resp, err := http.Get(u)
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		},
+	}
+
+	// Login with the default auth method
+	code := cmd.Run([]string{"-address=" + url, "-oidc-callback-addr=" + callbackAddr})
+	assert.Equal(0, code, ui.ErrorWriter.String())
+
+	// Check the output
+	out := ui.OutputWriter.String()
+	if !strings.Contains(out, "Successfully logged in via oidc") || !strings.Contains(out, policy.Name) {
+		t.Fatalf("bad: %v", out)
+	}
+}
+
+func TestLoginCommand_JWT(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	provider := oidctest.NewProvider(t)
+	defer provider.Close()
+
+	// Create a JWT auth method granting a policy to all workloads
+	policy := mock.ACLPolicy()
+	method := &structs.ACLAuthMethod{
+		Name:          "jwt",
+		Type:          structs.ACLAuthMethodTypeJWT,
+		TokenLocality: structs.ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   time.Hour,
+		Config: &structs.ACLAuthMethodConfig{
+			JWKSURL:     provider.JWKSURL(),
+			BoundIssuer: provider.Issuer(),
+		},
+	}
+	rule := &structs.ACLBindingRule{
+		ID:         "a3f1bd32-9a2e-4c9c-8f44-2bb2f1a6e5f0",
+		AuthMethod: method.Name,
+		BindType:   structs.ACLBindingRuleBindTypePolicy,
+		BindName:   policy.Name,
+	}
+	assert.Nil(state.UpsertACLPolicies(1000, []*structs.ACLPolicy{policy}))
+	assert.Nil(state.UpsertACLAuthMethods(1001, []*structs.ACLAuthMethod{method}))
+	assert.Nil(state.UpsertACLBindingRules(1002, []*structs.ACLBindingRule{rule}))
+
+	ui := new(cli.MockUi)
+	cmd := &LoginCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// There is no default auth method
+	code := cmd.Run([]string{"-address=" + url})
+	assert.Equal(1, code)
+
+	// A JWT is required
+	code = cmd.Run([]string{"-address=" + url, "-method=jwt"})
+	assert.Equal(1, code)
+
+	// An expired JWT is rejected
+	expired := provider.SignJWT(map[string]interface{}{
+		"sub": "web",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	})
+	code = cmd.Run([]string{"-address=" + url, "-method=jwt", "-login-token=" + expired})
+	assert.Equal(1, code)
+
+	// Login with a valid JWT
+	jwt := provider.SignJWT(map[string]interface{}{"sub": "web"})
+	code = cmd.Run([]string{"-address=" + url, "-method=jwt", "-login-token=" + jwt})
+	assert.Equal(0, code, ui.ErrorWriter.String())
+
+	// Check the output
+	out := ui.OutputWriter.String()
+	if !strings.Contains(out, "jwt login: web") || !strings.Contains(out, policy.Name) {
+		t.Fatalf("bad: %v", out)
+	}
+}
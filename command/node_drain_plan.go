@@ -0,0 +1,53 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type NodeDrainPlanCommand struct {
+	Meta
+}
+
+func (f *NodeDrainPlanCommand) Help() string {
+	helpText := `
+Usage: nomad node drain-plan <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with drain plans. A drain
+  plan drains every node matching a datacenter, node class or meta filter,
+  draining at most a fixed number of nodes at once. The next nodes are only
+  drained once the allocations migrated off the previous nodes are healthy.
+
+  Drain the nodes of a node class two at a time:
+
+      $ nomad node drain-plan run -class=batch -max-parallel=2
+
+  List drain plans:
+
+      $ nomad node drain-plan status
+
+  Inspect the progress of a drain plan:
+
+      $ nomad node drain-plan status <plan id>
+
+  Pause, resume or cancel a drain plan:
+
+      $ nomad node drain-plan pause <plan id>
+      $ nomad node drain-plan resume <plan id>
+      $ nomad node drain-plan cancel <plan id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *NodeDrainPlanCommand) Synopsis() string {
+	return "Interact with drain plans"
+}
+
+func (f *NodeDrainPlanCommand) Name() string { return "node drain-plan" }
+
+func (f *NodeDrainPlanCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type NodeDrainPlanCancelCommand struct {
+	Meta
+}
+
+func (c *NodeDrainPlanCancelCommand) Help() string {
+	helpText := `
+Usage: nomad node drain-plan cancel [options] <plan id>
+
+  Cancel is used to cancel a drain plan. A cancelled plan does not start
+  draining any further nodes. Drains already started by the plan are not
+  stopped and can be disabled with "nomad node drain -disable".
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Drain Plan Cancel Options:
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeDrainPlanCancelCommand) Synopsis() string {
+	return "Cancel a drain plan"
+}
+
+func (c *NodeDrainPlanCancelCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *NodeDrainPlanCancelCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodeDrainPlanCancelCommand) Name() string { return "node drain-plan cancel" }
+
+func (c *NodeDrainPlanCancelCommand) Run(args []string) int {
+	var verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly 1 argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <plan id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Do a prefix lookup
+	plan, possible, err := getDrainPlan(client.DrainPlans(), args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving drain plan: %s", err))
+		return 1
+	}
+
+	if len(possible) != 0 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple drain plans\n\n%s", formatDrainPlans(possible, length)))
+		return 1
+	}
+
+	if _, _, err := client.DrainPlans().Cancel(plan.ID, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error cancelling drain plan: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Drain plan %q cancelled", plan.ID))
+	return 0
+}
@@ -0,0 +1,101 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type NodeDrainPlanResumeCommand struct {
+	Meta
+}
+
+func (c *NodeDrainPlanResumeCommand) Help() string {
+	helpText := `
+Usage: nomad node drain-plan resume [options] <plan id>
+
+  Resume is used to resume a paused drain plan. Plans are also paused
+  automatically when an allocation migrated by the plan is unhealthy.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Drain Plan Resume Options:
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeDrainPlanResumeCommand) Synopsis() string {
+	return "Resume a paused drain plan"
+}
+
+func (c *NodeDrainPlanResumeCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *NodeDrainPlanResumeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodeDrainPlanResumeCommand) Name() string { return "node drain-plan resume" }
+
+func (c *NodeDrainPlanResumeCommand) Run(args []string) int {
+	var verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly 1 argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <plan id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Do a prefix lookup
+	plan, possible, err := getDrainPlan(client.DrainPlans(), args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving drain plan: %s", err))
+		return 1
+	}
+
+	if len(possible) != 0 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple drain plans\n\n%s", formatDrainPlans(possible, length)))
+		return 1
+	}
+
+	if _, _, err := client.DrainPlans().Resume(plan.ID, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error resuming drain plan: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Drain plan %q resumed", plan.ID))
+	return 0
+}
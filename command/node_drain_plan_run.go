@@ -0,0 +1,200 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
+	"github.com/posener/complete"
+)
+
+type NodeDrainPlanRunCommand struct {
+	Meta
+}
+
+func (c *NodeDrainPlanRunCommand) Help() string {
+	helpText := `
+Usage: nomad node drain-plan run [options]
+
+  Run creates a drain plan that drains every node matching the given filters.
+  At least one of -datacenter, -class or -meta must be given. Nodes are
+  drained at most -max-parallel at a time and a node keeps counting against
+  the limit until the allocations migrated off of it are healthy. If a
+  migrated allocation is unhealthy the plan is paused.
+
+  The nodes of the plan are selected when it is created. The plan's ID is
+  printed so its progress can be followed with "nomad node drain-plan status".
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Drain Plan Run Options:
+
+  -datacenter <datacenter>
+    Only drain nodes in the given datacenter.
+
+  -class <node class>
+    Only drain nodes of the given node class.
+
+  -meta <key>=<value>
+    Only drain nodes with the given meta value. May be specified multiple
+    times; nodes must match all of them.
+
+  -max-parallel <count>
+    The number of nodes that may be drained at once. Defaults to 1.
+
+  -deadline <duration>
+    Set the deadline by which all allocations must be moved off each node.
+    The deadline starts when the drain of the node is started by the plan.
+    If unspecified, a default deadline of one hour is applied.
+
+  -no-deadline
+    No deadline allows the allocations to drain off the nodes without being
+    force stopped after a certain deadline.
+
+  -ignore-system
+    Ignore system allows the drain to complete without stopping system job
+    allocations. By default system jobs are stopped last.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeDrainPlanRunCommand) Synopsis() string {
+	return "Drain a group of nodes in batches"
+}
+
+func (c *NodeDrainPlanRunCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-datacenter":    complete.PredictAnything,
+			"-class":         complete.PredictAnything,
+			"-meta":          complete.PredictAnything,
+			"-max-parallel":  complete.PredictAnything,
+			"-deadline":      complete.PredictAnything,
+			"-no-deadline":   complete.PredictNothing,
+			"-ignore-system": complete.PredictNothing,
+			"-verbose":       complete.PredictNothing,
+		})
+}
+
+func (c *NodeDrainPlanRunCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodeDrainPlanRunCommand) Name() string { return "node drain-plan run" }
+
+func (c *NodeDrainPlanRunCommand) Run(args []string) int {
+	var datacenter, class, deadline string
+	var meta []string
+	var maxParallel int
+	var noDeadline, ignoreSystem, verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&datacenter, "datacenter", "", "")
+	flags.StringVar(&class, "class", "", "")
+	flags.Var((*flaghelper.StringFlag)(&meta), "meta", "")
+	flags.IntVar(&maxParallel, "max-parallel", 1, "")
+	flags.StringVar(&deadline, "deadline", "", "")
+	flags.BoolVar(&noDeadline, "no-deadline", false, "")
+	flags.BoolVar(&ignoreSystem, "ignore-system", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Build the filter
+	filter := &api.DrainPlanFilter{
+		Datacenter: datacenter,
+		NodeClass:  class,
+	}
+	for _, m := range meta {
+		parts := strings.SplitN(m, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			c.Ui.Error(fmt.Sprintf("Invalid meta filter %q; must be of the form <key>=<value>", m))
+			return 1
+		}
+		if filter.Meta == nil {
+			filter.Meta = make(map[string]string)
+		}
+		filter.Meta[parts[0]] = parts[1]
+	}
+	if filter.Datacenter == "" && filter.NodeClass == "" && len(filter.Meta) == 0 {
+		c.Ui.Error("At least one of -datacenter, -class or -meta must be specified")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if maxParallel < 1 {
+		c.Ui.Error("-max-parallel must be at least 1")
+		return 1
+	}
+
+	if deadline != "" && noDeadline {
+		c.Ui.Error("-deadline can't be combined with -no-deadline")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Parse the duration
+	d := defaultDrainDuration
+	if noDeadline {
+		d = 0
+	} else if deadline != "" {
+		dur, err := time.ParseDuration(deadline)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse deadline %q: %v", deadline, err))
+			return 1
+		}
+		if dur <= 0 {
+			c.Ui.Error("A positive drain duration must be given")
+			return 1
+		}
+		d = dur
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	plan := &api.DrainPlan{
+		Filter: filter,
+		DrainSpec: api.DrainSpec{
+			Deadline:         d,
+			IgnoreSystemJobs: ignoreSystem,
+		},
+		MaxParallel: maxParallel,
+	}
+	resp, _, err := client.DrainPlans().Create(plan, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating drain plan: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Drain plan %q created", limit(resp.PlanID, length)))
+	return 0
+}
@@ -0,0 +1,72 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestNodeDrainPlanRunCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &NodeDrainPlanRunCommand{}
+}
+
+func TestNodeDrainPlanRunCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &NodeDrainPlanRunCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails without a filter
+	if code := cmd.Run([]string{"-max-parallel=2"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "At least one of") {
+		t.Fatalf("expected filter error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on a malformed meta filter
+	if code := cmd.Run([]string{"-meta=rack"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Invalid meta filter") {
+		t.Fatalf("expected meta error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on an invalid concurrency limit
+	if code := cmd.Run([]string{"-datacenter=dc1", "-max-parallel=0"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-max-parallel") {
+		t.Fatalf("expected max-parallel error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on conflicting deadline flags
+	if code := cmd.Run([]string{"-datacenter=dc1", "-deadline=1h", "-no-deadline"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-no-deadline") {
+		t.Fatalf("expected deadline error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "-datacenter=dc1"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error creating drain plan") {
+		t.Fatalf("expected failed create error, got: %s", out)
+	}
+}
@@ -0,0 +1,256 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type NodeDrainPlanStatusCommand struct {
+	Meta
+}
+
+func (c *NodeDrainPlanStatusCommand) Help() string {
+	helpText := `
+Usage: nomad node drain-plan status [options] [<plan id>]
+
+  Status is used to display the status of drain plans. If no plan ID is given,
+  a list of all drain plans is displayed. If a plan ID is given, the progress
+  of each of the plan's nodes is displayed.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Drain Plan Status Options:
+
+  -verbose
+    Display full information.
+
+  -json
+    Output the drain plan in its JSON format.
+
+  -t
+    Format and display the drain plan using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeDrainPlanStatusCommand) Synopsis() string {
+	return "Display the status of drain plans"
+}
+
+func (c *NodeDrainPlanStatusCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-verbose": complete.PredictNothing,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+		})
+}
+
+func (c *NodeDrainPlanStatusCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodeDrainPlanStatusCommand) Name() string { return "node drain-plan status" }
+
+func (c *NodeDrainPlanStatusCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no more than one argument
+	args = flags.Args()
+	if l := len(args); l > 1 {
+		c.Ui.Error("This command takes at most one argument: [<plan id>]")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if len(args) == 0 {
+		plans, _, err := client.DrainPlans().List(nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error retrieving drain plans: %s", err))
+			return 1
+		}
+
+		if json || len(tmpl) > 0 {
+			out, err := Format(json, tmpl, plans)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+			c.Ui.Output(out)
+			return 0
+		}
+
+		c.Ui.Output(formatDrainPlans(plans, length))
+		return 0
+	}
+
+	// Do a prefix lookup
+	plan, possible, err := getDrainPlan(client.DrainPlans(), args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving drain plan: %s", err))
+		return 1
+	}
+
+	if len(possible) != 0 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple drain plans\n\n%s", formatDrainPlans(possible, length)))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, plan)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatDrainPlan(plan, length)))
+	return 0
+}
+
+// getDrainPlan looks up a drain plan by ID prefix. If the prefix matches
+// multiple plans they are returned as possible matches.
+func getDrainPlan(client *api.DrainPlans, planID string) (match *api.DrainPlan, possible []*api.DrainPlanListStub, err error) {
+	// First attempt an immediate lookup if we have a proper length
+	if len(planID) == 36 {
+		p, _, err := client.Info(planID, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return p, nil, nil
+	}
+
+	planID = strings.Replace(planID, "-", "", -1)
+	if len(planID) == 1 {
+		return nil, nil, fmt.Errorf("Identifier must contain at least two characters.")
+	}
+	if len(planID)%2 == 1 {
+		// Identifiers must be of even length, so we strip off the last byte
+		// to provide a consistent user experience.
+		planID = planID[:len(planID)-1]
+	}
+
+	// Have to do a prefix lookup
+	plans, _, err := client.PrefixList(planID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch len(plans) {
+	case 0:
+		return nil, nil, fmt.Errorf("Drain plan ID %q matched no drain plans", planID)
+	case 1:
+		p, _, err := client.Info(plans[0].ID, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return p, nil, nil
+	default:
+		return nil, plans, nil
+	}
+}
+
+func formatDrainPlans(plans []*api.DrainPlanListStub, uuidLength int) string {
+	if len(plans) == 0 {
+		return "No drain plans found"
+	}
+
+	rows := make([]string, len(plans)+1)
+	rows[0] = "ID|Filter|Status|Nodes|Pending|Draining|Complete"
+	for i, p := range plans {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%d|%d|%d|%d",
+			limit(p.ID, uuidLength),
+			formatDrainPlanFilter(p.Filter),
+			p.Status,
+			p.TotalNodes,
+			p.PendingNodes,
+			p.DrainingNodes,
+			p.CompleteNodes)
+	}
+	return formatList(rows)
+}
+
+func formatDrainPlan(p *api.DrainPlan, uuidLength int) string {
+	deadline := "none"
+	if p.DrainSpec.Deadline > 0 {
+		deadline = p.DrainSpec.Deadline.String()
+	}
+
+	high := []string{
+		fmt.Sprintf("ID|%s", limit(p.ID, uuidLength)),
+		fmt.Sprintf("Filter|%s", formatDrainPlanFilter(p.Filter)),
+		fmt.Sprintf("Max Parallel|%d", p.MaxParallel),
+		fmt.Sprintf("Deadline|%s", deadline),
+		fmt.Sprintf("Ignore System Jobs|%v", p.DrainSpec.IgnoreSystemJobs),
+		fmt.Sprintf("Status|%s", p.Status),
+		fmt.Sprintf("Description|%s", p.StatusDescription),
+	}
+	base := formatKV(high)
+
+	if len(p.Nodes) == 0 {
+		return base
+	}
+
+	rows := make([]string, len(p.Nodes)+1)
+	rows[0] = "Node ID|Node Name|Status"
+	for i, n := range p.Nodes {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s", limit(n.NodeID, uuidLength), n.Name, n.Status)
+	}
+	return base + "\n\n[bold]Nodes[reset]\n" + formatList(rows)
+}
+
+func formatDrainPlanFilter(f *api.DrainPlanFilter) string {
+	if f == nil {
+		return ""
+	}
+
+	var parts []string
+	if f.Datacenter != "" {
+		parts = append(parts, fmt.Sprintf("datacenter=%s", f.Datacenter))
+	}
+	if f.NodeClass != "" {
+		parts = append(parts, fmt.Sprintf("class=%s", f.NodeClass))
+	}
+	keys := make([]string, 0, len(f.Meta))
+	for k := range f.Meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("meta.%s=%s", k, f.Meta[k]))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type NodeMetaCommand struct {
+	Meta
+}
+
+func (f *NodeMetaCommand) Help() string {
+	helpText := `
+Usage: nomad node meta <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with the meta of client
+  nodes. Meta set with these commands is persisted by the client and takes
+  effect without restarting it. Jobs with constraints on the changed meta are
+  re-evaluated.
+
+  Set and unset meta on the local node:
+
+      $ nomad node meta apply rack=r1 -unset=zone
+
+  Read the meta of a node:
+
+      $ nomad node meta read -node-id=<node id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *NodeMetaCommand) Synopsis() string {
+	return "Interact with node meta"
+}
+
+func (f *NodeMetaCommand) Name() string { return "node meta" }
+
+func (f *NodeMetaCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// lookupNodeID resolves a node ID prefix to a single node ID.
+func lookupNodeID(client *api.Client, nodeID string) (string, error) {
+	if len(nodeID) == 1 {
+		return "", fmt.Errorf("Identifier must contain at least two characters.")
+	}
+
+	nodeID = sanitizeUUIDPrefix(nodeID)
+	nodes, _, err := client.Nodes().PrefixList(nodeID)
+	if err != nil {
+		return "", err
+	}
+	switch len(nodes) {
+	case 0:
+		return "", fmt.Errorf("No node(s) with prefix or id %q found", nodeID)
+	case 1:
+		return nodes[0].ID, nil
+	default:
+		return "", fmt.Errorf("Prefix matched multiple nodes\n\n%s", formatNodeStubList(nodes, true))
+	}
+}
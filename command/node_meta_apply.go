@@ -0,0 +1,134 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/posener/complete"
+)
+
+type NodeMetaApplyCommand struct {
+	Meta
+}
+
+func (c *NodeMetaApplyCommand) Help() string {
+	helpText := `
+Usage: nomad node meta apply [options] <key>=<value>...
+
+  Apply sets and unsets meta on a client node. The changes are persisted by
+  the client, so they survive restarts, and override the meta set in the
+  client's configuration. Changes are applied to the local node unless
+  -node-id is given.
+
+  Jobs with constraints on the changed meta are re-evaluated once the client
+  has sent the updated node to the servers.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Node Meta Apply Options:
+
+  -node-id <node id>
+    Apply the changes to the given node instead of the local node.
+
+  -unset <key1,key2>
+    Comma separated list of meta keys to unset. Keys set in the client's
+    configuration are unset as well.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMetaApplyCommand) Synopsis() string {
+	return "Set and unset meta on a node"
+}
+
+func (c *NodeMetaApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": complete.PredictFunc(func(a complete.Args) []string {
+				client, err := c.Meta.Client()
+				if err != nil {
+					return nil
+				}
+
+				resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Nodes, nil)
+				if err != nil {
+					return []string{}
+				}
+				return resp.Matches[contexts.Nodes]
+			}),
+			"-unset": complete.PredictAnything,
+		})
+}
+
+func (c *NodeMetaApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *NodeMetaApplyCommand) Name() string { return "node meta apply" }
+
+func (c *NodeMetaApplyCommand) Run(args []string) int {
+	var nodeID, unset string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.StringVar(&unset, "unset", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Build the set of changes
+	args = flags.Args()
+	meta := make(map[string]*string, len(args))
+	for _, kv := range args {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			c.Ui.Error(fmt.Sprintf("Invalid meta %q; must be of the form <key>=<value>", kv))
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+		meta[parts[0]] = helper.StringToPtr(parts[1])
+	}
+	for _, k := range strings.Split(unset, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			meta[k] = nil
+		}
+	}
+
+	if len(meta) == 0 {
+		c.Ui.Error("At least one meta key must be set or unset")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if nodeID != "" {
+		if nodeID, err = lookupNodeID(client, nodeID); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error looking up node: %s", err))
+			return 1
+		}
+	}
+
+	req := &api.NodeMetaApplyRequest{
+		NodeID: nodeID,
+		Meta:   meta,
+	}
+	if _, err := client.NodeMeta().Apply(req, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error applying node meta: %s", err))
+		return 1
+	}
+
+	return 0
+}
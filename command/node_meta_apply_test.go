@@ -0,0 +1,66 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestNodeMetaApplyCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &NodeMetaApplyCommand{}
+}
+
+func TestNodeMetaApplyCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &NodeMetaApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Fails without changes
+	if code := cmd.Run([]string{}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "At least one meta key") {
+		t.Fatalf("expected missing meta error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on malformed meta
+	if code := cmd.Run([]string{"rack"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Invalid meta") {
+		t.Fatalf("expected invalid meta error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "rack=r1"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error applying node meta") {
+		t.Fatalf("expected failed apply error, got: %s", out)
+	}
+}
+
+func TestNodeMetaApplyCommand_Run(t *testing.T) {
+	t.Parallel()
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &NodeMetaApplyCommand{Meta: Meta{Ui: ui}}
+
+	if code := cmd.Run([]string{"-address=" + url, "rack=r1", "-unset=zone"}); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d\n%s", code, ui.ErrorWriter.String())
+	}
+
+	meta, err := client.NodeMeta().Read("", nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if meta.Meta["rack"] != "r1" {
+		t.Fatalf("expected rack meta, got: %v", meta.Meta)
+	}
+}
@@ -0,0 +1,165 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type NodeMetaReadCommand struct {
+	Meta
+}
+
+func (c *NodeMetaReadCommand) Help() string {
+	helpText := `
+Usage: nomad node meta read [options]
+
+  Read displays the meta of a client node. By default the effective meta used
+  for scheduling is displayed. The meta of the local node is read unless
+  -node-id is given.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Node Meta Read Options:
+
+  -node-id <node id>
+    Read the meta of the given node instead of the local node.
+
+  -verbose
+    Also display the meta set through the API and the meta set in the
+    client's configuration.
+
+  -json
+    Output the node meta in its JSON format.
+
+  -t
+    Format and display the node meta using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMetaReadCommand) Synopsis() string {
+	return "Read the meta of a node"
+}
+
+func (c *NodeMetaReadCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": complete.PredictFunc(func(a complete.Args) []string {
+				client, err := c.Meta.Client()
+				if err != nil {
+					return nil
+				}
+
+				resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Nodes, nil)
+				if err != nil {
+					return []string{}
+				}
+				return resp.Matches[contexts.Nodes]
+			}),
+			"-verbose": complete.PredictNothing,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+		})
+}
+
+func (c *NodeMetaReadCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodeMetaReadCommand) Name() string { return "node meta read" }
+
+func (c *NodeMetaReadCommand) Run(args []string) int {
+	var nodeID, tmpl string
+	var verbose, json bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if l := len(flags.Args()); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if nodeID != "" {
+		if nodeID, err = lookupNodeID(client, nodeID); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error looking up node: %s", err))
+			return 1
+		}
+	}
+
+	meta, err := client.NodeMeta().Read(nodeID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading node meta: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, meta)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color("[bold]All Meta[reset]"))
+	c.Ui.Output(formatNodeMeta(meta.Meta))
+
+	if verbose {
+		dynamic := make(map[string]string, len(meta.Dynamic))
+		for k, v := range meta.Dynamic {
+			if v == nil {
+				dynamic[k] = "<unset>"
+			} else {
+				dynamic[k] = *v
+			}
+		}
+		c.Ui.Output(c.Colorize().Color("\n[bold]Dynamic Meta[reset]"))
+		c.Ui.Output(formatNodeMeta(dynamic))
+		c.Ui.Output(c.Colorize().Color("\n[bold]Static Meta[reset]"))
+		c.Ui.Output(formatNodeMeta(meta.Static))
+	}
+	return 0
+}
+
+func formatNodeMeta(meta map[string]string) string {
+	if len(meta) == 0 {
+		return "No meta"
+	}
+
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rows := make([]string, len(keys))
+	for i, k := range keys {
+		rows[i] = fmt.Sprintf("%s|%s", k, meta[k])
+	}
+	return formatKV(rows)
+}
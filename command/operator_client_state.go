@@ -0,0 +1,289 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/posener/complete"
+)
+
+type OperatorClientStateCommand struct {
+	Meta
+}
+
+func (c *OperatorClientStateCommand) Help() string {
+	helpText := `
+Usage: nomad operator client-state [options] <state_dir>
+
+  Inspects the state database of a Nomad client for debugging. The state
+  directory is the "state_dir" of the client, by default the "client"
+  directory of the agent's data directory. The agent using the state
+  directory must be stopped.
+
+  The output lists the schema version of the state, the allocations that are
+  restored when the client starts, the allocations quarantined because they
+  failed to be restored and the plugins the client launched.
+
+Client State Options:
+
+  -json
+    Output the client state in a JSON format.
+
+  -t
+    Format and display the client state using a Go template.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorClientStateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-json":    complete.PredictNothing,
+		"-t":       complete.PredictAnything,
+		"-verbose": complete.PredictNothing,
+	}
+}
+
+func (c *OperatorClientStateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictDirs("*")
+}
+
+func (c *OperatorClientStateCommand) Synopsis() string {
+	return "Inspect the state database of a client"
+}
+
+func (c *OperatorClientStateCommand) Name() string { return "operator client-state" }
+
+// clientState is the inspected state of a client.
+type clientState struct {
+	SchemaVersion          uint64
+	Allocations            []*clientAllocState
+	QuarantinedAllocations []string
+	Plugins                []*clientPluginState
+	NodeMeta               map[string]*string
+}
+
+// clientAllocState is the inspected state of an allocation.
+type clientAllocState struct {
+	ID           string
+	JobID        string
+	TaskGroup    string
+	ClientStatus string
+	Tasks        []string
+	Error        string `json:",omitempty"`
+}
+
+// clientPluginState is how to reattach to a plugin launched by the client.
+type clientPluginState struct {
+	Type string
+	Name string
+	Pid  int
+	Addr string
+}
+
+func (c *OperatorClientStateCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <state_dir>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	db, err := state.NewReadOnlyBoltStateDB(log.New(ioutil.Discard, "", 0), args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error opening client state: %s", err))
+		return 1
+	}
+	defer db.Close()
+
+	cs, err := inspectClientState(db)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading client state: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, cs)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("Schema Version|%d", cs.SchemaVersion),
+		fmt.Sprintf("Latest Schema Version|%d", state.LatestSchemaVersion),
+	}))
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Allocations[reset]"))
+	c.Ui.Output(formatClientAllocStates(cs.Allocations, length))
+
+	if len(cs.QuarantinedAllocations) != 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Quarantined Allocations[reset]"))
+		rows := make([]string, len(cs.QuarantinedAllocations))
+		for i, id := range cs.QuarantinedAllocations {
+			rows[i] = limit(id, length)
+		}
+		c.Ui.Output(strings.Join(rows, "\n"))
+	}
+
+	if len(cs.Plugins) != 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Plugins[reset]"))
+		rows := make([]string, len(cs.Plugins)+1)
+		rows[0] = "Type|Name|Pid|Address"
+		for i, p := range cs.Plugins {
+			rows[i+1] = fmt.Sprintf("%s|%s|%d|%s", p.Type, p.Name, p.Pid, p.Addr)
+		}
+		c.Ui.Output(formatList(rows))
+	}
+
+	return 0
+}
+
+func formatClientAllocStates(allocs []*clientAllocState, uuidLength int) string {
+	if len(allocs) == 0 {
+		return "No allocations found"
+	}
+
+	rows := make([]string, len(allocs)+1)
+	rows[0] = "ID|Job ID|Task Group|Client Status|Tasks"
+	for i, a := range allocs {
+		status := a.ClientStatus
+		if a.Error != "" {
+			status = fmt.Sprintf("error: %s", a.Error)
+		}
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s",
+			limit(a.ID, uuidLength),
+			a.JobID,
+			a.TaskGroup,
+			status,
+			strings.Join(a.Tasks, ","))
+	}
+	return formatList(rows)
+}
+
+// inspectClientState reads the state of a client. The state of allocations
+// that can't be decoded is reported rather than failing the inspection.
+func inspectClientState(db state.StateDB) (*clientState, error) {
+	version, err := db.Version()
+	if err != nil {
+		return nil, err
+	}
+	cs := &clientState{SchemaVersion: version}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		ids, err := state.GetAllAllocationIDs(tx)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			cs.Allocations = append(cs.Allocations, inspectClientAllocState(tx, id))
+		}
+
+		cs.QuarantinedAllocations, err = state.GetQuarantinedAllocationIDs(tx)
+		if err != nil {
+			return err
+		}
+
+		plugins, err := state.GetPluginReattachConfigs(tx)
+		if err != nil {
+			return err
+		}
+		for id, rc := range plugins {
+			cs.Plugins = append(cs.Plugins, &clientPluginState{
+				Type: id.PluginType,
+				Name: id.Name,
+				Pid:  rc.Pid,
+				Addr: rc.Addr,
+			})
+		}
+		sort.Slice(cs.Plugins, func(i, j int) bool {
+			if cs.Plugins[i].Type != cs.Plugins[j].Type {
+				return cs.Plugins[i].Type < cs.Plugins[j].Type
+			}
+			return cs.Plugins[i].Name < cs.Plugins[j].Name
+		})
+
+		cs.NodeMeta, err = state.GetNodeMeta(tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cs, nil
+}
+
+// inspectClientAllocState reads the state persisted by the alloc runner of
+// an allocation.
+func inspectClientAllocState(tx *bolt.Tx, allocID string) *clientAllocState {
+	as := &clientAllocState{ID: allocID}
+
+	bkt, err := state.GetAllocationBucket(tx, allocID)
+	if err != nil {
+		as.Error = err.Error()
+		return as
+	}
+
+	// The tasks are the nested buckets of the allocation
+	bkt.ForEach(func(k, v []byte) error {
+		if v == nil {
+			as.Tasks = append(as.Tasks, string(k))
+		}
+		return nil
+	})
+
+	// Mirrors the alloc and mutable states persisted by the alloc runner
+	var allocState struct {
+		Alloc *structs.Allocation
+	}
+	var mutable struct {
+		AllocClientStatus string
+	}
+	if err := state.GetObject(bkt, []byte("alloc"), &allocState); err != nil {
+		as.Error = err.Error()
+		return as
+	}
+	if err := state.GetObject(bkt, []byte("mutable"), &mutable); err != nil {
+		as.Error = err.Error()
+		return as
+	}
+
+	if allocState.Alloc != nil {
+		as.JobID = allocState.Alloc.JobID
+		as.TaskGroup = allocState.Alloc.TaskGroup
+	}
+	as.ClientStatus = mutable.AllocClientStatus
+	return as
+}
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+)
+
+func TestOperatorClientStateCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorClientStateCommand{}
+}
+
+func TestOperatorClientStateCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &OperatorClientStateCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on a missing state database
+	if code := cmd.Run([]string{"/nonexistent"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error opening client state") {
+		t.Fatalf("expected failed open error, got: %s", out)
+	}
+}
+
+func TestOperatorClientStateCommand_Run(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "nomadtest-clientstate")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Persist an allocation the way the alloc runner does, and one that
+	// failed to be restored
+	alloc := mock.Alloc()
+	db, err := state.NewBoltStateDB(testlog.Logger(t), dir)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := db.Upgrade(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		bkt, err := state.GetAllocationBucket(tx, alloc.ID)
+		if err != nil {
+			return err
+		}
+		allocState := struct{ Alloc *structs.Allocation }{alloc}
+		if err := state.PutObject(bkt, []byte("alloc"), &allocState); err != nil {
+			return err
+		}
+		mutable := struct{ AllocClientStatus string }{structs.AllocClientStatusRunning}
+		if err := state.PutObject(bkt, []byte("mutable"), &mutable); err != nil {
+			return err
+		}
+		if _, err := state.GetTaskBucket(tx, alloc.ID, "web"); err != nil {
+			return err
+		}
+
+		if _, err := state.GetAllocationBucket(tx, "quarantined"); err != nil {
+			return err
+		}
+		return state.QuarantineAllocationBucket(tx, "quarantined")
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	db.Close()
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorClientStateCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-verbose", dir}); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	out := ui.OutputWriter.String()
+	for _, expected := range []string{"Schema Version", alloc.ID, alloc.JobID, "running", "web", "quarantined"} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("expected %q in output: %s", expected, out)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type PluginCommand struct {
+	Meta
+}
+
+func (c *PluginCommand) Help() string {
+	helpText := `
+Usage: nomad plugin <subcommand> [options]
+
+  This command groups subcommands for interacting with the CSI plugins run
+  by jobs.
+
+  Examine the status of a plugin:
+
+      $ nomad plugin status <id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *PluginCommand) Synopsis() string {
+	return "Inspect plugins"
+}
+
+func (c *PluginCommand) Name() string { return "plugin" }
+
+func (c *PluginCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
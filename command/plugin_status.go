@@ -0,0 +1,197 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type PluginStatusCommand struct {
+	Meta
+}
+
+func (c *PluginStatusCommand) Help() string {
+	helpText := `
+Usage: nomad plugin status [options] [<id>]
+
+  Display status information about the CSI plugins run by jobs. If no plugin
+  ID is given, a list of all plugins is displayed. If a plugin ID is given,
+  the health of the plugin on each of the nodes running it is displayed.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Status Options:
+
+  -verbose
+    Display full information.
+
+  -json
+    Output the plugin in its JSON format.
+
+  -t
+    Format and display the plugin using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *PluginStatusCommand) Synopsis() string {
+	return "Display status information about a plugin"
+}
+
+func (c *PluginStatusCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-verbose": complete.PredictNothing,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+		})
+}
+
+func (c *PluginStatusCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *PluginStatusCommand) Name() string { return "plugin status" }
+
+func (c *PluginStatusCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no more than one argument
+	args = flags.Args()
+	if l := len(args); l > 1 {
+		c.Ui.Error("This command takes at most one argument: [<id>]")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if len(args) == 0 {
+		plugins, _, err := client.CSIPlugins().List(nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error retrieving plugins: %s", err))
+			return 1
+		}
+
+		if json || len(tmpl) > 0 {
+			out, err := Format(json, tmpl, plugins)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+			c.Ui.Output(out)
+			return 0
+		}
+
+		c.Ui.Output(formatCSIPlugins(plugins))
+		return 0
+	}
+
+	plugin, _, err := client.CSIPlugins().Info(args[0], nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving plugin: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, plugin)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatCSIPlugin(plugin, length)))
+	return 0
+}
+
+func formatCSIPlugins(plugins []*api.CSIPluginListStub) string {
+	if len(plugins) == 0 {
+		return "No CSI plugins found"
+	}
+
+	rows := make([]string, len(plugins)+1)
+	rows[0] = "ID|Provider|Controllers Healthy/Expected|Nodes Healthy/Expected"
+	for i, p := range plugins {
+		rows[i+1] = fmt.Sprintf("%s|%s|%d/%d|%d/%d",
+			p.ID,
+			p.Provider,
+			p.ControllersHealthy,
+			p.ControllersExpected,
+			p.NodesHealthy,
+			p.NodesExpected)
+	}
+	return formatList(rows)
+}
+
+func formatCSIPlugin(p *api.CSIPlugin, uuidLength int) string {
+	high := []string{
+		fmt.Sprintf("ID|%s", p.ID),
+		fmt.Sprintf("Provider|%s", p.Provider),
+		fmt.Sprintf("Version|%s", p.Version),
+		fmt.Sprintf("Controller Required|%t", p.ControllerRequired),
+		fmt.Sprintf("Controllers Healthy|%d", p.ControllersHealthy),
+		fmt.Sprintf("Controllers Expected|%d", len(p.Controllers)),
+		fmt.Sprintf("Nodes Healthy|%d", p.NodesHealthy),
+		fmt.Sprintf("Nodes Expected|%d", len(p.Nodes)),
+	}
+	base := formatKV(high)
+
+	rows := formatCSIPluginInfos(p.Controllers, "controller", uuidLength)
+	rows = append(rows, formatCSIPluginInfos(p.Nodes, "node", uuidLength)...)
+	if len(rows) == 0 {
+		return base
+	}
+
+	rows = append([]string{"Node ID|Type|Allocation ID|Healthy|Description"}, rows...)
+	return base + "\n\n[bold]Instances[reset]\n" + formatList(rows)
+}
+
+func formatCSIPluginInfos(infos map[string]*api.CSIInfo, pluginType string, uuidLength int) []string {
+	nodeIDs := make([]string, 0, len(infos))
+	for id := range infos {
+		nodeIDs = append(nodeIDs, id)
+	}
+	sort.Strings(nodeIDs)
+
+	rows := make([]string, len(nodeIDs))
+	for i, id := range nodeIDs {
+		info := infos[id]
+		rows[i] = fmt.Sprintf("%s|%s|%s|%t|%s",
+			limit(id, uuidLength),
+			pluginType,
+			limit(info.AllocID, uuidLength),
+			info.Healthy,
+			info.HealthDescription)
+	}
+	return rows
+}
@@ -0,0 +1,38 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestPluginStatusCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &PluginStatusCommand{}
+}
+
+func TestPluginStatusCommand_Run(t *testing.T) {
+	t.Parallel()
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &PluginStatusCommand{Meta: Meta{Ui: ui}}
+
+	if code := cmd.Run([]string{"-address=" + url}); code != 0 {
+		t.Fatalf("expected exit code 0, got: %d", code)
+	}
+	if out := ui.OutputWriter.String(); !strings.Contains(out, "No CSI plugins found") {
+		t.Fatalf("expected empty list, got: %s", out)
+	}
+	ui.OutputWriter.Reset()
+
+	// Unknown plugins fail
+	if code := cmd.Run([]string{"-address=" + url, "ebs"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error retrieving plugin") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type VarCommand struct {
+	Meta
+}
+
+func (f *VarCommand) Help() string {
+	helpText := `
+Usage: nomad var <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with variables. Variables
+  are encrypted key/value items stored at a path within a namespace. Access to
+  variables is controlled by the variables block of ACL namespace policies.
+  Allocations may read the variables stored under "nomad/jobs/<job id>".
+
+  Write a variable:
+
+      $ nomad var put <path> <key>=<value> [<key>=<value>...]
+
+  Read a variable:
+
+      $ nomad var get <path>
+
+  List variables:
+
+      $ nomad var list [<prefix>]
+
+  Delete a variable:
+
+      $ nomad var purge <path>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *VarCommand) Synopsis() string {
+	return "Interact with variables"
+}
+
+func (f *VarCommand) Name() string { return "var" }
+
+func (f *VarCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// formatVariable returns the metadata and items of a variable for output
+func formatVariable(sv *api.Variable) string {
+	basic := []string{
+		fmt.Sprintf("Namespace|%s", sv.Namespace),
+		fmt.Sprintf("Path|%s", sv.Path),
+		fmt.Sprintf("Create Time|%s", formatUnixNanoTime(sv.CreateTime)),
+		fmt.Sprintf("Modify Time|%s", formatUnixNanoTime(sv.ModifyTime)),
+		fmt.Sprintf("Check Index|%d", sv.ModifyIndex),
+	}
+
+	keys := make([]string, 0, len(sv.Items))
+	for k := range sv.Items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, fmt.Sprintf("%s|%s", k, sv.Items[k]))
+	}
+
+	return fmt.Sprintf("%s\n\n[bold]Items[reset]\n%s", formatKV(basic), formatKV(items))
+}
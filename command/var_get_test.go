@@ -0,0 +1,46 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarGetCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &VarGetCommand{}
+}
+
+func TestVarGetCommand_Good(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	_, _, err := client.Variables().Upsert(&api.Variable{
+		Path:  "app/db",
+		Items: api.VariableItems{"user": "admin"},
+	}, nil)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &VarGetCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, "app/db"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.True(strings.Contains(out, "user"))
+	require.True(strings.Contains(out, "admin"))
+	ui.OutputWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-item=user", "app/db"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Equal("admin\n", ui.OutputWriter.String())
+
+	// Missing variables fail
+	code = cmd.Run([]string{"-address=" + url, "app/missing"})
+	require.Equal(1, code)
+}
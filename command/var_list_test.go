@@ -0,0 +1,43 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarListCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &VarListCommand{}
+}
+
+func TestVarListCommand_Good(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &VarListCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "No variables found")
+	ui.OutputWriter.Reset()
+
+	for _, path := range []string{"app/db", "other/db"} {
+		_, _, err := client.Variables().Upsert(&api.Variable{
+			Path:  path,
+			Items: api.VariableItems{"k": "v"},
+		}, nil)
+		require.NoError(err)
+	}
+
+	code = cmd.Run([]string{"-address=" + url, "app"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(out, "app/db")
+	require.NotContains(out, "other/db")
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarPurgeCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &VarPurgeCommand{}
+}
+
+func TestVarPurgeCommand_Good(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	sv, _, err := client.Variables().Upsert(&api.Variable{
+		Path:  "app/db",
+		Items: api.VariableItems{"k": "v"},
+	}, nil)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &VarPurgeCommand{Meta: Meta{Ui: ui}}
+
+	// A stale check index fails
+	code := cmd.Run([]string{"-address=" + url, "-check-index=1", "app/db"})
+	require.Equal(1, code)
+
+	code = cmd.Run([]string{"-address=" + url, "-check-index", "0", "app/db"})
+	require.Equal(1, code)
+
+	code = cmd.Run([]string{"-address=" + url, "app/db"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	_, _, err = client.Variables().Read(sv.Path, nil)
+	require.Error(err)
+}
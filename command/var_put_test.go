@@ -0,0 +1,60 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarPutCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &VarPutCommand{}
+}
+
+func TestVarPutCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &VarPutCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on missing items
+	if code := cmd.Run([]string{"app/db"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on malformed items
+	if code := cmd.Run([]string{"app/db", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Invalid item") {
+		t.Fatalf("expected invalid item error, got: %s", out)
+	}
+}
+
+func TestVarPutCommand_Good(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &VarPutCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, "app/db", "user=admin", "password=a=b"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	sv, _, err := client.Variables().Read("app/db", nil)
+	require.NoError(err)
+	require.Equal(api.VariableItems{"user": "admin", "password": "a=b"}, sv.Items)
+
+	// A stale check index fails
+	code = cmd.Run([]string{"-address=" + url, "-check-index=0", "app/db", "user=other"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "Check-and-set failed")
+}
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type VolumeCommand struct {
+	Meta
+}
+
+func (c *VolumeCommand) Help() string {
+	helpText := `
+Usage: nomad volume <subcommand> [options]
+
+  This command groups subcommands for interacting with CSI volumes. Volumes
+  are provided by CSI plugins run as jobs, and must be registered before
+  allocations can claim them.
+
+  Register a volume:
+
+      $ nomad volume register <path>
+
+  Examine the status of a volume:
+
+      $ nomad volume status <id>
+
+  Deregister an unused volume:
+
+      $ nomad volume deregister <id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VolumeCommand) Synopsis() string {
+	return "Interact with volumes"
+}
+
+func (c *VolumeCommand) Name() string { return "volume" }
+
+func (c *VolumeCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
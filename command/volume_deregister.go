@@ -0,0 +1,73 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type VolumeDeregisterCommand struct {
+	Meta
+}
+
+func (c *VolumeDeregisterCommand) Help() string {
+	helpText := `
+Usage: nomad volume deregister [options] <id>
+
+  Deregister is used to remove a CSI volume. Volumes claimed by allocations
+  can't be deregistered. The volume is not deleted from its storage provider.
+
+General Options:
+
+  ` + generalOptionsUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VolumeDeregisterCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *VolumeDeregisterCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VolumeDeregisterCommand) Synopsis() string {
+	return "Remove a volume"
+}
+
+func (c *VolumeDeregisterCommand) Name() string { return "volume deregister" }
+
+func (c *VolumeDeregisterCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we get exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	volID := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.CSIVolumes().Deregister(volID, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deregistering volume: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deregistered volume %q!", volID))
+	return 0
+}
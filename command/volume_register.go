@@ -0,0 +1,214 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/mapstructure"
+	"github.com/posener/complete"
+)
+
+type VolumeRegisterCommand struct {
+	Meta
+}
+
+func (c *VolumeRegisterCommand) Help() string {
+	helpText := `
+Usage: nomad volume register [options] <input>
+
+  Register is used to register a volume provided by a CSI plugin, or to
+  update a registered volume. The volume specification file will be read from
+  stdin by specifying "-", otherwise a path to the file is expected.
+
+  The specification describes a single volume:
+
+      id              = "database"
+      name            = "database"
+      external_id     = "vol-0123456789"
+      plugin_id       = "ebs"
+      access_mode     = "single-node-writer"
+      attachment_mode = "file-system"
+
+      mount_options {
+        fs_type = "ext4"
+      }
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Register Options:
+
+  -json
+    Parse the input as a JSON volume specification.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VolumeRegisterCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+		})
+}
+
+func (c *VolumeRegisterCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *VolumeRegisterCommand) Synopsis() string {
+	return "Register or update a CSI volume"
+}
+
+func (c *VolumeRegisterCommand) Name() string { return "volume register" }
+
+func (c *VolumeRegisterCommand) Run(args []string) int {
+	var jsonInput bool
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&jsonInput, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we get exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <input>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Read the file contents
+	file := args[0]
+	var rawVolume []byte
+	var err error
+	if file == "-" {
+		rawVolume, err = ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read stdin: %v", err))
+			return 1
+		}
+	} else {
+		rawVolume, err = ioutil.ReadFile(file)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read file: %v", err))
+			return 1
+		}
+	}
+
+	var vol *api.CSIVolume
+	if jsonInput {
+		var jsonVol api.CSIVolume
+		dec := json.NewDecoder(bytes.NewBuffer(rawVolume))
+		if err := dec.Decode(&jsonVol); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse volume: %v", err))
+			return 1
+		}
+		vol = &jsonVol
+	} else {
+		hclVol, err := parseCSIVolume(rawVolume)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error parsing volume specification: %s", err))
+			return 1
+		}
+		vol = hclVol
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.CSIVolumes().Register(vol, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error registering volume: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully registered volume %q!", vol.ID))
+	return 0
+}
+
+// parseCSIVolume is used to parse the volume specification from HCL
+func parseCSIVolume(input []byte) (*api.CSIVolume, error) {
+	root, err := hcl.ParseBytes(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Top-level item should be a list
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("error parsing: root should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"id",
+		"name",
+		"external_id",
+		"plugin_id",
+		"access_mode",
+		"attachment_mode",
+		"mount_options",
+		"context",
+	}
+	if err := helper.CheckHCLKeys(list, valid); err != nil {
+		return nil, err
+	}
+
+	// Decode the full thing into a map[string]interface for ease
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, list); err != nil {
+		return nil, err
+	}
+	delete(m, "mount_options")
+	delete(m, "context")
+
+	var vol api.CSIVolume
+	if err := mapstructure.WeakDecode(m, &vol); err != nil {
+		return nil, err
+	}
+
+	if o := list.Filter("mount_options"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return nil, fmt.Errorf("only one mount_options block is allowed")
+		}
+		item := o.Items[0]
+		if err := helper.CheckHCLKeys(item.Val, []string{"fs_type", "mount_flags"}); err != nil {
+			return nil, multierror.Prefix(err, "mount_options ->")
+		}
+
+		var mo map[string]interface{}
+		if err := hcl.DecodeObject(&mo, item.Val); err != nil {
+			return nil, err
+		}
+		vol.MountOptions = &api.CSIMountOptions{}
+		if err := mapstructure.WeakDecode(mo, vol.MountOptions); err != nil {
+			return nil, multierror.Prefix(err, "mount_options ->")
+		}
+	}
+
+	if o := list.Filter("context"); len(o.Items) > 0 {
+		var ctx map[string]string
+		if err := hcl.DecodeObject(&ctx, o.Items[0].Val); err != nil {
+			return nil, multierror.Prefix(err, "context ->")
+		}
+		vol.Context = ctx
+	}
+
+	return &vol, nil
+}
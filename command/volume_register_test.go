@@ -0,0 +1,80 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeRegisterCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &VolumeRegisterCommand{}
+}
+
+func TestVolumeRegisterCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &VolumeRegisterCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on a missing file
+	if code := cmd.Run([]string{"/unicorns/leprechauns"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Failed to read file") {
+		t.Fatalf("expected failed read error, got: %s", out)
+	}
+}
+
+func TestParseCSIVolume(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	input := `
+id              = "database"
+name            = "database"
+external_id     = "vol-01"
+plugin_id       = "ebs"
+access_mode     = "single-node-writer"
+attachment_mode = "file-system"
+
+mount_options {
+  fs_type     = "ext4"
+  mount_flags = ["noatime"]
+}
+
+context {
+  zone = "us-east-1a"
+}
+`
+	vol, err := parseCSIVolume([]byte(input))
+	require.NoError(err)
+	require.Equal(&api.CSIVolume{
+		ID:             "database",
+		Name:           "database",
+		ExternalID:     "vol-01",
+		PluginID:       "ebs",
+		AccessMode:     api.CSIVolumeAccessModeSingleNodeWriter,
+		AttachmentMode: api.CSIVolumeAttachmentModeFilesystem,
+		MountOptions: &api.CSIMountOptions{
+			FSType:     "ext4",
+			MountFlags: []string{"noatime"},
+		},
+		Context: map[string]string{"zone": "us-east-1a"},
+	}, vol)
+
+	// Unknown keys are rejected
+	_, err = parseCSIVolume([]byte(`size = "10GiB"`))
+	require.Error(err)
+}
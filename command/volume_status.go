@@ -0,0 +1,242 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VolumeStatusCommand struct {
+	Meta
+}
+
+func (c *VolumeStatusCommand) Help() string {
+	helpText := `
+Usage: nomad volume status [options] [<id>]
+
+  Display status information about a CSI volume. If no volume ID is given, a
+  list of all the volumes of the namespace is displayed. If a volume ID is
+  given, the volume and the allocations claiming it are displayed.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Status Options:
+
+  -plugin-id <id>
+    Only list the volumes provided by the plugin.
+
+  -verbose
+    Display full information.
+
+  -json
+    Output the volume in its JSON format.
+
+  -t
+    Format and display the volume using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VolumeStatusCommand) Synopsis() string {
+	return "Display status information about a volume"
+}
+
+func (c *VolumeStatusCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-plugin-id": complete.PredictAnything,
+			"-verbose":   complete.PredictNothing,
+			"-json":      complete.PredictNothing,
+			"-t":         complete.PredictAnything,
+		})
+}
+
+func (c *VolumeStatusCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VolumeStatusCommand) Name() string { return "volume status" }
+
+func (c *VolumeStatusCommand) Run(args []string) int {
+	var json, verbose bool
+	var pluginID, tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&pluginID, "plugin-id", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no more than one argument
+	args = flags.Args()
+	if l := len(args); l > 1 {
+		c.Ui.Error("This command takes at most one argument: [<id>]")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if len(args) == 0 {
+		q := &api.QueryOptions{}
+		if pluginID != "" {
+			q.Params = map[string]string{"plugin_id": pluginID}
+		}
+		vols, _, err := client.CSIVolumes().List(q)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error retrieving volumes: %s", err))
+			return 1
+		}
+
+		if json || len(tmpl) > 0 {
+			out, err := Format(json, tmpl, vols)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+			c.Ui.Output(out)
+			return 0
+		}
+
+		c.Ui.Output(formatCSIVolumes(vols))
+		return 0
+	}
+
+	// Do a prefix lookup if the volume isn't found
+	vol, possible, err := getCSIVolume(client.CSIVolumes(), args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving volume: %s", err))
+		return 1
+	}
+
+	if len(possible) != 0 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple volumes\n\n%s", formatCSIVolumes(possible)))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, vol)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatCSIVolume(vol, length)))
+	return 0
+}
+
+// getCSIVolume looks up a volume by ID, falling back to a prefix lookup. If
+// the prefix matches multiple volumes they are returned as possible matches.
+func getCSIVolume(client *api.CSIVolumes, volID string) (match *api.CSIVolume, possible []*api.CSIVolumeListStub, err error) {
+	vols, _, err := client.List(&api.QueryOptions{Prefix: volID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var id string
+	switch len(vols) {
+	case 0:
+		return nil, nil, fmt.Errorf("Volume ID %q matched no volumes", volID)
+	case 1:
+		id = vols[0].ID
+	default:
+		for _, v := range vols {
+			if v.ID == volID {
+				id = v.ID
+			}
+		}
+		if id == "" {
+			return nil, vols, nil
+		}
+	}
+
+	vol, _, err := client.Info(id, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return vol, nil, nil
+}
+
+func formatCSIVolumes(vols []*api.CSIVolumeListStub) string {
+	if len(vols) == 0 {
+		return "No volumes found"
+	}
+
+	rows := make([]string, len(vols)+1)
+	rows[0] = "ID|Name|Plugin ID|Schedulable|Access Mode|Readers|Writers"
+	for i, v := range vols {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%t|%s|%d|%d",
+			v.ID,
+			v.Name,
+			v.PluginID,
+			v.Schedulable,
+			v.AccessMode,
+			v.CurrentReaders,
+			v.CurrentWriters)
+	}
+	return formatList(rows)
+}
+
+func formatCSIVolume(v *api.CSIVolume, uuidLength int) string {
+	high := []string{
+		fmt.Sprintf("ID|%s", v.ID),
+		fmt.Sprintf("Name|%s", v.Name),
+		fmt.Sprintf("External ID|%s", v.ExternalID),
+		fmt.Sprintf("Plugin ID|%s", v.PluginID),
+		fmt.Sprintf("Provider|%s", v.Provider),
+		fmt.Sprintf("Schedulable|%t", v.Schedulable),
+		fmt.Sprintf("Controllers Healthy|%d", v.ControllersHealthy),
+		fmt.Sprintf("Nodes Healthy|%d", v.NodesHealthy),
+		fmt.Sprintf("Access Mode|%s", v.AccessMode),
+		fmt.Sprintf("Attachment Mode|%s", v.AttachmentMode),
+		fmt.Sprintf("Namespace|%s", v.Namespace),
+	}
+	base := formatKV(high)
+
+	claims := formatCSIVolumeClaims(v.WriteClaims, "write", uuidLength)
+	claims = append(claims, formatCSIVolumeClaims(v.ReadClaims, "read", uuidLength)...)
+	if len(claims) == 0 {
+		return base
+	}
+
+	rows := append([]string{"Allocation ID|Node ID|Mode"}, claims...)
+	return base + "\n\n[bold]Claims[reset]\n" + formatList(rows)
+}
+
+func formatCSIVolumeClaims(claims map[string]*api.CSIVolumeClaim, mode string, uuidLength int) []string {
+	ids := make([]string, 0, len(claims))
+	for id := range claims {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	rows := make([]string, len(ids))
+	for i, id := range ids {
+		rows[i] = fmt.Sprintf("%s|%s|%s", limit(id, uuidLength), limit(claims[id].NodeID, uuidLength), mode)
+	}
+	return rows
+}
@@ -0,0 +1,207 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLeeway is the clock skew tolerated when validating the time
+	// based claims of a token.
+	DefaultLeeway = 150 * time.Second
+)
+
+// Expected is the set of expectations a token's claims are validated against.
+// Empty fields are not checked.
+type Expected struct {
+	// Issuer is the expected "iss" claim
+	Issuer string
+
+	// Audiences is the set of audiences of which one must be in the "aud"
+	// claim.
+	Audiences []string
+
+	// Nonce is the expected "nonce" claim
+	Nonce string
+
+	// Leeway is the clock skew tolerated on the time based claims
+	Leeway time.Duration
+
+	// Now returns the current time, and is used for testing
+	Now func() time.Time
+}
+
+// Validate validates the claims against the expectations. The expiration
+// time of the token is always required.
+func (e *Expected) Validate(claims map[string]interface{}) error {
+	now := time.Now()
+	if e.Now != nil {
+		now = e.Now()
+	}
+
+	exp, ok, err := numericDate(claims, "exp")
+	if err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("token is missing the expiration time")
+	}
+	if now.After(exp.Add(e.Leeway)) {
+		return fmt.Errorf("token is expired")
+	}
+	if nbf, ok, err := numericDate(claims, "nbf"); err != nil {
+		return err
+	} else if ok && now.Add(e.Leeway).Before(nbf) {
+		return fmt.Errorf("token is not yet valid")
+	}
+	if iat, ok, err := numericDate(claims, "iat"); err != nil {
+		return err
+	} else if ok && now.Add(e.Leeway).Before(iat) {
+		return fmt.Errorf("token is issued in the future")
+	}
+
+	if e.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != e.Issuer {
+			return fmt.Errorf("invalid issuer %q", iss)
+		}
+	}
+
+	if len(e.Audiences) != 0 {
+		var auds []string
+		switch aud := claims["aud"].(type) {
+		case string:
+			auds = []string{aud}
+		case []interface{}:
+			for _, a := range aud {
+				if s, ok := a.(string); ok {
+					auds = append(auds, s)
+				}
+			}
+		}
+		if !containsAny(auds, e.Audiences) {
+			return fmt.Errorf("invalid audience")
+		}
+	}
+
+	if e.Nonce != "" {
+		if nonce, _ := claims["nonce"].(string); nonce != e.Nonce {
+			return fmt.Errorf("invalid nonce")
+		}
+	}
+	return nil
+}
+
+// numericDate returns the time of a NumericDate claim, and whether it is set
+func numericDate(claims map[string]interface{}, name string) (time.Time, bool, error) {
+	raw, ok := claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	var secs float64
+	switch v := raw.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %q claim: %v", name, err)
+		}
+		secs = f
+	case float64:
+		secs = v
+	default:
+		return time.Time{}, false, fmt.Errorf("invalid %q claim", name)
+	}
+	return time.Unix(int64(secs), 0), true, nil
+}
+
+func containsAny(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MapClaims extracts the claims referenced by the mappings. The keys of the
+// mappings are either the name of a top level claim or a JSON pointer to a
+// nested claim, and the values are the names the claims are mapped to.
+// Scalar claims are mapped to strings and list claims to string slices.
+// Missing claims are skipped.
+func MapClaims(claims map[string]interface{}, mappings, listMappings map[string]string) (map[string]string, map[string][]string, error) {
+	values := make(map[string]string, len(mappings))
+	for key, name := range mappings {
+		raw, ok := lookupClaim(claims, key)
+		if !ok {
+			continue
+		}
+		s, err := stringifyClaim(raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error converting claim %q to a string: %v", key, err)
+		}
+		values[name] = s
+	}
+
+	lists := make(map[string][]string, len(listMappings))
+	for key, name := range listMappings {
+		raw, ok := lookupClaim(claims, key)
+		if !ok {
+			continue
+		}
+		items, ok := raw.([]interface{})
+		if !ok {
+			items = []interface{}{raw}
+		}
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			s, err := stringifyClaim(item)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error converting claim %q to a list of strings: %v", key, err)
+			}
+			list = append(list, s)
+		}
+		lists[name] = list
+	}
+	return values, lists, nil
+}
+
+// lookupClaim returns the claim referenced by the key
+func lookupClaim(claims map[string]interface{}, key string) (interface{}, bool) {
+	if !strings.HasPrefix(key, "/") {
+		v, ok := claims[key]
+		return v, ok
+	}
+
+	var cur interface{} = claims
+	for _, part := range strings.Split(key[1:], "/") {
+		part = strings.Replace(part, "~1", "/", -1)
+		part = strings.Replace(part, "~0", "~", -1)
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func stringifyClaim(raw interface{}) (string, error) {
+	switch v := raw.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported type %T", raw)
+	}
+}
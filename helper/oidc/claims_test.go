@@ -0,0 +1,108 @@
+package oidc
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpected_Validate(t *testing.T) {
+	now := time.Now()
+	claims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":   "https://issuer.example.com",
+			"aud":   []interface{}{"nomad", "other"},
+			"nonce": "abc",
+			"iat":   json.Number(fmtUnix(now.Add(-time.Minute))),
+			"nbf":   json.Number(fmtUnix(now.Add(-time.Minute))),
+			"exp":   json.Number(fmtUnix(now.Add(time.Minute))),
+		}
+	}
+	expected := &Expected{
+		Issuer:    "https://issuer.example.com",
+		Audiences: []string{"nomad"},
+		Nonce:     "abc",
+	}
+
+	require.NoError(t, expected.Validate(claims()))
+
+	// Audience as a string
+	c := claims()
+	c["aud"] = "nomad"
+	require.NoError(t, expected.Validate(c))
+
+	cases := []struct {
+		Name   string
+		Mutate func(map[string]interface{})
+	}{
+		{"wrong issuer", func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" }},
+		{"wrong audience", func(c map[string]interface{}) { c["aud"] = "other" }},
+		{"missing audience", func(c map[string]interface{}) { delete(c, "aud") }},
+		{"wrong nonce", func(c map[string]interface{}) { c["nonce"] = "xyz" }},
+		{"missing expiry", func(c map[string]interface{}) { delete(c, "exp") }},
+		{"expired", func(c map[string]interface{}) { c["exp"] = json.Number(fmtUnix(now.Add(-time.Minute))) }},
+		{"not yet valid", func(c map[string]interface{}) { c["nbf"] = json.Number(fmtUnix(now.Add(time.Hour))) }},
+		{"issued in future", func(c map[string]interface{}) { c["iat"] = json.Number(fmtUnix(now.Add(time.Hour))) }},
+		{"invalid expiry", func(c map[string]interface{}) { c["exp"] = "tomorrow" }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			c := claims()
+			tc.Mutate(c)
+			require.Error(t, expected.Validate(c))
+		})
+	}
+
+	// Leeway tolerates clock skew
+	c = claims()
+	c["exp"] = json.Number(fmtUnix(now.Add(-time.Minute)))
+	leeway := &Expected{Leeway: DefaultLeeway}
+	require.NoError(t, leeway.Validate(c))
+}
+
+func TestMapClaims(t *testing.T) {
+	claims := map[string]interface{}{
+		"sub":    "alice",
+		"admin":  true,
+		"uid":    json.Number("1001"),
+		"groups": []interface{}{"developers", "oncall"},
+		"org": map[string]interface{}{
+			"team": "engineering",
+		},
+	}
+
+	values, lists, err := MapClaims(claims,
+		map[string]string{
+			"sub":       "user",
+			"admin":     "admin",
+			"uid":       "uid",
+			"/org/team": "team",
+			"missing":   "missing",
+		},
+		map[string]string{
+			"groups": "groups",
+			"sub":    "subs",
+		})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"user":  "alice",
+		"admin": "true",
+		"uid":   "1001",
+		"team":  "engineering",
+	}, values)
+	require.Equal(t, map[string][]string{
+		"groups": {"developers", "oncall"},
+		"subs":   {"alice"},
+	}, lists)
+
+	// Objects can't be mapped to values
+	_, _, err = MapClaims(claims, map[string]string{"org": "org"}, nil)
+	require.Error(t, err)
+}
+
+func fmtUnix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
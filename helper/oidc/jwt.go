@@ -0,0 +1,173 @@
+// Package oidc implements the subset of OpenID Connect and JWT validation used
+// by the ACL auth methods: provider discovery, the authorization code flow,
+// key sets and claim validation.
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	// Register the hash functions used by the supported algorithms
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// JWT is a parsed, but not yet verified, JSON Web Token.
+type JWT struct {
+	// Header is the decoded JOSE header of the token
+	Header Header
+
+	// Claims are the decoded claims of the token
+	Claims map[string]interface{}
+
+	signed    string
+	signature []byte
+}
+
+// Header is the JOSE header of a JWT.
+type Header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+	Type      string `json:"typ,omitempty"`
+}
+
+// ParseJWT decodes the compact serialization of a JWT. The signature is not
+// verified.
+func ParseJWT(token string) (*JWT, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token header: %v", err)
+	}
+	var header Header
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %v", err)
+	}
+
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token claims: %v", err)
+	}
+	claims := make(map[string]interface{})
+	dec := json.NewDecoder(strings.NewReader(string(rawClaims)))
+	dec.UseNumber()
+	if err := dec.Decode(&claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %v", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %v", err)
+	}
+
+	return &JWT{
+		Header:    header,
+		Claims:    claims,
+		signed:    parts[0] + "." + parts[1],
+		signature: sig,
+	}, nil
+}
+
+// KeySet is the set of public keys used to verify the signature of JWTs.
+type KeySet interface {
+	// Keys returns the candidate keys for the given key ID. The key ID may
+	// be empty if the token doesn't specify one.
+	Keys(ctx context.Context, keyID string) ([]crypto.PublicKey, error)
+}
+
+// Verify parses the JWT and verifies its signature against the key set. The
+// claims are returned without being validated.
+func Verify(ctx context.Context, keys KeySet, token string) (map[string]interface{}, error) {
+	jwt, err := ParseJWT(token)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := keys.Keys(ctx, jwt.Header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no key found to verify token signed by key %q", jwt.Header.KeyID)
+	}
+
+	for _, key := range candidates {
+		if err := verifySignature(jwt.Header.Algorithm, key, []byte(jwt.signed), jwt.signature); err == nil {
+			return jwt.Claims, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid token signature")
+}
+
+// verifySignature verifies the signature of the signed input for the given
+// algorithm and public key.
+func verifySignature(alg string, key crypto.PublicKey, signed, sig []byte) error {
+	switch alg {
+	case "RS256", "RS384", "RS512":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %s requires an RSA key", alg)
+		}
+		hash := algorithmHash(alg)
+		return rsa.VerifyPKCS1v15(pub, hash, digest(hash, signed), sig)
+
+	case "ES256", "ES384", "ES512":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %s requires an ECDSA key", alg)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return fmt.Errorf("invalid signature length")
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(pub, digest(algorithmHash(alg), signed), r, s) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+
+	case "EdDSA":
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %s requires an Ed25519 key", alg)
+		}
+		if !ed25519.Verify(pub, signed, sig) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+}
+
+// algorithmHash returns the hash function used by the signing algorithm
+func algorithmHash(alg string) crypto.Hash {
+	switch alg[2:] {
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
+
+func digest(hash crypto.Hash, data []byte) []byte {
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
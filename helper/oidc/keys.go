@@ -0,0 +1,253 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksRefreshInterval is the minimum time between fetches of a remote
+	// key set, which bounds how often an unknown key ID can trigger a fetch.
+	jwksRefreshInterval = 30 * time.Second
+)
+
+// StaticKeySet is a KeySet of locally configured public keys.
+type StaticKeySet struct {
+	keys []crypto.PublicKey
+}
+
+// NewStaticKeySet returns a key set of the given PEM encoded public keys.
+func NewStaticKeySet(pemKeys []string) (*StaticKeySet, error) {
+	s := &StaticKeySet{}
+	for i, p := range pemKeys {
+		key, err := ParsePublicKeyPEM(p)
+		if err != nil {
+			return nil, fmt.Errorf("public key %d: %v", i, err)
+		}
+		s.keys = append(s.keys, key)
+	}
+	return s, nil
+}
+
+// Keys returns all the keys of the set since PEM keys aren't identified.
+func (s *StaticKeySet) Keys(ctx context.Context, keyID string) ([]crypto.PublicKey, error) {
+	return s.keys, nil
+}
+
+// ParsePublicKeyPEM parses a PEM encoded PKIX public key or certificate.
+func ParsePublicKeyPEM(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+}
+
+// RemoteKeySet is a KeySet fetched from a JWKS endpoint. The keys are cached
+// and refetched when a token references an unknown key ID.
+type RemoteKeySet struct {
+	url    string
+	client *http.Client
+
+	keys      map[string]crypto.PublicKey
+	lastFetch time.Time
+	l         sync.Mutex
+}
+
+// NewRemoteKeySet returns a key set fetched from the given JWKS URL.
+func NewRemoteKeySet(client *http.Client, url string) *RemoteKeySet {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &RemoteKeySet{
+		url:    url,
+		client: client,
+	}
+}
+
+// Keys returns the key with the given ID, or all the keys if the ID is empty.
+func (r *RemoteKeySet) Keys(ctx context.Context, keyID string) ([]crypto.PublicKey, error) {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	if out := r.lookup(keyID); len(out) != 0 {
+		return out, nil
+	}
+
+	// Refetch the key set if the key wasn't found, unless it was
+	// just fetched.
+	if time.Since(r.lastFetch) < jwksRefreshInterval && r.keys != nil {
+		return nil, nil
+	}
+	keys, err := fetchJWKS(ctx, r.client, r.url)
+	if err != nil {
+		return nil, err
+	}
+	r.keys = keys
+	r.lastFetch = time.Now()
+	return r.lookup(keyID), nil
+}
+
+func (r *RemoteKeySet) lookup(keyID string) []crypto.PublicKey {
+	if keyID != "" {
+		if key, ok := r.keys[keyID]; ok {
+			return []crypto.PublicKey{key}
+		}
+		return nil
+	}
+
+	out := make([]crypto.PublicKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		out = append(out, key)
+	}
+	return out
+}
+
+// JSONWebKey is a single public key of a JWKS document.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+
+	// RSA keys
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC and OKP keys
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is a JWKS document.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// fetchJWKS fetches and decodes the JWKS document at the given URL. Keys of
+// unsupported types are skipped.
+func fetchJWKS(ctx context.Context, client *http.Client, url string) (map[string]crypto.PublicKey, error) {
+	var set JSONWebKeySet
+	if err := getJSON(ctx, client, url, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch key set: %v", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	return keys, nil
+}
+
+// PublicKey decodes the public key of the JWK.
+func (j *JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch j.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(j.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", j.Curve)
+		}
+		x, err := decodeBigInt(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if j.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", j.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", j.KeyType)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// getJSON fetches the URL and decodes the JSON response into out
+func getJSON(ctx context.Context, client *http.Client, url string, out interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
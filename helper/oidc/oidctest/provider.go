@@ -0,0 +1,271 @@
+// Package oidctest provides a stand-in OpenID Connect provider for testing
+// the OIDC and JWT auth methods without an external identity provider.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/oidc"
+	"github.com/hashicorp/nomad/helper/uuid"
+)
+
+const (
+	// ClientID and ClientSecret are the credentials of the only client
+	// registered with the provider.
+	ClientID     = "nomad"
+	ClientSecret = "nomad-secret"
+
+	keyID = "oidctest"
+)
+
+// TestingT is the subset of testing.T used by the provider.
+type TestingT interface {
+	Fatalf(format string, args ...interface{})
+}
+
+// Provider is an OpenID Connect provider serving the discovery document, the
+// JWKS, and the authorization and token endpoints of the authorization code
+// flow. The authorization endpoint authenticates every request immediately
+// as the configured subject.
+type Provider struct {
+	srv *httptest.Server
+	key *rsa.PrivateKey
+
+	l           sync.Mutex
+	claims      map[string]interface{}
+	codes       map[string]*authCode
+	discoveries int
+}
+
+// authCode is an issued authorization code awaiting exchange
+type authCode struct {
+	redirectURI string
+	nonce       string
+}
+
+// NewProvider starts a provider listening on a local address. It must be
+// closed once the test completes.
+func NewProvider(t TestingT) *Provider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	p := &Provider{
+		key: key,
+		claims: map[string]interface{}{
+			"sub": "alice",
+		},
+		codes: make(map[string]*authCode),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.handleDiscovery)
+	mux.HandleFunc("/keys", p.handleKeys)
+	mux.HandleFunc("/authorize", p.handleAuthorize)
+	mux.HandleFunc("/token", p.handleToken)
+	p.srv = httptest.NewServer(mux)
+	return p
+}
+
+// Close shuts down the provider.
+func (p *Provider) Close() {
+	p.srv.Close()
+}
+
+// Issuer returns the issuer URL of the provider, which is also its discovery
+// URL.
+func (p *Provider) Issuer() string {
+	return p.srv.URL
+}
+
+// JWKSURL returns the URL of the provider's key set.
+func (p *Provider) JWKSURL() string {
+	return p.srv.URL + "/keys"
+}
+
+// PublicKeyPEM returns the PEM encoded public key signing the tokens.
+func (p *Provider) PublicKeyPEM() string {
+	der, err := x509.MarshalPKIXPublicKey(&p.key.PublicKey)
+	if err != nil {
+		panic(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// SetClaims sets the custom claims of the ID tokens issued by the provider.
+// The standard claims are always set by the provider.
+func (p *Provider) SetClaims(claims map[string]interface{}) {
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.claims = claims
+}
+
+// SignJWT returns a JWT with the given claims signed by the provider. The
+// issuer, issued at and expiration claims are added unless set.
+func (p *Provider) SignJWT(claims map[string]interface{}) string {
+	now := time.Now()
+	out := map[string]interface{}{
+		"iss": p.Issuer(),
+		"iat": now.Unix(),
+		"exp": now.Add(5 * time.Minute).Unix(),
+	}
+	for k, v := range claims {
+		out[k] = v
+	}
+
+	header, _ := json.Marshal(&oidc.Header{Algorithm: "RS256", KeyID: keyID, Type: "JWT"})
+	payload, err := json.Marshal(out)
+	if err != nil {
+		panic(err)
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// Discoveries returns the number of times the discovery document was served
+func (p *Provider) Discoveries() int {
+	p.l.Lock()
+	defer p.l.Unlock()
+	return p.discoveries
+}
+
+func (p *Provider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	p.l.Lock()
+	p.discoveries++
+	p.l.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.Issuer(),
+		"authorization_endpoint":                p.srv.URL + "/authorize",
+		"token_endpoint":                        p.srv.URL + "/token",
+		"jwks_uri":                              p.JWKSURL(),
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (p *Provider) handleKeys(w http.ResponseWriter, r *http.Request) {
+	pub := p.key.PublicKey
+	writeJSON(w, http.StatusOK, &oidc.JSONWebKeySet{
+		Keys: []oidc.JSONWebKey{{
+			KeyType:   "RSA",
+			KeyID:     keyID,
+			Use:       "sig",
+			Algorithm: "RS256",
+			N:         base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// handleAuthorize authenticates the request and redirects back to the client
+// with an authorization code.
+func (p *Provider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != ClientID {
+		http.Error(w, "unknown client", http.StatusBadRequest)
+		return
+	}
+	if q.Get("response_type") != "code" {
+		http.Error(w, "unsupported response type", http.StatusBadRequest)
+		return
+	}
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirect.Scheme == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	code := uuid.Generate()
+	p.l.Lock()
+	p.codes[code] = &authCode{
+		redirectURI: q.Get("redirect_uri"),
+		nonce:       q.Get("nonce"),
+	}
+	p.l.Unlock()
+
+	v := redirect.Query()
+	v.Set("code", code)
+	v.Set("state", q.Get("state"))
+	redirect.RawQuery = v.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// handleToken exchanges an authorization code for a signed ID token
+func (p *Provider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, secret, ok := r.BasicAuth()
+	if ok {
+		id, _ = url.QueryUnescape(id)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		id, secret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+	if id != ClientID || secret != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostFormValue("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	p.l.Lock()
+	code, ok := p.codes[r.PostFormValue("code")]
+	delete(p.codes, r.PostFormValue("code"))
+	claims := make(map[string]interface{}, len(p.claims)+2)
+	for k, v := range p.claims {
+		claims[k] = v
+	}
+	p.l.Unlock()
+
+	if !ok || code.redirectURI != r.PostFormValue("redirect_uri") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims["aud"] = ClientID
+	if code.nonce != "" {
+		claims["nonce"] = code.nonce
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": uuid.Generate(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     p.SignJWT(claims),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		panic(fmt.Sprintf("failed to encode response: %v", err))
+	}
+}
@@ -0,0 +1,111 @@
+package oidctest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/oidc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider_AuthCodeFlow(t *testing.T) {
+	t.Parallel()
+	p := NewProvider(t)
+	defer p.Close()
+	p.SetClaims(map[string]interface{}{
+		"sub":    "alice",
+		"groups": []string{"engineering"},
+	})
+
+	ctx := context.Background()
+	provider, err := oidc.Discover(ctx, nil, p.Issuer())
+	require.NoError(t, err)
+	require.Equal(t, p.Issuer(), provider.Issuer)
+
+	// Follow the authorization URL without following the redirect back
+	redirectURI := "http://localhost:4649/oidc/callback"
+	authURL := provider.AuthCodeURL(ClientID, redirectURI, "state1", "nonce1", []string{"groups"})
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, "state1", loc.Query().Get("state"))
+	code := loc.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	// Exchanging with the wrong secret fails
+	_, err = provider.Exchange(ctx, ClientID, "wrong", code, redirectURI)
+	require.Error(t, err)
+
+	idToken, err := provider.Exchange(ctx, ClientID, ClientSecret, code, redirectURI)
+	require.NoError(t, err)
+
+	// The code can only be used once
+	_, err = provider.Exchange(ctx, ClientID, ClientSecret, code, redirectURI)
+	require.Error(t, err)
+
+	claims, err := oidc.Verify(ctx, provider.KeySet(), idToken)
+	require.NoError(t, err)
+	expected := &oidc.Expected{
+		Issuer:    p.Issuer(),
+		Audiences: []string{ClientID},
+		Nonce:     "nonce1",
+	}
+	require.NoError(t, expected.Validate(claims))
+	require.Equal(t, "alice", claims["sub"])
+	require.Equal(t, []interface{}{"engineering"}, claims["groups"])
+}
+
+func TestProvider_StaticKeys(t *testing.T) {
+	t.Parallel()
+	p := NewProvider(t)
+	defer p.Close()
+
+	other := NewProvider(t)
+	defer other.Close()
+
+	keys, err := oidc.NewStaticKeySet([]string{p.PublicKeyPEM()})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	claims, err := oidc.Verify(ctx, keys, p.SignJWT(map[string]interface{}{"sub": "bob"}))
+	require.NoError(t, err)
+	require.Equal(t, "bob", claims["sub"])
+
+	// Tokens signed by another key are rejected
+	_, err = oidc.Verify(ctx, keys, other.SignJWT(map[string]interface{}{"sub": "bob"}))
+	require.Error(t, err)
+
+	// Tampered tokens are rejected
+	token := p.SignJWT(map[string]interface{}{"sub": "bob"})
+	_, err = oidc.Verify(ctx, keys, token[:len(token)-4]+"AAAA")
+	require.Error(t, err)
+}
+
+func TestProvider_RemoteKeys(t *testing.T) {
+	t.Parallel()
+	p := NewProvider(t)
+	defer p.Close()
+
+	resp, err := http.Get(p.JWKSURL())
+	require.NoError(t, err)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.Contains(t, string(body), `"kid":"oidctest"`)
+
+	keys := oidc.NewRemoteKeySet(nil, p.JWKSURL())
+	claims, err := oidc.Verify(context.Background(), keys, p.SignJWT(map[string]interface{}{"sub": "carol"}))
+	require.NoError(t, err)
+	require.Equal(t, "carol", claims["sub"])
+}
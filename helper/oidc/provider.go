@@ -0,0 +1,127 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Provider is an OpenID Connect provider, as described by its discovery
+// document.
+type Provider struct {
+	// Issuer is the issuer identifier of the provider
+	Issuer string `json:"issuer"`
+
+	// AuthURL is the authorization endpoint the user is redirected to
+	AuthURL string `json:"authorization_endpoint"`
+
+	// TokenURL is the endpoint used to exchange authorization codes
+	TokenURL string `json:"token_endpoint"`
+
+	// JWKSURL is the endpoint serving the provider's signing keys
+	JWKSURL string `json:"jwks_uri"`
+
+	client *http.Client
+	keys   *RemoteKeySet
+}
+
+// Discover fetches the discovery document of the provider with the given
+// issuer URL. The issuer of the document must match the URL.
+func Discover(ctx context.Context, client *http.Client, issuer string) (*Provider, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	p := &Provider{client: client}
+	if err := getJSON(ctx, client, wellKnown, p); err != nil {
+		return nil, fmt.Errorf("failed to discover provider %q: %v", issuer, err)
+	}
+	if p.Issuer != issuer {
+		return nil, fmt.Errorf("provider issuer %q does not match discovery URL %q", p.Issuer, issuer)
+	}
+	if p.AuthURL == "" || p.TokenURL == "" || p.JWKSURL == "" {
+		return nil, fmt.Errorf("provider %q discovery document is incomplete", issuer)
+	}
+	p.keys = NewRemoteKeySet(client, p.JWKSURL)
+	return p, nil
+}
+
+// KeySet returns the key set used to verify the provider's ID tokens.
+func (p *Provider) KeySet() KeySet {
+	return p.keys
+}
+
+// AuthCodeURL returns the URL the user visits to authenticate with the
+// provider using the authorization code flow.
+func (p *Provider) AuthCodeURL(clientID, redirectURI, state, nonce string, scopes []string) string {
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", clientID)
+	v.Set("redirect_uri", redirectURI)
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+	v.Set("scope", strings.Join(append([]string{"openid"}, scopes...), " "))
+
+	sep := "?"
+	if strings.Contains(p.AuthURL, "?") {
+		sep = "&"
+	}
+	return p.AuthURL + sep + v.Encode()
+}
+
+// tokenResponse is the response of the token endpoint
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+	ErrorDesc   string `json:"error_description"`
+}
+
+// Exchange exchanges the authorization code for the ID token issued by the
+// provider. The ID token is returned unverified.
+func (p *Provider) Exchange(ctx context.Context, clientID, clientSecret, code, redirectURI string) (string, error) {
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", redirectURI)
+
+	req, err := http.NewRequest("POST", p.TokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+
+	resp, err := p.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange authorization code: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read token response: %v", err)
+	}
+	var out tokenResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || out.Error != "" {
+		if out.Error != "" {
+			return "", fmt.Errorf("failed to exchange authorization code: %s: %s", out.Error, out.ErrorDesc)
+		}
+		return "", fmt.Errorf("failed to exchange authorization code: unexpected response code %d", resp.StatusCode)
+	}
+	if out.IDToken == "" {
+		return "", fmt.Errorf("token response is missing the ID token")
+	}
+	return out.IDToken, nil
+}
@@ -0,0 +1,234 @@
+package oidc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Selector is a boolean expression evaluated against the claims mapped from
+// a token to decide whether a binding rule applies. The grammar is a
+// conjunction of clauses:
+//
+//	value.team == "engineering" and "admins" in list.groups
+//
+// The supported clauses are:
+//
+//	value.<name> == "<string>"
+//	value.<name> != "<string>"
+//	"<string>" in list.<name>
+//	"<string>" not in list.<name>
+//
+// The empty selector matches all tokens.
+type Selector struct {
+	clauses []*selectorClause
+}
+
+type selectorClause struct {
+	op    string
+	name  string
+	value string
+}
+
+// ParseSelector parses the selector expression.
+func ParseSelector(expr string) (*Selector, error) {
+	tokens, err := tokenizeSelector(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Selector{}
+	for len(tokens) != 0 {
+		if len(s.clauses) != 0 {
+			if tokens[0].kind != tokenWord || tokens[0].text != "and" {
+				return nil, fmt.Errorf("expected \"and\" at %q", tokens[0].text)
+			}
+			tokens = tokens[1:]
+		}
+
+		var clause *selectorClause
+		clause, tokens, err = parseClause(tokens)
+		if err != nil {
+			return nil, err
+		}
+		s.clauses = append(s.clauses, clause)
+	}
+	return s, nil
+}
+
+func parseClause(tokens []selectorToken) (*selectorClause, []selectorToken, error) {
+	next := func() (selectorToken, bool) {
+		if len(tokens) == 0 {
+			return selectorToken{}, false
+		}
+		t := tokens[0]
+		tokens = tokens[1:]
+		return t, true
+	}
+
+	first, _ := next()
+	switch first.kind {
+	case tokenWord:
+		if !strings.HasPrefix(first.text, "value.") || len(first.text) == len("value.") {
+			return nil, nil, fmt.Errorf("expected value.<name> at %q", first.text)
+		}
+		op, ok := next()
+		if !ok || op.kind != tokenOperator {
+			return nil, nil, fmt.Errorf("expected == or != after %q", first.text)
+		}
+		str, ok := next()
+		if !ok || str.kind != tokenString {
+			return nil, nil, fmt.Errorf("expected a quoted string after %q", op.text)
+		}
+		return &selectorClause{
+			op:    op.text,
+			name:  strings.TrimPrefix(first.text, "value."),
+			value: str.text,
+		}, tokens, nil
+
+	case tokenString:
+		op, ok := next()
+		if !ok || op.kind != tokenWord || (op.text != "in" && op.text != "not") {
+			return nil, nil, fmt.Errorf("expected \"in\" after %q", first.text)
+		}
+		if op.text == "not" {
+			in, ok := next()
+			if !ok || in.kind != tokenWord || in.text != "in" {
+				return nil, nil, fmt.Errorf("expected \"in\" after \"not\"")
+			}
+			op.text = "not in"
+		}
+		list, ok := next()
+		if !ok || list.kind != tokenWord || !strings.HasPrefix(list.text, "list.") || len(list.text) == len("list.") {
+			return nil, nil, fmt.Errorf("expected list.<name> after %q", op.text)
+		}
+		return &selectorClause{
+			op:    op.text,
+			name:  strings.TrimPrefix(list.text, "list."),
+			value: first.text,
+		}, tokens, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unexpected %q", first.text)
+	}
+}
+
+// Match returns whether the mapped claims satisfy the selector.
+func (s *Selector) Match(values map[string]string, lists map[string][]string) bool {
+	for _, c := range s.clauses {
+		switch c.op {
+		case "==", "!=":
+			v, ok := values[c.name]
+			if (c.op == "==") != (ok && v == c.value) {
+				return false
+			}
+		case "in", "not in":
+			found := false
+			for _, item := range lists[c.name] {
+				if item == c.value {
+					found = true
+					break
+				}
+			}
+			if (c.op == "in") != found {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+const (
+	tokenWord = iota
+	tokenString
+	tokenOperator
+)
+
+type selectorToken struct {
+	kind int
+	text string
+}
+
+func tokenizeSelector(expr string) ([]selectorToken, error) {
+	var tokens []selectorToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+
+		case c == '"':
+			// Find the closing quote, skipping escaped characters
+			j := i + 1
+			for ; j < len(expr) && expr[j] != '"'; j++ {
+				if expr[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at %q", expr[i:])
+			}
+			s, err := strconv.Unquote(expr[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s: %v", expr[i:j+1], err)
+			}
+			tokens = append(tokens, selectorToken{tokenString, s})
+			i = j + 1
+
+		case c == '=' || c == '!':
+			if i+1 >= len(expr) || expr[i+1] != '=' {
+				return nil, fmt.Errorf("invalid operator at %q", expr[i:])
+			}
+			tokens = append(tokens, selectorToken{tokenOperator, expr[i : i+2]})
+			i += 2
+
+		case isWordChar(rune(c)):
+			j := i
+			for j < len(expr) && isWordChar(rune(expr[j])) {
+				j++
+			}
+			tokens = append(tokens, selectorToken{tokenWord, expr[i:j]})
+			i = j
+
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return tokens, nil
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+// InterpolateValues replaces the ${value.<name>} references in s with the
+// mapped claim values. It is an error to reference a missing value.
+func InterpolateValues(s string, values map[string]string) (string, error) {
+	var out strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			out.WriteString(s)
+			return out.String(), nil
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated reference in %q", s)
+		}
+		end += start
+
+		ref := strings.TrimSpace(s[start+2 : end])
+		if !strings.HasPrefix(ref, "value.") {
+			return "", fmt.Errorf("invalid reference %q", ref)
+		}
+		v, ok := values[strings.TrimPrefix(ref, "value.")]
+		if !ok {
+			return "", fmt.Errorf("missing value for %q", ref)
+		}
+
+		out.WriteString(s[:start])
+		out.WriteString(v)
+		s = s[end+1:]
+	}
+}
@@ -0,0 +1,90 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelector_Match(t *testing.T) {
+	values := map[string]string{
+		"team": "engineering",
+		"role": "admin",
+	}
+	lists := map[string][]string{
+		"groups": {"developers", "oncall"},
+	}
+
+	cases := []struct {
+		Selector string
+		Match    bool
+	}{
+		{``, true},
+		{`value.team == "engineering"`, true},
+		{`value.team == "sales"`, false},
+		{`value.team != "sales"`, true},
+		{`value.missing == "x"`, false},
+		{`value.missing != "x"`, true},
+		{`"oncall" in list.groups`, true},
+		{`"admins" in list.groups`, false},
+		{`"admins" not in list.groups`, true},
+		{`"oncall" in list.missing`, false},
+		{`value.team == "engineering" and "oncall" in list.groups`, true},
+		{`value.team == "engineering" and value.role == "viewer"`, false},
+		{`value.team == "eng\"ineering"`, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Selector, func(t *testing.T) {
+			s, err := ParseSelector(c.Selector)
+			require.NoError(t, err)
+			require.Equal(t, c.Match, s.Match(values, lists))
+		})
+	}
+}
+
+func TestSelector_Parse_Invalid(t *testing.T) {
+	cases := []string{
+		`team == "engineering"`,
+		`value.team = "engineering"`,
+		`value.team == engineering`,
+		`value.team == "engineering`,
+		`"oncall" in groups`,
+		`"oncall" not list.groups`,
+		`value.team == "a" or value.team == "b"`,
+		`value.team == "a" and`,
+		`value. == "a"`,
+		`(value.team == "a")`,
+	}
+
+	for _, c := range cases {
+		t.Run(c, func(t *testing.T) {
+			_, err := ParseSelector(c)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestInterpolateValues(t *testing.T) {
+	values := map[string]string{
+		"team": "engineering",
+		"env":  "prod",
+	}
+
+	out, err := InterpolateValues("${value.team}-${ value.env }", values)
+	require.NoError(t, err)
+	require.Equal(t, "engineering-prod", out)
+
+	out, err = InterpolateValues("static", values)
+	require.NoError(t, err)
+	require.Equal(t, "static", out)
+
+	_, err = InterpolateValues("${value.missing}", values)
+	require.Error(t, err)
+
+	_, err = InterpolateValues("${list.groups}", values)
+	require.Error(t, err)
+
+	_, err = InterpolateValues("${value.team", values)
+	require.Error(t, err)
+}
@@ -295,6 +295,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"reschedule",
 			"vault",
 			"migrate",
+			"volume",
 		}
 		if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -312,6 +313,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 		delete(m, "update")
 		delete(m, "vault")
 		delete(m, "migrate")
+		delete(m, "volume")
 
 		// Build the group with the basic decode
 		var g api.TaskGroup
@@ -362,6 +364,13 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			}
 		}
 
+		// Parse volumes
+		if o := listVal.Filter("volume"); len(o.Items) > 0 {
+			if err := parseVolumes(&g.Volumes, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', volume ->", n))
+			}
+		}
+
 		// Parse out meta fields. These are in HCL as a list so we need
 		// to iterate over them and merge them.
 		if metaO := listVal.Filter("meta"); len(metaO.Items) > 0 {
@@ -609,6 +618,73 @@ func parseEphemeralDisk(result **api.EphemeralDisk, list *ast.ObjectList) error
 	return nil
 }
 
+func parseVolumes(out *map[string]*api.VolumeRequest, list *ast.ObjectList) error {
+	volumes := make(map[string]*api.VolumeRequest, len(list.Items))
+
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("volume must have exactly one name")
+		}
+		n := item.Keys[0].Token.Value().(string)
+		if _, ok := volumes[n]; ok {
+			return fmt.Errorf("volume %q defined more than once", n)
+		}
+
+		valid := []string{
+			"type",
+			"source",
+			"read_only",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		var v api.VolumeRequest
+		if err := mapstructure.WeakDecode(m, &v); err != nil {
+			return err
+		}
+		v.Name = n
+		volumes[n] = &v
+	}
+
+	*out = volumes
+	return nil
+}
+
+func parseVolumeMounts(out *[]*api.VolumeMount, list *ast.ObjectList) error {
+	mounts := make([]*api.VolumeMount, len(list.Items))
+
+	for i, item := range list.Items {
+		valid := []string{
+			"volume",
+			"destination",
+			"read_only",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		var mount api.VolumeMount
+		if err := mapstructure.WeakDecode(m, &mount); err != nil {
+			return err
+		}
+		mounts[i] = &mount
+	}
+
+	*out = mounts
+	return nil
+}
+
 // parseBool takes an interface value and tries to convert it to a boolean and
 // returns an error if the type can't be converted.
 func parseBool(value interface{}) (bool, error) {
@@ -656,9 +732,11 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			"artifact",
 			"config",
 			"constraint",
+			"csi_plugin",
 			"dispatch_payload",
 			"driver",
 			"env",
+			"identity",
 			"kill_timeout",
 			"leader",
 			"logs",
@@ -670,6 +748,7 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			"user",
 			"vault",
 			"kill_signal",
+			"volume_mount",
 		}
 		if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -682,14 +761,17 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 		delete(m, "artifact")
 		delete(m, "config")
 		delete(m, "constraint")
+		delete(m, "csi_plugin")
 		delete(m, "dispatch_payload")
 		delete(m, "env")
+		delete(m, "identity")
 		delete(m, "logs")
 		delete(m, "meta")
 		delete(m, "resources")
 		delete(m, "service")
 		delete(m, "template")
 		delete(m, "vault")
+		delete(m, "volume_mount")
 
 		// Build the task
 		var t api.Task
@@ -751,6 +833,14 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			}
 		}
 
+		// Parse volume mounts
+		if o := listVal.Filter("volume_mount"); len(o.Items) > 0 {
+			if err := parseVolumeMounts(&t.VolumeMounts, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf(
+					"'%s', volume_mount ->", n))
+			}
+		}
+
 		// Parse out meta fields. These are in HCL as a list so we need
 		// to iterate over them and merge them.
 		if metaO := listVal.Filter("meta"); len(metaO.Items) > 0 {
@@ -787,6 +877,9 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			valid := []string{
 				"max_files",
 				"max_file_size",
+				"rotate_interval",
+				"compress",
+				"log_sink",
 			}
 			if err := helper.CheckHCLKeys(logsBlock.Val, valid); err != nil {
 				return multierror.Prefix(err, fmt.Sprintf("'%s', logs ->", n))
@@ -795,12 +888,30 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			if err := hcl.DecodeObject(&m, logsBlock.Val); err != nil {
 				return err
 			}
+			delete(m, "log_sink")
 
 			var log api.LogConfig
-			if err := mapstructure.WeakDecode(m, &log); err != nil {
+			dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+				DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+				WeaklyTypedInput: true,
+				Result:           &log,
+			})
+			if err != nil {
+				return err
+			}
+			if err := dec.Decode(m); err != nil {
 				return err
 			}
 
+			// Parse log sinks
+			if ot, ok := logsBlock.Val.(*ast.ObjectType); ok {
+				if o := ot.List.Filter("log_sink"); len(o.Items) > 0 {
+					if err := parseLogSinks(&log.Sinks, o); err != nil {
+						return multierror.Prefix(err, fmt.Sprintf("'%s', logs ->", n))
+					}
+				}
+			}
+
 			t.LogConfig = &log
 		}
 
@@ -858,6 +969,61 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			}
 		}
 
+		// If we have an identity block parse that
+		if o := listVal.Filter("identity"); len(o.Items) > 0 {
+			if len(o.Items) > 1 {
+				return fmt.Errorf("only one identity block is allowed in a task. Number of identity blocks found: %d", len(o.Items))
+			}
+			var m map[string]interface{}
+			identityBlock := o.Items[0]
+
+			// Check for invalid keys
+			valid := []string{
+				"env",
+				"file",
+			}
+			if err := helper.CheckHCLKeys(identityBlock.Val, valid); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', identity ->", n))
+			}
+
+			if err := hcl.DecodeObject(&m, identityBlock.Val); err != nil {
+				return err
+			}
+
+			t.Identity = &api.WorkloadIdentity{}
+			if err := mapstructure.WeakDecode(m, t.Identity); err != nil {
+				return err
+			}
+		}
+
+		// If we have a csi_plugin block parse that
+		if o := listVal.Filter("csi_plugin"); len(o.Items) > 0 {
+			if len(o.Items) > 1 {
+				return fmt.Errorf("only one csi_plugin block is allowed in a task. Number of csi_plugin blocks found: %d", len(o.Items))
+			}
+			var m map[string]interface{}
+			pluginBlock := o.Items[0]
+
+			// Check for invalid keys
+			valid := []string{
+				"id",
+				"type",
+				"mount_dir",
+			}
+			if err := helper.CheckHCLKeys(pluginBlock.Val, valid); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', csi_plugin ->", n))
+			}
+
+			if err := hcl.DecodeObject(&m, pluginBlock.Val); err != nil {
+				return err
+			}
+
+			t.CSIPluginConfig = &api.TaskCSIPluginConfig{}
+			if err := mapstructure.WeakDecode(m, t.CSIPluginConfig); err != nil {
+				return err
+			}
+		}
+
 		*result = append(*result, &t)
 	}
 
@@ -980,6 +1146,51 @@ func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseLogSinks(result *[]*api.LogSink, list *ast.ObjectList) error {
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("log_sink must have exactly one type")
+		}
+		sinkType := item.Keys[0].Token.Value().(string)
+
+		// Check for invalid keys
+		valid := []string{
+			"address",
+			"tag",
+			"batch_size",
+			"batch_wait",
+			"buffer_size",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("log_sink %q ->", sinkType))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		sink := &api.LogSink{
+			Type: helper.StringToPtr(sinkType),
+		}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           sink,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return err
+		}
+
+		*result = append(*result, sink)
+	}
+
+	return nil
+}
+
 func parseServices(jobName string, taskGroupName string, task *api.Task, serviceObjs *ast.ObjectList) error {
 	task.Services = make([]*api.Service, len(serviceObjs.Items))
 	for idx, o := range serviceObjs.Items {
@@ -1202,6 +1413,7 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		"iops",
 		"disk",
 		"memory",
+		"memory_max",
 		"network",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
@@ -172,8 +172,10 @@ func TestParse(t *testing.T) {
 								KillTimeout:   helper.TimeToPtr(22 * time.Second),
 								ShutdownDelay: 11 * time.Second,
 								LogConfig: &api.LogConfig{
-									MaxFiles:      helper.IntToPtr(14),
-									MaxFileSizeMB: helper.IntToPtr(101),
+									MaxFiles:       helper.IntToPtr(14),
+									MaxFileSizeMB:  helper.IntToPtr(101),
+									RotateInterval: helper.TimeToPtr(24 * time.Hour),
+									Compress:       helper.BoolToPtr(true),
 								},
 								Artifacts: []*api.TaskArtifact{
 									{
@@ -228,9 +230,10 @@ func TestParse(t *testing.T) {
 									"image": "hashicorp/storagelocker",
 								},
 								Resources: &api.Resources{
-									CPU:      helper.IntToPtr(500),
-									MemoryMB: helper.IntToPtr(128),
-									IOPS:     helper.IntToPtr(30),
+									CPU:         helper.IntToPtr(500),
+									MemoryMB:    helper.IntToPtr(128),
+									MemoryMaxMB: helper.IntToPtr(256),
+									IOPS:        helper.IntToPtr(30),
 								},
 								Constraints: []*api.Constraint{
 									{
@@ -598,6 +601,150 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"log-sinks.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "exec",
+								LogConfig: &api.LogConfig{
+									MaxFiles: helper.IntToPtr(5),
+									Sinks: []*api.LogSink{
+										{
+											Type:    helper.StringToPtr("syslog"),
+											Address: helper.StringToPtr("udp://127.0.0.1:514"),
+											Tag:     helper.StringToPtr("web"),
+										},
+										{
+											Type:       helper.StringToPtr("http"),
+											Address:    helper.StringToPtr("https://logs.example.com/ingest"),
+											BatchSize:  helper.IntToPtr(500),
+											BatchWait:  helper.TimeToPtr(5 * time.Second),
+											BufferSize: helper.IntToPtr(20000),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"volumes.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Volumes: map[string]*api.VolumeRequest{
+							"data": {
+								Name:   "data",
+								Type:   "host",
+								Source: "shared_data",
+							},
+							"certs": {
+								Name:     "certs",
+								Type:     "host",
+								Source:   "certs",
+								ReadOnly: true,
+							},
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "baz",
+								Driver: "exec",
+								VolumeMounts: []*api.VolumeMount{
+									{
+										Volume:      "data",
+										Destination: "/srv/data",
+									},
+									{
+										Volume:      "certs",
+										Destination: "/etc/ssl/certs",
+										ReadOnly:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"csi-plugin.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("binstore-storagelocker"),
+				Name: helper.StringToPtr("binstore-storagelocker"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("binsl"),
+						Volumes: map[string]*api.VolumeRequest{
+							"data": {
+								Name:     "data",
+								Type:     "csi",
+								Source:   "ebs-vol0",
+								ReadOnly: true,
+							},
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "binstore",
+								Driver: "docker",
+								VolumeMounts: []*api.VolumeMount{
+									{
+										Volume:      "data",
+										Destination: "/srv/data",
+									},
+								},
+							},
+							{
+								Name:   "plugin",
+								Driver: "docker",
+								CSIPluginConfig: &api.TaskCSIPluginConfig{
+									ID:       "org.hashicorp.csi",
+									Type:     api.CSIPluginTypeMonolith,
+									MountDir: "/csi/test",
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"identity.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("example"),
+				Name: helper.StringToPtr("example"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("cache"),
+						Tasks: []*api.Task{
+							{
+								Name:   "redis",
+								Driver: "docker",
+								Identity: &api.WorkloadIdentity{
+									Env:  true,
+									File: true,
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"service-check-driver-address.hcl",
 			&api.Job{
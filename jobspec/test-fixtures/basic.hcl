@@ -90,8 +90,10 @@ job "binstore-storagelocker" {
       }
 
       logs {
-        max_files     = 14
-        max_file_size = 101
+        max_files       = 14
+        max_file_size   = 101
+        rotate_interval = "24h"
+        compress        = true
       }
 
       env {
@@ -204,9 +206,10 @@ job "binstore-storagelocker" {
       }
 
       resources {
-        cpu    = 500
-        memory = 128
-        iops   = 30
+        cpu        = 500
+        memory     = 128
+        memory_max = 256
+        iops       = 30
       }
 
       constraint {
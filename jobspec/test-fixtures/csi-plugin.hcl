@@ -0,0 +1,28 @@
+job "binstore-storagelocker" {
+  group "binsl" {
+    volume "data" {
+      type      = "csi"
+      source    = "ebs-vol0"
+      read_only = true
+    }
+
+    task "binstore" {
+      driver = "docker"
+
+      volume_mount {
+        volume      = "data"
+        destination = "/srv/data"
+      }
+    }
+
+    task "plugin" {
+      driver = "docker"
+
+      csi_plugin {
+        id        = "org.hashicorp.csi"
+        type      = "monolith"
+        mount_dir = "/csi/test"
+      }
+    }
+  }
+}
@@ -0,0 +1,12 @@
+job "example" {
+  group "cache" {
+    task "redis" {
+      driver = "docker"
+
+      identity {
+        env  = true
+        file = true
+      }
+    }
+  }
+}
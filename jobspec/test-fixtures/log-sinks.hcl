@@ -0,0 +1,21 @@
+job "foo" {
+  task "bar" {
+    driver = "exec"
+
+    logs {
+      max_files = 5
+
+      log_sink "syslog" {
+        address = "udp://127.0.0.1:514"
+        tag     = "web"
+      }
+
+      log_sink "http" {
+        address     = "https://logs.example.com/ingest"
+        batch_size  = 500
+        batch_wait  = "5s"
+        buffer_size = 20000
+      }
+    }
+  }
+}
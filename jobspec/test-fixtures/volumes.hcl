@@ -0,0 +1,29 @@
+job "foo" {
+  group "bar" {
+    volume "data" {
+      type   = "host"
+      source = "shared_data"
+    }
+
+    volume "certs" {
+      type      = "host"
+      source    = "certs"
+      read_only = true
+    }
+
+    task "baz" {
+      driver = "exec"
+
+      volume_mount {
+        volume      = "data"
+        destination = "/srv/data"
+      }
+
+      volume_mount {
+        volume      = "certs"
+        destination = "/etc/ssl/certs"
+        read_only   = true
+      }
+    }
+  }
+}
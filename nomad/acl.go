@@ -1,6 +1,8 @@
 package nomad
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -31,10 +33,80 @@ func (s *Server) ResolveToken(secretID string) (*acl.ACL, error) {
 		return nil, err
 	}
 
+	// Workload identities are JWTs rather than UUIDs
+	if strings.Count(secretID, ".") == 2 {
+		return s.resolveWorkloadIdentity(snap, secretID)
+	}
+
 	// Resolve the ACL
 	return resolveTokenFromSnapshotCache(snap, s.aclCache, secretID)
 }
 
+// resolveAccessorID returns the accessor ID of the token with the given
+// secret ID. An empty string is returned if ACLs are disabled or the token
+// can't be found.
+func (s *Server) resolveAccessorID(secretID string) string {
+	if !s.config.ACLEnabled {
+		return ""
+	}
+	if secretID == "" {
+		return structs.AnonymousACLToken.AccessorID
+	}
+
+	token, err := s.fsm.State().ACLTokenBySecretID(nil, secretID)
+	if err != nil || token == nil {
+		return ""
+	}
+	return token.AccessorID
+}
+
+// resolveWorkloadIdentity is used to translate the signed identity of a task
+// into an ACL object. The identity is only valid while its allocation is
+// running and grants read access to the job and its variables.
+func (s *Server) resolveWorkloadIdentity(snap *state.StateSnapshot, token string) (*acl.ACL, error) {
+	claims, err := s.encrypter.VerifyClaim(token)
+	if err != nil {
+		s.logger.Printf("[DEBUG] nomad: failed to verify workload identity: %v", err)
+		return nil, structs.ErrTokenNotFound
+	}
+
+	alloc, err := snap.AllocByID(nil, claims.AllocationID)
+	if err != nil {
+		return nil, err
+	}
+	if alloc == nil || alloc.TerminalStatus() ||
+		alloc.Namespace != claims.Namespace || alloc.JobID != claims.JobID {
+		return nil, structs.ErrTokenNotFound
+	}
+
+	policy := &structs.ACLPolicy{
+		Name:  fmt.Sprintf("_workload:%s:%s", claims.Namespace, claims.JobID),
+		Rules: workloadIdentityRules(claims.Namespace, claims.JobID),
+	}
+	policy.SetHash()
+	return structs.CompileACLObject(s.aclCache, []*structs.ACLPolicy{policy})
+}
+
+// workloadIdentityRules returns the ACL policy rules granted to the workload
+// identities of the given job. Only the variables of the job are granted, as
+// namespace capabilities would expose the other jobs of the namespace.
+func workloadIdentityRules(namespace, jobID string) string {
+	rules := fmt.Sprintf("namespace %q {\n", namespace)
+
+	// Glob characters in the job ID would grant access to the variables of
+	// other jobs
+	if !strings.Contains(jobID, "*") {
+		jobPath := structs.VariablePathForJob(jobID)
+		rules += "\tvariables {\n"
+		for _, path := range []string{jobPath, jobPath + "/*"} {
+			rules += fmt.Sprintf("\t\tpath %q {\n\t\t\tcapabilities = [%q, %q]\n\t\t}\n",
+				path, acl.VariablesCapabilityRead, acl.VariablesCapabilityList)
+		}
+		rules += "\t}\n"
+	}
+	return rules + "}\n"
+}
+
 // resolveTokenFromSnapshotCache is used to resolve an ACL object from a snapshot of state,
 // using a cache to avoid parsing and ACL construction when possible. It is split from resolveToken
 // to simplify testing.
@@ -54,6 +126,9 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		if token == nil {
 			return nil, structs.ErrTokenNotFound
 		}
+		if token.IsExpired(time.Now().UTC()) {
+			return nil, structs.ErrTokenExpired
+		}
 	}
 
 	// Check if this is a management token
@@ -61,9 +136,13 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		return acl.ManagementACL, nil
 	}
 
-	// Get all associated policies
-	policies := make([]*structs.ACLPolicy, 0, len(token.Policies))
-	for _, policyName := range token.Policies {
+	// Get all associated policies, including those granted by roles
+	policyNames, err := snap.ACLTokenPolicies(nil, token)
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]*structs.ACLPolicy, 0, len(policyNames))
+	for _, policyName := range policyNames {
 		policy, err := snap.ACLPolicyByName(nil, policyName)
 		if err != nil {
 			return nil, err
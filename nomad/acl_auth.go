@@ -0,0 +1,288 @@
+package nomad
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/oidc"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// oidcStateTTL is how long the user has to authenticate with the OIDC
+	// provider once the authorization URL is generated
+	oidcStateTTL = 10 * time.Minute
+
+	// authProviderTimeout bounds the requests made to identity providers
+	authProviderTimeout = 10 * time.Second
+
+	// maxAuthTokenSubjectLength bounds the length of the subject included in
+	// the name of the tokens minted by auth methods
+	maxAuthTokenSubjectLength = 128
+)
+
+// authProviderClient is the HTTP client used to reach identity providers
+var authProviderClient = &http.Client{Timeout: authProviderTimeout}
+
+// oidcState is round tripped through the OIDC provider as the state parameter
+// of the authorization code flow. It is encrypted with the keyring so that
+// any server of the region can complete the authentication without storing
+// pending requests.
+type oidcState struct {
+	AuthMethod  string
+	RedirectURI string
+	ClientNonce string
+	Nonce       string
+	ExpiresAt   time.Time
+}
+
+// encodeOIDCState encrypts the state with the active root key
+func (s *Server) encodeOIDCState(st *oidcState) (string, error) {
+	buf, err := json.Marshal(st)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, keyID, err := s.encrypter.Encrypt(buf)
+	if err != nil {
+		return "", err
+	}
+	return keyID + "." + base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+// decodeOIDCState decrypts the state and checks that it hasn't expired
+func (s *Server) decodeOIDCState(raw string) (*oidcState, error) {
+	parts := strings.SplitN(raw, ".", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("malformed state")
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed state: %v", err)
+	}
+	buf, err := s.encrypter.Decrypt(ciphertext, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt state: %v", err)
+	}
+
+	var st oidcState
+	if err := json.Unmarshal(buf, &st); err != nil {
+		return nil, fmt.Errorf("malformed state: %v", err)
+	}
+	if time.Now().After(st.ExpiresAt) {
+		return nil, fmt.Errorf("state expired")
+	}
+	return &st, nil
+}
+
+// validate checks that the state was issued for the completion request
+func (st *oidcState) validate(args *structs.ACLOIDCCompleteAuthRequest) error {
+	if st.AuthMethod != args.AuthMethodName {
+		return fmt.Errorf("state issued for auth method %q", st.AuthMethod)
+	}
+	if st.RedirectURI != args.RedirectURI {
+		return fmt.Errorf("redirect URI mismatch")
+	}
+	if subtle.ConstantTimeCompare([]byte(st.ClientNonce), []byte(args.ClientNonce)) != 1 {
+		return fmt.Errorf("client nonce mismatch")
+	}
+	return nil
+}
+
+// authProvider is the identity provider of an auth method. It is cached so
+// that logins don't rediscover the provider and refetch its keys.
+type authProvider struct {
+	// modifyIndex is the index of the auth method the provider was built for
+	modifyIndex uint64
+
+	// provider is the discovered OIDC provider. It is nil for JWT auth
+	// methods configured with static keys or a JWKS URL.
+	provider *oidc.Provider
+
+	// keys is the key set validating the tokens of the auth method
+	keys oidc.KeySet
+
+	// issuer is the issuer of the discovered provider if any
+	issuer string
+}
+
+// authProviderCache caches the identity provider of each auth method. Entries
+// are invalidated when the auth method is modified or deleted.
+type authProviderCache struct {
+	providers map[string]*authProvider
+	l         sync.Mutex
+}
+
+// newAuthProviderCache returns an empty auth provider cache
+func newAuthProviderCache() *authProviderCache {
+	return &authProviderCache{
+		providers: make(map[string]*authProvider),
+	}
+}
+
+// get returns the identity provider of the auth method, building it if it
+// isn't cached or the auth method was modified since it was cached.
+func (c *authProviderCache) get(ctx context.Context, method *structs.ACLAuthMethod) (*authProvider, error) {
+	c.l.Lock()
+	p, ok := c.providers[method.Name]
+	c.l.Unlock()
+	if ok && p.modifyIndex == method.ModifyIndex {
+		return p, nil
+	}
+
+	// Build the provider without holding the lock since discovery makes
+	// requests to the provider
+	p, err := newAuthProvider(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+
+	c.l.Lock()
+	defer c.l.Unlock()
+	if existing, ok := c.providers[method.Name]; ok && existing.modifyIndex > p.modifyIndex {
+		return p, nil
+	}
+	c.providers[method.Name] = p
+	return p, nil
+}
+
+// invalidate removes the cached identity providers of the auth methods
+func (c *authProviderCache) invalidate(names ...string) {
+	c.l.Lock()
+	defer c.l.Unlock()
+	for _, name := range names {
+		delete(c.providers, name)
+	}
+}
+
+// newAuthProvider returns the identity provider of the auth method,
+// discovering it if the auth method doesn't configure its keys.
+func newAuthProvider(ctx context.Context, method *structs.ACLAuthMethod) (*authProvider, error) {
+	p := &authProvider{modifyIndex: method.ModifyIndex}
+
+	c := method.Config
+	switch {
+	case method.Type == structs.ACLAuthMethodTypeJWT && len(c.JWTValidationPubKeys) != 0:
+		keys, err := oidc.NewStaticKeySet(c.JWTValidationPubKeys)
+		if err != nil {
+			return nil, err
+		}
+		p.keys = keys
+	case method.Type == structs.ACLAuthMethodTypeJWT && c.JWKSURL != "":
+		p.keys = oidc.NewRemoteKeySet(authProviderClient, c.JWKSURL)
+	default:
+		provider, err := oidc.Discover(ctx, authProviderClient, c.OIDCDiscoveryURL)
+		if err != nil {
+			return nil, err
+		}
+		p.provider = provider
+		p.keys = provider.KeySet()
+		p.issuer = provider.Issuer
+	}
+	return p, nil
+}
+
+// verifyAuthToken verifies the signature of the token issued to the identity
+// and validates its claims against the auth method's configuration.
+func verifyAuthToken(ctx context.Context, method *structs.ACLAuthMethod, keys oidc.KeySet, issuer, nonce, token string) (map[string]interface{}, error) {
+	claims, err := oidc.Verify(ctx, keys, token)
+	if err != nil {
+		return nil, err
+	}
+
+	c := method.Config
+	expected := &oidc.Expected{
+		Issuer:    issuer,
+		Audiences: c.BoundAudiences,
+		Nonce:     nonce,
+		Leeway:    c.ClockSkewLeeway,
+	}
+	if c.BoundIssuer != "" {
+		expected.Issuer = c.BoundIssuer
+	}
+	if len(expected.Audiences) == 0 && method.Type == structs.ACLAuthMethodTypeOIDC {
+		expected.Audiences = []string{c.OIDCClientID}
+	}
+	if expected.Leeway == 0 {
+		expected.Leeway = oidc.DefaultLeeway
+	}
+	if err := expected.Validate(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// bindAuthIdentity evaluates the binding rules of the auth method against the
+// claims of the identity and returns the policies and roles granted. Bindings
+// to policies or roles that don't exist are skipped.
+func bindAuthIdentity(snap *state.StateSnapshot, method *structs.ACLAuthMethod, claims map[string]interface{}) ([]string, []string, error) {
+	values, lists, err := oidc.MapClaims(claims, method.Config.ClaimMappings, method.Config.ListClaimMappings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	iter, err := snap.ACLBindingRulesByAuthMethod(nil, method.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var policies, roles []string
+	seen := make(map[string]struct{})
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		rule := raw.(*structs.ACLBindingRule)
+
+		selector, err := oidc.ParseSelector(rule.Selector)
+		if err != nil || !selector.Match(values, lists) {
+			continue
+		}
+		name, err := oidc.InterpolateValues(rule.BindName, values)
+		if err != nil {
+			continue
+		}
+
+		key := rule.BindType + "/" + name
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		switch rule.BindType {
+		case structs.ACLBindingRuleBindTypePolicy:
+			if policy, err := snap.ACLPolicyByName(nil, name); err != nil {
+				return nil, nil, err
+			} else if policy != nil {
+				policies = append(policies, name)
+			}
+		case structs.ACLBindingRuleBindTypeRole:
+			if role, err := snap.ACLRoleByName(nil, name); err != nil {
+				return nil, nil, err
+			} else if role != nil {
+				roles = append(roles, name)
+			}
+		}
+	}
+	return policies, roles, nil
+}
+
+// authTokenName returns the name of the token minted for the identity
+func authTokenName(method *structs.ACLAuthMethod, claims map[string]interface{}) string {
+	sub, _ := claims["sub"].(string)
+	if len(sub) > maxAuthTokenSubjectLength {
+		sub = sub[:maxAuthTokenSubjectLength]
+	}
+	if sub == "" {
+		return fmt.Sprintf("%s login", method.Name)
+	}
+	return fmt.Sprintf("%s login: %s", method.Name, sub)
+}
@@ -0,0 +1,179 @@
+package nomad
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/oidc/oidctest"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindAuthIdentity(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s := state.TestStateStore(t)
+
+	method := mock.ACLAuthMethod()
+	policy := mock.ACLPolicy()
+	policy.Name = "engineering"
+	role := mock.ACLRole()
+	role.Name = "user-alice"
+
+	rules := []*structs.ACLBindingRule{
+		// Matches and binds an existing policy
+		{ID: "2d9a4f22-cc64-4a1f-a2e5-3ab3c9d3e7a1", AuthMethod: method.Name,
+			Selector: `"engineering" in list.groups`, BindType: structs.ACLBindingRuleBindTypePolicy, BindName: "engineering"},
+		// Matches and binds an interpolated role
+		{ID: "3f5b6b5a-d4c3-4a5b-8d7e-0c1d2e3f4a5b", AuthMethod: method.Name,
+			BindType: structs.ACLBindingRuleBindTypeRole, BindName: "user-${value.user}"},
+		// Matches but the policy doesn't exist
+		{ID: "4a6c7d8e-9f0a-4b1c-8d2e-3f4a5b6c7d8e", AuthMethod: method.Name,
+			BindType: structs.ACLBindingRuleBindTypePolicy, BindName: "missing"},
+		// Doesn't match
+		{ID: "5b7d8e9f-0a1b-4c2d-9e3f-4a5b6c7d8e9f", AuthMethod: method.Name,
+			Selector: `"sales" in list.groups`, BindType: structs.ACLBindingRuleBindTypePolicy, BindName: "engineering"},
+		// References a missing value
+		{ID: "6c8e9f0a-1b2c-4d3e-8f4a-5b6c7d8e9f0a", AuthMethod: method.Name,
+			BindType: structs.ACLBindingRuleBindTypeRole, BindName: "team-${value.team}"},
+		// Belongs to another auth method
+		{ID: "7d9f0a1b-2c3d-4e4f-9a5b-6c7d8e9f0a1b", AuthMethod: "other",
+			BindType: structs.ACLBindingRuleBindTypePolicy, BindName: "other"},
+	}
+	require.NoError(s.UpsertACLPolicies(100, []*structs.ACLPolicy{policy}))
+	require.NoError(s.UpsertACLRoles(101, []*structs.ACLRole{role}))
+	require.NoError(s.UpsertACLBindingRules(102, rules))
+
+	snap, err := s.Snapshot()
+	require.NoError(err)
+
+	claims := map[string]interface{}{
+		"sub":    "alice",
+		"groups": []interface{}{"engineering"},
+	}
+	policies, roles, err := bindAuthIdentity(snap, method, claims)
+	require.NoError(err)
+	require.Equal([]string{"engineering"}, policies)
+	require.Equal([]string{"user-alice"}, roles)
+
+	// Claims that can't be mapped are an error
+	claims["sub"] = map[string]interface{}{"name": "alice"}
+	_, _, err = bindAuthIdentity(snap, method, claims)
+	require.Error(err)
+}
+
+func TestAuthProviderCache(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	provider := oidctest.NewProvider(t)
+	defer provider.Close()
+
+	method := mock.ACLAuthMethod()
+	method.Config.OIDCDiscoveryURL = provider.Issuer()
+	cache := newAuthProviderCache()
+	ctx := context.Background()
+
+	// The provider is discovered once
+	p1, err := cache.get(ctx, method)
+	require.NoError(err)
+	require.Equal(provider.Issuer(), p1.issuer)
+	p2, err := cache.get(ctx, method)
+	require.NoError(err)
+	require.True(p1 == p2)
+	require.Equal(1, provider.Discoveries())
+
+	// Modifying the auth method rediscovers the provider
+	method.ModifyIndex++
+	p3, err := cache.get(ctx, method)
+	require.NoError(err)
+	require.False(p1 == p3)
+	require.Equal(2, provider.Discoveries())
+
+	// Invalidating the auth method rediscovers the provider
+	cache.invalidate(method.Name)
+	_, err = cache.get(ctx, method)
+	require.NoError(err)
+	require.Equal(3, provider.Discoveries())
+}
+
+func TestServer_OIDCState(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	st := &oidcState{
+		AuthMethod:  "okta",
+		RedirectURI: "http://localhost:4649/oidc/callback",
+		ClientNonce: "client-nonce",
+		Nonce:       "nonce",
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+	encoded, err := s1.encodeOIDCState(st)
+	assert.Nil(t, err)
+	assert.NotContains(t, encoded, "client-nonce")
+
+	decoded, err := s1.decodeOIDCState(encoded)
+	assert.Nil(t, err)
+	assert.Equal(t, st.Nonce, decoded.Nonce)
+
+	req := &structs.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: "okta",
+		ClientNonce:    "client-nonce",
+		RedirectURI:    "http://localhost:4649/oidc/callback",
+	}
+	assert.Nil(t, decoded.validate(req))
+
+	req.ClientNonce = "other"
+	assert.NotNil(t, decoded.validate(req))
+
+	// Tampered states are rejected
+	_, err = s1.decodeOIDCState(encoded[:len(encoded)-4] + "AAAA")
+	assert.NotNil(t, err)
+
+	// Expired states are rejected
+	st.ExpiresAt = time.Now().Add(-time.Minute)
+	encoded, err = s1.encodeOIDCState(st)
+	assert.Nil(t, err)
+	_, err = s1.decodeOIDCState(encoded)
+	assert.NotNil(t, err)
+}
+
+func TestVerifyAuthToken_WorkloadIdentity(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	token, err := s1.encrypter.SignClaims(structs.NewIdentityClaims(mock.Alloc(), "web"))
+	require.NoError(err)
+
+	// Build a JWT auth method trusting the public key of the active root key
+	meta, err := s1.fsm.State().GetActiveRootKeyMeta(nil)
+	require.NoError(err)
+	pub, err := s1.encrypter.PublicKey(meta)
+	require.NoError(err)
+	der, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(pub.PublicKey))
+	require.NoError(err)
+	method := mock.ACLAuthMethod()
+	method.Type = structs.ACLAuthMethodTypeJWT
+	method.Config.JWTValidationPubKeys = []string{
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}
+	method.Config.BoundAudiences = []string{structs.IdentityDefaultAudience}
+
+	p, err := newAuthProvider(context.Background(), method)
+	require.NoError(err)
+	claims, err := verifyAuthToken(context.Background(), method, p.keys, p.issuer, "", token)
+	require.NoError(err)
+	require.Equal("web", claims["nomad_task"])
+}
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -10,6 +11,7 @@ import (
 
 	metrics "github.com/armon/go-metrics"
 	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -146,8 +148,12 @@ func (a *ACL) ListPolicies(args *structs.ACLPolicyListRequest, reply *structs.AC
 			return structs.ErrTokenNotFound
 		}
 
-		policies = make(map[string]struct{}, len(token.Policies))
-		for _, p := range token.Policies {
+		names, err := snap.ACLTokenPolicies(nil, token)
+		if err != nil {
+			return err
+		}
+		policies = make(map[string]struct{}, len(names))
+		for _, p := range names {
 			policies[p] = struct{}{}
 		}
 	}
@@ -157,33 +163,904 @@ func (a *ACL) ListPolicies(args *structs.ACLPolicyListRequest, reply *structs.AC
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, state *state.StateStore) error {
-			// Iterate over all the policies
+			// Iterate over all the policies
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.ACLPolicyByNamePrefix(ws, prefix)
+			} else {
+				iter, err = state.ACLPolicies(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Convert all the policies to a list stub
+			reply.Policies = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				policy := raw.(*structs.ACLPolicy)
+				if _, ok := policies[policy.Name]; ok || mgt {
+					reply.Policies = append(reply.Policies, policy.Stub())
+				}
+			}
+
+			// Use the last index that affected the policy table
+			index, err := state.Index("acl_policy")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetPolicy is used to get a specific policy
+func (a *ACL) GetPolicy(args *structs.ACLPolicySpecificRequest, reply *structs.SingleACLPolicyResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetPolicy", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_policy"}, time.Now())
+
+	// Check management level permissions
+	acl, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if acl == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// If it is not a management token determine if it can get this policy
+	mgt := acl.IsManagement()
+	if !mgt {
+		snap, err := a.srv.fsm.State().Snapshot()
+		if err != nil {
+			return err
+		}
+
+		token, err := snap.ACLTokenBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return structs.ErrTokenNotFound
+		}
+
+		names, err := snap.ACLTokenPolicies(nil, token)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, p := range names {
+			if p == args.Name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the policy
+			out, err := state.ACLPolicyByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Policy = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the policy table
+				index, err := state.Index("acl_policy")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetPolicies is used to get a set of policies
+func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLPolicySetResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetPolicies", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_policies"}, time.Now())
+
+	var token *structs.ACLToken
+	var err error
+	if args.AuthToken == "" {
+		// No need to look up the anonymous token
+		token = structs.AnonymousACLToken
+	} else {
+		// For client typed tokens, allow them to query any policies associated with that token.
+		// This is used by clients which are resolving the policies to enforce. Any associated
+		// policies need to be fetched so that the client can determine what to allow.
+		token, err = a.srv.State().ACLTokenBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	if token == nil {
+		return structs.ErrTokenNotFound
+	}
+	if token.IsExpired(time.Now().UTC()) {
+		return structs.ErrTokenExpired
+	}
+	if token.Type != structs.ACLManagementToken && !token.PolicySubset(args.Names) {
+		// The policies may be granted through the roles of the token
+		names, err := a.srv.State().ACLTokenPolicies(nil, token)
+		if err != nil {
+			return err
+		}
+		expanded := &structs.ACLToken{Type: token.Type, Policies: names}
+		if !expanded.PolicySubset(args.Names) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Setup the output
+			reply.Policies = make(map[string]*structs.ACLPolicy, len(args.Names))
+
+			// Look for the policy
+			for _, policyName := range args.Names {
+				out, err := state.ACLPolicyByName(ws, policyName)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.Policies[policyName] = out
+				}
+			}
+
+			// Use the last index that affected the policy table
+			index, err := state.Index("acl_policy")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// UpsertRoles is used to create or update a set of roles
+func (a *ACL) UpsertRoles(args *structs.ACLRoleUpsertRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles
+	if len(args.Roles) == 0 {
+		return fmt.Errorf("must specify as least one role")
+	}
+
+	// Validate each role, compute hash
+	for idx, role := range args.Roles {
+		if err := role.Validate(); err != nil {
+			return fmt.Errorf("role %d invalid: %v", idx, err)
+		}
+		role.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLRoleUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteRoles is used to delete roles
+func (a *ACL) DeleteRoles(args *structs.ACLRoleDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeleteRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify as least one role")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLRoleDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListRoles is used to list the roles
+func (a *ACL) ListRoles(args *structs.ACLRoleListRequest, reply *structs.ACLRoleListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.ListRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_roles"}, time.Now())
+
+	// Check management level permissions
+	acl, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if acl == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// If it is not a management token determine the roles that may be listed
+	mgt := acl.IsManagement()
+	var roles map[string]struct{}
+	if !mgt {
+		token, err := a.srv.State().ACLTokenBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return structs.ErrTokenNotFound
+		}
+
+		roles = make(map[string]struct{}, len(token.Roles))
+		for _, r := range token.Roles {
+			roles[r] = struct{}{}
+		}
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the roles
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.ACLRoleByNamePrefix(ws, prefix)
+			} else {
+				iter, err = state.ACLRoles(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Convert all the roles to a list stub
+			reply.Roles = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				role := raw.(*structs.ACLRole)
+				if _, ok := roles[role.Name]; ok || mgt {
+					reply.Roles = append(reply.Roles, role.Stub())
+				}
+			}
+
+			// Use the last index that affected the role table
+			index, err := state.Index("acl_role")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetRole is used to get a specific role
+func (a *ACL) GetRole(args *structs.ACLRoleSpecificRequest, reply *structs.SingleACLRoleResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetRole", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_role"}, time.Now())
+
+	// Check management level permissions
+	acl, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if acl == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// If it is not a management token determine if it can get this role
+	if !acl.IsManagement() {
+		token, err := a.srv.State().ACLTokenBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return structs.ErrTokenNotFound
+		}
+		if !token.RoleSubset([]string{args.Name}) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the role
+			out, err := state.ACLRoleByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Role = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the role table
+				index, err := state.Index("acl_role")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetRoles is used to get a set of roles
+func (a *ACL) GetRoles(args *structs.ACLRoleSetRequest, reply *structs.ACLRoleSetResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_roles"}, time.Now())
+
+	// For client typed tokens, allow them to query any roles associated with
+	// that token. This is used by clients which are resolving the policies to
+	// enforce.
+	var token *structs.ACLToken
+	var err error
+	if args.AuthToken == "" {
+		token = structs.AnonymousACLToken
+	} else {
+		token, err = a.srv.State().ACLTokenBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+	}
+	if token == nil {
+		return structs.ErrTokenNotFound
+	}
+	if token.IsExpired(time.Now().UTC()) {
+		return structs.ErrTokenExpired
+	}
+	if !token.RoleSubset(args.Names) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Setup the output
+			reply.Roles = make(map[string]*structs.ACLRole, len(args.Names))
+
+			// Look for the role
+			for _, roleName := range args.Names {
+				out, err := state.ACLRoleByName(ws, roleName)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.Roles[roleName] = out
+				}
+			}
+
+			// Use the last index that affected the role table
+			index, err := state.Index("acl_role")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// UpsertAuthMethods is used to create or update a set of auth methods
+func (a *ACL) UpsertAuthMethods(args *structs.ACLAuthMethodUpsertRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods
+	if len(args.AuthMethods) == 0 {
+		return fmt.Errorf("must specify as least one auth method")
+	}
+
+	// Validate each auth method, compute hash
+	defaultMethod := ""
+	for idx, method := range args.AuthMethods {
+		method.Canonicalize()
+		if err := method.Validate(); err != nil {
+			return fmt.Errorf("auth method %d invalid: %v", idx, err)
+		}
+		if method.Default {
+			if defaultMethod != "" {
+				return fmt.Errorf("only one auth method can be the default")
+			}
+			defaultMethod = method.Name
+		}
+		method.SetHash()
+	}
+
+	// Ensure there is at most one default auth method
+	if defaultMethod != "" {
+		existing, err := a.srv.State().ACLDefaultAuthMethod(nil)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.Name != defaultMethod {
+			return fmt.Errorf("default auth method already exists: %s", existing.Name)
+		}
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLAuthMethodUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteAuthMethods is used to delete auth methods, along with their binding
+// rules
+func (a *ACL) DeleteAuthMethods(args *structs.ACLAuthMethodDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeleteAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify as least one auth method")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLAuthMethodDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListAuthMethods is used to list the auth methods. Listing doesn't require
+// a token since it is used to find the auth method to login with.
+func (a *ACL) ListAuthMethods(args *structs.ACLAuthMethodListRequest, reply *structs.ACLAuthMethodListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.ListAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_auth_methods"}, time.Now())
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the auth methods
+			iter, err := state.ACLAuthMethods(ws)
+			if err != nil {
+				return err
+			}
+
+			// Convert all the auth methods to a list stub
+			reply.AuthMethods = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				method := raw.(*structs.ACLAuthMethod)
+				if strings.HasPrefix(method.Name, args.QueryOptions.Prefix) {
+					reply.AuthMethods = append(reply.AuthMethods, method.Stub())
+				}
+			}
+
+			// Use the last index that affected the auth method table
+			index, err := state.Index("acl_auth_method")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetAuthMethod is used to get a specific auth method
+func (a *ACL) GetAuthMethod(args *structs.ACLAuthMethodSpecificRequest, reply *structs.SingleACLAuthMethodResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetAuthMethod", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_method"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the auth method
+			out, err := state.ACLAuthMethodByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.AuthMethod = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the auth method table
+				index, err := state.Index("acl_auth_method")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetAuthMethods is used to get a set of auth methods
+func (a *ACL) GetAuthMethods(args *structs.ACLAuthMethodSetRequest, reply *structs.ACLAuthMethodSetResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Setup the output
+			reply.AuthMethods = make(map[string]*structs.ACLAuthMethod, len(args.Names))
+
+			// Look for the auth method
+			for _, name := range args.Names {
+				out, err := state.ACLAuthMethodByName(ws, name)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.AuthMethods[name] = out
+				}
+			}
+
+			// Use the last index that affected the auth method table
+			index, err := state.Index("acl_auth_method")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// UpsertBindingRules is used to create or update a set of binding rules
+func (a *ACL) UpsertBindingRules(args *structs.ACLBindingRuleUpsertRequest, reply *structs.ACLBindingRuleUpsertResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of binding rules
+	if len(args.BindingRules) == 0 {
+		return fmt.Errorf("must specify as least one binding rule")
+	}
+
+	// Snapshot the state
+	state, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Validate each binding rule, compute hash
+	for idx, rule := range args.BindingRules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("binding rule %d invalid: %v", idx, err)
+		}
+
+		// Verify the auth method exists
+		method, err := state.ACLAuthMethodByName(nil, rule.AuthMethod)
+		if err != nil {
+			return fmt.Errorf("auth method lookup failed: %v", err)
+		}
+		if method == nil {
+			return fmt.Errorf("binding rule %d invalid: cannot find auth method %s", idx, rule.AuthMethod)
+		}
+
+		// Generate an ID if new, otherwise verify the rule exists
+		if rule.ID == "" {
+			rule.ID = uuid.Generate()
+		} else {
+			out, err := state.ACLBindingRuleByID(nil, rule.ID)
+			if err != nil {
+				return fmt.Errorf("binding rule lookup failed: %v", err)
+			}
+			if out == nil {
+				return fmt.Errorf("cannot find binding rule %s", rule.ID)
+			}
+		}
+		rule.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLBindingRuleUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to
+	// pickup the proper create / modify indexes.
+	state, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, rule := range args.BindingRules {
+		out, err := state.ACLBindingRuleByID(nil, rule.ID)
+		if err != nil {
+			return fmt.Errorf("binding rule lookup failed: %v", err)
+		}
+		reply.BindingRules = append(reply.BindingRules, out)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteBindingRules is used to delete binding rules
+func (a *ACL) DeleteBindingRules(args *structs.ACLBindingRuleDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeleteBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of binding rules
+	if len(args.IDs) == 0 {
+		return fmt.Errorf("must specify as least one binding rule")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLBindingRuleDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListBindingRules is used to list the binding rules
+func (a *ACL) ListBindingRules(args *structs.ACLBindingRuleListRequest, reply *structs.ACLBindingRuleListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.ListBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over the binding rules
 			var err error
 			var iter memdb.ResultIterator
-			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.ACLPolicyByNamePrefix(ws, prefix)
+			if args.AuthMethod != "" {
+				iter, err = state.ACLBindingRulesByAuthMethod(ws, args.AuthMethod)
+			} else if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.ACLBindingRuleByIDPrefix(ws, prefix)
 			} else {
-				iter, err = state.ACLPolicies(ws)
+				iter, err = state.ACLBindingRules(ws)
 			}
 			if err != nil {
 				return err
 			}
 
-			// Convert all the policies to a list stub
-			reply.Policies = nil
+			// Convert all the binding rules to a list stub
+			reply.BindingRules = nil
 			for {
 				raw := iter.Next()
 				if raw == nil {
 					break
 				}
-				policy := raw.(*structs.ACLPolicy)
-				if _, ok := policies[policy.Name]; ok || mgt {
-					reply.Policies = append(reply.Policies, policy.Stub())
+				rule := raw.(*structs.ACLBindingRule)
+				if strings.HasPrefix(rule.ID, args.QueryOptions.Prefix) {
+					reply.BindingRules = append(reply.BindingRules, rule.Stub())
 				}
 			}
 
-			// Use the last index that affected the policy table
-			index, err := state.Index("acl_policy")
+			// Use the last index that affected the binding rule table
+			index, err := state.Index("acl_binding_rule")
 			if err != nil {
 				return err
 			}
@@ -199,71 +1076,41 @@ func (a *ACL) ListPolicies(args *structs.ACLPolicyListRequest, reply *structs.AC
 	return a.srv.blockingRPC(&opts)
 }
 
-// GetPolicy is used to get a specific policy
-func (a *ACL) GetPolicy(args *structs.ACLPolicySpecificRequest, reply *structs.SingleACLPolicyResponse) error {
+// GetBindingRule is used to get a specific binding rule
+func (a *ACL) GetBindingRule(args *structs.ACLBindingRuleSpecificRequest, reply *structs.SingleACLBindingRuleResponse) error {
 	if !a.srv.config.ACLEnabled {
 		return aclDisabled
 	}
-	if done, err := a.srv.forward("ACL.GetPolicy", args, args, reply); done {
+	if done, err := a.srv.forward("ACL.GetBindingRule", args, args, reply); done {
 		return err
 	}
-	defer metrics.MeasureSince([]string{"nomad", "acl", "get_policy"}, time.Now())
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rule"}, time.Now())
 
 	// Check management level permissions
-	acl, err := a.srv.ResolveToken(args.AuthToken)
-	if err != nil {
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if acl == nil {
+	} else if acl == nil || !acl.IsManagement() {
 		return structs.ErrPermissionDenied
 	}
 
-	// If it is not a management token determine if it can get this policy
-	mgt := acl.IsManagement()
-	if !mgt {
-		snap, err := a.srv.fsm.State().Snapshot()
-		if err != nil {
-			return err
-		}
-
-		token, err := snap.ACLTokenBySecretID(nil, args.AuthToken)
-		if err != nil {
-			return err
-		}
-		if token == nil {
-			return structs.ErrTokenNotFound
-		}
-
-		found := false
-		for _, p := range token.Policies {
-			if p == args.Name {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			return structs.ErrPermissionDenied
-		}
-	}
-
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, state *state.StateStore) error {
-			// Look for the policy
-			out, err := state.ACLPolicyByName(ws, args.Name)
+			// Look for the binding rule
+			out, err := state.ACLBindingRuleByID(ws, args.ID)
 			if err != nil {
 				return err
 			}
 
 			// Setup the output
-			reply.Policy = out
+			reply.BindingRule = out
 			if out != nil {
 				reply.Index = out.ModifyIndex
 			} else {
-				// Use the last index that affected the policy table
-				index, err := state.Index("acl_policy")
+				// Use the last index that affected the binding rule table
+				index, err := state.Index("acl_binding_rule")
 				if err != nil {
 					return err
 				}
@@ -274,35 +1121,20 @@ func (a *ACL) GetPolicy(args *structs.ACLPolicySpecificRequest, reply *structs.S
 	return a.srv.blockingRPC(&opts)
 }
 
-// GetPolicies is used to get a set of policies
-func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLPolicySetResponse) error {
+// GetBindingRules is used to get a set of binding rules
+func (a *ACL) GetBindingRules(args *structs.ACLBindingRuleSetRequest, reply *structs.ACLBindingRuleSetResponse) error {
 	if !a.srv.config.ACLEnabled {
 		return aclDisabled
 	}
-	if done, err := a.srv.forward("ACL.GetPolicies", args, args, reply); done {
+	if done, err := a.srv.forward("ACL.GetBindingRules", args, args, reply); done {
 		return err
 	}
-	defer metrics.MeasureSince([]string{"nomad", "acl", "get_policies"}, time.Now())
-
-	var token *structs.ACLToken
-	var err error
-	if args.AuthToken == "" {
-		// No need to look up the anonymous token
-		token = structs.AnonymousACLToken
-	} else {
-		// For client typed tokens, allow them to query any policies associated with that token.
-		// This is used by clients which are resolving the policies to enforce. Any associated
-		// policies need to be fetched so that the client can determine what to allow.
-		token, err = a.srv.State().ACLTokenBySecretID(nil, args.AuthToken)
-		if err != nil {
-			return err
-		}
-	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rules"}, time.Now())
 
-	if token == nil {
-		return structs.ErrTokenNotFound
-	}
-	if token.Type != structs.ACLManagementToken && !token.PolicySubset(args.Names) {
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -312,21 +1144,21 @@ func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLP
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, state *state.StateStore) error {
 			// Setup the output
-			reply.Policies = make(map[string]*structs.ACLPolicy, len(args.Names))
+			reply.BindingRules = make(map[string]*structs.ACLBindingRule, len(args.IDs))
 
-			// Look for the policy
-			for _, policyName := range args.Names {
-				out, err := state.ACLPolicyByName(ws, policyName)
+			// Look for the binding rule
+			for _, id := range args.IDs {
+				out, err := state.ACLBindingRuleByID(ws, id)
 				if err != nil {
 					return err
 				}
 				if out != nil {
-					reply.Policies[policyName] = out
+					reply.BindingRules[id] = out
 				}
 			}
 
-			// Use the last index that affected the policy table
-			index, err := state.Index("acl_policy")
+			// Use the last index that affected the binding rule table
+			index, err := state.Index("acl_binding_rule")
 			if err != nil {
 				return err
 			}
@@ -336,6 +1168,228 @@ func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLP
 	return a.srv.blockingRPC(&opts)
 }
 
+// OIDCAuthURL is used to start the OIDC authorization code flow. It returns
+// the URL at which the user authenticates with the OIDC provider.
+func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *structs.ACLOIDCAuthURLResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.OIDCAuthURL", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_auth_url"}, time.Now())
+
+	method, err := a.authMethod(args.AuthMethodName, structs.ACLAuthMethodTypeOIDC)
+	if err != nil {
+		return err
+	}
+	args.AuthMethodName = method.Name
+
+	// Global tokens are minted by the authoritative region, which must also
+	// issue the state to complete the authentication
+	if done, err := a.forwardGlobalAuthMethod(method, "ACL.OIDCAuthURL", &args.WriteRequest, args, reply); done {
+		return err
+	}
+
+	if args.ClientNonce == "" {
+		return fmt.Errorf("missing client nonce")
+	}
+	if _, ok := helper.SliceStringToSet(method.Config.AllowedRedirectURIs)[args.RedirectURI]; !ok {
+		return fmt.Errorf("redirect URI %q is not allowed by auth method %q", args.RedirectURI, method.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), authProviderTimeout)
+	defer cancel()
+	p, err := a.srv.authProviders.get(ctx, method)
+	if err != nil {
+		return err
+	}
+
+	nonce := uuid.Generate()
+	state, err := a.srv.encodeOIDCState(&oidcState{
+		AuthMethod:  method.Name,
+		RedirectURI: args.RedirectURI,
+		ClientNonce: args.ClientNonce,
+		Nonce:       nonce,
+		ExpiresAt:   time.Now().Add(oidcStateTTL),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %v", err)
+	}
+
+	reply.AuthURL = p.provider.AuthCodeURL(method.Config.OIDCClientID, args.RedirectURI,
+		state, nonce, method.Config.OIDCScopes)
+	return nil
+}
+
+// OIDCCompleteAuth is used to complete the OIDC authorization code flow. It
+// exchanges the authorization code for the identity of the user and mints an
+// ACL token granting the policies and roles bound to the identity.
+func (a *ACL) OIDCCompleteAuth(args *structs.ACLOIDCCompleteAuthRequest, reply *structs.ACLLoginResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.OIDCCompleteAuth", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_complete_auth"}, time.Now())
+
+	method, err := a.authMethod(args.AuthMethodName, structs.ACLAuthMethodTypeOIDC)
+	if err != nil {
+		return err
+	}
+	args.AuthMethodName = method.Name
+
+	if done, err := a.forwardGlobalAuthMethod(method, "ACL.OIDCCompleteAuth", &args.WriteRequest, args, reply); done {
+		return err
+	}
+
+	// Verify the state was issued for this request
+	st, err := a.srv.decodeOIDCState(args.State)
+	if err == nil {
+		err = st.validate(args)
+	}
+	if err != nil {
+		a.srv.logger.Printf("[DEBUG] nomad.acl: invalid OIDC state for auth method %q: %v", method.Name, err)
+		return structs.ErrPermissionDenied
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), authProviderTimeout)
+	defer cancel()
+	p, err := a.srv.authProviders.get(ctx, method)
+	if err != nil {
+		return err
+	}
+	idToken, err := p.provider.Exchange(ctx, method.Config.OIDCClientID, method.Config.OIDCClientSecret,
+		args.Code, args.RedirectURI)
+	if err != nil {
+		a.srv.logger.Printf("[DEBUG] nomad.acl: OIDC auth method %q failed to exchange code: %v", method.Name, err)
+		return structs.ErrPermissionDenied
+	}
+	claims, err := verifyAuthToken(ctx, method, p.keys, p.issuer, st.Nonce, idToken)
+	if err != nil {
+		a.srv.logger.Printf("[DEBUG] nomad.acl: OIDC auth method %q rejected ID token: %v", method.Name, err)
+		return structs.ErrPermissionDenied
+	}
+
+	return a.mintAuthToken(method, claims, reply)
+}
+
+// Login is used to exchange a JWT issued by a trusted identity provider for
+// an ACL token granting the policies and roles bound to the identity.
+func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.Login", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "login"}, time.Now())
+
+	method, err := a.authMethod(args.AuthMethodName, structs.ACLAuthMethodTypeJWT)
+	if err != nil {
+		return err
+	}
+	args.AuthMethodName = method.Name
+
+	if done, err := a.forwardGlobalAuthMethod(method, "ACL.Login", &args.WriteRequest, args, reply); done {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), authProviderTimeout)
+	defer cancel()
+	p, err := a.srv.authProviders.get(ctx, method)
+	if err != nil {
+		return err
+	}
+	claims, err := verifyAuthToken(ctx, method, p.keys, p.issuer, "", args.LoginToken)
+	if err != nil {
+		a.srv.logger.Printf("[DEBUG] nomad.acl: JWT auth method %q rejected token: %v", method.Name, err)
+		return structs.ErrPermissionDenied
+	}
+
+	return a.mintAuthToken(method, claims, reply)
+}
+
+// authMethod returns the auth method with the given name, or the default auth
+// method if the name is empty, and checks its type.
+func (a *ACL) authMethod(name, methodType string) (*structs.ACLAuthMethod, error) {
+	var method *structs.ACLAuthMethod
+	var err error
+	if name == "" {
+		method, err = a.srv.State().ACLDefaultAuthMethod(nil)
+		if err == nil && method == nil {
+			return nil, fmt.Errorf("no auth method specified and no default auth method configured")
+		}
+	} else {
+		method, err = a.srv.State().ACLAuthMethodByName(nil, name)
+		if err == nil && method == nil {
+			return nil, fmt.Errorf("cannot find auth method %s", name)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if method.Type != methodType {
+		return nil, fmt.Errorf("auth method %s is not of type %q", method.Name, methodType)
+	}
+	return method, nil
+}
+
+// forwardGlobalAuthMethod forwards the request to the authoritative region if
+// the auth method mints global tokens.
+func (a *ACL) forwardGlobalAuthMethod(method *structs.ACLAuthMethod, rpc string,
+	w *structs.WriteRequest, args, reply interface{}) (bool, error) {
+	if method.TokenLocality != structs.ACLAuthMethodTokenLocalityGlobal ||
+		a.srv.config.Region == a.srv.config.AuthoritativeRegion {
+		return false, nil
+	}
+	w.Region = a.srv.config.AuthoritativeRegion
+	return true, a.srv.forwardRegion(w.Region, rpc, args, reply)
+}
+
+// mintAuthToken creates the token of the identity authenticated by the auth
+// method. The token is created through UpsertTokens with the leader's token
+// since the caller isn't authenticated yet.
+func (a *ACL) mintAuthToken(method *structs.ACLAuthMethod, claims map[string]interface{}, reply *structs.ACLLoginResponse) error {
+	snap, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	policies, roles, err := bindAuthIdentity(snap, method, claims)
+	if err != nil {
+		return err
+	}
+	if len(policies) == 0 && len(roles) == 0 {
+		a.srv.logger.Printf("[DEBUG] nomad.acl: no binding rules of auth method %q matched the identity", method.Name)
+		return structs.ErrPermissionDenied
+	}
+
+	req := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{{
+			Name:          authTokenName(method, claims),
+			Type:          structs.ACLClientToken,
+			Policies:      policies,
+			Roles:         roles,
+			Global:        method.TokenLocality == structs.ACLAuthMethodTokenLocalityGlobal,
+			ExpirationTTL: method.MaxTokenTTL,
+		}},
+		WriteRequest: structs.WriteRequest{
+			Region:    a.srv.config.Region,
+			AuthToken: a.srv.getLeaderAcl(),
+		},
+	}
+	var resp structs.ACLTokenUpsertResponse
+	if err := a.UpsertTokens(req, &resp); err != nil {
+		return err
+	}
+
+	reply.ACLToken = resp.Tokens[0]
+	reply.Index = resp.Index
+	return nil
+}
+
 // Bootstrap is used to bootstrap the initial token
 func (a *ACL) Bootstrap(args *structs.ACLTokenBootstrapRequest, reply *structs.ACLTokenUpsertResponse) error {
 	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
@@ -501,6 +1555,20 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 			token.SecretID = uuid.Generate()
 			token.CreateTime = time.Now().UTC()
 
+			// Convert the TTL to an absolute time, so that global tokens
+			// expire at the same time in all regions
+			if token.ExpirationTTL != 0 {
+				expiry := token.CreateTime.Add(token.ExpirationTTL)
+				token.ExpirationTime = &expiry
+				token.ExpirationTTL = 0
+			} else if token.ExpirationTime != nil {
+				if !token.ExpirationTime.After(token.CreateTime) {
+					return fmt.Errorf("token %d invalid: expiration time must be in the future", idx)
+				}
+				expiry := token.ExpirationTime.UTC()
+				token.ExpirationTime = &expiry
+			}
+
 		} else {
 			// Verify the token exists
 			out, err := state.ACLTokenByAccessorID(nil, token.AccessorID)
@@ -515,6 +1583,13 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 			if token.Global != out.Global {
 				return fmt.Errorf("cannot toggle global mode of %s", token.AccessorID)
 			}
+
+			// Cannot change the expiration. Omitting it keeps the existing one.
+			if token.ExpirationTTL != 0 ||
+				(token.ExpirationTime != nil && (out.ExpirationTime == nil || !token.ExpirationTime.Equal(*out.ExpirationTime))) {
+				return fmt.Errorf("cannot change expiration of %s", token.AccessorID)
+			}
+			token.ExpirationTime = out.ExpirationTime
 		}
 
 		// Compute the token hash
@@ -3,6 +3,8 @@ package nomad
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +12,7 @@ import (
 	"time"
 
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/helper/oidc/oidctest"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -519,6 +522,224 @@ func TestACLEndpoint_UpsertPolicies_Invalid(t *testing.T) {
 	}
 }
 
+func TestACLEndpoint_UpsertRoles(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	r1 := mock.ACLRole()
+
+	// Upsert the role
+	req := &structs.ACLRoleUpsertRequest{
+		Roles: []*structs.ACLRole{r1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertRoles", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Check we created the role
+	out, err := s1.fsm.State().ACLRoleByName(nil, r1.Name)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+
+	// Roles without policies are rejected
+	r2 := mock.ACLRole()
+	r2.Policies = nil
+	req.Roles = []*structs.ACLRole{r2}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertRoles", req, &resp)
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "missing policies") {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestACLEndpoint_DeleteRoles(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	r1 := mock.ACLRole()
+	s1.fsm.State().UpsertACLRoles(1000, []*structs.ACLRole{r1})
+
+	// Delete the role
+	req := &structs.ACLRoleDeleteRequest{
+		Names: []string{r1.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.DeleteRoles", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	out, err := s1.fsm.State().ACLRoleByName(nil, r1.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
+func TestACLEndpoint_GetRole(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	s1.fsm.State().UpsertACLRoles(1000, []*structs.ACLRole{r1, r2})
+
+	// Create a token with only the first role
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{r1.Name}
+	s1.fsm.State().UpsertACLTokens(1001, []*structs.ACLToken{token})
+
+	// Lookup the role
+	get := &structs.ACLRoleSpecificRequest{
+		Name: r1.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SingleACLRoleResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.GetRole", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Equal(t, r1, resp.Role)
+
+	// Lookup non-existing role
+	get.Name = uuid.Generate()
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.GetRole", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Nil(t, resp.Role)
+
+	// Lookup the role with the token
+	get.Name = r1.Name
+	get.AuthToken = token.SecretID
+	var resp2 structs.SingleACLRoleResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.GetRole", get, &resp2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, r1, resp2.Role)
+
+	// The token may not read a role it doesn't have
+	get.Name = r2.Name
+	err := msgpackrpc.CallWithCodec(codec, "ACL.GetRole", get, &resp2)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_GetRoles(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	s1.fsm.State().UpsertACLRoles(1000, []*structs.ACLRole{r1, r2})
+
+	token := mock.ACLToken()
+	token.Roles = []string{r1.Name}
+	s1.fsm.State().UpsertACLTokens(1001, []*structs.ACLToken{token})
+
+	// Lookup the roles
+	get := &structs.ACLRoleSetRequest{
+		Names: []string{r1.Name},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.ACLRoleSetResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.GetRoles", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Len(t, resp.Roles, 1)
+	assert.Equal(t, r1, resp.Roles[r1.Name])
+
+	// The token is not granted the second role
+	get.Names = []string{r1.Name, r2.Name}
+	err := msgpackrpc.CallWithCodec(codec, "ACL.GetRoles", get, &resp)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_ListRoles(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	r1.Name = "aaaaaaaa-3350-4b4b-d185-0e1992ed43e9"
+	r2.Name = "aaaabbbb-3350-4b4b-d185-0e1992ed43e9"
+	s1.fsm.State().UpsertACLRoles(1000, []*structs.ACLRole{r1, r2})
+
+	// Create a token with only the second role
+	token := mock.ACLToken()
+	token.Roles = []string{r2.Name}
+	s1.fsm.State().UpsertACLTokens(1001, []*structs.ACLToken{token})
+
+	// Lookup the roles
+	get := &structs.ACLRoleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLRoleListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListRoles", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Len(t, resp.Roles, 2)
+
+	// Lookup the roles by prefix
+	get.Prefix = "aaaabb"
+	var resp2 structs.ACLRoleListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListRoles", get, &resp2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Len(t, resp2.Roles, 1)
+
+	// A client token only lists its own roles
+	get.Prefix = ""
+	get.AuthToken = token.SecretID
+	var resp3 structs.ACLRoleListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListRoles", get, &resp3); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Len(t, resp3.Roles, 1)
+	assert.Equal(t, r2.Name, resp3.Roles[0].Name)
+}
+
 func TestACLEndpoint_GetToken(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, nil)
@@ -1089,6 +1310,69 @@ func TestACLEndpoint_UpsertTokens(t *testing.T) {
 	assert.Equal(t, created, out)
 }
 
+func TestACLEndpoint_UpsertTokens_Expiration(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a token with a TTL
+	p1 := mock.ACLToken()
+	p1.AccessorID = ""
+	p1.ExpirationTTL = time.Hour
+	req := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{p1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLTokenUpsertResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// The TTL is converted to an absolute expiration time
+	created := resp.Tokens[0]
+	if assert.NotNil(t, created.ExpirationTime) {
+		assert.True(t, created.CreateTime.Add(time.Hour).Equal(*created.ExpirationTime))
+	}
+	assert.Zero(t, created.ExpirationTTL)
+
+	// Updating the token keeps the expiration time
+	update := *created
+	update.Name = "renamed"
+	update.ExpirationTime = nil
+	req.Tokens = []*structs.ACLToken{&update}
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	out, err := s1.fsm.State().ACLTokenByAccessorID(nil, created.AccessorID)
+	assert.Nil(t, err)
+	assert.Equal(t, "renamed", out.Name)
+	if assert.NotNil(t, out.ExpirationTime) {
+		assert.True(t, created.ExpirationTime.Equal(*out.ExpirationTime))
+	}
+
+	// The expiration time can't be changed
+	update.ExpirationTTL = 2 * time.Hour
+	update.ExpirationTime = nil
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cannot change expiration")
+
+	// Expiration times must be in the future
+	past := time.Now().Add(-time.Hour)
+	p2 := mock.ACLToken()
+	p2.AccessorID = ""
+	p2.ExpirationTime = &past
+	req.Tokens = []*structs.ACLToken{p2}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "must be in the future")
+}
+
 func TestACLEndpoint_UpsertTokens_Invalid(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, nil)
@@ -1147,3 +1431,559 @@ func TestACLEndpoint_ResolveToken(t *testing.T) {
 	assert.Equal(t, uint64(1000), resp.Index)
 	assert.Nil(t, resp.Token)
 }
+
+func TestACLEndpoint_UpsertAuthMethods(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	m1 := mock.ACLAuthMethod()
+	m1.Default = true
+	m1.TokenLocality = ""
+
+	// Upsert the auth method
+	req := &structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{m1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Check we created the auth method with the defaults
+	out, err := s1.fsm.State().ACLAuthMethodByName(nil, m1.Name)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+	assert.Equal(t, structs.ACLAuthMethodTokenLocalityLocal, out.TokenLocality)
+
+	// Only one auth method may be the default
+	m2 := mock.ACLAuthMethod()
+	m2.Default = true
+	req.AuthMethods = []*structs.ACLAuthMethod{m2}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp)
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "default auth method already exists") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// Invalid auth methods are rejected
+	m3 := mock.ACLAuthMethod()
+	m3.Config.OIDCClientSecret = ""
+	req.AuthMethods = []*structs.ACLAuthMethod{m3}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp)
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "client ID and secret") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// Management tokens are required
+	req.AuthMethods = []*structs.ACLAuthMethod{mock.ACLAuthMethod()}
+	req.AuthToken = ""
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp)
+	assert.Equal(t, structs.ErrPermissionDenied.Error(), err.Error())
+}
+
+func TestACLEndpoint_DeleteAuthMethods(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the auth method and a binding rule
+	m1 := mock.ACLAuthMethod()
+	r1 := mock.ACLBindingRule()
+	r1.AuthMethod = m1.Name
+	s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{m1})
+	s1.fsm.State().UpsertACLBindingRules(1001, []*structs.ACLBindingRule{r1})
+
+	// Delete the auth method
+	req := &structs.ACLAuthMethodDeleteRequest{
+		Names: []string{m1.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.DeleteAuthMethods", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Ensure the auth method and its binding rule are gone
+	out, err := s1.fsm.State().ACLAuthMethodByName(nil, m1.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+	rule, err := s1.fsm.State().ACLBindingRuleByID(nil, r1.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, rule)
+}
+
+func TestACLEndpoint_ListAuthMethods(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the auth methods
+	m1 := mock.ACLAuthMethod()
+	m2 := mock.ACLAuthMethod()
+	s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{m1, m2})
+
+	// Lookup the auth methods without a token, since listing is used to
+	// find the auth method to login with
+	get := &structs.ACLAuthMethodListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+	var resp structs.ACLAuthMethodListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListAuthMethods", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Len(t, resp.AuthMethods, 2)
+
+	// Lookup the auth methods by prefix
+	get.Prefix = m1.Name
+	var resp2 structs.ACLAuthMethodListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListAuthMethods", get, &resp2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Len(t, resp2.AuthMethods, 1)
+	assert.Equal(t, m1.Name, resp2.AuthMethods[0].Name)
+}
+
+func TestACLEndpoint_GetAuthMethod(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the auth method
+	m1 := mock.ACLAuthMethod()
+	s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{m1})
+
+	// Lookup the auth method
+	get := &structs.ACLAuthMethodSpecificRequest{
+		Name: m1.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SingleACLAuthMethodResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.GetAuthMethod", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Equal(t, m1, resp.AuthMethod)
+
+	// The configuration, including the client secret, requires a
+	// management token
+	get.AuthToken = ""
+	err := msgpackrpc.CallWithCodec(codec, "ACL.GetAuthMethod", get, &resp)
+	assert.Equal(t, structs.ErrPermissionDenied.Error(), err.Error())
+}
+
+func TestACLEndpoint_UpsertBindingRules(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	m1 := mock.ACLAuthMethod()
+	s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{m1})
+
+	// Create the register request
+	r1 := mock.ACLBindingRule()
+	r1.ID = ""
+	r1.AuthMethod = m1.Name
+
+	// Upsert the binding rule
+	req := &structs.ACLBindingRuleUpsertRequest{
+		BindingRules: []*structs.ACLBindingRule{r1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLBindingRuleUpsertResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+	assert.Len(t, resp.BindingRules, 1)
+
+	// Check we created the binding rule
+	iter, err := s1.fsm.State().ACLBindingRulesByAuthMethod(nil, m1.Name)
+	assert.Nil(t, err)
+	raw := iter.Next()
+	assert.NotNil(t, raw)
+	assert.NotEmpty(t, raw.(*structs.ACLBindingRule).ID)
+	assert.Equal(t, raw, resp.BindingRules[0])
+
+	// Binding rules of unknown auth methods are rejected
+	r2 := mock.ACLBindingRule()
+	r2.ID = ""
+	req.BindingRules = []*structs.ACLBindingRule{r2}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp)
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "cannot find auth method") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// Updating unknown binding rules is rejected
+	r3 := mock.ACLBindingRule()
+	r3.AuthMethod = m1.Name
+	req.BindingRules = []*structs.ACLBindingRule{r3}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp)
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "cannot find binding rule") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// Invalid selectors are rejected
+	r4 := mock.ACLBindingRule()
+	r4.ID = ""
+	r4.AuthMethod = m1.Name
+	r4.Selector = "groups contains engineering"
+	req.BindingRules = []*structs.ACLBindingRule{r4}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp)
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "invalid selector") {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestACLEndpoint_DeleteBindingRules(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the binding rule
+	r1 := mock.ACLBindingRule()
+	s1.fsm.State().UpsertACLBindingRules(1000, []*structs.ACLBindingRule{r1})
+
+	// Delete the binding rule
+	req := &structs.ACLBindingRuleDeleteRequest{
+		IDs: []string{r1.ID},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.DeleteBindingRules", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Ensure the binding rule is gone
+	out, err := s1.fsm.State().ACLBindingRuleByID(nil, r1.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
+func TestACLEndpoint_ListBindingRules(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the binding rules
+	r1 := mock.ACLBindingRule()
+	r2 := mock.ACLBindingRule()
+	r2.AuthMethod = "other"
+	s1.fsm.State().UpsertACLBindingRules(1000, []*structs.ACLBindingRule{r1, r2})
+
+	// Lookup the binding rules
+	get := &structs.ACLBindingRuleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLBindingRuleListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListBindingRules", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Len(t, resp.BindingRules, 2)
+
+	// Lookup the binding rules of an auth method
+	get.AuthMethod = "other"
+	var resp2 structs.ACLBindingRuleListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListBindingRules", get, &resp2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Len(t, resp2.BindingRules, 1)
+	assert.Equal(t, r2.ID, resp2.BindingRules[0].ID)
+
+	// Management tokens are required
+	get.AuthToken = ""
+	err := msgpackrpc.CallWithCodec(codec, "ACL.ListBindingRules", get, &resp2)
+	assert.Equal(t, structs.ErrPermissionDenied.Error(), err.Error())
+}
+
+func TestACLEndpoint_GetBindingRule(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the binding rule
+	r1 := mock.ACLBindingRule()
+	s1.fsm.State().UpsertACLBindingRules(1000, []*structs.ACLBindingRule{r1})
+
+	// Lookup the binding rule
+	get := &structs.ACLBindingRuleSpecificRequest{
+		ID: r1.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SingleACLBindingRuleResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.GetBindingRule", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Equal(t, r1, resp.BindingRule)
+}
+
+func TestACLEndpoint_OIDCLogin(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	provider := oidctest.NewProvider(t)
+	defer provider.Close()
+	provider.SetClaims(map[string]interface{}{
+		"sub":    "alice",
+		"groups": []string{"engineering"},
+	})
+
+	// Create the policy, auth method and binding rule
+	policy := mock.ACLPolicy()
+	policy.Name = "engineering"
+	method := mock.ACLAuthMethod()
+	method.Config.OIDCDiscoveryURL = provider.Issuer()
+	method.Config.OIDCClientID = oidctest.ClientID
+	method.Config.OIDCClientSecret = oidctest.ClientSecret
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	state := s1.fsm.State()
+	assert.Nil(t, state.UpsertACLPolicies(1000, []*structs.ACLPolicy{policy}))
+	assert.Nil(t, state.UpsertACLAuthMethods(1001, []*structs.ACLAuthMethod{method}))
+	assert.Nil(t, state.UpsertACLBindingRules(1002, []*structs.ACLBindingRule{rule}))
+
+	// Get the authorization URL
+	redirectURI := method.Config.AllowedRedirectURIs[0]
+	urlReq := &structs.ACLOIDCAuthURLRequest{
+		AuthMethodName: method.Name,
+		RedirectURI:    redirectURI,
+		ClientNonce:    "client-nonce",
+		WriteRequest:   structs.WriteRequest{Region: "global"},
+	}
+	var urlResp structs.ACLOIDCAuthURLResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.OIDCAuthURL", urlReq, &urlResp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.True(t, strings.HasPrefix(urlResp.AuthURL, provider.Issuer()))
+
+	// Redirect URIs must be allowed by the auth method
+	urlReq.RedirectURI = "http://evil.example.com/callback"
+	err := msgpackrpc.CallWithCodec(codec, "ACL.OIDCAuthURL", urlReq, &urlResp)
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "is not allowed") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// Authenticate with the provider, which redirects back with the code
+	code, authState := testOIDCAuthenticate(t, urlResp.AuthURL)
+
+	// Completing with a different client nonce is rejected
+	complete := &structs.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: method.Name,
+		ClientNonce:    "other-nonce",
+		State:          authState,
+		Code:           code,
+		RedirectURI:    redirectURI,
+		WriteRequest:   structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.ACLLoginResponse
+	err = msgpackrpc.CallWithCodec(codec, "ACL.OIDCCompleteAuth", complete, &resp)
+	assert.Equal(t, structs.ErrPermissionDenied.Error(), err.Error())
+
+	// Complete the authentication
+	complete.ClientNonce = "client-nonce"
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.OIDCCompleteAuth", complete, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	token := resp.ACLToken
+	assert.NotNil(t, token)
+	assert.Equal(t, []string{"engineering"}, token.Policies)
+	assert.False(t, token.Global)
+	assert.Contains(t, token.Name, "alice")
+	assert.NotNil(t, token.ExpirationTime)
+	assert.True(t, token.ExpirationTime.Before(time.Now().Add(method.MaxTokenTTL+time.Second)))
+
+	// The token resolves to the bound policy
+	acl, err := s1.ResolveToken(token.SecretID)
+	assert.Nil(t, err)
+	assert.NotNil(t, acl)
+	assert.False(t, acl.IsManagement())
+
+	// Identities not matching any binding rule are rejected
+	provider.SetClaims(map[string]interface{}{
+		"sub":    "bob",
+		"groups": []string{"sales"},
+	})
+	urlReq.RedirectURI = redirectURI
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.OIDCAuthURL", urlReq, &urlResp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	complete.Code, complete.State = testOIDCAuthenticate(t, urlResp.AuthURL)
+	err = msgpackrpc.CallWithCodec(codec, "ACL.OIDCCompleteAuth", complete, &resp)
+	assert.Equal(t, structs.ErrPermissionDenied.Error(), err.Error())
+}
+
+// testOIDCAuthenticate visits the authorization URL and returns the code and
+// state the provider redirects to.
+func testOIDCAuthenticate(t *testing.T, authURL string) (string, string) {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	resp.Body.Close()
+
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	return loc.Query().Get("code"), loc.Query().Get("state")
+}
+
+func TestACLEndpoint_Login(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	provider := oidctest.NewProvider(t)
+	defer provider.Close()
+
+	// Create the role, auth method and a binding rule interpolating the
+	// role from the claims
+	policy := mock.ACLPolicy()
+	role := mock.ACLRole()
+	role.Name = "team-payments"
+	role.Policies = []string{policy.Name}
+	method := &structs.ACLAuthMethod{
+		Name:          "ci",
+		Type:          structs.ACLAuthMethodTypeJWT,
+		TokenLocality: structs.ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   10 * time.Minute,
+		Default:       true,
+		Config: &structs.ACLAuthMethodConfig{
+			JWTValidationPubKeys: []string{provider.PublicKeyPEM()},
+			BoundIssuer:          provider.Issuer(),
+			BoundAudiences:       []string{"nomad"},
+			ClaimMappings:        map[string]string{"/ci/team": "team"},
+		},
+	}
+	rule := &structs.ACLBindingRule{
+		ID:         uuid.Generate(),
+		AuthMethod: method.Name,
+		Selector:   `value.team != ""`,
+		BindType:   structs.ACLBindingRuleBindTypeRole,
+		BindName:   "team-${value.team}",
+	}
+	state := s1.fsm.State()
+	assert.Nil(t, state.UpsertACLPolicies(1000, []*structs.ACLPolicy{policy}))
+	assert.Nil(t, state.UpsertACLRoles(1001, []*structs.ACLRole{role}))
+	assert.Nil(t, state.UpsertACLAuthMethods(1002, []*structs.ACLAuthMethod{method}))
+	assert.Nil(t, state.UpsertACLBindingRules(1003, []*structs.ACLBindingRule{rule}))
+
+	// Login with the default auth method
+	req := &structs.ACLLoginRequest{
+		LoginToken: provider.SignJWT(map[string]interface{}{
+			"sub": "pipeline",
+			"aud": "nomad",
+			"ci":  map[string]interface{}{"team": "payments"},
+		}),
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.ACLLoginResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, []string{"team-payments"}, resp.ACLToken.Roles)
+	assert.Empty(t, resp.ACLToken.Policies)
+	assert.NotNil(t, resp.ACLToken.ExpirationTime)
+
+	cases := []struct {
+		Name   string
+		Claims map[string]interface{}
+	}{
+		{"wrong audience", map[string]interface{}{"aud": "other", "ci": map[string]interface{}{"team": "payments"}}},
+		{"wrong issuer", map[string]interface{}{"iss": "https://evil.example.com", "aud": "nomad", "ci": map[string]interface{}{"team": "payments"}}},
+		{"expired", map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix(), "aud": "nomad", "ci": map[string]interface{}{"team": "payments"}}},
+		{"unknown role", map[string]interface{}{"aud": "nomad", "ci": map[string]interface{}{"team": "sales"}}},
+		{"no matching rule", map[string]interface{}{"aud": "nomad"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req.LoginToken = provider.SignJWT(tc.Claims)
+			err := msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp)
+			assert.NotNil(t, err)
+			assert.Equal(t, structs.ErrPermissionDenied.Error(), err.Error())
+		})
+	}
+
+	// Tokens signed by another provider are rejected
+	other := oidctest.NewProvider(t)
+	defer other.Close()
+	req.LoginToken = other.SignJWT(map[string]interface{}{
+		"iss": provider.Issuer(),
+		"aud": "nomad",
+		"ci":  map[string]interface{}{"team": "payments"},
+	})
+	err := msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp)
+	assert.Equal(t, structs.ErrPermissionDenied.Error(), err.Error())
+
+	// OIDC auth methods can't be used to login with a JWT
+	m2 := mock.ACLAuthMethod()
+	assert.Nil(t, state.UpsertACLAuthMethods(1004, []*structs.ACLAuthMethod{m2}))
+	req.AuthMethodName = m2.Name
+	err = msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp)
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "is not of type") {
+		t.Fatalf("bad: %s", err)
+	}
+}
@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"testing"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/acl"
@@ -92,6 +93,90 @@ func TestResolveACLToken(t *testing.T) {
 	}
 }
 
+func TestResolveACLToken_Roles(t *testing.T) {
+	t.Parallel()
+
+	// Create mock state store and cache
+	state := state.TestStateStore(t)
+	cache, err := lru.New2Q(16)
+	assert.Nil(t, err)
+
+	// Create a policy, a role granting it and a token with the role
+	policy := mock.ACLPolicy()
+	policy2 := mock.ACLPolicy()
+	policy2.Rules = `namespace "other" { policy = "read" }`
+	policy2.SetHash()
+	role := mock.ACLRole()
+	role.Policies = []string{policy.Name}
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{role.Name}
+	assert.Nil(t, state.UpsertACLPolicies(100, []*structs.ACLPolicy{policy, policy2}))
+	assert.Nil(t, state.UpsertACLRoles(110, []*structs.ACLRole{role}))
+	assert.Nil(t, state.UpsertACLTokens(120, []*structs.ACLToken{token}))
+
+	snap, err := state.Snapshot()
+	assert.Nil(t, err)
+
+	// The token is granted the policy of the role
+	aclObj, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	assert.Nil(t, err)
+	assert.NotNil(t, aclObj)
+	assert.True(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+	assert.False(t, aclObj.AllowNamespaceOperation("other", acl.NamespaceCapabilityListJobs))
+
+	// Update the role to grant the second policy
+	role2 := mock.ACLRole()
+	role2.Name = role.Name
+	role2.Policies = []string{policy.Name, policy2.Name}
+	role2.SetHash()
+	assert.Nil(t, state.UpsertACLRoles(130, []*structs.ACLRole{role2}))
+	snap, err = state.Snapshot()
+	assert.Nil(t, err)
+
+	// Resolve the same token again, should get the updated ACL
+	aclObj2, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	assert.Nil(t, err)
+	assert.NotNil(t, aclObj2)
+	if aclObj == aclObj2 {
+		t.Fatalf("unexpected cached value")
+	}
+	assert.True(t, aclObj2.AllowNamespaceOperation("other", acl.NamespaceCapabilityListJobs))
+}
+
+func TestResolveACLToken_Expired(t *testing.T) {
+	t.Parallel()
+
+	// Create mock state store and cache
+	state := state.TestStateStore(t)
+	cache, err := lru.New2Q(16)
+	assert.Nil(t, err)
+
+	// Create an expired and a valid token
+	past := time.Now().UTC().Add(-time.Minute)
+	future := time.Now().UTC().Add(time.Hour)
+	expired := mock.ACLToken()
+	expired.ExpirationTime = &past
+	valid := mock.ACLToken()
+	valid.Type = structs.ACLManagementToken
+	valid.Policies = nil
+	valid.ExpirationTime = &future
+	assert.Nil(t, state.UpsertACLTokens(110, []*structs.ACLToken{expired, valid}))
+
+	snap, err := state.Snapshot()
+	assert.Nil(t, err)
+
+	// The expired token is rejected
+	aclObj, err := resolveTokenFromSnapshotCache(snap, cache, expired.SecretID)
+	assert.Equal(t, structs.ErrTokenExpired, err)
+	assert.Nil(t, aclObj)
+
+	// The token that has not expired yet resolves
+	aclObj, err = resolveTokenFromSnapshotCache(snap, cache, valid.SecretID)
+	assert.Nil(t, err)
+	assert.True(t, aclObj.IsManagement())
+}
+
 func TestResolveACLToken_LeaderToken(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
@@ -107,3 +192,44 @@ func TestResolveACLToken_LeaderToken(t *testing.T) {
 		assert.True(token.IsManagement())
 	}
 }
+
+func TestResolveToken_WorkloadIdentity(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+
+	alloc := mock.Alloc()
+	state := s1.fsm.State()
+	assert.Nil(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	token, err := s1.encrypter.SignClaims(structs.NewIdentityClaims(alloc, "web"))
+	assert.Nil(err)
+
+	// The identity may only read the variables of its job
+	aclObj, err := s1.ResolveToken(token)
+	assert.Nil(err)
+	assert.NotNil(aclObj)
+	assert.False(aclObj.IsManagement())
+	assert.False(aclObj.AllowNamespaceOperation(alloc.Namespace, acl.NamespaceCapabilityReadJob))
+	assert.False(aclObj.AllowNamespaceOperation(alloc.Namespace, acl.NamespaceCapabilityListJobs))
+	assert.False(aclObj.AllowNamespaceOperation(alloc.Namespace, acl.NamespaceCapabilitySubmitJob))
+	assert.True(aclObj.AllowVariableOperation(alloc.Namespace,
+		structs.VariablePathForJob(alloc.JobID)+"/db", acl.VariablesCapabilityRead))
+	assert.False(aclObj.AllowVariableOperation(alloc.Namespace,
+		structs.VariablePathForJob(alloc.JobID)+"/db", acl.VariablesCapabilityWrite))
+	assert.False(aclObj.AllowVariableOperation(alloc.Namespace, "app/db", acl.VariablesCapabilityRead))
+
+	// The identity is no longer valid once the allocation is terminal
+	stopped := alloc.Copy()
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	stopped.ClientStatus = structs.AllocClientStatusComplete
+	assert.Nil(state.UpsertAllocs(1001, []*structs.Allocation{stopped}))
+	_, err = s1.ResolveToken(token)
+	assert.Equal(structs.ErrTokenNotFound, err)
+
+	// A tampered token is rejected
+	_, err = s1.ResolveToken(token + "a")
+	assert.Equal(structs.ErrTokenNotFound, err)
+}
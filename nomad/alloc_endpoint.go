@@ -8,6 +8,8 @@ import (
 	"github.com/hashicorp/go-memdb"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -24,10 +26,10 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 	}
 	defer metrics.MeasureSince([]string{"nomad", "alloc", "list"}, time.Now())
 
-	// Check namespace read-job permissions
+	// Check namespace read-job-status permissions
 	if aclObj, err := a.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJobStatus) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -233,3 +235,166 @@ func (a *Alloc) UpdateDesiredTransition(args *structs.AllocUpdateDesiredTransiti
 	reply.Index = index
 	return nil
 }
+
+// Stop is used to stop an allocation and migrate it to another node. The
+// allocation's desired transition is marked for migration and an evaluation is
+// created to replace it.
+func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopResponse) error {
+	if done, err := a.srv.forward("Alloc.Stop", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "stop"}, time.Now())
+
+	// Check alloc-lifecycle permissions before looking up the allocation so
+	// its existence is not revealed to callers without them
+	aclObj, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityAllocLifecycle) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.AllocID == "" {
+		return fmt.Errorf("must provide an alloc id")
+	}
+
+	alloc, err := a.srv.State().AllocByID(nil, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil {
+		return structs.NewErrUnknownAllocation(args.AllocID)
+	}
+
+	// The permissions must also hold in the namespace of the allocation
+	if aclObj != nil && alloc.Namespace != args.RequestNamespace() && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocLifecycle) {
+		return structs.ErrPermissionDenied
+	}
+
+	if alloc.TerminalStatus() {
+		return fmt.Errorf("allocation %q is already stopped", alloc.ID)
+	}
+
+	// Record who stopped the allocation
+	reason := "alloc stop requested"
+	if accessor := a.srv.resolveAccessorID(args.AuthToken); accessor != "" {
+		reason = fmt.Sprintf("alloc stop requested by accessor %s", accessor)
+	}
+
+	eval := &structs.Evaluation{
+		ID:             uuid.Generate(),
+		Namespace:      alloc.Namespace,
+		Priority:       alloc.Job.Priority,
+		Type:           alloc.Job.Type,
+		TriggeredBy:    structs.EvalTriggerAllocStop,
+		JobID:          alloc.Job.ID,
+		JobModifyIndex: alloc.Job.ModifyIndex,
+		Status:         structs.EvalStatusPending,
+	}
+
+	transitionReq := &structs.AllocUpdateDesiredTransitionRequest{
+		Allocs: map[string]*structs.DesiredTransition{
+			alloc.ID: {
+				Migrate: helper.BoolToPtr(true),
+				Reason:  reason,
+			},
+		},
+		Evals: []*structs.Evaluation{eval},
+	}
+
+	// Commit this update via Raft
+	_, index, err := a.srv.raftApply(structs.AllocUpdateDesiredTransitionRequestType, transitionReq)
+	if err != nil {
+		a.srv.logger.Printf("[ERR] nomad.allocs: stopping alloc %q failed: %v", alloc.ID, err)
+		return err
+	}
+
+	// Setup the response
+	reply.EvalID = eval.ID
+	reply.Index = index
+	return nil
+}
+
+// SignIdentity is used by clients to retrieve the signed workload identity of
+// a task of an allocation running on the node.
+func (a *Alloc) SignIdentity(args *structs.AllocIdentityRequest, reply *structs.AllocIdentityResponse) error {
+	if done, err := a.srv.forward("Alloc.SignIdentity", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "sign_identity"}, time.Now())
+
+	if args.TaskName == "" {
+		return fmt.Errorf("missing task name")
+	}
+
+	snap, err := a.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	alloc, err := nodeAllocByID(snap, args.NodeID, args.SecretID, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg == nil || tg.LookupTask(args.TaskName) == nil {
+		return fmt.Errorf("Task %q does not exist in allocation %q", args.TaskName, args.AllocID)
+	}
+
+	claims := structs.NewIdentityClaims(alloc, args.TaskName)
+	claims.Issuer = a.srv.config.OIDCIssuer
+	token, err := a.srv.encrypter.SignClaims(claims)
+	if err != nil {
+		return fmt.Errorf("failed to sign identity: %v", err)
+	}
+
+	index, err := snap.Index("allocs")
+	if err != nil {
+		return err
+	}
+	reply.Token = token
+	reply.Expiry = time.Unix(claims.Expiry, 0)
+	reply.Index = index
+	a.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// nodeAllocByID returns the non-terminal allocation with the given ID after
+// verifying that the requesting node exists with the given SecretID and that
+// the allocation is running on it.
+func nodeAllocByID(snap *state.StateSnapshot, nodeID, secretID, allocID string) (*structs.Allocation, error) {
+	// Verify the arguments
+	if nodeID == "" {
+		return nil, fmt.Errorf("missing node ID")
+	}
+	if secretID == "" {
+		return nil, fmt.Errorf("missing node SecretID")
+	}
+	if allocID == "" {
+		return nil, fmt.Errorf("missing allocation ID")
+	}
+
+	node, err := snap.NodeByID(nil, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, fmt.Errorf("Node %q does not exist", nodeID)
+	}
+	if node.SecretID != secretID {
+		return nil, structs.ErrPermissionDenied
+	}
+
+	alloc, err := snap.AllocByID(nil, allocID)
+	if err != nil {
+		return nil, err
+	}
+	if alloc == nil {
+		return nil, fmt.Errorf("Allocation %q does not exist", allocID)
+	}
+	if alloc.NodeID != nodeID {
+		return nil, fmt.Errorf("Allocation %q not running on Node %q", allocID, nodeID)
+	}
+	if alloc.TerminalStatus() {
+		return nil, fmt.Errorf("Can't request %q for terminal allocation", allocID)
+	}
+	return alloc, nil
+}
@@ -567,3 +567,135 @@ func TestAllocEndpoint_UpdateDesiredTransition(t *testing.T) {
 	require.True(*out1.DesiredTransition.Migrate)
 	require.True(*out2.DesiredTransition.Migrate)
 }
+
+func TestAllocEndpoint_Stop(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the alloc
+	alloc := mock.Alloc()
+	state := s1.fsm.State()
+	require.Nil(state.UpsertJobSummary(998, mock.JobSummary(alloc.JobID)))
+	require.Nil(state.UpsertAllocs(999, []*structs.Allocation{alloc}))
+
+	// Create the namespace policy and tokens
+	validToken := mock.CreatePolicyAndToken(t, state, 1001, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocLifecycle}))
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+
+	req := &structs.AllocStopRequest{
+		AllocID: alloc.ID,
+		WriteRequest: structs.WriteRequest{
+			Region: "global",
+		},
+	}
+
+	// Try without permissions
+	var resp structs.AllocStopResponse
+	err := msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp)
+	require.NotNil(err)
+	require.True(structs.IsErrPermissionDenied(err))
+
+	// Try with an invalid token
+	req.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp)
+	require.NotNil(err)
+	require.True(structs.IsErrPermissionDenied(err))
+
+	// The invalid token doesn't learn whether an allocation exists
+	unknown := &structs.AllocStopRequest{
+		AllocID: uuid.Generate(),
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: invalidToken.SecretID,
+		},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Stop", unknown, &resp)
+	require.NotNil(err)
+	require.True(structs.IsErrPermissionDenied(err))
+
+	// A valid token is told the allocation is unknown
+	unknown.AuthToken = validToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Stop", unknown, &resp)
+	require.NotNil(err)
+	require.True(structs.IsErrUnknownAllocation(err))
+
+	// Try with a valid token
+	req.AuthToken = validToken.SecretID
+	var resp2 structs.AllocStopResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp2))
+	require.NotZero(resp2.Index)
+	require.NotEmpty(resp2.EvalID)
+
+	// Look up the allocation and the evaluation
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.Nil(err)
+	require.NotNil(out.DesiredTransition.Migrate)
+	require.True(*out.DesiredTransition.Migrate)
+	require.Contains(out.DesiredTransition.Reason, validToken.AccessorID)
+
+	eval, err := state.EvalByID(nil, resp2.EvalID)
+	require.Nil(err)
+	require.NotNil(eval)
+	require.Equal(structs.EvalTriggerAllocStop, eval.TriggeredBy)
+	require.Equal(alloc.JobID, eval.JobID)
+
+	// Stopping an unknown allocation fails
+	req.AllocID = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp)
+	require.NotNil(err)
+	require.True(structs.IsErrUnknownAllocation(err))
+}
+
+func TestAllocEndpoint_SignIdentity(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1000, node))
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	require.NoError(state.UpsertAllocs(1001, []*structs.Allocation{alloc}))
+	task := alloc.Job.TaskGroups[0].Tasks[0].Name
+
+	req := &structs.AllocIdentityRequest{
+		NodeID:       node.ID,
+		SecretID:     node.SecretID,
+		AllocID:      alloc.ID,
+		TaskName:     task,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.AllocIdentityResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Alloc.SignIdentity", req, &resp))
+	require.NotEmpty(resp.Token)
+
+	// The token is verified by the keyring
+	claims, err := s1.encrypter.VerifyClaim(resp.Token)
+	require.NoError(err)
+	require.Equal(alloc.ID, claims.AllocationID)
+	require.Equal(alloc.JobID, claims.JobID)
+	require.Equal(alloc.Namespace, claims.Namespace)
+	require.Equal(task, claims.Task)
+
+	// An unknown task is rejected
+	req.TaskName = "foo"
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.SignIdentity", req, &resp)
+	require.Error(err)
+
+	// A bad secret is rejected
+	req.TaskName = task
+	req.SecretID = "foo"
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.SignIdentity", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+}
@@ -357,6 +357,9 @@ func TestClientAllocations_GarbageCollect_Local_ACL(t *testing.T) {
 	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob})
 	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
 
+	policyLifecycle := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocLifecycle})
+	tokenLifecycle := mock.CreatePolicyAndToken(t, s.State(), 1011, "valid3", policyLifecycle)
+
 	cases := []struct {
 		Name          string
 		Token         string
@@ -372,6 +375,11 @@ func TestClientAllocations_GarbageCollect_Local_ACL(t *testing.T) {
 			Token:         tokenGood.SecretID,
 			ExpectedError: structs.ErrUnknownAllocationPrefix,
 		},
+		{
+			Name:          "alloc-lifecycle token",
+			Token:         tokenLifecycle.SecretID,
+			ExpectedError: structs.ErrUnknownAllocationPrefix,
+		},
 		{
 			Name:          "root token",
 			Token:         root.SecretID,
@@ -0,0 +1,112 @@
+package nomad
+
+import (
+	"errors"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ClientCSI is used to forward RPC requests to the CSI plugins running on the
+// targeted Nomad client. The servers use it to attach and detach volumes while
+// allocations claim them.
+type ClientCSI struct {
+	srv *Server
+}
+
+// ControllerAttachVolume is used to attach a volume to a node using the
+// controller plugin running on a client.
+func (a *ClientCSI) ControllerAttachVolume(args *cstructs.ClientCSIControllerAttachVolumeRequest, reply *cstructs.ClientCSIControllerAttachVolumeResponse) error {
+	// Potentially forward to a different region.
+	if done, err := a.srv.forward("ClientCSI.ControllerAttachVolume", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_csi_controller", "attach_volume"}, time.Now())
+
+	if err := a.checkServerRequest(args.AuthToken, args.ControllerNodeID); err != nil {
+		return err
+	}
+
+	// Get the connection to the client
+	state, ok := a.srv.getNodeConn(args.ControllerNodeID)
+	if !ok {
+		return findNodeConnAndForward(a.srv, args.ControllerNodeID, "ClientCSI.ControllerAttachVolume", args, reply)
+	}
+
+	// Make the RPC
+	return NodeRpc(state.Session, "CSI.ControllerAttachVolume", args, reply)
+}
+
+// ControllerDetachVolume is used to detach a volume from a node using the
+// controller plugin running on a client.
+func (a *ClientCSI) ControllerDetachVolume(args *cstructs.ClientCSIControllerDetachVolumeRequest, reply *cstructs.ClientCSIControllerDetachVolumeResponse) error {
+	// Potentially forward to a different region.
+	if done, err := a.srv.forward("ClientCSI.ControllerDetachVolume", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_csi_controller", "detach_volume"}, time.Now())
+
+	if err := a.checkServerRequest(args.AuthToken, args.ControllerNodeID); err != nil {
+		return err
+	}
+
+	// Get the connection to the client
+	state, ok := a.srv.getNodeConn(args.ControllerNodeID)
+	if !ok {
+		return findNodeConnAndForward(a.srv, args.ControllerNodeID, "ClientCSI.ControllerDetachVolume", args, reply)
+	}
+
+	// Make the RPC
+	return NodeRpc(state.Session, "CSI.ControllerDetachVolume", args, reply)
+}
+
+// NodeDetachVolume is used to unpublish a volume on the node of an allocation
+// that stopped without releasing its claim on the volume.
+func (a *ClientCSI) NodeDetachVolume(args *cstructs.ClientCSINodeDetachVolumeRequest, reply *cstructs.ClientCSINodeDetachVolumeResponse) error {
+	// Potentially forward to a different region.
+	if done, err := a.srv.forward("ClientCSI.NodeDetachVolume", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_csi_node", "detach_volume"}, time.Now())
+
+	if err := a.checkServerRequest(args.AuthToken, args.NodeID); err != nil {
+		return err
+	}
+
+	// Get the connection to the client
+	state, ok := a.srv.getNodeConn(args.NodeID)
+	if !ok {
+		return findNodeConnAndForward(a.srv, args.NodeID, "ClientCSI.NodeDetachVolume", args, reply)
+	}
+
+	// Make the RPC
+	return NodeRpc(state.Session, "CSI.NodeDetachVolume", args, reply)
+}
+
+// checkServerRequest checks that the request is made by the servers and that
+// the target node can be reached.
+func (a *ClientCSI) checkServerRequest(token, nodeID string) error {
+	// Plugin operations are only made by the servers on behalf of clients
+	aclObj, err := a.srv.ResolveToken(token)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Verify the arguments.
+	if nodeID == "" {
+		return errors.New("missing node ID")
+	}
+
+	// Make sure Node is valid and new enough to support RPC
+	snap, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	_, err = getNodeForRpc(snap, nodeID)
+	return err
+}
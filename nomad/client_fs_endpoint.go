@@ -30,7 +30,7 @@ func (f *FileSystem) register() {
 // handleStreamResultError is a helper for sending an error with a potential
 // error code. The transmission of the error is ignored if the error has been
 // generated by the closing of the underlying transport.
-func (f *FileSystem) handleStreamResultError(err error, code *int64, encoder *codec.Encoder) {
+func handleStreamResultError(err error, code *int64, encoder *codec.Encoder) {
 	// Nothing to do as the conn is closed
 	if err == io.EOF || strings.Contains(err.Error(), "closed") {
 		return
@@ -45,39 +45,42 @@ func (f *FileSystem) handleStreamResultError(err error, code *int64, encoder *co
 // forwardRegionStreamingRpc is used to make a streaming RPC to a different
 // region. It looks up the allocation in the remote region to determine what
 // remote server can route the request.
-func (f *FileSystem) forwardRegionStreamingRpc(conn io.ReadWriteCloser,
+func forwardRegionStreamingRpc(fsrv *Server, conn io.ReadWriteCloser,
 	encoder *codec.Encoder, args interface{}, method, allocID string, qo *structs.QueryOptions) {
+	// Mark that we are forwarding the RPC
+	qo.SetForwarded()
+
 	// Request the allocation from the target region
 	allocReq := &structs.AllocSpecificRequest{
 		AllocID:      allocID,
 		QueryOptions: *qo,
 	}
 	var allocResp structs.SingleAllocResponse
-	if err := f.srv.forwardRegion(qo.RequestRegion(), "Alloc.GetAlloc", allocReq, &allocResp); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+	if err := fsrv.forwardRegion(qo.RequestRegion(), "Alloc.GetAlloc", allocReq, &allocResp); err != nil {
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
 	if allocResp.Alloc == nil {
-		f.handleStreamResultError(structs.NewErrUnknownAllocation(allocID), helper.Int64ToPtr(404), encoder)
+		handleStreamResultError(structs.NewErrUnknownAllocation(allocID), helper.Int64ToPtr(404), encoder)
 		return
 	}
 
 	// Determine the Server that has a connection to the node.
-	srv, err := f.srv.serverWithNodeConn(allocResp.Alloc.NodeID, qo.RequestRegion())
+	srv, err := fsrv.serverWithNodeConn(allocResp.Alloc.NodeID, qo.RequestRegion())
 	if err != nil {
 		var code *int64
 		if structs.IsErrNoNodeConn(err) {
 			code = helper.Int64ToPtr(404)
 		}
-		f.handleStreamResultError(err, code, encoder)
+		handleStreamResultError(err, code, encoder)
 		return
 	}
 
 	// Get a connection to the server
-	srvConn, err := f.srv.streamingRpc(srv, method)
+	srvConn, err := fsrv.streamingRpc(srv, method)
 	if err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 	defer srvConn.Close()
@@ -85,7 +88,7 @@ func (f *FileSystem) forwardRegionStreamingRpc(conn io.ReadWriteCloser,
 	// Send the request.
 	outEncoder := codec.NewEncoder(srvConn, structs.MsgpackHandle)
 	if err := outEncoder.Encode(args); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
@@ -214,46 +217,52 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
 
 	if err := decoder.Decode(&args); err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Enforce the rate limits before doing any work for the request
+	if err := f.srv.checkStreamingRateLimit("FileSystem.Stream", conn, &args); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(429), encoder)
 		return
 	}
 
 	// Check if we need to forward to a different region
 	if r := args.RequestRegion(); r != f.srv.Region() {
-		f.forwardRegionStreamingRpc(conn, encoder, &args, "FileSystem.Stream",
+		forwardRegionStreamingRpc(f.srv, conn, encoder, &args, "FileSystem.Stream",
 			args.AllocID, &args.QueryOptions)
 		return
 	}
 
 	// Check node read permissions
 	if aclObj, err := f.srv.ResolveToken(args.AuthToken); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	} else if aclObj != nil && !aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilityReadFS) {
-		f.handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
 	}
 
 	// Verify the arguments.
 	if args.AllocID == "" {
-		f.handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
 		return
 	}
 
 	// Retrieve the allocation
 	snap, err := f.srv.State().Snapshot()
 	if err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
 	alloc, err := snap.AllocByID(nil, args.AllocID)
 	if err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 	if alloc == nil {
-		f.handleStreamResultError(structs.NewErrUnknownAllocation(args.AllocID), helper.Int64ToPtr(404), encoder)
+		handleStreamResultError(structs.NewErrUnknownAllocation(args.AllocID), helper.Int64ToPtr(404), encoder)
 		return
 	}
 	nodeID := alloc.NodeID
@@ -261,18 +270,18 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	// Make sure Node is valid and new enough to support RPC
 	node, err := snap.NodeByID(nil, nodeID)
 	if err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
 		return
 	}
 
 	if node == nil {
 		err := fmt.Errorf("Unknown node %q", nodeID)
-		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
 		return
 	}
 
 	if err := nodeSupportsRpc(node); err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
 		return
 	}
 
@@ -288,21 +297,24 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 			if structs.IsErrNoNodeConn(err) {
 				code = helper.Int64ToPtr(404)
 			}
-			f.handleStreamResultError(err, code, encoder)
+			handleStreamResultError(err, code, encoder)
 		}
 
 		// Get a connection to the server
 		conn, err := f.srv.streamingRpc(srv, "FileSystem.Stream")
 		if err != nil {
-			f.handleStreamResultError(err, nil, encoder)
+			handleStreamResultError(err, nil, encoder)
 			return
 		}
 
+		// Mark that we are forwarding the RPC
+		args.SetForwarded()
+
 		clientConn = conn
 	} else {
 		stream, err := NodeStreamingRpc(state.Session, "FileSystem.Stream")
 		if err != nil {
-			f.handleStreamResultError(err, nil, encoder)
+			handleStreamResultError(err, nil, encoder)
 			return
 		}
 		clientConn = stream
@@ -312,7 +324,7 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	// Send the request.
 	outEncoder := codec.NewEncoder(clientConn, structs.MsgpackHandle)
 	if err := outEncoder.Encode(args); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
@@ -331,50 +343,56 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
 
 	if err := decoder.Decode(&args); err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Enforce the rate limits before doing any work for the request
+	if err := f.srv.checkStreamingRateLimit("FileSystem.Logs", conn, &args); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(429), encoder)
 		return
 	}
 
 	// Check if we need to forward to a different region
 	if r := args.RequestRegion(); r != f.srv.Region() {
-		f.forwardRegionStreamingRpc(conn, encoder, &args, "FileSystem.Logs",
+		forwardRegionStreamingRpc(f.srv, conn, encoder, &args, "FileSystem.Logs",
 			args.AllocID, &args.QueryOptions)
 		return
 	}
 
 	// Check node read permissions
 	if aclObj, err := f.srv.ResolveToken(args.AuthToken); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	} else if aclObj != nil {
 		readfs := aclObj.AllowNsOp(args.QueryOptions.Namespace, acl.NamespaceCapabilityReadFS)
 		logs := aclObj.AllowNsOp(args.QueryOptions.Namespace, acl.NamespaceCapabilityReadLogs)
 		if !readfs && !logs {
-			f.handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+			handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 			return
 		}
 	}
 
 	// Verify the arguments.
 	if args.AllocID == "" {
-		f.handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
 		return
 	}
 
 	// Retrieve the allocation
 	snap, err := f.srv.State().Snapshot()
 	if err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
 	alloc, err := snap.AllocByID(nil, args.AllocID)
 	if err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 	if alloc == nil {
-		f.handleStreamResultError(structs.NewErrUnknownAllocation(args.AllocID), helper.Int64ToPtr(404), encoder)
+		handleStreamResultError(structs.NewErrUnknownAllocation(args.AllocID), helper.Int64ToPtr(404), encoder)
 		return
 	}
 	nodeID := alloc.NodeID
@@ -382,18 +400,18 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	// Make sure Node is valid and new enough to support RPC
 	node, err := snap.NodeByID(nil, nodeID)
 	if err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
 		return
 	}
 
 	if node == nil {
 		err := fmt.Errorf("Unknown node %q", nodeID)
-		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
 		return
 	}
 
 	if err := nodeSupportsRpc(node); err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
 		return
 	}
 
@@ -409,22 +427,25 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 			if structs.IsErrNoNodeConn(err) {
 				code = helper.Int64ToPtr(404)
 			}
-			f.handleStreamResultError(err, code, encoder)
+			handleStreamResultError(err, code, encoder)
 			return
 		}
 
 		// Get a connection to the server
 		conn, err := f.srv.streamingRpc(srv, "FileSystem.Logs")
 		if err != nil {
-			f.handleStreamResultError(err, nil, encoder)
+			handleStreamResultError(err, nil, encoder)
 			return
 		}
 
+		// Mark that we are forwarding the RPC
+		args.SetForwarded()
+
 		clientConn = conn
 	} else {
 		stream, err := NodeStreamingRpc(state.Session, "FileSystem.Logs")
 		if err != nil {
-			f.handleStreamResultError(err, nil, encoder)
+			handleStreamResultError(err, nil, encoder)
 			return
 		}
 		clientConn = stream
@@ -434,7 +455,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	// Send the request.
 	outEncoder := codec.NewEncoder(clientConn, structs.MsgpackHandle)
 	if err := outEncoder.Encode(args); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
@@ -0,0 +1,115 @@
+package nomad
+
+import (
+	"errors"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ClientNodeMeta is used to forward RPC requests to the targed Nomad client's
+// NodeMeta endpoint.
+type ClientNodeMeta struct {
+	srv *Server
+}
+
+// Apply is used to update the dynamic meta of a client.
+func (m *ClientNodeMeta) Apply(args *cstructs.NodeMetaApplyRequest, reply *cstructs.NodeMetaResponse) error {
+	// Potentially forward to a different region.
+	if done, err := m.srv.forward("ClientNodeMeta.Apply", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_node_meta", "apply"}, time.Now())
+
+	// Check node write permissions
+	aclObj, err := m.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowAnyNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Verify the arguments.
+	if args.NodeID == "" {
+		return errors.New("missing NodeID")
+	}
+
+	// Make sure Node is valid and new enough to support RPC
+	snap, err := m.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	node, err := getNodeForRpc(snap, args.NodeID)
+	if err != nil {
+		return err
+	}
+
+	// Check the write permissions on the node's class
+	if aclObj != nil && !aclObj.AllowNodeClassWrite(node.NodeClass) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Get the connection to the client
+	state, ok := m.srv.getNodeConn(args.NodeID)
+	if !ok {
+		return findNodeConnAndForward(m.srv, args.NodeID, "ClientNodeMeta.Apply", args, reply)
+	}
+
+	// Make the RPC
+	return NodeRpc(state.Session, "NodeMeta.Apply", args, reply)
+}
+
+// Read is used to retrieve the meta of a client.
+func (m *ClientNodeMeta) Read(args *structs.NodeSpecificRequest, reply *cstructs.NodeMetaResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hope
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := m.srv.forward("ClientNodeMeta.Read", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_node_meta", "read"}, time.Now())
+
+	// Check node read permissions
+	aclObj, err := m.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowAnyNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Verify the arguments.
+	if args.NodeID == "" {
+		return errors.New("missing NodeID")
+	}
+
+	// Make sure Node is valid and new enough to support RPC
+	snap, err := m.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	node, err := getNodeForRpc(snap, args.NodeID)
+	if err != nil {
+		return err
+	}
+
+	// Check the read permissions on the node's class
+	if aclObj != nil && !aclObj.AllowNodeClassRead(node.NodeClass) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Get the connection to the client
+	state, ok := m.srv.getNodeConn(args.NodeID)
+	if !ok {
+		return findNodeConnAndForward(m.srv, args.NodeID, "ClientNodeMeta.Read", args, reply)
+	}
+
+	// Make the RPC
+	return NodeRpc(state.Session, "NodeMeta.Read", args, reply)
+}
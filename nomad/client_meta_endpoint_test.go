@@ -0,0 +1,120 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client"
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientNodeMeta_Local(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+	})
+	defer c.Shutdown()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Make the request without having a node-id
+	req := &cstructs.NodeMetaApplyRequest{
+		Meta:         map[string]*string{"rack": helper.StringToPtr("r1")},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp cstructs.NodeMetaResponse
+	err := msgpackrpc.CallWithCodec(codec, "ClientNodeMeta.Apply", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "missing")
+
+	// Apply the meta setting the node id
+	req.NodeID = c.NodeID()
+	require.Nil(msgpackrpc.CallWithCodec(codec, "ClientNodeMeta.Apply", req, &resp))
+	require.Equal("r1", resp.Meta["rack"])
+
+	// Read the meta back
+	read := &structs.NodeSpecificRequest{
+		NodeID:       c.NodeID(),
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp2 cstructs.NodeMetaResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "ClientNodeMeta.Read", read, &resp2))
+	require.Equal("r1", resp2.Meta["rack"])
+	require.Equal("r1", *resp2.Dynamic["rack"])
+}
+
+func TestClientNodeMeta_Apply_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server
+	s, root := TestACLServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	tokenRead := mock.CreatePolicyAndToken(t, s.State(), 1005, "read", mock.NodePolicy(acl.PolicyRead))
+	tokenWrite := mock.CreatePolicyAndToken(t, s.State(), 1007, "write", mock.NodePolicy(acl.PolicyWrite))
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenRead.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenWrite.SecretID,
+			ExpectedError: "Unknown node",
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: "Unknown node",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			// Make the request with a nonexistent node-id
+			req := &cstructs.NodeMetaApplyRequest{
+				NodeID: uuid.Generate(),
+				Meta:   map[string]*string{"rack": helper.StringToPtr("r1")},
+				WriteRequest: structs.WriteRequest{
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+
+			var resp cstructs.NodeMetaResponse
+			err := msgpackrpc.CallWithCodec(codec, "ClientNodeMeta.Apply", req, &resp)
+			require.NotNil(err)
+			require.Contains(err.Error(), c.ExpectedError)
+		})
+	}
+}
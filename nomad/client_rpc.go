@@ -295,5 +295,9 @@ func findNodeConnAndForward(srv *Server, nodeID, method string, args, reply inte
 		return structs.ErrNoNodeConn
 	}
 
+	// Mark that we are forwarding the RPC
+	if info, ok := args.(structs.RPCInfo); ok {
+		info.SetForwarded()
+	}
 	return srv.forwardServer(srvWithConn, method, args, reply)
 }
@@ -28,9 +28,10 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 	defer metrics.MeasureSince([]string{"nomad", "client_stats", "stats"}, time.Now())
 
 	// Check node read permissions
-	if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := s.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeRead() {
 		return nstructs.ErrPermissionDenied
 	}
 
@@ -46,11 +47,16 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 	}
 
 	// Make sure Node is new enough to support RPC
-	_, err = getNodeForRpc(snap, args.NodeID)
+	node, err := getNodeForRpc(snap, args.NodeID)
 	if err != nil {
 		return err
 	}
 
+	// Check the read permissions on the node's class
+	if aclObj != nil && !aclObj.AllowNodeClassRead(node.NodeClass) {
+		return nstructs.ErrPermissionDenied
+	}
+
 	// Get the connection to the client
 	state, ok := s.srv.getNodeConn(args.NodeID)
 	if !ok {
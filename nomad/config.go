@@ -170,6 +170,15 @@ type Config struct {
 	// for GC. This gives users some time to view and debug a failed nodes.
 	NodeGCThreshold time.Duration
 
+	// RootKeyRotationThreshold is how old the active root key must be before
+	// the leader replaces it with a new key.
+	RootKeyRotationThreshold time.Duration
+
+	// OIDCIssuer is the issuer of the workload identities signed by the
+	// servers. If set, it is included as the "iss" claim and the OIDC
+	// discovery document is served.
+	OIDCIssuer string
+
 	// DeploymentGCInterval is how often we dispatch a job to GC terminal
 	// deployments.
 	DeploymentGCInterval time.Duration
@@ -178,6 +187,14 @@ type Config struct {
 	// for GC. This gives users some time to view terminal deployments.
 	DeploymentGCThreshold time.Duration
 
+	// ACLTokenGCInterval is how often we dispatch a job to GC expired ACL
+	// tokens.
+	ACLTokenGCInterval time.Duration
+
+	// ACLTokenGCThreshold is how long an ACL token must have been expired to
+	// be eligible for GC. This gives users some time to view expired tokens.
+	ACLTokenGCThreshold time.Duration
+
 	// EvalNackTimeout controls how long we allow a sub-scheduler to
 	// work on an evaluation before we consider it failed and Nack it.
 	// This allows that evaluation to be handed to another sub-scheduler
@@ -214,6 +231,11 @@ type Config struct {
 	// additional delay is selected from this range randomly.
 	EvalFailedFollowupDelayRange time.Duration
 
+	// EvalNamespaceWeights is the relative share of evaluations dequeued for
+	// each namespace within a priority when several namespaces have work
+	// ready. Namespaces without a weight have a weight of one.
+	EvalNamespaceWeights map[string]int
+
 	// MinHeartbeatTTL is the minimum time between heartbeats.
 	// This is used as a floor to prevent excessive updates.
 	MinHeartbeatTTL time.Duration
@@ -266,6 +288,19 @@ type Config struct {
 	// SentinelConfig is this Agent's Sentinel configuration
 	SentinelConfig *config.SentinelConfig
 
+	// AdmissionWebhooks are the external admission controllers called by
+	// Job.Register and Job.Plan, in order.
+	AdmissionWebhooks []*config.AdmissionWebhookConfig
+
+	// RPCRateLimit is the rate limit applied to the RPCs made with each ACL
+	// token. Requests are not limited if it is nil.
+	RPCRateLimit *config.RPCRateLimitConfig
+
+	// MemoryOversubscriptionEnabled allows tasks to set a memory limit above
+	// the memory they reserve. When disabled, the memory_max of submitted
+	// tasks is dropped.
+	MemoryOversubscriptionEnabled bool
+
 	// StatsCollectionInterval is the interval at which the Nomad server
 	// publishes metrics which are periodic in nature like updating gauges
 	StatsCollectionInterval time.Duration
@@ -332,7 +367,10 @@ func DefaultConfig() *Config {
 		NodeGCInterval:                   5 * time.Minute,
 		NodeGCThreshold:                  24 * time.Hour,
 		DeploymentGCInterval:             5 * time.Minute,
+		RootKeyRotationThreshold:         30 * 24 * time.Hour,
 		DeploymentGCThreshold:            1 * time.Hour,
+		ACLTokenGCInterval:               5 * time.Minute,
+		ACLTokenGCThreshold:              1 * time.Hour,
 		EvalNackTimeout:                  60 * time.Second,
 		EvalDeliveryLimit:                3,
 		EvalNackInitialReenqueueDelay:    1 * time.Second,
@@ -46,6 +46,8 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.jobGC(eval)
 	case structs.CoreJobDeploymentGC:
 		return c.deploymentGC(eval)
+	case structs.CoreJobExpiredACLTokenGC:
+		return c.expiredACLTokenGC(eval)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
@@ -64,6 +66,9 @@ func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 	if err := c.deploymentGC(eval); err != nil {
 		return err
 	}
+	if err := c.expiredACLTokenGC(eval); err != nil {
+		return err
+	}
 
 	// Node GC must occur after the others to ensure the allocations are
 	// cleared.
@@ -597,6 +602,82 @@ func (c *CoreScheduler) partitionDeploymentReap(deployments []string) []*structs
 	return requests
 }
 
+// expiredACLTokenGC is used to garbage collect expired ACL tokens. Local tokens
+// are collected by each region, while global tokens are only collected by the
+// authoritative region and the deletion is replicated to the other regions.
+func (c *CoreScheduler) expiredACLTokenGC(eval *structs.Evaluation) error {
+	if !c.srv.config.ACLEnabled {
+		return nil
+	}
+
+	cutoff := time.Now().UTC()
+	if eval.JobID == structs.CoreJobForceGC {
+		c.srv.logger.Println("[DEBUG] sched.core: forced expired ACL token GC")
+	} else {
+		cutoff = cutoff.Add(-1 * c.srv.config.ACLTokenGCThreshold)
+		c.srv.logger.Printf("[DEBUG] sched.core: expired ACL token GC: scanning tokens expired before %v (%v)",
+			cutoff, c.srv.config.ACLTokenGCThreshold)
+	}
+
+	global := []bool{false}
+	if c.srv.config.Region == c.srv.config.AuthoritativeRegion {
+		global = append(global, true)
+	}
+
+	for _, g := range global {
+		// Collect the expired tokens, global and local tokens can't be
+		// deleted in the same request
+		ws := memdb.NewWatchSet()
+		iter, err := c.snap.ACLTokensByGlobal(ws, g)
+		if err != nil {
+			return err
+		}
+
+		var gcToken []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			token := raw.(*structs.ACLToken)
+			if token.IsExpired(cutoff) {
+				gcToken = append(gcToken, token.AccessorID)
+			}
+		}
+
+		// Fast-path the nothing case
+		if len(gcToken) == 0 {
+			continue
+		}
+		c.srv.logger.Printf("[DEBUG] sched.core: expired ACL token GC: %d tokens eligible", len(gcToken))
+		if err := c.aclTokenReap(gcToken, eval.LeaderACL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aclTokenReap contacts the leader and issues a reap on the passed ACL tokens.
+func (c *CoreScheduler) aclTokenReap(accessorIDs []string, leaderACL string) error {
+	for len(accessorIDs) != 0 {
+		n := len(accessorIDs)
+		if n > maxIdsPerReap {
+			n = maxIdsPerReap
+		}
+		req := structs.ACLTokenDeleteRequest{
+			AccessorIDs: accessorIDs[:n],
+			WriteRequest: structs.WriteRequest{
+				Region:    c.srv.config.Region,
+				AuthToken: leaderACL,
+			},
+		}
+		accessorIDs = accessorIDs[n:]
+
+		var resp structs.GenericResponse
+		if err := c.srv.RPC("ACL.DeleteTokens", &req, &resp); err != nil {
+			c.srv.logger.Printf("[ERR] sched.core: ACL token reap failed: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
 // allocGCEligible returns if the allocation is eligible to be garbage collected
 // according to its terminal status and its reschedule trackers
 func allocGCEligible(a *structs.Allocation, job *structs.Job, gcTime time.Time, thresholdIndex uint64) bool {
@@ -1690,6 +1690,63 @@ func TestCoreScheduler_DeploymentGC_Force(t *testing.T) {
 	}
 }
 
+func TestCoreScheduler_ExpiredACLTokenGC(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+
+	// Insert a token that doesn't expire, one recently expired and one
+	// expired beyond the GC threshold, both local and global
+	now := time.Now().UTC()
+	recent := now.Add(-1 * time.Minute)
+	old := now.Add(-2 * s1.config.ACLTokenGCThreshold)
+	tk1, tk2, tk3, tk4 := mock.ACLToken(), mock.ACLToken(), mock.ACLToken(), mock.ACLToken()
+	tk2.ExpirationTime = &recent
+	tk3.ExpirationTime = &old
+	tk4.ExpirationTime = &old
+	tk4.Global = true
+	state := s1.fsm.State()
+	assert.Nil(state.UpsertACLTokens(1000, []*structs.ACLToken{tk1, tk2, tk3, tk4}))
+
+	// Create a core scheduler
+	snap, err := state.Snapshot()
+	assert.Nil(err, "Snapshot")
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC
+	gc := s1.coreJobEval(structs.CoreJobExpiredACLTokenGC, 2000)
+	assert.Nil(core.Process(gc), "Process GC")
+
+	// Only the tokens expired beyond the threshold should be gone
+	ws := memdb.NewWatchSet()
+	for _, tk := range []*structs.ACLToken{tk1, tk2} {
+		out, err := state.ACLTokenByAccessorID(ws, tk.AccessorID)
+		assert.Nil(err)
+		assert.NotNil(out)
+	}
+	for _, tk := range []*structs.ACLToken{tk3, tk4} {
+		out, err := state.ACLTokenByAccessorID(ws, tk.AccessorID)
+		assert.Nil(err)
+		assert.Nil(out)
+	}
+
+	// Forcing the GC collects all the expired tokens
+	snap, err = state.Snapshot()
+	assert.Nil(err, "Snapshot")
+	core = NewCoreScheduler(s1, snap)
+	gc = s1.coreJobEval(structs.CoreJobForceGC, 2001)
+	assert.Nil(core.Process(gc), "Process Force GC")
+
+	out, err := state.ACLTokenByAccessorID(ws, tk2.AccessorID)
+	assert.Nil(err)
+	assert.Nil(out)
+	out, err = state.ACLTokenByAccessorID(ws, tk1.AccessorID)
+	assert.Nil(err)
+	assert.NotNil(out)
+}
+
 func TestCoreScheduler_PartitionEvalReap(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
@@ -0,0 +1,592 @@
+package nomad
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// CSIVolume endpoint is used for registering CSI volumes and tracking the
+// claims of allocations on them
+type CSIVolume struct {
+	srv *Server
+}
+
+// Register is used to register volumes provided by CSI plugins
+func (v *CSIVolume) Register(args *structs.CSIVolumeRegisterRequest, reply *structs.CSIVolumeRegisterResponse) error {
+	if done, err := v.srv.forward("CSIVolume.Register", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "register"}, time.Now())
+
+	// Check namespace csi-write-volume permissions
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityCSIWriteVolume) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the volumes
+	if len(args.Volumes) == 0 {
+		return fmt.Errorf("missing volumes")
+	}
+	for _, vol := range args.Volumes {
+		vol.Namespace = args.RequestNamespace()
+		if err := vol.Validate(); err != nil {
+			return fmt.Errorf("volume %q validation failed: %v", vol.ID, err)
+		}
+	}
+
+	// Commit this update via Raft
+	resp, index, err := v.srv.raftApply(structs.CSIVolumeRegisterRequestType, args)
+	if err != nil {
+		v.srv.logger.Printf("[ERR] nomad.csi_volume: register failed: %v", err)
+		return err
+	}
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	reply.Index = index
+	v.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// Deregister is used to remove volumes that are no longer claimed
+func (v *CSIVolume) Deregister(args *structs.CSIVolumeDeregisterRequest, reply *structs.CSIVolumeDeregisterResponse) error {
+	if done, err := v.srv.forward("CSIVolume.Deregister", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "deregister"}, time.Now())
+
+	// Check namespace csi-write-volume permissions
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityCSIWriteVolume) {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.VolumeIDs) == 0 {
+		return fmt.Errorf("missing volume IDs")
+	}
+
+	// Commit this update via Raft
+	resp, index, err := v.srv.raftApply(structs.CSIVolumeDeregisterRequestType, args)
+	if err != nil {
+		v.srv.logger.Printf("[ERR] nomad.csi_volume: deregister failed: %v", err)
+		return err
+	}
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	reply.Index = index
+	v.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// List is used to list the volumes of a namespace, optionally only those
+// provided by a plugin
+func (v *CSIVolume) List(args *structs.CSIVolumeListRequest, reply *structs.CSIVolumeListResponse) error {
+	if done, err := v.srv.forward("CSIVolume.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "list"}, time.Now())
+
+	// Check namespace csi-list-volume permissions
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityCSIListVolume) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			var err error
+			var iter memdb.ResultIterator
+			if args.PluginID != "" {
+				iter, err = state.CSIVolumesByPluginID(ws, args.PluginID)
+			} else {
+				iter, err = state.CSIVolumesByNamespace(ws, args.RequestNamespace())
+			}
+			if err != nil {
+				return err
+			}
+
+			volumes := []*structs.CSIVolListStub{}
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+
+				vol := raw.(*structs.CSIVolume)
+				if vol.Namespace != args.RequestNamespace() {
+					continue
+				}
+				if prefix := args.QueryOptions.Prefix; prefix != "" && !strings.HasPrefix(vol.ID, prefix) {
+					continue
+				}
+
+				vol, err = state.CSIVolumeDenormalize(ws, vol)
+				if err != nil {
+					return err
+				}
+				volumes = append(volumes, vol.Stub())
+			}
+			reply.Volumes = volumes
+
+			// Use the last index that affected the volumes or nodes tables
+			index, err := state.Index("csi_volumes")
+			if err != nil {
+				return err
+			}
+			nodeIndex, err := state.Index("nodes")
+			if err != nil {
+				return err
+			}
+			if nodeIndex > index {
+				index = nodeIndex
+			}
+			reply.Index = index
+
+			// Set the query response
+			v.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// Get is used to lookup a volume
+func (v *CSIVolume) Get(args *structs.CSIVolumeGetRequest, reply *structs.CSIVolumeGetResponse) error {
+	if done, err := v.srv.forward("CSIVolume.Get", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "get"}, time.Now())
+
+	// Check namespace csi-read-volume permissions
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityCSIReadVolume) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			vol, err := state.CSIVolumeByID(ws, args.RequestNamespace(), args.ID)
+			if err != nil {
+				return err
+			}
+
+			reply.Volume = vol
+			if vol != nil {
+				reply.Index = vol.ModifyIndex
+			} else {
+				// Use the last index that affected the volumes table
+				index, err := state.Index("csi_volumes")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+
+			// Set the query response
+			v.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// Claim is used by clients to claim a volume for an allocation before
+// mounting it, and to release the claim once the volume is unmounted. If the
+// volume's plugin requires a controller, the volume is attached to the
+// allocation's node on claim and detached on release.
+func (v *CSIVolume) Claim(args *structs.CSIVolumeClaimRequest, reply *structs.CSIVolumeClaimResponse) error {
+	if done, err := v.srv.forward("CSIVolume.Claim", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "claim"}, time.Now())
+
+	if args.VolumeID == "" {
+		return fmt.Errorf("missing volume ID")
+	}
+
+	snap, err := v.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	if args.Claim == structs.CSIVolumeClaimRelease {
+		return v.release(snap, args, reply)
+	}
+
+	// Only the node running the allocation may claim volumes for it
+	alloc, err := nodeAllocByID(snap, args.NodeID, args.SecretID, args.AllocationID)
+	if err != nil {
+		return err
+	}
+
+	// The allocation must request the volume
+	if err := csiVolumeRequested(alloc, args.VolumeID, args.Claim); err != nil {
+		return err
+	}
+	args.Namespace = alloc.Namespace
+
+	vol, err := snap.CSIVolumeByID(nil, alloc.Namespace, args.VolumeID)
+	if err != nil {
+		return err
+	}
+	if vol == nil {
+		return fmt.Errorf("volume %q not found", args.VolumeID)
+	}
+	if !vol.Schedulable {
+		return fmt.Errorf("volume %q is not schedulable, its plugin %q is unhealthy", vol.ID, vol.PluginID)
+	}
+
+	// Commit the claim via Raft
+	resp, index, err := v.srv.raftApply(structs.CSIVolumeClaimRequestType, args)
+	if err != nil {
+		v.srv.logger.Printf("[ERR] nomad.csi_volume: claim failed: %v", err)
+		return err
+	}
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	if vol.ControllerRequired {
+		readOnly := args.Claim == structs.CSIVolumeClaimRead
+		publishContext, err := v.controllerAttach(snap, vol, args.NodeID, readOnly)
+		if err != nil {
+			// Release the claim so that it doesn't block other allocations
+			args.Claim = structs.CSIVolumeClaimRelease
+			if _, _, rerr := v.srv.raftApply(structs.CSIVolumeClaimRequestType, args); rerr != nil {
+				v.srv.logger.Printf("[ERR] nomad.csi_volume: releasing claim of alloc %q on volume %q failed: %v",
+					args.AllocationID, vol.ID, rerr)
+			}
+			return fmt.Errorf("attaching volume %q failed: %v", vol.ID, err)
+		}
+		reply.PublishContext = publishContext
+	}
+
+	reply.Volume = vol
+	reply.Index = index
+	v.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// release releases the claim of an allocation on a volume. The allocation
+// may already be stopped.
+func (v *CSIVolume) release(snap *state.StateSnapshot, args *structs.CSIVolumeClaimRequest, reply *structs.CSIVolumeClaimResponse) error {
+	node, err := snap.NodeByID(nil, args.NodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("Node %q does not exist", args.NodeID)
+	}
+	if node.SecretID != args.SecretID {
+		return structs.ErrPermissionDenied
+	}
+
+	alloc, err := snap.AllocByID(nil, args.AllocationID)
+	if err != nil {
+		return err
+	}
+	if alloc != nil {
+		if alloc.NodeID != args.NodeID {
+			return fmt.Errorf("Allocation %q not running on Node %q", args.AllocationID, args.NodeID)
+		}
+		args.Namespace = alloc.Namespace
+	}
+
+	vol, err := snap.CSIVolumeByID(nil, args.RequestNamespace(), args.VolumeID)
+	if err != nil {
+		return err
+	}
+	if vol == nil {
+		// The volume was deregistered, there's nothing to release
+		return nil
+	}
+
+	index, err := v.releaseClaim(snap, vol, node, args)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	v.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// releaseClaim detaches the volume from the node once no other allocation on
+// the node claims it, and then releases the claim. The claim is kept if the
+// volume can't be detached, so that the volume isn't claimed by another node
+// while it is still attached.
+func (v *CSIVolume) releaseClaim(snap *state.StateSnapshot, vol *structs.CSIVolume, node *structs.Node, args *structs.CSIVolumeClaimRequest) (uint64, error) {
+	// The claim may already be released
+	_, read := vol.ReadClaims[args.AllocationID]
+	_, write := vol.WriteClaims[args.AllocationID]
+	if !read && !write {
+		return 0, nil
+	}
+
+	if vol.ControllerRequired && node != nil && !csiVolumeClaimedOnNode(vol, node.ID, args.AllocationID) {
+		if err := v.controllerDetach(snap, vol, node); err != nil {
+			return 0, fmt.Errorf("detaching volume %q failed: %v", vol.ID, err)
+		}
+	}
+
+	resp, index, err := v.srv.raftApply(structs.CSIVolumeClaimRequestType, args)
+	if err != nil {
+		v.srv.logger.Printf("[ERR] nomad.csi_volume: release failed: %v", err)
+		return 0, err
+	}
+	if respErr, ok := resp.(error); ok {
+		return 0, respErr
+	}
+	return index, nil
+}
+
+// csiVolumeClaimedOnNode returns whether an allocation other than the given
+// one claims the volume on the node.
+func csiVolumeClaimedOnNode(vol *structs.CSIVolume, nodeID, allocID string) bool {
+	for _, claims := range []map[string]*structs.CSIVolumeClaim{vol.ReadClaims, vol.WriteClaims} {
+		for id, c := range claims {
+			if id != allocID && c.NodeID == nodeID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// csiVolumeRequested returns an error if the allocation's task group doesn't
+// request the volume with the claim's mode.
+func csiVolumeRequested(alloc *structs.Allocation, volumeID string, mode structs.CSIVolumeClaimMode) error {
+	if alloc.Job == nil {
+		return fmt.Errorf("allocation %q has no job", alloc.ID)
+	}
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return fmt.Errorf("allocation %q has no task group", alloc.ID)
+	}
+
+	for _, req := range tg.Volumes {
+		if req.Type != structs.VolumeTypeCSI || req.Source != volumeID {
+			continue
+		}
+		if mode == structs.CSIVolumeClaimWrite && req.ReadOnly {
+			return fmt.Errorf("allocation %q requests volume %q read only", alloc.ID, volumeID)
+		}
+		return nil
+	}
+	return fmt.Errorf("allocation %q doesn't request volume %q", alloc.ID, volumeID)
+}
+
+// controllerAttach attaches the volume to the node using one of the healthy
+// controllers of the volume's plugin, and returns the publish context.
+func (v *CSIVolume) controllerAttach(snap *state.StateSnapshot, vol *structs.CSIVolume, nodeID string, readOnly bool) (map[string]string, error) {
+	node, err := snap.NodeByID(nil, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	csiNodeID, err := csiClientNodeID(node, vol.PluginID)
+	if err != nil {
+		return nil, err
+	}
+	controllerNodeID, err := csiControllerNodeID(snap, vol.PluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &cstructs.ClientCSIControllerAttachVolumeRequest{
+		ControllerNodeID: controllerNodeID,
+		PluginID:         vol.PluginID,
+		VolumeID:         vol.ExternalID,
+		ClientCSINodeID:  csiNodeID,
+		AttachmentMode:   vol.AttachmentMode,
+		AccessMode:       vol.AccessMode,
+		MountOptions:     vol.MountOptions,
+		ReadOnly:         readOnly,
+		VolumeContext:    vol.Context,
+		QueryOptions: structs.QueryOptions{
+			Region:    v.srv.Region(),
+			AuthToken: v.srv.getLeaderAcl(),
+		},
+	}
+	var resp cstructs.ClientCSIControllerAttachVolumeResponse
+	if err := v.srv.RPC("ClientCSI.ControllerAttachVolume", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.PublishContext, nil
+}
+
+// controllerDetach detaches the volume from the node using one of the healthy
+// controllers of the volume's plugin.
+func (v *CSIVolume) controllerDetach(snap *state.StateSnapshot, vol *structs.CSIVolume, node *structs.Node) error {
+	csiNodeID, err := csiClientNodeID(node, vol.PluginID)
+	if err != nil {
+		return err
+	}
+	controllerNodeID, err := csiControllerNodeID(snap, vol.PluginID)
+	if err != nil {
+		return err
+	}
+
+	req := &cstructs.ClientCSIControllerDetachVolumeRequest{
+		ControllerNodeID: controllerNodeID,
+		PluginID:         vol.PluginID,
+		VolumeID:         vol.ExternalID,
+		ClientCSINodeID:  csiNodeID,
+		QueryOptions: structs.QueryOptions{
+			Region:    v.srv.Region(),
+			AuthToken: v.srv.getLeaderAcl(),
+		},
+	}
+	var resp cstructs.ClientCSIControllerDetachVolumeResponse
+	return v.srv.RPC("ClientCSI.ControllerDetachVolume", req, &resp)
+}
+
+// csiClientNodeID returns the identity of the node as known by the plugin's
+// storage provider.
+func csiClientNodeID(node *structs.Node, pluginID string) (string, error) {
+	if node == nil {
+		return "", fmt.Errorf("node not found")
+	}
+	info, ok := node.CSINodePlugins[pluginID]
+	if !ok || info.NodeInfo == nil {
+		return "", fmt.Errorf("node %q doesn't run the node plugin %q", node.ID, pluginID)
+	}
+	return info.NodeInfo.ID, nil
+}
+
+// csiControllerNodeID returns the ID of a node running a healthy controller of
+// the plugin.
+func csiControllerNodeID(snap *state.StateSnapshot, pluginID string) (string, error) {
+	plugin, err := snap.CSIPluginByID(nil, pluginID)
+	if err != nil {
+		return "", err
+	}
+	if plugin == nil {
+		return "", fmt.Errorf("plugin %q not found", pluginID)
+	}
+
+	for nodeID, info := range plugin.Controllers {
+		if !info.Healthy {
+			continue
+		}
+		node, err := snap.NodeByID(nil, nodeID)
+		if err != nil {
+			return "", err
+		}
+		if node != nil && node.Status == structs.NodeStatusReady {
+			return nodeID, nil
+		}
+	}
+	return "", fmt.Errorf("plugin %q has no healthy controller", pluginID)
+}
+
+// CSIPlugin endpoint is used for querying the CSI plugins running on the
+// clients
+type CSIPlugin struct {
+	srv *Server
+}
+
+// List is used to list the plugins
+func (p *CSIPlugin) List(args *structs.CSIPluginListRequest, reply *structs.CSIPluginListResponse) error {
+	if done, err := p.srv.forward("CSIPlugin.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "plugin", "list"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := p.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			plugins, err := state.CSIPlugins(ws)
+			if err != nil {
+				return err
+			}
+
+			stubs := []*structs.CSIPluginListStub{}
+			for _, plugin := range plugins {
+				if prefix := args.QueryOptions.Prefix; prefix != "" && !strings.HasPrefix(plugin.ID, prefix) {
+					continue
+				}
+				stubs = append(stubs, plugin.Stub())
+			}
+			reply.Plugins = stubs
+
+			// Plugins are collected from the nodes
+			index, err := state.Index("nodes")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			p.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return p.srv.blockingRPC(&opts)
+}
+
+// Get is used to lookup a plugin
+func (p *CSIPlugin) Get(args *structs.CSIPluginGetRequest, reply *structs.CSIPluginGetResponse) error {
+	if done, err := p.srv.forward("CSIPlugin.Get", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "plugin", "get"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := p.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			plugin, err := state.CSIPluginByID(ws, args.ID)
+			if err != nil {
+				return err
+			}
+			reply.Plugin = plugin
+
+			// Plugins are collected from the nodes
+			index, err := state.Index("nodes")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			p.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return p.srv.blockingRPC(&opts)
+}
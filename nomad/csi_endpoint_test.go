@@ -0,0 +1,217 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testCSINode returns a node running the node service of the "minnie"
+// plugin, which doesn't require a controller
+func testCSINode() *structs.Node {
+	node := mock.CSINode()
+	node.CSIControllerPlugins = nil
+	node.CSINodePlugins["minnie"].RequiresControllerPlugin = false
+	return node
+}
+
+func TestCSIVolumeEndpoint_Register(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := testCSINode()
+	require.Nil(state.UpsertNode(1000, node))
+
+	vol := mock.CSIVolume()
+	req := &structs.CSIVolumeRegisterRequest{
+		Volumes:      []*structs.CSIVolume{vol},
+		WriteRequest: structs.WriteRequest{Region: "global", Namespace: vol.Namespace},
+	}
+	var resp structs.CSIVolumeRegisterResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "CSIVolume.Register", req, &resp))
+	require.NotZero(resp.Index)
+
+	// Invalid volumes are rejected
+	invalid := mock.CSIVolume()
+	invalid.AccessMode = "anything-goes"
+	req.Volumes = []*structs.CSIVolume{invalid}
+	require.Error(msgpackrpc.CallWithCodec(codec, "CSIVolume.Register", req, &resp))
+
+	get := &structs.CSIVolumeGetRequest{
+		ID:           vol.ID,
+		QueryOptions: structs.QueryOptions{Region: "global", Namespace: vol.Namespace},
+	}
+	var getResp structs.CSIVolumeGetResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "CSIVolume.Get", get, &getResp))
+	require.NotNil(getResp.Volume)
+	require.Equal(vol.ExternalID, getResp.Volume.ExternalID)
+	require.True(getResp.Volume.Schedulable)
+	require.Equal(1, getResp.Volume.NodesHealthy)
+
+	list := &structs.CSIVolumeListRequest{
+		PluginID:     "minnie",
+		QueryOptions: structs.QueryOptions{Region: "global", Namespace: vol.Namespace},
+	}
+	var listResp structs.CSIVolumeListResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "CSIVolume.List", list, &listResp))
+	require.Len(listResp.Volumes, 1)
+	require.Equal(vol.ID, listResp.Volumes[0].ID)
+
+	dereg := &structs.CSIVolumeDeregisterRequest{
+		VolumeIDs:    []string{vol.ID},
+		WriteRequest: structs.WriteRequest{Region: "global", Namespace: vol.Namespace},
+	}
+	var deregResp structs.CSIVolumeDeregisterResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "CSIVolume.Deregister", dereg, &deregResp))
+
+	out, err := state.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	require.Nil(err)
+	require.Nil(out)
+}
+
+func TestCSIVolumeEndpoint_Claim(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := testCSINode()
+	require.Nil(state.UpsertNode(1000, node))
+
+	vol := mock.CSIVolume()
+	require.Nil(state.CSIVolumeRegister(1001, []*structs.CSIVolume{vol}))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.Job.TaskGroups[0].Volumes = map[string]*structs.VolumeRequest{
+		"data": {Name: "data", Type: structs.VolumeTypeCSI, Source: vol.ID},
+	}
+	other := mock.Alloc()
+	other.NodeID = node.ID
+	require.Nil(state.UpsertJob(1002, alloc.Job))
+	require.Nil(state.UpsertAllocs(1003, []*structs.Allocation{alloc, other}))
+
+	req := &structs.CSIVolumeClaimRequest{
+		VolumeID:     vol.ID,
+		AllocationID: alloc.ID,
+		NodeID:       node.ID,
+		SecretID:     node.SecretID,
+		Claim:        structs.CSIVolumeClaimWrite,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.CSIVolumeClaimResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "CSIVolume.Claim", req, &resp))
+	require.NotNil(resp.Volume)
+	require.Equal(vol.ExternalID, resp.Volume.ExternalID)
+
+	out, err := state.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	require.Nil(err)
+	require.Contains(out.WriteClaims, alloc.ID)
+
+	// Allocations can only claim the volumes they request
+	req.AllocationID = other.ID
+	err = msgpackrpc.CallWithCodec(codec, "CSIVolume.Claim", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "doesn't request volume")
+
+	// Only the node running the allocation may claim for it
+	req.AllocationID = alloc.ID
+	req.SecretID = "wrong"
+	require.Error(msgpackrpc.CallWithCodec(codec, "CSIVolume.Claim", req, &resp))
+
+	req.SecretID = node.SecretID
+	req.Claim = structs.CSIVolumeClaimRelease
+	require.Nil(msgpackrpc.CallWithCodec(codec, "CSIVolume.Claim", req, &resp))
+
+	out, err = state.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	require.Nil(err)
+	require.False(out.InUse())
+}
+
+func TestCSIVolumeEndpoint_ReleasePastClaims(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := testCSINode()
+	require.Nil(state.UpsertNode(1000, node))
+
+	vol := mock.CSIVolume()
+	require.Nil(state.CSIVolumeRegister(1001, []*structs.CSIVolume{vol}))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	require.Nil(state.UpsertJob(1002, alloc.Job))
+	require.Nil(state.UpsertAllocs(1003, []*structs.Allocation{alloc}))
+
+	claim := &structs.CSIVolumeClaim{
+		AllocationID: alloc.ID,
+		NodeID:       node.ID,
+		Mode:         structs.CSIVolumeClaimWrite,
+	}
+	require.Nil(state.CSIVolumeClaim(1004, vol.Namespace, vol.ID, claim))
+
+	// Claims of running allocations are kept
+	require.Nil(s1.releasePastCSIVolumeClaims())
+	out, err := state.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	require.Nil(err)
+	require.Contains(out.WriteClaims, alloc.ID)
+
+	// Claims of allocations stopped on a down node are released
+	require.Nil(state.UpdateNodeStatus(1005, node.ID, structs.NodeStatusDown, nil))
+	stopped := alloc.Copy()
+	stopped.ClientStatus = structs.AllocClientStatusLost
+	require.Nil(state.UpdateAllocsFromClient(1006, []*structs.Allocation{stopped}))
+
+	require.Nil(s1.releasePastCSIVolumeClaims())
+	out, err = state.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	require.Nil(err)
+	require.False(out.InUse())
+}
+
+func TestCSIPluginEndpoint_List(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	require.Nil(state.UpsertNode(1000, mock.CSINode()))
+	require.Nil(state.UpsertNode(1001, testCSINode()))
+
+	req := &structs.CSIPluginListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.CSIPluginListResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "CSIPlugin.List", req, &resp))
+	require.Len(resp.Plugins, 1)
+	require.Equal("minnie", resp.Plugins[0].ID)
+	require.Equal(1, resp.Plugins[0].ControllersHealthy)
+	require.Equal(2, resp.Plugins[0].NodesHealthy)
+
+	get := &structs.CSIPluginGetRequest{
+		ID:           "minnie",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.CSIPluginGetResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "CSIPlugin.Get", get, &getResp))
+	require.NotNil(getResp.Plugin)
+	require.Len(getResp.Plugin.Nodes, 2)
+}
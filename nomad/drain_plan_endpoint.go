@@ -0,0 +1,367 @@
+package nomad
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// drainPlanUpdateRetries is the number of times a drain plan status update is
+// retried when the plan is modified concurrently.
+const drainPlanUpdateRetries = 5
+
+// DrainPlan endpoint is used for draining groups of nodes in batches
+type DrainPlan struct {
+	srv *Server
+}
+
+// Create is used to create a drain plan for the nodes matching the plan's
+// filter. The nodes are drained by the leader's drain plan watcher.
+func (d *DrainPlan) Create(args *structs.DrainPlanCreateRequest, reply *structs.DrainPlanUpdateResponse) error {
+	if done, err := d.srv.forward("DrainPlan.Create", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "drain_plan", "create"}, time.Now())
+
+	// Check node write permissions
+	aclObj, err := d.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowAnyNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.Plan == nil {
+		return fmt.Errorf("missing drain plan")
+	}
+	plan := args.Plan.Copy()
+	if err := plan.Validate(); err != nil {
+		return err
+	}
+
+	snap, err := d.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Capture the nodes already targeted by other plans
+	ws := memdb.NewWatchSet()
+	planned, err := activeDrainPlanNodes(ws, snap)
+	if err != nil {
+		return err
+	}
+
+	// Select the nodes of the plan
+	iter, err := snap.Nodes(ws)
+	if err != nil {
+		return err
+	}
+
+	var nodes []*structs.Node
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		node := raw.(*structs.Node)
+		if !plan.Filter.Matches(node) {
+			continue
+		}
+
+		// Check node class write permissions
+		if aclObj != nil && !aclObj.AllowNodeClassWrite(node.NodeClass) {
+			return structs.ErrPermissionDenied
+		}
+
+		if other, ok := planned[node.ID]; ok {
+			return fmt.Errorf("node %q is already being drained by drain plan %q", node.ID, other)
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("no nodes match the drain plan filter")
+	}
+
+	// Drain the nodes in a stable order
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Name != nodes[j].Name {
+			return nodes[i].Name < nodes[j].Name
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	plan.ID = uuid.Generate()
+	plan.Status = structs.DrainPlanStatusRunning
+	plan.StatusDescription = structs.DrainPlanStatusDescriptionRunning
+	plan.Nodes = make([]*structs.DrainPlanNode, len(nodes))
+	for i, node := range nodes {
+		plan.Nodes[i] = &structs.DrainPlanNode{
+			NodeID: node.ID,
+			Name:   node.Name,
+			Status: structs.DrainPlanNodeStatusPending,
+		}
+	}
+
+	// Commit this update via Raft
+	req := &structs.DrainPlanUpsertRequest{
+		Plan:         plan,
+		WriteRequest: args.WriteRequest,
+	}
+	_, index, err := d.srv.raftApply(structs.DrainPlanUpsertRequestType, req)
+	if err != nil {
+		d.srv.logger.Printf("[ERR] nomad.drain_plan: create failed: %v", err)
+		return err
+	}
+
+	reply.PlanID = plan.ID
+	reply.PlanModifyIndex = index
+	reply.Index = index
+	return nil
+}
+
+// UpdateStatus is used to pause, resume or cancel a drain plan. Node drains
+// already started by the plan are not affected.
+func (d *DrainPlan) UpdateStatus(args *structs.DrainPlanStatusUpdateRequest, reply *structs.DrainPlanUpdateResponse) error {
+	if done, err := d.srv.forward("DrainPlan.UpdateStatus", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "drain_plan", "update_status"}, time.Now())
+
+	// Check node write permissions
+	aclObj, err := d.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowAnyNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.PlanID == "" {
+		return fmt.Errorf("missing drain plan ID")
+	}
+
+	// The update is rejected if the drain plan watcher progressed the plan
+	// after it was read, so retry with the latest plan.
+	for attempt := 0; ; attempt++ {
+		index, err := d.updateStatus(aclObj, args)
+		if structs.IsErrDrainPlanModified(err) && attempt < drainPlanUpdateRetries {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		reply.PlanID = args.PlanID
+		reply.PlanModifyIndex = index
+		reply.Index = index
+		return nil
+	}
+}
+
+// updateStatus applies the status update to the current version of the drain
+// plan and returns the index it was committed at.
+func (d *DrainPlan) updateStatus(aclObj *acl.ACL, args *structs.DrainPlanStatusUpdateRequest) (uint64, error) {
+	snap, err := d.srv.fsm.State().Snapshot()
+	if err != nil {
+		return 0, err
+	}
+	existing, err := snap.DrainPlanByID(nil, args.PlanID)
+	if err != nil {
+		return 0, err
+	}
+	if existing == nil {
+		return 0, fmt.Errorf("drain plan not found")
+	}
+
+	// Check node class write permissions for the nodes of the plan
+	if aclObj != nil {
+		for _, n := range existing.Nodes {
+			node, err := snap.NodeByID(nil, n.NodeID)
+			if err != nil {
+				return 0, err
+			}
+			if node != nil && !aclObj.AllowNodeClassWrite(node.NodeClass) {
+				return 0, structs.ErrPermissionDenied
+			}
+		}
+	}
+
+	plan := existing.Copy()
+	switch args.Status {
+	case structs.DrainPlanStatusPaused:
+		if existing.Status != structs.DrainPlanStatusRunning {
+			return 0, fmt.Errorf("can't pause drain plan with status %q", existing.Status)
+		}
+		plan.StatusDescription = structs.DrainPlanStatusDescriptionPaused
+	case structs.DrainPlanStatusRunning:
+		if existing.Status != structs.DrainPlanStatusPaused {
+			return 0, fmt.Errorf("can't resume drain plan with status %q", existing.Status)
+		}
+		plan.StatusDescription = structs.DrainPlanStatusDescriptionResumed
+	case structs.DrainPlanStatusCancelled:
+		if !existing.Active() {
+			return 0, fmt.Errorf("can't cancel drain plan with status %q", existing.Status)
+		}
+		plan.StatusDescription = structs.DrainPlanStatusDescriptionCancelled
+	default:
+		return 0, fmt.Errorf("invalid drain plan status %q", args.Status)
+	}
+	plan.Status = args.Status
+
+	// Commit this update via Raft
+	req := &structs.DrainPlanUpsertRequest{
+		Plan:            plan,
+		PlanModifyIndex: existing.ModifyIndex,
+		WriteRequest:    args.WriteRequest,
+	}
+	resp, index, err := d.srv.raftApply(structs.DrainPlanUpsertRequestType, req)
+	if err != nil {
+		d.srv.logger.Printf("[ERR] nomad.drain_plan: status update failed: %v", err)
+		return 0, err
+	}
+	if err, ok := resp.(error); ok && err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+// GetPlan is used to request information about a specific drain plan
+func (d *DrainPlan) GetPlan(args *structs.DrainPlanSpecificRequest, reply *structs.SingleDrainPlanResponse) error {
+	if done, err := d.srv.forward("DrainPlan.GetPlan", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "drain_plan", "get_plan"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := d.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowAnyNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Verify the arguments
+			if args.PlanID == "" {
+				return fmt.Errorf("missing drain plan ID")
+			}
+
+			out, err := state.DrainPlanByID(ws, args.PlanID)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Plan = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the drain plans table
+				index, err := state.Index("drain_plans")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+
+			// Set the query response
+			d.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return d.srv.blockingRPC(&opts)
+}
+
+// List is used to list the drain plans
+func (d *DrainPlan) List(args *structs.DrainPlanListRequest, reply *structs.DrainPlanListResponse) error {
+	if done, err := d.srv.forward("DrainPlan.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "drain_plan", "list"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := d.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowAnyNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.DrainPlansByIDPrefix(ws, prefix)
+			} else {
+				iter, err = state.DrainPlans(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			var plans []*structs.DrainPlanListStub
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				plan := raw.(*structs.DrainPlan)
+				plans = append(plans, plan.Stub())
+			}
+			reply.Plans = plans
+
+			// Use the last index that affected the drain plans table
+			index, err := state.Index("drain_plans")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			d.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return d.srv.blockingRPC(&opts)
+}
+
+// activeDrainPlanNodes returns the nodes that are still to be drained by an
+// active drain plan, mapped to the ID of the plan.
+func activeDrainPlanNodes(ws memdb.WatchSet, snap *state.StateSnapshot) (map[string]string, error) {
+	iter, err := snap.DrainPlans(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]string)
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		plan := raw.(*structs.DrainPlan)
+		if !plan.Active() {
+			continue
+		}
+		for _, n := range plan.Nodes {
+			if n.Status != structs.DrainPlanNodeStatusComplete {
+				nodes[n.NodeID] = plan.ID
+			}
+		}
+	}
+	return nodes, nil
+}
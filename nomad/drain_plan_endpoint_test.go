@@ -0,0 +1,218 @@
+package nomad
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainPlanEndpoint_Create(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create two nodes in the targeted datacenter and one outside of it
+	n1, n2, n3 := mock.Node(), mock.Node(), mock.Node()
+	n1.Datacenter, n2.Datacenter, n3.Datacenter = "dc2", "dc2", "dc3"
+	for _, n := range []*structs.Node{n1, n2, n3} {
+		nodeReg := &structs.NodeRegisterRequest{
+			Node:         n,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var nodeResp structs.NodeUpdateResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", nodeReg, &nodeResp))
+	}
+
+	req := &structs.DrainPlanCreateRequest{
+		Plan: &structs.DrainPlan{
+			Filter:      &structs.DrainPlanFilter{Datacenter: "dc2"},
+			DrainSpec:   structs.DrainSpec{Deadline: time.Hour},
+			MaxParallel: 1,
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.DrainPlanUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "DrainPlan.Create", req, &resp))
+	require.NotEmpty(resp.PlanID)
+	require.NotZero(resp.Index)
+
+	plan, err := state.DrainPlanByID(nil, resp.PlanID)
+	require.Nil(err)
+	require.Len(plan.Nodes, 2)
+
+	// The nodes have no allocations so the leader drains both of them
+	testutil.WaitForResult(func() (bool, error) {
+		plan, err := state.DrainPlanByID(nil, resp.PlanID)
+		if err != nil {
+			return false, err
+		}
+		if plan.Status != structs.DrainPlanStatusComplete {
+			return false, fmt.Errorf("plan status %q", plan.Status)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	for _, id := range []string{n1.ID, n2.ID} {
+		node, err := state.NodeByID(nil, id)
+		require.Nil(err)
+		require.Equal(structs.NodeSchedulingIneligible, node.SchedulingEligibility)
+	}
+	node, err := state.NodeByID(nil, n3.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingEligible, node.SchedulingEligibility)
+
+	// Nodes can't be part of two active plans
+	active := mock.DrainPlan()
+	active.Status = structs.DrainPlanStatusPaused
+	active.Nodes[0].NodeID = n3.ID
+	require.Nil(state.UpsertDrainPlan(2000, &structs.DrainPlanUpsertRequest{Plan: active}))
+
+	req.Plan.Filter.Datacenter = "dc3"
+	err = msgpackrpc.CallWithCodec(codec, "DrainPlan.Create", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "already being drained")
+
+	// An empty filter is rejected
+	req.Plan.Filter = nil
+	err = msgpackrpc.CallWithCodec(codec, "DrainPlan.Create", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "must filter nodes")
+}
+
+func TestDrainPlanEndpoint_UpdateStatus(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a paused plan so the leader does not progress it
+	plan := mock.DrainPlan()
+	plan.Status = structs.DrainPlanStatusPaused
+	require.Nil(state.UpsertDrainPlan(1000, &structs.DrainPlanUpsertRequest{Plan: plan}))
+
+	update := func(status string) error {
+		req := &structs.DrainPlanStatusUpdateRequest{
+			PlanID:       plan.ID,
+			Status:       status,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.DrainPlanUpdateResponse
+		return msgpackrpc.CallWithCodec(codec, "DrainPlan.UpdateStatus", req, &resp)
+	}
+
+	// A paused plan can't be paused again
+	require.Error(update(structs.DrainPlanStatusPaused))
+
+	// Cancel the plan
+	require.Nil(update(structs.DrainPlanStatusCancelled))
+	out, err := state.DrainPlanByID(nil, plan.ID)
+	require.Nil(err)
+	require.Equal(structs.DrainPlanStatusCancelled, out.Status)
+	require.Equal(structs.DrainPlanStatusDescriptionCancelled, out.StatusDescription)
+
+	// A cancelled plan can't be resumed
+	err = update(structs.DrainPlanStatusRunning)
+	require.Error(err)
+	require.Contains(err.Error(), "can't resume")
+
+	// Unknown statuses are rejected
+	require.Error(update(structs.DrainPlanStatusComplete))
+}
+
+func TestDrainPlanEndpoint_GetPlan_List(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	plan := mock.DrainPlan()
+	plan.Status = structs.DrainPlanStatusCancelled
+	require.Nil(state.UpsertDrainPlan(1000, &structs.DrainPlanUpsertRequest{Plan: plan}))
+
+	get := &structs.DrainPlanSpecificRequest{
+		PlanID:       plan.ID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.SingleDrainPlanResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "DrainPlan.GetPlan", get, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Equal(plan, resp.Plan)
+
+	list := &structs.DrainPlanListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", Prefix: plan.ID[:4]},
+	}
+	var listResp structs.DrainPlanListResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "DrainPlan.List", list, &listResp))
+	require.EqualValues(1000, listResp.Index)
+	require.Len(listResp.Plans, 1)
+	require.Equal(1, listResp.Plans[0].TotalNodes)
+	require.Equal(1, listResp.Plans[0].PendingNodes)
+}
+
+func TestDrainPlanEndpoint_Create_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	n1, n2 := mock.Node(), mock.Node()
+	n1.Datacenter, n2.Datacenter = "dc2", "dc2"
+	n1.NodeClass, n2.NodeClass = "web", "batch"
+	require.Nil(state.UpsertNode(1000, n1))
+	require.Nil(state.UpsertNode(1001, n2))
+
+	// A token that may only write nodes of the web class
+	webToken := mock.CreatePolicyAndToken(t, state, 1002, "test-web",
+		mock.NodeClassPolicy("web", "write"))
+
+	req := &structs.DrainPlanCreateRequest{
+		Plan: &structs.DrainPlan{
+			Filter:      &structs.DrainPlanFilter{Datacenter: "dc2"},
+			MaxParallel: 1,
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+
+	// Try without a token
+	var resp structs.DrainPlanUpdateResponse
+	err := msgpackrpc.CallWithCodec(codec, "DrainPlan.Create", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// The plan targets a node the token can't write
+	req.AuthToken = webToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "DrainPlan.Create", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Narrowing the plan to the web class succeeds
+	req.Plan.Filter.NodeClass = "web"
+	require.Nil(msgpackrpc.CallWithCodec(codec, "DrainPlan.Create", req, &resp))
+
+	// Listing with the root token succeeds
+	list := &structs.DrainPlanListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: root.SecretID},
+	}
+	var listResp structs.DrainPlanListResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "DrainPlan.List", list, &listResp))
+	require.Len(listResp.Plans, 1)
+}
@@ -0,0 +1,438 @@
+package drainer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DrainPlanEventNodeDrainSet is the node event emitted when a drain plan
+	// starts draining a node.
+	DrainPlanEventNodeDrainSet = "Node drain strategy set by drain plan"
+
+	// DrainPlanEventDetailPlanID is the node event detail key holding the ID
+	// of the drain plan that started the drain.
+	DrainPlanEventDetailPlanID = "drain_plan_id"
+)
+
+// DrainPlanRaftApplier contains the raft requests required by the
+// DrainPlanWatcher.
+type DrainPlanRaftApplier interface {
+	UpsertDrainPlan(req *structs.DrainPlanUpsertRequest) (uint64, error)
+}
+
+// DrainPlanWatcher is used to progress drain plans. Nodes of a running plan
+// are drained until MaxParallel nodes are in progress and a node only stops
+// counting against the limit once the node drainer has finished with it and
+// the allocations migrated off of it are healthy.
+type DrainPlanWatcher struct {
+	enabled bool
+	logger  *log.Logger
+
+	// state is the state that is watched for state changes.
+	state *state.StateStore
+
+	// queryLimiter is used to limit the rate of blocking queries
+	queryLimiter *rate.Limiter
+
+	// raft is a shim around the raft messages necessary for progressing
+	// plans
+	raft DrainPlanRaftApplier
+
+	// ctx and exitFn are used to cancel the watcher
+	ctx    context.Context
+	exitFn context.CancelFunc
+
+	l sync.Mutex
+}
+
+// NewDrainPlanWatcher returns a new drain plan watcher.
+func NewDrainPlanWatcher(logger *log.Logger, raft DrainPlanRaftApplier, stateQueriesPerSecond float64) *DrainPlanWatcher {
+	return &DrainPlanWatcher{
+		logger:       logger,
+		raft:         raft,
+		queryLimiter: rate.NewLimiter(rate.Limit(stateQueriesPerSecond), 100),
+	}
+}
+
+// SetEnabled will start or stop the drain plan watcher depending on the
+// enabled boolean.
+func (w *DrainPlanWatcher) SetEnabled(enabled bool, state *state.StateStore) {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	if w.exitFn != nil {
+		w.exitFn()
+	}
+
+	w.enabled = enabled
+	if !enabled {
+		return
+	}
+
+	if state != nil {
+		w.state = state
+	}
+	w.ctx, w.exitFn = context.WithCancel(context.Background())
+	go w.watch(w.ctx)
+}
+
+// watch is the long lived watching routine that progresses active plans
+// whenever plans, nodes or allocations change.
+func (w *DrainPlanWatcher) watch(ctx context.Context) {
+	index := uint64(1)
+	for {
+		plans, newIndex, err := w.getActivePlans(ctx, index)
+		if err != nil {
+			if err == context.Canceled {
+				w.logger.Printf("[TRACE] nomad.drain.plan_watcher: shutting down")
+				return
+			}
+
+			w.logger.Printf("[ERR] nomad.drain.plan_watcher: error watching drain plans at index %d: %v", index, err)
+			select {
+			case <-ctx.Done():
+				w.logger.Printf("[TRACE] nomad.drain.plan_watcher: shutting down")
+				return
+			case <-time.After(stateReadErrorDelay):
+				continue
+			}
+		}
+		index = newIndex
+
+		if len(plans) == 0 {
+			continue
+		}
+
+		snap, err := w.state.Snapshot()
+		if err != nil {
+			w.logger.Printf("[ERR] nomad.drain.plan_watcher: failed to snapshot state: %v", err)
+			continue
+		}
+
+		for _, plan := range plans {
+			req, err := ProgressDrainPlan(snap, plan, time.Now())
+			if err != nil {
+				w.logger.Printf("[ERR] nomad.drain.plan_watcher: failed to progress drain plan %q: %v", plan.ID, err)
+				continue
+			}
+			if req == nil {
+				continue
+			}
+
+			if _, err := w.raft.UpsertDrainPlan(req); err != nil {
+				// The plan is progressed again once the update that modified
+				// it is seen
+				if structs.IsErrDrainPlanModified(err) {
+					w.logger.Printf("[DEBUG] nomad.drain.plan_watcher: drain plan %q was modified, retrying", plan.ID)
+					continue
+				}
+				w.logger.Printf("[ERR] nomad.drain.plan_watcher: failed to update drain plan %q: %v", plan.ID, err)
+				continue
+			}
+			w.logger.Printf("[DEBUG] nomad.drain.plan_watcher: drain plan %q started draining %d nodes; status %q",
+				plan.ID, len(req.Updates), req.Plan.Status)
+		}
+	}
+}
+
+// getActivePlans returns the active drain plans, blocking until plans, nodes
+// or allocations change after the given index.
+func (w *DrainPlanWatcher) getActivePlans(ctx context.Context, minIndex uint64) ([]*structs.DrainPlan, uint64, error) {
+	if err := w.queryLimiter.Wait(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	resp, index, err := w.state.BlockingQuery(w.getActivePlansImpl, minIndex, ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return resp.([]*structs.DrainPlan), index, nil
+}
+
+// getActivePlansImpl is used to get the active plans from the state store. The
+// node and allocation tables are only watched while there is an active plan.
+func (w *DrainPlanWatcher) getActivePlansImpl(ws memdb.WatchSet, state *state.StateStore) (interface{}, uint64, error) {
+	iter, err := state.DrainPlans(ws)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var plans []*structs.DrainPlan
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		plan := raw.(*structs.DrainPlan)
+		if plan.Active() {
+			plans = append(plans, plan)
+		}
+	}
+
+	tables := []string{"drain_plans"}
+	if len(plans) != 0 {
+		if _, err := state.Nodes(ws); err != nil {
+			return nil, 0, err
+		}
+		if _, err := state.Allocs(ws); err != nil {
+			return nil, 0, err
+		}
+		tables = append(tables, "nodes", "allocs")
+	}
+
+	var index uint64
+	for _, table := range tables {
+		i, err := state.Index(table)
+		if err != nil {
+			return nil, 0, err
+		}
+		if i > index {
+			index = i
+		}
+	}
+
+	return plans, index, nil
+}
+
+// ProgressDrainPlan computes the next step of the drain plan. Nodes whose
+// drains have finished are advanced and, if the plan is running, drains are
+// started on pending nodes while fewer than MaxParallel nodes are in progress.
+// A nil request is returned if the plan is unchanged.
+func ProgressDrainPlan(snap *state.StateSnapshot, plan *structs.DrainPlan, now time.Time) (*structs.DrainPlanUpsertRequest, error) {
+	updated := plan.Copy()
+	changed := false
+
+	// Advance the nodes that are in progress, remembering why the first
+	// migrating node can not complete yet
+	inProgress := 0
+	waiting := ""
+	for _, n := range updated.Nodes {
+		if n.Status == structs.DrainPlanNodeStatusDraining {
+			node, err := snap.NodeByID(nil, n.NodeID)
+			if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case node == nil:
+				n.Status = structs.DrainPlanNodeStatusComplete
+				changed = true
+			case node.DrainStrategy == nil:
+				n.Status = structs.DrainPlanNodeStatusMigrating
+				changed = true
+			}
+		}
+
+		if n.Status == structs.DrainPlanNodeStatusMigrating {
+			migration, err := migratedAllocsHealthy(snap, n)
+			if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case migration.unhealthy != "":
+				// Stop draining further nodes until an operator has
+				// investigated the failed migration.
+				if updated.Status == structs.DrainPlanStatusRunning {
+					updated.Status = structs.DrainPlanStatusPaused
+					updated.StatusDescription = fmt.Sprintf("Allocation %q migrated from node %q is unhealthy", migration.unhealthy, n.NodeID)
+					changed = true
+				}
+			case migration.healthy:
+				n.Status = structs.DrainPlanNodeStatusComplete
+				changed = true
+			case waiting == "":
+				waiting = migration.waiting
+			}
+		}
+
+		switch n.Status {
+		case structs.DrainPlanNodeStatusDraining, structs.DrainPlanNodeStatusMigrating:
+			inProgress++
+		}
+	}
+
+	// Start draining pending nodes
+	var updates map[string]*structs.DrainUpdate
+	var events map[string]*structs.NodeEvent
+	for _, n := range updated.Nodes {
+		if updated.Status != structs.DrainPlanStatusRunning || inProgress >= updated.MaxParallel {
+			break
+		}
+		if n.Status != structs.DrainPlanNodeStatusPending {
+			continue
+		}
+
+		node, err := snap.NodeByID(nil, n.NodeID)
+		if err != nil {
+			return nil, err
+		}
+
+		changed = true
+		if node == nil {
+			n.Status = structs.DrainPlanNodeStatusComplete
+			continue
+		}
+
+		n.Status = structs.DrainPlanNodeStatusDraining
+		inProgress++
+
+		// The node may have been drained outside of the plan, in which case
+		// the plan simply waits for that drain to finish.
+		if node.DrainStrategy != nil {
+			continue
+		}
+
+		if updates == nil {
+			updates = make(map[string]*structs.DrainUpdate)
+			events = make(map[string]*structs.NodeEvent)
+		}
+		strategy := &structs.DrainStrategy{DrainSpec: updated.DrainSpec}
+		if strategy.Deadline.Nanoseconds() > 0 {
+			strategy.ForceDeadline = now.Add(strategy.Deadline)
+		}
+		updates[n.NodeID] = &structs.DrainUpdate{DrainStrategy: strategy}
+		events[n.NodeID] = structs.NewNodeEvent().
+			SetSubsystem(structs.NodeEventSubsystemDrain).
+			SetMessage(DrainPlanEventNodeDrainSet).
+			AddDetail(DrainPlanEventDetailPlanID, updated.ID)
+	}
+
+	// Report what a running plan is waiting on so that a stalled plan can be
+	// diagnosed. The description is reset once the plan is no longer waiting.
+	if updated.Status == structs.DrainPlanStatusRunning {
+		switch {
+		case waiting != "":
+			if updated.StatusDescription != waiting {
+				updated.StatusDescription = waiting
+				changed = true
+			}
+		case updated.StatusDescription != structs.DrainPlanStatusDescriptionRunning &&
+			updated.StatusDescription != structs.DrainPlanStatusDescriptionResumed:
+			updated.StatusDescription = structs.DrainPlanStatusDescriptionRunning
+			changed = true
+		}
+	}
+
+	// Mark the plan complete once every node is
+	if updated.NodeStatusCount(structs.DrainPlanNodeStatusComplete) == len(updated.Nodes) {
+		updated.Status = structs.DrainPlanStatusComplete
+		updated.StatusDescription = structs.DrainPlanStatusDescriptionComplete
+		changed = true
+	}
+
+	if !changed {
+		return nil, nil
+	}
+
+	return &structs.DrainPlanUpsertRequest{
+		Plan:            updated,
+		Updates:         updates,
+		NodeEvents:      events,
+		PlanModifyIndex: plan.ModifyIndex,
+	}, nil
+}
+
+// migrationHealth is the health of the allocations migrated off a node.
+type migrationHealth struct {
+	// healthy is whether every replacement is healthy.
+	healthy bool
+
+	// waiting describes the first replacement that is not healthy yet.
+	waiting string
+
+	// unhealthy is the ID of a replacement that is unhealthy.
+	unhealthy string
+}
+
+// migratedAllocsHealthy returns whether the replacements of the service
+// allocations migrated off the node since the plan started draining it are
+// healthy. Replacements whose task group uses manual health checks are not
+// waited on once they are running since their health is only set by an
+// operator, matching how the node drainer stops waiting at its deadline.
+func migratedAllocsHealthy(snap *state.StateSnapshot, n *structs.DrainPlanNode) (*migrationHealth, error) {
+	allocs, err := snap.AllocsByNode(nil, n.NodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &migrationHealth{healthy: true}
+	wait := func(format string, args ...interface{}) {
+		if health.healthy {
+			health.healthy = false
+			health.waiting = fmt.Sprintf(format, args...)
+		}
+	}
+
+	for _, alloc := range allocs {
+		if alloc.ModifyIndex <= n.DrainIndex ||
+			!alloc.DesiredTransition.ShouldMigrate() ||
+			alloc.Job == nil || alloc.Job.Type != structs.JobTypeService {
+			continue
+		}
+
+		// Follow the replacements to the latest one
+		next := alloc
+		for next != nil && next.NextAllocation != "" {
+			next, err = snap.AllocByID(nil, next.NextAllocation)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// The replacement has not been placed yet
+		if next == alloc {
+			job, err := snap.JobByID(nil, alloc.Namespace, alloc.JobID)
+			if err != nil {
+				return nil, err
+			}
+			if job == nil || job.Stopped() || job.LookupTaskGroup(alloc.TaskGroup) == nil {
+				continue
+			}
+			wait("Waiting for allocation %q migrated from node %q to be replaced", alloc.ID, n.NodeID)
+			continue
+		}
+
+		// The replacement was garbage collected or stopped
+		if next == nil || next.TerminalStatus() {
+			continue
+		}
+
+		switch {
+		case next.DeploymentStatus.IsUnhealthy():
+			health.healthy = false
+			health.unhealthy = next.ID
+			return health, nil
+		case next.DeploymentStatus.IsHealthy():
+		case manualHealthCheck(next):
+			if next.ClientStatus != structs.AllocClientStatusRunning {
+				wait("Waiting for allocation %q migrated from node %q to be running", next.ID, n.NodeID)
+			}
+		default:
+			wait("Waiting for allocation %q migrated from node %q to be healthy", next.ID, n.NodeID)
+		}
+	}
+
+	return health, nil
+}
+
+// manualHealthCheck returns whether the health of the allocation is set by an
+// operator.
+func manualHealthCheck(alloc *structs.Allocation) bool {
+	if alloc.Job == nil {
+		return false
+	}
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	return tg != nil && tg.Update != nil && tg.Update.HealthCheck == structs.UpdateStrategyHealthCheck_Manual
+}
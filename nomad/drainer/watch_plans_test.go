@@ -0,0 +1,266 @@
+package drainer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// testDrainPlan creates a running drain plan for the given nodes and stores the
+// nodes and the plan.
+func testDrainPlan(t *testing.T, s *state.StateStore, maxParallel int, nodes ...*structs.Node) *structs.DrainPlan {
+	t.Helper()
+	plan := mock.DrainPlan()
+	plan.MaxParallel = maxParallel
+	plan.Nodes = nil
+	for _, node := range nodes {
+		require.NoError(t, s.UpsertNode(100, node))
+		plan.Nodes = append(plan.Nodes, &structs.DrainPlanNode{
+			NodeID: node.ID,
+			Name:   node.Name,
+			Status: structs.DrainPlanNodeStatusPending,
+		})
+	}
+	require.NoError(t, s.UpsertDrainPlan(101, &structs.DrainPlanUpsertRequest{Plan: plan}))
+	return plan
+}
+
+// applyDrainPlanProgress progresses the plan and applies the result.
+func applyDrainPlanProgress(t *testing.T, s *state.StateStore, index uint64, planID string) *structs.DrainPlanUpsertRequest {
+	t.Helper()
+	snap, err := s.Snapshot()
+	require.NoError(t, err)
+	plan, err := snap.DrainPlanByID(nil, planID)
+	require.NoError(t, err)
+
+	req, err := ProgressDrainPlan(snap, plan, time.Now())
+	require.NoError(t, err)
+	if req != nil {
+		require.NoError(t, s.UpsertDrainPlan(index, req))
+	}
+	return req
+}
+
+func TestProgressDrainPlan_MaxParallel(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s := state.TestStateStore(t)
+
+	n1, n2, n3 := mock.Node(), mock.Node(), mock.Node()
+	plan := testDrainPlan(t, s, 2, n1, n2, n3)
+
+	// Only the first two nodes are drained
+	req := applyDrainPlanProgress(t, s, 200, plan.ID)
+	require.NotNil(req)
+	require.Len(req.Updates, 2)
+	require.Contains(req.Updates, n1.ID)
+	require.Contains(req.Updates, n2.ID)
+	require.Equal(plan.ID, req.NodeEvents[n1.ID].Details[DrainPlanEventDetailPlanID])
+
+	deadline := req.Updates[n1.ID].DrainStrategy.ForceDeadline
+	require.True(deadline.After(time.Now().Add(59 * time.Minute)))
+
+	// Nothing changes while the nodes are draining
+	require.Nil(applyDrainPlanProgress(t, s, 201, plan.ID))
+
+	// Finishing a drain with nothing to migrate starts the last node
+	require.NoError(s.UpdateNodeDrain(202, n1.ID, nil, false, nil))
+	req = applyDrainPlanProgress(t, s, 203, plan.ID)
+	require.NotNil(req)
+	require.Len(req.Updates, 1)
+	require.Contains(req.Updates, n3.ID)
+	require.Equal(structs.DrainPlanNodeStatusComplete, req.Plan.Nodes[0].Status)
+
+	// Finishing the remaining drains completes the plan
+	require.NoError(s.UpdateNodeDrain(204, n2.ID, nil, false, nil))
+	require.NoError(s.UpdateNodeDrain(205, n3.ID, nil, false, nil))
+	req = applyDrainPlanProgress(t, s, 206, plan.ID)
+	require.NotNil(req)
+	require.Empty(req.Updates)
+	require.Equal(structs.DrainPlanStatusComplete, req.Plan.Status)
+}
+
+func TestProgressDrainPlan_WaitsForHealthyMigrations(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s := state.TestStateStore(t)
+
+	n1, n2 := mock.Node(), mock.Node()
+	plan := testDrainPlan(t, s, 1, n1, n2)
+	require.NotNil(applyDrainPlanProgress(t, s, 200, plan.ID))
+
+	// Migrate an allocation off the first node
+	job := mock.Job()
+	require.NoError(s.UpsertJob(201, job))
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = n1.ID
+	require.NoError(s.UpsertAllocs(202, []*structs.Allocation{alloc}))
+
+	alloc = alloc.Copy()
+	alloc.DesiredStatus = structs.AllocDesiredStatusStop
+	alloc.DesiredTransition.Migrate = helper.BoolToPtr(true)
+	replacement := mock.Alloc()
+	replacement.Job = job
+	replacement.JobID = job.ID
+	replacement.NodeID = n2.ID
+	replacement.PreviousAllocation = alloc.ID
+	require.NoError(s.UpsertAllocs(203, []*structs.Allocation{alloc, replacement}))
+	require.NoError(s.UpdateNodeDrain(204, n1.ID, nil, false, nil))
+
+	// The node is done draining but the replacement isn't healthy yet
+	req := applyDrainPlanProgress(t, s, 205, plan.ID)
+	require.NotNil(req)
+	require.Empty(req.Updates)
+	require.Equal(structs.DrainPlanNodeStatusMigrating, req.Plan.Nodes[0].Status)
+	require.Contains(req.Plan.StatusDescription, replacement.ID)
+	require.Nil(applyDrainPlanProgress(t, s, 206, plan.ID))
+
+	// Once the replacement is healthy the next node is drained
+	replacement = replacement.Copy()
+	replacement.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Healthy: helper.BoolToPtr(true),
+	}
+	require.NoError(s.UpsertAllocs(207, []*structs.Allocation{replacement}))
+
+	req = applyDrainPlanProgress(t, s, 208, plan.ID)
+	require.NotNil(req)
+	require.Contains(req.Updates, n2.ID)
+	require.Equal(structs.DrainPlanNodeStatusComplete, req.Plan.Nodes[0].Status)
+	require.Equal(structs.DrainPlanStatusDescriptionRunning, req.Plan.StatusDescription)
+}
+
+func TestProgressDrainPlan_ManualHealthMigration(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s := state.TestStateStore(t)
+
+	n1, n2 := mock.Node(), mock.Node()
+	plan := testDrainPlan(t, s, 1, n1, n2)
+	require.NotNil(applyDrainPlanProgress(t, s, 200, plan.ID))
+
+	// Migrate an allocation of a job whose health is set by an operator
+	job := mock.Job()
+	job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	job.TaskGroups[0].Update.HealthCheck = structs.UpdateStrategyHealthCheck_Manual
+	require.NoError(s.UpsertJob(201, job))
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = n1.ID
+	require.NoError(s.UpsertAllocs(202, []*structs.Allocation{alloc}))
+
+	alloc = alloc.Copy()
+	alloc.DesiredStatus = structs.AllocDesiredStatusStop
+	alloc.DesiredTransition.Migrate = helper.BoolToPtr(true)
+	replacement := mock.Alloc()
+	replacement.Job = job
+	replacement.JobID = job.ID
+	replacement.NodeID = n2.ID
+	replacement.PreviousAllocation = alloc.ID
+	replacement.ClientStatus = structs.AllocClientStatusPending
+	require.NoError(s.UpsertAllocs(203, []*structs.Allocation{alloc, replacement}))
+	require.NoError(s.UpdateNodeDrain(204, n1.ID, nil, false, nil))
+
+	// The plan reports that it is waiting for the replacement to run
+	req := applyDrainPlanProgress(t, s, 205, plan.ID)
+	require.NotNil(req)
+	require.Empty(req.Updates)
+	require.Equal(structs.DrainPlanNodeStatusMigrating, req.Plan.Nodes[0].Status)
+	require.Equal(structs.DrainPlanStatusRunning, req.Plan.Status)
+	require.Contains(req.Plan.StatusDescription, replacement.ID)
+	require.Contains(req.Plan.StatusDescription, "to be running")
+	require.Nil(applyDrainPlanProgress(t, s, 206, plan.ID))
+
+	// Once the client reports the replacement as running the next node is
+	// drained without waiting for an operator to set its health
+	replacement = replacement.Copy()
+	replacement.ClientStatus = structs.AllocClientStatusRunning
+	require.NoError(s.UpdateAllocsFromClient(207, []*structs.Allocation{replacement}))
+
+	snap, err := s.Snapshot()
+	require.NoError(err)
+	running, err := snap.AllocByID(nil, replacement.ID)
+	require.NoError(err)
+	require.Equal(structs.AllocClientStatusRunning, running.ClientStatus)
+	require.Nil(running.DeploymentStatus)
+
+	req = applyDrainPlanProgress(t, s, 208, plan.ID)
+	require.NotNil(req)
+	require.Len(req.Updates, 1)
+	require.Contains(req.Updates, n2.ID)
+	require.Equal(structs.DrainPlanNodeStatusComplete, req.Plan.Nodes[0].Status)
+	require.Equal(structs.DrainPlanStatusDescriptionRunning, req.Plan.StatusDescription)
+}
+
+func TestProgressDrainPlan_PausesOnUnhealthyMigration(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s := state.TestStateStore(t)
+
+	n1, n2 := mock.Node(), mock.Node()
+	plan := testDrainPlan(t, s, 1, n1, n2)
+	require.NotNil(applyDrainPlanProgress(t, s, 200, plan.ID))
+
+	job := mock.Job()
+	require.NoError(s.UpsertJob(201, job))
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = n1.ID
+	require.NoError(s.UpsertAllocs(202, []*structs.Allocation{alloc}))
+
+	alloc = alloc.Copy()
+	alloc.DesiredStatus = structs.AllocDesiredStatusStop
+	alloc.DesiredTransition.Migrate = helper.BoolToPtr(true)
+	replacement := mock.Alloc()
+	replacement.Job = job
+	replacement.JobID = job.ID
+	replacement.NodeID = n2.ID
+	replacement.PreviousAllocation = alloc.ID
+	replacement.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Healthy: helper.BoolToPtr(false),
+	}
+	require.NoError(s.UpsertAllocs(203, []*structs.Allocation{alloc, replacement}))
+	require.NoError(s.UpdateNodeDrain(204, n1.ID, nil, false, nil))
+
+	req := applyDrainPlanProgress(t, s, 205, plan.ID)
+	require.NotNil(req)
+	require.Empty(req.Updates)
+	require.Equal(structs.DrainPlanStatusPaused, req.Plan.Status)
+	require.Contains(req.Plan.StatusDescription, replacement.ID)
+}
+
+func TestProgressDrainPlan_Paused(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s := state.TestStateStore(t)
+
+	n1, n2 := mock.Node(), mock.Node()
+	plan := testDrainPlan(t, s, 1, n1, n2)
+	require.NotNil(applyDrainPlanProgress(t, s, 200, plan.ID))
+
+	// Pause the plan
+	snap, err := s.Snapshot()
+	require.NoError(err)
+	paused, err := snap.DrainPlanByID(nil, plan.ID)
+	require.NoError(err)
+	paused = paused.Copy()
+	paused.Status = structs.DrainPlanStatusPaused
+	require.NoError(s.UpsertDrainPlan(201, &structs.DrainPlanUpsertRequest{Plan: paused}))
+
+	// Finished drains are still tracked but no new drains are started
+	require.NoError(s.UpdateNodeDrain(202, n1.ID, nil, false, nil))
+	req := applyDrainPlanProgress(t, s, 203, plan.ID)
+	require.NotNil(req)
+	require.Empty(req.Updates)
+	require.Equal(structs.DrainPlanNodeStatusComplete, req.Plan.Nodes[0].Status)
+	require.Equal(structs.DrainPlanNodeStatusPending, req.Plan.Nodes[1].Status)
+	require.Equal(structs.DrainPlanStatusPaused, req.Plan.Status)
+}
@@ -2,8 +2,9 @@ package nomad
 
 import "github.com/hashicorp/nomad/nomad/structs"
 
-// drainerShim implements the drainer.RaftApplier interface required by the
-// NodeDrainer.
+// drainerShim implements the drainer.RaftApplier and
+// drainer.DrainPlanRaftApplier interfaces required by the NodeDrainer and the
+// DrainPlanWatcher.
 type drainerShim struct {
 	s *Server
 }
@@ -37,6 +38,12 @@ func (d drainerShim) AllocUpdateDesiredTransition(allocs map[string]*structs.Des
 	return d.convertApplyErrors(resp, index, err)
 }
 
+func (d drainerShim) UpsertDrainPlan(args *structs.DrainPlanUpsertRequest) (uint64, error) {
+	args.WriteRequest = structs.WriteRequest{Region: d.s.config.Region}
+	resp, index, err := d.s.raftApply(structs.DrainPlanUpsertRequestType, args)
+	return d.convertApplyErrors(resp, index, err)
+}
+
 // convertApplyErrors parses the results of a raftApply and returns the index at
 // which it was applied and any error that occurred. Raft Apply returns two
 // separate errors, Raft library errors and user returned errors from the FSM.
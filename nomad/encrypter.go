@@ -0,0 +1,496 @@
+package nomad
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// rootKeySize is the size in bytes of the AES-256 root keys
+	rootKeySize = 32
+
+	// keyringInitTimeout is how long an encryption waits for the leader to
+	// initialize the keyring before failing
+	keyringInitTimeout = 5 * time.Second
+
+	// keyringReplicationRetry is how long the keyring replication waits
+	// before retrying to fetch keys missing from the keystore
+	keyringReplicationRetry = 5 * time.Second
+
+	// keystoreExtension is the extension of the files in the keystore
+	keystoreExtension = ".nks.json"
+)
+
+var (
+	// signingKeyContext is mixed into the root key to derive the workload
+	// identity signing key so the key material isn't used for two purposes
+	signingKeyContext = []byte("nomad workload identity")
+)
+
+// jwtHeader is the JOSE header of the workload identities
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Type      string `json:"typ"`
+}
+
+// Encrypter is used to encrypt and decrypt data and to sign workload
+// identities with the root keys. Only the metadata of the keys is written to
+// raft. The key material is held in memory and, outside of dev mode, in the
+// keystore directory of the server; servers fetch the keys they are missing
+// from each other over RPC.
+type Encrypter struct {
+	srv *Server
+
+	// keystorePath is the directory the keys are persisted in. It is empty
+	// in dev mode, in which case the keys are only kept in memory.
+	keystorePath string
+
+	keys    map[string]*structs.RootKey
+	ciphers map[string]cipher.AEAD
+	signers map[string]ed25519.PrivateKey
+
+	// addedCh is closed and replaced whenever a key is added so that
+	// callers can wait for the key material to be replicated
+	addedCh chan struct{}
+	l       sync.RWMutex
+}
+
+// NewEncrypter returns an Encrypter with the keys loaded from the keystore at
+// the given path.
+func NewEncrypter(srv *Server, keystorePath string) (*Encrypter, error) {
+	e := &Encrypter{
+		srv:          srv,
+		keystorePath: keystorePath,
+		keys:         make(map[string]*structs.RootKey),
+		ciphers:      make(map[string]cipher.AEAD),
+		signers:      make(map[string]ed25519.PrivateKey),
+		addedCh:      make(chan struct{}),
+	}
+	if err := e.loadKeystore(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// loadKeystore loads the keys persisted in the keystore
+func (e *Encrypter) loadKeystore() error {
+	if e.keystorePath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(e.keystorePath, 0700); err != nil {
+		return fmt.Errorf("failed to create keystore: %v", err)
+	}
+
+	files, err := ioutil.ReadDir(e.keystorePath)
+	if err != nil {
+		return fmt.Errorf("failed to read keystore: %v", err)
+	}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), keystoreExtension) {
+			continue
+		}
+
+		path := filepath.Join(e.keystorePath, file.Name())
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read key %q: %v", path, err)
+		}
+		var key structs.RootKey
+		if err := json.Unmarshal(buf, &key); err != nil {
+			return fmt.Errorf("failed to decode key %q: %v", path, err)
+		}
+		if key.Meta == nil || key.Meta.KeyID+keystoreExtension != file.Name() {
+			return fmt.Errorf("key %q does not match its file name", path)
+		}
+		if err := e.addKeyLocked(&key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddKey adds the root key to the keystore
+func (e *Encrypter) AddKey(key *structs.RootKey) error {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	if _, ok := e.keys[key.Meta.KeyID]; ok {
+		return nil
+	}
+	if err := e.saveKey(key); err != nil {
+		return err
+	}
+	return e.addKeyLocked(key)
+}
+
+// addKeyLocked validates the key and adds it to the in-memory keystore. The
+// lock must be held.
+func (e *Encrypter) addKeyLocked(key *structs.RootKey) error {
+	if key.Meta.Algorithm != structs.EncryptionAlgorithmAES256GCM {
+		return fmt.Errorf("unsupported encryption algorithm %q", key.Meta.Algorithm)
+	}
+	if len(key.Key) != rootKeySize {
+		return fmt.Errorf("root key %q has invalid size %d", key.Meta.KeyID, len(key.Key))
+	}
+
+	e.keys[key.Meta.KeyID] = key.Copy()
+	close(e.addedCh)
+	e.addedCh = make(chan struct{})
+	return nil
+}
+
+// saveKey persists the key to the keystore. The file is written with
+// permissions that only allow the server to read it.
+func (e *Encrypter) saveKey(key *structs.RootKey) error {
+	if e.keystorePath == "" {
+		return nil
+	}
+
+	buf, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(e.keystorePath, key.Meta.KeyID+keystoreExtension)
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf, 0600); err != nil {
+		return fmt.Errorf("failed to write key %q: %v", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write key %q: %v", path, err)
+	}
+	return nil
+}
+
+// GetKey returns the root key with the given ID from the keystore
+func (e *Encrypter) GetKey(keyID string) (*structs.RootKey, error) {
+	e.l.RLock()
+	defer e.l.RUnlock()
+
+	key, ok := e.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("root key %q not found in keystore", keyID)
+	}
+	return key, nil
+}
+
+// Encrypt encrypts the plaintext with the active root key and returns the
+// ciphertext along with the ID of the key used.
+func (e *Encrypter) Encrypt(plaintext []byte) ([]byte, string, error) {
+	key, err := e.activeKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	aead, err := e.cipher(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The nonce is prepended to the ciphertext
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), key.Meta.KeyID, nil
+}
+
+// activeKey returns the active root key. The keyring is initialized by the
+// leader shortly after it is elected and the key material is then replicated
+// to the other servers, so this waits for the key to exist.
+func (e *Encrypter) activeKey() (*structs.RootKey, error) {
+	timeout := time.NewTimer(keyringInitTimeout)
+	defer timeout.Stop()
+
+	for {
+		ws := memdb.NewWatchSet()
+		meta, err := e.srv.State().GetActiveRootKeyMeta(ws)
+		if err != nil {
+			return nil, err
+		}
+		if meta != nil {
+			e.l.RLock()
+			key, ok := e.keys[meta.KeyID]
+			addedCh := e.addedCh
+			e.l.RUnlock()
+			if ok {
+				return key, nil
+			}
+			ws.Add(addedCh)
+		}
+		if timedOut := ws.Watch(timeout.C); timedOut {
+			return nil, fmt.Errorf("keyring has not been initialized")
+		}
+	}
+}
+
+// Decrypt decrypts the ciphertext with the root key with the given ID.
+func (e *Encrypter) Decrypt(ciphertext []byte, keyID string) ([]byte, error) {
+	key, err := e.GetKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := e.cipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+}
+
+// cipher returns the cached AEAD for the given root key, creating it if
+// necessary.
+func (e *Encrypter) cipher(key *structs.RootKey) (cipher.AEAD, error) {
+	e.l.RLock()
+	aead, ok := e.ciphers[key.Meta.KeyID]
+	e.l.RUnlock()
+	if ok {
+		return aead, nil
+	}
+
+	block, err := aes.NewCipher(key.Key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err = cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	e.l.Lock()
+	e.ciphers[key.Meta.KeyID] = aead
+	e.l.Unlock()
+	return aead, nil
+}
+
+// SignClaims signs the identity claims with the signing key derived from the
+// active root key and returns the resulting JWT.
+func (e *Encrypter) SignClaims(claims *structs.IdentityClaims) (string, error) {
+	key, err := e.activeKey()
+	if err != nil {
+		return "", err
+	}
+	signer := e.signer(key)
+
+	header, err := json.Marshal(&jwtHeader{
+		Algorithm: structs.IdentityAlgorithmEdDSA,
+		KeyID:     key.Meta.KeyID,
+		Type:      "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	sig := ed25519.Sign(signer, []byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// VerifyClaim verifies the signature of the JWT against the key that signed
+// it and returns its claims.
+func (e *Encrypter) VerifyClaim(token string) (*structs.IdentityClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token header: %v", err)
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %v", err)
+	}
+	if header.Algorithm != structs.IdentityAlgorithmEdDSA {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", header.Algorithm)
+	}
+
+	key, err := e.GetKey(header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %v", err)
+	}
+	pub := e.signer(key).Public().(ed25519.PublicKey)
+	if !ed25519.Verify(pub, []byte(parts[0]+"."+parts[1]), sig) {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token claims: %v", err)
+	}
+	var claims structs.IdentityClaims
+	if err := json.Unmarshal(rawClaims, &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %v", err)
+	}
+	now := time.Now().UTC().Unix()
+	if claims.NotBefore > now {
+		return nil, fmt.Errorf("token is not yet valid")
+	}
+	if claims.Expiry <= now {
+		return nil, fmt.Errorf("token is expired")
+	}
+	return &claims, nil
+}
+
+// PublicKey returns the public key used to verify the identities signed with
+// the given root key.
+func (e *Encrypter) PublicKey(meta *structs.RootKeyMeta) (*structs.KeyringPublicKey, error) {
+	key, err := e.GetKey(meta.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	return &structs.KeyringPublicKey{
+		KeyID:      meta.KeyID,
+		PublicKey:  e.signer(key).Public().(ed25519.PublicKey),
+		Algorithm:  structs.IdentityAlgorithmEdDSA,
+		CreateTime: meta.CreateTime,
+	}, nil
+}
+
+// signer returns the cached signing key derived from the given root key,
+// creating it if necessary.
+func (e *Encrypter) signer(key *structs.RootKey) ed25519.PrivateKey {
+	e.l.RLock()
+	signer, ok := e.signers[key.Meta.KeyID]
+	e.l.RUnlock()
+	if ok {
+		return signer
+	}
+
+	mac := hmac.New(sha256.New, key.Key)
+	mac.Write(signingKeyContext)
+	signer = ed25519.NewKeyFromSeed(mac.Sum(nil))
+
+	e.l.Lock()
+	e.signers[key.Meta.KeyID] = signer
+	e.l.Unlock()
+	return signer
+}
+
+// generateRootKey returns a new active root key with random key material.
+func generateRootKey() (*structs.RootKey, error) {
+	buf := make([]byte, rootKeySize)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	return structs.NewRootKey(uuid.Generate(), buf), nil
+}
+
+// replicate fetches the root keys whose metadata is in the state store but
+// whose key material is missing from the keystore from the other servers.
+func (e *Encrypter) replicate(stopCh <-chan struct{}) {
+	for {
+		state := e.srv.State()
+		ws := memdb.NewWatchSet()
+		ws.Add(state.AbandonCh())
+		iter, err := state.RootKeyMetas(ws)
+		if err != nil {
+			e.srv.logger.Printf("[ERR] nomad.keyring: failed to list root keys: %v", err)
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(keyringReplicationRetry):
+				continue
+			}
+		}
+
+		missing := false
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			meta := raw.(*structs.RootKeyMeta)
+			if _, err := e.GetKey(meta.KeyID); err == nil {
+				continue
+			}
+			if err := e.fetchKey(meta.KeyID); err != nil {
+				e.srv.logger.Printf("[WARN] nomad.keyring: failed to replicate root key %q: %v", meta.KeyID, err)
+				missing = true
+			}
+		}
+
+		if missing {
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(keyringReplicationRetry):
+				continue
+			}
+		}
+		ws.Add(stopCh)
+		ws.Watch(nil)
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+	}
+}
+
+// fetchKey fetches the root key with the given ID from the first server that
+// has it in its keystore.
+func (e *Encrypter) fetchKey(keyID string) error {
+	req := &structs.KeyringGetRootKeyRequest{
+		KeyID:       keyID,
+		ServerProof: e.srv.keyringServerProof(keyID),
+		QueryOptions: structs.QueryOptions{
+			Region:     e.srv.config.Region,
+			AllowStale: true,
+		},
+	}
+
+	self := e.srv.LocalMember().Name
+	e.srv.peerLock.RLock()
+	peers := make([]*serverParts, 0, len(e.srv.localPeers))
+	for _, peer := range e.srv.localPeers {
+		if peer.Name != self {
+			peers = append(peers, peer)
+		}
+	}
+	e.srv.peerLock.RUnlock()
+
+	for _, peer := range peers {
+		var resp structs.KeyringGetRootKeyResponse
+		if err := e.srv.forwardServer(peer, "Keyring.Get", req, &resp); err != nil {
+			e.srv.logger.Printf("[DEBUG] nomad.keyring: failed to fetch root key %q from %s: %v", keyID, peer.Name, err)
+			continue
+		}
+		if resp.Key == nil || resp.Key.Meta == nil || resp.Key.Meta.KeyID != keyID {
+			continue
+		}
+		return e.AddKey(resp.Key)
+	}
+	return fmt.Errorf("no server has the key")
+}
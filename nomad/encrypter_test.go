@@ -0,0 +1,137 @@
+package nomad
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncrypter_EncryptDecrypt(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	ciphertext, keyID, err := s1.encrypter.Encrypt([]byte("hunter2"))
+	require.NoError(err)
+	require.NotContains(string(ciphertext), "hunter2")
+
+	plaintext, err := s1.encrypter.Decrypt(ciphertext, keyID)
+	require.NoError(err)
+	require.Equal("hunter2", string(plaintext))
+}
+
+func TestEncrypter_SignVerifyClaims(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	alloc := mock.Alloc()
+	claims := structs.NewIdentityClaims(alloc, "web")
+	token, err := s1.encrypter.SignClaims(claims)
+	require.NoError(err)
+
+	out, err := s1.encrypter.VerifyClaim(token)
+	require.NoError(err)
+	require.Equal(claims, out)
+	require.Equal(claims.IssuedAt+int64(structs.IdentityDefaultTTL/time.Second), out.Expiry)
+
+	// Expired identities are rejected
+	expired := structs.NewIdentityClaims(alloc, "web")
+	expired.Expiry = time.Now().Add(-time.Minute).Unix()
+	expiredToken, err := s1.encrypter.SignClaims(expired)
+	require.NoError(err)
+	_, err = s1.encrypter.VerifyClaim(expiredToken)
+	require.Error(err)
+
+	// Tampering with the claims invalidates the signature
+	parts := strings.Split(token, ".")
+	other, err := s1.encrypter.SignClaims(structs.NewIdentityClaims(mock.Alloc(), "web"))
+	require.NoError(err)
+	parts[1] = strings.Split(other, ".")[1]
+	_, err = s1.encrypter.VerifyClaim(strings.Join(parts, "."))
+	require.Error(err)
+
+	// Identities signed before a key rotation can still be verified
+	key, err := generateRootKey()
+	require.NoError(err)
+	require.NoError(s1.addRootKey(key))
+	_, err = s1.encrypter.VerifyClaim(token)
+	require.NoError(err)
+}
+
+func TestEncrypter_Keystore(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "nomad")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, keystoreDir)
+
+	e, err := NewEncrypter(nil, path)
+	require.NoError(err)
+	key, err := generateRootKey()
+	require.NoError(err)
+	require.NoError(e.AddKey(key))
+
+	// Only the server may read the key material
+	info, err := os.Stat(path)
+	require.NoError(err)
+	require.Equal(os.FileMode(0700), info.Mode().Perm())
+	info, err = os.Stat(filepath.Join(path, key.Meta.KeyID+keystoreExtension))
+	require.NoError(err)
+	require.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	// The keys are loaded from the keystore on restart
+	e, err = NewEncrypter(nil, path)
+	require.NoError(err)
+	out, err := e.GetKey(key.Meta.KeyID)
+	require.NoError(err)
+	require.Equal(key, out)
+}
+
+func TestEncrypter_Replication(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		testGossipKeyring(t, c)
+	})
+	defer s1.Shutdown()
+	s2 := TestServer(t, func(c *Config) {
+		c.DevDisableBootstrap = true
+		testGossipKeyring(t, c)
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// The key material is never applied through raft
+	meta, err := s1.State().GetActiveRootKeyMeta(nil)
+	require.NoError(err)
+	require.NotNil(meta)
+
+	// The follower fetches the key from the leader and can decrypt what the
+	// leader encrypted
+	ciphertext, keyID, err := s1.encrypter.Encrypt([]byte("hunter2"))
+	require.NoError(err)
+	testutil.WaitForResult(func() (bool, error) {
+		plaintext, err := s2.encrypter.Decrypt(ciphertext, keyID)
+		if err != nil {
+			return false, err
+		}
+		return string(plaintext) == "hunter2", nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
@@ -21,6 +21,10 @@ const (
 	// they've reached the deliveryLimit. This allows the leader to
 	// set the status to failed.
 	failedQueue = "_failed"
+
+	// defaultNamespaceWeight is the weight of namespaces without a
+	// configured weight when dequeueing fairly across namespaces.
+	defaultNamespaceWeight = 1
 )
 
 var (
@@ -38,9 +42,16 @@ var (
 // created, due to a change in a job specification or a node, we put it into the
 // broker. The broker sorts by evaluations by priority and scheduler type. This
 // allows us to dequeue the highest priority work first, while also allowing sub-schedulers
-// to only dequeue work they know how to handle. The broker is designed to be entirely
+// to only dequeue work they know how to handle. Within a priority, work is dequeued
+// fairly across namespaces in proportion to their weight so that a burst in one
+// namespace can not starve the others. The broker is designed to be entirely
 // in-memory and is managed by the leader node.
 //
+// Evaluations for a job are serialized. When an evaluation for a job is Acked,
+// only the most recent of the evaluations blocked behind it is enqueued and
+// the others are marked cancelable, since scheduling the latest version of the
+// job makes them redundant. The leader reaps cancelable evaluations.
+//
 // The broker must provide at-least-once delivery semantics. It relies on explicit
 // Ack/Nack messages to handle this. If a delivery is not Ack'd in a sufficient time
 // span, it will be assumed Nack'd.
@@ -62,8 +73,20 @@ type EvalBroker struct {
 	// blocked tracks the blocked evaluations by JobID in a priority queue
 	blocked map[structs.NamespacedID]PendingEvaluations
 
-	// ready tracks the ready jobs by scheduler in a priority queue
-	ready map[string]PendingEvaluations
+	// cancelable tracks blocked evaluations that were coalesced into a more
+	// recent evaluation for the same job and should be canceled.
+	cancelable []*structs.Evaluation
+
+	// cancelableCh is used to signal that evaluations were added to the
+	// cancelable set.
+	cancelableCh chan struct{}
+
+	// ready tracks the ready jobs by scheduler in a fair priority queue
+	ready map[string]*ReadyEvaluations
+
+	// namespaceWeights is the relative share of dequeues each namespace gets
+	// within a priority when several namespaces have ready work.
+	namespaceWeights map[string]int
 
 	// unack is a map of evalID to an un-acknowledged evaluation
 	unack map[string]*unackEval
@@ -135,7 +158,8 @@ func NewEvalBroker(timeout, initialNackDelay, subsequentNackDelay time.Duration,
 		evals:                make(map[string]int),
 		jobEvals:             make(map[structs.NamespacedID]string),
 		blocked:              make(map[structs.NamespacedID]PendingEvaluations),
-		ready:                make(map[string]PendingEvaluations),
+		cancelableCh:         make(chan struct{}, 1),
+		ready:                make(map[string]*ReadyEvaluations),
 		unack:                make(map[string]*unackEval),
 		waiting:              make(map[string]chan struct{}),
 		requeue:              make(map[string]*structs.Evaluation),
@@ -146,10 +170,27 @@ func NewEvalBroker(timeout, initialNackDelay, subsequentNackDelay time.Duration,
 		delayedEvalsUpdateCh: make(chan struct{}, 1),
 	}
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
+	b.stats.ByNamespace = make(map[string]*NamespaceStats)
 
 	return b, nil
 }
 
+// SetNamespaceWeights sets the relative share of dequeues of each namespace
+// within a priority. Namespaces without a weight have a weight of one.
+func (b *EvalBroker) SetNamespaceWeights(weights map[string]int) {
+	b.l.Lock()
+	defer b.l.Unlock()
+	b.namespaceWeights = weights
+}
+
+// namespaceWeight returns the weight of the namespace. The lock must be held.
+func (b *EvalBroker) namespaceWeight(namespace string) int {
+	if w, ok := b.namespaceWeights[namespace]; ok && w > 0 {
+		return w
+	}
+	return defaultNamespaceWeight
+}
+
 // Enabled is used to check if the broker is enabled.
 func (b *EvalBroker) Enabled() bool {
 	b.l.RLock()
@@ -284,24 +325,26 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, queue string) {
 		heap.Push(&blocked, eval)
 		b.blocked[namespacedID] = blocked
 		b.stats.TotalBlocked += 1
+		b.namespaceStats(eval.Namespace).Blocked += 1
 		return
 	}
 
 	// Find the pending by scheduler class
 	pending, ok := b.ready[queue]
 	if !ok {
-		pending = make([]*structs.Evaluation, 0, 16)
+		pending = NewReadyEvaluations()
+		b.ready[queue] = pending
 		if _, ok := b.waiting[queue]; !ok {
 			b.waiting[queue] = make(chan struct{}, 1)
 		}
 	}
 
-	// Push onto the heap
-	heap.Push(&pending, eval)
-	b.ready[queue] = pending
+	// Push onto the queue
+	pending.Push(eval)
 
 	// Update the stats
 	b.stats.TotalReady += 1
+	b.namespaceStats(eval.Namespace).Ready += 1
 	bySched, ok := b.stats.ByScheduler[queue]
 	if !ok {
 		bySched = &SchedulerStats{}
@@ -414,10 +457,7 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 // This assumes locks are held and that this scheduler has work
 func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string, error) {
 	// Get the pending queue
-	pending := b.ready[sched]
-	raw := heap.Pop(&pending)
-	b.ready[sched] = pending
-	eval := raw.(*structs.Evaluation)
+	eval := b.ready[sched].Pop(b.namespaceWeight)
 
 	// Generate a UUID for the token
 	token := uuid.Generate()
@@ -443,6 +483,9 @@ func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string,
 	bySched := b.stats.ByScheduler[sched]
 	bySched.Ready -= 1
 	bySched.Unacked += 1
+	byNamespace := b.namespaceStats(eval.Namespace)
+	byNamespace.Ready -= 1
+	byNamespace.Unacked += 1
 
 	return eval, token, nil
 }
@@ -550,6 +593,7 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 	}
 	bySched := b.stats.ByScheduler[queue]
 	bySched.Unacked -= 1
+	b.namespaceStats(unack.Eval.Namespace).Unacked -= 1
 
 	// Cleanup
 	delete(b.unack, evalID)
@@ -561,16 +605,26 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 	}
 	delete(b.jobEvals, namespacedID)
 
-	// Check if there are any blocked evaluations
+	// Check if there are any blocked evaluations. Only the most recent one
+	// needs to be processed, the others are coalesced into it and canceled.
 	if blocked := b.blocked[namespacedID]; len(blocked) != 0 {
-		raw := heap.Pop(&blocked)
-		if len(blocked) > 0 {
-			b.blocked[namespacedID] = blocked
-		} else {
-			delete(b.blocked, namespacedID)
+		delete(b.blocked, namespacedID)
+		b.stats.TotalBlocked -= len(blocked)
+		b.namespaceStats(namespacedID.Namespace).Blocked -= len(blocked)
+
+		eval, canceled := blocked.Coalesce()
+		if len(canceled) != 0 {
+			for _, c := range canceled {
+				delete(b.evals, c.ID)
+			}
+			b.cancelable = append(b.cancelable, canceled...)
+			b.stats.TotalCancelable = len(b.cancelable)
+
+			select {
+			case b.cancelableCh <- struct{}{}:
+			default:
+			}
 		}
-		eval := raw.(*structs.Evaluation)
-		b.stats.TotalBlocked -= 1
 		b.enqueueLocked(eval, eval.Type)
 	}
 
@@ -610,6 +664,8 @@ func (b *EvalBroker) Nack(evalID, token string) error {
 	b.stats.TotalUnacked -= 1
 	bySched := b.stats.ByScheduler[unack.Eval.Type]
 	bySched.Unacked -= 1
+	byNamespace := b.namespaceStats(unack.Eval.Namespace)
+	byNamespace.Unacked -= 1
 
 	// Check if we've hit the delivery limit, and re-enqueue
 	// in the failedQueue
@@ -630,6 +686,49 @@ func (b *EvalBroker) Nack(evalID, token string) error {
 	return nil
 }
 
+// Cancelable returns the evaluations that were coalesced into more recent
+// evaluations and should be canceled. It blocks until there are cancelable
+// evaluations or the passed timeout is reached.
+func (b *EvalBroker) Cancelable(timeout time.Duration) []*structs.Evaluation {
+	var timeoutTimer *time.Timer
+	var timeoutCh <-chan time.Time
+SCAN:
+	b.l.Lock()
+	if len(b.cancelable) != 0 {
+		cancelable := b.cancelable
+		b.cancelable = nil
+		b.stats.TotalCancelable = 0
+		b.l.Unlock()
+		return cancelable
+	}
+	b.l.Unlock()
+
+	// Create the timer
+	if timeoutTimer == nil && timeout != 0 {
+		timeoutTimer = time.NewTimer(timeout)
+		timeoutCh = timeoutTimer.C
+		defer timeoutTimer.Stop()
+	}
+
+	select {
+	case <-timeoutCh:
+		return nil
+	case <-b.cancelableCh:
+		goto SCAN
+	}
+}
+
+// namespaceStats returns the stats of the namespace, creating them if
+// necessary. The lock must be held.
+func (b *EvalBroker) namespaceStats(namespace string) *NamespaceStats {
+	stats, ok := b.stats.ByNamespace[namespace]
+	if !ok {
+		stats = &NamespaceStats{}
+		b.stats.ByNamespace[namespace] = stats
+	}
+	return stats
+}
+
 // nackReenqueueDelay is used to determine the delay that should be applied on
 // the evaluation given the number of previous attempts
 func (b *EvalBroker) nackReenqueueDelay(eval *structs.Evaluation, prevDequeues int) time.Duration {
@@ -712,11 +811,14 @@ func (b *EvalBroker) flush() {
 	b.stats.TotalUnacked = 0
 	b.stats.TotalBlocked = 0
 	b.stats.TotalWaiting = 0
+	b.stats.TotalCancelable = 0
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
+	b.stats.ByNamespace = make(map[string]*NamespaceStats)
 	b.evals = make(map[string]int)
 	b.jobEvals = make(map[structs.NamespacedID]string)
 	b.blocked = make(map[structs.NamespacedID]PendingEvaluations)
-	b.ready = make(map[string]PendingEvaluations)
+	b.cancelable = nil
+	b.ready = make(map[string]*ReadyEvaluations)
 	b.unack = make(map[string]*unackEval)
 	b.timeWait = make(map[string]*time.Timer)
 	b.delayHeap = lib.NewDelayHeap()
@@ -795,6 +897,7 @@ func (b *EvalBroker) Stats() *BrokerStats {
 	// Allocate a new stats struct
 	stats := new(BrokerStats)
 	stats.ByScheduler = make(map[string]*SchedulerStats)
+	stats.ByNamespace = make(map[string]*NamespaceStats)
 
 	b.l.RLock()
 	defer b.l.RUnlock()
@@ -804,11 +907,17 @@ func (b *EvalBroker) Stats() *BrokerStats {
 	stats.TotalUnacked = b.stats.TotalUnacked
 	stats.TotalBlocked = b.stats.TotalBlocked
 	stats.TotalWaiting = b.stats.TotalWaiting
+	stats.TotalCancelable = b.stats.TotalCancelable
 	for sched, subStat := range b.stats.ByScheduler {
 		subStatCopy := new(SchedulerStats)
 		*subStatCopy = *subStat
 		stats.ByScheduler[sched] = subStatCopy
 	}
+	for ns, subStat := range b.stats.ByNamespace {
+		subStatCopy := new(NamespaceStats)
+		*subStatCopy = *subStat
+		stats.ByNamespace[ns] = subStatCopy
+	}
 	return stats
 }
 
@@ -822,10 +931,17 @@ func (b *EvalBroker) EmitStats(period time.Duration, stopCh chan struct{}) {
 			metrics.SetGauge([]string{"nomad", "broker", "total_unacked"}, float32(stats.TotalUnacked))
 			metrics.SetGauge([]string{"nomad", "broker", "total_blocked"}, float32(stats.TotalBlocked))
 			metrics.SetGauge([]string{"nomad", "broker", "total_waiting"}, float32(stats.TotalWaiting))
+			metrics.SetGauge([]string{"nomad", "broker", "total_cancelable"}, float32(stats.TotalCancelable))
 			for sched, schedStats := range stats.ByScheduler {
 				metrics.SetGauge([]string{"nomad", "broker", sched, "ready"}, float32(schedStats.Ready))
 				metrics.SetGauge([]string{"nomad", "broker", sched, "unacked"}, float32(schedStats.Unacked))
 			}
+			for ns, nsStats := range stats.ByNamespace {
+				labels := []metrics.Label{{Name: "namespace", Value: ns}}
+				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "namespace", "ready"}, float32(nsStats.Ready), labels)
+				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "namespace", "unacked"}, float32(nsStats.Unacked), labels)
+				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "namespace", "blocked"}, float32(nsStats.Blocked), labels)
+			}
 
 		case <-stopCh:
 			return
@@ -835,11 +951,13 @@ func (b *EvalBroker) EmitStats(period time.Duration, stopCh chan struct{}) {
 
 // BrokerStats returns all the stats about the broker
 type BrokerStats struct {
-	TotalReady   int
-	TotalUnacked int
-	TotalBlocked int
-	TotalWaiting int
-	ByScheduler  map[string]*SchedulerStats
+	TotalReady      int
+	TotalUnacked    int
+	TotalBlocked    int
+	TotalWaiting    int
+	TotalCancelable int
+	ByScheduler     map[string]*SchedulerStats
+	ByNamespace     map[string]*NamespaceStats
 }
 
 // SchedulerStats returns the stats per scheduler
@@ -848,6 +966,13 @@ type SchedulerStats struct {
 	Unacked int
 }
 
+// NamespaceStats returns the stats per namespace
+type NamespaceStats struct {
+	Ready   int
+	Unacked int
+	Blocked int
+}
+
 // Len is for the sorting interface
 func (p PendingEvaluations) Len() int {
 	return len(p)
@@ -890,3 +1015,164 @@ func (p PendingEvaluations) Peek() *structs.Evaluation {
 	}
 	return p[n-1]
 }
+
+// Coalesce returns the most recent evaluation along with the others, which are
+// made redundant by it.
+func (p PendingEvaluations) Coalesce() (*structs.Evaluation, []*structs.Evaluation) {
+	latest := 0
+	for i, eval := range p {
+		l := p[latest]
+		if eval.ModifyIndex > l.ModifyIndex ||
+			(eval.ModifyIndex == l.ModifyIndex && eval.CreateIndex > l.CreateIndex) {
+			latest = i
+		}
+	}
+
+	canceled := make([]*structs.Evaluation, 0, len(p)-1)
+	for i, eval := range p {
+		if i != latest {
+			canceled = append(canceled, eval)
+		}
+	}
+	return p[latest], canceled
+}
+
+// ReadyEvaluations is the queue of evaluations ready to be dequeued by a
+// scheduler. Evaluations are dequeued by priority. Within a priority, each
+// namespace has its own FIFO queue and the queues are served using weighted
+// fair queueing: each namespace has a virtual time that advances by the
+// inverse of its weight when one of its evaluations is dequeued, and the
+// namespace with the lowest virtual time is served next.
+type ReadyEvaluations struct {
+	levels map[int]*readyLevel
+
+	// seq orders the namespace queues by when they became ready
+	seq uint64
+}
+
+// readyLevel holds the ready evaluations of a single priority
+type readyLevel struct {
+	// namespaces holds the ready evaluations by namespace
+	namespaces map[string]*readyNamespace
+
+	// vtime is the virtual time of the namespace that was last served. A
+	// namespace that becomes ready starts at vtime so that it can not claim
+	// the time it was idle.
+	vtime float64
+
+	// idle is the virtual time of namespaces that were served until their
+	// queue was empty, so that they resume from it if they become ready
+	// again while the priority still has work.
+	idle map[string]float64
+}
+
+// readyNamespace holds the ready evaluations of a namespace at a priority
+type readyNamespace struct {
+	evals PendingEvaluations
+	vtime float64
+	seq   uint64
+}
+
+// NewReadyEvaluations returns an empty ready queue
+func NewReadyEvaluations() *ReadyEvaluations {
+	return &ReadyEvaluations{
+		levels: make(map[int]*readyLevel),
+	}
+}
+
+// Push adds an evaluation to the queue
+func (r *ReadyEvaluations) Push(eval *structs.Evaluation) {
+	level, ok := r.levels[eval.Priority]
+	if !ok {
+		level = &readyLevel{
+			namespaces: make(map[string]*readyNamespace),
+			idle:       make(map[string]float64),
+		}
+		r.levels[eval.Priority] = level
+	}
+
+	ns, ok := level.namespaces[eval.Namespace]
+	if !ok {
+		vtime := level.vtime
+		if idle, ok := level.idle[eval.Namespace]; ok && idle > vtime {
+			vtime = idle
+		}
+		delete(level.idle, eval.Namespace)
+
+		r.seq++
+		ns = &readyNamespace{
+			vtime: vtime,
+			seq:   r.seq,
+		}
+		level.namespaces[eval.Namespace] = ns
+	}
+
+	heap.Push(&ns.evals, eval)
+}
+
+// Peek returns the next evaluation that would be popped
+func (r *ReadyEvaluations) Peek() *structs.Evaluation {
+	_, ns := r.next()
+	if ns == nil {
+		return nil
+	}
+	return ns.evals.Peek()
+}
+
+// Pop removes the next evaluation from the queue. The weight function returns
+// the weight of a namespace.
+func (r *ReadyEvaluations) Pop(weight func(namespace string) int) *structs.Evaluation {
+	level, ns := r.next()
+	if ns == nil {
+		return nil
+	}
+
+	eval := heap.Pop(&ns.evals).(*structs.Evaluation)
+	level.vtime = ns.vtime
+	ns.vtime += 1 / float64(weight(eval.Namespace))
+
+	// Drop the empty queues
+	if len(ns.evals) == 0 {
+		delete(level.namespaces, eval.Namespace)
+		level.idle[eval.Namespace] = ns.vtime
+	}
+	if len(level.namespaces) == 0 {
+		delete(r.levels, eval.Priority)
+	}
+	return eval
+}
+
+// next returns the priority level and namespace that should be served next
+func (r *ReadyEvaluations) next() (*readyLevel, *readyNamespace) {
+	var level *readyLevel
+	priority := 0
+	for p, l := range r.levels {
+		if level == nil || p > priority {
+			level, priority = l, p
+		}
+	}
+	if level == nil {
+		return nil, nil
+	}
+
+	// Serve the namespace with the lowest virtual time, breaking ties in
+	// FIFO order.
+	var next *readyNamespace
+	for _, ns := range level.namespaces {
+		if next == nil || ns.before(next) {
+			next = ns
+		}
+	}
+	return level, next
+}
+
+// before returns whether the namespace should be served before the other
+func (n *readyNamespace) before(o *readyNamespace) bool {
+	if n.vtime != o.vtime {
+		return n.vtime < o.vtime
+	}
+	if a, b := n.evals.Peek().CreateIndex, o.evals.Peek().CreateIndex; a != b {
+		return a < b
+	}
+	return n.seq < o.seq
+}
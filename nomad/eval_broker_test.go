@@ -453,7 +453,7 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
-	// Check the stats
+	// Check the stats. eval2 is coalesced into eval3 and is cancelable.
 	stats = b.Stats()
 	if stats.TotalReady != 2 {
 		t.Fatalf("bad: %#v", stats)
@@ -461,16 +461,19 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 	if stats.TotalUnacked != 0 {
 		t.Fatalf("bad: %#v", stats)
 	}
-	if stats.TotalBlocked != 2 {
+	if stats.TotalBlocked != 1 {
+		t.Fatalf("bad: %#v", stats)
+	}
+	if stats.TotalCancelable != 1 {
 		t.Fatalf("bad: %#v", stats)
 	}
 
-	// Dequeue should work
+	// Dequeue should work and serve the other namespace first
 	out, token, err = b.Dequeue(defaultSched, time.Second)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	if out != eval2 {
+	if out != eval4 {
 		t.Fatalf("bad : %#v", out)
 	}
 
@@ -482,12 +485,12 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 	if stats.TotalUnacked != 1 {
 		t.Fatalf("bad: %#v", stats)
 	}
-	if stats.TotalBlocked != 2 {
+	if stats.TotalBlocked != 1 {
 		t.Fatalf("bad: %#v", stats)
 	}
 
 	// Ack out
-	err = b.Ack(eval2.ID, token)
+	err = b.Ack(eval4.ID, token)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -500,7 +503,7 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 	if stats.TotalUnacked != 0 {
 		t.Fatalf("bad: %#v", stats)
 	}
-	if stats.TotalBlocked != 1 {
+	if stats.TotalBlocked != 0 {
 		t.Fatalf("bad: %#v", stats)
 	}
 
@@ -513,66 +516,30 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 		t.Fatalf("bad : %#v", out)
 	}
 
-	// Check the stats
-	stats = b.Stats()
-	if stats.TotalReady != 1 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalUnacked != 1 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalBlocked != 1 {
-		t.Fatalf("bad: %#v", stats)
-	}
-
 	// Ack out
 	err = b.Ack(eval3.ID, token)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
-	// Check the stats
-	stats = b.Stats()
-	if stats.TotalReady != 1 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalUnacked != 0 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalBlocked != 1 {
-		t.Fatalf("bad: %#v", stats)
-	}
-
 	// Dequeue should work
 	out, token, err = b.Dequeue(defaultSched, time.Second)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	if out != eval4 {
+	if out != eval5 {
 		t.Fatalf("bad : %#v", out)
 	}
 
-	// Check the stats
-	stats = b.Stats()
-	if stats.TotalReady != 0 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalUnacked != 1 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalBlocked != 1 {
-		t.Fatalf("bad: %#v", stats)
-	}
-
 	// Ack out
-	err = b.Ack(eval4.ID, token)
+	err = b.Ack(eval5.ID, token)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
 	// Check the stats
 	stats = b.Stats()
-	if stats.TotalReady != 1 {
+	if stats.TotalReady != 0 {
 		t.Fatalf("bad: %#v", stats)
 	}
 	if stats.TotalUnacked != 0 {
@@ -582,44 +549,122 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 		t.Fatalf("bad: %#v", stats)
 	}
 
-	// Dequeue should work
-	out, token, err = b.Dequeue(defaultSched, time.Second)
-	if err != nil {
-		t.Fatalf("err: %v", err)
+	// The coalesced evaluation is cancelable
+	cancelable := b.Cancelable(time.Second)
+	if len(cancelable) != 1 || cancelable[0] != eval2 {
+		t.Fatalf("bad: %#v", cancelable)
 	}
-	if out != eval5 {
-		t.Fatalf("bad : %#v", out)
-	}
-
-	// Check the stats
-	stats = b.Stats()
-	if stats.TotalReady != 0 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalUnacked != 1 {
+	if stats := b.Stats(); stats.TotalCancelable != 0 {
 		t.Fatalf("bad: %#v", stats)
 	}
-	if stats.TotalBlocked != 0 {
-		t.Fatalf("bad: %#v", stats)
+}
+
+// Ensure only the most recent blocked evaluation of a job is enqueued
+func TestEvalBroker_Coalesce(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	eval := mock.Eval()
+	eval.ModifyIndex = 10
+	b.Enqueue(eval)
+
+	// Block many evaluations behind the first
+	var latest *structs.Evaluation
+	for i := 1; i <= 100; i++ {
+		blocked := mock.Eval()
+		blocked.JobID = eval.JobID
+		blocked.ModifyIndex = 10 + uint64(i)
+		b.Enqueue(blocked)
+		latest = blocked
 	}
+	require.Equal(100, b.Stats().TotalBlocked)
 
-	// Ack out
-	err = b.Ack(eval5.ID, token)
-	if err != nil {
-		t.Fatalf("err: %v", err)
+	out, token, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(err)
+	require.Equal(eval, out)
+	require.NoError(b.Ack(eval.ID, token))
+
+	// Only the latest evaluation is enqueued
+	stats := b.Stats()
+	require.Equal(1, stats.TotalReady)
+	require.Equal(0, stats.TotalBlocked)
+	require.Equal(99, stats.TotalCancelable)
+
+	out, token, err = b.Dequeue(defaultSched, time.Second)
+	require.NoError(err)
+	require.Equal(latest, out)
+	require.NoError(b.Ack(out.ID, token))
+
+	cancelable := b.Cancelable(time.Second)
+	require.Len(cancelable, 99)
+	for _, c := range cancelable {
+		require.NotEqual(latest.ID, c.ID)
 	}
 
-	// Check the stats
-	stats = b.Stats()
-	if stats.TotalReady != 0 {
-		t.Fatalf("bad: %#v", stats)
+	// Nothing else is cancelable
+	require.Nil(b.Cancelable(5 * time.Millisecond))
+}
+
+// Ensure namespaces are dequeued fairly by weight within a priority
+func TestEvalBroker_Dequeue_NamespaceFairness(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	b := testBroker(t, 0)
+	b.SetNamespaceWeights(map[string]int{"heavy": 2})
+	b.SetEnabled(true)
+
+	// A burst in one namespace followed by work in two others
+	for i := 0; i < 100; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "burst"
+		eval.CreateIndex = uint64(i)
+		b.Enqueue(eval)
 	}
-	if stats.TotalUnacked != 0 {
-		t.Fatalf("bad: %#v", stats)
+	for i := 0; i < 10; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "light"
+		eval.CreateIndex = uint64(100 + i)
+		b.Enqueue(eval)
+
+		eval = mock.Eval()
+		eval.Namespace = "heavy"
+		eval.CreateIndex = uint64(100 + i)
+		b.Enqueue(eval)
 	}
-	if stats.TotalBlocked != 0 {
-		t.Fatalf("bad: %#v", stats)
+
+	stats := b.Stats()
+	require.Equal(100, stats.ByNamespace["burst"].Ready)
+	require.Equal(10, stats.ByNamespace["light"].Ready)
+	require.Equal(10, stats.ByNamespace["heavy"].Ready)
+
+	// The first 20 dequeues are shared 1:1:2
+	counts := make(map[string]int)
+	var lastBurst uint64
+	for i := 0; i < 20; i++ {
+		out, _, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(err)
+		counts[out.Namespace]++
+
+		// Each namespace is still dequeued in FIFO order
+		if out.Namespace == "burst" {
+			require.True(out.CreateIndex >= lastBurst)
+			lastBurst = out.CreateIndex
+		}
 	}
+	require.Equal(5, counts["burst"])
+	require.Equal(5, counts["light"])
+	require.Equal(10, counts["heavy"])
+
+	// Priority still takes precedence over fairness
+	eval := mock.Eval()
+	eval.Namespace = "burst"
+	eval.Priority = 90
+	b.Enqueue(eval)
+	out, _, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(err)
+	require.Equal(eval, out)
 }
 
 func TestEvalBroker_Enqueue_Disable(t *testing.T) {
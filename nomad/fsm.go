@@ -44,6 +44,14 @@ const (
 	DeploymentSnapshot
 	ACLPolicySnapshot
 	ACLTokenSnapshot
+	RootKeyMetaSnapshot
+	VariablesSnapshot
+	ACLRoleSnapshot
+	ACLAuthMethodSnapshot
+	ACLBindingRuleSnapshot
+	JobSubmissionSnapshot
+	DrainPlanSnapshot
+	CSIVolumeSnapshot
 )
 
 // LogApplier is the definition of a function that can apply a Raft log
@@ -117,6 +125,10 @@ type FSMConfig struct {
 
 	// Region is the region of the server embedding the FSM
 	Region string
+
+	// AuthProviders is the cache of the auth methods' identity providers,
+	// which is invalidated when auth methods are modified
+	AuthProviders *authProviderCache
 }
 
 // NewFSMPath is used to construct a new FSM with a blank state
@@ -244,8 +256,34 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyAllocUpdateDesiredTransition(buf[1:], log.Index)
 	case structs.NodeUpdateEligibilityRequestType:
 		return n.applyNodeEligibilityUpdate(buf[1:], log.Index)
+	case structs.NodeUpdateMetaRequestType:
+		return n.applyNodeMetaUpdate(buf[1:], log.Index)
 	case structs.BatchNodeUpdateDrainRequestType:
 		return n.applyBatchDrainUpdate(buf[1:], log.Index)
+	case structs.RootKeyMetaUpsertRequestType:
+		return n.applyRootKeyMetaUpsert(buf[1:], log.Index)
+	case structs.VarApplyStateRequestType:
+		return n.applyVariableOperation(buf[1:], log.Index)
+	case structs.ACLRoleUpsertRequestType:
+		return n.applyACLRoleUpsert(buf[1:], log.Index)
+	case structs.ACLRoleDeleteRequestType:
+		return n.applyACLRoleDelete(buf[1:], log.Index)
+	case structs.ACLAuthMethodUpsertRequestType:
+		return n.applyACLAuthMethodUpsert(buf[1:], log.Index)
+	case structs.ACLAuthMethodDeleteRequestType:
+		return n.applyACLAuthMethodDelete(buf[1:], log.Index)
+	case structs.ACLBindingRuleUpsertRequestType:
+		return n.applyACLBindingRuleUpsert(buf[1:], log.Index)
+	case structs.ACLBindingRuleDeleteRequestType:
+		return n.applyACLBindingRuleDelete(buf[1:], log.Index)
+	case structs.DrainPlanUpsertRequestType:
+		return n.applyDrainPlanUpsert(buf[1:], log.Index)
+	case structs.CSIVolumeRegisterRequestType:
+		return n.applyCSIVolumeRegister(buf[1:], log.Index)
+	case structs.CSIVolumeDeregisterRequestType:
+		return n.applyCSIVolumeDeregister(buf[1:], log.Index)
+	case structs.CSIVolumeClaimRequestType:
+		return n.applyCSIVolumeClaim(buf[1:], log.Index)
 	}
 
 	// Check enterprise only message types.
@@ -370,6 +408,73 @@ func (n *nomadFSM) applyBatchDrainUpdate(buf []byte, index uint64) interface{} {
 	return nil
 }
 
+// applyDrainPlanUpsert is used to create or update a drain plan along with the
+// node drains it starts
+func (n *nomadFSM) applyDrainPlanUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "drain_plan_upsert"}, time.Now())
+	var req structs.DrainPlanUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertDrainPlan(index, &req); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpsertDrainPlan failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyCSIVolumeRegister is used to register CSI volumes
+func (n *nomadFSM) applyCSIVolumeRegister(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "csi_volume_register"}, time.Now())
+	var req structs.CSIVolumeRegisterRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.CSIVolumeRegister(index, req.Volumes); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: CSIVolumeRegister failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyCSIVolumeDeregister is used to deregister CSI volumes
+func (n *nomadFSM) applyCSIVolumeDeregister(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "csi_volume_deregister"}, time.Now())
+	var req structs.CSIVolumeDeregisterRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.CSIVolumeDeregister(index, req.RequestNamespace(), req.VolumeIDs); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: CSIVolumeDeregister failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyCSIVolumeClaim is used to record or release the claim of an
+// allocation on a CSI volume
+func (n *nomadFSM) applyCSIVolumeClaim(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "csi_volume_claim"}, time.Now())
+	var req structs.CSIVolumeClaimRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	claim := &structs.CSIVolumeClaim{
+		AllocationID: req.AllocationID,
+		NodeID:       req.NodeID,
+		Mode:         req.Claim,
+	}
+	if err := n.state.CSIVolumeClaim(index, req.RequestNamespace(), req.VolumeID, claim); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: CSIVolumeClaim failed: %v", err)
+		return err
+	}
+	return nil
+}
+
 func (n *nomadFSM) applyNodeEligibilityUpdate(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "node_eligibility_update"}, time.Now())
 	var req structs.NodeUpdateEligibilityRequest
@@ -399,6 +504,33 @@ func (n *nomadFSM) applyNodeEligibilityUpdate(buf []byte, index uint64) interfac
 	return nil
 }
 
+// applyNodeMetaUpdate is used to update the meta of a node
+func (n *nomadFSM) applyNodeMetaUpdate(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "node_meta_update"}, time.Now())
+	var req structs.NodeUpdateMetaRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateNodeMeta(index, req.NodeID, req.Meta); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpdateNodeMeta failed: %v", err)
+		return err
+	}
+
+	// Unblock evals for the node's new computed node class if it is in a
+	// ready state.
+	node, err := n.state.NodeByID(nil, req.NodeID)
+	if err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpdateNodeMeta failed to lookup node %q: %v", req.NodeID, err)
+		return err
+	}
+	if node != nil && node.Status == structs.NodeStatusReady {
+		n.blockedEvals.Unblock(node.ComputedClass, index)
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) applyUpsertJob(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "register_job"}, time.Now())
 	var req structs.JobRegisterRequest
@@ -415,7 +547,8 @@ func (n *nomadFSM) applyUpsertJob(buf []byte, index uint64) interface{} {
 	 */
 	req.Job.Canonicalize()
 
-	if err := n.state.UpsertJob(index, req.Job); err != nil {
+	// Store the job along with the source of the new job version
+	if err := n.state.UpsertJobWithSubmission(index, req.Job, req.Submission); err != nil {
 		n.logger.Printf("[ERR] nomad.fsm: UpsertJob failed: %v", err)
 		return err
 	}
@@ -939,6 +1072,108 @@ func (n *nomadFSM) applyACLPolicyDelete(buf []byte, index uint64) interface{} {
 	return nil
 }
 
+// applyACLRoleUpsert is used to upsert a set of roles
+func (n *nomadFSM) applyACLRoleUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_role_upsert"}, time.Now())
+	var req structs.ACLRoleUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLRoles(index, req.Roles); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpsertACLRoles failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLRoleDelete is used to delete a set of roles
+func (n *nomadFSM) applyACLRoleDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_role_delete"}, time.Now())
+	var req structs.ACLRoleDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLRoles(index, req.Names); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: DeleteACLRoles failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLAuthMethodUpsert is used to upsert a set of auth methods
+func (n *nomadFSM) applyACLAuthMethodUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_method_upsert"}, time.Now())
+	var req structs.ACLAuthMethodUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLAuthMethods(index, req.AuthMethods); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpsertACLAuthMethods failed: %v", err)
+		return err
+	}
+
+	if n.config.AuthProviders != nil {
+		names := make([]string, len(req.AuthMethods))
+		for i, method := range req.AuthMethods {
+			names[i] = method.Name
+		}
+		n.config.AuthProviders.invalidate(names...)
+	}
+	return nil
+}
+
+// applyACLAuthMethodDelete is used to delete a set of auth methods
+func (n *nomadFSM) applyACLAuthMethodDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_method_delete"}, time.Now())
+	var req structs.ACLAuthMethodDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLAuthMethods(index, req.Names); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: DeleteACLAuthMethods failed: %v", err)
+		return err
+	}
+
+	if n.config.AuthProviders != nil {
+		n.config.AuthProviders.invalidate(req.Names...)
+	}
+	return nil
+}
+
+// applyACLBindingRuleUpsert is used to upsert a set of binding rules
+func (n *nomadFSM) applyACLBindingRuleUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rule_upsert"}, time.Now())
+	var req structs.ACLBindingRuleUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLBindingRules(index, req.BindingRules); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpsertACLBindingRules failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLBindingRuleDelete is used to delete a set of binding rules
+func (n *nomadFSM) applyACLBindingRuleDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rule_delete"}, time.Now())
+	var req structs.ACLBindingRuleDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLBindingRules(index, req.IDs); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: DeleteACLBindingRules failed: %v", err)
+		return err
+	}
+	return nil
+}
+
 // applyACLTokenUpsert is used to upsert a set of policies
 func (n *nomadFSM) applyACLTokenUpsert(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_upsert"}, time.Now())
@@ -1001,6 +1236,49 @@ func (n *nomadFSM) applyAutopilotUpdate(buf []byte, index uint64) interface{} {
 	return n.state.AutopilotSetConfig(index, &req.Config)
 }
 
+// applyRootKeyMetaUpsert is used to add the metadata of a root key to the
+// keyring
+func (n *nomadFSM) applyRootKeyMetaUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_root_key_meta_upsert"}, time.Now())
+	var req structs.KeyringUpsertRootKeyMetaRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertRootKeyMeta(index, req.RootKeyMeta); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpsertRootKeyMeta failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyVariableOperation is used to write or delete a variable. The
+// VarApplyStateResponse is returned so that check-and-set conflicts can be
+// reported to the caller.
+func (n *nomadFSM) applyVariableOperation(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_operation"}, time.Now())
+	var req structs.VarApplyStateRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	var resp *structs.VarApplyStateResponse
+	var err error
+	switch req.Op {
+	case structs.VarOpSet, structs.VarOpCAS:
+		resp, err = n.state.VarSet(index, &req)
+	case structs.VarOpDelete, structs.VarOpDeleteCAS:
+		resp, err = n.state.VarDelete(index, &req)
+	default:
+		err = fmt.Errorf("unknown variable operation %q", req.Op)
+	}
+	if err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: variable operation %q failed: %v", req.Op, err)
+		return err
+	}
+	return resp
+}
+
 func (n *nomadFSM) Snapshot() (raft.FSMSnapshot, error) {
 	// Create a new snapshot
 	snap, err := n.state.Snapshot()
@@ -1221,6 +1499,78 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case RootKeyMetaSnapshot:
+			meta := new(structs.RootKeyMeta)
+			if err := dec.Decode(meta); err != nil {
+				return err
+			}
+			if err := restore.RootKeyMetaRestore(meta); err != nil {
+				return err
+			}
+
+		case VariablesSnapshot:
+			sv := new(structs.VariableEncrypted)
+			if err := dec.Decode(sv); err != nil {
+				return err
+			}
+			if err := restore.VariableRestore(sv); err != nil {
+				return err
+			}
+
+		case ACLRoleSnapshot:
+			role := new(structs.ACLRole)
+			if err := dec.Decode(role); err != nil {
+				return err
+			}
+			if err := restore.ACLRoleRestore(role); err != nil {
+				return err
+			}
+
+		case ACLAuthMethodSnapshot:
+			method := new(structs.ACLAuthMethod)
+			if err := dec.Decode(method); err != nil {
+				return err
+			}
+			if err := restore.ACLAuthMethodRestore(method); err != nil {
+				return err
+			}
+
+		case ACLBindingRuleSnapshot:
+			rule := new(structs.ACLBindingRule)
+			if err := dec.Decode(rule); err != nil {
+				return err
+			}
+			if err := restore.ACLBindingRuleRestore(rule); err != nil {
+				return err
+			}
+
+		case JobSubmissionSnapshot:
+			sub := new(structs.JobSubmission)
+			if err := dec.Decode(sub); err != nil {
+				return err
+			}
+			if err := restore.JobSubmissionRestore(sub); err != nil {
+				return err
+			}
+
+		case DrainPlanSnapshot:
+			plan := new(structs.DrainPlan)
+			if err := dec.Decode(plan); err != nil {
+				return err
+			}
+			if err := restore.DrainPlanRestore(plan); err != nil {
+				return err
+			}
+
+		case CSIVolumeSnapshot:
+			vol := new(structs.CSIVolume)
+			if err := dec.Decode(vol); err != nil {
+				return err
+			}
+			if err := restore.CSIVolumeRestore(vol); err != nil {
+				return err
+			}
+
 		default:
 			// Check if this is an enterprise only object being restored
 			restorer, ok := n.enterpriseRestorers[snapType]
@@ -1492,18 +1842,50 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistJobSubmissions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistDeployments(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistDrainPlans(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistCSIVolumes(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistACLPolicies(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistACLRoles(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLAuthMethods(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLBindingRules(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistACLTokens(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistRootKeyMetas(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistVariables(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistEnterpriseTables(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
@@ -1756,6 +2138,35 @@ func (s *nomadSnapshot) persistJobVersions(sink raft.SnapshotSink,
 	return nil
 }
 
+// persistJobSubmissions is used to persist the sources of job versions
+func (s *nomadSnapshot) persistJobSubmissions(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the job submissions
+	ws := memdb.NewWatchSet()
+	subs, err := s.snap.JobSubmissions(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := subs.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		sub := raw.(*structs.JobSubmission)
+
+		// Write out a job submission registration
+		sink.Write([]byte{byte(JobSubmissionSnapshot)})
+		if err := encoder.Encode(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistDeployments(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get all the jobs
@@ -1840,6 +2251,187 @@ func (s *nomadSnapshot) persistACLTokens(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistRootKeyMetas(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	ws := memdb.NewWatchSet()
+	metas, err := s.snap.RootKeyMetas(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := metas.Next()
+		if raw == nil {
+			break
+		}
+
+		meta := raw.(*structs.RootKeyMeta)
+		sink.Write([]byte{byte(RootKeyMetaSnapshot)})
+		if err := encoder.Encode(meta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistVariables(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	ws := memdb.NewWatchSet()
+	vars, err := s.snap.Variables(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := vars.Next()
+		if raw == nil {
+			break
+		}
+
+		sv := raw.(*structs.VariableEncrypted)
+		sink.Write([]byte{byte(VariablesSnapshot)})
+		if err := encoder.Encode(sv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistACLRoles is used to persist ACL Roles
+func (s *nomadSnapshot) persistACLRoles(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the roles
+	ws := memdb.NewWatchSet()
+	roles, err := s.snap.ACLRoles(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := roles.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		role := raw.(*structs.ACLRole)
+
+		// Write out a role registration
+		sink.Write([]byte{byte(ACLRoleSnapshot)})
+		if err := encoder.Encode(role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistACLAuthMethods is used to persist ACL auth methods
+func (s *nomadSnapshot) persistACLAuthMethods(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the auth methods
+	ws := memdb.NewWatchSet()
+	methods, err := s.snap.ACLAuthMethods(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := methods.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		method := raw.(*structs.ACLAuthMethod)
+
+		// Write out an auth method registration
+		sink.Write([]byte{byte(ACLAuthMethodSnapshot)})
+		if err := encoder.Encode(method); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistACLBindingRules is used to persist ACL binding rules
+func (s *nomadSnapshot) persistACLBindingRules(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the binding rules
+	ws := memdb.NewWatchSet()
+	rules, err := s.snap.ACLBindingRules(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := rules.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		rule := raw.(*structs.ACLBindingRule)
+
+		// Write out a binding rule registration
+		sink.Write([]byte{byte(ACLBindingRuleSnapshot)})
+		if err := encoder.Encode(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistDrainPlans is used to persist the drain plans
+func (s *nomadSnapshot) persistDrainPlans(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	ws := memdb.NewWatchSet()
+	plans, err := s.snap.DrainPlans(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := plans.Next()
+		if raw == nil {
+			break
+		}
+
+		plan := raw.(*structs.DrainPlan)
+		sink.Write([]byte{byte(DrainPlanSnapshot)})
+		if err := encoder.Encode(plan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistCSIVolumes is used to persist the CSI volumes
+func (s *nomadSnapshot) persistCSIVolumes(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	ws := memdb.NewWatchSet()
+	volumes, err := s.snap.CSIVolumes(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := volumes.Next()
+		if raw == nil {
+			break
+		}
+
+		vol := raw.(*structs.CSIVolume)
+		sink.Write([]byte{byte(CSIVolumeSnapshot)})
+		if err := encoder.Encode(vol); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Release is a no-op, as we just need to GC the pointer
 // to the state store snapshot. There is nothing to explicitly
 // cleanup.
@@ -49,11 +49,12 @@ func testFSM(t *testing.T) *nomadFSM {
 	broker := testBroker(t, 0)
 	dispatcher, _ := testPeriodicDispatcher(t)
 	fsmConfig := &FSMConfig{
-		EvalBroker: broker,
-		Periodic:   dispatcher,
-		Blocked:    NewBlockedEvals(broker),
-		LogOutput:  os.Stderr,
-		Region:     "global",
+		EvalBroker:    broker,
+		Periodic:      dispatcher,
+		Blocked:       NewBlockedEvals(broker),
+		LogOutput:     os.Stderr,
+		Region:        "global",
+		AuthProviders: newAuthProviderCache(),
 	}
 	fsm, err := NewFSM(fsmConfig)
 	if err != nil {
@@ -557,6 +558,40 @@ func TestFSM_UpdateNodeEligibility_Unblock(t *testing.T) {
 	})
 }
 
+func TestFSM_RegisterJob_Submission(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	job := mock.Job()
+	req := structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source: "# example\njob \"example\" {}",
+			Format: structs.JobSubmissionFormatHCL,
+		},
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Register the job twice to create two versions
+	for i := 0; i < 2; i++ {
+		buf, err := structs.Encode(structs.JobRegisterRequestType, req)
+		require.Nil(err)
+		require.Nil(fsm.Apply(makeLog(buf)))
+	}
+
+	// Verify the source was stored with the second version
+	out, err := fsm.State().JobSubmission(nil, job.Namespace, job.ID, 1)
+	require.Nil(err)
+	require.NotNil(out)
+	require.Equal(req.Submission.Source, out.Source)
+	require.Equal(job.ID, out.JobID)
+	require.Equal(job.Namespace, out.Namespace)
+	require.EqualValues(1, out.Version)
+}
+
 func TestFSM_RegisterJob(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -2137,6 +2172,209 @@ func TestFSM_DeleteACLPolicies(t *testing.T) {
 	assert.Nil(t, out)
 }
 
+func TestFSM_UpsertDrainPlan(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	node := mock.Node()
+	require.Nil(fsm.State().UpsertNode(1000, node))
+
+	plan := mock.DrainPlan()
+	plan.Nodes[0].NodeID = node.ID
+	plan.Nodes[0].Status = structs.DrainPlanNodeStatusDraining
+	req := structs.DrainPlanUpsertRequest{
+		Plan: plan,
+		Updates: map[string]*structs.DrainUpdate{
+			node.ID: {
+				DrainStrategy: &structs.DrainStrategy{
+					DrainSpec: plan.DrainSpec,
+				},
+			},
+		},
+	}
+	buf, err := structs.Encode(structs.DrainPlanUpsertRequestType, req)
+	require.Nil(err)
+
+	resp := fsm.Apply(makeLog(buf))
+	require.Nil(resp)
+
+	// Verify the plan and the node drain were written together
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().DrainPlanByID(ws, plan.ID)
+	require.Nil(err)
+	require.NotNil(out)
+	require.Equal(uint64(1), out.Nodes[0].DrainIndex)
+
+	outNode, err := fsm.State().NodeByID(ws, node.ID)
+	require.Nil(err)
+	require.NotNil(outNode.DrainStrategy)
+	require.Equal(structs.NodeSchedulingIneligible, outNode.SchedulingEligibility)
+}
+
+func TestFSM_UpsertACLRoles(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	role := mock.ACLRole()
+	req := structs.ACLRoleUpsertRequest{
+		Roles: []*structs.ACLRole{role},
+	}
+	buf, err := structs.Encode(structs.ACLRoleUpsertRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().ACLRoleByName(ws, role.Name)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestFSM_DeleteACLRoles(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	role := mock.ACLRole()
+	err := fsm.State().UpsertACLRoles(1000, []*structs.ACLRole{role})
+	assert.Nil(t, err)
+
+	req := structs.ACLRoleDeleteRequest{
+		Names: []string{role.Name},
+	}
+	buf, err := structs.Encode(structs.ACLRoleDeleteRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are NOT registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().ACLRoleByName(ws, role.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
+func TestFSM_UpsertACLAuthMethods(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	method := mock.ACLAuthMethod()
+	req := structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{method},
+	}
+	buf, err := structs.Encode(structs.ACLAuthMethodUpsertRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().ACLAuthMethodByName(ws, method.Name)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestFSM_DeleteACLAuthMethods(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	method := mock.ACLAuthMethod()
+	err := fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method})
+	assert.Nil(t, err)
+	fsm.config.AuthProviders.providers[method.Name] = &authProvider{}
+
+	req := structs.ACLAuthMethodDeleteRequest{
+		Names: []string{method.Name},
+	}
+	buf, err := structs.Encode(structs.ACLAuthMethodDeleteRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are NOT registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().ACLAuthMethodByName(ws, method.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+
+	// Verify the cached provider was invalidated
+	assert.NotContains(t, fsm.config.AuthProviders.providers, method.Name)
+}
+
+func TestFSM_UpsertACLBindingRules(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	rule := mock.ACLBindingRule()
+	req := structs.ACLBindingRuleUpsertRequest{
+		BindingRules: []*structs.ACLBindingRule{rule},
+	}
+	buf, err := structs.Encode(structs.ACLBindingRuleUpsertRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().ACLBindingRuleByID(ws, rule.ID)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestFSM_DeleteACLBindingRules(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	rule := mock.ACLBindingRule()
+	err := fsm.State().UpsertACLBindingRules(1000, []*structs.ACLBindingRule{rule})
+	assert.Nil(t, err)
+
+	req := structs.ACLBindingRuleDeleteRequest{
+		IDs: []string{rule.ID},
+	}
+	buf, err := structs.Encode(structs.ACLBindingRuleDeleteRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are NOT registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().ACLBindingRuleByID(ws, rule.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
 func TestFSM_BootstrapACLTokens(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -2563,6 +2801,29 @@ func TestFSM_SnapshotRestore_JobVersions(t *testing.T) {
 	}
 }
 
+func TestFSM_SnapshotRestore_JobSubmissions(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	job := mock.Job()
+	state.UpsertJob(1000, job)
+	sub := &structs.JobSubmission{
+		Source:    "job \"example\" {}",
+		Format:    structs.JobSubmissionFormatHCL,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+		Version:   job.Version,
+	}
+	state.UpsertJobSubmission(1001, sub)
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, _ := state2.JobSubmission(nil, job.Namespace, job.ID, job.Version)
+	require.Equal(t, sub, out)
+}
+
 func TestFSM_SnapshotRestore_Deployments(t *testing.T) {
 	t.Parallel()
 	// Add some state
@@ -2612,6 +2873,83 @@ func TestFSM_SnapshotRestore_ACLPolicy(t *testing.T) {
 	assert.Equal(t, p2, out2)
 }
 
+func TestFSM_SnapshotRestore_ACLRoles(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	state.UpsertACLRoles(1000, []*structs.ACLRole{r1, r2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.ACLRoleByName(ws, r1.Name)
+	out2, _ := state2.ACLRoleByName(ws, r2.Name)
+	assert.Equal(t, r1, out1)
+	assert.Equal(t, r2, out2)
+}
+
+func TestFSM_SnapshotRestore_DrainPlans(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	p1 := mock.DrainPlan()
+	p2 := mock.DrainPlan()
+	state.UpsertDrainPlan(1000, &structs.DrainPlanUpsertRequest{Plan: p1})
+	state.UpsertDrainPlan(1001, &structs.DrainPlanUpsertRequest{Plan: p2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.DrainPlanByID(ws, p1.ID)
+	out2, _ := state2.DrainPlanByID(ws, p2.ID)
+	assert.Equal(t, p1, out1)
+	assert.Equal(t, p2, out2)
+}
+
+func TestFSM_SnapshotRestore_ACLAuthMethods(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	m1 := mock.ACLAuthMethod()
+	m2 := mock.ACLAuthMethod()
+	state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{m1, m2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.ACLAuthMethodByName(ws, m1.Name)
+	out2, _ := state2.ACLAuthMethodByName(ws, m2.Name)
+	assert.Equal(t, m1, out1)
+	assert.Equal(t, m2, out2)
+}
+
+func TestFSM_SnapshotRestore_ACLBindingRules(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	r1 := mock.ACLBindingRule()
+	r2 := mock.ACLBindingRule()
+	state.UpsertACLBindingRules(1000, []*structs.ACLBindingRule{r1, r2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.ACLBindingRuleByID(ws, r1.ID)
+	out2, _ := state2.ACLBindingRuleByID(ws, r2.ID)
+	assert.Equal(t, r1, out1)
+	assert.Equal(t, r2, out2)
+}
+
 func TestFSM_SnapshotRestore_ACLTokens(t *testing.T) {
 	t.Parallel()
 	// Add some state
@@ -2631,6 +2969,37 @@ func TestFSM_SnapshotRestore_ACLTokens(t *testing.T) {
 	assert.Equal(t, tk2, out2)
 }
 
+func TestFSM_SnapshotRestore_Variables(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	key := structs.NewRootKey(uuid.Generate(), []byte("secret"))
+	state.UpsertRootKeyMeta(1000, key.Meta)
+	sv := &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace: structs.DefaultNamespace,
+			Path:      "app/db",
+		},
+		VariableData: structs.VariableData{
+			Data:  []byte("encrypted"),
+			KeyID: key.Meta.KeyID,
+		},
+	}
+	state.VarSet(1001, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: sv})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	outKey, _ := state2.RootKeyMetaByID(ws, key.Meta.KeyID)
+	outVar, _ := state2.VarGet(ws, sv.Namespace, sv.Path)
+	assert.Equal(t, key.Meta.KeyID, outKey.KeyID)
+	assert.True(t, outKey.IsActive())
+	assert.Equal(t, sv.Data, outVar.Data)
+	assert.Equal(t, uint64(1001), outVar.ModifyIndex)
+}
+
 func TestFSM_SnapshotRestore_AddMissingSummary(t *testing.T) {
 	t.Parallel()
 	// Add some state
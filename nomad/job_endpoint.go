@@ -51,6 +51,11 @@ var (
 // Job endpoint is used for job interactions
 type Job struct {
 	srv *Server
+
+	// mutators and validators make up the admission chain run on jobs
+	// before they are registered or planned
+	mutators   []jobMutator
+	validators []jobValidator
 }
 
 // Register is used to upsert a job for scheduling
@@ -65,22 +70,8 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return fmt.Errorf("missing job for registration")
 	}
 
-	// Initialize the job fields (sets defaults and any necessary init work).
-	canonicalizeWarnings := args.Job.Canonicalize()
-
-	// Add implicit constraints
-	setImplicitConstraints(args.Job)
-
-	// Validate the job and capture any warnings
-	err, warnings := validateJob(args.Job)
-	if err != nil {
-		return err
-	}
-
-	// Set the warning message
-	reply.Warnings = structs.MergeMultierrorWarnings(warnings, canonicalizeWarnings)
-
-	// Check job submission permissions
+	// Check job submission permissions. This is done before admission so
+	// jobs are only sent to admission webhooks on behalf of allowed users.
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
 	} else if aclObj != nil {
@@ -97,6 +88,29 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		}
 	}
 
+	// Run the admission controllers, which canonicalize, mutate and validate
+	// the job and capture any warnings
+	job, warnings, err := j.admissionControllers(admissionOpRegister, args.Job)
+	if err != nil {
+		return err
+	}
+	args.Job = job
+
+	// Drop the submitted source if it is too large to be stored
+	if sub := args.Submission; sub != nil {
+		if err := sub.Validate(); err != nil {
+			return err
+		}
+		if len(sub.Source) > structs.JobSubmissionMaxSize {
+			warnings = append(warnings, fmt.Errorf("job source of %d bytes exceeds the limit of %d bytes and was not stored",
+				len(sub.Source), structs.JobSubmissionMaxSize))
+			args.Submission = nil
+		}
+	}
+
+	// Set the warning message
+	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
+
 	// Lookup the job
 	snap, err := j.srv.State().Snapshot()
 	if err != nil {
@@ -171,8 +185,8 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 	if policyWarnings != nil {
-		reply.Warnings = structs.MergeMultierrorWarnings(warnings,
-			canonicalizeWarnings, policyWarnings)
+		warnings = append(warnings, policyWarnings)
+		reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
 	}
 
 	// Clear the Vault token
@@ -318,10 +332,10 @@ func (j *Job) Summary(args *structs.JobSummaryRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "job_summary", "get_job_summary"}, time.Now())
 
-	// Check for read-job permissions
+	// Check for read-job-status permissions
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJobStatus) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -438,9 +452,16 @@ func (j *Job) Revert(args *structs.JobRevertRequest, reply *structs.JobRegisterR
 		return fmt.Errorf("job %q in namespace %q at version %d not found", args.JobID, args.RequestNamespace(), args.JobVersion)
 	}
 
+	// Carry over the source of the version being reverted to
+	sub, err := snap.JobSubmission(ws, args.RequestNamespace(), args.JobID, args.JobVersion)
+	if err != nil {
+		return err
+	}
+
 	// Build the register request
 	reg := &structs.JobRegisterRequest{
 		Job:          jobV.Copy(),
+		Submission:   sub.Copy(),
 		WriteRequest: args.WriteRequest,
 	}
 
@@ -860,6 +881,52 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// GetJobSubmission is used to retrieve the original source of a job version
+func (j *Job) GetJobSubmission(args *structs.JobSubmissionRequest,
+	reply *structs.JobSubmissionResponse) error {
+	if done, err := j.srv.forward("Job.GetJobSubmission", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "get_job_submission"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the submission
+			out, err := state.JobSubmission(ws, args.RequestNamespace(), args.JobID, args.Version)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Submission = out
+			if out != nil {
+				reply.Index = out.JobModifyIndex
+			} else {
+				// Use the last index that affected the job submission table
+				index, err := state.Index("job_submission")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // List is used to list the jobs registered in the system
 func (j *Job) List(args *structs.JobListRequest,
 	reply *structs.JobListResponse) error {
@@ -933,10 +1000,10 @@ func (j *Job) Allocations(args *structs.JobSpecificRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "job", "allocations"}, time.Now())
 
-	// Check for read-job permissions
+	// Check for read-job-status permissions
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJobStatus) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -982,10 +1049,10 @@ func (j *Job) Evaluations(args *structs.JobSpecificRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "job", "evaluations"}, time.Now())
 
-	// Check for read-job permissions
+	// Check for read-job-status permissions
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJobStatus) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -1016,6 +1083,75 @@ func (j *Job) Evaluations(args *structs.JobSpecificRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// PlacementStatus is used to explain why the task groups of a job could not
+// be placed
+func (j *Job) PlacementStatus(args *structs.JobSpecificRequest,
+	reply *structs.JobPlacementStatusResponse) error {
+	if done, err := j.srv.forward("Job.PlacementStatus", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "placement_status"}, time.Now())
+
+	// Check for read-job-status permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJobStatus) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			job, err := state.JobByID(ws, args.RequestNamespace(), args.JobID)
+			if err != nil {
+				return err
+			}
+
+			// Use the last index that affected the evals table
+			index, err := state.Index("evals")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			reply.Status = nil
+			if job == nil {
+				j.srv.setQueryMeta(&reply.QueryMeta)
+				return nil
+			}
+
+			summary, err := state.JobSummaryByID(ws, args.RequestNamespace(), args.JobID)
+			if err != nil {
+				return err
+			}
+			evals, err := state.EvalsByJob(ws, args.RequestNamespace(), args.JobID)
+			if err != nil {
+				return err
+			}
+
+			// Resolve the computed classes blocked evaluations wait on to
+			// the node classes users know.
+			iter, err := state.Nodes(ws)
+			if err != nil {
+				return err
+			}
+			nodeClasses := make(map[string]string)
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				node := raw.(*structs.Node)
+				nodeClasses[node.ComputedClass] = node.NodeClass
+			}
+
+			reply.Status = structs.NewJobPlacementStatus(args.RequestNamespace(), args.JobID, summary, evals, nodeClasses)
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+
+	return j.srv.blockingRPC(&opts)
+}
+
 // Deployments is used to list the deployments for a job
 func (j *Job) Deployments(args *structs.JobSpecificRequest,
 	reply *structs.DeploymentListResponse) error {
@@ -1024,10 +1160,10 @@ func (j *Job) Deployments(args *structs.JobSpecificRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "job", "deployments"}, time.Now())
 
-	// Check for read-job permissions
+	// Check for read-job-status permissions
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJobStatus) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -1066,10 +1202,10 @@ func (j *Job) LatestDeployment(args *structs.JobSpecificRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "job", "latest_deployment"}, time.Now())
 
-	// Check for read-job permissions
+	// Check for read-job-status permissions
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJobStatus) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -1118,21 +1254,6 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		return fmt.Errorf("Job required for plan")
 	}
 
-	// Initialize the job fields (sets defaults and any necessary init work).
-	canonicalizeWarnings := args.Job.Canonicalize()
-
-	// Add implicit constraints
-	setImplicitConstraints(args.Job)
-
-	// Validate the job and capture any warnings
-	err, warnings := validateJob(args.Job)
-	if err != nil {
-		return err
-	}
-
-	// Set the warning message
-	reply.Warnings = structs.MergeMultierrorWarnings(warnings, canonicalizeWarnings)
-
 	// Check job submission permissions, which we assume is the same for plan
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
@@ -1148,14 +1269,25 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		}
 	}
 
+	// Run the admission controllers, which canonicalize, mutate and validate
+	// the job and capture any warnings
+	job, warnings, err := j.admissionControllers(admissionOpPlan, args.Job)
+	if err != nil {
+		return err
+	}
+	args.Job = job
+
+	// Set the warning message
+	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
+
 	// Enforce Sentinel policies
 	policyWarnings, err := j.enforceSubmitJob(args.PolicyOverride, args.Job)
 	if err != nil {
 		return err
 	}
 	if policyWarnings != nil {
-		reply.Warnings = structs.MergeMultierrorWarnings(warnings,
-			canonicalizeWarnings, policyWarnings)
+		warnings = append(warnings, policyWarnings)
+		reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
 	}
 
 	// Acquire a snapshot of the state
@@ -0,0 +1,340 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/hashicorp/go-cleanhttp"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// admissionOpRegister and admissionOpPlan are the operations sent to
+	// admission webhooks.
+	admissionOpRegister = "register"
+	admissionOpPlan     = "plan"
+
+	// maxAdmissionResponseSize limits the size of a webhook response
+	maxAdmissionResponseSize = 10 * 1024 * 1024
+)
+
+// admissionController is a step of the admission chain run on jobs before
+// they are registered or planned.
+type admissionController interface {
+	Name() string
+}
+
+// jobMutator is an admissionController that may modify the job. Mutators
+// run before validators so that validators see the final job.
+type jobMutator interface {
+	admissionController
+	Mutate(op string, job *structs.Job) (out *structs.Job, warnings []error, err error)
+}
+
+// jobValidator is an admissionController that may reject the job.
+type jobValidator interface {
+	admissionController
+	Validate(op string, job *structs.Job) (warnings []error, err error)
+}
+
+// NewJobEndpoints returns the Job endpoint with its admission chain built
+// from the server configuration.
+func NewJobEndpoints(s *Server) *Job {
+	j := &Job{srv: s}
+
+	// The built-in mutators canonicalize the job before any webhook sees it
+	// and gate memory oversubscription and add implicit constraints after
+	// all webhooks have run, so they also apply to anything a webhook
+	// injected.
+	j.mutators = []jobMutator{jobCanonicalizer{}}
+	j.validators = []jobValidator{jobValidate{}}
+	for _, conf := range s.config.AdmissionWebhooks {
+		hook := newAdmissionWebhook(conf)
+		switch conf.Type {
+		case config.AdmissionWebhookMutating:
+			j.mutators = append(j.mutators, hook)
+		default:
+			j.validators = append(j.validators, hook)
+		}
+	}
+	j.mutators = append(j.mutators,
+		jobMemoryOversubscription{enabled: s.config.MemoryOversubscriptionEnabled},
+		jobImplicitConstraints{})
+
+	return j
+}
+
+// admissionControllers runs the mutators and then the validators on the job.
+// The returned job must be used in place of the given one.
+func (j *Job) admissionControllers(op string, job *structs.Job) (out *structs.Job, warnings []error, err error) {
+	out, warnings, err = j.admissionMutators(op, job)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	validateWarnings, err := j.admissionValidators(op, out)
+	warnings = append(warnings, validateWarnings...)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	return out, warnings, nil
+}
+
+// admissionMutators runs the mutators in order, passing the job returned by
+// each to the next.
+func (j *Job) admissionMutators(op string, job *structs.Job) (*structs.Job, []error, error) {
+	var warnings []error
+	for _, mutator := range j.mutators {
+		out, w, err := mutator.Mutate(op, job)
+		warnings = append(warnings, w...)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("job mutation by %q failed: %v", mutator.Name(), err)
+		}
+		job = out
+	}
+	return job, warnings, nil
+}
+
+// admissionValidators runs all validators and returns the combined errors so
+// that every violation is reported at once.
+func (j *Job) admissionValidators(op string, job *structs.Job) ([]error, error) {
+	var warnings []error
+	var mErr multierror.Error
+	for _, validator := range j.validators {
+		w, err := validator.Validate(op, job)
+		warnings = append(warnings, w...)
+		if err != nil {
+			multierror.Append(&mErr, err)
+		}
+	}
+	return warnings, mErr.ErrorOrNil()
+}
+
+// jobCanonicalizer sets the defaults of the job
+type jobCanonicalizer struct{}
+
+func (jobCanonicalizer) Name() string {
+	return "canonicalize"
+}
+
+func (jobCanonicalizer) Mutate(_ string, job *structs.Job) (*structs.Job, []error, error) {
+	return job, flattenWarnings(job.Canonicalize()), nil
+}
+
+// jobImplicitConstraints adds the constraints implied by the features the job
+// uses, such as Vault policies and signals.
+type jobImplicitConstraints struct{}
+
+func (jobImplicitConstraints) Name() string {
+	return "implicit-constraints"
+}
+
+func (jobImplicitConstraints) Mutate(_ string, job *structs.Job) (*structs.Job, []error, error) {
+	setImplicitConstraints(job)
+	return job, nil, nil
+}
+
+// jobMemoryOversubscription drops the memory limit of tasks when memory
+// oversubscription isn't enabled on the servers, so that tasks are limited to
+// the memory they reserve.
+type jobMemoryOversubscription struct {
+	enabled bool
+}
+
+func (jobMemoryOversubscription) Name() string {
+	return "memory-oversubscription"
+}
+
+func (m jobMemoryOversubscription) Mutate(_ string, job *structs.Job) (*structs.Job, []error, error) {
+	if m.enabled {
+		return job, nil, nil
+	}
+
+	var warnings []error
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			if task.Resources == nil || task.Resources.MemoryMaxMB == 0 {
+				continue
+			}
+			task.Resources.MemoryMaxMB = 0
+			warnings = append(warnings, fmt.Errorf("task %q in group %q: memory_max is ignored as memory oversubscription is not enabled", task.Name, tg.Name))
+		}
+	}
+	return job, warnings, nil
+}
+
+// jobValidate runs the built-in job and driver validation
+type jobValidate struct{}
+
+func (jobValidate) Name() string {
+	return "validate"
+}
+
+func (jobValidate) Validate(_ string, job *structs.Job) ([]error, error) {
+	err, warnings := validateJob(job)
+	return flattenWarnings(warnings), err
+}
+
+// flattenWarnings turns a possibly multierror warning into a list
+func flattenWarnings(warnings error) []error {
+	if warnings == nil {
+		return nil
+	}
+	if mErr, ok := warnings.(*multierror.Error); ok {
+		return mErr.Errors
+	}
+	return []error{warnings}
+}
+
+// AdmissionRequest is the body POSTed to admission webhooks
+type AdmissionRequest struct {
+	// Operation is either "register" or "plan"
+	Operation string
+
+	// Job is the job being submitted
+	Job *structs.Job
+}
+
+// AdmissionResponse is the body returned by admission webhooks
+type AdmissionResponse struct {
+	// Allowed must be set for the job to be admitted
+	Allowed bool
+
+	// Reason is returned to the user when the job is rejected
+	Reason string
+
+	// Warnings are returned to the user whether or not the job is admitted
+	Warnings []string
+
+	// Job is the modified job returned by mutating webhooks. It is ignored
+	// for validating webhooks and may be omitted to leave the job unchanged.
+	Job *structs.Job
+}
+
+// admissionWebhook is an admission controller implemented by an external
+// HTTP service.
+type admissionWebhook struct {
+	conf   *config.AdmissionWebhookConfig
+	client *http.Client
+}
+
+func newAdmissionWebhook(conf *config.AdmissionWebhookConfig) *admissionWebhook {
+	client := cleanhttp.DefaultClient()
+	client.Timeout = conf.Timeout
+	return &admissionWebhook{
+		conf:   conf,
+		client: client,
+	}
+}
+
+func (w *admissionWebhook) Name() string {
+	return w.conf.Name
+}
+
+func (w *admissionWebhook) Mutate(op string, job *structs.Job) (*structs.Job, []error, error) {
+	resp, warnings, err := w.call(op, job)
+	if err != nil || resp == nil || resp.Job == nil {
+		return job, warnings, err
+	}
+
+	// Webhooks may not move the job
+	out := resp.Job
+	if out.ID != job.ID || out.Namespace != job.Namespace || out.Region != job.Region {
+		return nil, warnings, fmt.Errorf("mutated job must have the same ID, namespace and region")
+	}
+
+	// The Vault token is never sent to webhooks so restore it
+	out.VaultToken = job.VaultToken
+
+	// Set the defaults of anything the webhook added
+	warnings = append(warnings, flattenWarnings(out.Canonicalize())...)
+	return out, warnings, nil
+}
+
+func (w *admissionWebhook) Validate(op string, job *structs.Job) ([]error, error) {
+	_, warnings, err := w.call(op, job)
+	return warnings, err
+}
+
+// call sends the job to the webhook. A nil response is returned without an
+// error if the webhook failed and its failure policy is to ignore failures.
+func (w *admissionWebhook) call(op string, job *structs.Job) (*AdmissionResponse, []error, error) {
+	resp, err := w.do(op, job)
+	if err != nil {
+		if w.conf.FailurePolicy == config.AdmissionFailurePolicyIgnore {
+			warning := fmt.Errorf("admission webhook %q failed and was ignored: %v", w.conf.Name, err)
+			return nil, []error{warning}, nil
+		}
+		return nil, nil, fmt.Errorf("admission webhook %q failed: %v", w.conf.Name, err)
+	}
+
+	warnings := make([]error, 0, len(resp.Warnings))
+	for _, warning := range resp.Warnings {
+		warnings = append(warnings, fmt.Errorf("%s: %s", w.conf.Name, warning))
+	}
+
+	if !resp.Allowed {
+		reason := resp.Reason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		return nil, warnings, fmt.Errorf("admission webhook %q rejected job: %s", w.conf.Name, reason)
+	}
+
+	return resp, warnings, nil
+}
+
+func (w *admissionWebhook) do(op string, job *structs.Job) (*AdmissionResponse, error) {
+	// Strip the Vault token from the job sent to the webhook
+	if job.VaultToken != "" {
+		job = job.Copy()
+		job.VaultToken = ""
+	}
+
+	body, err := json.Marshal(&AdmissionRequest{
+		Operation: op,
+		Job:       job,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.conf.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequest("POST", w.conf.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.conf.Headers {
+		req.Header.Set(k, v)
+	}
+
+	httpResp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(httpResp.Body, 1024))
+		return nil, fmt.Errorf("unexpected response code %d: %s", httpResp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var resp AdmissionResponse
+	dec := json.NewDecoder(io.LimitReader(httpResp.Body, maxAdmissionResponseSize))
+	if err := dec.Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,290 @@
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testAdmissionServer starts an HTTP stand-in for an admission webhook that
+// responds using the given function.
+func testAdmissionServer(t *testing.T, fn func(*AdmissionRequest) *AdmissionResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AdmissionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode admission request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(fn(&req))
+	}))
+}
+
+func TestJobEndpoint_Register_AdmissionWebhooks(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Mutating webhook that injects a log shipping sidecar
+	mutating := testAdmissionServer(t, func(req *AdmissionRequest) *AdmissionResponse {
+		job := req.Job
+		sidecar := job.TaskGroups[0].Tasks[0].Copy()
+		sidecar.Name = "log-shipper"
+		sidecar.Services = nil
+		job.TaskGroups[0].Tasks = append(job.TaskGroups[0].Tasks, sidecar)
+		return &AdmissionResponse{
+			Allowed:  true,
+			Warnings: []string{"injected log-shipper"},
+			Job:      job,
+		}
+	})
+	defer mutating.Close()
+
+	// Validating webhook that requires an owner
+	var ops []string
+	validating := testAdmissionServer(t, func(req *AdmissionRequest) *AdmissionResponse {
+		ops = append(ops, req.Operation)
+		if req.Job.Meta["owner"] == "" {
+			return &AdmissionResponse{Reason: "meta.owner must be set"}
+		}
+		return &AdmissionResponse{Allowed: true}
+	})
+	defer validating.Close()
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionWebhooks = []*config.AdmissionWebhookConfig{
+			{
+				Name:          "sidecar",
+				Type:          config.AdmissionWebhookMutating,
+				URL:           mutating.URL,
+				Timeout:       5 * time.Second,
+				FailurePolicy: config.AdmissionFailurePolicyFail,
+			},
+			{
+				Name:          "owner",
+				Type:          config.AdmissionWebhookValidating,
+				URL:           validating.URL,
+				Timeout:       5 * time.Second,
+				FailurePolicy: config.AdmissionFailurePolicyFail,
+			},
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register a job without an owner and expect a rejection
+	job := mock.Job()
+	delete(job.Meta, "owner")
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), `admission webhook "owner" rejected job: meta.owner must be set`)
+
+	// Plan and register the job with an owner
+	job.Meta["owner"] = "platform"
+	planReq := &structs.JobPlanRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var planResp structs.JobPlanResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp))
+	require.Contains(planResp.Warnings, "sidecar: injected log-shipper")
+
+	resp = structs.JobRegisterResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.Contains(resp.Warnings, "sidecar: injected log-shipper")
+	require.Equal([]string{admissionOpRegister, admissionOpPlan, admissionOpRegister}, ops)
+
+	// The registered job has the sidecar
+	out, err := s1.fsm.State().JobByID(memdb.NewWatchSet(), job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Len(out.TaskGroups[0].Tasks, 2)
+	require.Equal("log-shipper", out.TaskGroups[0].Tasks[1].Name)
+}
+
+func TestJobEndpoint_Register_AdmissionWebhook_Moved(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Mutating webhook that illegally renames the job
+	mutating := testAdmissionServer(t, func(req *AdmissionRequest) *AdmissionResponse {
+		req.Job.ID = "renamed"
+		return &AdmissionResponse{Allowed: true, Job: req.Job}
+	})
+	defer mutating.Close()
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionWebhooks = []*config.AdmissionWebhookConfig{
+			{
+				Name:          "rename",
+				Type:          config.AdmissionWebhookMutating,
+				URL:           mutating.URL,
+				Timeout:       5 * time.Second,
+				FailurePolicy: config.AdmissionFailurePolicyFail,
+			},
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "same ID, namespace and region")
+}
+
+func TestJobEndpoint_Register_MemoryOversubscription(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.MemoryOversubscriptionEnabled = true
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// A memory limit below the reservation is rejected
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Resources.MemoryMaxMB = 128
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "MemoryMaxMB value (128) must be greater than or equal to MemoryMB value (256)")
+
+	// The memory limit is kept when oversubscription is enabled
+	job.TaskGroups[0].Tasks[0].Resources.MemoryMaxMB = 1024
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.Empty(resp.Warnings)
+
+	out, err := s1.fsm.State().JobByID(memdb.NewWatchSet(), job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal(256, out.TaskGroups[0].Tasks[0].Resources.MemoryMB)
+	require.Equal(1024, out.TaskGroups[0].Tasks[0].Resources.MemoryMaxMB)
+}
+
+func TestJobMemoryOversubscription_Disabled(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Resources.MemoryMaxMB = 1024
+
+	out, warnings, err := jobMemoryOversubscription{}.Mutate(admissionOpRegister, job)
+	require.NoError(err)
+	require.Zero(out.TaskGroups[0].Tasks[0].Resources.MemoryMaxMB)
+	require.Len(warnings, 1)
+	require.Contains(warnings[0].Error(), `task "web" in group "web": memory_max is ignored`)
+
+	// Jobs without a memory limit are left alone
+	out, warnings, err = jobMemoryOversubscription{}.Mutate(admissionOpRegister, mock.Job())
+	require.NoError(err)
+	require.Empty(warnings)
+}
+
+func TestAdmissionWebhook_FailurePolicy(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// A webhook that always errors
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	conf := &config.AdmissionWebhookConfig{
+		Name:          "flaky",
+		Type:          config.AdmissionWebhookValidating,
+		URL:           ts.URL,
+		Timeout:       5 * time.Second,
+		FailurePolicy: config.AdmissionFailurePolicyFail,
+	}
+
+	// Failing closed rejects the job
+	warnings, err := newAdmissionWebhook(conf).Validate(admissionOpRegister, mock.Job())
+	require.Error(err)
+	require.Contains(err.Error(), "unexpected response code 503")
+	require.Empty(warnings)
+
+	// Ignoring failures admits the job with a warning
+	conf.FailurePolicy = config.AdmissionFailurePolicyIgnore
+	warnings, err = newAdmissionWebhook(conf).Validate(admissionOpRegister, mock.Job())
+	require.NoError(err)
+	require.Len(warnings, 1)
+	require.Contains(warnings[0].Error(), `admission webhook "flaky" failed and was ignored`)
+}
+
+func TestAdmissionWebhook_VaultToken(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Mutating webhook that records the Vault token it was sent
+	var sent string
+	ts := testAdmissionServer(t, func(req *AdmissionRequest) *AdmissionResponse {
+		sent = req.Job.VaultToken
+		req.Job.Meta = map[string]string{"mutated": "true"}
+		return &AdmissionResponse{Allowed: true, Job: req.Job}
+	})
+	defer ts.Close()
+
+	conf := &config.AdmissionWebhookConfig{
+		Name:          "mutate",
+		Type:          config.AdmissionWebhookMutating,
+		URL:           ts.URL,
+		Timeout:       5 * time.Second,
+		FailurePolicy: config.AdmissionFailurePolicyFail,
+	}
+
+	job := mock.Job()
+	job.VaultToken = "secret"
+	out, _, err := newAdmissionWebhook(conf).Mutate(admissionOpRegister, job)
+	require.NoError(err)
+
+	// The token isn't sent, isn't stripped from the submitted job and is
+	// restored on the mutated job
+	require.Empty(sent)
+	require.Equal("secret", job.VaultToken)
+	require.Equal("secret", out.VaultToken)
+	require.Equal("true", out.Meta["mutated"])
+}
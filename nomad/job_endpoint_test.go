@@ -2270,6 +2270,221 @@ func TestJobEndpoint_GetJob_Blocking(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_GetJobSubmission(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register the job with its source
+	job := mock.Job()
+	source := "# The example job\njob \"example\" {}\n"
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source: source,
+			Format: structs.JobSubmissionFormatHCL,
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Register a new version with a source that is too large
+	job2 := job.Copy()
+	job2.Priority = 100
+	req.Job = job2
+	req.Submission = &structs.JobSubmission{
+		Source: strings.Repeat("#", structs.JobSubmissionMaxSize+1),
+		Format: structs.JobSubmissionFormatHCL,
+	}
+	var resp2 structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp2))
+	require.Contains(resp2.Warnings, "was not stored")
+
+	// Lookup the source of the first version
+	get := &structs.JobSubmissionRequest{
+		JobID:   job.ID,
+		Version: 0,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var getResp structs.JobSubmissionResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &getResp))
+	require.NotNil(getResp.Submission)
+	require.Equal(source, getResp.Submission.Source)
+	require.Equal(structs.JobSubmissionFormatHCL, getResp.Submission.Format)
+	require.Equal(resp.JobModifyIndex, getResp.Index)
+
+	// The second version has no source
+	get.Version = 1
+	getResp = structs.JobSubmissionResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &getResp))
+	require.Nil(getResp.Submission)
+
+	// Reverting to the first version carries over its source
+	revert := &structs.JobRevertRequest{
+		JobID:      job.ID,
+		JobVersion: 0,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var revertResp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Revert", revert, &revertResp))
+
+	get.Version = 2
+	getResp = structs.JobSubmissionResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &getResp))
+	require.NotNil(getResp.Submission)
+	require.Equal(source, getResp.Submission.Source)
+}
+
+func TestJobEndpoint_GetJobSubmission_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create the job and its source
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+	require.NoError(state.UpsertJobSubmission(1001, &structs.JobSubmission{
+		Source:    "job \"example\" {}",
+		Format:    structs.JobSubmissionFormatHCL,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+		Version:   job.Version,
+	}))
+
+	get := &structs.JobSubmissionRequest{
+		JobID:   job.ID,
+		Version: job.Version,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Lookup without a token
+	var resp structs.JobSubmissionResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "Permission denied")
+
+	// Lookup with a token that can only see the status of the job
+	statusToken := mock.CreatePolicyAndToken(t, state, 1003, "test-status",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJobStatus}))
+	get.AuthToken = statusToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "Permission denied")
+
+	// Lookup with a read-job token
+	readToken := mock.CreatePolicyAndToken(t, state, 1005, "test-read",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	get.AuthToken = readToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp))
+	require.NotNil(resp.Submission)
+
+	// Lookup with the root token
+	get.AuthToken = root.SecretID
+	resp = structs.JobSubmissionResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp))
+	require.NotNil(resp.Submission)
+}
+
+func TestJobEndpoint_PlacementStatus(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a node and a job that failed to place on it
+	node := mock.Node()
+	node.NodeClass = "batch"
+	require.NoError(node.ComputeClass())
+	require.NoError(state.UpsertNode(1000, node))
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1001, job))
+	summary := mock.JobSummary(job.ID)
+	summary.Summary["web"] = structs.TaskGroupSummary{Queued: 10}
+	require.NoError(state.UpsertJobSummary(1002, summary))
+
+	failed := mock.Eval()
+	failed.JobID = job.ID
+	failed.Status = structs.EvalStatusComplete
+	failed.FailedTGAllocs = map[string]*structs.AllocMetric{
+		"web": {
+			NodesEvaluated:     1,
+			NodesExhausted:     1,
+			DimensionExhausted: map[string]int{"memory": 1},
+		},
+	}
+	blocked := mock.Eval()
+	blocked.JobID = job.ID
+	blocked.Status = structs.EvalStatusBlocked
+	blocked.ClassEligibility = map[string]bool{node.ComputedClass: true}
+	require.NoError(state.UpsertEvals(1003, []*structs.Evaluation{failed, blocked}))
+
+	get := &structs.JobSpecificRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Lookup without a token
+	var resp structs.JobPlacementStatusResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.PlacementStatus", get, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "Permission denied")
+
+	// Lookup with a token that can read the status of the job
+	statusToken := mock.CreatePolicyAndToken(t, state, 1005, "test-status",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJobStatus}))
+	get.AuthToken = statusToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.PlacementStatus", get, &resp))
+	require.EqualValues(1003, resp.Index)
+
+	status := resp.Status
+	require.NotNil(status)
+	require.Equal(failed.ID, status.EvalID)
+	require.Equal(blocked.ID, status.BlockedEvalID)
+	require.Equal([]string{"batch"}, status.WaitingClasses)
+	require.Len(status.TaskGroups, 1)
+	require.Equal(10, status.TaskGroups["web"].Queued)
+	require.Equal([]*structs.PlacementReason{{Reason: "memory", Nodes: 1}},
+		status.TaskGroups["web"].ExhaustedDimensions)
+
+	// Lookup a missing job
+	get.AuthToken = root.SecretID
+	get.JobID = "missing"
+	resp = structs.JobPlacementStatusResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.PlacementStatus", get, &resp))
+	require.Nil(resp.Status)
+}
+
 func TestJobEndpoint_GetJobVersions(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
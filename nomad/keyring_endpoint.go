@@ -0,0 +1,132 @@
+package nomad
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Keyring endpoint is used to access the keyring used to encrypt variables
+// and sign workload identities
+type Keyring struct {
+	srv *Server
+
+	// ctx provides context regarding the underlying connection
+	ctx *RPCContext
+}
+
+// ListPublic is used to list the public keys used to verify workload
+// identities. The keys are public so no ACL token is required.
+func (k *Keyring) ListPublic(args *structs.GenericRequest, reply *structs.KeyringListPublicResponse) error {
+	if done, err := k.srv.forward("Keyring.ListPublic", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "list_public"}, time.Now())
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.RootKeyMetas(ws)
+			if err != nil {
+				return err
+			}
+
+			reply.PublicKeys = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				meta := raw.(*structs.RootKeyMeta)
+				pub, err := k.srv.encrypter.PublicKey(meta)
+				if err != nil {
+					return err
+				}
+				reply.PublicKeys = append(reply.PublicKeys, pub)
+			}
+
+			// Use the last index that affected the root key metadata table
+			index, err := state.Index("root_key_meta")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking
+			// query cannot be used. We floor the index at one, since
+			// realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return k.srv.blockingRPC(&opts)
+}
+
+// Get is used by servers to fetch the key material of a root key from the
+// keystore of this server. The key material is only returned to callers that
+// present a verified server certificate for the region or prove they have
+// the gossip encryption key of the servers.
+func (k *Keyring) Get(args *structs.KeyringGetRootKeyRequest, reply *structs.KeyringGetRootKeyResponse) error {
+	if !k.srv.isVerifiedServerConn(k.ctx) && !k.srv.validKeyringServerProof(args.KeyID, args.ServerProof) {
+		return structs.ErrPermissionDenied
+	}
+	if done, err := k.srv.forward("Keyring.Get", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "get"}, time.Now())
+
+	// A missing key is not an error so that the caller can try another
+	// server
+	if key, err := k.srv.encrypter.GetKey(args.KeyID); err == nil {
+		reply.Key = key.Copy()
+	}
+
+	index, err := k.srv.State().Index("root_key_meta")
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	k.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// keyringServerProof returns the proof that a request for the root key with
+// the given ID is made by a server of the region, or nil if gossip isn't
+// encrypted.
+func (s *Server) keyringServerProof(keyID string) []byte {
+	keyring := s.config.SerfConfig.MemberlistConfig.Keyring
+	if keyring == nil {
+		return nil
+	}
+	return keyringProof(keyring.GetPrimaryKey(), s.config.Region, keyID)
+}
+
+// validKeyringServerProof returns whether the proof of a request for the root
+// key with the given ID was made with one of the gossip encryption keys.
+func (s *Server) validKeyringServerProof(keyID string, proof []byte) bool {
+	keyring := s.config.SerfConfig.MemberlistConfig.Keyring
+	if keyring == nil || len(proof) == 0 {
+		return false
+	}
+
+	for _, key := range keyring.GetKeys() {
+		if hmac.Equal(proof, keyringProof(key, s.config.Region, keyID)) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyringProof returns the HMAC of the region and key ID keyed by the gossip
+// encryption key.
+func keyringProof(gossipKey []byte, region, keyID string) []byte {
+	mac := hmac.New(sha256.New, gossipKey)
+	mac.Write([]byte(region))
+	mac.Write([]byte{0})
+	mac.Write([]byte(keyID))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,131 @@
+package nomad
+
+import (
+	"net"
+	"testing"
+
+	"github.com/hashicorp/memberlist"
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringEndpoint_ListPublic(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Rotate the key so that the keyring holds two keys
+	key, err := generateRootKey()
+	require.NoError(err)
+	require.NoError(s1.addRootKey(key))
+
+	// The public keys don't require a token
+	req := &structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.KeyringListPublicResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.ListPublic", req, &resp))
+	require.Len(resp.PublicKeys, 2)
+	meta, err := s1.fsm.State().RootKeyMetaByID(nil, key.Meta.KeyID)
+	require.NoError(err)
+	require.Equal(meta.ModifyIndex, resp.Index)
+
+	for _, pub := range resp.PublicKeys {
+		require.Equal(structs.IdentityAlgorithmEdDSA, pub.Algorithm)
+		require.Len(pub.PublicKey, 32)
+	}
+}
+
+// testGossipKey is the gossip encryption key of test servers that exchange
+// root keys.
+var testGossipKey = []byte("0123456789abcdef")
+
+// testGossipKeyring configures the server to encrypt gossip with
+// testGossipKey.
+func testGossipKeyring(t *testing.T, c *Config) {
+	keyring, err := memberlist.NewKeyring(nil, testGossipKey)
+	require.NoError(t, err)
+	c.SerfConfig.MemberlistConfig.Keyring = keyring
+}
+
+func TestKeyringEndpoint_Get(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		testGossipKeyring(t, c)
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	meta, err := s1.State().GetActiveRootKeyMeta(nil)
+	require.NoError(err)
+	require.NotNil(meta)
+
+	req := &structs.KeyringGetRootKeyRequest{
+		KeyID: meta.KeyID,
+		QueryOptions: structs.QueryOptions{
+			Region:     "global",
+			AllowStale: true,
+		},
+	}
+
+	// Callers without a server certificate or proof can't fetch the key
+	// material, even in-process
+	err = s1.RPC("Keyring.Get", req, &structs.KeyringGetRootKeyResponse{})
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	conn, other := net.Pipe()
+	defer conn.Close()
+	defer other.Close()
+	endpoint := &Keyring{srv: s1, ctx: &RPCContext{Conn: conn}}
+	err = endpoint.Get(req, &structs.KeyringGetRootKeyResponse{})
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// A proof made with another gossip key is rejected
+	req.ServerProof = keyringProof([]byte("fedcba9876543210"), "global", meta.KeyID)
+	err = s1.RPC("Keyring.Get", req, &structs.KeyringGetRootKeyResponse{})
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// A proof for another key is rejected
+	req.ServerProof = s1.keyringServerProof("other")
+	err = s1.RPC("Keyring.Get", req, &structs.KeyringGetRootKeyResponse{})
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Servers can fetch the key material
+	req.ServerProof = s1.keyringServerProof(meta.KeyID)
+	var resp structs.KeyringGetRootKeyResponse
+	require.NoError(s1.RPC("Keyring.Get", req, &resp))
+	require.NotNil(resp.Key)
+	require.Equal(meta.KeyID, resp.Key.Meta.KeyID)
+	require.Len(resp.Key.Key, rootKeySize)
+}
+
+func TestKeyringEndpoint_Get_NoGossipEncryption(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	meta, err := s1.State().GetActiveRootKeyMeta(nil)
+	require.NoError(err)
+	require.NotNil(meta)
+
+	// Without gossip encryption there is no proof a caller can make
+	require.Nil(s1.keyringServerProof(meta.KeyID))
+	req := &structs.KeyringGetRootKeyRequest{
+		KeyID:       meta.KeyID,
+		ServerProof: keyringProof(nil, "global", meta.KeyID),
+		QueryOptions: structs.QueryOptions{
+			Region:     "global",
+			AllowStale: true,
+		},
+	}
+	err = s1.RPC("Keyring.Get", req, &structs.KeyringGetRootKeyResponse{})
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+}
@@ -16,7 +16,9 @@ import (
 
 	"github.com/armon/go-metrics"
 	memdb "github.com/hashicorp/go-memdb"
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-version"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -30,6 +32,10 @@ const (
 	// high contention when the schedulers plan does not make progress.
 	failedEvalUnblockInterval = 1 * time.Minute
 
+	// csiVolumeClaimReapInterval is the interval at which the claims of CSI
+	// volumes held by stopped allocations are detached and released.
+	csiVolumeClaimReapInterval = 1 * time.Minute
+
 	// replicationRateLimit is used to rate limit how often data is replicated
 	// between the authoritative region and the local region
 	replicationRateLimit rate.Limit = 10.0
@@ -38,10 +44,18 @@ const (
 	// possible loss of leadership event if we are unable to get a barrier
 	// while leader.
 	barrierWriteTimeout = 2 * time.Minute
+
+	// keyringRotationCheckInterval is the interval at which the leader checks
+	// whether the active root key must be rotated
+	keyringRotationCheckInterval = 10 * time.Minute
 )
 
 var minAutopilotVersion = version.Must(version.NewVersion("0.8.0"))
 
+// minVariablesVersion is the minimum version all servers must run before the
+// keyring used by variables is initialized
+var minVariablesVersion = version.Must(version.NewVersion("0.9.0"))
+
 // monitorLeadership is used to monitor if we acquire or lose our role
 // as the leader in the Raft cluster. There is some work the leader is
 // expected to do, so we must react to changes
@@ -183,6 +197,11 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 		}
 	}
 
+	// Initialize the keyring used to encrypt variables
+	if err := s.initializeKeyring(); err != nil {
+		return err
+	}
+
 	// Initialize and start the autopilot routine
 	s.getOrCreateAutopilotConfig()
 	s.autopilot.Start()
@@ -206,6 +225,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Enable the NodeDrainer
 	s.nodeDrainer.SetEnabled(true, s.State())
 
+	// Enable the drain plan watcher
+	s.drainPlanWatcher.SetEnabled(true, s.State())
+
 	// Restore the eval broker state
 	if err := s.restoreEvals(); err != nil {
 		return err
@@ -234,12 +256,21 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Reap any duplicate blocked evaluations
 	go s.reapDupBlockedEvaluations(stopCh)
 
+	// Reap any evaluations coalesced by the eval broker
+	go s.reapCancelableEvaluations(stopCh)
+
 	// Periodically unblock failed allocations
 	go s.periodicUnblockFailedEvals(stopCh)
 
+	// Release the CSI volume claims of stopped allocations
+	go s.reapCSIVolumeClaims(stopCh)
+
 	// Periodically publish job summary metrics
 	go s.publishJobSummaryMetrics(stopCh)
 
+	// Periodically rotate the root key
+	go s.rotateKeyring(stopCh)
+
 	// Setup the heartbeat timers. This is done both when starting up or when
 	// a leader fail over happens. Since the timers are maintained by the leader
 	// node, effectively this means all the timers are renewed at the time of failover.
@@ -267,6 +298,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// and we are not the authoritative region.
 	if s.config.ACLEnabled && s.config.Region != s.config.AuthoritativeRegion {
 		go s.replicateACLPolicies(stopCh)
+		go s.replicateACLRoles(stopCh)
+		go s.replicateACLAuthMethods(stopCh)
+		go s.replicateACLBindingRules(stopCh)
 		go s.replicateACLTokens(stopCh)
 	}
 
@@ -438,6 +472,8 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer jobGC.Stop()
 	deploymentGC := time.NewTicker(s.config.DeploymentGCInterval)
 	defer deploymentGC.Stop()
+	aclTokenGC := time.NewTicker(s.config.ACLTokenGCInterval)
+	defer aclTokenGC.Stop()
 
 	// getLatest grabs the latest index from the state store. It returns true if
 	// the index was retrieved successfully.
@@ -470,6 +506,13 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := getLatest(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobDeploymentGC, index))
 			}
+		case <-aclTokenGC.C:
+			if !s.config.ACLEnabled {
+				continue
+			}
+			if index, ok := getLatest(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobExpiredACLTokenGC, index))
+			}
 		case <-stopCh:
 			return
 		}
@@ -571,6 +614,47 @@ func (s *Server) reapDupBlockedEvaluations(stopCh chan struct{}) {
 	}
 }
 
+// reapCancelableEvaluations is used to cancel evaluations the eval broker
+// coalesced into a more recent evaluation for the same job.
+func (s *Server) reapCancelableEvaluations(stopCh chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			evals := s.evalBroker.Cancelable(time.Second)
+			if evals == nil {
+				continue
+			}
+
+			// Cancel in batches to bound the size of the Raft entries
+			for len(evals) != 0 {
+				n := len(evals)
+				if n > maxIdsPerReap {
+					n = maxIdsPerReap
+				}
+
+				cancel := make([]*structs.Evaluation, n)
+				for i, eval := range evals[:n] {
+					newEval := eval.Copy()
+					newEval.Status = structs.EvalStatusCancelled
+					newEval.StatusDescription = fmt.Sprintf("canceled after more recent evaluation for job %q was enqueued", newEval.JobID)
+					cancel[i] = newEval
+				}
+				evals = evals[n:]
+
+				// Update via Raft
+				req := structs.EvalUpdateRequest{
+					Evals: cancel,
+				}
+				if _, _, err := s.raftApply(structs.EvalUpdateRequestType, &req); err != nil {
+					s.logger.Printf("[ERR] nomad: failed to cancel coalesced evals: %v", err)
+				}
+			}
+		}
+	}
+}
+
 // periodicUnblockFailedEvals periodically unblocks failed, blocked evaluations.
 func (s *Server) periodicUnblockFailedEvals(stopCh chan struct{}) {
 	ticker := time.NewTicker(failedEvalUnblockInterval)
@@ -586,6 +670,101 @@ func (s *Server) periodicUnblockFailedEvals(stopCh chan struct{}) {
 	}
 }
 
+// reapCSIVolumeClaims periodically releases the CSI volume claims held by
+// allocations that stopped without releasing them, such as allocations on
+// lost nodes.
+func (s *Server) reapCSIVolumeClaims(stopCh chan struct{}) {
+	ticker := time.NewTicker(csiVolumeClaimReapInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := s.releasePastCSIVolumeClaims(); err != nil {
+				s.logger.Printf("[ERR] nomad: failed to release CSI volume claims: %v", err)
+			}
+		}
+	}
+}
+
+// releasePastCSIVolumeClaims releases the claims of stopped allocations. The
+// node of the allocation is asked to unpublish the volume and release the
+// claim if it is up, otherwise the volume is detached by the controller before
+// the claim is released.
+func (s *Server) releasePastCSIVolumeClaims() error {
+	snap, err := s.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	iter, err := snap.CSIVolumes(nil)
+	if err != nil {
+		return err
+	}
+
+	var mErr multierror.Error
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		vol, err := snap.CSIVolumeDenormalize(nil, raw.(*structs.CSIVolume))
+		if err != nil {
+			return err
+		}
+
+		for _, claims := range []map[string]*structs.CSIVolumeClaim{vol.ReadClaims, vol.WriteClaims} {
+			for allocID, claim := range claims {
+				alloc, err := snap.AllocByID(nil, allocID)
+				if err != nil {
+					return err
+				}
+				if alloc != nil && !alloc.ClientTerminalStatus() {
+					continue
+				}
+
+				if err := s.releasePastCSIVolumeClaim(snap, vol, claim); err != nil {
+					multierror.Append(&mErr, fmt.Errorf("volume %q, allocation %q: %v", vol.ID, allocID, err))
+				}
+			}
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// releasePastCSIVolumeClaim releases a claim held by a stopped allocation.
+func (s *Server) releasePastCSIVolumeClaim(snap *state.StateSnapshot, vol *structs.CSIVolume, claim *structs.CSIVolumeClaim) error {
+	node, err := snap.NodeByID(nil, claim.NodeID)
+	if err != nil {
+		return err
+	}
+
+	// The node unpublishes the volume before releasing the claim
+	if node != nil && node.Status != structs.NodeStatusDown {
+		req := &cstructs.ClientCSINodeDetachVolumeRequest{
+			NodeID:          node.ID,
+			AllocID:         claim.AllocationID,
+			VolumeID:        vol.ID,
+			VolumeNamespace: vol.Namespace,
+			QueryOptions: structs.QueryOptions{
+				Region:    s.Region(),
+				AuthToken: s.getLeaderAcl(),
+			},
+		}
+		var resp cstructs.ClientCSINodeDetachVolumeResponse
+		return s.RPC("ClientCSI.NodeDetachVolume", req, &resp)
+	}
+
+	args := &structs.CSIVolumeClaimRequest{
+		VolumeID:     vol.ID,
+		AllocationID: claim.AllocationID,
+		NodeID:       claim.NodeID,
+		Claim:        structs.CSIVolumeClaimRelease,
+		WriteRequest: structs.WriteRequest{
+			Region:    s.Region(),
+			Namespace: vol.Namespace,
+		},
+	}
+	_, err = s.staticEndpoints.CSIVolume.releaseClaim(snap, vol, node, args)
+	return err
+}
+
 // publishJobSummaryMetrics publishes the job summaries as metrics
 func (s *Server) publishJobSummaryMetrics(stopCh chan struct{}) {
 	timer := time.NewTimer(0)
@@ -709,6 +888,9 @@ func (s *Server) revokeLeadership() error {
 	// Disable the node drainer
 	s.nodeDrainer.SetEnabled(false, nil)
 
+	// Disable the drain plan watcher
+	s.drainPlanWatcher.SetEnabled(false, nil)
+
 	// Disable any enterprise systems required.
 	if err := s.revokeEnterpriseLeadership(); err != nil {
 		return err
@@ -1065,6 +1247,429 @@ func diffACLPolicies(state *state.StateStore, minIndex uint64, remoteList []*str
 	return
 }
 
+// replicateACLRoles is used to replicate ACL roles from
+// the authoritative region to this region.
+func (s *Server) replicateACLRoles(stopCh chan struct{}) {
+	req := structs.ACLRoleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Printf("[DEBUG] nomad: starting ACL role replication from authoritative region %q", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of roles
+			var resp structs.ACLRoleListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"ACL.ListRoles", &req, &resp)
+			if err != nil {
+				s.logger.Printf("[ERR] nomad: failed to fetch roles from authoritative region: %v", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLRoles(s.State(), req.MinQueryIndex, resp.Roles)
+
+			// Delete roles that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLRoleDeleteRequest{
+					Names: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLRoleDeleteRequestType, args)
+				if err != nil {
+					s.logger.Printf("[ERR] nomad: failed to delete roles: %v", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated roles
+			var fetched []*structs.ACLRole
+			if len(update) > 0 {
+				req := structs.ACLRoleSetRequest{
+					Names: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLRoleSetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"ACL.GetRoles", &req, &reply); err != nil {
+					s.logger.Printf("[ERR] nomad: failed to fetch roles from authoritative region: %v", err)
+					goto ERR_WAIT
+				}
+				for _, role := range reply.Roles {
+					fetched = append(fetched, role)
+				}
+			}
+
+			// Update local roles
+			if len(fetched) > 0 {
+				args := &structs.ACLRoleUpsertRequest{
+					Roles: fetched,
+				}
+				_, _, err := s.raftApply(structs.ACLRoleUpsertRequestType, args)
+				if err != nil {
+					s.logger.Printf("[ERR] nomad: failed to update roles: %v", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLRoles is used to perform a two-way diff between the local
+// roles and the remote roles to determine which roles need to
+// be deleted or updated.
+func diffACLRoles(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLRoleListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote roles
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local roles
+	iter, err := state.ACLRoles(nil)
+	if err != nil {
+		panic("failed to iterate local roles")
+	}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		role := raw.(*structs.ACLRole)
+		local[role.Name] = role.Hash
+	}
+
+	// Iterate over the remote roles
+	for _, rp := range remoteList {
+		remote[rp.Name] = struct{}{}
+
+		// Check if the role is missing locally
+		if localHash, ok := local[rp.Name]; !ok {
+			update = append(update, rp.Name)
+
+			// Check if role is newer remotely and there is a hash mis-match.
+		} else if rp.ModifyIndex > minIndex && !bytes.Equal(localHash, rp.Hash) {
+			update = append(update, rp.Name)
+		}
+	}
+
+	// Check if role should be deleted
+	for lp := range local {
+		if _, ok := remote[lp]; !ok {
+			delete = append(delete, lp)
+		}
+	}
+	return
+}
+
+// replicateACLAuthMethods is used to replicate ACL auth methods from
+// the authoritative region to this region.
+func (s *Server) replicateACLAuthMethods(stopCh chan struct{}) {
+	req := structs.ACLAuthMethodListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Printf("[DEBUG] nomad: starting ACL auth method replication from authoritative region %q", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of auth methods
+			var resp structs.ACLAuthMethodListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"ACL.ListAuthMethods", &req, &resp)
+			if err != nil {
+				s.logger.Printf("[ERR] nomad: failed to fetch auth methods from authoritative region: %v", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLAuthMethods(s.State(), req.MinQueryIndex, resp.AuthMethods)
+
+			// Delete auth methods that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLAuthMethodDeleteRequest{
+					Names: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLAuthMethodDeleteRequestType, args)
+				if err != nil {
+					s.logger.Printf("[ERR] nomad: failed to delete auth methods: %v", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated auth methods
+			var fetched []*structs.ACLAuthMethod
+			if len(update) > 0 {
+				req := structs.ACLAuthMethodSetRequest{
+					Names: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLAuthMethodSetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"ACL.GetAuthMethods", &req, &reply); err != nil {
+					s.logger.Printf("[ERR] nomad: failed to fetch auth methods from authoritative region: %v", err)
+					goto ERR_WAIT
+				}
+				for _, method := range reply.AuthMethods {
+					fetched = append(fetched, method)
+				}
+			}
+
+			// Update local auth methods
+			if len(fetched) > 0 {
+				args := &structs.ACLAuthMethodUpsertRequest{
+					AuthMethods: fetched,
+				}
+				_, _, err := s.raftApply(structs.ACLAuthMethodUpsertRequestType, args)
+				if err != nil {
+					s.logger.Printf("[ERR] nomad: failed to update auth methods: %v", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLAuthMethods is used to perform a two-way diff between the local
+// auth methods and the remote auth methods to determine which auth methods need to
+// be deleted or updated.
+func diffACLAuthMethods(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLAuthMethodListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote auth methods
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local auth methods
+	iter, err := state.ACLAuthMethods(nil)
+	if err != nil {
+		panic("failed to iterate local auth methods")
+	}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		method := raw.(*structs.ACLAuthMethod)
+		local[method.Name] = method.Hash
+	}
+
+	// Iterate over the remote auth methods
+	for _, rp := range remoteList {
+		remote[rp.Name] = struct{}{}
+
+		// Check if the auth method is missing locally
+		if localHash, ok := local[rp.Name]; !ok {
+			update = append(update, rp.Name)
+
+			// Check if auth method is newer remotely and there is a hash mis-match.
+		} else if rp.ModifyIndex > minIndex && !bytes.Equal(localHash, rp.Hash) {
+			update = append(update, rp.Name)
+		}
+	}
+
+	// Check if auth method should be deleted
+	for lp := range local {
+		if _, ok := remote[lp]; !ok {
+			delete = append(delete, lp)
+		}
+	}
+	return
+}
+
+// replicateACLBindingRules is used to replicate ACL binding rules from
+// the authoritative region to this region.
+func (s *Server) replicateACLBindingRules(stopCh chan struct{}) {
+	req := structs.ACLBindingRuleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Printf("[DEBUG] nomad: starting ACL binding rule replication from authoritative region %q", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of binding rules
+			var resp structs.ACLBindingRuleListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"ACL.ListBindingRules", &req, &resp)
+			if err != nil {
+				s.logger.Printf("[ERR] nomad: failed to fetch binding rules from authoritative region: %v", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLBindingRules(s.State(), req.MinQueryIndex, resp.BindingRules)
+
+			// Delete binding rules that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLBindingRuleDeleteRequest{
+					IDs: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLBindingRuleDeleteRequestType, args)
+				if err != nil {
+					s.logger.Printf("[ERR] nomad: failed to delete binding rules: %v", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated binding rules
+			var fetched []*structs.ACLBindingRule
+			if len(update) > 0 {
+				req := structs.ACLBindingRuleSetRequest{
+					IDs: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLBindingRuleSetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"ACL.GetBindingRules", &req, &reply); err != nil {
+					s.logger.Printf("[ERR] nomad: failed to fetch binding rules from authoritative region: %v", err)
+					goto ERR_WAIT
+				}
+				for _, rule := range reply.BindingRules {
+					fetched = append(fetched, rule)
+				}
+			}
+
+			// Update local binding rules
+			if len(fetched) > 0 {
+				args := &structs.ACLBindingRuleUpsertRequest{
+					BindingRules: fetched,
+				}
+				_, _, err := s.raftApply(structs.ACLBindingRuleUpsertRequestType, args)
+				if err != nil {
+					s.logger.Printf("[ERR] nomad: failed to update binding rules: %v", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLBindingRules is used to perform a two-way diff between the local
+// binding rules and the remote binding rules to determine which binding rules need to
+// be deleted or updated.
+func diffACLBindingRules(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLBindingRuleListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote binding rules
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local binding rules
+	iter, err := state.ACLBindingRules(nil)
+	if err != nil {
+		panic("failed to iterate local binding rules")
+	}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		rule := raw.(*structs.ACLBindingRule)
+		local[rule.ID] = rule.Hash
+	}
+
+	// Iterate over the remote binding rules
+	for _, rp := range remoteList {
+		remote[rp.ID] = struct{}{}
+
+		// Check if the binding rule is missing locally
+		if localHash, ok := local[rp.ID]; !ok {
+			update = append(update, rp.ID)
+
+			// Check if binding rule is newer remotely and there is a hash mis-match.
+		} else if rp.ModifyIndex > minIndex && !bytes.Equal(localHash, rp.Hash) {
+			update = append(update, rp.ID)
+		}
+	}
+
+	// Check if binding rule should be deleted
+	for lp := range local {
+		if _, ok := remote[lp]; !ok {
+			delete = append(delete, lp)
+		}
+	}
+	return
+}
+
 // replicateACLTokens is used to replicate global ACL tokens from
 // the authoritative region to this region.
 func (s *Server) replicateACLTokens(stopCh chan struct{}) {
@@ -1207,6 +1812,94 @@ func diffACLTokens(state *state.StateStore, minIndex uint64, remoteList []*struc
 	return
 }
 
+// initializeKeyring creates the active root key if the keyring is empty
+func (s *Server) initializeKeyring() error {
+	meta, err := s.State().GetActiveRootKeyMeta(nil)
+	if err != nil {
+		return fmt.Errorf("failed to lookup active root key: %v", err)
+	}
+	if meta != nil {
+		return nil
+	}
+
+	if !ServersMeetMinimumVersion(s.Members(), minVariablesVersion) {
+		s.logger.Printf("[WARN] nomad: can't initialize keyring until all servers are >= %s", minVariablesVersion.String())
+		return nil
+	}
+
+	key, err := generateRootKey()
+	if err != nil {
+		return fmt.Errorf("failed to generate root key: %v", err)
+	}
+	if err := s.addRootKey(key); err != nil {
+		return fmt.Errorf("failed to initialize keyring: %v", err)
+	}
+	s.logger.Printf("[INFO] nomad: initialized keyring with root key %s", key.Meta.KeyID)
+	return nil
+}
+
+// addRootKey persists the root key to the local keystore and then applies its
+// metadata through raft. The key is written to the keystore first so that the
+// other servers can always fetch the key material of the keys in the state
+// store from the leader.
+func (s *Server) addRootKey(key *structs.RootKey) error {
+	if err := s.encrypter.AddKey(key); err != nil {
+		return err
+	}
+
+	req := structs.KeyringUpsertRootKeyMetaRequest{
+		RootKeyMeta: key.Meta,
+		WriteRequest: structs.WriteRequest{
+			Region: s.config.Region,
+		},
+	}
+	_, _, err := s.raftApply(structs.RootKeyMetaUpsertRequestType, req)
+	return err
+}
+
+// rotateKeyring periodically replaces the active root key once it is older
+// than the rotation threshold. Older keys are kept in the keyring so that the
+// data and identities they protect can still be decrypted and verified. It
+// also initializes the keyring if that was deferred because of older servers.
+func (s *Server) rotateKeyring(stopCh chan struct{}) {
+	ticker := time.NewTicker(keyringRotationCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			meta, err := s.State().GetActiveRootKeyMeta(nil)
+			if err != nil {
+				s.logger.Printf("[ERR] nomad: failed to lookup active root key: %v", err)
+				continue
+			}
+			if meta == nil {
+				if err := s.initializeKeyring(); err != nil {
+					s.logger.Printf("[ERR] nomad: %v", err)
+				}
+				continue
+			}
+
+			age := time.Since(time.Unix(0, meta.CreateTime))
+			if age < s.config.RootKeyRotationThreshold {
+				continue
+			}
+
+			newKey, err := generateRootKey()
+			if err != nil {
+				s.logger.Printf("[ERR] nomad: failed to generate root key: %v", err)
+				continue
+			}
+			if err := s.addRootKey(newKey); err != nil {
+				s.logger.Printf("[ERR] nomad: failed to rotate root key: %v", err)
+				continue
+			}
+			s.logger.Printf("[INFO] nomad: rotated root key %s to %s", meta.KeyID, newKey.Meta.KeyID)
+		}
+	}
+}
+
 // getOrCreateAutopilotConfig is used to get the autopilot config, initializing it if necessary
 func (s *Server) getOrCreateAutopilotConfig() *structs.AutopilotConfig {
 	state := s.fsm.State()
@@ -646,6 +646,50 @@ func TestLeader_ReapDuplicateEval(t *testing.T) {
 	})
 }
 
+func TestLeader_ReapCancelableEvaluations(t *testing.T) {
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Enqueue three evaluations for the same job. The second is coalesced
+	// into the third once the first is Acked.
+	state := s1.fsm.State()
+	eval := mock.Eval()
+	eval2 := mock.Eval()
+	eval2.JobID = eval.JobID
+	eval3 := mock.Eval()
+	eval3.JobID = eval.JobID
+	if err := state.UpsertEvals(1000, []*structs.Evaluation{eval, eval2, eval3}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	s1.evalBroker.Enqueue(eval)
+	s1.evalBroker.Enqueue(eval2)
+	eval3.ModifyIndex = 1001
+	s1.evalBroker.Enqueue(eval3)
+
+	out, token, err := s1.evalBroker.Dequeue([]string{eval.Type}, time.Second)
+	if err != nil || out == nil {
+		t.Fatalf("bad: %v %v", out, err)
+	}
+	if err := s1.evalBroker.Ack(out.ID, token); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Wait for the evaluation to marked as cancelled
+	testutil.WaitForResult(func() (bool, error) {
+		ws := memdb.NewWatchSet()
+		out, err := state.EvalByID(ws, eval2.ID)
+		if err != nil {
+			return false, err
+		}
+		return out != nil && out.Status == structs.EvalStatusCancelled, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
 func TestLeader_RestoreVaultAccessors(t *testing.T) {
 	s1 := TestServer(t, func(c *Config) {
 		c.NumSchedulers = 0
@@ -742,6 +786,183 @@ func TestLeader_DiffACLPolicies(t *testing.T) {
 	assert.Equal(t, []string{p3.Name, p4.Name}, update)
 }
 
+func TestLeader_ReplicateACLRoles(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.Region = "region1"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+	})
+	defer s1.Shutdown()
+	s2, _ := TestACLServer(t, func(c *Config) {
+		c.Region = "region2"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+		c.ReplicationBackoff = 20 * time.Millisecond
+		c.ReplicationToken = root.SecretID
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// Write a role to the authoritative region
+	r1 := mock.ACLRole()
+	if err := s1.State().UpsertACLRoles(100, []*structs.ACLRole{r1}); err != nil {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Wait for the role to replicate
+	testutil.WaitForResult(func() (bool, error) {
+		state := s2.State()
+		out, err := state.ACLRoleByName(nil, r1.Name)
+		return out != nil, err
+	}, func(err error) {
+		t.Fatalf("should replicate role")
+	})
+}
+
+func TestLeader_DiffACLRoles(t *testing.T) {
+	t.Parallel()
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	r3 := mock.ACLRole()
+	assert.Nil(t, state.UpsertACLRoles(100, []*structs.ACLRole{r1, r2, r3}))
+
+	// Simulate a remote list
+	r2Stub := r2.Stub()
+	r2Stub.ModifyIndex = 50 // Ignored, same index
+	r3Stub := r3.Stub()
+	r3Stub.ModifyIndex = 100 // Updated, higher index
+	r3Stub.Hash = []byte{0, 1, 2, 3}
+	r4 := mock.ACLRole()
+	remoteList := []*structs.ACLRoleListStub{
+		r2Stub,
+		r3Stub,
+		r4.Stub(),
+	}
+	delete, update := diffACLRoles(state, 50, remoteList)
+
+	// R1 does not exist on the remote side, should delete
+	assert.Equal(t, []string{r1.Name}, delete)
+
+	// R2 is un-modified - ignore. R3 modified, R4 new.
+	assert.Equal(t, []string{r3.Name, r4.Name}, update)
+}
+
+func TestLeader_ReplicateACLAuthMethodsAndBindingRules(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.Region = "region1"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+	})
+	defer s1.Shutdown()
+	s2, _ := TestACLServer(t, func(c *Config) {
+		c.Region = "region2"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+		c.ReplicationBackoff = 20 * time.Millisecond
+		c.ReplicationToken = root.SecretID
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// Write an auth method and binding rule to the authoritative region
+	m1 := mock.ACLAuthMethod()
+	if err := s1.State().UpsertACLAuthMethods(100, []*structs.ACLAuthMethod{m1}); err != nil {
+		t.Fatalf("bad: %v", err)
+	}
+	r1 := mock.ACLBindingRule()
+	r1.AuthMethod = m1.Name
+	if err := s1.State().UpsertACLBindingRules(101, []*structs.ACLBindingRule{r1}); err != nil {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Wait for the auth method and binding rule to replicate
+	testutil.WaitForResult(func() (bool, error) {
+		state := s2.State()
+		method, err := state.ACLAuthMethodByName(nil, m1.Name)
+		if err != nil {
+			return false, err
+		}
+		rule, err := state.ACLBindingRuleByID(nil, r1.ID)
+		return method != nil && rule != nil, err
+	}, func(err error) {
+		t.Fatalf("should replicate auth method and binding rule")
+	})
+}
+
+func TestLeader_DiffACLAuthMethods(t *testing.T) {
+	t.Parallel()
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	m1 := mock.ACLAuthMethod()
+	m2 := mock.ACLAuthMethod()
+	m3 := mock.ACLAuthMethod()
+	assert.Nil(t, state.UpsertACLAuthMethods(100, []*structs.ACLAuthMethod{m1, m2, m3}))
+
+	// Simulate a remote list
+	m2Stub := m2.Stub()
+	m2Stub.ModifyIndex = 50 // Ignored, same index
+	m3Stub := m3.Stub()
+	m3Stub.ModifyIndex = 100 // Updated, higher index
+	m3Stub.Hash = []byte{0, 1, 2, 3}
+	m4 := mock.ACLAuthMethod()
+	remoteList := []*structs.ACLAuthMethodListStub{
+		m2Stub,
+		m3Stub,
+		m4.Stub(),
+	}
+	delete, update := diffACLAuthMethods(state, 50, remoteList)
+
+	// M1 does not exist on the remote side, should delete
+	assert.Equal(t, []string{m1.Name}, delete)
+
+	// M2 is un-modified - ignore. M3 modified, M4 new.
+	assert.Equal(t, []string{m3.Name, m4.Name}, update)
+}
+
+func TestLeader_DiffACLBindingRules(t *testing.T) {
+	t.Parallel()
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	r1 := mock.ACLBindingRule()
+	r2 := mock.ACLBindingRule()
+	r3 := mock.ACLBindingRule()
+	assert.Nil(t, state.UpsertACLBindingRules(100, []*structs.ACLBindingRule{r1, r2, r3}))
+
+	// Simulate a remote list
+	r2Stub := r2.Stub()
+	r2Stub.ModifyIndex = 50 // Ignored, same index
+	r3Stub := r3.Stub()
+	r3Stub.ModifyIndex = 100 // Updated, higher index
+	r3Stub.Hash = []byte{0, 1, 2, 3}
+	r4 := mock.ACLBindingRule()
+	remoteList := []*structs.ACLBindingRuleListStub{
+		r2Stub,
+		r3Stub,
+		r4.Stub(),
+	}
+	delete, update := diffACLBindingRules(state, 50, remoteList)
+
+	// R1 does not exist on the remote side, should delete
+	assert.Equal(t, []string{r1.ID}, delete)
+
+	// R2 is un-modified - ignore. R3 modified, R4 new.
+	assert.Equal(t, []string{r3.ID, r4.ID}, update)
+}
+
 func TestLeader_ReplicateACLTokens(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, func(c *Config) {
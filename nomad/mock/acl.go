@@ -47,6 +47,12 @@ func NodePolicy(policy string) string {
 	return fmt.Sprintf("node {\n\tpolicy = %q\n}\n", policy)
 }
 
+// NodeClassPolicy is a helper for generating the hcl for a node policy that
+// only grants the given policy to nodes of a single class.
+func NodeClassPolicy(class, policy string) string {
+	return fmt.Sprintf("node {\n\tclass %q {\n\t\tpolicy = %q\n\t}\n}\n", class, policy)
+}
+
 // QuotaPolicy is a helper for generating the hcl for a given quota policy.
 func QuotaPolicy(policy string) string {
 	return fmt.Sprintf("quota {\n\tpolicy = %q\n}\n", policy)
@@ -570,6 +570,76 @@ func ACLPolicy() *structs.ACLPolicy {
 	return ap
 }
 
+func ACLRole() *structs.ACLRole {
+	role := &structs.ACLRole{
+		Name:        fmt.Sprintf("role-%s", uuid.Generate()),
+		Description: "Super cool role!",
+		Policies:    []string{"foo", "bar"},
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	role.SetHash()
+	return role
+}
+
+func DrainPlan() *structs.DrainPlan {
+	return &structs.DrainPlan{
+		ID: uuid.Generate(),
+		Filter: &structs.DrainPlanFilter{
+			Datacenter: "dc1",
+		},
+		DrainSpec: structs.DrainSpec{
+			Deadline: time.Hour,
+		},
+		MaxParallel: 1,
+		Nodes: []*structs.DrainPlanNode{
+			{
+				NodeID: uuid.Generate(),
+				Name:   "foobar",
+				Status: structs.DrainPlanNodeStatusPending,
+			},
+		},
+		Status:            structs.DrainPlanStatusRunning,
+		StatusDescription: structs.DrainPlanStatusDescriptionRunning,
+	}
+}
+
+func ACLAuthMethod() *structs.ACLAuthMethod {
+	method := &structs.ACLAuthMethod{
+		Name:          fmt.Sprintf("auth-method-%s", uuid.Generate()[:8]),
+		Type:          structs.ACLAuthMethodTypeOIDC,
+		TokenLocality: structs.ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   time.Hour,
+		Config: &structs.ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "http://example.com",
+			OIDCClientID:        "mock",
+			OIDCClientSecret:    "very secret secret",
+			AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+			ClaimMappings:       map[string]string{"sub": "user"},
+			ListClaimMappings:   map[string]string{"groups": "groups"},
+		},
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	method.SetHash()
+	return method
+}
+
+func ACLBindingRule() *structs.ACLBindingRule {
+	rule := &structs.ACLBindingRule{
+		ID:          uuid.Generate(),
+		Description: "Super cool binding rule!",
+		AuthMethod:  "auth-method",
+		Selector:    `"engineering" in list.groups`,
+		BindType:    structs.ACLBindingRuleBindTypePolicy,
+		BindName:    "engineering",
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	rule.SetHash()
+	return rule
+}
+
 func ACLToken() *structs.ACLToken {
 	tk := &structs.ACLToken{
 		AccessorID:  uuid.Generate(),
@@ -598,3 +668,42 @@ func ACLManagementToken() *structs.ACLToken {
 		ModifyIndex: 20,
 	}
 }
+
+func CSIVolume() *structs.CSIVolume {
+	return &structs.CSIVolume{
+		ID:             uuid.Generate(),
+		Name:           "test-vol",
+		ExternalID:     "vol-01",
+		Namespace:      structs.DefaultNamespace,
+		AccessMode:     structs.CSIVolumeAccessModeSingleNodeWriter,
+		AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+		PluginID:       "minnie",
+	}
+}
+
+// CSINode returns a node running the node and controller services of the
+// "minnie" CSI plugin
+func CSINode() *structs.Node {
+	node := Node()
+	node.CSIControllerPlugins = map[string]*structs.CSIInfo{
+		"minnie": {
+			PluginID:                 "minnie",
+			Healthy:                  true,
+			Provider:                 "com.hashicorp:minnie",
+			ProviderVersion:          "1.0.0",
+			RequiresControllerPlugin: true,
+			ControllerInfo:           &structs.CSIControllerInfo{SupportsAttachDetach: true},
+		},
+	}
+	node.CSINodePlugins = map[string]*structs.CSIInfo{
+		"minnie": {
+			PluginID:                 "minnie",
+			Healthy:                  true,
+			Provider:                 "com.hashicorp:minnie",
+			ProviderVersion:          "1.0.0",
+			RequiresControllerPlugin: true,
+			NodeInfo:                 &structs.CSINodeInfo{ID: node.ID},
+		},
+	}
+	return node
+}
@@ -169,7 +169,7 @@ func (n *Node) Register(args *structs.NodeRegisterRequest, reply *structs.NodeUp
 		originalStatus = originalNode.Status
 	}
 	transitionToReady := transitionedToReady(args.Node.Status, originalStatus)
-	if structs.ShouldDrainNode(args.Node.Status) || transitionToReady {
+	if structs.ShouldDrainNode(args.Node.Status) || transitionToReady || nodeMetaChanged(originalNode, args.Node) {
 		evalIDs, evalIndex, err := n.createNodeEvals(args.Node.ID, index)
 		if err != nil {
 			n.srv.logger.Printf("[ERR] nomad.client: eval creation failed: %v", err)
@@ -251,9 +251,10 @@ func (n *Node) Deregister(args *structs.NodeDeregisterRequest, reply *structs.No
 	defer metrics.MeasureSince([]string{"nomad", "client", "deregister"}, time.Now())
 
 	// Check node permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeWrite() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -276,6 +277,11 @@ func (n *Node) Deregister(args *structs.NodeDeregisterRequest, reply *structs.No
 		return fmt.Errorf("node not found")
 	}
 
+	// Check node class write permissions
+	if aclObj != nil && !aclObj.AllowNodeClassWrite(node.NodeClass) {
+		return structs.ErrPermissionDenied
+	}
+
 	// Commit this update via Raft
 	_, index, err := n.srv.raftApply(structs.NodeDeregisterRequestType, args)
 	if err != nil {
@@ -445,6 +451,23 @@ func transitionedToReady(newStatus, oldStatus string) bool {
 	return initToReady || terminalToReady
 }
 
+// nodeMetaChanged returns whether a ready node re-registered with different
+// meta, in which case constraints on the meta must be re-evaluated.
+func nodeMetaChanged(original, updated *structs.Node) bool {
+	if original == nil || original.Status != structs.NodeStatusReady || updated.Status != structs.NodeStatusReady {
+		return false
+	}
+	if len(original.Meta) != len(updated.Meta) {
+		return true
+	}
+	for k, v := range original.Meta {
+		if uv, ok := updated.Meta[k]; !ok || uv != v {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateDrain is used to update the drain mode of a client node
 func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 	reply *structs.NodeDrainUpdateResponse) error {
@@ -454,9 +477,10 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 	defer metrics.MeasureSince([]string{"nomad", "client", "update_drain"}, time.Now())
 
 	// Check node write permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeWrite() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -481,6 +505,11 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 		return fmt.Errorf("node not found")
 	}
 
+	// Check node class write permissions
+	if aclObj != nil && !aclObj.AllowNodeClassWrite(node.NodeClass) {
+		return structs.ErrPermissionDenied
+	}
+
 	// COMPAT: Remove in 0.9. Attempt to upgrade the request if it is of the old
 	// format.
 	if args.Drain && args.DrainStrategy == nil {
@@ -533,6 +562,67 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 	return nil
 }
 
+// UpdateMeta is used by a client to update the meta of its node. Only the
+// meta is written, so other changes to the node are never overwritten.
+func (n *Node) UpdateMeta(args *structs.NodeUpdateMetaRequest, reply *structs.NodeUpdateResponse) error {
+	if done, err := n.srv.forward("Node.UpdateMeta", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "update_meta"}, time.Now())
+
+	// Verify the arguments
+	if args.NodeID == "" {
+		return fmt.Errorf("missing node ID for client meta update")
+	}
+	if args.SecretID == "" {
+		return fmt.Errorf("missing node secret ID for client meta update")
+	}
+
+	// Look for the node
+	snap, err := n.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	node, err := snap.NodeByID(nil, args.NodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("node not found")
+	}
+	if args.SecretID != node.SecretID {
+		return fmt.Errorf("node secret ID does not match")
+	}
+
+	// Commit this update via Raft
+	resp, index, err := n.srv.raftApply(structs.NodeUpdateMetaRequestType, args)
+	if err != nil {
+		n.srv.logger.Printf("[ERR] nomad.client: meta update failed: %v", err)
+		return err
+	}
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+	reply.NodeModifyIndex = index
+
+	// Re-evaluate the node so constraints on its meta are checked again
+	updated := node.Copy()
+	updated.Meta = args.Meta
+	if nodeMetaChanged(node, updated) {
+		evalIDs, evalIndex, err := n.createNodeEvals(args.NodeID, index)
+		if err != nil {
+			n.srv.logger.Printf("[ERR] nomad.client: eval creation failed: %v", err)
+			return err
+		}
+		reply.EvalIDs = evalIDs
+		reply.EvalCreateIndex = evalIndex
+	}
+
+	// Set the reply index
+	reply.Index = index
+	return nil
+}
+
 // UpdateEligibility is used to update the scheduling eligibility of a node
 func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 	reply *structs.NodeEligibilityUpdateResponse) error {
@@ -542,9 +632,10 @@ func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 	defer metrics.MeasureSince([]string{"nomad", "client", "update_eligibility"}, time.Now())
 
 	// Check node write permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeWrite() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -576,6 +667,11 @@ func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 		return fmt.Errorf("node not found")
 	}
 
+	// Check node class write permissions
+	if aclObj != nil && !aclObj.AllowNodeClassWrite(node.NodeClass) {
+		return structs.ErrPermissionDenied
+	}
+
 	if node.DrainStrategy != nil && args.Eligibility == structs.NodeSchedulingEligible {
 		return fmt.Errorf("can not set node's scheduling eligibility to eligible while it is draining")
 	}
@@ -634,9 +730,10 @@ func (n *Node) Evaluate(args *structs.NodeEvaluateRequest, reply *structs.NodeUp
 	defer metrics.MeasureSince([]string{"nomad", "client", "evaluate"}, time.Now())
 
 	// Check node write permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeWrite() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -659,6 +756,11 @@ func (n *Node) Evaluate(args *structs.NodeEvaluateRequest, reply *structs.NodeUp
 		return fmt.Errorf("node not found")
 	}
 
+	// Check node class write permissions
+	if aclObj != nil && !aclObj.AllowNodeClassWrite(node.NodeClass) {
+		return structs.ErrPermissionDenied
+	}
+
 	// Create the evaluation
 	evalIDs, evalIndex, err := n.createNodeEvals(args.NodeID, node.ModifyIndex)
 	if err != nil {
@@ -689,7 +791,8 @@ func (n *Node) GetNode(args *structs.NodeSpecificRequest,
 	defer metrics.MeasureSince([]string{"nomad", "client", "get_node"}, time.Now())
 
 	// Check node read permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		// If ResolveToken had an unexpected error return that
 		if err != structs.ErrTokenNotFound {
 			return err
@@ -709,7 +812,7 @@ func (n *Node) GetNode(args *structs.NodeSpecificRequest,
 		if node == nil {
 			return structs.ErrTokenNotFound
 		}
-	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeRead() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -729,6 +832,11 @@ func (n *Node) GetNode(args *structs.NodeSpecificRequest,
 				return err
 			}
 
+			// Check node class read permissions
+			if out != nil && aclObj != nil && !aclObj.AllowNodeClassRead(out.NodeClass) {
+				return structs.ErrPermissionDenied
+			}
+
 			// Setup the output
 			if out != nil {
 				// Clear the secret ID
@@ -765,7 +873,7 @@ func (n *Node) GetAllocs(args *structs.NodeSpecificRequest,
 	if err != nil {
 		return err
 	}
-	if aclObj != nil && !aclObj.AllowNodeRead() {
+	if aclObj != nil && !aclObj.AllowAnyNodeRead() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -800,6 +908,17 @@ func (n *Node) GetAllocs(args *structs.NodeSpecificRequest,
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Check node class read permissions
+			if aclObj != nil {
+				node, err := state.NodeByID(ws, args.NodeID)
+				if err != nil {
+					return err
+				}
+				if node != nil && !aclObj.AllowNodeClassRead(node.NodeClass) {
+					return structs.ErrPermissionDenied
+				}
+			}
+
 			// Look for the node
 			allocs, err := state.AllocsByNode(ws, args.NodeID)
 			if err != nil {
@@ -1150,9 +1269,10 @@ func (n *Node) List(args *structs.NodeListRequest,
 	defer metrics.MeasureSince([]string{"nomad", "client", "list"}, time.Now())
 
 	// Check node read permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeRead() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -1180,6 +1300,11 @@ func (n *Node) List(args *structs.NodeListRequest,
 					break
 				}
 				node := raw.(*structs.Node)
+
+				// Only include nodes whose class is readable
+				if aclObj != nil && !aclObj.AllowNodeClassRead(node.NodeClass) {
+					continue
+				}
 				nodes = append(nodes, node.Stub())
 			}
 			reply.Nodes = nodes
@@ -80,6 +80,100 @@ func TestClientEndpoint_Register(t *testing.T) {
 	})
 }
 
+func TestClientEndpoint_Register_MetaChange(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a system job so node evaluations are created
+	job := mock.SystemJob()
+	require.Nil(s1.fsm.State().UpsertJob(1000, job))
+
+	// Register a ready node
+	node := mock.Node()
+	req := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp))
+
+	// Re-registering the unchanged node doesn't create evaluations
+	var resp2 structs.NodeUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp2))
+	require.Empty(resp2.EvalIDs)
+
+	// Re-registering with changed meta creates evaluations
+	node = node.Copy()
+	node.Meta["rack"] = "r2"
+	req.Node = node
+	var resp3 structs.NodeUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp3))
+	require.Len(resp3.EvalIDs, 1)
+
+	eval, err := s1.fsm.State().EvalByID(nil, resp3.EvalIDs[0])
+	require.Nil(err)
+	require.Equal(structs.EvalTriggerNodeUpdate, eval.TriggeredBy)
+	require.Equal(job.ID, eval.JobID)
+}
+
+func TestClientEndpoint_UpdateMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a system job so node evaluations are created
+	job := mock.SystemJob()
+	require.Nil(s1.fsm.State().UpsertJob(1000, job))
+
+	// Register a ready node
+	node := mock.Node()
+	reg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+
+	// Updating the meta with the wrong secret ID fails
+	req := &structs.NodeUpdateMetaRequest{
+		NodeID:       node.ID,
+		SecretID:     uuid.Generate(),
+		Meta:         map[string]string{"rack": "r2"},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	require.Error(msgpackrpc.CallWithCodec(codec, "Node.UpdateMeta", req, &resp))
+
+	// Updating the meta writes it and creates evaluations
+	req.SecretID = node.SecretID
+	var resp2 structs.NodeUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateMeta", req, &resp2))
+	require.NotZero(resp2.Index)
+	require.Len(resp2.EvalIDs, 1)
+
+	out, err := s1.fsm.State().NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Equal(req.Meta, out.Meta)
+	require.Equal(resp2.Index, out.ModifyIndex)
+	require.NotEqual(node.ComputedClass, out.ComputedClass)
+	require.Equal(node.Attributes, out.Attributes)
+
+	eval, err := s1.fsm.State().EvalByID(nil, resp2.EvalIDs[0])
+	require.Nil(err)
+	require.Equal(structs.EvalTriggerNodeUpdate, eval.TriggeredBy)
+
+	// Sending the same meta doesn't create evaluations
+	var resp3 structs.NodeUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateMeta", req, &resp3))
+	require.Empty(resp3.EvalIDs)
+}
+
 // This test asserts that we only track node connections if they are not from
 // forwarded RPCs. This is essential otherwise we will think a Yamux session to
 // a Nomad server is actually the session to the node.
@@ -993,6 +1087,55 @@ func TestClientEndpoint_UpdateDrain_ACL(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_UpdateDrain_ACL_NodeClass(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	// Create two nodes in different classes
+	state := s1.fsm.State()
+	gpuNode := mock.Node()
+	gpuNode.NodeClass = "gpu"
+	require.Nil(state.UpsertNode(1, gpuNode), "UpsertNode")
+	webNode := mock.Node()
+	webNode.NodeClass = "web"
+	require.Nil(state.UpsertNode(2, webNode), "UpsertNode")
+
+	// Create a token that may only write to the gpu class
+	token := mock.CreatePolicyAndToken(t, state, 1001, "test-gpu", mock.NodeClassPolicy("gpu", acl.PolicyWrite))
+
+	drain := &structs.NodeUpdateDrainRequest{
+		NodeID: gpuNode.ID,
+		DrainStrategy: &structs.DrainStrategy{
+			DrainSpec: structs.DrainSpec{
+				Deadline: 10 * time.Second,
+			},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+
+	// Draining a node in the gpu class is allowed
+	{
+		var resp structs.NodeDrainUpdateResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateDrain", drain, &resp), "RPC")
+	}
+
+	// Draining a node in another class is denied
+	drain.NodeID = webNode.ID
+	{
+		var resp structs.NodeDrainUpdateResponse
+		err := msgpackrpc.CallWithCodec(codec, "Node.UpdateDrain", drain, &resp)
+		require.NotNil(err, "RPC")
+		require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+}
+
 // This test ensures that Nomad marks client state of allocations which are in
 // pending/running state to lost when a node is marked as down.
 func TestClientEndpoint_Drain_Down(t *testing.T) {
@@ -2608,6 +2751,53 @@ func TestClientEndpoint_ListNodes_ACL(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_ListNodes_ACL_NodeClass(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+
+	// Create two nodes in different classes
+	state := s1.fsm.State()
+	gpuNode := mock.Node()
+	gpuNode.NodeClass = "gpu"
+	assert.Nil(state.UpsertNode(1, gpuNode), "UpsertNode")
+	webNode := mock.Node()
+	webNode.NodeClass = "web"
+	assert.Nil(state.UpsertNode(2, webNode), "UpsertNode")
+
+	// Create a token that may only read the gpu class
+	token := mock.CreatePolicyAndToken(t, state, 1001, "test-gpu", mock.NodeClassPolicy("gpu", acl.PolicyRead))
+
+	// Only the gpu node should be listed
+	req := &structs.NodeListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.NodeListResponse
+	assert.Nil(msgpackrpc.CallWithCodec(codec, "Node.List", req, &resp), "RPC")
+	if assert.Len(resp.Nodes, 1) {
+		assert.Equal(gpuNode.ID, resp.Nodes[0].ID)
+	}
+
+	// Reading the web node directly is denied
+	get := &structs.NodeSpecificRequest{
+		NodeID: webNode.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var getResp structs.SingleNodeResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.GetNode", get, &getResp)
+	assert.NotNil(err, "RPC")
+	assert.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+}
+
 func TestClientEndpoint_ListNodes_Blocking(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
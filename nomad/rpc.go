@@ -123,7 +123,7 @@ func (s *Server) handleConn(ctx context.Context, conn net.Conn, rpcCtx *RPCConte
 		// Create an RPC Server and handle the request
 		server := rpc.NewServer()
 		s.setupRpcServer(server, rpcCtx)
-		s.handleNomadConn(ctx, conn, server)
+		s.handleNomadConn(ctx, conn, server, rpcCtx)
 
 		// Remove any potential mapping between a NodeID to this connection and
 		// close the underlying connection.
@@ -170,7 +170,7 @@ func (s *Server) handleConn(ctx context.Context, conn net.Conn, rpcCtx *RPCConte
 		s.handleConn(ctx, conn, rpcCtx)
 
 	case pool.RpcStreaming:
-		s.handleStreamingConn(conn)
+		s.handleStreamingConn(conn, rpcCtx)
 
 	case pool.RpcMultiplexV2:
 		s.handleMultiplexV2(ctx, conn, rpcCtx)
@@ -220,14 +220,18 @@ func (s *Server) handleMultiplex(ctx context.Context, conn net.Conn, rpcCtx *RPC
 			}
 			return
 		}
-		go s.handleNomadConn(ctx, sub, rpcServer)
+		go s.handleNomadConn(ctx, sub, rpcServer, rpcCtx)
 	}
 }
 
 // handleNomadConn is used to service a single Nomad RPC connection
-func (s *Server) handleNomadConn(ctx context.Context, conn net.Conn, server *rpc.Server) {
+func (s *Server) handleNomadConn(ctx context.Context, conn net.Conn, server *rpc.Server, rpcCtx *RPCContext) {
 	defer conn.Close()
-	rpcCodec := pool.NewServerCodec(conn)
+	rpcCodec := &rpcConnCodec{
+		ServerCodec: pool.NewServerCodec(conn),
+		srv:         s,
+		rpcCtx:      rpcCtx,
+	}
 	for {
 		select {
 		case <-ctx.Done():
@@ -250,7 +254,7 @@ func (s *Server) handleNomadConn(ctx context.Context, conn net.Conn, server *rpc
 }
 
 // handleStreamingConn is used to handle a single Streaming Nomad RPC connection.
-func (s *Server) handleStreamingConn(conn net.Conn) {
+func (s *Server) handleStreamingConn(conn net.Conn, rpcCtx *RPCContext) {
 	defer conn.Close()
 
 	// Decode the header
@@ -286,7 +290,47 @@ func (s *Server) handleStreamingConn(conn net.Conn) {
 
 	// Invoke the handler
 	metrics.IncrCounter([]string{"nomad", "streaming_rpc", "request"}, 1)
-	handler(conn)
+	handler(&streamingRpcConn{Conn: conn, rpcCtx: rpcCtx})
+}
+
+// rpcConnCodec wraps the codec of an RPC connection to record where each
+// request was received from.
+type rpcConnCodec struct {
+	rpc.ServerCodec
+	srv    *Server
+	rpcCtx *RPCContext
+}
+
+// ReadRequestBody decodes the request and records its remote address.
+func (c *rpcConnCodec) ReadRequestBody(body interface{}) error {
+	if err := c.ServerCodec.ReadRequestBody(body); err != nil {
+		return err
+	}
+	if info, ok := body.(structs.RPCInfo); ok {
+		c.srv.setRPCRemote(c.rpcCtx, info)
+	}
+	return nil
+}
+
+// streamingRpcConn is the connection passed to streaming RPC handlers. It
+// carries the context of the connection the stream was opened on, as the
+// handlers decode their own arguments.
+type streamingRpcConn struct {
+	net.Conn
+	rpcCtx *RPCContext
+}
+
+// setRPCRemote records the address of the connection an RPC was received on
+// and whether it is from another server.
+func (s *Server) setRPCRemote(rpcCtx *RPCContext, info structs.RPCInfo) {
+	if rpcCtx == nil || rpcCtx.Conn == nil {
+		return
+	}
+	host, _, err := net.SplitHostPort(rpcCtx.Conn.RemoteAddr().String())
+	if err != nil {
+		return
+	}
+	info.SetRemote(host, s.isPeerServerConn(rpcCtx))
 }
 
 // handleMultiplexV2 is used to multiplex a single incoming connection
@@ -342,9 +386,9 @@ func (s *Server) handleMultiplexV2(ctx context.Context, conn net.Conn, rpcCtx *R
 		// Determine which handler to use
 		switch pool.RPCType(buf[0]) {
 		case pool.RpcNomad:
-			go s.handleNomadConn(ctx, sub, rpcServer)
+			go s.handleNomadConn(ctx, sub, rpcServer, rpcCtx)
 		case pool.RpcStreaming:
-			go s.handleStreamingConn(sub)
+			go s.handleStreamingConn(sub, rpcCtx)
 
 		default:
 			s.logger.Printf("[ERR] nomad.rpc: multiplex_v2 unrecognized RPC byte: %v", buf[0])
@@ -364,6 +408,11 @@ func (s *Server) forward(method string, info structs.RPCInfo, args interface{},
 		return true, fmt.Errorf("missing target RPC")
 	}
 
+	// Enforce the rate limits before doing any work for the request
+	if err := s.checkRateLimit(method, info); err != nil {
+		return true, err
+	}
+
 	// Handle region forwarding
 	if region != s.config.Region {
 		// Mark that we are forwarding the RPC
@@ -675,3 +724,107 @@ RUN_QUERY:
 	}
 	return err
 }
+
+// isServerConn returns whether the RPC was made over a connection from
+// another server of the region. When the peer presented a verified
+// certificate it must be a server certificate for the region. Otherwise the
+// remote address must belong to a known server.
+func (s *Server) isServerConn(ctx *RPCContext) bool {
+	if ctx == nil {
+		return false
+	}
+
+	if ctx.TLS && len(ctx.VerifiedChains) != 0 {
+		return s.isVerifiedServerConn(ctx)
+	}
+
+	if ctx.Conn == nil {
+		return false
+	}
+	host, _, err := net.SplitHostPort(ctx.Conn.RemoteAddr().String())
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	s.peerLock.RLock()
+	defer s.peerLock.RUnlock()
+	for _, peer := range s.localPeers {
+		if addr, ok := peer.Addr.(*net.TCPAddr); ok && addr.IP.Equal(ip) {
+			return true
+		}
+		if addr, ok := peer.RPCAddr.(*net.TCPAddr); ok && addr.IP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isVerifiedServerConn returns whether the peer of the RPC connection
+// presented a verified server certificate for the region.
+func (s *Server) isVerifiedServerConn(ctx *RPCContext) bool {
+	if ctx == nil || !ctx.TLS {
+		return false
+	}
+
+	name := "server." + s.config.Region + ".nomad"
+	for _, chain := range ctx.VerifiedChains {
+		if len(chain) != 0 && chain[0].VerifyHostname(name) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isPeerServerConn returns whether the RPC connection is from a server in any
+// region.
+func (s *Server) isPeerServerConn(ctx *RPCContext) bool {
+	if ctx == nil {
+		return false
+	}
+	if s.isServerConn(ctx) {
+		return true
+	}
+
+	s.peerLock.RLock()
+	defer s.peerLock.RUnlock()
+
+	if ctx.TLS && len(ctx.VerifiedChains) != 0 {
+		for region := range s.peers {
+			name := "server." + region + ".nomad"
+			for _, chain := range ctx.VerifiedChains {
+				if len(chain) != 0 && chain[0].VerifyHostname(name) == nil {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	if ctx.Conn == nil {
+		return false
+	}
+	host, _, err := net.SplitHostPort(ctx.Conn.RemoteAddr().String())
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, peers := range s.peers {
+		for _, peer := range peers {
+			if addr, ok := peer.Addr.(*net.TCPAddr); ok && addr.IP.Equal(ip) {
+				return true
+			}
+			if addr, ok := peer.RPCAddr.(*net.TCPAddr); ok && addr.IP.Equal(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package nomad
+
+import (
+	"io"
+	"sync"
+
+	metrics "github.com/armon/go-metrics"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimiterCacheSize is the number of token and endpoint class limiters
+	// to track. The limiters of the least recently seen tokens are dropped
+	// first, which resets their limits.
+	rateLimiterCacheSize = 4096
+
+	// rateLimitAnonymous is the accessor of requests made without a known
+	// token, including all requests when ACLs are disabled. These requests are
+	// limited separately for each remote address.
+	rateLimitAnonymous = "anonymous"
+
+	// rateLimitRead and rateLimitWrite are the endpoint classes that are
+	// limited separately.
+	rateLimitRead  = "read"
+	rateLimitWrite = "write"
+)
+
+// rateLimitExemptRPCs are the RPCs made by clients to stay registered and run
+// their allocations. They are never limited so that an overloaded token can
+// not cause nodes to miss heartbeats.
+var rateLimitExemptRPCs = map[string]struct{}{
+	"ACL.GetPolicies":              {},
+	"ACL.GetRoles":                 {},
+	"ACL.ResolveToken":             {},
+	"Alloc.GetAlloc":               {},
+	"Alloc.GetAllocs":              {},
+	"Alloc.SignIdentity":           {},
+	"CSIVolume.Claim":              {},
+	"Node.DeriveVaultToken":        {},
+	"Node.EmitEvents":              {},
+	"Node.GetClientAllocs":         {},
+	"Node.GetNode":                 {},
+	"Node.Register":                {},
+	"Node.UpdateAlloc":             {},
+	"Node.UpdateMeta":              {},
+	"Node.UpdateStatus":            {},
+	"Status.Ping":                  {},
+	"Variables.ReadAllocVariables": {},
+}
+
+// rpcRateLimiter limits the rate of RPCs made with each ACL token, with
+// separate limits for reads and writes.
+type rpcRateLimiter struct {
+	config *config.RPCRateLimitConfig
+
+	// limiters is the cache of *rate.Limiter keyed by accessor and class
+	limiters *lru.Cache
+	l        sync.Mutex
+}
+
+// newRPCRateLimiter returns a rate limiter for the given config or nil if no
+// limits are configured.
+func newRPCRateLimiter(conf *config.RPCRateLimitConfig) (*rpcRateLimiter, error) {
+	if conf == nil || (conf.ReadRate <= 0 && conf.WriteRate <= 0) {
+		return nil, nil
+	}
+
+	limiters, err := lru.New(rateLimiterCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	conf = conf.Copy()
+	conf.Canonicalize()
+	return &rpcRateLimiter{
+		config:   conf,
+		limiters: limiters,
+	}, nil
+}
+
+// allow returns whether a request of the given class made by the accessor is
+// within its limit.
+func (r *rpcRateLimiter) allow(accessor, class string) bool {
+	limit, burst := r.config.WriteRate, r.config.WriteBurst
+	if class == rateLimitRead {
+		limit, burst = r.config.ReadRate, r.config.ReadBurst
+	}
+	if limit <= 0 {
+		return true
+	}
+
+	key := accessor + ":" + class
+	r.l.Lock()
+	limiter, ok := r.limiters.Get(key)
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(limit), burst)
+		r.limiters.Add(key, limiter)
+	}
+	r.l.Unlock()
+
+	return limiter.(*rate.Limiter).Allow()
+}
+
+// checkRateLimit returns ErrRateLimited if the RPC exceeds the limits of the
+// token it was made with. Only requests entering the cluster through this
+// server are limited, so requests forwarded by other servers are not counted
+// twice.
+func (s *Server) checkRateLimit(method string, info structs.RPCInfo) error {
+	if s.rpcRateLimiter == nil || (info.IsForwarded() && info.IsFromServer()) {
+		return nil
+	}
+	if _, ok := rateLimitExemptRPCs[method]; ok {
+		return nil
+	}
+
+	class := rateLimitWrite
+	if info.IsRead() {
+		class = rateLimitRead
+	}
+
+	accessor := s.rateLimitAccessor(info.RequestToken())
+	key := accessor
+	if accessor == rateLimitAnonymous && info.RemoteAddr() != "" {
+		key = accessor + ":" + info.RemoteAddr()
+	}
+	if s.rpcRateLimiter.allow(key, class) {
+		return nil
+	}
+
+	// The metric isn't labeled by accessor or address so that its cardinality
+	// stays bounded
+	metrics.IncrCounterWithLabels([]string{"nomad", "rpc", "rate_limited"}, 1,
+		[]metrics.Label{{Name: "class", Value: class}})
+	return structs.ErrRateLimited
+}
+
+// rateLimitAccessor returns the accessor ID of the token with the given
+// secret ID, which is used as the rate limit key so that the secret is never
+// kept by the limiter.
+func (s *Server) rateLimitAccessor(secretID string) string {
+	if !s.config.ACLEnabled || secretID == "" {
+		return rateLimitAnonymous
+	}
+
+	token, err := s.fsm.State().ACLTokenBySecretID(nil, secretID)
+	if err != nil || token == nil {
+		return rateLimitAnonymous
+	}
+	return token.AccessorID
+}
+
+// checkStreamingRateLimit is checkRateLimit for streaming RPCs, which decode
+// their own arguments from the connection. The remote address is taken from
+// the connection, or from the caller of an in-process streaming RPC.
+func (s *Server) checkStreamingRateLimit(method string, conn io.ReadWriteCloser, info structs.RPCInfo) error {
+	switch c := conn.(type) {
+	case *streamingRpcConn:
+		s.setRPCRemote(c.rpcCtx, info)
+	case *structs.StreamingRpcRemoteConn:
+		info.SetRemote(c.RemoteHost, false)
+	}
+	return s.checkRateLimit(method, info)
+}
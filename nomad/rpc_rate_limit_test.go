@@ -0,0 +1,190 @@
+package nomad
+
+import (
+	"net"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+	codec "github.com/ugorji/go/codec"
+)
+
+func TestRPCRateLimiter_Allow(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// No limits configured
+	limiter, err := newRPCRateLimiter(&config.RPCRateLimitConfig{})
+	require.NoError(err)
+	require.Nil(limiter)
+
+	// Only writes are limited
+	limiter, err = newRPCRateLimiter(&config.RPCRateLimitConfig{
+		WriteRate:  0.001,
+		WriteBurst: 2,
+	})
+	require.NoError(err)
+	require.NotNil(limiter)
+
+	require.True(limiter.allow("foo", rateLimitWrite))
+	require.True(limiter.allow("foo", rateLimitWrite))
+	require.False(limiter.allow("foo", rateLimitWrite))
+
+	// Each accessor has its own limit and reads are unlimited
+	require.True(limiter.allow("bar", rateLimitWrite))
+	for i := 0; i < 10; i++ {
+		require.True(limiter.allow("foo", rateLimitRead))
+	}
+}
+
+func TestRPC_RateLimit(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.RPCRateLimit = &config.RPCRateLimitConfig{
+			WriteRate:  0.001,
+			WriteBurst: 1,
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a second token
+	state := s1.fsm.State()
+	policy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{"submit-job", "list-jobs"})
+	token := mock.CreatePolicyAndToken(t, state, 1001, "submit", policy)
+
+	register := func(secretID string) error {
+		req := &structs.JobRegisterRequest{
+			Job: mock.Job(),
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+				AuthToken: secretID,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		return msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	}
+
+	// The second write of a token is limited
+	require.NoError(register(root.SecretID))
+	err := register(root.SecretID)
+	require.Error(err)
+	require.True(structs.IsErrRateLimited(err))
+
+	// Other tokens are not affected
+	require.NoError(register(token.SecretID))
+
+	// Reads are not limited
+	get := &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.JobListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp))
+	require.Len(resp.Jobs, 2)
+}
+
+func TestRPC_RateLimit_Remote(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.RPCRateLimit = &config.RPCRateLimitConfig{
+			WriteRate:  0.001,
+			WriteBurst: 1,
+		}
+	})
+	defer s1.Shutdown()
+
+	write := func(addr string, forwarded, server bool) error {
+		req := &structs.JobRegisterRequest{}
+		req.SetRemote(addr, server)
+		if forwarded {
+			req.SetForwarded()
+		}
+		return s1.checkRateLimit("Job.Register", req)
+	}
+
+	// Anonymous requests are limited per remote address
+	require.NoError(write("10.0.0.1", false, false))
+	require.True(structs.IsErrRateLimited(write("10.0.0.1", false, false)))
+	require.NoError(write("10.0.0.2", false, false))
+
+	// Callers can not skip the limit by marking their request as forwarded
+	require.True(structs.IsErrRateLimited(write("10.0.0.1", true, false)))
+
+	// Requests forwarded by servers are not counted twice
+	require.NoError(write("10.0.0.1", true, true))
+}
+
+func TestRPC_RateLimit_Streaming(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.RPCRateLimit = &config.RPCRateLimitConfig{
+			ReadRate:  0.001,
+			ReadBurst: 1,
+		}
+	})
+	defer s1.Shutdown()
+
+	logs := func(addr string, forwarded bool) error {
+		handler, err := s1.StreamingRpcHandler("FileSystem.Logs")
+		require.NoError(err)
+
+		// In-process streams made for an HTTP client carry its address
+		p1, p2 := net.Pipe()
+		defer p1.Close()
+		defer p2.Close()
+		if addr != "" {
+			go handler(structs.NewStreamingRpcRemoteConn(p2, addr))
+		} else {
+			go handler(p2)
+		}
+
+		req := &cstructs.FsLogsRequest{
+			AllocID: uuid.Generate(),
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		if forwarded {
+			req.SetForwarded()
+		}
+		require.NoError(codec.NewEncoder(p1, structs.MsgpackHandle).Encode(req))
+
+		var msg cstructs.StreamErrWrapper
+		require.NoError(codec.NewDecoder(p1, structs.MsgpackHandle).Decode(&msg))
+		require.NotNil(msg.Error)
+		return msg.Error
+	}
+
+	// The first stream is allowed and the second is limited
+	err := logs("", false)
+	require.False(structs.IsErrRateLimited(err))
+	require.True(structs.IsErrUnknownAllocation(err))
+	require.True(structs.IsErrRateLimited(logs("", false)))
+
+	// Streams made for HTTP clients are limited per client
+	require.True(structs.IsErrUnknownAllocation(logs("10.0.0.1:4646", false)))
+	require.True(structs.IsErrRateLimited(logs("10.0.0.1:4646", false)))
+	require.True(structs.IsErrUnknownAllocation(logs("10.0.0.2:4646", false)))
+
+	// Streams marked as forwarded by the caller are limited too
+	require.True(structs.IsErrRateLimited(logs("", true)))
+}
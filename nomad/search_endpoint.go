@@ -88,7 +88,11 @@ func getResourceIter(context structs.Context, aclObj *acl.ACL, namespace, prefix
 	case structs.Allocs:
 		return state.AllocsByIDPrefix(ws, namespace, prefix)
 	case structs.Nodes:
-		return state.NodesByIDPrefix(ws, prefix)
+		iter, err := state.NodesByIDPrefix(ws, prefix)
+		if err != nil || aclObj == nil {
+			return iter, err
+		}
+		return memdb.NewFilterIterator(iter, nodeClassFilter(aclObj)), nil
 	case structs.Deployments:
 		return state.DeploymentsByIDPrefix(ws, namespace, prefix)
 	default:
@@ -96,6 +100,18 @@ func getResourceIter(context structs.Context, aclObj *acl.ACL, namespace, prefix
 	}
 }
 
+// nodeClassFilter returns a filter function that filters out nodes whose
+// class is not readable by the given ACL.
+func nodeClassFilter(aclObj *acl.ACL) func(interface{}) bool {
+	return func(raw interface{}) bool {
+		node, ok := raw.(*structs.Node)
+		if !ok {
+			return true
+		}
+		return !aclObj.AllowNodeClassRead(node.NodeClass)
+	}
+}
+
 // If the length of a prefix is odd, return a subset to the last even character
 // This only applies to UUIDs, jobs are excluded
 func roundUUIDDownIfOdd(prefix string, context structs.Context) string {
@@ -42,7 +42,7 @@ func anySearchPerms(aclObj *acl.ACL, namespace string, context structs.Context)
 		return true
 	}
 
-	nodeRead := aclObj.AllowNodeRead()
+	nodeRead := aclObj.AllowAnyNodeRead()
 	jobRead := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob)
 	if !nodeRead && !jobRead {
 		return false
@@ -93,7 +93,7 @@ func searchContexts(aclObj *acl.ACL, namespace string, context structs.Context)
 				available = append(available, c)
 			}
 		case structs.Nodes:
-			if aclObj.AllowNodeRead() {
+			if aclObj.AllowAnyNodeRead() {
 				available = append(available, c)
 			}
 		}
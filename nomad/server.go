@@ -56,6 +56,7 @@ const (
 
 	raftState         = "raft/"
 	serfSnapshot      = "serf/snapshot"
+	keystoreDir       = "keystore"
 	snapshotsRetained = 2
 
 	// serverRPCCache controls how long we keep an idle connection open to a server
@@ -176,6 +177,9 @@ type Server struct {
 	// nodeDrainer is used to drain allocations from nodes.
 	nodeDrainer *drainer.NodeDrainer
 
+	// drainPlanWatcher is used to start node drains for drain plans.
+	drainPlanWatcher *drainer.DrainPlanWatcher
+
 	// evalBroker is used to manage the in-progress evaluations
 	// that are waiting to be brokered to a sub-scheduler
 	evalBroker *EvalBroker
@@ -201,9 +205,19 @@ type Server struct {
 	// Worker used for processing
 	workers []*Worker
 
+	// encrypter is used to encrypt and decrypt variables using the keyring
+	encrypter *Encrypter
+
 	// aclCache is used to maintain the parsed ACL objects
 	aclCache *lru.TwoQueueCache
 
+	// authProviders caches the identity providers of the auth methods
+	authProviders *authProviderCache
+
+	// rpcRateLimiter limits the RPCs of each ACL token. It is nil if no
+	// limits are configured.
+	rpcRateLimiter *rpcRateLimiter
+
 	// leaderAcl is the management ACL token that is valid when resolved by the
 	// current leader.
 	leaderAcl     string
@@ -231,18 +245,24 @@ type endpoints struct {
 	Plan       *Plan
 	Alloc      *Alloc
 	Deployment *Deployment
+	DrainPlan  *DrainPlan
+	CSIVolume  *CSIVolume
+	CSIPlugin  *CSIPlugin
 	Region     *Region
 	Search     *Search
 	Periodic   *Periodic
 	System     *System
 	Operator   *Operator
 	ACL        *ACL
+	Variables  *Variables
 	Enterprise *EnterpriseEndpoints
 
 	// Client endpoints
 	ClientStats       *ClientStats
 	FileSystem        *FileSystem
 	ClientAllocations *ClientAllocations
+	ClientNodeMeta    *ClientNodeMeta
+	ClientCSI         *ClientCSI
 }
 
 // NewServer is used to construct a new Nomad server from the
@@ -262,6 +282,7 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, logger *log.Logg
 	if err != nil {
 		return nil, err
 	}
+	evalBroker.SetNamespaceWeights(config.EvalNamespaceWeights)
 
 	// Create a new blocked eval tracker.
 	blockedEvals := NewBlockedEvals(evalBroker)
@@ -307,9 +328,27 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, logger *log.Logg
 		planQueue:     planQueue,
 		rpcTLS:        incomingTLS,
 		aclCache:      aclCache,
+		authProviders: newAuthProviderCache(),
 		shutdownCh:    make(chan struct{}),
 	}
 
+	// Create the encrypter used for variables. The keys are only kept in
+	// memory in dev mode.
+	keystorePath := ""
+	if !s.config.DevMode {
+		keystorePath = filepath.Join(s.config.DataDir, keystoreDir)
+	}
+	s.encrypter, err = NewEncrypter(s, keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup keystore: %v", err)
+	}
+
+	// Create the rate limiter of RPCs
+	s.rpcRateLimiter, err = newRPCRateLimiter(config.RPCRateLimit)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the periodic dispatcher for launching periodic jobs.
 	s.periodicDispatcher = NewPeriodicDispatch(s.logger, s)
 
@@ -394,6 +433,9 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, logger *log.Logg
 	// Emit metrics
 	go s.heartbeatStats()
 
+	// Replicate the root keys missing from the keystore
+	go s.encrypter.replicate(s.shutdownCh)
+
 	// Start enterprise background workers
 	s.startEnterpriseBackground()
 
@@ -920,6 +962,7 @@ func (s *Server) setupNodeDrainer() {
 		BatchUpdateInterval:   drainer.BatchUpdateInterval,
 	}
 	s.nodeDrainer = drainer.NewNodeDrainer(c)
+	s.drainPlanWatcher = drainer.NewDrainPlanWatcher(s.logger, shim, drainer.LimitStateQueriesPerSecond)
 }
 
 // setupVaultClient is used to set up the Vault API client.
@@ -1001,9 +1044,12 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		s.staticEndpoints.ACL = &ACL{s}
 		s.staticEndpoints.Alloc = &Alloc{s}
 		s.staticEndpoints.Eval = &Eval{s}
-		s.staticEndpoints.Job = &Job{s}
+		s.staticEndpoints.Job = NewJobEndpoints(s)
 		s.staticEndpoints.Node = &Node{srv: s} // Add but don't register
 		s.staticEndpoints.Deployment = &Deployment{srv: s}
+		s.staticEndpoints.DrainPlan = &DrainPlan{s}
+		s.staticEndpoints.CSIVolume = &CSIVolume{s}
+		s.staticEndpoints.CSIPlugin = &CSIPlugin{s}
 		s.staticEndpoints.Operator = &Operator{s}
 		s.staticEndpoints.Periodic = &Periodic{s}
 		s.staticEndpoints.Plan = &Plan{s}
@@ -1011,11 +1057,15 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		s.staticEndpoints.Status = &Status{s}
 		s.staticEndpoints.System = &System{s}
 		s.staticEndpoints.Search = &Search{s}
+		s.staticEndpoints.Variables = &Variables{s}
 		s.staticEndpoints.Enterprise = NewEnterpriseEndpoints(s)
 
 		// Client endpoints
 		s.staticEndpoints.ClientStats = &ClientStats{s}
 		s.staticEndpoints.ClientAllocations = &ClientAllocations{s}
+		s.staticEndpoints.ClientAllocations.register()
+		s.staticEndpoints.ClientNodeMeta = &ClientNodeMeta{s}
+		s.staticEndpoints.ClientCSI = &ClientCSI{s}
 
 		// Streaming endpoints
 		s.staticEndpoints.FileSystem = &FileSystem{s}
@@ -1028,6 +1078,9 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(s.staticEndpoints.Eval)
 	server.Register(s.staticEndpoints.Job)
 	server.Register(s.staticEndpoints.Deployment)
+	server.Register(s.staticEndpoints.DrainPlan)
+	server.Register(s.staticEndpoints.CSIVolume)
+	server.Register(s.staticEndpoints.CSIPlugin)
 	server.Register(s.staticEndpoints.Operator)
 	server.Register(s.staticEndpoints.Periodic)
 	server.Register(s.staticEndpoints.Plan)
@@ -1035,16 +1088,21 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(s.staticEndpoints.Status)
 	server.Register(s.staticEndpoints.System)
 	server.Register(s.staticEndpoints.Search)
+	server.Register(s.staticEndpoints.Variables)
 	s.staticEndpoints.Enterprise.Register(server)
 	server.Register(s.staticEndpoints.ClientStats)
 	server.Register(s.staticEndpoints.ClientAllocations)
+	server.Register(s.staticEndpoints.ClientNodeMeta)
+	server.Register(s.staticEndpoints.ClientCSI)
 	server.Register(s.staticEndpoints.FileSystem)
 
 	// Create new dynamic endpoints and add them to the RPC server.
 	node := &Node{srv: s, ctx: ctx}
+	keyring := &Keyring{srv: s, ctx: ctx}
 
 	// Register the dynamic endpoints
 	server.Register(node)
+	server.Register(keyring)
 }
 
 // setupRaft is used to setup and initialize Raft
@@ -1060,11 +1118,12 @@ func (s *Server) setupRaft() error {
 
 	// Create the FSM
 	fsmConfig := &FSMConfig{
-		EvalBroker: s.evalBroker,
-		Periodic:   s.periodicDispatcher,
-		Blocked:    s.blockedEvals,
-		LogOutput:  s.config.LogOutput,
-		Region:     s.Region(),
+		EvalBroker:    s.evalBroker,
+		Periodic:      s.periodicDispatcher,
+		Blocked:       s.blockedEvals,
+		LogOutput:     s.config.LogOutput,
+		Region:        s.Region(),
+		AuthProviders: s.authProviders,
 	}
 	var err error
 	s.fsm, err = NewFSM(fsmConfig)
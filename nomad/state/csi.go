@@ -0,0 +1,325 @@
+package state
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// csiVolumeTableSchema returns the MemDB schema for the CSI volumes table.
+// This table stores the volumes registered with the servers and the claims of
+// allocations on them.
+func csiVolumeTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "csi_volumes",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field: "ID",
+						},
+					},
+				},
+			},
+			"plugin_id": {
+				Name:         "plugin_id",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "PluginID",
+				},
+			},
+		},
+	}
+}
+
+// CSIVolumeRegister adds or updates volumes. The claims of existing volumes
+// are preserved.
+func (s *StateStore) CSIVolumeRegister(index uint64, volumes []*structs.CSIVolume) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, v := range volumes {
+		existing, err := txn.First("csi_volumes", "id", v.Namespace, v.ID)
+		if err != nil {
+			return fmt.Errorf("volume lookup failed: %v", err)
+		}
+
+		if existing != nil {
+			old := existing.(*structs.CSIVolume)
+			if old.InUse() && (old.PluginID != v.PluginID || old.ExternalID != v.ExternalID) {
+				return fmt.Errorf("volume %q is in use and can't change its plugin or external ID", v.ID)
+			}
+			v.ReadClaims = old.ReadClaims
+			v.WriteClaims = old.WriteClaims
+			v.CreateIndex = old.CreateIndex
+		} else {
+			v.ReadClaims = nil
+			v.WriteClaims = nil
+			v.CreateIndex = index
+		}
+		v.ModifyIndex = index
+
+		if err := txn.Insert("csi_volumes", v); err != nil {
+			return fmt.Errorf("volume insert failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"csi_volumes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// CSIVolumeDeregister removes volumes. Volumes that are claimed by allocations
+// can't be removed until the claims are released.
+func (s *StateStore) CSIVolumeDeregister(index uint64, namespace string, ids []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, id := range ids {
+		existing, err := txn.First("csi_volumes", "id", namespace, id)
+		if err != nil {
+			return fmt.Errorf("volume lookup failed: %v", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("volume %q not found", id)
+		}
+
+		if existing.(*structs.CSIVolume).InUse() {
+			return fmt.Errorf("volume %q is in use", id)
+		}
+
+		if err := txn.Delete("csi_volumes", existing); err != nil {
+			return fmt.Errorf("volume delete failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"csi_volumes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// CSIVolumeClaim records or releases the claim of an allocation on a volume.
+// Claims of allocations that are gone or stopped are kept until they are
+// released, as the volume may still be published on their node or attached to
+// it.
+func (s *StateStore) CSIVolumeClaim(index uint64, namespace, id string, claim *structs.CSIVolumeClaim) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("csi_volumes", "id", namespace, id)
+	if err != nil {
+		return fmt.Errorf("volume lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("volume %q not found", id)
+	}
+
+	vol := existing.(*structs.CSIVolume).Copy()
+	if claim.Mode != structs.CSIVolumeClaimRelease {
+		alloc, err := txn.First("allocs", "id", claim.AllocationID)
+		if err != nil {
+			return fmt.Errorf("alloc lookup failed: %v", err)
+		}
+		if alloc == nil {
+			return fmt.Errorf("alloc %q not found", claim.AllocationID)
+		}
+	}
+
+	if err := vol.Claim(claim); err != nil {
+		return err
+	}
+	vol.ModifyIndex = index
+
+	if err := txn.Insert("csi_volumes", vol); err != nil {
+		return fmt.Errorf("volume insert failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"csi_volumes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// CSIVolumeByID is used to lookup a volume. The health of its plugin is
+// denormalized into the volume.
+func (s *StateStore) CSIVolumeByID(ws memdb.WatchSet, namespace, id string) (*structs.CSIVolume, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("csi_volumes", "id", namespace, id)
+	if err != nil {
+		return nil, fmt.Errorf("volume lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+
+	return s.csiVolumeDenormalize(ws, txn, existing.(*structs.CSIVolume))
+}
+
+// csiVolumeDenormalize returns a copy of the volume with the health of its
+// plugin.
+func (s *StateStore) csiVolumeDenormalize(ws memdb.WatchSet, txn *memdb.Txn, vol *structs.CSIVolume) (*structs.CSIVolume, error) {
+	plugin, err := s.csiPluginByIDImpl(ws, txn, vol.PluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	vol = vol.Copy()
+	vol.Provider = ""
+	vol.ControllerRequired = false
+	vol.ControllersHealthy = 0
+	vol.NodesHealthy = 0
+	vol.Schedulable = false
+	if plugin != nil {
+		vol.Provider = plugin.Provider
+		vol.ControllerRequired = plugin.ControllerRequired
+		vol.ControllersHealthy = plugin.ControllersHealthy
+		vol.NodesHealthy = plugin.NodesHealthy
+		vol.Schedulable = plugin.NodesHealthy > 0 &&
+			(!plugin.ControllerRequired || plugin.ControllersHealthy > 0)
+	}
+	return vol, nil
+}
+
+// CSIVolumes returns an iterator over all the volumes
+func (s *StateStore) CSIVolumes(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("csi_volumes", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// CSIVolumesByNamespace returns an iterator over the volumes of a namespace
+func (s *StateStore) CSIVolumesByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("csi_volumes", "id_prefix", namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// CSIVolumesByPluginID returns an iterator over the volumes of a plugin
+func (s *StateStore) CSIVolumesByPluginID(ws memdb.WatchSet, pluginID string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("csi_volumes", "plugin_id", pluginID)
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// CSIVolumeDenormalize returns a copy of the volume with the health of its
+// plugin. It is used for volumes read from the iterators.
+func (s *StateStore) CSIVolumeDenormalize(ws memdb.WatchSet, vol *structs.CSIVolume) (*structs.CSIVolume, error) {
+	txn := s.db.Txn(false)
+	return s.csiVolumeDenormalize(ws, txn, vol)
+}
+
+// CSIPluginByID is used to lookup a plugin. Plugins are not stored but are
+// collected from the fingerprints of the nodes running them.
+func (s *StateStore) CSIPluginByID(ws memdb.WatchSet, id string) (*structs.CSIPlugin, error) {
+	txn := s.db.Txn(false)
+	return s.csiPluginByIDImpl(ws, txn, id)
+}
+
+func (s *StateStore) csiPluginByIDImpl(ws memdb.WatchSet, txn *memdb.Txn, id string) (*structs.CSIPlugin, error) {
+	plugins, err := s.csiPluginsImpl(ws, txn)
+	if err != nil {
+		return nil, err
+	}
+	return plugins[id], nil
+}
+
+// CSIPlugins returns all the plugins running on the nodes, sorted by ID
+func (s *StateStore) CSIPlugins(ws memdb.WatchSet) ([]*structs.CSIPlugin, error) {
+	txn := s.db.Txn(false)
+
+	plugins, err := s.csiPluginsImpl(ws, txn)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*structs.CSIPlugin, 0, len(plugins))
+	for _, p := range plugins {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// csiPluginsImpl collects the plugins from the fingerprints of all the nodes
+func (s *StateStore) csiPluginsImpl(ws memdb.WatchSet, txn *memdb.Txn) (map[string]*structs.CSIPlugin, error) {
+	iter, err := txn.Get("nodes", "id")
+	if err != nil {
+		return nil, fmt.Errorf("node lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	plugins := make(map[string]*structs.CSIPlugin)
+	get := func(id string, node *structs.Node) *structs.CSIPlugin {
+		p, ok := plugins[id]
+		if !ok {
+			p = structs.NewCSIPlugin(id, node.CreateIndex)
+			plugins[id] = p
+		}
+		if node.CreateIndex < p.CreateIndex {
+			p.CreateIndex = node.CreateIndex
+		}
+		if node.ModifyIndex > p.ModifyIndex {
+			p.ModifyIndex = node.ModifyIndex
+		}
+		return p
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		ready := node.Status == structs.NodeStatusReady
+		for id, info := range node.CSIControllerPlugins {
+			get(id, node).AddPlugin(node.ID, info, node.CSINodePlugins[id], ready)
+		}
+		for id, info := range node.CSINodePlugins {
+			if _, ok := node.CSIControllerPlugins[id]; ok {
+				continue
+			}
+			get(id, node).AddPlugin(node.ID, nil, info, ready)
+		}
+	}
+	return plugins, nil
+}
+
+// CSIVolumeRestore is used to restore a volume
+func (r *StateRestore) CSIVolumeRestore(vol *structs.CSIVolume) error {
+	if err := r.txn.Insert("csi_volumes", vol); err != nil {
+		return fmt.Errorf("volume insert failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_CSIVolume(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	vol := mock.CSIVolume()
+	require.NoError(state.CSIVolumeRegister(1000, []*structs.CSIVolume{vol}))
+
+	// The volume isn't schedulable until its plugin is running
+	out, err := state.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	require.NoError(err)
+	require.Equal(uint64(1000), out.CreateIndex)
+	require.False(out.Schedulable)
+
+	node := mock.CSINode()
+	require.NoError(state.UpsertNode(1001, node))
+
+	out, err = state.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	require.NoError(err)
+	require.True(out.Schedulable)
+	require.True(out.ControllerRequired)
+	require.Equal(1, out.ControllersHealthy)
+	require.Equal(1, out.NodesHealthy)
+	require.Equal("com.hashicorp:minnie", out.Provider)
+
+	// Claims require the allocation to exist
+	alloc1, alloc2 := mock.Alloc(), mock.Alloc()
+	alloc1.NodeID, alloc2.NodeID = node.ID, node.ID
+	claim1 := &structs.CSIVolumeClaim{AllocationID: alloc1.ID, NodeID: node.ID, Mode: structs.CSIVolumeClaimWrite}
+	claim2 := &structs.CSIVolumeClaim{AllocationID: alloc2.ID, NodeID: node.ID, Mode: structs.CSIVolumeClaimWrite}
+	require.Error(state.CSIVolumeClaim(1002, vol.Namespace, vol.ID, claim1))
+
+	require.NoError(state.UpsertJob(1003, alloc1.Job))
+	require.NoError(state.UpsertAllocs(1004, []*structs.Allocation{alloc1, alloc2}))
+	require.NoError(state.CSIVolumeClaim(1005, vol.Namespace, vol.ID, claim1))
+
+	// A single node writer volume can't be claimed by a second writer
+	err = state.CSIVolumeClaim(1006, vol.Namespace, vol.ID, claim2)
+	require.Error(err)
+	require.Contains(err.Error(), "max write claims")
+
+	// Claimed volumes can't change their plugin or be deregistered
+	update := vol.Copy()
+	update.PluginID = "mickey"
+	require.Error(state.CSIVolumeRegister(1007, []*structs.CSIVolume{update}))
+	require.Error(state.CSIVolumeDeregister(1007, vol.Namespace, []string{vol.ID}))
+
+	// Re-registering the volume preserves its claims
+	update = vol.Copy()
+	update.Name = "renamed"
+	require.NoError(state.CSIVolumeRegister(1008, []*structs.CSIVolume{update}))
+	out, err = state.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	require.NoError(err)
+	require.Equal("renamed", out.Name)
+	require.Equal(uint64(1000), out.CreateIndex)
+	require.Contains(out.WriteClaims, alloc1.ID)
+
+	// Claims of terminal allocations are kept until they are released
+	stopped := alloc1.Copy()
+	stopped.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(state.UpdateAllocsFromClient(1009, []*structs.Allocation{stopped}))
+	require.Error(state.CSIVolumeClaim(1010, vol.Namespace, vol.ID, claim2))
+
+	release := &structs.CSIVolumeClaim{AllocationID: alloc1.ID, Mode: structs.CSIVolumeClaimRelease}
+	require.NoError(state.CSIVolumeClaim(1010, vol.Namespace, vol.ID, release))
+	require.NoError(state.CSIVolumeClaim(1010, vol.Namespace, vol.ID, claim2))
+
+	out, err = state.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	require.NoError(err)
+	require.Len(out.WriteClaims, 1)
+	require.Contains(out.WriteClaims, alloc2.ID)
+
+	// Released volumes can be deregistered
+	release = &structs.CSIVolumeClaim{AllocationID: alloc2.ID, Mode: structs.CSIVolumeClaimRelease}
+	require.NoError(state.CSIVolumeClaim(1011, vol.Namespace, vol.ID, release))
+	require.NoError(state.CSIVolumeDeregister(1012, vol.Namespace, []string{vol.ID}))
+
+	out, err = state.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	index, err := state.Index("csi_volumes")
+	require.NoError(err)
+	require.Equal(uint64(1012), index)
+}
+
+func TestStateStore_CSIPlugins(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	node1, node2 := mock.CSINode(), mock.CSINode()
+	delete(node2.CSIControllerPlugins, "minnie")
+	node2.CSINodePlugins["minnie"].Healthy = false
+	require.NoError(state.UpsertNode(1000, node1))
+	require.NoError(state.UpsertNode(1001, node2))
+
+	plugin, err := state.CSIPluginByID(nil, "minnie")
+	require.NoError(err)
+	require.Len(plugin.Controllers, 1)
+	require.Len(plugin.Nodes, 2)
+	require.Equal(1, plugin.ControllersHealthy)
+	require.Equal(1, plugin.NodesHealthy)
+	require.True(plugin.ControllerRequired)
+	require.Equal(uint64(1000), plugin.CreateIndex)
+	require.Equal(uint64(1001), plugin.ModifyIndex)
+
+	// Plugins on down nodes aren't healthy
+	require.NoError(state.UpdateNodeStatus(1002, node1.ID, structs.NodeStatusDown, nil))
+	plugins, err := state.CSIPlugins(nil)
+	require.NoError(err)
+	require.Len(plugins, 1)
+	require.Zero(plugins[0].ControllersHealthy)
+	require.Zero(plugins[0].NodesHealthy)
+
+	plugin, err = state.CSIPluginByID(nil, "mickey")
+	require.NoError(err)
+	require.Nil(plugin)
+}
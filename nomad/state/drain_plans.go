@@ -0,0 +1,128 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// drainPlanTableSchema returns the MemDB schema for the drain plans table.
+// This table stores the plans used to drain groups of nodes in batches.
+func drainPlanTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "drain_plans",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "ID",
+				},
+			},
+		},
+	}
+}
+
+// UpsertDrainPlan is used to insert or update a drain plan. Any node drain
+// updates in the request are applied in the same transaction.
+func (s *StateStore) UpsertDrainPlan(index uint64, req *structs.DrainPlanUpsertRequest) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	plan := req.Plan
+	existing, err := txn.First("drain_plans", "id", plan.ID)
+	if err != nil {
+		return fmt.Errorf("drain plan lookup failed: %v", err)
+	}
+
+	// Check that the plan was not modified since the update was made
+	if req.PlanModifyIndex != 0 {
+		if existing == nil {
+			return fmt.Errorf("drain plan %q not found", plan.ID)
+		}
+		if modifyIndex := existing.(*structs.DrainPlan).ModifyIndex; modifyIndex != req.PlanModifyIndex {
+			return fmt.Errorf("%s: drain plan %q has modify index %d, expected %d",
+				structs.ErrDrainPlanModified, plan.ID, modifyIndex, req.PlanModifyIndex)
+		}
+	}
+
+	for node, update := range req.Updates {
+		if err := s.updateNodeDrainImpl(txn, index, node, update.DrainStrategy, update.MarkEligible, req.NodeEvents[node]); err != nil {
+			return err
+		}
+	}
+
+	// Record the index at which the plan started draining each node
+	for _, n := range plan.Nodes {
+		if _, ok := req.Updates[n.NodeID]; ok {
+			n.DrainIndex = index
+		}
+	}
+
+	if existing != nil {
+		plan.CreateIndex = existing.(*structs.DrainPlan).CreateIndex
+	} else {
+		plan.CreateIndex = index
+	}
+	plan.ModifyIndex = index
+
+	if err := txn.Insert("drain_plans", plan); err != nil {
+		return fmt.Errorf("drain plan insert failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"drain_plans", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DrainPlanByID is used to lookup a drain plan by its ID
+func (s *StateStore) DrainPlanByID(ws memdb.WatchSet, id string) (*structs.DrainPlan, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("drain_plans", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("drain plan lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.DrainPlan), nil
+	}
+	return nil, nil
+}
+
+// DrainPlansByIDPrefix is used to lookup drain plans by prefix
+func (s *StateStore) DrainPlansByIDPrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("drain_plans", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("drain plan lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// DrainPlans returns an iterator over all the drain plans
+func (s *StateStore) DrainPlans(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("drain_plans", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// DrainPlanRestore is used to restore a drain plan
+func (r *StateRestore) DrainPlanRestore(plan *structs.DrainPlan) error {
+	if err := r.txn.Insert("drain_plans", plan); err != nil {
+		return fmt.Errorf("drain plan insert failed: %v", err)
+	}
+	return nil
+}
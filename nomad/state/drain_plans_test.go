@@ -0,0 +1,132 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertDrainPlan(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1000, node))
+
+	plan := mock.DrainPlan()
+	plan.Nodes[0].NodeID = node.ID
+	require.NoError(state.UpsertDrainPlan(1001, &structs.DrainPlanUpsertRequest{Plan: plan}))
+
+	out, err := state.DrainPlanByID(nil, plan.ID)
+	require.NoError(err)
+	require.Equal(uint64(1001), out.CreateIndex)
+	require.Zero(out.Nodes[0].DrainIndex)
+
+	// Starting the drain of the node records the index on the plan
+	plan = plan.Copy()
+	plan.Nodes[0].Status = structs.DrainPlanNodeStatusDraining
+	req := &structs.DrainPlanUpsertRequest{
+		Plan: plan,
+		Updates: map[string]*structs.DrainUpdate{
+			node.ID: {
+				DrainStrategy: &structs.DrainStrategy{DrainSpec: plan.DrainSpec},
+			},
+		},
+		NodeEvents: map[string]*structs.NodeEvent{
+			node.ID: structs.NewNodeEvent().SetMessage("drain set"),
+		},
+	}
+	require.NoError(state.UpsertDrainPlan(1002, req))
+
+	out, err = state.DrainPlanByID(nil, plan.ID)
+	require.NoError(err)
+	require.Equal(uint64(1001), out.CreateIndex)
+	require.Equal(uint64(1002), out.ModifyIndex)
+	require.Equal(uint64(1002), out.Nodes[0].DrainIndex)
+
+	outNode, err := state.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.NotNil(outNode.DrainStrategy)
+	require.Len(outNode.Events, 2)
+
+	index, err := state.Index("drain_plans")
+	require.NoError(err)
+	require.Equal(uint64(1002), index)
+
+	// A failed node update aborts the plan update
+	plan = plan.Copy()
+	plan.Status = structs.DrainPlanStatusComplete
+	req = &structs.DrainPlanUpsertRequest{
+		Plan: plan,
+		Updates: map[string]*structs.DrainUpdate{
+			"missing": {},
+		},
+	}
+	require.Error(state.UpsertDrainPlan(1003, req))
+
+	out, err = state.DrainPlanByID(nil, plan.ID)
+	require.NoError(err)
+	require.Equal(structs.DrainPlanStatusRunning, out.Status)
+}
+
+func TestStateStore_UpsertDrainPlan_ModifyIndex(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	plan := mock.DrainPlan()
+	require.NoError(state.UpsertDrainPlan(1000, &structs.DrainPlanUpsertRequest{Plan: plan}))
+
+	// An operator pauses the plan
+	paused := plan.Copy()
+	paused.Status = structs.DrainPlanStatusPaused
+	require.NoError(state.UpsertDrainPlan(1001, &structs.DrainPlanUpsertRequest{
+		Plan:            paused,
+		PlanModifyIndex: 1000,
+	}))
+
+	// An update made from the plan before it was paused is rejected
+	progressed := plan.Copy()
+	progressed.Nodes[0].Status = structs.DrainPlanNodeStatusDraining
+	err := state.UpsertDrainPlan(1002, &structs.DrainPlanUpsertRequest{
+		Plan:            progressed,
+		PlanModifyIndex: 1000,
+	})
+	require.Error(err)
+	require.True(structs.IsErrDrainPlanModified(err))
+
+	out, err := state.DrainPlanByID(nil, plan.ID)
+	require.NoError(err)
+	require.Equal(structs.DrainPlanStatusPaused, out.Status)
+	require.Equal(uint64(1001), out.ModifyIndex)
+	require.Equal(structs.DrainPlanNodeStatusPending, out.Nodes[0].Status)
+}
+
+func TestStateStore_DrainPlansByIDPrefix(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	p1 := mock.DrainPlan()
+	p1.ID = "11111111-7bfb-40d1-f1b1-e6c55a6b8a5e"
+	p2 := mock.DrainPlan()
+	p2.ID = "11222222-7bfb-40d1-f1b1-e6c55a6b8a5e"
+	require.NoError(state.UpsertDrainPlan(1000, &structs.DrainPlanUpsertRequest{Plan: p1}))
+	require.NoError(state.UpsertDrainPlan(1001, &structs.DrainPlanUpsertRequest{Plan: p2}))
+
+	count := func(prefix string) int {
+		iter, err := state.DrainPlansByIDPrefix(nil, prefix)
+		require.NoError(err)
+		n := 0
+		for iter.Next() != nil {
+			n++
+		}
+		return n
+	}
+	require.Equal(2, count("11"))
+	require.Equal(1, count("1122"))
+	require.Equal(0, count("33"))
+}
@@ -0,0 +1,132 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// rootKeyMetaTableSchema returns the MemDB schema for the root key metadata
+// table. The key material itself is never stored in the state store.
+func rootKeyMetaTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "root_key_meta",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "KeyID",
+				},
+			},
+		},
+	}
+}
+
+// UpsertRootKeyMeta is used to insert or update the metadata of a root key.
+// If the key is active all other keys are marked inactive.
+func (s *StateStore) UpsertRootKeyMeta(index uint64, meta *structs.RootKeyMeta) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("root_key_meta", "id", meta.KeyID)
+	if err != nil {
+		return fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+
+	meta = meta.Copy()
+	if existing != nil {
+		meta.CreateIndex = existing.(*structs.RootKeyMeta).CreateIndex
+	} else {
+		meta.CreateIndex = index
+	}
+	meta.ModifyIndex = index
+
+	if meta.IsActive() {
+		iter, err := txn.Get("root_key_meta", "id")
+		if err != nil {
+			return fmt.Errorf("root key metadata lookup failed: %v", err)
+		}
+
+		var deactivate []*structs.RootKeyMeta
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			other := raw.(*structs.RootKeyMeta)
+			if other.KeyID != meta.KeyID && other.IsActive() {
+				deactivate = append(deactivate, other)
+			}
+		}
+
+		for _, other := range deactivate {
+			other = other.Copy()
+			other.State = structs.RootKeyStateInactive
+			other.ModifyIndex = index
+			if err := txn.Insert("root_key_meta", other); err != nil {
+				return fmt.Errorf("root key metadata insert failed: %v", err)
+			}
+		}
+	}
+
+	if err := txn.Insert("root_key_meta", meta); err != nil {
+		return fmt.Errorf("root key metadata insert failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"root_key_meta", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// RootKeyMetaByID is used to lookup the metadata of a root key by its ID
+func (s *StateStore) RootKeyMetaByID(ws memdb.WatchSet, id string) (*structs.RootKeyMeta, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("root_key_meta", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.RootKeyMeta), nil
+	}
+	return nil, nil
+}
+
+// RootKeyMetas returns an iterator over the metadata of all the root keys
+func (s *StateStore) RootKeyMetas(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("root_key_meta", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// GetActiveRootKeyMeta returns the metadata of the root key used for new
+// encryptions, or nil if the keyring has not been initialized
+func (s *StateStore) GetActiveRootKeyMeta(ws memdb.WatchSet) (*structs.RootKeyMeta, error) {
+	iter, err := s.RootKeyMetas(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		meta := raw.(*structs.RootKeyMeta)
+		if meta.IsActive() {
+			return meta, nil
+		}
+	}
+	return nil, nil
+}
+
+// RootKeyMetaRestore is used to restore the metadata of a root key
+func (r *StateRestore) RootKeyMetaRestore(meta *structs.RootKeyMeta) error {
+	if err := r.txn.Insert("root_key_meta", meta); err != nil {
+		return fmt.Errorf("inserting root key metadata failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertRootKeyMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	key1 := structs.NewRootKey("key1", []byte("secret1"))
+	require.NoError(state.UpsertRootKeyMeta(1000, key1.Meta))
+
+	active, err := state.GetActiveRootKeyMeta(nil)
+	require.NoError(err)
+	require.Equal("key1", active.KeyID)
+	require.Equal(uint64(1000), active.CreateIndex)
+
+	// Adding a new active key makes the existing one inactive
+	key2 := structs.NewRootKey("key2", []byte("secret2"))
+	require.NoError(state.UpsertRootKeyMeta(1001, key2.Meta))
+
+	active, err = state.GetActiveRootKeyMeta(nil)
+	require.NoError(err)
+	require.Equal("key2", active.KeyID)
+
+	out, err := state.RootKeyMetaByID(nil, "key1")
+	require.NoError(err)
+	require.False(out.IsActive())
+	require.Equal(uint64(1001), out.ModifyIndex)
+
+	index, err := state.Index("root_key_meta")
+	require.NoError(err)
+	require.Equal(uint64(1001), index)
+}
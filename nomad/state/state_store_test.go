@@ -1669,6 +1669,116 @@ func TestStateStore_DeleteJob_MultipleVersions(t *testing.T) {
 	assert.False(watchFired(ws))
 }
 
+func TestStateStore_JobSubmission(t *testing.T) {
+	state := testStateStore(t)
+	require := require.New(t)
+
+	job := mock.Job()
+	require.Nil(state.UpsertJob(1000, job))
+
+	// Storing the source of a missing version fails
+	sub := &structs.JobSubmission{
+		Source:    "job \"example\" {}",
+		Format:    structs.JobSubmissionFormatHCL,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+		Version:   1,
+	}
+	require.Error(state.UpsertJobSubmission(1001, sub))
+
+	// Store the source of the first version
+	ws := memdb.NewWatchSet()
+	out, err := state.JobSubmission(ws, job.Namespace, job.ID, 0)
+	require.Nil(err)
+	require.Nil(out)
+
+	sub.Version = 0
+	require.Nil(state.UpsertJobSubmission(1001, sub))
+	require.True(watchFired(ws))
+
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.Nil(err)
+	require.Equal(sub, out)
+
+	index, err := state.Index("job_submission")
+	require.Nil(err)
+	require.EqualValues(1001, index)
+
+	// Create enough versions that the first one is no longer tracked
+	for i := 1; i <= structs.JobTrackedVersions; i++ {
+		next := mock.Job()
+		next.ID = job.ID
+		next.Priority = i
+		require.Nil(state.UpsertJob(uint64(1001+i), next))
+	}
+
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.Nil(err)
+	require.Nil(out)
+
+	// Store the source of the latest version and delete the job
+	latest := sub.Copy()
+	latest.Version = structs.JobTrackedVersions
+	require.Nil(state.UpsertJobSubmission(1010, latest))
+	require.Nil(state.DeleteJob(1011, job.Namespace, job.ID))
+
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, latest.Version)
+	require.Nil(err)
+	require.Nil(out)
+}
+
+func TestStateStore_UpsertJobWithSubmission(t *testing.T) {
+	state := testStateStore(t)
+	require := require.New(t)
+
+	job := mock.Job()
+	require.Nil(state.UpsertJob(1000, job))
+
+	// Watch both the job and the source of its next version
+	ws := memdb.NewWatchSet()
+	_, err := state.JobByID(ws, job.Namespace, job.ID)
+	require.Nil(err)
+	_, err = state.JobSubmission(ws, job.Namespace, job.ID, 1)
+	require.Nil(err)
+
+	// Update the job along with its source
+	update := job.Copy()
+	update.Priority = 90
+	sub := &structs.JobSubmission{
+		Source: "job \"example\" {}",
+		Format: structs.JobSubmissionFormatHCL,
+	}
+	require.Nil(state.UpsertJobWithSubmission(1001, update, sub))
+	require.True(watchFired(ws))
+
+	// The source is bound to the version that was written
+	out, err := state.JobSubmission(nil, job.Namespace, job.ID, 1)
+	require.Nil(err)
+	require.NotNil(out)
+	require.Equal(sub.Source, out.Source)
+	require.Equal(job.ID, out.JobID)
+	require.EqualValues(1, out.Version)
+	require.EqualValues(1001, out.JobModifyIndex)
+
+	for _, table := range []string{"jobs", "job_submission"} {
+		index, err := state.Index(table)
+		require.Nil(err)
+		require.EqualValues(1001, index)
+	}
+
+	// Neither the job nor its source is stored if the job can not be written
+	bad := mock.Job()
+	bad.Namespace = "nonexistent"
+	require.Error(state.UpsertJobWithSubmission(1002, bad, sub))
+
+	outJob, err := state.JobByID(nil, bad.Namespace, bad.ID)
+	require.Nil(err)
+	require.Nil(outJob)
+	out, err = state.JobSubmission(nil, bad.Namespace, bad.ID, 0)
+	require.Nil(err)
+	require.Nil(out)
+}
+
 func TestStateStore_DeleteJob_ChildJob(t *testing.T) {
 	state := testStateStore(t)
 
@@ -6483,6 +6593,463 @@ func TestStateStore_ACLPolicyByNamePrefix(t *testing.T) {
 	assert.Equal(t, expect, out)
 }
 
+func TestStateStore_UpsertACLRoles(t *testing.T) {
+	state := testStateStore(t)
+	role := mock.ACLRole()
+	role2 := mock.ACLRole()
+
+	ws := memdb.NewWatchSet()
+	if _, err := state.ACLRoleByName(ws, role.Name); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if err := state.UpsertACLRoles(1000,
+		[]*structs.ACLRole{role, role2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
+
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLRoleByName(ws, role.Name)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, role, out)
+
+	out, err = state.ACLRoleByName(ws, role2.Name)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, role2, out)
+
+	iter, err := state.ACLRoles(ws)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure we see both roles
+	count := 0
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+	}
+	if count != 2 {
+		t.Fatalf("bad: %d", count)
+	}
+
+	index, err := state.Index("acl_role")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1000 {
+		t.Fatalf("bad: %d", index)
+	}
+
+	if watchFired(ws) {
+		t.Fatalf("bad")
+	}
+}
+
+func TestStateStore_DeleteACLRoles(t *testing.T) {
+	state := testStateStore(t)
+	role := mock.ACLRole()
+	role2 := mock.ACLRole()
+
+	// Create the roles
+	if err := state.UpsertACLRoles(1000,
+		[]*structs.ACLRole{role, role2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Create a watcher
+	ws := memdb.NewWatchSet()
+	if _, err := state.ACLRoleByName(ws, role.Name); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Delete the roles
+	if err := state.DeleteACLRoles(1001,
+		[]string{role.Name, role2.Name}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure watching triggered
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
+
+	// Ensure we don't get the object back
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLRoleByName(ws, role.Name)
+	assert.Equal(t, nil, err)
+	if out != nil {
+		t.Fatalf("bad: %#v", out)
+	}
+
+	index, err := state.Index("acl_role")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1001 {
+		t.Fatalf("bad: %d", index)
+	}
+}
+
+func TestStateStore_ACLRoleByNamePrefix(t *testing.T) {
+	state := testStateStore(t)
+	names := []string{
+		"foo",
+		"bar",
+		"foobar",
+		"foozip",
+		"zip",
+	}
+
+	// Create the roles
+	var baseIndex uint64 = 1000
+	for _, name := range names {
+		r := mock.ACLRole()
+		r.Name = name
+		if err := state.UpsertACLRoles(baseIndex, []*structs.ACLRole{r}); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		baseIndex++
+	}
+
+	// Scan by prefix
+	iter, err := state.ACLRoleByNamePrefix(nil, "foo")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	out := []string{}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		out = append(out, raw.(*structs.ACLRole).Name)
+	}
+	sort.Strings(out)
+
+	expect := []string{"foo", "foobar", "foozip"}
+	assert.Equal(t, expect, out)
+}
+
+func TestStateStore_ACLTokenPolicies(t *testing.T) {
+	state := testStateStore(t)
+	role := mock.ACLRole()
+	role.Policies = []string{"foo", "bar"}
+	if err := state.UpsertACLRoles(1000, []*structs.ACLRole{role}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Policies of the roles are merged with the ones of the token, missing
+	// roles are ignored
+	token := mock.ACLToken()
+	token.Policies = []string{"bar", "baz"}
+	token.Roles = []string{role.Name, "missing"}
+	out, err := state.ACLTokenPolicies(nil, token)
+	assert.Nil(t, err)
+	sort.Strings(out)
+	assert.Equal(t, []string{"bar", "baz", "foo"}, out)
+}
+
+func TestStateStore_RestoreACLRole(t *testing.T) {
+	state := testStateStore(t)
+	role := mock.ACLRole()
+
+	restore, err := state.Restore()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	err = restore.ACLRoleRestore(role)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	restore.Commit()
+
+	ws := memdb.NewWatchSet()
+	out, err := state.ACLRoleByName(ws, role.Name)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, role, out)
+}
+
+func TestStateStore_UpsertACLAuthMethods(t *testing.T) {
+	state := testStateStore(t)
+	method := mock.ACLAuthMethod()
+	method2 := mock.ACLAuthMethod()
+	method2.Default = true
+
+	ws := memdb.NewWatchSet()
+	if _, err := state.ACLAuthMethodByName(ws, method.Name); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if err := state.UpsertACLAuthMethods(1000,
+		[]*structs.ACLAuthMethod{method, method2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
+
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLAuthMethodByName(ws, method.Name)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, method, out)
+
+	out, err = state.ACLDefaultAuthMethod(ws)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, method2, out)
+
+	iter, err := state.ACLAuthMethods(ws)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure we see both auth methods
+	count := 0
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+	}
+	if count != 2 {
+		t.Fatalf("bad: %d", count)
+	}
+
+	index, err := state.Index("acl_auth_method")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1000 {
+		t.Fatalf("bad: %d", index)
+	}
+
+	if watchFired(ws) {
+		t.Fatalf("bad")
+	}
+}
+
+func TestStateStore_DeleteACLAuthMethods(t *testing.T) {
+	state := testStateStore(t)
+	method := mock.ACLAuthMethod()
+	method2 := mock.ACLAuthMethod()
+
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	rule2 := mock.ACLBindingRule()
+	rule2.AuthMethod = method2.Name
+
+	// Create the auth methods and binding rules
+	if err := state.UpsertACLAuthMethods(1000,
+		[]*structs.ACLAuthMethod{method, method2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := state.UpsertACLBindingRules(1001,
+		[]*structs.ACLBindingRule{rule, rule2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Create a watcher
+	ws := memdb.NewWatchSet()
+	if _, err := state.ACLAuthMethodByName(ws, method.Name); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Delete the first auth method
+	if err := state.DeleteACLAuthMethods(1002, []string{method.Name}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure watching triggered
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
+
+	// Ensure we don't get the auth method or its binding rule back
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLAuthMethodByName(ws, method.Name)
+	assert.Equal(t, nil, err)
+	assert.Nil(t, out)
+
+	outRule, err := state.ACLBindingRuleByID(ws, rule.ID)
+	assert.Equal(t, nil, err)
+	assert.Nil(t, outRule)
+
+	// The other binding rule is untouched
+	outRule, err = state.ACLBindingRuleByID(ws, rule2.ID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, rule2, outRule)
+
+	for _, table := range []string{"acl_auth_method", "acl_binding_rule"} {
+		index, err := state.Index(table)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if index != 1002 {
+			t.Fatalf("bad: %s %d", table, index)
+		}
+	}
+}
+
+func TestStateStore_RestoreACLAuthMethod(t *testing.T) {
+	state := testStateStore(t)
+	method := mock.ACLAuthMethod()
+
+	restore, err := state.Restore()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	err = restore.ACLAuthMethodRestore(method)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	restore.Commit()
+
+	ws := memdb.NewWatchSet()
+	out, err := state.ACLAuthMethodByName(ws, method.Name)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, method, out)
+}
+
+func TestStateStore_UpsertACLBindingRules(t *testing.T) {
+	state := testStateStore(t)
+	rule := mock.ACLBindingRule()
+	rule2 := mock.ACLBindingRule()
+	rule2.AuthMethod = "other"
+
+	ws := memdb.NewWatchSet()
+	if _, err := state.ACLBindingRuleByID(ws, rule.ID); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if err := state.UpsertACLBindingRules(1000,
+		[]*structs.ACLBindingRule{rule, rule2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
+
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLBindingRuleByID(ws, rule.ID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, rule, out)
+
+	// Lookup by auth method
+	iter, err := state.ACLBindingRulesByAuthMethod(ws, rule.AuthMethod)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	var found []*structs.ACLBindingRule
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		found = append(found, raw.(*structs.ACLBindingRule))
+	}
+	assert.Equal(t, []*structs.ACLBindingRule{rule}, found)
+
+	// Lookup by prefix
+	iter, err = state.ACLBindingRuleByIDPrefix(ws, rule2.ID[:4])
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	found = nil
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		found = append(found, raw.(*structs.ACLBindingRule))
+	}
+	assert.Contains(t, found, rule2)
+
+	index, err := state.Index("acl_binding_rule")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1000 {
+		t.Fatalf("bad: %d", index)
+	}
+
+	if watchFired(ws) {
+		t.Fatalf("bad")
+	}
+}
+
+func TestStateStore_DeleteACLBindingRules(t *testing.T) {
+	state := testStateStore(t)
+	rule := mock.ACLBindingRule()
+	rule2 := mock.ACLBindingRule()
+
+	// Create the binding rules
+	if err := state.UpsertACLBindingRules(1000,
+		[]*structs.ACLBindingRule{rule, rule2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Create a watcher
+	ws := memdb.NewWatchSet()
+	if _, err := state.ACLBindingRuleByID(ws, rule.ID); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Delete the binding rules
+	if err := state.DeleteACLBindingRules(1001,
+		[]string{rule.ID, rule2.ID}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure watching triggered
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
+
+	// Ensure we don't get the object back
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLBindingRuleByID(ws, rule.ID)
+	assert.Equal(t, nil, err)
+	if out != nil {
+		t.Fatalf("bad: %#v", out)
+	}
+
+	index, err := state.Index("acl_binding_rule")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1001 {
+		t.Fatalf("bad: %d", index)
+	}
+}
+
+func TestStateStore_RestoreACLBindingRule(t *testing.T) {
+	state := testStateStore(t)
+	rule := mock.ACLBindingRule()
+
+	restore, err := state.Restore()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	err = restore.ACLBindingRuleRestore(rule)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	restore.Commit()
+
+	ws := memdb.NewWatchSet()
+	out, err := state.ACLBindingRuleByID(ws, rule.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, rule, out)
+}
+
 func TestStateStore_BootstrapACLTokens(t *testing.T) {
 	state := testStateStore(t)
 	tk1 := mock.ACLToken()
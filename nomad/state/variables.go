@@ -0,0 +1,218 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// variablesTableSchema returns the MemDB schema for the variables table.
+// This table stores the encrypted variables keyed by namespace and path.
+func variablesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "variables",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, Path) is
+				// uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field: "Path",
+						},
+					},
+				},
+			},
+
+			"key_id": {
+				Name:         "key_id",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "KeyID",
+				},
+			},
+		},
+	}
+}
+
+// VarSet is used to apply a variable write. Check-and-set operations that do
+// not match the current modify index are not applied and return the conflicting
+// variable. The modify time must be set by the caller.
+func (s *StateStore) VarSet(index uint64, req *structs.VarApplyStateRequest) (*structs.VarApplyStateResponse, error) {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	resp := &structs.VarApplyStateResponse{Op: req.Op}
+	sv := req.Var.Copy()
+
+	// Check if the variable already exists
+	raw, err := txn.First("variables", "id", sv.Namespace, sv.Path)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+
+	var existing *structs.VariableEncrypted
+	if raw != nil {
+		existing = raw.(*structs.VariableEncrypted)
+	}
+
+	// Enforce the check index if required
+	if req.Op == structs.VarOpCAS {
+		switch {
+		case existing == nil && sv.ModifyIndex != 0:
+			// The variable was expected to exist
+			resp.Result = structs.VarOpResultConflict
+			resp.Conflict = &structs.VariableEncrypted{}
+			return resp, nil
+		case existing != nil && existing.ModifyIndex != sv.ModifyIndex:
+			resp.Result = structs.VarOpResultConflict
+			resp.Conflict = existing.Copy()
+			return resp, nil
+		}
+	}
+
+	// Update all the indexes
+	if existing != nil {
+		sv.CreateIndex = existing.CreateIndex
+		sv.CreateTime = existing.CreateTime
+	} else {
+		sv.CreateIndex = index
+		sv.CreateTime = sv.ModifyTime
+	}
+	sv.ModifyIndex = index
+
+	if err := txn.Insert("variables", sv); err != nil {
+		return nil, fmt.Errorf("upserting variable failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"variables", index}); err != nil {
+		return nil, fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+
+	resp.Result = structs.VarOpResultOk
+	resp.Output = sv
+	resp.Index = index
+	return resp, nil
+}
+
+// VarDelete is used to delete a variable. Check-and-set deletes that do not
+// match the current modify index are not applied and return the conflicting
+// variable.
+func (s *StateStore) VarDelete(index uint64, req *structs.VarApplyStateRequest) (*structs.VarApplyStateResponse, error) {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	resp := &structs.VarApplyStateResponse{Op: req.Op}
+	sv := req.Var
+
+	raw, err := txn.First("variables", "id", sv.Namespace, sv.Path)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+
+	if req.Op == structs.VarOpDeleteCAS {
+		switch {
+		case raw == nil && sv.ModifyIndex != 0:
+			resp.Result = structs.VarOpResultConflict
+			resp.Conflict = &structs.VariableEncrypted{}
+			return resp, nil
+		case raw != nil && raw.(*structs.VariableEncrypted).ModifyIndex != sv.ModifyIndex:
+			resp.Result = structs.VarOpResultConflict
+			resp.Conflict = raw.(*structs.VariableEncrypted).Copy()
+			return resp, nil
+		}
+	}
+
+	// Deleting a variable that doesn't exist is a no-op
+	if raw != nil {
+		if err := txn.Delete("variables", raw); err != nil {
+			return nil, fmt.Errorf("deleting variable failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"variables", index}); err != nil {
+		return nil, fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+
+	resp.Result = structs.VarOpResultOk
+	resp.Index = index
+	return resp, nil
+}
+
+// VarGet is used to lookup a variable by namespace and path
+func (s *StateStore) VarGet(ws memdb.WatchSet, namespace, path string) (*structs.VariableEncrypted, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("variables", "id", namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.VariableEncrypted), nil
+	}
+	return nil, nil
+}
+
+// VarsByNamespace returns an iterator over all the variables of a namespace
+func (s *StateStore) VarsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	return s.VarsByNamespaceAndPrefix(ws, namespace, "")
+}
+
+// VarsByNamespaceAndPrefix returns an iterator over the variables of a
+// namespace whose path starts with the given prefix
+func (s *StateStore) VarsByNamespaceAndPrefix(ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("variables", "id_prefix", namespace, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// Variables returns an iterator over all the variables
+func (s *StateStore) Variables(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("variables", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// VariablesByKeyID returns an iterator over all the variables encrypted with
+// the given root key
+func (s *StateStore) VariablesByKeyID(ws memdb.WatchSet, keyID string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("variables", "key_id", keyID)
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// VariableRestore is used to restore a variable
+func (r *StateRestore) VariableRestore(sv *structs.VariableEncrypted) error {
+	if err := r.txn.Insert("variables", sv); err != nil {
+		return fmt.Errorf("inserting variable failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package state
+
+import (
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testVariable(path string) *structs.VariableEncrypted {
+	return &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace:  structs.DefaultNamespace,
+			Path:       path,
+			ModifyTime: 100,
+		},
+		VariableData: structs.VariableData{
+			Data:  []byte("encrypted"),
+			KeyID: "key1",
+		},
+	}
+}
+
+func TestStateStore_VarSet_Get(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	ws := memdb.NewWatchSet()
+	out, err := state.VarGet(ws, structs.DefaultNamespace, "app/db")
+	require.NoError(err)
+	require.Nil(out)
+
+	sv := testVariable("app/db")
+	resp, err := state.VarSet(1000, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: sv})
+	require.NoError(err)
+	require.True(resp.IsOk())
+	require.True(watchFired(ws))
+
+	out, err = state.VarGet(nil, structs.DefaultNamespace, "app/db")
+	require.NoError(err)
+	require.Equal(uint64(1000), out.CreateIndex)
+	require.Equal(uint64(1000), out.ModifyIndex)
+	require.Equal(int64(100), out.CreateTime)
+
+	// Updating preserves the create index and time
+	sv = testVariable("app/db")
+	sv.ModifyTime = 200
+	_, err = state.VarSet(1001, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: sv})
+	require.NoError(err)
+
+	out, err = state.VarGet(nil, structs.DefaultNamespace, "app/db")
+	require.NoError(err)
+	require.Equal(uint64(1000), out.CreateIndex)
+	require.Equal(uint64(1001), out.ModifyIndex)
+	require.Equal(int64(100), out.CreateTime)
+	require.Equal(int64(200), out.ModifyTime)
+
+	index, err := state.Index("variables")
+	require.NoError(err)
+	require.Equal(uint64(1001), index)
+}
+
+func TestStateStore_VarSet_CAS(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	// A check index of zero requires the variable to not exist
+	sv := testVariable("app/db")
+	resp, err := state.VarSet(1000, &structs.VarApplyStateRequest{Op: structs.VarOpCAS, Var: sv})
+	require.NoError(err)
+	require.True(resp.IsOk())
+
+	resp, err = state.VarSet(1001, &structs.VarApplyStateRequest{Op: structs.VarOpCAS, Var: sv})
+	require.NoError(err)
+	require.True(resp.IsConflict())
+	require.Equal(uint64(1000), resp.Conflict.ModifyIndex)
+
+	// A matching check index is applied
+	sv.ModifyIndex = 1000
+	resp, err = state.VarSet(1002, &structs.VarApplyStateRequest{Op: structs.VarOpCAS, Var: sv})
+	require.NoError(err)
+	require.True(resp.IsOk())
+
+	// A stale delete is not applied
+	del := testVariable("app/db")
+	del.ModifyIndex = 1000
+	resp, err = state.VarDelete(1003, &structs.VarApplyStateRequest{Op: structs.VarOpDeleteCAS, Var: del})
+	require.NoError(err)
+	require.True(resp.IsConflict())
+
+	del.ModifyIndex = 1002
+	resp, err = state.VarDelete(1004, &structs.VarApplyStateRequest{Op: structs.VarOpDeleteCAS, Var: del})
+	require.NoError(err)
+	require.True(resp.IsOk())
+
+	out, err := state.VarGet(nil, structs.DefaultNamespace, "app/db")
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestStateStore_VarsByNamespaceAndPrefix(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	for i, path := range []string{"app/db", "app/cache", "nomad/jobs/example"} {
+		_, err := state.VarSet(uint64(1000+i), &structs.VarApplyStateRequest{
+			Op:  structs.VarOpSet,
+			Var: testVariable(path),
+		})
+		require.NoError(err)
+	}
+
+	count := func(iter memdb.ResultIterator) int {
+		var n int
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			n++
+		}
+		return n
+	}
+
+	iter, err := state.VarsByNamespaceAndPrefix(nil, structs.DefaultNamespace, "app")
+	require.NoError(err)
+	require.Equal(2, count(iter))
+
+	iter, err = state.VarsByNamespace(nil, structs.DefaultNamespace)
+	require.NoError(err)
+	require.Equal(3, count(iter))
+
+	iter, err = state.VarsByNamespace(nil, "other")
+	require.NoError(err)
+	require.Zero(count(iter))
+
+	iter, err = state.VariablesByKeyID(nil, "key1")
+	require.NoError(err)
+	require.Equal(3, count(iter))
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// AdmissionWebhookMutating is the type of a webhook that may return a
+	// modified job.
+	AdmissionWebhookMutating = "mutating"
+
+	// AdmissionWebhookValidating is the type of a webhook that may only
+	// accept or reject a job.
+	AdmissionWebhookValidating = "validating"
+
+	// AdmissionFailurePolicyFail rejects the job if the webhook can not be
+	// reached or returns an invalid response.
+	AdmissionFailurePolicyFail = "fail"
+
+	// AdmissionFailurePolicyIgnore admits the job with a warning if the
+	// webhook can not be reached or returns an invalid response.
+	AdmissionFailurePolicyIgnore = "ignore"
+
+	// DefaultAdmissionWebhookTimeout is the default time to wait for a
+	// webhook to respond.
+	DefaultAdmissionWebhookTimeout = 10 * time.Second
+)
+
+// AdmissionWebhookConfig is the configuration of an external admission
+// controller that is called when jobs are registered or planned.
+type AdmissionWebhookConfig struct {
+	// Name is used to identify the webhook in errors and warnings
+	Name string `mapstructure:"-"`
+
+	// Type is either "mutating" or "validating"
+	Type string `mapstructure:"type"`
+
+	// URL is the address the job is POSTed to
+	URL string `mapstructure:"url"`
+
+	// Timeout is the time to wait for the webhook to respond
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// FailurePolicy is either "fail" or "ignore" and determines whether a
+	// job is admitted when the webhook can not be called.
+	FailurePolicy string `mapstructure:"failure_policy"`
+
+	// Headers are added to each request sent to the webhook
+	Headers map[string]string `mapstructure:"headers"`
+}
+
+// Copy returns a copy of the webhook config
+func (a *AdmissionWebhookConfig) Copy() *AdmissionWebhookConfig {
+	if a == nil {
+		return nil
+	}
+
+	nc := *a
+	if a.Headers != nil {
+		nc.Headers = make(map[string]string, len(a.Headers))
+		for k, v := range a.Headers {
+			nc.Headers[k] = v
+		}
+	}
+	return &nc
+}
+
+// Canonicalize sets the defaults of unset fields
+func (a *AdmissionWebhookConfig) Canonicalize() {
+	if a.Timeout == 0 {
+		a.Timeout = DefaultAdmissionWebhookTimeout
+	}
+	if a.FailurePolicy == "" {
+		a.FailurePolicy = AdmissionFailurePolicyFail
+	}
+}
+
+// Validate returns an error if the webhook config is invalid
+func (a *AdmissionWebhookConfig) Validate() error {
+	switch a.Type {
+	case AdmissionWebhookMutating, AdmissionWebhookValidating:
+	default:
+		return fmt.Errorf("invalid type %q, must be %q or %q",
+			a.Type, AdmissionWebhookMutating, AdmissionWebhookValidating)
+	}
+
+	u, err := url.Parse(a.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid url %q, must be http or https", a.URL)
+	}
+
+	switch a.FailurePolicy {
+	case "", AdmissionFailurePolicyFail, AdmissionFailurePolicyIgnore:
+	default:
+		return fmt.Errorf("invalid failure_policy %q, must be %q or %q",
+			a.FailurePolicy, AdmissionFailurePolicyFail, AdmissionFailurePolicyIgnore)
+	}
+
+	if a.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	return nil
+}
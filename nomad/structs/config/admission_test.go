@@ -0,0 +1,79 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmissionWebhookConfig_Validate(t *testing.T) {
+	cases := []struct {
+		Name string
+		Conf *AdmissionWebhookConfig
+		Err  string
+	}{
+		{
+			Name: "valid",
+			Conf: &AdmissionWebhookConfig{
+				Type: AdmissionWebhookMutating,
+				URL:  "https://admission.example.com/mutate",
+			},
+		},
+		{
+			Name: "bad type",
+			Conf: &AdmissionWebhookConfig{
+				Type: "rewrite",
+				URL:  "https://admission.example.com/mutate",
+			},
+			Err: "invalid type",
+		},
+		{
+			Name: "bad scheme",
+			Conf: &AdmissionWebhookConfig{
+				Type: AdmissionWebhookValidating,
+				URL:  "ftp://admission.example.com",
+			},
+			Err: "must be http or https",
+		},
+		{
+			Name: "bad failure policy",
+			Conf: &AdmissionWebhookConfig{
+				Type:          AdmissionWebhookValidating,
+				URL:           "http://127.0.0.1:8080",
+				FailurePolicy: "retry",
+			},
+			Err: "invalid failure_policy",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			err := c.Conf.Validate()
+			if c.Err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.Err)
+			}
+		})
+	}
+}
+
+func TestAdmissionWebhookConfig_Canonicalize(t *testing.T) {
+	c := &AdmissionWebhookConfig{
+		Type:    AdmissionWebhookValidating,
+		URL:     "http://127.0.0.1:8080",
+		Headers: map[string]string{"Authorization": "Bearer foo"},
+	}
+
+	out := c.Copy()
+	out.Canonicalize()
+	require.Equal(t, DefaultAdmissionWebhookTimeout, out.Timeout)
+	require.Equal(t, AdmissionFailurePolicyFail, out.FailurePolicy)
+
+	// The copy must not share the headers
+	out.Headers["Authorization"] = "Bearer bar"
+	require.Equal(t, "Bearer foo", c.Headers["Authorization"])
+	require.Equal(t, time.Duration(0), c.Timeout)
+}
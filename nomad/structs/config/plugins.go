@@ -0,0 +1,77 @@
+package config
+
+import "github.com/mitchellh/copystructure"
+
+// PluginConfig is the configuration of a plugin given by a plugin block of
+// the agent configuration.
+type PluginConfig struct {
+	// Name is the name of the plugin binary in the plugin directory
+	Name string
+
+	// Args are the arguments the plugin binary is launched with
+	Args []string `mapstructure:"args"`
+
+	// Config is the configuration passed to the plugin. It is validated
+	// against the configuration schema of the plugin.
+	Config map[string]interface{} `mapstructure:"config"`
+}
+
+// Copy returns a deep copy of the plugin config
+func (p *PluginConfig) Copy() *PluginConfig {
+	if p == nil {
+		return nil
+	}
+
+	np := *p
+	if p.Args != nil {
+		np.Args = make([]string, len(p.Args))
+		copy(np.Args, p.Args)
+	}
+	if p.Config != nil {
+		if c, err := copystructure.Copy(p.Config); err == nil {
+			np.Config = c.(map[string]interface{})
+		}
+	}
+	return &np
+}
+
+// Merge merges two plugin configs, with the values of o taking precedence
+// when set.
+func (p *PluginConfig) Merge(o *PluginConfig) *PluginConfig {
+	result := p.Copy()
+	other := o.Copy()
+
+	if other.Name != "" {
+		result.Name = other.Name
+	}
+	if len(other.Args) != 0 {
+		result.Args = other.Args
+	}
+	if len(other.Config) != 0 {
+		result.Config = other.Config
+	}
+	return result
+}
+
+// PluginConfigSetMerge merges two sets of plugin configs. Configs of the
+// same plugin are merged, with the values of the second set taking
+// precedence.
+func PluginConfigSetMerge(first, second []*PluginConfig) []*PluginConfig {
+	var result []*PluginConfig
+	index := make(map[string]int, len(first))
+	for _, p := range first {
+		index[p.Name] = len(result)
+		result = append(result, p.Copy())
+	}
+
+	for _, p := range second {
+		if i, ok := index[p.Name]; ok {
+			result[i] = result[i].Merge(p)
+			continue
+		}
+		index[p.Name] = len(result)
+		result = append(result, p.Copy())
+	}
+
+	return result
+}
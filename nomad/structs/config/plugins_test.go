@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginConfigSetMerge(t *testing.T) {
+	first := []*PluginConfig{
+		{
+			Name: "docker",
+			Args: []string{"foo"},
+			Config: map[string]interface{}{
+				"foo": "bar",
+			},
+		},
+		{
+			Name: "exec",
+			Args: []string{"a"},
+		},
+	}
+	second := []*PluginConfig{
+		{
+			Name: "docker",
+			Config: map[string]interface{}{
+				"foo": "baz",
+			},
+		},
+		{
+			Name: "java",
+			Args: []string{"b"},
+		},
+	}
+
+	out := PluginConfigSetMerge(first, second)
+	require.Equal(t, []*PluginConfig{
+		{
+			Name: "docker",
+			Args: []string{"foo"},
+			Config: map[string]interface{}{
+				"foo": "baz",
+			},
+		},
+		{
+			Name: "exec",
+			Args: []string{"a"},
+		},
+		{
+			Name: "java",
+			Args: []string{"b"},
+		},
+	}, out)
+
+	// The inputs must not be modified
+	require.Equal(t, "bar", first[0].Config["foo"])
+}
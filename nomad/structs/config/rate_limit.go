@@ -0,0 +1,80 @@
+package config
+
+import "fmt"
+
+// RPCRateLimitConfig is the configuration of the rate limits servers apply
+// to the RPCs of each ACL token. A rate of zero disables the limit.
+type RPCRateLimitConfig struct {
+	// ReadRate is the number of read RPCs per second allowed for each token
+	ReadRate float64 `mapstructure:"read_rate"`
+
+	// ReadBurst is the number of read RPCs a token may make at once. It
+	// defaults to the read rate.
+	ReadBurst int `mapstructure:"read_burst"`
+
+	// WriteRate is the number of write RPCs per second allowed for each token
+	WriteRate float64 `mapstructure:"write_rate"`
+
+	// WriteBurst is the number of write RPCs a token may make at once. It
+	// defaults to the write rate.
+	WriteBurst int `mapstructure:"write_burst"`
+}
+
+// Copy returns a copy of the rate limit config
+func (r *RPCRateLimitConfig) Copy() *RPCRateLimitConfig {
+	if r == nil {
+		return nil
+	}
+
+	nr := *r
+	return &nr
+}
+
+// Merge merges two rate limit configs, with the values of b taking
+// precedence when set.
+func (r *RPCRateLimitConfig) Merge(b *RPCRateLimitConfig) *RPCRateLimitConfig {
+	result := *r
+
+	if b.ReadRate != 0 {
+		result.ReadRate = b.ReadRate
+	}
+	if b.ReadBurst != 0 {
+		result.ReadBurst = b.ReadBurst
+	}
+	if b.WriteRate != 0 {
+		result.WriteRate = b.WriteRate
+	}
+	if b.WriteBurst != 0 {
+		result.WriteBurst = b.WriteBurst
+	}
+	return &result
+}
+
+// Canonicalize sets the defaults of unset fields
+func (r *RPCRateLimitConfig) Canonicalize() {
+	r.ReadBurst = defaultBurst(r.ReadRate, r.ReadBurst)
+	r.WriteBurst = defaultBurst(r.WriteRate, r.WriteBurst)
+}
+
+// defaultBurst returns the burst to use for the given rate, allowing at
+// least one request at once when the rate is limited.
+func defaultBurst(rate float64, burst int) int {
+	if rate <= 0 || burst != 0 {
+		return burst
+	}
+	if rate < 1 {
+		return 1
+	}
+	return int(rate)
+}
+
+// Validate returns an error if the rate limit config is invalid
+func (r *RPCRateLimitConfig) Validate() error {
+	if r.ReadRate < 0 || r.WriteRate < 0 {
+		return fmt.Errorf("rates must not be negative")
+	}
+	if r.ReadBurst < 0 || r.WriteBurst < 0 {
+		return fmt.Errorf("bursts must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPCRateLimitConfig_Merge(t *testing.T) {
+	a := &RPCRateLimitConfig{
+		ReadRate:  100,
+		WriteRate: 10,
+	}
+	b := &RPCRateLimitConfig{
+		WriteRate:  20,
+		WriteBurst: 40,
+	}
+
+	out := a.Merge(b)
+	require.Equal(t, &RPCRateLimitConfig{
+		ReadRate:   100,
+		WriteRate:  20,
+		WriteBurst: 40,
+	}, out)
+
+	// The inputs must not be modified
+	require.Equal(t, float64(10), a.WriteRate)
+}
+
+func TestRPCRateLimitConfig_Canonicalize(t *testing.T) {
+	c := &RPCRateLimitConfig{
+		ReadRate:   50.5,
+		WriteRate:  0.5,
+		WriteBurst: 0,
+	}
+	c.Canonicalize()
+	require.Equal(t, 50, c.ReadBurst)
+	require.Equal(t, 1, c.WriteBurst)
+
+	// Unlimited rates have no burst
+	c = &RPCRateLimitConfig{}
+	c.Canonicalize()
+	require.Zero(t, c.ReadBurst)
+	require.Zero(t, c.WriteBurst)
+
+	// Explicit bursts are kept
+	c = &RPCRateLimitConfig{ReadRate: 10, ReadBurst: 100}
+	c.Canonicalize()
+	require.Equal(t, 100, c.ReadBurst)
+}
+
+func TestRPCRateLimitConfig_Validate(t *testing.T) {
+	require.NoError(t, (&RPCRateLimitConfig{ReadRate: 10}).Validate())
+	require.Error(t, (&RPCRateLimitConfig{WriteRate: -1}).Validate())
+	require.Error(t, (&RPCRateLimitConfig{ReadBurst: -1}).Validate())
+}
@@ -0,0 +1,704 @@
+package structs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+// CSISocketName is the filename that Nomad expects plugins to create inside the
+// PluginMountDir.
+const CSISocketName = "csi.sock"
+
+// CSIPluginDefaultMountDir is the directory in the plugin task where the
+// plugin directory is mounted when the job doesn't specify one.
+const CSIPluginDefaultMountDir = "/csi"
+
+// CSIPluginType is an enum string that encapsulates the valid options for a
+// CSIPlugin stanza's Type. These modes will allow the plugin to be used in
+// different ways by the client.
+type CSIPluginType string
+
+const (
+	// CSIPluginTypeNode indicates that Nomad should only use the plugin for
+	// performing Node RPCs against the provided plugin.
+	CSIPluginTypeNode CSIPluginType = "node"
+
+	// CSIPluginTypeController indicates that Nomad should only use the plugin
+	// for performing Controller RPCs against the provided plugin.
+	CSIPluginTypeController CSIPluginType = "controller"
+
+	// CSIPluginTypeMonolith indicates that Nomad can use the provided plugin
+	// for both controller and node rpcs.
+	CSIPluginTypeMonolith CSIPluginType = "monolith"
+)
+
+// CSIPluginTypeIsValid validates the given CSIPluginType string and returns
+// true only when a correct plugin type is specified.
+func CSIPluginTypeIsValid(pt CSIPluginType) bool {
+	switch pt {
+	case CSIPluginTypeNode, CSIPluginTypeController, CSIPluginTypeMonolith:
+		return true
+	default:
+		return false
+	}
+}
+
+// TaskCSIPluginConfig contains the data that is required to setup a task as a
+// CSI plugin. The client mounts the plugin directory into the task and watches
+// the plugin's socket to fingerprint it.
+type TaskCSIPluginConfig struct {
+	// ID is the identifier of the plugin.
+	// Ideally this should be the FQDN of the plugin.
+	ID string
+
+	// Type instructs Nomad on how to handle processing a plugin
+	Type CSIPluginType
+
+	// MountDir is the destination that nomad should mount in its CSI
+	// directory for the plugin. It will then expect a file called CSISocketName
+	// to be created by the plugin, and will stage and publish volumes below
+	// it.
+	MountDir string
+}
+
+func (t *TaskCSIPluginConfig) Copy() *TaskCSIPluginConfig {
+	if t == nil {
+		return nil
+	}
+
+	nt := new(TaskCSIPluginConfig)
+	*nt = *t
+	return nt
+}
+
+// Validate is used to sanity check the plugin configuration of a task
+func (t *TaskCSIPluginConfig) Validate() error {
+	var mErr multierror.Error
+	if t.ID == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("CSI plugin must have an ID"))
+	}
+	if !CSIPluginTypeIsValid(t.Type) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("CSI plugin has invalid type %q", t.Type))
+	}
+	if t.MountDir == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("CSI plugin must have a mount directory"))
+	} else if !filepath.IsAbs(t.MountDir) || filepath.Clean(t.MountDir) == "/" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("CSI plugin mount directory %q must be an absolute path below the task's root", t.MountDir))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// CSIVolumeAccessMode indicates how a volume can be claimed by allocations
+type CSIVolumeAccessMode string
+
+const (
+	CSIVolumeAccessModeUnknown CSIVolumeAccessMode = ""
+
+	CSIVolumeAccessModeSingleNodeReader CSIVolumeAccessMode = "single-node-reader-only"
+	CSIVolumeAccessModeSingleNodeWriter CSIVolumeAccessMode = "single-node-writer"
+
+	CSIVolumeAccessModeMultiNodeReader       CSIVolumeAccessMode = "multi-node-reader-only"
+	CSIVolumeAccessModeMultiNodeSingleWriter CSIVolumeAccessMode = "multi-node-single-writer"
+	CSIVolumeAccessModeMultiNodeMultiWriter  CSIVolumeAccessMode = "multi-node-multi-writer"
+)
+
+// ValidCSIVolumeAccessMode checks to see that the provided access mode is a
+// valid, non-empty access mode.
+func ValidCSIVolumeAccessMode(accessMode CSIVolumeAccessMode) bool {
+	switch accessMode {
+	case CSIVolumeAccessModeSingleNodeReader, CSIVolumeAccessModeSingleNodeWriter,
+		CSIVolumeAccessModeMultiNodeReader, CSIVolumeAccessModeMultiNodeSingleWriter,
+		CSIVolumeAccessModeMultiNodeMultiWriter:
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidCSIVolumeWriteAccessMode checks for a writable access mode
+func ValidCSIVolumeWriteAccessMode(accessMode CSIVolumeAccessMode) bool {
+	switch accessMode {
+	case CSIVolumeAccessModeSingleNodeWriter, CSIVolumeAccessModeMultiNodeSingleWriter,
+		CSIVolumeAccessModeMultiNodeMultiWriter:
+		return true
+	default:
+		return false
+	}
+}
+
+// csiVolumeSingleNode returns whether all the claims of a volume with the given
+// access mode must be made from a single node.
+func csiVolumeSingleNode(accessMode CSIVolumeAccessMode) bool {
+	switch accessMode {
+	case CSIVolumeAccessModeSingleNodeReader, CSIVolumeAccessModeSingleNodeWriter:
+		return true
+	default:
+		return false
+	}
+}
+
+// CSIVolumeAttachmentMode chooses the type of storage api that will be used to
+// interact with the device.
+type CSIVolumeAttachmentMode string
+
+const (
+	CSIVolumeAttachmentModeUnknown     CSIVolumeAttachmentMode = ""
+	CSIVolumeAttachmentModeBlockDevice CSIVolumeAttachmentMode = "block-device"
+	CSIVolumeAttachmentModeFilesystem  CSIVolumeAttachmentMode = "file-system"
+)
+
+// ValidCSIVolumeAttachmentMode checks to see that the provided attachment mode
+// is a valid, non-empty attachment mode.
+func ValidCSIVolumeAttachmentMode(attachmentMode CSIVolumeAttachmentMode) bool {
+	switch attachmentMode {
+	case CSIVolumeAttachmentModeBlockDevice, CSIVolumeAttachmentModeFilesystem:
+		return true
+	default:
+		return false
+	}
+}
+
+// CSIVolumeClaimMode is the kind of claim an allocation makes on a volume
+type CSIVolumeClaimMode int
+
+const (
+	CSIVolumeClaimRead CSIVolumeClaimMode = iota
+	CSIVolumeClaimWrite
+	CSIVolumeClaimRelease
+)
+
+func (m CSIVolumeClaimMode) String() string {
+	switch m {
+	case CSIVolumeClaimRead:
+		return "read"
+	case CSIVolumeClaimWrite:
+		return "write"
+	case CSIVolumeClaimRelease:
+		return "release"
+	default:
+		return "unknown"
+	}
+}
+
+// CSIVolumeClaim is the claim of an allocation on a volume
+type CSIVolumeClaim struct {
+	AllocationID string
+	NodeID       string
+	Mode         CSIVolumeClaimMode
+}
+
+func (c *CSIVolumeClaim) Copy() *CSIVolumeClaim {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(CSIVolumeClaim)
+	*nc = *c
+	return nc
+}
+
+// CSIMountOptions contain optional additional configuration that can be used
+// when specifying that a Volume should be used with VolumeAccessTypeMount.
+type CSIMountOptions struct {
+	// FSType is an optional field that allows an operator to specify the type
+	// of the filesystem.
+	FSType string
+
+	// MountFlags contains additional options that may be used when mounting the
+	// volume by the plugin. This may contain sensitive data and should not be
+	// leaked.
+	MountFlags []string
+}
+
+func (o *CSIMountOptions) Copy() *CSIMountOptions {
+	if o == nil {
+		return nil
+	}
+
+	no := new(CSIMountOptions)
+	*no = *o
+	no.MountFlags = helper.CopySliceString(o.MountFlags)
+	return no
+}
+
+// CSIVolume is a volume provided by a CSI plugin that has been registered
+// with Nomad. Allocations claim the volume to mount it.
+type CSIVolume struct {
+	// ID is a namespace unique URL safe identifier for the volume
+	ID string
+
+	// Name is a display name for the volume, not required to be unique
+	Name string
+
+	// ExternalID identifies the volume for the CSI interface, may be URL unsafe
+	ExternalID string
+
+	Namespace      string
+	AccessMode     CSIVolumeAccessMode
+	AttachmentMode CSIVolumeAttachmentMode
+	MountOptions   *CSIMountOptions
+
+	// Context is passed to the plugin when the volume is staged and
+	// published.
+	Context map[string]string
+
+	// Claims of allocations on the volume, by allocation ID
+	ReadClaims  map[string]*CSIVolumeClaim
+	WriteClaims map[string]*CSIVolumeClaim
+
+	// Schedulable is true if all the denormalized plugin health fields are
+	// true, and we are not in an error state.
+	Schedulable bool
+
+	// Denormalized fields from the plugin
+	PluginID           string
+	Provider           string
+	ControllerRequired bool
+	ControllersHealthy int
+	NodesHealthy       int
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// CSIVolListStub is partial representation of a CSI Volume for inclusion in
+// lists
+type CSIVolListStub struct {
+	ID                 string
+	Namespace          string
+	Name               string
+	ExternalID         string
+	AccessMode         CSIVolumeAccessMode
+	AttachmentMode     CSIVolumeAttachmentMode
+	CurrentReaders     int
+	CurrentWriters     int
+	Schedulable        bool
+	PluginID           string
+	Provider           string
+	ControllersHealthy int
+	NodesHealthy       int
+	CreateIndex        uint64
+	ModifyIndex        uint64
+}
+
+// Stub returns a list stub of the volume
+func (v *CSIVolume) Stub() *CSIVolListStub {
+	return &CSIVolListStub{
+		ID:                 v.ID,
+		Namespace:          v.Namespace,
+		Name:               v.Name,
+		ExternalID:         v.ExternalID,
+		AccessMode:         v.AccessMode,
+		AttachmentMode:     v.AttachmentMode,
+		CurrentReaders:     len(v.ReadClaims),
+		CurrentWriters:     len(v.WriteClaims),
+		Schedulable:        v.Schedulable,
+		PluginID:           v.PluginID,
+		Provider:           v.Provider,
+		ControllersHealthy: v.ControllersHealthy,
+		NodesHealthy:       v.NodesHealthy,
+		CreateIndex:        v.CreateIndex,
+		ModifyIndex:        v.ModifyIndex,
+	}
+}
+
+func (v *CSIVolume) Copy() *CSIVolume {
+	if v == nil {
+		return nil
+	}
+
+	nv := new(CSIVolume)
+	*nv = *v
+	nv.MountOptions = v.MountOptions.Copy()
+	nv.Context = helper.CopyMapStringString(v.Context)
+	nv.ReadClaims = copyCSIVolumeClaims(v.ReadClaims)
+	nv.WriteClaims = copyCSIVolumeClaims(v.WriteClaims)
+	return nv
+}
+
+func copyCSIVolumeClaims(claims map[string]*CSIVolumeClaim) map[string]*CSIVolumeClaim {
+	if claims == nil {
+		return nil
+	}
+
+	nc := make(map[string]*CSIVolumeClaim, len(claims))
+	for k, v := range claims {
+		nc[k] = v.Copy()
+	}
+	return nc
+}
+
+// Validate is used to sanity check a volume before it is registered
+func (v *CSIVolume) Validate() error {
+	var mErr multierror.Error
+	if v.ID == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing volume ID"))
+	} else if strings.ContainsAny(v.ID, `/\ `) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("volume ID %q must be URL safe", v.ID))
+	}
+	if v.ExternalID == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing external ID"))
+	}
+	if v.PluginID == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing plugin ID"))
+	}
+	if v.Namespace == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing namespace"))
+	}
+	if !ValidCSIVolumeAccessMode(v.AccessMode) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid access mode %q", v.AccessMode))
+	}
+	if !ValidCSIVolumeAttachmentMode(v.AttachmentMode) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid attachment mode %q", v.AttachmentMode))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// InUse returns whether any allocation claims the volume
+func (v *CSIVolume) InUse() bool {
+	return len(v.ReadClaims) != 0 || len(v.WriteClaims) != 0
+}
+
+// ReadSchedulable determines if the volume is potentially schedulable
+// for reads, considering only the volume capabilities and plugin health
+func (v *CSIVolume) ReadSchedulable() bool {
+	return v.Schedulable
+}
+
+// WriteSchedulable determines if the volume is potentially schedulable
+// for writes, considering only volume capabilities and plugin health
+func (v *CSIVolume) WriteSchedulable() bool {
+	return v.Schedulable && ValidCSIVolumeWriteAccessMode(v.AccessMode)
+}
+
+// ClaimAllowed returns an error if the claim can't be made on the volume
+// given its access mode and the existing claims. Claims of other allocations
+// that are ignored by the skip function don't conflict with the claim.
+func (v *CSIVolume) ClaimAllowed(claim *CSIVolumeClaim, skip func(*CSIVolumeClaim) bool) error {
+	active := func(c *CSIVolumeClaim) bool {
+		return c.AllocationID != claim.AllocationID && (skip == nil || !skip(c))
+	}
+
+	if claim.Mode == CSIVolumeClaimWrite {
+		if !ValidCSIVolumeWriteAccessMode(v.AccessMode) {
+			return fmt.Errorf("volume %q is read only", v.ID)
+		}
+		if v.AccessMode != CSIVolumeAccessModeMultiNodeMultiWriter {
+			for _, c := range v.WriteClaims {
+				if active(c) {
+					return fmt.Errorf("volume %q max write claims reached", v.ID)
+				}
+			}
+		}
+	}
+
+	if csiVolumeSingleNode(v.AccessMode) {
+		for _, claims := range []map[string]*CSIVolumeClaim{v.ReadClaims, v.WriteClaims} {
+			for _, c := range claims {
+				if active(c) && c.NodeID != claim.NodeID {
+					return fmt.Errorf("volume %q is claimed on another node", v.ID)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Claim records the claim of an allocation on the volume, or releases it
+func (v *CSIVolume) Claim(claim *CSIVolumeClaim) error {
+	switch claim.Mode {
+	case CSIVolumeClaimRelease:
+		v.ClaimRelease(claim.AllocationID)
+		return nil
+	case CSIVolumeClaimRead, CSIVolumeClaimWrite:
+	default:
+		return fmt.Errorf("unknown claim mode %d", claim.Mode)
+	}
+
+	if err := v.ClaimAllowed(claim, nil); err != nil {
+		return err
+	}
+
+	// An allocation holds a single claim on the volume
+	v.ClaimRelease(claim.AllocationID)
+	if claim.Mode == CSIVolumeClaimWrite {
+		if v.WriteClaims == nil {
+			v.WriteClaims = make(map[string]*CSIVolumeClaim)
+		}
+		v.WriteClaims[claim.AllocationID] = claim
+	} else {
+		if v.ReadClaims == nil {
+			v.ReadClaims = make(map[string]*CSIVolumeClaim)
+		}
+		v.ReadClaims[claim.AllocationID] = claim
+	}
+	return nil
+}
+
+// ClaimRelease releases the claim of the allocation on the volume
+func (v *CSIVolume) ClaimRelease(allocID string) {
+	delete(v.ReadClaims, allocID)
+	delete(v.WriteClaims, allocID)
+}
+
+// CSIControllerInfo is the fingerprinted data from a CSI Plugin that is
+// specific to the Controller API.
+type CSIControllerInfo struct {
+	// SupportsAttachDetach indicates whether the controller supports
+	// attaching and detaching volumes to nodes.
+	SupportsAttachDetach bool
+}
+
+// CSINodeInfo is the fingerprinted data from a CSI Plugin that is specific to
+// the Node API.
+type CSINodeInfo struct {
+	// ID is the identity of a given nomad client as observed by the storage
+	// provider.
+	ID string
+
+	// MaxVolumes is the maximum number of volumes that can be attached to the
+	// current host via this provider. If 0 then unlimited volumes may be
+	// attached.
+	MaxVolumes int64
+
+	// RequiresNodeStageVolume indicates whether the client should Stage/Unstage
+	// volumes on this node.
+	RequiresNodeStageVolume bool
+}
+
+// CSIInfo is the current state of a single CSI Plugin. This is updated
+// regularly as plugin health changes on the node.
+type CSIInfo struct {
+	PluginID          string
+	AllocID           string
+	Healthy           bool
+	HealthDescription string
+	UpdateTime        time.Time
+
+	Provider        string
+	ProviderVersion string
+
+	// RequiresControllerPlugin is set when the plugin provides a controller
+	// that must be used to attach volumes before they are published.
+	RequiresControllerPlugin bool
+
+	// ControllerInfo is populated if the plugin provides the controller
+	// service, NodeInfo if it provides the node service.
+	ControllerInfo *CSIControllerInfo
+	NodeInfo       *CSINodeInfo
+}
+
+func (c *CSIInfo) Copy() *CSIInfo {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(CSIInfo)
+	*nc = *c
+	if c.ControllerInfo != nil {
+		ci := *c.ControllerInfo
+		nc.ControllerInfo = &ci
+	}
+	if c.NodeInfo != nil {
+		ni := *c.NodeInfo
+		nc.NodeInfo = &ni
+	}
+	return nc
+}
+
+// CopyMapStringCSIInfo returns a deep copy of the CSI plugin infos.
+func CopyMapStringCSIInfo(m map[string]*CSIInfo) map[string]*CSIInfo {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(map[string]*CSIInfo, len(m))
+	for k, v := range m {
+		nm[k] = v.Copy()
+	}
+	return nm
+}
+
+// CSIPlugin collects the fingerprints of a CSI plugin on all the nodes
+// running it.
+type CSIPlugin struct {
+	ID                 string
+	Provider           string
+	Version            string
+	ControllerRequired bool
+
+	// Map Node.IDs to fingerprint results
+	Controllers map[string]*CSIInfo
+	Nodes       map[string]*CSIInfo
+
+	ControllersHealthy int
+	NodesHealthy       int
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// NewCSIPlugin creates the plugin struct. No side-effects
+func NewCSIPlugin(id string, index uint64) *CSIPlugin {
+	return &CSIPlugin{
+		ID:          id,
+		Controllers: map[string]*CSIInfo{},
+		Nodes:       map[string]*CSIInfo{},
+		CreateIndex: index,
+		ModifyIndex: index,
+	}
+}
+
+// AddPlugin adds the plugin fingerprints of a node
+func (p *CSIPlugin) AddPlugin(nodeID string, controller, node *CSIInfo, nodeReady bool) {
+	for _, info := range []*CSIInfo{controller, node} {
+		if info == nil {
+			continue
+		}
+		if p.Provider == "" {
+			p.Provider = info.Provider
+			p.Version = info.ProviderVersion
+		}
+		p.ControllerRequired = p.ControllerRequired || info.RequiresControllerPlugin
+	}
+
+	if controller != nil {
+		p.Controllers[nodeID] = controller
+		if controller.Healthy && nodeReady {
+			p.ControllersHealthy++
+		}
+	}
+	if node != nil {
+		p.Nodes[nodeID] = node
+		if node.Healthy && nodeReady {
+			p.NodesHealthy++
+		}
+	}
+}
+
+// CSIPluginListStub is partial representation of a CSI Plugin for inclusion
+// in lists
+type CSIPluginListStub struct {
+	ID                  string
+	Provider            string
+	ControllerRequired  bool
+	ControllersHealthy  int
+	ControllersExpected int
+	NodesHealthy        int
+	NodesExpected       int
+	CreateIndex         uint64
+	ModifyIndex         uint64
+}
+
+// Stub returns a list stub of the plugin
+func (p *CSIPlugin) Stub() *CSIPluginListStub {
+	return &CSIPluginListStub{
+		ID:                  p.ID,
+		Provider:            p.Provider,
+		ControllerRequired:  p.ControllerRequired,
+		ControllersHealthy:  p.ControllersHealthy,
+		ControllersExpected: len(p.Controllers),
+		NodesHealthy:        p.NodesHealthy,
+		NodesExpected:       len(p.Nodes),
+		CreateIndex:         p.CreateIndex,
+		ModifyIndex:         p.ModifyIndex,
+	}
+}
+
+// CSIVolumeRegisterRequest is used to register volumes
+type CSIVolumeRegisterRequest struct {
+	Volumes []*CSIVolume
+	WriteRequest
+}
+
+// CSIVolumeRegisterResponse is the response to a CSIVolumeRegisterRequest
+type CSIVolumeRegisterResponse struct {
+	QueryMeta
+}
+
+// CSIVolumeDeregisterRequest is used to deregister volumes
+type CSIVolumeDeregisterRequest struct {
+	VolumeIDs []string
+	WriteRequest
+}
+
+// CSIVolumeDeregisterResponse is the response to a CSIVolumeDeregisterRequest
+type CSIVolumeDeregisterResponse struct {
+	QueryMeta
+}
+
+// CSIVolumeClaimRequest is used by clients to claim a volume for an
+// allocation, or to release the claim.
+type CSIVolumeClaimRequest struct {
+	VolumeID     string
+	AllocationID string
+	NodeID       string
+	SecretID     string
+	Claim        CSIVolumeClaimMode
+	WriteRequest
+}
+
+// CSIVolumeClaimResponse is the response to a CSIVolumeClaimRequest
+type CSIVolumeClaimResponse struct {
+	// PublishContext is returned by the controller when it attaches the
+	// volume and must be passed to the node plugin.
+	PublishContext map[string]string
+
+	// Volume is the volume that was claimed
+	Volume *CSIVolume
+
+	QueryMeta
+}
+
+// CSIVolumeListRequest is used to list volumes, optionally of a plugin
+type CSIVolumeListRequest struct {
+	PluginID string
+	QueryOptions
+}
+
+// CSIVolumeListResponse is the response to a CSIVolumeListRequest
+type CSIVolumeListResponse struct {
+	Volumes []*CSIVolListStub
+	QueryMeta
+}
+
+// CSIVolumeGetRequest is used to lookup a volume
+type CSIVolumeGetRequest struct {
+	ID string
+	QueryOptions
+}
+
+// CSIVolumeGetResponse is the response to a CSIVolumeGetRequest
+type CSIVolumeGetResponse struct {
+	Volume *CSIVolume
+	QueryMeta
+}
+
+// CSIPluginListRequest is used to list plugins
+type CSIPluginListRequest struct {
+	QueryOptions
+}
+
+// CSIPluginListResponse is the response to a CSIPluginListRequest
+type CSIPluginListResponse struct {
+	Plugins []*CSIPluginListStub
+	QueryMeta
+}
+
+// CSIPluginGetRequest is used to lookup a plugin
+type CSIPluginGetRequest struct {
+	ID string
+	QueryOptions
+}
+
+// CSIPluginGetResponse is the response to a CSIPluginGetRequest
+type CSIPluginGetResponse struct {
+	Plugin *CSIPlugin
+	QueryMeta
+}
@@ -0,0 +1,101 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSIVolumeClaim(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	vol := &CSIVolume{
+		ID:          "vol",
+		AccessMode:  CSIVolumeAccessModeSingleNodeWriter,
+		Schedulable: true,
+	}
+	require.True(vol.WriteSchedulable())
+
+	// A single writer is allowed
+	require.NoError(vol.Claim(&CSIVolumeClaim{AllocationID: "a1", NodeID: "n1", Mode: CSIVolumeClaimWrite}))
+	require.Error(vol.Claim(&CSIVolumeClaim{AllocationID: "a2", NodeID: "n1", Mode: CSIVolumeClaimWrite}))
+
+	// Readers must be on the writer's node
+	require.NoError(vol.Claim(&CSIVolumeClaim{AllocationID: "a3", NodeID: "n1", Mode: CSIVolumeClaimRead}))
+	err := vol.Claim(&CSIVolumeClaim{AllocationID: "a4", NodeID: "n2", Mode: CSIVolumeClaimRead})
+	require.Error(err)
+	require.Contains(err.Error(), "another node")
+
+	// Claiming again with the same allocation replaces the claim
+	require.NoError(vol.Claim(&CSIVolumeClaim{AllocationID: "a1", NodeID: "n1", Mode: CSIVolumeClaimWrite}))
+	require.Len(vol.WriteClaims, 1)
+
+	// Skipped claims don't conflict
+	skip := func(c *CSIVolumeClaim) bool { return c.AllocationID == "a1" }
+	require.NoError(vol.ClaimAllowed(&CSIVolumeClaim{AllocationID: "a2", NodeID: "n1", Mode: CSIVolumeClaimWrite}, skip))
+
+	// Releasing the claims frees the volume
+	require.NoError(vol.Claim(&CSIVolumeClaim{AllocationID: "a1", Mode: CSIVolumeClaimRelease}))
+	require.NoError(vol.Claim(&CSIVolumeClaim{AllocationID: "a3", Mode: CSIVolumeClaimRelease}))
+	require.False(vol.InUse())
+	require.NoError(vol.Claim(&CSIVolumeClaim{AllocationID: "a4", NodeID: "n2", Mode: CSIVolumeClaimRead}))
+}
+
+func TestCSIVolumeClaim_MultiNodeReader(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	vol := &CSIVolume{
+		ID:          "vol",
+		AccessMode:  CSIVolumeAccessModeMultiNodeReader,
+		Schedulable: true,
+	}
+	require.False(vol.WriteSchedulable())
+	require.True(vol.ReadSchedulable())
+
+	require.NoError(vol.Claim(&CSIVolumeClaim{AllocationID: "a1", NodeID: "n1", Mode: CSIVolumeClaimRead}))
+	require.NoError(vol.Claim(&CSIVolumeClaim{AllocationID: "a2", NodeID: "n2", Mode: CSIVolumeClaimRead}))
+	require.Len(vol.ReadClaims, 2)
+
+	err := vol.Claim(&CSIVolumeClaim{AllocationID: "a3", NodeID: "n1", Mode: CSIVolumeClaimWrite})
+	require.Error(err)
+	require.Contains(err.Error(), "read only")
+}
+
+func TestCSIVolume_Validate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	vol := &CSIVolume{
+		ID:             "vol",
+		ExternalID:     "ext",
+		Namespace:      DefaultNamespace,
+		PluginID:       "ebs",
+		AccessMode:     CSIVolumeAccessModeSingleNodeWriter,
+		AttachmentMode: CSIVolumeAttachmentModeFilesystem,
+	}
+	require.NoError(vol.Validate())
+
+	vol.AccessMode = "anything-goes"
+	vol.PluginID = ""
+	err := vol.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "access mode")
+	require.Contains(err.Error(), "plugin")
+}
+
+func TestTaskCSIPluginConfig_Validate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	cfg := &TaskCSIPluginConfig{ID: "ebs", Type: CSIPluginTypeNode, MountDir: "/csi"}
+	require.NoError(cfg.Validate())
+
+	cfg.Type = "sidecar"
+	require.Error(cfg.Validate())
+
+	cfg.Type = CSIPluginTypeMonolith
+	cfg.MountDir = "/"
+	require.Error(cfg.Validate())
+}
@@ -398,8 +398,7 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(t.LogConfig, other.LogConfig, nil, "LogConfig", contextual)
-	if lDiff != nil {
+	if lDiff := t.LogConfig.Diff(other.LogConfig, contextual); lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
 
@@ -409,6 +408,12 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, dDiff)
 	}
 
+	// Workload identity diff
+	iDiff := primitiveObjectDiff(t.Identity, other.Identity, nil, "Identity", contextual)
+	if iDiff != nil {
+		diff.Objects = append(diff.Objects, iDiff)
+	}
+
 	// Artifacts diff
 	diffs := primitiveObjectSetDiff(
 		interfaceSlice(t.Artifacts),
@@ -777,6 +782,45 @@ func parameterizedJobDiff(old, new *ParameterizedJobConfig, contextual bool) *Ob
 	return diff
 }
 
+// Diff returns a diff of two log configs. If contextual diff is enabled,
+// non-changed fields will still be returned.
+func (l *LogConfig) Diff(other *LogConfig, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "LogConfig"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(l, other) {
+		return nil
+	} else if l == nil {
+		l = &LogConfig{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(other, nil, true)
+	} else if other == nil {
+		other = &LogConfig{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(l, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(l, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(other, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	// Log sinks diff
+	sDiffs := primitiveObjectSetDiff(
+		interfaceSlice(l.Sinks),
+		interfaceSlice(other.Sinks),
+		nil,
+		"LogSink",
+		contextual)
+	if sDiffs != nil {
+		diff.Objects = append(diff.Objects, sDiffs...)
+	}
+
+	return diff
+}
+
 // Diff returns a diff of two resource objects. If contextual diff is enabled,
 // non-changed fields will still be returned.
 func (r *Resources) Diff(other *Resources, contextual bool) *ObjectDiff {
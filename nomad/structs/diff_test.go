@@ -2615,8 +2615,10 @@ func TestTaskDiff(t *testing.T) {
 			Old:  &Task{},
 			New: &Task{
 				LogConfig: &LogConfig{
-					MaxFiles:      1,
-					MaxFileSizeMB: 10,
+					MaxFiles:       1,
+					MaxFileSizeMB:  10,
+					RotateInterval: time.Hour,
+					Compress:       true,
 				},
 			},
 			Expected: &TaskDiff{
@@ -2626,6 +2628,12 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Compress",
+								Old:  "",
+								New:  "true",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "MaxFileSizeMB",
@@ -2638,6 +2646,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "RotateInterval",
+								Old:  "",
+								New:  "3600000000000",
+							},
 						},
 					},
 				},
@@ -2647,8 +2661,10 @@ func TestTaskDiff(t *testing.T) {
 			Name: "LogConfig deleted",
 			Old: &Task{
 				LogConfig: &LogConfig{
-					MaxFiles:      1,
-					MaxFileSizeMB: 10,
+					MaxFiles:       1,
+					MaxFileSizeMB:  10,
+					RotateInterval: time.Hour,
+					Compress:       true,
 				},
 			},
 			New: &Task{},
@@ -2659,6 +2675,12 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Compress",
+								Old:  "true",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "MaxFileSizeMB",
@@ -2671,6 +2693,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "1",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "RotateInterval",
+								Old:  "3600000000000",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -2686,8 +2714,9 @@ func TestTaskDiff(t *testing.T) {
 			},
 			New: &Task{
 				LogConfig: &LogConfig{
-					MaxFiles:      2,
-					MaxFileSizeMB: 20,
+					MaxFiles:       2,
+					MaxFileSizeMB:  20,
+					RotateInterval: time.Hour,
 				},
 			},
 			Expected: &TaskDiff{
@@ -2709,6 +2738,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "1",
 								New:  "2",
 							},
+							{
+								Type: DiffTypeEdited,
+								Name: "RotateInterval",
+								Old:  "0",
+								New:  "3600000000000",
+							},
 						},
 					},
 				},
@@ -2736,6 +2771,12 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "Compress",
+								Old:  "false",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "MaxFileSizeMB",
@@ -2748,6 +2789,80 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "1",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "RotateInterval",
+								Old:  "0",
+								New:  "0",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "LogConfig sinks edited",
+			Old: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+				},
+			},
+			New: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+					Sinks: []*LogSink{
+						{
+							Type:    LogSinkTypeSyslog,
+							Address: "udp://127.0.0.1:514",
+						},
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "LogConfig",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "LogSink",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Address",
+										Old:  "",
+										New:  "udp://127.0.0.1:514",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "BatchSize",
+										Old:  "",
+										New:  "0",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "BatchWait",
+										Old:  "",
+										New:  "0",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "BufferSize",
+										Old:  "",
+										New:  "0",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Type",
+										Old:  "",
+										New:  "syslog",
+									},
+								},
+							},
 						},
 					},
 				},
@@ -2963,6 +3078,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "100",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "MemoryMaxMB",
+								Old:  "0",
+								New:  "0",
+							},
 						},
 					},
 				},
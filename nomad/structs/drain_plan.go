@@ -0,0 +1,301 @@
+package structs
+
+import (
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DrainPlanStatusRunning marks a plan that is starting node drains as
+	// capacity allows.
+	DrainPlanStatusRunning = "running"
+
+	// DrainPlanStatusPaused marks a plan that will not start any further node
+	// drains until it is resumed. Drains already in progress continue.
+	DrainPlanStatusPaused = "paused"
+
+	// DrainPlanStatusComplete marks a plan whose nodes have all been drained.
+	DrainPlanStatusComplete = "complete"
+
+	// DrainPlanStatusCancelled marks a plan that was stopped by an operator.
+	DrainPlanStatusCancelled = "cancelled"
+)
+
+const (
+	// DrainPlanNodeStatusPending is a node that has not been drained yet.
+	DrainPlanNodeStatusPending = "pending"
+
+	// DrainPlanNodeStatusDraining is a node whose drain is in progress.
+	DrainPlanNodeStatusDraining = "draining"
+
+	// DrainPlanNodeStatusMigrating is a node that is done draining but whose
+	// migrated allocations have not all become healthy yet.
+	DrainPlanNodeStatusMigrating = "migrating"
+
+	// DrainPlanNodeStatusComplete is a node that has been drained and whose
+	// migrated allocations are healthy.
+	DrainPlanNodeStatusComplete = "complete"
+)
+
+const (
+	// DrainPlanStatusDescriptionRunning is the status description of a
+	// running plan.
+	DrainPlanStatusDescriptionRunning = "Drain plan is running"
+
+	// DrainPlanStatusDescriptionPaused is the status description of a plan
+	// paused by an operator.
+	DrainPlanStatusDescriptionPaused = "Drain plan was paused by an operator"
+
+	// DrainPlanStatusDescriptionResumed is the status description of a plan
+	// resumed by an operator.
+	DrainPlanStatusDescriptionResumed = "Drain plan was resumed by an operator"
+
+	// DrainPlanStatusDescriptionCancelled is the status description of a
+	// plan cancelled by an operator.
+	DrainPlanStatusDescriptionCancelled = "Drain plan was cancelled by an operator"
+
+	// DrainPlanStatusDescriptionComplete is the status description of a plan
+	// that drained all of its nodes.
+	DrainPlanStatusDescriptionComplete = "All nodes were drained successfully"
+)
+
+// DrainPlanFilter selects the nodes targeted by a drain plan. A node must
+// match every field that is set.
+type DrainPlanFilter struct {
+	// Datacenter restricts the plan to nodes in the datacenter.
+	Datacenter string
+
+	// NodeClass restricts the plan to nodes of the node class.
+	NodeClass string
+
+	// Meta restricts the plan to nodes with all of the given meta values.
+	Meta map[string]string
+}
+
+// Empty returns whether the filter would select every node.
+func (f *DrainPlanFilter) Empty() bool {
+	return f == nil || (f.Datacenter == "" && f.NodeClass == "" && len(f.Meta) == 0)
+}
+
+// Matches returns whether the node is selected by the filter.
+func (f *DrainPlanFilter) Matches(node *Node) bool {
+	if f.Datacenter != "" && node.Datacenter != f.Datacenter {
+		return false
+	}
+	if f.NodeClass != "" && node.NodeClass != f.NodeClass {
+		return false
+	}
+	for k, v := range f.Meta {
+		if actual, ok := node.Meta[k]; !ok || actual != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *DrainPlanFilter) Copy() *DrainPlanFilter {
+	if f == nil {
+		return nil
+	}
+	nf := new(DrainPlanFilter)
+	*nf = *f
+	if f.Meta != nil {
+		nf.Meta = make(map[string]string, len(f.Meta))
+		for k, v := range f.Meta {
+			nf.Meta[k] = v
+		}
+	}
+	return nf
+}
+
+// DrainPlanNode tracks the progress of a single node of a drain plan.
+type DrainPlanNode struct {
+	// NodeID is the ID of the targeted node.
+	NodeID string
+
+	// Name is the name of the node at the time the plan was created.
+	Name string
+
+	// Status is the progress of the node within the plan.
+	Status string
+
+	// DrainIndex is the index at which the plan started draining the node.
+	// Only allocations migrated after this index are waited on.
+	DrainIndex uint64
+}
+
+// DrainPlan drains a group of nodes in batches. Each batch is bounded by
+// MaxParallel and the next batch is only started once the allocations
+// migrated off the previous nodes are healthy.
+type DrainPlan struct {
+	// ID is a unique identifier for the plan.
+	ID string
+
+	// Filter is the filter used to select the nodes of the plan.
+	Filter *DrainPlanFilter
+
+	// DrainSpec is applied to each node when its drain is started.
+	DrainSpec DrainSpec
+
+	// MaxParallel is the maximum number of nodes that may be draining or
+	// waiting for their migrated allocations to become healthy at once.
+	MaxParallel int
+
+	// Nodes are the nodes targeted by the plan in the order they are drained.
+	Nodes []*DrainPlanNode
+
+	// Status and StatusDescription describe the progress of the plan.
+	Status            string
+	StatusDescription string
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Validate returns an error if the user specified portion of the plan is
+// invalid.
+func (p *DrainPlan) Validate() error {
+	var mErr multierror.Error
+	if p.Filter.Empty() {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("drain plan must filter nodes by datacenter, node class or meta"))
+	}
+	if p.MaxParallel < 1 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max parallel must be at least 1; got %d", p.MaxParallel))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Active returns whether the plan may still start node drains.
+func (p *DrainPlan) Active() bool {
+	switch p.Status {
+	case DrainPlanStatusRunning, DrainPlanStatusPaused:
+		return true
+	default:
+		return false
+	}
+}
+
+// NodeStatusCount returns the number of nodes of the plan in the given status.
+func (p *DrainPlan) NodeStatusCount(status string) int {
+	count := 0
+	for _, n := range p.Nodes {
+		if n.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func (p *DrainPlan) Copy() *DrainPlan {
+	if p == nil {
+		return nil
+	}
+	np := new(DrainPlan)
+	*np = *p
+	np.Filter = p.Filter.Copy()
+	if p.Nodes != nil {
+		np.Nodes = make([]*DrainPlanNode, len(p.Nodes))
+		for i, n := range p.Nodes {
+			nn := *n
+			np.Nodes[i] = &nn
+		}
+	}
+	return np
+}
+
+// Stub returns a summarized version of the plan.
+func (p *DrainPlan) Stub() *DrainPlanListStub {
+	return &DrainPlanListStub{
+		ID:                p.ID,
+		Filter:            p.Filter.Copy(),
+		MaxParallel:       p.MaxParallel,
+		Status:            p.Status,
+		StatusDescription: p.StatusDescription,
+		TotalNodes:        len(p.Nodes),
+		PendingNodes:      p.NodeStatusCount(DrainPlanNodeStatusPending),
+		DrainingNodes: p.NodeStatusCount(DrainPlanNodeStatusDraining) +
+			p.NodeStatusCount(DrainPlanNodeStatusMigrating),
+		CompleteNodes: p.NodeStatusCount(DrainPlanNodeStatusComplete),
+		CreateIndex:   p.CreateIndex,
+		ModifyIndex:   p.ModifyIndex,
+	}
+}
+
+// DrainPlanListStub is used to return a subset of a drain plan for the list
+// endpoint.
+type DrainPlanListStub struct {
+	ID                string
+	Filter            *DrainPlanFilter
+	MaxParallel       int
+	Status            string
+	StatusDescription string
+	TotalNodes        int
+	PendingNodes      int
+	DrainingNodes     int
+	CompleteNodes     int
+	CreateIndex       uint64
+	ModifyIndex       uint64
+}
+
+// DrainPlanUpsertRequest is used to create or update a drain plan. Drains
+// started by the plan are committed in the same transaction so the plan and
+// the nodes can not disagree about which nodes are draining.
+type DrainPlanUpsertRequest struct {
+	Plan       *DrainPlan
+	Updates    map[string]*DrainUpdate
+	NodeEvents map[string]*NodeEvent
+
+	// PlanModifyIndex is the ModifyIndex of the plan the update was made
+	// from. If set, the update is rejected when the plan has been modified
+	// since, so that operator and drain plan watcher updates never overwrite
+	// each other.
+	PlanModifyIndex uint64
+
+	WriteRequest
+}
+
+// DrainPlanCreateRequest is used to create a new drain plan. The nodes of the
+// plan are selected by the server using the plan's filter.
+type DrainPlanCreateRequest struct {
+	Plan *DrainPlan
+	WriteRequest
+}
+
+// DrainPlanStatusUpdateRequest is used to pause, resume or cancel a drain
+// plan.
+type DrainPlanStatusUpdateRequest struct {
+	PlanID string
+	Status string
+	WriteRequest
+}
+
+// DrainPlanUpdateResponse is used to respond to a drain plan write.
+type DrainPlanUpdateResponse struct {
+	PlanID          string
+	PlanModifyIndex uint64
+	WriteMeta
+}
+
+// DrainPlanSpecificRequest is used to make a request specific to a drain plan.
+type DrainPlanSpecificRequest struct {
+	PlanID string
+	QueryOptions
+}
+
+// SingleDrainPlanResponse is used to return a single drain plan.
+type SingleDrainPlanResponse struct {
+	Plan *DrainPlan
+	QueryMeta
+}
+
+// DrainPlanListRequest is used to list the drain plans.
+type DrainPlanListRequest struct {
+	QueryOptions
+}
+
+// DrainPlanListResponse is used for a list request.
+type DrainPlanListResponse struct {
+	Plans []*DrainPlanListStub
+	QueryMeta
+}
@@ -10,11 +10,14 @@ const (
 	errNoLeader            = "No cluster leader"
 	errNoRegionPath        = "No path to region"
 	errTokenNotFound       = "ACL token not found"
+	errTokenExpired        = "ACL token expired"
 	errPermissionDenied    = "Permission denied"
 	errNoNodeConn          = "No path to node"
 	errUnknownMethod       = "Unknown rpc method"
 	errUnknownNomadVersion = "Unable to determine Nomad version"
 	errNodeLacksRpc        = "Node does not support RPC; requires 0.8 or later"
+	errRateLimited         = "RPC rate limit exceeded"
+	errDrainPlanModified   = "Drain plan modified concurrently"
 
 	// Prefix based errors that are used to check if the error is of a given
 	// type. These errors should be created with the associated constructor.
@@ -29,11 +32,14 @@ var (
 	ErrNoLeader            = errors.New(errNoLeader)
 	ErrNoRegionPath        = errors.New(errNoRegionPath)
 	ErrTokenNotFound       = errors.New(errTokenNotFound)
+	ErrTokenExpired        = errors.New(errTokenExpired)
 	ErrPermissionDenied    = errors.New(errPermissionDenied)
 	ErrNoNodeConn          = errors.New(errNoNodeConn)
 	ErrUnknownMethod       = errors.New(errUnknownMethod)
 	ErrUnknownNomadVersion = errors.New(errUnknownNomadVersion)
 	ErrNodeLacksRpc        = errors.New(errNodeLacksRpc)
+	ErrRateLimited         = errors.New(errRateLimited)
+	ErrDrainPlanModified   = errors.New(errDrainPlanModified)
 )
 
 // IsErrNoLeader returns whether the error is due to there being no leader.
@@ -53,6 +59,12 @@ func IsErrTokenNotFound(err error) bool {
 	return err != nil && strings.Contains(err.Error(), errTokenNotFound)
 }
 
+// IsErrTokenExpired returns whether the error is due to the passed token
+// having expired.
+func IsErrTokenExpired(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errTokenExpired)
+}
+
 // IsErrPermissionDenied returns whether the error is due to the operation not
 // being allowed due to lack of permissions.
 func IsErrPermissionDenied(err error) bool {
@@ -71,6 +83,14 @@ func IsErrUnknownMethod(err error) bool {
 	return err != nil && strings.Contains(err.Error(), errUnknownMethod)
 }
 
+// IsErrUnknownRPCMethod returns whether the error is due to the server not
+// supporting the called RPC, either because the service or the method is
+// unknown. This is the case when calling servers running older versions.
+func IsErrUnknownRPCMethod(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "rpc: can't find") ||
+		strings.Contains(err.Error(), errUnknownMethod))
+}
+
 // NewErrUnknownAllocation returns a new error caused by the allocation being
 // unknown.
 func NewErrUnknownAllocation(allocID string) error {
@@ -140,3 +160,15 @@ func IsErrUnknownNomadVersion(err error) bool {
 func IsErrNodeLacksRpc(err error) bool {
 	return err != nil && strings.Contains(err.Error(), errNodeLacksRpc)
 }
+
+// IsErrRateLimited returns whether the error is due to the request being
+// rejected by the rate limits of the servers. These requests may be retried.
+func IsErrRateLimited(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errRateLimited)
+}
+
+// IsErrDrainPlanModified returns whether the error is due to the drain plan
+// being modified since it was read.
+func IsErrDrainPlanModified(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errDrainPlanModified)
+}
@@ -0,0 +1,95 @@
+package structs
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// IdentityAlgorithmEdDSA is the JWS algorithm used to sign workload
+	// identities. The signing keys are derived from the root keys.
+	IdentityAlgorithmEdDSA = "EdDSA"
+
+	// IdentityDefaultAudience is the audience of workload identities
+	IdentityDefaultAudience = "nomadproject.io"
+
+	// IdentityDefaultTTL is the duration for which workload identities are
+	// valid. Clients renew the identities of running tasks before they
+	// expire.
+	IdentityDefaultTTL = time.Hour
+)
+
+// IdentityClaims are the claims of the JWT signed for a task of an
+// allocation, identifying the workload to Nomad and to third parties.
+type IdentityClaims struct {
+	// Registered claims
+	Issuer    string `json:"iss,omitempty"`
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud"`
+	ID        string `json:"jti"`
+	IssuedAt  int64  `json:"iat"`
+	NotBefore int64  `json:"nbf"`
+	Expiry    int64  `json:"exp"`
+
+	// Nomad claims
+	Namespace    string `json:"nomad_namespace"`
+	JobID        string `json:"nomad_job_id"`
+	TaskGroup    string `json:"nomad_task_group"`
+	Task         string `json:"nomad_task"`
+	AllocationID string `json:"nomad_allocation_id"`
+}
+
+// NewIdentityClaims returns the claims identifying the given task of the
+// allocation. The subject uniquely identifies the task across the cluster.
+func NewIdentityClaims(alloc *Allocation, task string) *IdentityClaims {
+	now := time.Now().UTC()
+	return &IdentityClaims{
+		Subject: fmt.Sprintf("%s:%s:%s:%s:%s",
+			alloc.Namespace, alloc.JobID, alloc.TaskGroup, task, alloc.ID),
+		Audience:     IdentityDefaultAudience,
+		ID:           fmt.Sprintf("%s:%s", alloc.ID, task),
+		IssuedAt:     now.Unix(),
+		NotBefore:    now.Unix(),
+		Expiry:       now.Add(IdentityDefaultTTL).Unix(),
+		Namespace:    alloc.Namespace,
+		JobID:        alloc.JobID,
+		TaskGroup:    alloc.TaskGroup,
+		Task:         task,
+		AllocationID: alloc.ID,
+	}
+}
+
+// AllocIdentityRequest is used by clients to retrieve the signed identity of
+// a task of an allocation running on the node.
+type AllocIdentityRequest struct {
+	NodeID   string
+	SecretID string
+	AllocID  string
+	TaskName string
+	QueryOptions
+}
+
+// AllocIdentityResponse is the response to an AllocIdentityRequest
+type AllocIdentityResponse struct {
+	// Token is the signed JWT of the task
+	Token string
+
+	// Expiry is the time at which the token expires
+	Expiry time.Time
+	QueryMeta
+}
+
+// KeyringPublicKey is the public half of the signing key derived from a root
+// key. It is used to verify workload identities.
+type KeyringPublicKey struct {
+	KeyID      string
+	PublicKey  []byte
+	Algorithm  string
+	CreateTime int64
+}
+
+// KeyringListPublicResponse is used to return the public keys of the keyring
+type KeyringListPublicResponse struct {
+	PublicKeys []*KeyringPublicKey
+	QueryMeta
+}
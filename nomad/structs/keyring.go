@@ -0,0 +1,107 @@
+package structs
+
+import "time"
+
+// RootKeyState is the lifecycle state of a root key
+type RootKeyState string
+
+const (
+	// RootKeyStateActive is the key used for new encryptions
+	RootKeyStateActive RootKeyState = "active"
+
+	// RootKeyStateInactive keys are only used for decryption
+	RootKeyStateInactive RootKeyState = "inactive"
+)
+
+const (
+	// EncryptionAlgorithmAES256GCM is the only supported algorithm
+	EncryptionAlgorithmAES256GCM = "aes256-gcm"
+)
+
+// RootKey is a key used to encrypt data stored in the state store. The key
+// material is only held in the keystore of each server; only the metadata is
+// written to raft.
+type RootKey struct {
+	Meta *RootKeyMeta
+	Key  []byte
+}
+
+// Copy returns a deep copy of the root key
+func (k *RootKey) Copy() *RootKey {
+	if k == nil {
+		return nil
+	}
+	nk := &RootKey{
+		Meta: k.Meta.Copy(),
+	}
+	if k.Key != nil {
+		nk.Key = make([]byte, len(k.Key))
+		copy(nk.Key, k.Key)
+	}
+	return nk
+}
+
+// RootKeyMeta is the non-secret portion of a root key
+type RootKeyMeta struct {
+	KeyID       string
+	Algorithm   string
+	State       RootKeyState
+	CreateTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// IsActive returns whether the key is used for new encryptions
+func (k *RootKeyMeta) IsActive() bool {
+	return k.State == RootKeyStateActive
+}
+
+// Copy returns a copy of the root key metadata
+func (k *RootKeyMeta) Copy() *RootKeyMeta {
+	if k == nil {
+		return nil
+	}
+	nk := new(RootKeyMeta)
+	*nk = *k
+	return nk
+}
+
+// KeyringUpsertRootKeyMetaRequest is used to add the metadata of a root key
+// to the keyring. If the key is active, all other keys are made inactive.
+type KeyringUpsertRootKeyMetaRequest struct {
+	RootKeyMeta *RootKeyMeta
+	WriteRequest
+}
+
+// KeyringGetRootKeyRequest is used by servers to fetch the key material of a
+// root key from the keystore of another server.
+type KeyringGetRootKeyRequest struct {
+	KeyID string
+
+	// ServerProof proves that the request was made by a server of the
+	// region when it isn't made over mutual TLS. It is an HMAC of the region
+	// and key ID keyed by the gossip encryption key, which only servers have.
+	ServerProof []byte
+
+	QueryOptions
+}
+
+// KeyringGetRootKeyResponse is used to return a root key
+type KeyringGetRootKeyResponse struct {
+	Key *RootKey
+	QueryMeta
+}
+
+// NewRootKey returns a new active root key created from the given key
+// material.
+func NewRootKey(id string, key []byte) *RootKey {
+	return &RootKey{
+		Meta: &RootKeyMeta{
+			KeyID:      id,
+			Algorithm:  EncryptionAlgorithmAES256GCM,
+			State:      RootKeyStateActive,
+			CreateTime: time.Now().UTC().UnixNano(),
+		},
+		Key: key,
+	}
+}
@@ -3,6 +3,7 @@ package structs
 import (
 	"fmt"
 	"io"
+	"net"
 	"sync"
 )
 
@@ -24,6 +25,27 @@ type StreamingRpcAck struct {
 // StreamingRpcHandler defines the handler for a streaming RPC.
 type StreamingRpcHandler func(conn io.ReadWriteCloser)
 
+// StreamingRpcRemoteConn is the connection of a streaming RPC made in-process
+// on behalf of a remote caller, such as an HTTP client of the agent. It
+// carries the host of the caller so that the RPC is limited per caller.
+type StreamingRpcRemoteConn struct {
+	io.ReadWriteCloser
+
+	// RemoteHost is the host of the caller
+	RemoteHost string
+}
+
+// NewStreamingRpcRemoteConn wraps the connection of an in-process streaming
+// RPC made on behalf of the caller with the given address. The connection is
+// returned as is if the address is invalid.
+func NewStreamingRpcRemoteConn(conn io.ReadWriteCloser, addr string) io.ReadWriteCloser {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return conn
+	}
+	return &StreamingRpcRemoteConn{ReadWriteCloser: conn, RemoteHost: host}
+}
+
 // StreamingRpcRegistry is used to add and retrieve handlers
 type StreamingRpcRegistry struct {
 	registry map[string]StreamingRpcHandler
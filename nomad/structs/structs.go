@@ -31,6 +31,7 @@ import (
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/args"
+	"github.com/hashicorp/nomad/helper/oidc"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/mitchellh/copystructure"
 	"github.com/ugorji/go/codec"
@@ -81,6 +82,19 @@ const (
 	AllocUpdateDesiredTransitionRequestType
 	NodeUpdateEligibilityRequestType
 	BatchNodeUpdateDrainRequestType
+	RootKeyMetaUpsertRequestType
+	VarApplyStateRequestType
+	ACLRoleUpsertRequestType
+	ACLRoleDeleteRequestType
+	ACLAuthMethodUpsertRequestType
+	ACLAuthMethodDeleteRequestType
+	ACLBindingRuleUpsertRequestType
+	ACLBindingRuleDeleteRequestType
+	DrainPlanUpsertRequestType
+	CSIVolumeRegisterRequestType
+	CSIVolumeDeregisterRequestType
+	CSIVolumeClaimRequestType
+	NodeUpdateMetaRequestType
 )
 
 const (
@@ -163,10 +177,14 @@ func (n NamespacedID) String() string {
 // RPCInfo is used to describe common information about query
 type RPCInfo interface {
 	RequestRegion() string
+	RequestToken() string
 	IsRead() bool
 	AllowStaleRead() bool
 	IsForwarded() bool
 	SetForwarded()
+	SetRemote(addr string, server bool)
+	RemoteAddr() string
+	IsFromServer() bool
 }
 
 // InternalRpcInfo allows adding internal RPC metadata to an RPC. This struct
@@ -174,6 +192,12 @@ type RPCInfo interface {
 type InternalRpcInfo struct {
 	// Forwarded marks whether the RPC has been forwarded.
 	Forwarded bool
+
+	// remoteAddr and fromServer are set by the server receiving the RPC from
+	// the connection it was read from. They are unexported so that they are
+	// never encoded and can not be set by the caller.
+	remoteAddr string
+	fromServer bool
 }
 
 // IsForwarded returns whether the RPC is forwarded from another server.
@@ -186,6 +210,23 @@ func (i *InternalRpcInfo) SetForwarded() {
 	i.Forwarded = true
 }
 
+// SetRemote records the address the RPC was received from and whether that
+// connection is from another server.
+func (i *InternalRpcInfo) SetRemote(addr string, server bool) {
+	i.remoteAddr = addr
+	i.fromServer = server
+}
+
+// RemoteAddr returns the address the RPC was received from, if known.
+func (i *InternalRpcInfo) RemoteAddr() string {
+	return i.remoteAddr
+}
+
+// IsFromServer returns whether the RPC was received from another server.
+func (i *InternalRpcInfo) IsFromServer() bool {
+	return i.fromServer
+}
+
 // QueryOptions is used to specify various flags for read queries
 type QueryOptions struct {
 	// The target region for this query
@@ -218,6 +259,11 @@ func (q QueryOptions) RequestRegion() string {
 	return q.Region
 }
 
+// RequestToken returns the secret ID of the ACL token used for the request
+func (q QueryOptions) RequestToken() string {
+	return q.AuthToken
+}
+
 func (q QueryOptions) RequestNamespace() string {
 	if q.Namespace == "" {
 		return DefaultNamespace
@@ -252,6 +298,11 @@ func (w WriteRequest) RequestRegion() string {
 	return w.Region
 }
 
+// RequestToken returns the secret ID of the ACL token used for the request
+func (w WriteRequest) RequestToken() string {
+	return w.AuthToken
+}
+
 func (w WriteRequest) RequestNamespace() string {
 	if w.Namespace == "" {
 		return DefaultNamespace
@@ -385,6 +436,15 @@ type NodeUpdateEligibilityRequest struct {
 	WriteRequest
 }
 
+// NodeUpdateMetaRequest is used by a client to update the meta of its node
+// without re-registering it.
+type NodeUpdateMetaRequest struct {
+	NodeID   string
+	SecretID string
+	Meta     map[string]string
+	WriteRequest
+}
+
 // NodeEvaluateRequest is used to re-evaluate the node
 type NodeEvaluateRequest struct {
 	NodeID string
@@ -441,6 +501,10 @@ type JobRegisterRequest struct {
 	// PolicyOverride is set when the user is attempting to override any policies
 	PolicyOverride bool
 
+	// Submission is the original source of the job. It is optional and is
+	// stored alongside the job version created by the registration.
+	Submission *JobSubmission
+
 	WriteRequest
 }
 
@@ -669,6 +733,21 @@ type AllocUpdateDesiredTransitionRequest struct {
 	WriteRequest
 }
 
+// AllocStopRequest is used to stop and reschedule a running Allocation.
+type AllocStopRequest struct {
+	AllocID string
+
+	WriteRequest
+}
+
+// AllocStopResponse is the response to an `AllocStopRequest`
+type AllocStopResponse struct {
+	// EvalID is the id of the follow up evaluation for the rescheduled alloc.
+	EvalID string
+
+	WriteMeta
+}
+
 // AllocListRequest is used to request a list of allocations
 type AllocListRequest struct {
 	QueryOptions
@@ -1025,6 +1104,25 @@ type JobListResponse struct {
 	QueryMeta
 }
 
+// JobSubmissionRequest is used to get the source of a job version
+type JobSubmissionRequest struct {
+	JobID   string
+	Version uint64
+	QueryOptions
+}
+
+// JobSubmissionResponse is used to return the source of a job version
+type JobSubmissionResponse struct {
+	Submission *JobSubmission
+	QueryMeta
+}
+
+// JobPlacementStatusResponse is used to explain why a job is not placed
+type JobPlacementStatusResponse struct {
+	Status *JobPlacementStatus
+	QueryMeta
+}
+
 // JobVersionsRequest is used to get a jobs versions
 type JobVersionsRequest struct {
 	JobID string
@@ -1476,6 +1574,14 @@ type Node struct {
 	// Drivers is a map of driver names to current driver information
 	Drivers map[string]*DriverInfo
 
+	// HostVolumes is a map of host volume names to their configuration
+	HostVolumes map[string]*ClientHostVolumeConfig
+
+	// CSIControllerPlugins and CSINodePlugins are the fingerprints of the CSI
+	// plugins running on the node, by plugin ID
+	CSIControllerPlugins map[string]*CSIInfo
+	CSINodePlugins       map[string]*CSIInfo
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -1518,6 +1624,9 @@ func (n *Node) Copy() *Node {
 	nn.Events = copyNodeEvents(n.Events)
 	nn.DrainStrategy = nn.DrainStrategy.Copy()
 	nn.Drivers = copyNodeDrivers(n.Drivers)
+	nn.HostVolumes = CopyMapStringClientHostVolumeConfig(n.HostVolumes)
+	nn.CSIControllerPlugins = CopyMapStringCSIInfo(n.CSIControllerPlugins)
+	nn.CSINodePlugins = CopyMapStringCSIInfo(n.CSINodePlugins)
 	return nn
 }
 
@@ -1628,6 +1737,12 @@ type Resources struct {
 	DiskMB   int
 	IOPS     int
 	Networks Networks
+
+	// MemoryMaxMB is the hard memory limit of a task when it may use more
+	// memory than it reserves. MemoryMB stays the reservation used for
+	// scheduling. It is ignored unless memory oversubscription is enabled on
+	// the servers.
+	MemoryMaxMB int
 }
 
 const (
@@ -1672,6 +1787,9 @@ func (r *Resources) Merge(other *Resources) {
 	if other.MemoryMB != 0 {
 		r.MemoryMB = other.MemoryMB
 	}
+	if other.MemoryMaxMB != 0 {
+		r.MemoryMaxMB = other.MemoryMaxMB
+	}
 	if other.DiskMB != 0 {
 		r.DiskMB = other.DiskMB
 	}
@@ -1952,6 +2070,17 @@ const (
 	// JobTrackedVersions is the number of historic job versions that are
 	// kept.
 	JobTrackedVersions = 6
+
+	// JobSubmissionMaxSize is the maximum size in bytes of the source of a
+	// job that is stored. Larger sources are dropped with a warning.
+	JobSubmissionMaxSize = 1024 * 1024
+)
+
+const (
+	// JobSubmissionFormatHCL and JobSubmissionFormatJSON are the formats of
+	// a job submission source.
+	JobSubmissionFormatHCL  = "hcl"
+	JobSubmissionFormatJSON = "json"
 )
 
 // Job is the scope of a scheduling request to Nomad. It is the largest
@@ -2440,6 +2569,44 @@ func (j *Job) SetSubmitTime() {
 	j.SubmitTime = time.Now().UTC().UnixNano()
 }
 
+// JobSubmission is the original source of a job version, exactly as it was
+// submitted by the user, including comments.
+type JobSubmission struct {
+	// Source is the raw job specification
+	Source string
+
+	// Format is the format of the source, either "hcl" or "json"
+	Format string
+
+	// Namespace, JobID and Version identify the job version the source
+	// created. They are set by the server.
+	Namespace string
+	JobID     string
+	Version   uint64
+
+	// JobModifyIndex is the index of the job version
+	JobModifyIndex uint64
+}
+
+// Copy returns a copy of the submission
+func (s *JobSubmission) Copy() *JobSubmission {
+	if s == nil {
+		return nil
+	}
+	ns := *s
+	return &ns
+}
+
+// Validate returns an error if the submission is invalid
+func (s *JobSubmission) Validate() error {
+	switch s.Format {
+	case JobSubmissionFormatHCL, JobSubmissionFormatJSON:
+	default:
+		return fmt.Errorf("invalid job submission format %q", s.Format)
+	}
+	return nil
+}
+
 // JobListStub is used to return a subset of job information
 // for the job list
 type JobListStub struct {
@@ -2889,6 +3056,30 @@ func (d *DispatchPayloadConfig) Copy() *DispatchPayloadConfig {
 	return nd
 }
 
+// WorkloadIdentity configures how the signed workload identity of a task is
+// exposed to it. The identity is only retrieved if it is exposed.
+type WorkloadIdentity struct {
+	// Env injects the identity into the task's environment as NOMAD_TOKEN
+	Env bool
+
+	// File writes the identity to the task's secrets directory
+	File bool
+}
+
+func (w *WorkloadIdentity) Copy() *WorkloadIdentity {
+	if w == nil {
+		return nil
+	}
+	nw := new(WorkloadIdentity)
+	*nw = *w
+	return nw
+}
+
+// Exposed returns whether the identity is exposed to the task
+func (w *WorkloadIdentity) Exposed() bool {
+	return w != nil && (w.Env || w.File)
+}
+
 func (d *DispatchPayloadConfig) Validate() error {
 	// Verify the destination doesn't escape
 	escaped, err := PathEscapesAllocDir("task/local/", d.File)
@@ -3315,6 +3506,9 @@ type TaskGroup struct {
 	// ReschedulePolicy is used to configure how the scheduler should
 	// retry failed allocations.
 	ReschedulePolicy *ReschedulePolicy
+
+	// Volumes is a map of volumes that have been requested by the task group.
+	Volumes map[string]*VolumeRequest
 }
 
 func (tg *TaskGroup) Copy() *TaskGroup {
@@ -3337,6 +3531,7 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	}
 
 	ntg.Meta = helper.CopyMapStringString(ntg.Meta)
+	ntg.Volumes = CopyMapVolumeRequest(ntg.Volumes)
 
 	if tg.EphemeralDisk != nil {
 		ntg.EphemeralDisk = tg.EphemeralDisk.Copy()
@@ -3502,6 +3697,28 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Only one task may be marked as leader"))
 	}
 
+	// Validate the volumes and the tasks' mounts of them
+	for name, v := range tg.Volumes {
+		if v == nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Volume %q is empty", name))
+			continue
+		}
+		if v.Name != name {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Volume %q has mismatched name %q", name, v.Name))
+		}
+		if err := v.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+	for _, task := range tg.Tasks {
+		for idx, mount := range task.VolumeMounts {
+			if err := mount.Validate(tg.Volumes); err != nil {
+				outer := fmt.Errorf("Task %s volume mount %d validation failed: %s", task.Name, idx+1, err)
+				mErr.Errors = append(mErr.Errors, outer)
+			}
+		}
+	}
+
 	// Validate the tasks
 	for _, task := range tg.Tasks {
 		if err := task.Validate(tg.EphemeralDisk); err != nil {
@@ -3952,8 +4169,22 @@ const (
 type LogConfig struct {
 	MaxFiles      int
 	MaxFileSizeMB int
+
+	// RotateInterval is the age a log file is allowed to reach before being
+	// rotated, regardless of its size. Zero disables it.
+	RotateInterval time.Duration
+
+	// Compress enables compressing the rotated log files
+	Compress bool
+
+	// Sinks are the destinations task logs are shipped to in addition to
+	// the local log files
+	Sinks []*LogSink
 }
 
+// minLogRotateInterval is the minimum interval log files can be rotated at
+const minLogRotateInterval = 1 * time.Minute
+
 // DefaultLogConfig returns the default LogConfig values.
 func DefaultLogConfig() *LogConfig {
 	return &LogConfig{
@@ -3972,9 +4203,143 @@ func (l *LogConfig) Validate() error {
 	if l.MaxFileSizeMB < 1 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum file size is 1MB; got %d", l.MaxFileSizeMB))
 	}
+	if l.RotateInterval < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("rotate interval can not be negative; got %v", l.RotateInterval))
+	} else if l.RotateInterval != 0 && l.RotateInterval < minLogRotateInterval {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum rotate interval is %v; got %v", minLogRotateInterval, l.RotateInterval))
+	}
+	for i, sink := range l.Sinks {
+		if err := sink.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("log sink %d validation failed: %v", i+1, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+func (l *LogConfig) Copy() *LogConfig {
+	if l == nil {
+		return nil
+	}
+	nl := new(LogConfig)
+	*nl = *l
+	if l.Sinks != nil {
+		nl.Sinks = make([]*LogSink, len(l.Sinks))
+		for i, s := range l.Sinks {
+			nl.Sinks[i] = s.Copy()
+		}
+	}
+	return nl
+}
+
+const (
+	LogSinkTypeSyslog   = "syslog"
+	LogSinkTypeJournald = "journald"
+	LogSinkTypeFluentd  = "fluentd"
+	LogSinkTypeHTTP     = "http"
+)
+
+// LogSink configures a destination task logs are shipped to
+type LogSink struct {
+	// Type is the type of the sink: syslog, journald, fluentd or http
+	Type string
+
+	// Address is where logs are shipped to. Syslog addresses are URLs with a
+	// tcp, udp or unix scheme, fluentd addresses are host:port pairs or unix
+	// URLs and HTTP addresses are http or https URLs. Journald ships to the
+	// local journal unless the address of its socket is given.
+	Address string
+
+	// Tag identifies the logs of the task at the destination. It defaults to
+	// the name of the task.
+	Tag string
+
+	// BatchSize is the maximum number of log lines sent in a single request
+	// by the HTTP sink
+	BatchSize int
+
+	// BatchWait is the maximum duration log lines are held before the HTTP
+	// sink sends a partial batch
+	BatchWait time.Duration
+
+	// BufferSize is the maximum number of log lines buffered while the
+	// destination is unavailable or slow. Lines are dropped once the buffer
+	// is full so the task is never blocked on its logs.
+	BufferSize int
+}
+
+func (s *LogSink) Copy() *LogSink {
+	if s == nil {
+		return nil
+	}
+	ns := new(LogSink)
+	*ns = *s
+	return ns
+}
+
+// Validate returns an error if the log sink is invalid
+func (s *LogSink) Validate() error {
+	var mErr multierror.Error
+
+	switch s.Type {
+	case LogSinkTypeSyslog:
+		if s.Address == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("syslog sink requires an address"))
+		} else if err := validateLogSinkURL(s.Address, "tcp", "udp", "unix", "unixgram"); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	case LogSinkTypeJournald:
+		if s.Address != "" {
+			if err := validateLogSinkURL(s.Address, "unixgram"); err != nil {
+				mErr.Errors = append(mErr.Errors, err)
+			}
+		}
+	case LogSinkTypeFluentd:
+		if s.Address == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("fluentd sink requires an address"))
+		} else if strings.Contains(s.Address, "://") {
+			if err := validateLogSinkURL(s.Address, "tcp", "unix"); err != nil {
+				mErr.Errors = append(mErr.Errors, err)
+			}
+		} else if _, _, err := net.SplitHostPort(s.Address); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid fluentd address %q: %v", s.Address, err))
+		}
+	case LogSinkTypeHTTP:
+		if s.Address == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("http sink requires an address"))
+		} else if err := validateLogSinkURL(s.Address, "http", "https"); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("unknown log sink type %q", s.Type))
+	}
+
+	if s.BatchSize < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("batch size must be non-negative; got %d", s.BatchSize))
+	}
+	if s.BatchWait < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("batch wait must be non-negative; got %v", s.BatchWait))
+	}
+	if s.BufferSize < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("buffer size must be non-negative; got %d", s.BufferSize))
+	}
 	return mErr.ErrorOrNil()
 }
 
+// validateLogSinkURL returns an error if the address isn't a URL with one of
+// the given schemes
+func validateLogSinkURL(addr string, schemes ...string) error {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %v", addr, err)
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid address %q: scheme must be one of %s", addr, strings.Join(schemes, ", "))
+}
+
 // Task is a single process typically that is executed as part of a task group.
 type Task struct {
 	// Name of the task
@@ -4013,6 +4378,9 @@ type Task struct {
 	// DispatchPayload configures how the task retrieves its input from a dispatch
 	DispatchPayload *DispatchPayloadConfig
 
+	// Identity configures how the task's workload identity is exposed to it
+	Identity *WorkloadIdentity
+
 	// Meta is used to associate arbitrary metadata with this
 	// task. This is opaque to Nomad.
 	Meta map[string]string
@@ -4041,6 +4409,13 @@ type Task struct {
 	// KillSignal is the kill signal to use for the task. This is an optional
 	// specification and defaults to SIGINT
 	KillSignal string
+
+	// VolumeMounts is a list of the task group's volumes to mount into the
+	// task.
+	VolumeMounts []*VolumeMount
+
+	// CSIPluginConfig is used to configure the task as a CSI plugin.
+	CSIPluginConfig *TaskCSIPluginConfig
 }
 
 func (t *Task) Copy() *Task {
@@ -4065,6 +4440,10 @@ func (t *Task) Copy() *Task {
 	nt.Resources = nt.Resources.Copy()
 	nt.Meta = helper.CopyMapStringString(nt.Meta)
 	nt.DispatchPayload = nt.DispatchPayload.Copy()
+	nt.Identity = nt.Identity.Copy()
+	nt.VolumeMounts = CopySliceVolumeMount(nt.VolumeMounts)
+	nt.CSIPluginConfig = nt.CSIPluginConfig.Copy()
+	nt.LogConfig = nt.LogConfig.Copy()
 
 	if t.Artifacts != nil {
 		artifacts := make([]*TaskArtifact, 0, len(t.Artifacts))
@@ -4128,6 +4507,10 @@ func (t *Task) Canonicalize(job *Job, tg *TaskGroup) {
 	for _, template := range t.Templates {
 		template.Canonicalize()
 	}
+
+	if t.CSIPluginConfig != nil && t.CSIPluginConfig.MountDir == "" {
+		t.CSIPluginConfig.MountDir = CSIPluginDefaultMountDir
+	}
 }
 
 func (t *Task) GoString() string {
@@ -4167,6 +4550,11 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk) error {
 		if t.Resources.DiskMB > 0 {
 			mErr.Errors = append(mErr.Errors, errors.New("Task can't ask for disk resources, they have to be specified at the task group level."))
 		}
+
+		// Ensure the memory limit isn't below the reservation
+		if max := t.Resources.MemoryMaxMB; max != 0 && max < t.Resources.MemoryMB {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("MemoryMaxMB value (%d) must be greater than or equal to MemoryMB value (%d)", max, t.Resources.MemoryMB))
+		}
 	}
 
 	// Validate the log config
@@ -4194,6 +4582,7 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk) error {
 		mErr.Errors = append(mErr.Errors, err)
 	}
 
+	// Compressed log files count towards MaxFiles so they are accounted for
 	if t.LogConfig != nil && ephemeralDisk != nil {
 		logUsage := (t.LogConfig.MaxFiles * t.LogConfig.MaxFileSizeMB)
 		if ephemeralDisk.SizeMB <= logUsage {
@@ -4238,6 +4627,13 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk) error {
 		}
 	}
 
+	// Validate the CSI plugin block if there
+	if t.CSIPluginConfig != nil {
+		if err := t.CSIPluginConfig.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("CSI plugin validation failed: %v", err))
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -5682,6 +6078,10 @@ type DesiredTransition struct {
 	// This field is only used when operators want to force a placement even if
 	// a failed allocation is not eligible to be rescheduled
 	ForceReschedule *bool
+
+	// Reason is a human readable reason for the transition. It is recorded as
+	// the kill reason of the tasks when the allocation is stopped.
+	Reason string
 }
 
 // Merge merges the two desired transitions, preferring the values from the
@@ -5698,6 +6098,10 @@ func (d *DesiredTransition) Merge(o *DesiredTransition) {
 	if o.ForceReschedule != nil {
 		d.ForceReschedule = o.ForceReschedule
 	}
+
+	if o.Reason != "" {
+		d.Reason = o.Reason
+	}
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
@@ -6307,6 +6711,157 @@ func (a *AllocMetric) ScoreNode(node *Node, name string, score float64) {
 	a.Scores[key] = score
 }
 
+// JobPlacementStatus explains why task groups of a job could not be placed. It
+// is built from the placement failures of the most recent evaluation that
+// failed to place allocations and the blocked evaluation that retries them.
+type JobPlacementStatus struct {
+	Namespace string
+	JobID     string
+
+	// EvalID is the evaluation the placement failures were recorded by
+	EvalID string
+
+	// BlockedEvalID is the blocked evaluation that will retry the placements
+	// once the cluster changes.
+	BlockedEvalID string
+
+	// WaitingClasses are the node classes the blocked evaluation is waiting
+	// on to gain capacity. Computed classes without any nodes are given as is.
+	WaitingClasses []string
+
+	// EscapedComputedClass is set if the job has constraints that can not be
+	// tracked by node class, so a capacity change on any node unblocks it.
+	EscapedComputedClass bool
+
+	// QuotaLimitReached is the quota that is blocking placement, if any
+	QuotaLimitReached string
+
+	// TaskGroups is the placement status of each unplaced task group
+	TaskGroups map[string]*TaskGroupPlacementStatus
+}
+
+// TaskGroupPlacementStatus explains why a task group could not be placed
+type TaskGroupPlacementStatus struct {
+	// Queued is the number of allocations waiting to be placed
+	Queued int
+
+	// Metrics is the last placement failure of the task group
+	Metrics *AllocMetric
+
+	// ExhaustedDimensions are the resources that nodes ran out of, ordered
+	// by the number of nodes exhausted.
+	ExhaustedDimensions []*PlacementReason
+
+	// FilteringConstraints are the constraints that filtered nodes, ordered
+	// by the number of nodes filtered.
+	FilteringConstraints []*PlacementReason
+
+	// ExhaustedClasses and FilteredClasses are the node classes of the
+	// exhausted and filtered nodes, ordered by the number of nodes.
+	ExhaustedClasses []*PlacementReason
+	FilteredClasses  []*PlacementReason
+}
+
+// PlacementReason is the number of nodes that could not be used for the
+// given reason.
+type PlacementReason struct {
+	Reason string
+	Nodes  int
+}
+
+// NewJobPlacementStatus builds the placement status of a job from its
+// summary and evaluations. nodeClasses maps computed node classes to the node
+// class of their nodes.
+func NewJobPlacementStatus(namespace, jobID string, summary *JobSummary,
+	evals []*Evaluation, nodeClasses map[string]string) *JobPlacementStatus {
+
+	status := &JobPlacementStatus{
+		Namespace:  namespace,
+		JobID:      jobID,
+		TaskGroups: make(map[string]*TaskGroupPlacementStatus),
+	}
+
+	// Find the most recent failed and blocked evaluations
+	var failed, blocked *Evaluation
+	for _, eval := range evals {
+		if len(eval.FailedTGAllocs) != 0 && (failed == nil || eval.ModifyIndex > failed.ModifyIndex) {
+			failed = eval
+		}
+		if eval.Status == EvalStatusBlocked && (blocked == nil || eval.ModifyIndex > blocked.ModifyIndex) {
+			blocked = eval
+		}
+	}
+	if failed == nil {
+		return status
+	}
+	status.EvalID = failed.ID
+
+	if blocked != nil {
+		status.BlockedEvalID = blocked.ID
+		status.EscapedComputedClass = blocked.EscapedComputedClass
+		status.QuotaLimitReached = blocked.QuotaLimitReached
+
+		seen := make(map[string]struct{})
+		for computed, eligible := range blocked.ClassEligibility {
+			if !eligible {
+				continue
+			}
+			class, ok := nodeClasses[computed]
+			if !ok {
+				class = computed
+			}
+			if _, ok := seen[class]; ok {
+				continue
+			}
+			seen[class] = struct{}{}
+			status.WaitingClasses = append(status.WaitingClasses, class)
+		}
+		sort.Strings(status.WaitingClasses)
+	}
+
+	for tg, metric := range failed.FailedTGAllocs {
+		// Skip task groups that have since been placed
+		var queued int
+		if summary != nil {
+			queued = summary.Summary[tg].Queued
+			if queued == 0 {
+				continue
+			}
+		}
+
+		status.TaskGroups[tg] = &TaskGroupPlacementStatus{
+			Queued:               queued,
+			Metrics:              metric.Copy(),
+			ExhaustedDimensions:  sortedPlacementReasons(metric.DimensionExhausted),
+			FilteringConstraints: sortedPlacementReasons(metric.ConstraintFiltered),
+			ExhaustedClasses:     sortedPlacementReasons(metric.ClassExhausted),
+			FilteredClasses:      sortedPlacementReasons(metric.ClassFiltered),
+		}
+	}
+
+	return status
+}
+
+// sortedPlacementReasons returns the reasons ordered by the number of nodes,
+// most first.
+func sortedPlacementReasons(counts map[string]int) []*PlacementReason {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	reasons := make([]*PlacementReason, 0, len(counts))
+	for reason, nodes := range counts {
+		reasons = append(reasons, &PlacementReason{Reason: reason, Nodes: nodes})
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].Nodes != reasons[j].Nodes {
+			return reasons[i].Nodes > reasons[j].Nodes
+		}
+		return reasons[i].Reason < reasons[j].Reason
+	})
+	return reasons
+}
+
 // AllocDeploymentStatus captures the status of the allocation as part of the
 // deployment. This can include things like if the allocation has been marked as
 // healthy.
@@ -6397,6 +6952,7 @@ const (
 	EvalTriggerFailedFollowUp    = "failed-follow-up"
 	EvalTriggerMaxPlans          = "max-plan-attempts"
 	EvalTriggerRetryFailedAlloc  = "alloc-failure"
+	EvalTriggerAllocStop         = "alloc-stop"
 )
 
 const (
@@ -6422,6 +6978,10 @@ const (
 	// check if they are terminal. If so, we delete these out of the system.
 	CoreJobDeploymentGC = "deployment-gc"
 
+	// CoreJobExpiredACLTokenGC is used for the garbage collection of ACL
+	// tokens that have expired.
+	CoreJobExpiredACLTokenGC = "expired-acl-token-gc"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
 )
@@ -7132,46 +7692,19 @@ type ACLPolicyUpsertRequest struct {
 	WriteRequest
 }
 
-// ACLToken represents a client token which is used to Authenticate
-type ACLToken struct {
-	AccessorID  string   // Public Accessor ID (UUID)
-	SecretID    string   // Secret ID, private (UUID)
-	Name        string   // Human friendly name
-	Type        string   // Client or Management
-	Policies    []string // Policies this token ties to
-	Global      bool     // Global or Region local
-	Hash        []byte
-	CreateTime  time.Time // Time of creation
-	CreateIndex uint64
-	ModifyIndex uint64
-}
-
-var (
-	// AnonymousACLToken is used no SecretID is provided, and the
-	// request is made anonymously.
-	AnonymousACLToken = &ACLToken{
-		AccessorID: "anonymous",
-		Name:       "Anonymous Token",
-		Type:       ACLClientToken,
-		Policies:   []string{"anonymous"},
-		Global:     false,
-	}
-)
-
-type ACLTokenListStub struct {
-	AccessorID  string
-	Name        string
-	Type        string
-	Policies    []string
-	Global      bool
+// ACLRole is used to represent a named set of ACL policies that may be
+// attached to tokens instead of the individual policies
+type ACLRole struct {
+	Name        string   // Unique name
+	Description string   // Human readable
+	Policies    []string // Policies granted by the role
 	Hash        []byte
-	CreateTime  time.Time
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
-// SetHash is used to compute and set the hash of the ACL token
-func (a *ACLToken) SetHash() []byte {
+// SetHash is used to compute and set the hash of the ACL role
+func (r *ACLRole) SetHash() []byte {
 	// Initialize a 256bit Blake2 hash (32 bytes)
 	hash, err := blake2b.New256(nil)
 	if err != nil {
@@ -7179,40 +7712,692 @@ func (a *ACLToken) SetHash() []byte {
 	}
 
 	// Write all the user set fields
-	hash.Write([]byte(a.Name))
-	hash.Write([]byte(a.Type))
-	for _, policyName := range a.Policies {
+	hash.Write([]byte(r.Name))
+	hash.Write([]byte(r.Description))
+	for _, policyName := range r.Policies {
 		hash.Write([]byte(policyName))
 	}
-	if a.Global {
-		hash.Write([]byte("global"))
-	} else {
-		hash.Write([]byte("local"))
-	}
 
 	// Finalize the hash
 	hashVal := hash.Sum(nil)
 
 	// Set and return the hash
-	a.Hash = hashVal
+	r.Hash = hashVal
 	return hashVal
 }
 
-func (a *ACLToken) Stub() *ACLTokenListStub {
-	return &ACLTokenListStub{
-		AccessorID:  a.AccessorID,
-		Name:        a.Name,
-		Type:        a.Type,
-		Policies:    a.Policies,
-		Global:      a.Global,
-		Hash:        a.Hash,
-		CreateTime:  a.CreateTime,
-		CreateIndex: a.CreateIndex,
-		ModifyIndex: a.ModifyIndex,
+func (r *ACLRole) Stub() *ACLRoleListStub {
+	return &ACLRoleListStub{
+		Name:        r.Name,
+		Description: r.Description,
+		Policies:    r.Policies,
+		Hash:        r.Hash,
+		CreateIndex: r.CreateIndex,
+		ModifyIndex: r.ModifyIndex,
 	}
 }
 
-// Validate is used to sanity check a token
+// Validate is used to sanity check a role
+func (r *ACLRole) Validate() error {
+	var mErr multierror.Error
+	if !validPolicyName.MatchString(r.Name) {
+		err := fmt.Errorf("invalid name '%s'", r.Name)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(r.Policies) == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("role missing policies"))
+	}
+	if len(r.Description) > maxPolicyDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxPolicyDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ACLRoleListStub is used to for listing ACL roles
+type ACLRoleListStub struct {
+	Name        string
+	Description string
+	Policies    []string
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLRoleListRequest is used to request a list of roles
+type ACLRoleListRequest struct {
+	QueryOptions
+}
+
+// ACLRoleSpecificRequest is used to query a specific role
+type ACLRoleSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// ACLRoleSetRequest is used to query a set of roles
+type ACLRoleSetRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// ACLRoleListResponse is used for a list request
+type ACLRoleListResponse struct {
+	Roles []*ACLRoleListStub
+	QueryMeta
+}
+
+// SingleACLRoleResponse is used to return a single role
+type SingleACLRoleResponse struct {
+	Role *ACLRole
+	QueryMeta
+}
+
+// ACLRoleSetResponse is used to return a set of roles
+type ACLRoleSetResponse struct {
+	Roles map[string]*ACLRole
+	QueryMeta
+}
+
+// ACLRoleDeleteRequest is used to delete a set of roles
+type ACLRoleDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// ACLRoleUpsertRequest is used to upsert a set of roles
+type ACLRoleUpsertRequest struct {
+	Roles []*ACLRole
+	WriteRequest
+}
+
+const (
+	// ACLAuthMethodTypeOIDC is the type of auth methods authenticating users
+	// with the OIDC authorization code flow
+	ACLAuthMethodTypeOIDC = "OIDC"
+
+	// ACLAuthMethodTypeJWT is the type of auth methods validating JWTs
+	// presented by machines
+	ACLAuthMethodTypeJWT = "JWT"
+
+	// ACLAuthMethodTokenLocalityLocal and ACLAuthMethodTokenLocalityGlobal
+	// determine whether the tokens minted by an auth method are local to
+	// the region or global.
+	ACLAuthMethodTokenLocalityLocal  = "local"
+	ACLAuthMethodTokenLocalityGlobal = "global"
+
+	// ACLBindingRuleBindTypePolicy and ACLBindingRuleBindTypeRole determine
+	// whether a binding rule grants a policy or a role.
+	ACLBindingRuleBindTypePolicy = "policy"
+	ACLBindingRuleBindTypeRole   = "role"
+)
+
+var (
+	// validClaimMappingName is the set of names claims may be mapped to
+	validClaimMappingName = regexp.MustCompile("^[a-zA-Z0-9_-]{1,128}$")
+)
+
+// ACLAuthMethod is used to configure how ACL tokens are obtained by
+// authenticating with an external identity provider
+type ACLAuthMethod struct {
+	Name          string // Unique name
+	Type          string // OIDC or JWT
+	TokenLocality string // Local or global tokens
+
+	// MaxTokenTTL is the lifetime of the tokens minted by the auth method
+	MaxTokenTTL time.Duration
+
+	// Default marks the auth method used by login when none is specified
+	Default bool
+
+	Config      *ACLAuthMethodConfig
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodConfig is the configuration of the identity provider used by
+// an auth method
+type ACLAuthMethodConfig struct {
+	// OIDCDiscoveryURL is the issuer URL of the OIDC provider. It is
+	// required by OIDC auth methods and may be used by JWT auth methods to
+	// discover the keys validating the tokens.
+	OIDCDiscoveryURL string
+
+	// OIDCClientID and OIDCClientSecret are the credentials of Nomad with
+	// the OIDC provider
+	OIDCClientID     string
+	OIDCClientSecret string
+
+	// OIDCScopes are the scopes requested in addition to "openid"
+	OIDCScopes []string
+
+	// AllowedRedirectURIs are the URIs the OIDC provider may redirect to
+	// once the user has authenticated
+	AllowedRedirectURIs []string
+
+	// BoundAudiences is the set of audiences of which one must be in the
+	// token. Defaults to the client ID for OIDC auth methods.
+	BoundAudiences []string
+
+	// BoundIssuer is the expected issuer of JWTs
+	BoundIssuer string
+
+	// JWKSURL is the URL of the key set validating JWTs
+	JWKSURL string
+
+	// JWTValidationPubKeys are the PEM encoded keys validating JWTs
+	JWTValidationPubKeys []string
+
+	// ClockSkewLeeway is the clock skew tolerated when validating the time
+	// based claims of the tokens
+	ClockSkewLeeway time.Duration
+
+	// ClaimMappings and ListClaimMappings map the claims of the token to
+	// the values and lists available to binding rules
+	ClaimMappings     map[string]string
+	ListClaimMappings map[string]string
+}
+
+// Canonicalize sets the defaults of the auth method
+func (a *ACLAuthMethod) Canonicalize() {
+	if a.TokenLocality == "" {
+		a.TokenLocality = ACLAuthMethodTokenLocalityLocal
+	}
+}
+
+// SetHash is used to compute and set the hash of the ACL auth method
+func (a *ACLAuthMethod) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(a.Name))
+	hash.Write([]byte(a.Type))
+	hash.Write([]byte(a.TokenLocality))
+	hash.Write([]byte(a.MaxTokenTTL.String()))
+	if a.Default {
+		hash.Write([]byte("default"))
+	}
+	if c := a.Config; c != nil {
+		hash.Write([]byte(c.OIDCDiscoveryURL))
+		hash.Write([]byte(c.OIDCClientID))
+		hash.Write([]byte(c.OIDCClientSecret))
+		hash.Write([]byte(c.BoundIssuer))
+		hash.Write([]byte(c.JWKSURL))
+		hash.Write([]byte(c.ClockSkewLeeway.String()))
+		for _, l := range [][]string{c.OIDCScopes, c.AllowedRedirectURIs, c.BoundAudiences, c.JWTValidationPubKeys} {
+			for _, s := range l {
+				hash.Write([]byte(s))
+			}
+		}
+		for _, m := range []map[string]string{c.ClaimMappings, c.ListClaimMappings} {
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				hash.Write([]byte(k))
+				hash.Write([]byte(m[k]))
+			}
+		}
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	a.Hash = hashVal
+	return hashVal
+}
+
+func (a *ACLAuthMethod) Stub() *ACLAuthMethodListStub {
+	return &ACLAuthMethodListStub{
+		Name:        a.Name,
+		Type:        a.Type,
+		Default:     a.Default,
+		Hash:        a.Hash,
+		CreateIndex: a.CreateIndex,
+		ModifyIndex: a.ModifyIndex,
+	}
+}
+
+// Validate is used to sanity check an auth method
+func (a *ACLAuthMethod) Validate() error {
+	var mErr multierror.Error
+	if !validPolicyName.MatchString(a.Name) {
+		err := fmt.Errorf("invalid name '%s'", a.Name)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	switch a.TokenLocality {
+	case ACLAuthMethodTokenLocalityLocal, ACLAuthMethodTokenLocalityGlobal:
+	default:
+		err := fmt.Errorf("token locality must be %q or %q", ACLAuthMethodTokenLocalityLocal, ACLAuthMethodTokenLocalityGlobal)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if a.MaxTokenTTL <= 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max token TTL must be positive"))
+	}
+
+	c := a.Config
+	if c == nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("auth method missing config"))
+		return mErr.ErrorOrNil()
+	}
+
+	switch a.Type {
+	case ACLAuthMethodTypeOIDC:
+		if c.OIDCDiscoveryURL == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("OIDC auth method requires a discovery URL"))
+		}
+		if c.OIDCClientID == "" || c.OIDCClientSecret == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("OIDC auth method requires a client ID and secret"))
+		}
+		if len(c.AllowedRedirectURIs) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("OIDC auth method requires allowed redirect URIs"))
+		}
+		if c.JWKSURL != "" || len(c.JWTValidationPubKeys) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("OIDC auth method keys are discovered from the provider"))
+		}
+	case ACLAuthMethodTypeJWT:
+		sources := 0
+		for _, set := range []bool{c.OIDCDiscoveryURL != "", c.JWKSURL != "", len(c.JWTValidationPubKeys) != 0} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("JWT auth method requires exactly one of a discovery URL, JWKS URL or validation public keys"))
+		}
+		if c.OIDCClientID != "" || c.OIDCClientSecret != "" || len(c.AllowedRedirectURIs) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("JWT auth method cannot set OIDC client settings"))
+		}
+		for i, key := range c.JWTValidationPubKeys {
+			if _, err := oidc.ParsePublicKeyPEM(key); err != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid validation public key %d: %v", i, err))
+			}
+		}
+	default:
+		err := fmt.Errorf("auth method type must be %q or %q", ACLAuthMethodTypeOIDC, ACLAuthMethodTypeJWT)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	if c.ClockSkewLeeway < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("clock skew leeway cannot be negative"))
+	}
+	for _, m := range []map[string]string{c.ClaimMappings, c.ListClaimMappings} {
+		for claim, name := range m {
+			if !validClaimMappingName.MatchString(name) {
+				err := fmt.Errorf("invalid name '%s' for claim %q", name, claim)
+				mErr.Errors = append(mErr.Errors, err)
+			}
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ACLAuthMethodListStub is used to for listing ACL auth methods
+type ACLAuthMethodListStub struct {
+	Name        string
+	Type        string
+	Default     bool
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodListRequest is used to request a list of auth methods
+type ACLAuthMethodListRequest struct {
+	QueryOptions
+}
+
+// ACLAuthMethodSpecificRequest is used to query a specific auth method
+type ACLAuthMethodSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// ACLAuthMethodSetRequest is used to query a set of auth methods
+type ACLAuthMethodSetRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// ACLAuthMethodListResponse is used for a list request
+type ACLAuthMethodListResponse struct {
+	AuthMethods []*ACLAuthMethodListStub
+	QueryMeta
+}
+
+// SingleACLAuthMethodResponse is used to return a single auth method
+type SingleACLAuthMethodResponse struct {
+	AuthMethod *ACLAuthMethod
+	QueryMeta
+}
+
+// ACLAuthMethodSetResponse is used to return a set of auth methods
+type ACLAuthMethodSetResponse struct {
+	AuthMethods map[string]*ACLAuthMethod
+	QueryMeta
+}
+
+// ACLAuthMethodDeleteRequest is used to delete a set of auth methods
+type ACLAuthMethodDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// ACLAuthMethodUpsertRequest is used to upsert a set of auth methods
+type ACLAuthMethodUpsertRequest struct {
+	AuthMethods []*ACLAuthMethod
+	WriteRequest
+}
+
+// ACLBindingRule is used to grant a policy or role to the tokens minted by an
+// auth method when the claims of the identity match its selector
+type ACLBindingRule struct {
+	ID          string // Unique ID (UUID)
+	Description string // Human readable
+	AuthMethod  string // Auth method the rule applies to
+
+	// Selector is matched against the mapped claims of the identity. The
+	// empty selector matches all identities.
+	Selector string
+
+	// BindType is whether BindName is a policy or a role
+	BindType string
+
+	// BindName is the name of the policy or role granted. It may
+	// interpolate the mapped claim values as ${value.<name>}.
+	BindName string
+
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// SetHash is used to compute and set the hash of the ACL binding rule
+func (b *ACLBindingRule) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(b.ID))
+	hash.Write([]byte(b.Description))
+	hash.Write([]byte(b.AuthMethod))
+	hash.Write([]byte(b.Selector))
+	hash.Write([]byte(b.BindType))
+	hash.Write([]byte(b.BindName))
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	b.Hash = hashVal
+	return hashVal
+}
+
+func (b *ACLBindingRule) Stub() *ACLBindingRuleListStub {
+	return &ACLBindingRuleListStub{
+		ID:          b.ID,
+		Description: b.Description,
+		AuthMethod:  b.AuthMethod,
+		BindType:    b.BindType,
+		BindName:    b.BindName,
+		Hash:        b.Hash,
+		CreateIndex: b.CreateIndex,
+		ModifyIndex: b.ModifyIndex,
+	}
+}
+
+// Validate is used to sanity check a binding rule
+func (b *ACLBindingRule) Validate() error {
+	var mErr multierror.Error
+	if b.AuthMethod == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("binding rule missing auth method"))
+	}
+	switch b.BindType {
+	case ACLBindingRuleBindTypePolicy, ACLBindingRuleBindTypeRole:
+	default:
+		err := fmt.Errorf("bind type must be %q or %q", ACLBindingRuleBindTypePolicy, ACLBindingRuleBindTypeRole)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if b.BindName == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("binding rule missing bind name"))
+	}
+	if _, err := oidc.ParseSelector(b.Selector); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid selector: %v", err))
+	}
+	if len(b.Description) > maxPolicyDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxPolicyDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ACLBindingRuleListStub is used to for listing ACL binding rules
+type ACLBindingRuleListStub struct {
+	ID          string
+	Description string
+	AuthMethod  string
+	BindType    string
+	BindName    string
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLBindingRuleListRequest is used to request a list of binding rules,
+// optionally only those of an auth method
+type ACLBindingRuleListRequest struct {
+	AuthMethod string
+	QueryOptions
+}
+
+// ACLBindingRuleSpecificRequest is used to query a specific binding rule
+type ACLBindingRuleSpecificRequest struct {
+	ID string
+	QueryOptions
+}
+
+// ACLBindingRuleSetRequest is used to query a set of binding rules
+type ACLBindingRuleSetRequest struct {
+	IDs []string
+	QueryOptions
+}
+
+// ACLBindingRuleListResponse is used for a list request
+type ACLBindingRuleListResponse struct {
+	BindingRules []*ACLBindingRuleListStub
+	QueryMeta
+}
+
+// SingleACLBindingRuleResponse is used to return a single binding rule
+type SingleACLBindingRuleResponse struct {
+	BindingRule *ACLBindingRule
+	QueryMeta
+}
+
+// ACLBindingRuleSetResponse is used to return a set of binding rules
+type ACLBindingRuleSetResponse struct {
+	BindingRules map[string]*ACLBindingRule
+	QueryMeta
+}
+
+// ACLBindingRuleDeleteRequest is used to delete a set of binding rules
+type ACLBindingRuleDeleteRequest struct {
+	IDs []string
+	WriteRequest
+}
+
+// ACLBindingRuleUpsertRequest is used to upsert a set of binding rules
+type ACLBindingRuleUpsertRequest struct {
+	BindingRules []*ACLBindingRule
+	WriteRequest
+}
+
+// ACLBindingRuleUpsertResponse is used to return the binding rules upserted
+type ACLBindingRuleUpsertResponse struct {
+	BindingRules []*ACLBindingRule
+	WriteMeta
+}
+
+// ACLOIDCAuthURLRequest is used to start the OIDC authorization code flow
+type ACLOIDCAuthURLRequest struct {
+	// AuthMethodName is the OIDC auth method to authenticate with
+	AuthMethodName string
+
+	// RedirectURI is the URI the provider redirects to once the user has
+	// authenticated. It must be allowed by the auth method.
+	RedirectURI string
+
+	// ClientNonce is a secret generated by the client that must be
+	// presented again to complete the authentication
+	ClientNonce string
+
+	WriteRequest
+}
+
+// ACLOIDCAuthURLResponse is used to return the URL the user visits to
+// authenticate with the OIDC provider
+type ACLOIDCAuthURLResponse struct {
+	AuthURL string
+	WriteMeta
+}
+
+// ACLOIDCCompleteAuthRequest is used to complete the OIDC authorization code
+// flow with the parameters the provider redirected to
+type ACLOIDCCompleteAuthRequest struct {
+	AuthMethodName string
+	ClientNonce    string
+	State          string
+	Code           string
+	RedirectURI    string
+	WriteRequest
+}
+
+// ACLLoginRequest is used to exchange a JWT for an ACL token
+type ACLLoginRequest struct {
+	AuthMethodName string
+	LoginToken     string
+	WriteRequest
+}
+
+// ACLLoginResponse is used to return the ACL token minted by an auth method
+type ACLLoginResponse struct {
+	ACLToken *ACLToken
+	WriteMeta
+}
+
+// ACLToken represents a client token which is used to Authenticate
+type ACLToken struct {
+	AccessorID string   // Public Accessor ID (UUID)
+	SecretID   string   // Secret ID, private (UUID)
+	Name       string   // Human friendly name
+	Type       string   // Client or Management
+	Policies   []string // Policies this token ties to
+	Roles      []string // Roles this token ties to
+	Global     bool     // Global or Region local
+	Hash       []byte
+	CreateTime time.Time // Time of creation
+
+	// ExpirationTime is the time after which the token is no longer valid.
+	// It is nil for tokens that never expire.
+	ExpirationTime *time.Time
+
+	// ExpirationTTL is a convenience field to set the ExpirationTime
+	// relative to the CreateTime when the token is created. It is not
+	// persisted.
+	ExpirationTTL time.Duration
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+var (
+	// AnonymousACLToken is used no SecretID is provided, and the
+	// request is made anonymously.
+	AnonymousACLToken = &ACLToken{
+		AccessorID: "anonymous",
+		Name:       "Anonymous Token",
+		Type:       ACLClientToken,
+		Policies:   []string{"anonymous"},
+		Global:     false,
+	}
+)
+
+type ACLTokenListStub struct {
+	AccessorID     string
+	Name           string
+	Type           string
+	Policies       []string
+	Roles          []string
+	Global         bool
+	Hash           []byte
+	CreateTime     time.Time
+	ExpirationTime *time.Time
+	CreateIndex    uint64
+	ModifyIndex    uint64
+}
+
+// SetHash is used to compute and set the hash of the ACL token
+func (a *ACLToken) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(a.Name))
+	hash.Write([]byte(a.Type))
+	for _, policyName := range a.Policies {
+		hash.Write([]byte(policyName))
+	}
+	for _, roleName := range a.Roles {
+		hash.Write([]byte(roleName))
+	}
+	if a.Global {
+		hash.Write([]byte("global"))
+	} else {
+		hash.Write([]byte("local"))
+	}
+	if a.ExpirationTime != nil {
+		hash.Write([]byte(a.ExpirationTime.UTC().String()))
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	a.Hash = hashVal
+	return hashVal
+}
+
+func (a *ACLToken) Stub() *ACLTokenListStub {
+	return &ACLTokenListStub{
+		AccessorID:     a.AccessorID,
+		Name:           a.Name,
+		Type:           a.Type,
+		Policies:       a.Policies,
+		Roles:          a.Roles,
+		Global:         a.Global,
+		Hash:           a.Hash,
+		CreateTime:     a.CreateTime,
+		ExpirationTime: a.ExpirationTime,
+		CreateIndex:    a.CreateIndex,
+		ModifyIndex:    a.ModifyIndex,
+	}
+}
+
+// Validate is used to sanity check a token
 func (a *ACLToken) Validate() error {
 	var mErr multierror.Error
 	if len(a.Name) > maxTokenNameLength {
@@ -7220,19 +8405,34 @@ func (a *ACLToken) Validate() error {
 	}
 	switch a.Type {
 	case ACLClientToken:
-		if len(a.Policies) == 0 {
-			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies"))
+		if len(a.Policies) == 0 && len(a.Roles) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies or roles"))
 		}
 	case ACLManagementToken:
-		if len(a.Policies) != 0 {
-			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with policies"))
+		if len(a.Policies) != 0 || len(a.Roles) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with policies or roles"))
 		}
 	default:
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("token type must be client or management"))
 	}
+	if a.ExpirationTTL < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("token expiration TTL cannot be negative"))
+	}
+	if a.ExpirationTTL != 0 && a.ExpirationTime != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("token cannot set both expiration time and TTL"))
+	}
 	return mErr.ErrorOrNil()
 }
 
+// IsExpired returns whether the token has expired at the given time. Tokens
+// without an expiration time never expire.
+func (a *ACLToken) IsExpired(t time.Time) bool {
+	if a == nil || a.ExpirationTime == nil {
+		return false
+	}
+	return a.ExpirationTime.Before(t)
+}
+
 // PolicySubset checks if a given set of policies is a subset of the token
 func (a *ACLToken) PolicySubset(policies []string) bool {
 	// Hot-path the management tokens, superset of all policies.
@@ -7251,6 +8451,24 @@ func (a *ACLToken) PolicySubset(policies []string) bool {
 	return true
 }
 
+// RoleSubset checks if a given set of roles is a subset of the token
+func (a *ACLToken) RoleSubset(roles []string) bool {
+	// Hot-path the management tokens, superset of all roles.
+	if a.Type == ACLManagementToken {
+		return true
+	}
+	associatedRoles := make(map[string]struct{}, len(a.Roles))
+	for _, role := range a.Roles {
+		associatedRoles[role] = struct{}{}
+	}
+	for _, role := range roles {
+		if _, ok := associatedRoles[role]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // ACLTokenListRequest is used to request a list of tokens
 type ACLTokenListRequest struct {
 	GlobalOnly bool
@@ -734,6 +734,34 @@ func TestTaskGroup_Validate(t *testing.T) {
 	if !strings.Contains(err.Error(), "System jobs should not have a reschedule policy") {
 		t.Fatalf("err: %s", err)
 	}
+
+	tg = &TaskGroup{
+		Volumes: map[string]*VolumeRequest{
+			"foo": {
+				Name: "foo",
+				Type: "nfs",
+			},
+		},
+		Tasks: []*Task{
+			{
+				Name: "task-a",
+				VolumeMounts: []*VolumeMount{
+					{Volume: "bar", Destination: "/"},
+				},
+			},
+		},
+	}
+	err = tg.Validate(&Job{})
+	for _, expected := range []string{
+		`Volume "foo" has unsupported type "nfs"`,
+		`Volume "foo" must have a source`,
+		`Volume mount references unknown volume "bar"`,
+		`Volume mount of "bar" can't be mounted at the task's root`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %s but found: %v", expected, err)
+		}
+	}
 }
 
 func TestTask_Validate(t *testing.T) {
@@ -1312,6 +1340,26 @@ func TestTask_Validate_Service_Check_CheckRestart(t *testing.T) {
 	assert.Nil(t, validCheckRestart.Validate())
 }
 
+func TestTask_Validate_MemoryMax(t *testing.T) {
+	task := &Task{
+		Name:   "web",
+		Driver: "docker",
+		Resources: &Resources{
+			CPU:         100,
+			MemoryMB:    100,
+			MemoryMaxMB: 200,
+		},
+		LogConfig: DefaultLogConfig(),
+	}
+	ephemeralDisk := DefaultEphemeralDisk()
+	require.NoError(t, task.Validate(ephemeralDisk))
+
+	task.Resources.MemoryMaxMB = 50
+	err := task.Validate(ephemeralDisk)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "MemoryMaxMB value (50) must be greater than or equal to MemoryMB value (100)")
+}
+
 func TestTask_Validate_LogConfig(t *testing.T) {
 	task := &Task{
 		LogConfig: DefaultLogConfig(),
@@ -1327,6 +1375,84 @@ func TestTask_Validate_LogConfig(t *testing.T) {
 	}
 }
 
+func TestLogConfig_Validate_RotateInterval(t *testing.T) {
+	l := DefaultLogConfig()
+	l.RotateInterval = time.Hour
+	l.Compress = true
+	require.NoError(t, l.Validate())
+
+	l.RotateInterval = time.Second
+	err := l.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "minimum rotate interval")
+
+	l.RotateInterval = -time.Hour
+	err = l.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can not be negative")
+}
+
+func TestLogSink_Validate(t *testing.T) {
+	cases := []struct {
+		Sink *LogSink
+		Err  string
+	}{
+		{
+			Sink: &LogSink{Type: LogSinkTypeSyslog, Address: "tcp://127.0.0.1:514"},
+		},
+		{
+			Sink: &LogSink{Type: LogSinkTypeSyslog},
+			Err:  "requires an address",
+		},
+		{
+			Sink: &LogSink{Type: LogSinkTypeSyslog, Address: "/dev/log"},
+			Err:  "scheme must be one of",
+		},
+		{
+			Sink: &LogSink{Type: LogSinkTypeJournald},
+		},
+		{
+			Sink: &LogSink{Type: LogSinkTypeJournald, Address: "tcp://127.0.0.1:514"},
+			Err:  "scheme must be one of",
+		},
+		{
+			Sink: &LogSink{Type: LogSinkTypeFluentd, Address: "127.0.0.1:24224"},
+		},
+		{
+			Sink: &LogSink{Type: LogSinkTypeFluentd, Address: "unix:///var/run/fluentd.sock"},
+		},
+		{
+			Sink: &LogSink{Type: LogSinkTypeFluentd, Address: "localhost"},
+			Err:  "invalid fluentd address",
+		},
+		{
+			Sink: &LogSink{Type: LogSinkTypeHTTP, Address: "https://logs.example.com", BatchSize: 10, BatchWait: time.Second},
+		},
+		{
+			Sink: &LogSink{Type: LogSinkTypeHTTP, Address: "ftp://logs.example.com"},
+			Err:  "scheme must be one of",
+		},
+		{
+			Sink: &LogSink{Type: LogSinkTypeHTTP, Address: "http://logs.example.com", BufferSize: -1},
+			Err:  "buffer size must be non-negative",
+		},
+		{
+			Sink: &LogSink{Type: "kafka"},
+			Err:  "unknown log sink type",
+		},
+	}
+
+	for _, c := range cases {
+		err := c.Sink.Validate()
+		if c.Err == "" {
+			require.NoError(t, err, "%#v", c.Sink)
+			continue
+		}
+		require.Error(t, err, "%#v", c.Sink)
+		require.Contains(t, err.Error(), c.Err)
+	}
+}
+
 func TestTask_Validate_Template(t *testing.T) {
 
 	bad := &Template{}
@@ -3448,6 +3574,31 @@ func TestACLTokenValidate(t *testing.T) {
 	tk.Name = "foo"
 	err = tk.Validate()
 	assert.Nil(t, err)
+
+	// Negative TTL
+	tk.ExpirationTTL = -time.Hour
+	err = tk.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cannot be negative")
+
+	// Both TTL and expiration time
+	expiry := time.Now().Add(time.Hour)
+	tk.ExpirationTTL = time.Hour
+	tk.ExpirationTime = &expiry
+	err = tk.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "both expiration time and TTL")
+}
+
+func TestACLTokenIsExpired(t *testing.T) {
+	now := time.Now()
+	tk := &ACLToken{}
+	assert.False(t, tk.IsExpired(now))
+
+	expiry := now.Add(time.Hour)
+	tk.ExpirationTime = &expiry
+	assert.False(t, tk.IsExpired(now))
+	assert.True(t, tk.IsExpired(now.Add(2*time.Hour)))
 }
 
 func TestACLTokenPolicySubset(t *testing.T) {
@@ -3514,6 +3665,151 @@ func TestACLPolicySetHash(t *testing.T) {
 	assert.NotEqual(t, out1, out2)
 }
 
+func TestACLRoleValidate(t *testing.T) {
+	role := &ACLRole{Name: "bad name"}
+
+	err := role.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid name")
+	assert.Contains(t, err.Error(), "missing policies")
+
+	role.Name = "ops"
+	role.Policies = []string{"node-read"}
+	assert.Nil(t, role.Validate())
+}
+
+func TestACLRoleSetHash(t *testing.T) {
+	role := &ACLRole{
+		Name:        "ops",
+		Description: "operators",
+		Policies:    []string{"foo", "bar"},
+	}
+	out1 := role.SetHash()
+	assert.NotNil(t, out1)
+	assert.Equal(t, out1, role.Hash)
+
+	role.Policies = []string{"foo"}
+	out2 := role.SetHash()
+	assert.Equal(t, out2, role.Hash)
+	assert.NotEqual(t, out1, out2)
+}
+
+func TestACLTokenRoleSubset(t *testing.T) {
+	tk := &ACLToken{
+		Type:  ACLClientToken,
+		Roles: []string{"foo", "bar"},
+	}
+
+	assert.True(t, tk.RoleSubset([]string{"foo", "bar"}))
+	assert.True(t, tk.RoleSubset([]string{"foo"}))
+	assert.True(t, tk.RoleSubset([]string{}))
+	assert.False(t, tk.RoleSubset([]string{"foo", "new"}))
+
+	tk = &ACLToken{
+		Type: ACLManagementToken,
+	}
+	assert.True(t, tk.RoleSubset([]string{"foo", "new"}))
+}
+
+func TestACLAuthMethodValidate(t *testing.T) {
+	method := &ACLAuthMethod{Name: "bad name", Type: "ldap"}
+	method.Canonicalize()
+
+	err := method.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid name")
+	assert.Contains(t, err.Error(), "max token TTL must be positive")
+	assert.Contains(t, err.Error(), "missing config")
+
+	method.Name = "okta"
+	method.MaxTokenTTL = time.Hour
+	method.Config = &ACLAuthMethodConfig{}
+	err = method.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "auth method type must be")
+
+	// OIDC auth methods require the provider and client settings
+	method.Type = ACLAuthMethodTypeOIDC
+	err = method.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "requires a discovery URL")
+	assert.Contains(t, err.Error(), "requires a client ID and secret")
+	assert.Contains(t, err.Error(), "requires allowed redirect URIs")
+
+	method.Config = &ACLAuthMethodConfig{
+		OIDCDiscoveryURL:    "https://example.okta.com",
+		OIDCClientID:        "nomad",
+		OIDCClientSecret:    "secret",
+		AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+		ListClaimMappings:   map[string]string{"groups": "bad name"},
+	}
+	err = method.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid name 'bad name' for claim")
+
+	method.Config.ListClaimMappings = map[string]string{"groups": "groups"}
+	assert.Nil(t, method.Validate())
+
+	// JWT auth methods require exactly one source of keys
+	method.Type = ACLAuthMethodTypeJWT
+	method.Config = &ACLAuthMethodConfig{
+		JWKSURL:              "https://example.com/keys",
+		JWTValidationPubKeys: []string{"not a key"},
+	}
+	err = method.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "requires exactly one of")
+	assert.Contains(t, err.Error(), "invalid validation public key 0")
+
+	method.Config.JWTValidationPubKeys = nil
+	assert.Nil(t, method.Validate())
+}
+
+func TestACLAuthMethodSetHash(t *testing.T) {
+	method := &ACLAuthMethod{
+		Name:        "okta",
+		Type:        ACLAuthMethodTypeOIDC,
+		MaxTokenTTL: time.Hour,
+		Config: &ACLAuthMethodConfig{
+			OIDCDiscoveryURL: "https://example.okta.com",
+			ClaimMappings:    map[string]string{"a": "a", "b": "b"},
+		},
+	}
+	out1 := method.SetHash()
+	assert.NotNil(t, out1)
+	assert.Equal(t, out1, method.Hash)
+
+	// The hash is stable across map iteration orders
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, out1, method.SetHash())
+	}
+
+	method.Config.ClaimMappings["c"] = "c"
+	out2 := method.SetHash()
+	assert.Equal(t, out2, method.Hash)
+	assert.NotEqual(t, out1, out2)
+}
+
+func TestACLBindingRuleValidate(t *testing.T) {
+	rule := &ACLBindingRule{
+		BindType: "token",
+		Selector: "groups contains admins",
+	}
+
+	err := rule.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "missing auth method")
+	assert.Contains(t, err.Error(), "bind type must be")
+	assert.Contains(t, err.Error(), "missing bind name")
+	assert.Contains(t, err.Error(), "invalid selector")
+
+	rule.AuthMethod = "okta"
+	rule.BindType = ACLBindingRuleBindTypeRole
+	rule.BindName = "team-${value.team}"
+	rule.Selector = `"admins" in list.groups`
+	assert.Nil(t, rule.Validate())
+}
+
 func TestTaskEventPopulate(t *testing.T) {
 	prepopulatedEvent := NewTaskEvent(TaskSetup)
 	prepopulatedEvent.DisplayMessage = "Hola"
@@ -3817,3 +4113,93 @@ func TestNode_Copy(t *testing.T) {
 	require.Equal(node.DrainStrategy, node2.DrainStrategy)
 	require.Equal(node.Drivers, node2.Drivers)
 }
+
+func TestNewJobPlacementStatus(t *testing.T) {
+	require := require.New(t)
+
+	summary := &JobSummary{
+		JobID:     "example",
+		Namespace: DefaultNamespace,
+		Summary: map[string]TaskGroupSummary{
+			"web":   {Queued: 2},
+			"cache": {Running: 1},
+		},
+	}
+
+	// An older failure, the latest failure and the blocked follow up
+	old := &Evaluation{
+		ID:          uuid.Generate(),
+		Status:      EvalStatusComplete,
+		ModifyIndex: 10,
+		FailedTGAllocs: map[string]*AllocMetric{
+			"web": {NodesEvaluated: 1},
+		},
+	}
+	failed := &Evaluation{
+		ID:          uuid.Generate(),
+		Status:      EvalStatusComplete,
+		ModifyIndex: 20,
+		FailedTGAllocs: map[string]*AllocMetric{
+			"web": {
+				NodesEvaluated: 10,
+				NodesFiltered:  4,
+				NodesExhausted: 6,
+				ConstraintFiltered: map[string]int{
+					"${attr.kernel.name} = windows": 1,
+					"${node.class} = gpu":           3,
+				},
+				DimensionExhausted: map[string]int{
+					"memory": 4,
+					"cpu":    2,
+				},
+				ClassExhausted: map[string]int{"batch": 6},
+			},
+			"cache": {NodesEvaluated: 10},
+		},
+	}
+	blocked := &Evaluation{
+		ID:          uuid.Generate(),
+		Status:      EvalStatusBlocked,
+		ModifyIndex: 21,
+		ClassEligibility: map[string]bool{
+			"v1:1": true,
+			"v1:2": false,
+			"v1:3": true,
+		},
+		QuotaLimitReached: "team-a",
+	}
+
+	nodeClasses := map[string]string{
+		"v1:1": "batch",
+		"v1:2": "gpu",
+	}
+	status := NewJobPlacementStatus(DefaultNamespace, "example", summary,
+		[]*Evaluation{blocked, old, failed}, nodeClasses)
+
+	require.Equal(failed.ID, status.EvalID)
+	require.Equal(blocked.ID, status.BlockedEvalID)
+	require.Equal([]string{"batch", "v1:3"}, status.WaitingClasses)
+	require.Equal("team-a", status.QuotaLimitReached)
+
+	// The cache group has been placed since
+	require.Len(status.TaskGroups, 1)
+	web := status.TaskGroups["web"]
+	require.NotNil(web)
+	require.Equal(2, web.Queued)
+	require.Equal(10, web.Metrics.NodesEvaluated)
+	require.Equal([]*PlacementReason{
+		{Reason: "memory", Nodes: 4},
+		{Reason: "cpu", Nodes: 2},
+	}, web.ExhaustedDimensions)
+	require.Equal([]*PlacementReason{
+		{Reason: "${node.class} = gpu", Nodes: 3},
+		{Reason: "${attr.kernel.name} = windows", Nodes: 1},
+	}, web.FilteringConstraints)
+	require.Equal([]*PlacementReason{{Reason: "batch", Nodes: 6}}, web.ExhaustedClasses)
+	require.Nil(web.FilteredClasses)
+
+	// Jobs without failures have nothing to explain
+	status = NewJobPlacementStatus(DefaultNamespace, "example", summary, nil, nil)
+	require.Empty(status.EvalID)
+	require.Empty(status.TaskGroups)
+}
@@ -0,0 +1,291 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// VariablesJobPathPrefix is the path prefix under which variables that
+	// are readable by a job's allocations are stored. A job's variables live
+	// at "nomad/jobs/<job id>" and below.
+	VariablesJobPathPrefix = "nomad/jobs"
+
+	// maxVariablePathLength limits the length of a variable's path
+	maxVariablePathLength = 128
+
+	// maxVariableSize limits the combined size of a variable's items
+	maxVariableSize = 64 * 1024
+)
+
+var (
+	// validVariablePath is used to validate a variable path
+	validVariablePath = regexp.MustCompile("^[a-zA-Z0-9-_~/]+$")
+)
+
+// VarOp is the operation applied to a variable in the state store.
+type VarOp string
+
+const (
+	// VarOpSet creates or overwrites a variable
+	VarOpSet VarOp = "set"
+
+	// VarOpDelete deletes a variable
+	VarOpDelete VarOp = "delete"
+
+	// VarOpCAS creates or overwrites a variable only if its modify index
+	// matches the check index. A check index of zero requires the variable
+	// not to exist.
+	VarOpCAS VarOp = "cas"
+
+	// VarOpDeleteCAS deletes a variable only if its modify index matches the
+	// check index.
+	VarOpDeleteCAS VarOp = "delete-cas"
+)
+
+// VarOpResult is the outcome of applying a VarOp.
+type VarOpResult string
+
+const (
+	VarOpResultOk       VarOpResult = "ok"
+	VarOpResultConflict VarOpResult = "conflict"
+)
+
+// VariableMetadata is the non-secret portion of a variable. It is what is
+// returned when listing variables.
+type VariableMetadata struct {
+	Namespace   string
+	Path        string
+	CreateTime  int64
+	CreateIndex uint64
+	ModifyTime  int64
+	ModifyIndex uint64
+}
+
+// VariableItems are the key/value pairs stored in a variable
+type VariableItems map[string]string
+
+// Size returns the number of bytes used by the items' keys and values
+func (vi VariableItems) Size() int {
+	var out int
+	for k, v := range vi {
+		out += len(k) + len(v)
+	}
+	return out
+}
+
+// VariableDecrypted is a variable whose items are in plain text. It is never
+// written to the state store.
+type VariableDecrypted struct {
+	VariableMetadata
+	Items VariableItems
+}
+
+// Copy returns a deep copy of the decrypted variable
+func (v *VariableDecrypted) Copy() *VariableDecrypted {
+	if v == nil {
+		return nil
+	}
+	nv := new(VariableDecrypted)
+	*nv = *v
+	if v.Items != nil {
+		nv.Items = make(VariableItems, len(v.Items))
+		for k, val := range v.Items {
+			nv.Items[k] = val
+		}
+	}
+	return nv
+}
+
+// Canonicalize sets defaults on the variable
+func (v *VariableDecrypted) Canonicalize() {
+	if v.Namespace == "" {
+		v.Namespace = DefaultNamespace
+	}
+	v.Path = CanonicalVariablePath(v.Path)
+}
+
+// CanonicalVariablePath returns the canonical form of a variable path, which
+// is the form variables are stored and authorized under.
+func CanonicalVariablePath(path string) string {
+	return strings.Trim(path, "/")
+}
+
+// Validate is used to sanity check a variable before it is stored
+func (v *VariableDecrypted) Validate() error {
+	var mErr multierror.Error
+	if err := ValidateVariablePath(v.Path); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(v.Items) == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("variable must contain at least one item"))
+	}
+	for k := range v.Items {
+		if k == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("variable item keys must not be empty"))
+			break
+		}
+	}
+	if size := v.Items.Size(); size > maxVariableSize {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("variable items exceed maximum size of %d bytes (%d)", maxVariableSize, size))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ValidateVariablePath checks that a variable path is well formed
+func ValidateVariablePath(path string) error {
+	switch {
+	case path == "":
+		return fmt.Errorf("variable path must not be empty")
+	case len(path) > maxVariablePathLength:
+		return fmt.Errorf("variable path longer than %d", maxVariablePathLength)
+	case !validVariablePath.MatchString(path):
+		return fmt.Errorf("invalid variable path %q", path)
+	case strings.Contains(path, "//"):
+		return fmt.Errorf("variable path %q contains an empty segment", path)
+	}
+	return nil
+}
+
+// VariableData is the encrypted form of a variable's items
+type VariableData struct {
+	// Data is the AES-GCM sealed, JSON encoded items
+	Data []byte
+
+	// KeyID is the ID of the root key used to encrypt the data
+	KeyID string
+}
+
+// VariableEncrypted is a variable as it is stored in the state store.
+type VariableEncrypted struct {
+	VariableMetadata
+	VariableData
+}
+
+// Copy returns a deep copy of the encrypted variable
+func (v *VariableEncrypted) Copy() *VariableEncrypted {
+	if v == nil {
+		return nil
+	}
+	nv := new(VariableEncrypted)
+	*nv = *v
+	if v.Data != nil {
+		nv.Data = make([]byte, len(v.Data))
+		copy(nv.Data, v.Data)
+	}
+	return nv
+}
+
+// VariablePathForJob returns the variable path under which the variables of a
+// job are stored.
+func VariablePathForJob(jobID string) string {
+	return VariablesJobPathPrefix + "/" + jobID
+}
+
+// VariablePathAllowedForJob returns whether allocations of the given job may
+// read the variable at the given path.
+func VariablePathAllowedForJob(path, jobID string) bool {
+	jobPath := VariablePathForJob(jobID)
+	return path == jobPath || strings.HasPrefix(path, jobPath+"/")
+}
+
+// VariablesApplyRequest is used to create, update or delete a variable
+type VariablesApplyRequest struct {
+	Op  VarOp
+	Var *VariableDecrypted
+	WriteRequest
+}
+
+// VariablesApplyResponse is the response to a VariablesApplyRequest
+type VariablesApplyResponse struct {
+	Op     VarOp
+	Input  *VariableDecrypted
+	Result VarOpResult
+
+	// Conflict is the existing variable when the operation failed a
+	// check-and-set. Its items are omitted if the caller is not allowed to
+	// read it.
+	Conflict *VariableDecrypted
+
+	// Output is the variable as written
+	Output *VariableDecrypted
+	WriteMeta
+}
+
+// IsOk returns whether the operation was applied
+func (r *VariablesApplyResponse) IsOk() bool {
+	return r.Result == VarOpResultOk
+}
+
+// IsConflict returns whether the operation failed a check-and-set
+func (r *VariablesApplyResponse) IsConflict() bool {
+	return r.Result == VarOpResultConflict
+}
+
+// VarApplyStateRequest is the raft log entry for a variable operation
+type VarApplyStateRequest struct {
+	Op  VarOp
+	Var *VariableEncrypted
+	WriteRequest
+}
+
+// VarApplyStateResponse is returned by the FSM when applying a
+// VarApplyStateRequest
+type VarApplyStateResponse struct {
+	Op       VarOp
+	Result   VarOpResult
+	Conflict *VariableEncrypted
+	Output   *VariableEncrypted
+	WriteMeta
+}
+
+// IsOk returns whether the operation was applied
+func (r *VarApplyStateResponse) IsOk() bool {
+	return r.Result == VarOpResultOk
+}
+
+// IsConflict returns whether the operation failed a check-and-set
+func (r *VarApplyStateResponse) IsConflict() bool {
+	return r.Result == VarOpResultConflict
+}
+
+// VariablesListRequest is used to list the variables of a namespace
+type VariablesListRequest struct {
+	QueryOptions
+}
+
+// VariablesListResponse is the response to a VariablesListRequest
+type VariablesListResponse struct {
+	Data []*VariableMetadata
+	QueryMeta
+}
+
+// VariablesReadRequest is used to read a single variable
+type VariablesReadRequest struct {
+	Path string
+	QueryOptions
+}
+
+// VariablesReadResponse is the response to a VariablesReadRequest
+type VariablesReadResponse struct {
+	Data *VariableDecrypted
+	QueryMeta
+}
+
+// AllocVariablesRequest is used by clients to read the variables available
+// to an allocation.
+type AllocVariablesRequest struct {
+	NodeID   string
+	SecretID string
+	AllocID  string
+	QueryOptions
+}
+
+// AllocVariablesResponse is the response to an AllocVariablesRequest
+type AllocVariablesResponse struct {
+	Variables []*VariableDecrypted
+	QueryMeta
+}
@@ -0,0 +1,163 @@
+package structs
+
+import (
+	"fmt"
+	"path/filepath"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// VolumeTypeHost is the type of volumes declared by clients in their
+	// configuration.
+	VolumeTypeHost = "host"
+
+	// VolumeTypeCSI is the type of volumes provided by CSI plugins and
+	// registered with the servers.
+	VolumeTypeCSI = "csi"
+)
+
+// ClientHostVolumeConfig is used to configure access to host paths on a Nomad
+// Client
+type ClientHostVolumeConfig struct {
+	Name     string `hcl:",key" mapstructure:"-"`
+	Path     string `hcl:"path" mapstructure:"path"`
+	ReadOnly bool   `hcl:"read_only" mapstructure:"read_only"`
+}
+
+func (p *ClientHostVolumeConfig) Copy() *ClientHostVolumeConfig {
+	if p == nil {
+		return nil
+	}
+
+	c := new(ClientHostVolumeConfig)
+	*c = *p
+	return c
+}
+
+// CopyMapStringClientHostVolumeConfig returns a deep copy of the host volumes.
+func CopyMapStringClientHostVolumeConfig(m map[string]*ClientHostVolumeConfig) map[string]*ClientHostVolumeConfig {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(map[string]*ClientHostVolumeConfig, len(m))
+	for k, v := range m {
+		nm[k] = v.Copy()
+	}
+	return nm
+}
+
+// VolumeRequest is a request for a volume made by a task group. The volume can
+// be mounted by the group's tasks.
+type VolumeRequest struct {
+	// Name is the name the tasks use to refer to the volume.
+	Name string
+
+	// Type is the type of the volume, either host or csi.
+	Type string
+
+	// Source is the name of the volume on the client for host volumes, or the
+	// ID of the registered volume for CSI volumes.
+	Source string
+
+	// ReadOnly requests the volume to be read only. A writable volume can only
+	// be placed on clients that don't declare the volume read only.
+	ReadOnly bool
+}
+
+func (v *VolumeRequest) Copy() *VolumeRequest {
+	if v == nil {
+		return nil
+	}
+
+	nv := new(VolumeRequest)
+	*nv = *v
+	return nv
+}
+
+// Validate is used to sanity check a volume request
+func (v *VolumeRequest) Validate() error {
+	var mErr multierror.Error
+	if v.Name == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Volume must have a name"))
+	}
+	switch v.Type {
+	case VolumeTypeHost, VolumeTypeCSI:
+	case "":
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Volume %q must have a type", v.Name))
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Volume %q has unsupported type %q", v.Name, v.Type))
+	}
+	if v.Source == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Volume %q must have a source", v.Name))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// CopyMapVolumeRequest returns a deep copy of the volume requests.
+func CopyMapVolumeRequest(m map[string]*VolumeRequest) map[string]*VolumeRequest {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(map[string]*VolumeRequest, len(m))
+	for k, v := range m {
+		nm[k] = v.Copy()
+	}
+	return nm
+}
+
+// VolumeMount mounts a volume of the task group into a task.
+type VolumeMount struct {
+	// Volume is the name of the task group's volume to mount.
+	Volume string
+
+	// Destination is the path the volume is mounted at, relative to the
+	// task's root.
+	Destination string
+
+	// ReadOnly mounts the volume read only.
+	ReadOnly bool
+}
+
+func (v *VolumeMount) Copy() *VolumeMount {
+	if v == nil {
+		return nil
+	}
+
+	nv := new(VolumeMount)
+	*nv = *v
+	return nv
+}
+
+// Validate is used to sanity check a volume mount against the volumes of the
+// task group.
+func (v *VolumeMount) Validate(volumes map[string]*VolumeRequest) error {
+	var mErr multierror.Error
+	if v.Volume == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Volume mount must reference a volume"))
+	} else if _, ok := volumes[v.Volume]; !ok {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Volume mount references unknown volume %q", v.Volume))
+	}
+	if v.Destination == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Volume mount of %q must have a destination", v.Volume))
+	} else if filepath.Clean(v.Destination) == "/" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Volume mount of %q can't be mounted at the task's root", v.Volume))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// CopySliceVolumeMount returns a deep copy of the volume mounts.
+func CopySliceVolumeMount(s []*VolumeMount) []*VolumeMount {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	c := make([]*VolumeMount, l)
+	for i, v := range s {
+		c[i] = v.Copy()
+	}
+	return c
+}
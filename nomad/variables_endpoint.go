@@ -0,0 +1,312 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Variables endpoint is used for reading and writing variables
+type Variables struct {
+	srv *Server
+}
+
+// Apply is used to create, update or delete a variable
+func (v *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.VariablesApplyResponse) error {
+	if done, err := v.srv.forward("Variables.Apply", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "apply"}, time.Now())
+
+	if args.Var == nil {
+		return fmt.Errorf("missing variable")
+	}
+	args.Var.Namespace = args.RequestNamespace()
+	args.Var.Canonicalize()
+
+	// Determine the capability required by the operation
+	var cap string
+	switch args.Op {
+	case structs.VarOpSet, structs.VarOpCAS:
+		cap = acl.VariablesCapabilityWrite
+		if err := args.Var.Validate(); err != nil {
+			return err
+		}
+	case structs.VarOpDelete, structs.VarOpDeleteCAS:
+		cap = acl.VariablesCapabilityDestroy
+		if err := structs.ValidateVariablePath(args.Var.Path); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown variable operation %q", args.Op)
+	}
+
+	aclObj, err := v.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowVariableOperation(args.Var.Namespace, args.Var.Path, cap) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Build the encrypted form of the variable
+	sv := &structs.VariableEncrypted{
+		VariableMetadata: args.Var.VariableMetadata,
+	}
+	sv.ModifyTime = time.Now().UTC().UnixNano()
+	if args.Op == structs.VarOpSet || args.Op == structs.VarOpCAS {
+		if err := v.encrypt(args.Var.Items, sv); err != nil {
+			return err
+		}
+	}
+
+	req := &structs.VarApplyStateRequest{
+		Op:           args.Op,
+		Var:          sv,
+		WriteRequest: args.WriteRequest,
+	}
+	out, index, err := v.srv.raftApply(structs.VarApplyStateRequestType, req)
+	if err != nil {
+		v.srv.logger.Printf("[ERR] nomad.variables: Apply failed: %v", err)
+		return err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+	resp := out.(*structs.VarApplyStateResponse)
+
+	reply.Op = args.Op
+	reply.Input = args.Var
+	reply.Result = resp.Result
+	reply.Index = index
+
+	if resp.IsConflict() {
+		// Only include the conflicting variable's items if they may be read
+		reply.Conflict = &structs.VariableDecrypted{}
+		if resp.Conflict != nil {
+			reply.Conflict.VariableMetadata = resp.Conflict.VariableMetadata
+			if resp.Conflict.Data != nil && (aclObj == nil ||
+				aclObj.AllowVariableOperation(args.Var.Namespace, args.Var.Path, acl.VariablesCapabilityRead)) {
+				if reply.Conflict, err = v.decrypt(resp.Conflict); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if resp.Output != nil {
+		reply.Output = &structs.VariableDecrypted{
+			VariableMetadata: resp.Output.VariableMetadata,
+			Items:            args.Var.Items,
+		}
+	}
+	return nil
+}
+
+// Read is used to read a single variable
+func (v *Variables) Read(args *structs.VariablesReadRequest, reply *structs.VariablesReadResponse) error {
+	if done, err := v.srv.forward("Variables.Read", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "read"}, time.Now())
+
+	// Canonicalize the path so it is authorized as it is stored
+	args.Path = structs.CanonicalVariablePath(args.Path)
+
+	ns := args.RequestNamespace()
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowVariableOperation(ns, args.Path, acl.VariablesCapabilityRead) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			out, err := state.VarGet(ws, ns, args.Path)
+			if err != nil {
+				return err
+			}
+
+			reply.Data = nil
+			if out != nil {
+				if reply.Data, err = v.decrypt(out); err != nil {
+					return err
+				}
+			}
+
+			// Use the last index that affected the variables table
+			index, err := state.Index("variables")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// List is used to list the metadata of the variables in a namespace. Only the
+// variables the token may list are returned.
+func (v *Variables) List(args *structs.VariablesListRequest, reply *structs.VariablesListResponse) error {
+	if done, err := v.srv.forward("Variables.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "list"}, time.Now())
+
+	ns := args.RequestNamespace()
+	aclObj, err := v.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowVariableList(ns) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.VarsByNamespaceAndPrefix(ws, ns, args.Prefix)
+			if err != nil {
+				return err
+			}
+
+			reply.Data = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				sv := raw.(*structs.VariableEncrypted)
+				if aclObj != nil && !aclObj.AllowVariableOperation(ns, sv.Path, acl.VariablesCapabilityList) {
+					continue
+				}
+				meta := sv.VariableMetadata
+				reply.Data = append(reply.Data, &meta)
+			}
+
+			// Use the last index that affected the variables table
+			index, err := state.Index("variables")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// ReadAllocVariables is used by clients to read the variables that an
+// allocation may access. These are the variables stored under the path of the
+// allocation's job in the allocation's namespace.
+func (v *Variables) ReadAllocVariables(args *structs.AllocVariablesRequest, reply *structs.AllocVariablesResponse) error {
+	if done, err := v.srv.forward("Variables.ReadAllocVariables", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "read_alloc_variables"}, time.Now())
+
+	// Verify that the node exists with the given SecretID and that the
+	// allocation is running on it
+	snap, err := v.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	alloc, err := nodeAllocByID(snap, args.NodeID, args.SecretID, args.AllocID)
+	if err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			prefix := structs.VariablePathForJob(alloc.JobID)
+			iter, err := state.VarsByNamespaceAndPrefix(ws, alloc.Namespace, prefix)
+			if err != nil {
+				return err
+			}
+
+			reply.Variables = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				sv := raw.(*structs.VariableEncrypted)
+				if !structs.VariablePathAllowedForJob(sv.Path, alloc.JobID) {
+					continue
+				}
+				out, err := v.decrypt(sv)
+				if err != nil {
+					return err
+				}
+				reply.Variables = append(reply.Variables, out)
+			}
+
+			// Use the last index that affected the variables table
+			index, err := state.Index("variables")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// encrypt encrypts the items into the given variable
+func (v *Variables) encrypt(items structs.VariableItems, sv *structs.VariableEncrypted) error {
+	buf, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	sv.Data, sv.KeyID, err = v.srv.encrypter.Encrypt(buf)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt variable: %v", err)
+	}
+	return nil
+}
+
+// decrypt returns the decrypted form of the stored variable
+func (v *Variables) decrypt(sv *structs.VariableEncrypted) (*structs.VariableDecrypted, error) {
+	buf, err := v.srv.encrypter.Decrypt(sv.Data, sv.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt variable %q: %v", sv.Path, err)
+	}
+
+	out := &structs.VariableDecrypted{
+		VariableMetadata: sv.VariableMetadata,
+	}
+	if err := json.Unmarshal(buf, &out.Items); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,173 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariablesEndpoint_Apply_Read(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	sv := &structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{Path: "app/db"},
+		Items:            structs.VariableItems{"password": "hunter2"},
+	}
+	apply := &structs.VariablesApplyRequest{
+		Op:           structs.VarOpSet,
+		Var:          sv,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var applyResp structs.VariablesApplyResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Apply", apply, &applyResp))
+	require.True(applyResp.IsOk())
+	require.NotZero(applyResp.Index)
+
+	// The stored variable is encrypted
+	stored, err := s1.fsm.State().VarGet(nil, structs.DefaultNamespace, "app/db")
+	require.NoError(err)
+	require.NotEmpty(stored.KeyID)
+	require.NotContains(string(stored.Data), "hunter2")
+
+	read := &structs.VariablesReadRequest{
+		Path:         "app/db",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp))
+	require.Equal(sv.Items, readResp.Data.Items)
+	require.Equal(applyResp.Index, readResp.Index)
+
+	// A check-and-set against a stale index conflicts
+	apply.Op = structs.VarOpCAS
+	apply.Var.ModifyIndex = 1
+	var casResp structs.VariablesApplyResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Apply", apply, &casResp))
+	require.True(casResp.IsConflict())
+	require.Equal(sv.Items, casResp.Conflict.Items)
+}
+
+func TestVariablesEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	policy := `
+namespace "default" {
+	variables {
+		path "app/*" {
+			capabilities = ["read", "list"]
+		}
+	}
+}`
+	token := mock.CreatePolicyAndToken(t, s1.fsm.State(), 1001, "vars", policy)
+
+	for _, path := range []string{"app/db", "secret/db"} {
+		apply := &structs.VariablesApplyRequest{
+			Op: structs.VarOpSet,
+			Var: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{Path: path},
+				Items:            structs.VariableItems{"k": "v"},
+			},
+			WriteRequest: structs.WriteRequest{Region: "global", AuthToken: root.SecretID},
+		}
+		var resp structs.VariablesApplyResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Apply", apply, &resp))
+	}
+
+	// The token may not write
+	apply := &structs.VariablesApplyRequest{
+		Op: structs.VarOpSet,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{Path: "app/db"},
+			Items:            structs.VariableItems{"k": "v2"},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: token.SecretID},
+	}
+	var applyResp structs.VariablesApplyResponse
+	err := msgpackrpc.CallWithCodec(codec, "Variables.Apply", apply, &applyResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// The token may only read matching paths
+	read := &structs.VariablesReadRequest{
+		Path:         "secret/db",
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var readResp structs.VariablesReadResponse
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	read.Path = "app/db"
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp))
+	require.Equal("v", readResp.Data.Items["k"])
+
+	// Listing filters the paths the token may not list
+	list := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var listResp structs.VariablesListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.List", list, &listResp))
+	require.Len(listResp.Data, 1)
+	require.Equal("app/db", listResp.Data[0].Path)
+}
+
+func TestVariablesEndpoint_ReadAllocVariables(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1000, node))
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	require.NoError(state.UpsertAllocs(1001, []*structs.Allocation{alloc}))
+
+	for _, path := range []string{
+		structs.VariablePathForJob(alloc.JobID),
+		structs.VariablePathForJob(alloc.JobID) + "/web",
+		structs.VariablePathForJob(alloc.JobID) + "-other",
+		"app/db",
+	} {
+		apply := &structs.VariablesApplyRequest{
+			Op: structs.VarOpSet,
+			Var: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{Path: path},
+				Items:            structs.VariableItems{"k": "v"},
+			},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.VariablesApplyResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Apply", apply, &resp))
+	}
+
+	req := &structs.AllocVariablesRequest{
+		NodeID:       node.ID,
+		SecretID:     node.SecretID,
+		AllocID:      alloc.ID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.AllocVariablesResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.ReadAllocVariables", req, &resp))
+	require.Len(resp.Variables, 2)
+
+	// A bad secret is rejected
+	req.SecretID = "foo"
+	err := msgpackrpc.CallWithCodec(codec, "Variables.ReadAllocVariables", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+}
@@ -12,9 +12,9 @@ type BasePlugin interface {
 	// ConfigSchema returns the schema for parsing the plugins configuration.
 	ConfigSchema() (*hclspec.Spec, error)
 
-	// SetConfig is used to set the configuration by passing a MessagePack
-	// encoding of it.
-	SetConfig(data []byte) error
+	// SetConfig is used to set the configuration of the plugin and the
+	// version of the plugin API negotiated by Nomad.
+	SetConfig(c *Config) error
 }
 
 // PluginInfoResponse returns basic information about the plugin such that Nomad
@@ -23,9 +23,9 @@ type PluginInfoResponse struct {
 	// Type returns the plugins type
 	Type string
 
-	// PluginApiVersion returns the version of the Nomad plugin API it is built
-	// against.
-	PluginApiVersion string
+	// PluginApiVersions returns the versions of the Nomad plugin API the
+	// plugin supports.
+	PluginApiVersions []string
 
 	// PluginVersion is the version of the plugin.
 	PluginVersion string
@@ -33,3 +33,14 @@ type PluginInfoResponse struct {
 	// Name is the plugins name.
 	Name string
 }
+
+// Config contains the configuration passed to the plugin.
+type Config struct {
+	// PluginConfig is the MessagePack encoding of the plugin's configuration,
+	// decoded using the plugin's ConfigSchema.
+	PluginConfig []byte
+
+	// ApiVersion is the version of the plugin API negotiated by Nomad. It is
+	// one of the versions returned by PluginInfo.
+	ApiVersion string
+}
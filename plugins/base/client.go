@@ -8,14 +8,15 @@ import (
 	"github.com/hashicorp/nomad/plugins/shared/hclspec"
 )
 
-// basePluginClient implements the client side of a remote base plugin, using
-// gRPC to communicate to the remote plugin.
-type basePluginClient struct {
-	client proto.BasePluginClient
+// BasePluginClient implements the client side of a remote base plugin, using
+// gRPC to communicate to the remote plugin. It is embedded by the clients of
+// the other plugin types.
+type BasePluginClient struct {
+	Client proto.BasePluginClient
 }
 
-func (b *basePluginClient) PluginInfo() (*PluginInfoResponse, error) {
-	presp, err := b.client.PluginInfo(context.Background(), &proto.PluginInfoRequest{})
+func (b *BasePluginClient) PluginInfo() (*PluginInfoResponse, error) {
+	presp, err := b.Client.PluginInfo(context.Background(), &proto.PluginInfoRequest{})
 	if err != nil {
 		return nil, err
 	}
@@ -31,17 +32,17 @@ func (b *basePluginClient) PluginInfo() (*PluginInfoResponse, error) {
 	}
 
 	resp := &PluginInfoResponse{
-		Type:             ptype,
-		PluginApiVersion: presp.GetPluginApiVersion(),
-		PluginVersion:    presp.GetPluginVersion(),
-		Name:             presp.GetName(),
+		Type:              ptype,
+		PluginApiVersions: presp.GetPluginApiVersions(),
+		PluginVersion:     presp.GetPluginVersion(),
+		Name:              presp.GetName(),
 	}
 
 	return resp, nil
 }
 
-func (b *basePluginClient) ConfigSchema() (*hclspec.Spec, error) {
-	presp, err := b.client.ConfigSchema(context.Background(), &proto.ConfigSchemaRequest{})
+func (b *BasePluginClient) ConfigSchema() (*hclspec.Spec, error) {
+	presp, err := b.Client.ConfigSchema(context.Background(), &proto.ConfigSchemaRequest{})
 	if err != nil {
 		return nil, err
 	}
@@ -49,10 +50,11 @@ func (b *basePluginClient) ConfigSchema() (*hclspec.Spec, error) {
 	return presp.GetSpec(), nil
 }
 
-func (b *basePluginClient) SetConfig(data []byte) error {
+func (b *BasePluginClient) SetConfig(c *Config) error {
 	// Send the config
-	_, err := b.client.SetConfig(context.Background(), &proto.SetConfigRequest{
-		MsgpackConfig: data,
+	_, err := b.Client.SetConfig(context.Background(), &proto.SetConfigRequest{
+		MsgpackConfig:    c.PluginConfig,
+		PluginApiVersion: c.ApiVersion,
 	})
 
 	return err
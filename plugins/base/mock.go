@@ -10,9 +10,9 @@ import (
 type MockPlugin struct {
 	PluginInfoF   func() (*PluginInfoResponse, error)
 	ConfigSchemaF func() (*hclspec.Spec, error)
-	SetConfigF    func([]byte) error
+	SetConfigF    func(*Config) error
 }
 
 func (p *MockPlugin) PluginInfo() (*PluginInfoResponse, error) { return p.PluginInfoF() }
 func (p *MockPlugin) ConfigSchema() (*hclspec.Spec, error)     { return p.ConfigSchemaF() }
-func (p *MockPlugin) SetConfig(data []byte) error              { return p.SetConfigF(data) }
+func (p *MockPlugin) SetConfig(c *Config) error                { return p.SetConfigF(c) }
@@ -66,19 +66,19 @@ func TestBasePlugin_PluginInfo_GRPC(t *testing.T) {
 
 	knownType := func() (*PluginInfoResponse, error) {
 		info := &PluginInfoResponse{
-			Type:             PluginTypeDriver,
-			PluginApiVersion: apiVersion,
-			PluginVersion:    pluginVersion,
-			Name:             pluginName,
+			Type:              PluginTypeDriver,
+			PluginApiVersions: []string{apiVersion},
+			PluginVersion:     pluginVersion,
+			Name:              pluginName,
 		}
 		return info, nil
 	}
 	unknownType := func() (*PluginInfoResponse, error) {
 		info := &PluginInfoResponse{
-			Type:             "bad",
-			PluginApiVersion: apiVersion,
-			PluginVersion:    pluginVersion,
-			Name:             pluginName,
+			Type:              "bad",
+			PluginApiVersions: []string{apiVersion},
+			PluginVersion:     pluginVersion,
+			Name:              pluginName,
 		}
 		return info, nil
 	}
@@ -88,7 +88,7 @@ func TestBasePlugin_PluginInfo_GRPC(t *testing.T) {
 	}
 
 	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
-		"base": &PluginBase{impl: mock},
+		"base": &PluginBase{Impl: mock},
 	})
 	defer server.Stop()
 	defer client.Close()
@@ -105,7 +105,7 @@ func TestBasePlugin_PluginInfo_GRPC(t *testing.T) {
 
 	resp, err := impl.PluginInfo()
 	require.NoError(err)
-	require.Equal(apiVersion, resp.PluginApiVersion)
+	require.Equal([]string{apiVersion}, resp.PluginApiVersions)
 	require.Equal(pluginVersion, resp.PluginVersion)
 	require.Equal(pluginName, resp.Name)
 	require.Equal(PluginTypeDriver, resp.Type)
@@ -128,7 +128,7 @@ func TestBasePlugin_ConfigSchema(t *testing.T) {
 	}
 
 	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
-		"base": &PluginBase{impl: mock},
+		"base": &PluginBase{Impl: mock},
 	})
 	defer server.Stop()
 	defer client.Close()
@@ -152,19 +152,19 @@ func TestBasePlugin_SetConfig(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
 
-	var receivedData []byte
+	var received *Config
 	mock := &MockPlugin{
 		ConfigSchemaF: func() (*hclspec.Spec, error) {
 			return testSpec, nil
 		},
-		SetConfigF: func(data []byte) error {
-			receivedData = data
+		SetConfigF: func(c *Config) error {
+			received = c
 			return nil
 		},
 	}
 
 	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
-		"base": &PluginBase{impl: mock},
+		"base": &PluginBase{Impl: mock},
 	})
 	defer server.Stop()
 	defer client.Close()
@@ -186,12 +186,13 @@ func TestBasePlugin_SetConfig(t *testing.T) {
 	})
 	cdata, err := msgpack.Marshal(config, config.Type())
 	require.NoError(err)
-	require.NoError(impl.SetConfig(cdata))
-	require.Equal(cdata, receivedData)
+	require.NoError(impl.SetConfig(&Config{PluginConfig: cdata, ApiVersion: "v0.1.0"}))
+	require.Equal(cdata, received.PluginConfig)
+	require.Equal("v0.1.0", received.ApiVersion)
 
 	// Decode the value back
 	var actual testConfig
-	require.NoError(structs.Decode(receivedData, &actual))
+	require.NoError(structs.Decode(received.PluginConfig, &actual))
 	require.Equal("v1", actual.Foo)
 	require.EqualValues(1337, actual.Bar)
 	require.True(actual.Baz)
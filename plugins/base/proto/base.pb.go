@@ -48,7 +48,7 @@ func (x PluginType) String() string {
 	return proto.EnumName(PluginType_name, int32(x))
 }
 func (PluginType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_base_9cc78dc32b158b08, []int{0}
+	return fileDescriptor_base_5a448780a4a9be3e, []int{0}
 }
 
 // PluginInfoRequest is used to request the plugins basic information.
@@ -62,7 +62,7 @@ func (m *PluginInfoRequest) Reset()         { *m = PluginInfoRequest{} }
 func (m *PluginInfoRequest) String() string { return proto.CompactTextString(m) }
 func (*PluginInfoRequest) ProtoMessage()    {}
 func (*PluginInfoRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_base_9cc78dc32b158b08, []int{0}
+	return fileDescriptor_base_5a448780a4a9be3e, []int{0}
 }
 func (m *PluginInfoRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PluginInfoRequest.Unmarshal(m, b)
@@ -87,9 +87,9 @@ var xxx_messageInfo_PluginInfoRequest proto.InternalMessageInfo
 type PluginInfoResponse struct {
 	// type indicates what type of plugin this is.
 	Type PluginType `protobuf:"varint,1,opt,name=type,proto3,enum=hashicorp.nomad.plugins.base.proto.PluginType" json:"type,omitempty"`
-	// plugin_api_version indicates the version of the Nomad Plugin API
-	// this plugin is built against.
-	PluginApiVersion string `protobuf:"bytes,2,opt,name=plugin_api_version,json=pluginApiVersion,proto3" json:"plugin_api_version,omitempty"`
+	// plugin_api_versions indicates the versions of the Nomad Plugin API
+	// this plugin supports.
+	PluginApiVersions []string `protobuf:"bytes,2,rep,name=plugin_api_versions,json=pluginApiVersions,proto3" json:"plugin_api_versions,omitempty"`
 	// plugin_version is the semver version of this individual plugin.
 	// This is divorce from Nomad’s development and versioning.
 	PluginVersion string `protobuf:"bytes,3,opt,name=plugin_version,json=pluginVersion,proto3" json:"plugin_version,omitempty"`
@@ -104,7 +104,7 @@ func (m *PluginInfoResponse) Reset()         { *m = PluginInfoResponse{} }
 func (m *PluginInfoResponse) String() string { return proto.CompactTextString(m) }
 func (*PluginInfoResponse) ProtoMessage()    {}
 func (*PluginInfoResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_base_9cc78dc32b158b08, []int{1}
+	return fileDescriptor_base_5a448780a4a9be3e, []int{1}
 }
 func (m *PluginInfoResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PluginInfoResponse.Unmarshal(m, b)
@@ -131,11 +131,11 @@ func (m *PluginInfoResponse) GetType() PluginType {
 	return PluginType_UNKNOWN
 }
 
-func (m *PluginInfoResponse) GetPluginApiVersion() string {
+func (m *PluginInfoResponse) GetPluginApiVersions() []string {
 	if m != nil {
-		return m.PluginApiVersion
+		return m.PluginApiVersions
 	}
-	return ""
+	return nil
 }
 
 func (m *PluginInfoResponse) GetPluginVersion() string {
@@ -163,7 +163,7 @@ func (m *ConfigSchemaRequest) Reset()         { *m = ConfigSchemaRequest{} }
 func (m *ConfigSchemaRequest) String() string { return proto.CompactTextString(m) }
 func (*ConfigSchemaRequest) ProtoMessage()    {}
 func (*ConfigSchemaRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_base_9cc78dc32b158b08, []int{2}
+	return fileDescriptor_base_5a448780a4a9be3e, []int{2}
 }
 func (m *ConfigSchemaRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ConfigSchemaRequest.Unmarshal(m, b)
@@ -196,7 +196,7 @@ func (m *ConfigSchemaResponse) Reset()         { *m = ConfigSchemaResponse{} }
 func (m *ConfigSchemaResponse) String() string { return proto.CompactTextString(m) }
 func (*ConfigSchemaResponse) ProtoMessage()    {}
 func (*ConfigSchemaResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_base_9cc78dc32b158b08, []int{3}
+	return fileDescriptor_base_5a448780a4a9be3e, []int{3}
 }
 func (m *ConfigSchemaResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ConfigSchemaResponse.Unmarshal(m, b)
@@ -226,7 +226,10 @@ func (m *ConfigSchemaResponse) GetSpec() *hclspec.Spec {
 // SetConfigRequest is used to set the configuration
 type SetConfigRequest struct {
 	// msgpack_config is the configuration encoded as MessagePack.
-	MsgpackConfig        []byte   `protobuf:"bytes,1,opt,name=msgpack_config,json=msgpackConfig,proto3" json:"msgpack_config,omitempty"`
+	MsgpackConfig []byte `protobuf:"bytes,1,opt,name=msgpack_config,json=msgpackConfig,proto3" json:"msgpack_config,omitempty"`
+	// plugin_api_version is the version of the Nomad Plugin API negotiated
+	// with the plugin, which must be one of the versions it supports.
+	PluginApiVersion     string   `protobuf:"bytes,2,opt,name=plugin_api_version,json=pluginApiVersion,proto3" json:"plugin_api_version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -236,7 +239,7 @@ func (m *SetConfigRequest) Reset()         { *m = SetConfigRequest{} }
 func (m *SetConfigRequest) String() string { return proto.CompactTextString(m) }
 func (*SetConfigRequest) ProtoMessage()    {}
 func (*SetConfigRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_base_9cc78dc32b158b08, []int{4}
+	return fileDescriptor_base_5a448780a4a9be3e, []int{4}
 }
 func (m *SetConfigRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetConfigRequest.Unmarshal(m, b)
@@ -263,6 +266,13 @@ func (m *SetConfigRequest) GetMsgpackConfig() []byte {
 	return nil
 }
 
+func (m *SetConfigRequest) GetPluginApiVersion() string {
+	if m != nil {
+		return m.PluginApiVersion
+	}
+	return ""
+}
+
 // SetConfigResponse is used to respond to setting the configuration
 type SetConfigResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -274,7 +284,7 @@ func (m *SetConfigResponse) Reset()         { *m = SetConfigResponse{} }
 func (m *SetConfigResponse) String() string { return proto.CompactTextString(m) }
 func (*SetConfigResponse) ProtoMessage()    {}
 func (*SetConfigResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_base_9cc78dc32b158b08, []int{5}
+	return fileDescriptor_base_5a448780a4a9be3e, []int{5}
 }
 func (m *SetConfigResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetConfigResponse.Unmarshal(m, b)
@@ -449,37 +459,38 @@ var _BasePlugin_serviceDesc = grpc.ServiceDesc{
 }
 
 func init() {
-	proto.RegisterFile("github.com/hashicorp/nomad/plugins/base/proto/base.proto", fileDescriptor_base_9cc78dc32b158b08)
-}
-
-var fileDescriptor_base_9cc78dc32b158b08 = []byte{
-	// 436 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x92, 0xcf, 0x8b, 0xd3, 0x40,
-	0x14, 0xc7, 0x37, 0xdb, 0xb8, 0xcb, 0xbe, 0xdd, 0x2d, 0x71, 0xaa, 0x50, 0x72, 0x2a, 0x01, 0xa1,
-	0x48, 0x99, 0x60, 0xb5, 0x5a, 0x6f, 0xb5, 0xb5, 0x87, 0x22, 0x54, 0x49, 0xb5, 0x8a, 0x97, 0x30,
-	0x9d, 0x4e, 0x93, 0x60, 0x93, 0x19, 0x33, 0xa9, 0x50, 0xc1, 0x93, 0x67, 0xff, 0x28, 0xff, 0x33,
-	0xc9, 0x4c, 0xd2, 0x46, 0x51, 0x4c, 0x4f, 0x79, 0xbc, 0xf7, 0xf9, 0x7e, 0xe7, 0xfd, 0x08, 0x0c,
-	0x83, 0x28, 0x0b, 0x77, 0x2b, 0x4c, 0x79, 0xec, 0x86, 0x44, 0x86, 0x11, 0xe5, 0xa9, 0x70, 0x13,
-	0x1e, 0x93, 0xb5, 0x2b, 0xb6, 0xbb, 0x20, 0x4a, 0xa4, 0xbb, 0x22, 0x92, 0xb9, 0x22, 0xe5, 0x19,
-	0x57, 0x21, 0x56, 0x21, 0x72, 0x0e, 0x38, 0x56, 0x38, 0x2e, 0x70, 0x7c, 0x64, 0xec, 0x51, 0x0d,
-	0x77, 0x19, 0x92, 0x94, 0xad, 0xdd, 0x90, 0x6e, 0xa5, 0x60, 0x34, 0xff, 0xfa, 0x79, 0xa0, 0x1d,
-	0x9c, 0x16, 0xdc, 0x7d, 0xa3, 0xc0, 0x59, 0xb2, 0xe1, 0x1e, 0xfb, 0xbc, 0x63, 0x32, 0x73, 0x7e,
-	0x1a, 0x80, 0xaa, 0x59, 0x29, 0x78, 0x22, 0x19, 0x1a, 0x83, 0x99, 0xed, 0x05, 0x6b, 0x1b, 0x1d,
-	0xa3, 0xdb, 0xec, 0x63, 0xfc, 0xff, 0x06, 0xb1, 0x76, 0x79, 0xbb, 0x17, 0xcc, 0x53, 0x5a, 0xd4,
-	0x03, 0xa4, 0x31, 0x9f, 0x88, 0xc8, 0xff, 0xc2, 0x52, 0x19, 0xf1, 0xa4, 0x7d, 0xde, 0x31, 0xba,
-	0x57, 0x9e, 0xa5, 0x2b, 0x2f, 0x44, 0xb4, 0xd4, 0x79, 0xf4, 0x00, 0x9a, 0x05, 0x5d, 0x92, 0x0d,
-	0x45, 0xde, 0xea, 0x6c, 0x89, 0x21, 0x30, 0x13, 0x12, 0xb3, 0xb6, 0xa9, 0x8a, 0x2a, 0x76, 0xee,
-	0x43, 0x6b, 0xc2, 0x93, 0x4d, 0x14, 0x2c, 0x68, 0xc8, 0x62, 0x52, 0x8e, 0xf6, 0x01, 0xee, 0xfd,
-	0x9e, 0x2e, 0x66, 0x1b, 0x81, 0x99, 0x6f, 0x45, 0xcd, 0x76, 0xdd, 0xef, 0xfd, 0x73, 0x36, 0xbd,
-	0x4d, 0x5c, 0x6c, 0x13, 0x2f, 0x04, 0xa3, 0x9e, 0x52, 0x3a, 0xcf, 0xc1, 0x5a, 0xb0, 0x4c, 0x9b,
-	0x17, 0xaf, 0xe5, 0xfd, 0xc7, 0x32, 0x10, 0x84, 0x7e, 0xf2, 0xa9, 0x2a, 0x28, 0xff, 0x1b, 0xef,
-	0xb6, 0xc8, 0x6a, 0x3a, 0x3f, 0x42, 0x45, 0xaa, 0x3b, 0x7a, 0xf8, 0x08, 0xe0, 0xb8, 0x3d, 0x74,
-	0x0d, 0x97, 0xef, 0xe6, 0xaf, 0xe6, 0xaf, 0xdf, 0xcf, 0xad, 0x33, 0x04, 0x70, 0xf1, 0xd2, 0x9b,
-	0x2d, 0xa7, 0x9e, 0x75, 0xae, 0xe2, 0xe9, 0x72, 0x36, 0x99, 0x5a, 0x8d, 0xfe, 0x8f, 0x06, 0xc0,
-	0x98, 0x48, 0xa6, 0x75, 0xe8, 0x5b, 0xe9, 0x90, 0x5f, 0x11, 0x0d, 0xea, 0xdf, 0xab, 0xf2, 0x2f,
-	0xd8, 0x4f, 0x4f, 0x95, 0xe9, 0xf6, 0x9d, 0x33, 0xf4, 0xdd, 0x80, 0x9b, 0xea, 0xae, 0xd1, 0xb3,
-	0x3a, 0x56, 0x7f, 0x39, 0x9a, 0x3d, 0x3c, 0x5d, 0x78, 0xe8, 0xe2, 0x2b, 0x5c, 0x1d, 0x76, 0x8b,
-	0x9e, 0xd4, 0x31, 0xfa, 0xf3, 0x8a, 0xf6, 0xe0, 0x44, 0x55, 0xf9, 0xf6, 0xf8, 0xf2, 0xe3, 0x1d,
-	0x55, 0x5c, 0x5d, 0xa8, 0xcf, 0xe3, 0x5f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x0b, 0x29, 0x9a, 0xb0,
-	0x0e, 0x04, 0x00, 0x00,
+	proto.RegisterFile("github.com/hashicorp/nomad/plugins/base/proto/base.proto", fileDescriptor_base_5a448780a4a9be3e)
+}
+
+var fileDescriptor_base_5a448780a4a9be3e = []byte{
+	// 450 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xad, 0x93, 0xd0, 0x2a, 0xd3, 0x0f, 0xb9, 0x13, 0x90, 0xac, 0x9c, 0x22, 0x4b, 0x48, 0x11,
+	0xaa, 0xd6, 0x22, 0x50, 0xe8, 0xb1, 0xa4, 0xe4, 0x10, 0x21, 0x05, 0xb4, 0x81, 0x80, 0xb8, 0x44,
+	0x9b, 0xed, 0xd6, 0xb6, 0xa8, 0xbd, 0x4b, 0xd6, 0x41, 0x2a, 0x12, 0x27, 0xce, 0xfc, 0x2a, 0xfe,
+	0x18, 0xf2, 0xee, 0x36, 0x31, 0x05, 0x84, 0x73, 0xf2, 0x68, 0xe6, 0xbd, 0xb7, 0x33, 0xef, 0x19,
+	0xce, 0xe2, 0xb4, 0x48, 0x56, 0x0b, 0xc2, 0x65, 0x16, 0x25, 0x4c, 0x27, 0x29, 0x97, 0x4b, 0x15,
+	0xe5, 0x32, 0x63, 0x97, 0x91, 0xba, 0x5e, 0xc5, 0x69, 0xae, 0xa3, 0x05, 0xd3, 0x22, 0x52, 0x4b,
+	0x59, 0x48, 0x53, 0x12, 0x53, 0x62, 0xb8, 0x86, 0x13, 0x03, 0x27, 0x0e, 0x4e, 0x36, 0x98, 0xee,
+	0x79, 0x0d, 0x75, 0x9d, 0xb0, 0xa5, 0xb8, 0x8c, 0x12, 0x7e, 0xad, 0x95, 0xe0, 0xe5, 0x77, 0x5e,
+	0x16, 0x56, 0x21, 0xec, 0xc0, 0xf1, 0x1b, 0x03, 0x1c, 0xe7, 0x57, 0x92, 0x8a, 0xcf, 0x2b, 0xa1,
+	0x8b, 0xf0, 0xa7, 0x07, 0x58, 0xed, 0x6a, 0x25, 0x73, 0x2d, 0x70, 0x08, 0xad, 0xe2, 0x46, 0x89,
+	0xc0, 0xeb, 0x79, 0xfd, 0xa3, 0x01, 0x21, 0xff, 0x5f, 0x90, 0x58, 0x95, 0xb7, 0x37, 0x4a, 0x50,
+	0xc3, 0x45, 0x02, 0x1d, 0x0b, 0x9b, 0x33, 0x95, 0xce, 0xbf, 0x88, 0xa5, 0x4e, 0x65, 0xae, 0x83,
+	0x46, 0xaf, 0xd9, 0x6f, 0xd3, 0x63, 0x3b, 0x7a, 0xa1, 0xd2, 0x99, 0x1b, 0xe0, 0x43, 0x38, 0x72,
+	0x78, 0x87, 0x0d, 0x9a, 0x3d, 0xaf, 0xdf, 0xa6, 0x87, 0xb6, 0xeb, 0x70, 0x88, 0xd0, 0xca, 0x59,
+	0x26, 0x82, 0x96, 0x19, 0x9a, 0x3a, 0x7c, 0x00, 0x9d, 0x0b, 0x99, 0x5f, 0xa5, 0xf1, 0x94, 0x27,
+	0x22, 0x63, 0xb7, 0xc7, 0x7d, 0x80, 0xfb, 0xbf, 0xb7, 0xdd, 0x75, 0xe7, 0xd0, 0x2a, 0x7d, 0x31,
+	0xd7, 0xed, 0x0f, 0x4e, 0xfe, 0x79, 0x9d, 0xf5, 0x93, 0x38, 0x3f, 0xc9, 0x54, 0x09, 0x4e, 0x0d,
+	0x33, 0x8c, 0xc1, 0x9f, 0x8a, 0xc2, 0x8a, 0xbb, 0xd7, 0xca, 0xfd, 0x33, 0x1d, 0x2b, 0xc6, 0x3f,
+	0xcd, 0xb9, 0x19, 0x18, 0xfd, 0x03, 0x7a, 0xe8, 0xba, 0x16, 0x8d, 0x27, 0x80, 0x7f, 0xda, 0x12,
+	0x34, 0xcc, 0x35, 0xfe, 0x5d, 0x57, 0xca, 0xd0, 0x2a, 0x0f, 0xd9, 0xfd, 0x1f, 0x3d, 0x06, 0xd8,
+	0xb8, 0x8d, 0xfb, 0xb0, 0xf7, 0x6e, 0xf2, 0x6a, 0xf2, 0xfa, 0xfd, 0xc4, 0xdf, 0x41, 0x80, 0xdd,
+	0x97, 0x74, 0x3c, 0x1b, 0x51, 0xbf, 0x61, 0xea, 0xd1, 0x6c, 0x7c, 0x31, 0xf2, 0x9b, 0x83, 0x1f,
+	0x4d, 0x80, 0x21, 0xd3, 0xc2, 0xf2, 0xf0, 0x1b, 0xc0, 0x26, 0x75, 0x3c, 0xad, 0x9f, 0x6f, 0xe5,
+	0xdf, 0xe9, 0x3e, 0xdb, 0x96, 0x66, 0xd7, 0x0f, 0x77, 0xf0, 0xbb, 0x07, 0x07, 0xd5, 0x64, 0xf0,
+	0x79, 0x1d, 0xa9, 0xbf, 0x44, 0xdc, 0x3d, 0xdb, 0x9e, 0xb8, 0xde, 0xe2, 0x2b, 0xb4, 0xd7, 0xde,
+	0xe2, 0xd3, 0x3a, 0x42, 0x77, 0x33, 0xef, 0x9e, 0x6e, 0xc9, 0xba, 0x7d, 0x7b, 0xb8, 0xf7, 0xf1,
+	0x9e, 0x19, 0x2e, 0x76, 0xcd, 0xe7, 0xc9, 0xaf, 0x01, 0x00, 0xc5, 0x7c, 0xe8, 0x7f, 0x3e, 0x04,
+	0x00, 0x00,
 }
@@ -32,10 +32,10 @@ func (b *basePluginServer) PluginInfo(context.Context, *proto.PluginInfoRequest)
 	}
 
 	presp := &proto.PluginInfoResponse{
-		Type:             ptype,
-		PluginApiVersion: resp.PluginApiVersion,
-		PluginVersion:    resp.PluginVersion,
-		Name:             resp.Name,
+		Type:              ptype,
+		PluginApiVersions: resp.PluginApiVersions,
+		PluginVersion:     resp.PluginVersion,
+		Name:              resp.Name,
 	}
 
 	return presp, nil
@@ -56,7 +56,11 @@ func (b *basePluginServer) ConfigSchema(context.Context, *proto.ConfigSchemaRequ
 
 func (b *basePluginServer) SetConfig(ctx context.Context, req *proto.SetConfigRequest) (*proto.SetConfigResponse, error) {
 	// Set the config
-	if err := b.impl.SetConfig(req.GetMsgpackConfig()); err != nil {
+	c := &Config{
+		PluginConfig: req.GetMsgpackConfig(),
+		ApiVersion:   req.GetPluginApiVersion(),
+	}
+	if err := b.impl.SetConfig(c); err != nil {
 		return nil, fmt.Errorf("SetConfig failed: %v", err)
 	}
 
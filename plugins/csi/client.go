@@ -0,0 +1,252 @@
+package csi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	csipbv1 "github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+)
+
+// client implements the CSIPlugin interface over the gRPC services of a
+// plugin listening on a unix socket.
+type client struct {
+	conn             *grpc.ClientConn
+	identityClient   csipbv1.IdentityClient
+	controllerClient csipbv1.ControllerClient
+	nodeClient       csipbv1.NodeClient
+}
+
+// NewClient returns a client for the plugin listening on the given unix
+// socket.
+func NewClient(addr string) (CSIPlugin, error) {
+	if addr == "" {
+		return nil, fmt.Errorf("address is empty")
+	}
+
+	conn, err := newGrpcConn(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client{
+		conn:             conn,
+		identityClient:   csipbv1.NewIdentityClient(conn),
+		controllerClient: csipbv1.NewControllerClient(conn),
+		nodeClient:       csipbv1.NewNodeClient(conn),
+	}, nil
+}
+
+func newGrpcConn(addr string) (*grpc.ClientConn, error) {
+	conn, err := grpc.Dial(
+		addr,
+		grpc.WithInsecure(),
+		grpc.WithDialer(func(target string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", target, timeout)
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open grpc connection to addr: %s, err: %v", addr, err)
+	}
+
+	return conn, nil
+}
+
+func (c *client) Close() error {
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+func (c *client) PluginProbe(ctx context.Context) (bool, error) {
+	resp, err := c.identityClient.Probe(ctx, &csipbv1.ProbeRequest{})
+	if err != nil {
+		return false, err
+	}
+
+	// A plugin that doesn't report its readiness is ready
+	wrapper := resp.GetReady()
+	if wrapper == nil {
+		return true, nil
+	}
+	return wrapper.GetValue(), nil
+}
+
+func (c *client) PluginGetInfo(ctx context.Context) (string, string, error) {
+	resp, err := c.identityClient.GetPluginInfo(ctx, &csipbv1.GetPluginInfoRequest{})
+	if err != nil {
+		return "", "", err
+	}
+
+	name := resp.GetName()
+	if name == "" {
+		return "", "", fmt.Errorf("PluginGetInfo: plugin returned empty name field")
+	}
+	version := resp.GetVendorVersion()
+	if version == "" {
+		return "", "", fmt.Errorf("PluginGetInfo: plugin returned empty version field")
+	}
+
+	return name, version, nil
+}
+
+func (c *client) PluginGetCapabilities(ctx context.Context) (*PluginCapabilitySet, error) {
+	resp, err := c.identityClient.GetPluginCapabilities(ctx, &csipbv1.GetPluginCapabilitiesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPluginCapabilitySet(resp), nil
+}
+
+//
+// Controller Endpoints
+//
+
+func (c *client) ControllerGetCapabilities(ctx context.Context) (*ControllerCapabilitySet, error) {
+	resp, err := c.controllerClient.ControllerGetCapabilities(ctx, &csipbv1.ControllerGetCapabilitiesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewControllerCapabilitySet(resp), nil
+}
+
+func (c *client) ControllerPublishVolume(ctx context.Context, req *ControllerPublishVolumeRequest) (*ControllerPublishVolumeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation error: %v", err)
+	}
+
+	resp, err := c.controllerClient.ControllerPublishVolume(ctx, req.ToCSIRepresentation())
+	if err != nil {
+		return nil, err
+	}
+
+	return &ControllerPublishVolumeResponse{
+		PublishContext: resp.GetPublishContext(),
+	}, nil
+}
+
+func (c *client) ControllerUnpublishVolume(ctx context.Context, volumeID, nodeID string) error {
+	if volumeID == "" {
+		return fmt.Errorf("missing VolumeID")
+	}
+	if nodeID == "" {
+		return fmt.Errorf("missing NodeID")
+	}
+
+	req := &csipbv1.ControllerUnpublishVolumeRequest{
+		VolumeId: volumeID,
+		NodeId:   nodeID,
+	}
+	_, err := c.controllerClient.ControllerUnpublishVolume(ctx, req)
+	return err
+}
+
+//
+// Node Endpoints
+//
+
+func (c *client) NodeGetCapabilities(ctx context.Context) (*NodeCapabilitySet, error) {
+	resp, err := c.nodeClient.NodeGetCapabilities(ctx, &csipbv1.NodeGetCapabilitiesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNodeCapabilitySet(resp), nil
+}
+
+func (c *client) NodeGetInfo(ctx context.Context) (*NodeGetInfoResponse, error) {
+	resp, err := c.nodeClient.NodeGetInfo(ctx, &csipbv1.NodeGetInfoRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.GetNodeId() == "" {
+		return nil, fmt.Errorf("plugin failed to return nodeid")
+	}
+
+	return &NodeGetInfoResponse{
+		NodeID:     resp.GetNodeId(),
+		MaxVolumes: resp.GetMaxVolumesPerNode(),
+	}, nil
+}
+
+func (c *client) NodeStageVolume(ctx context.Context, volumeID string, publishContext map[string]string, stagingTargetPath string, capabilities *VolumeCapability) error {
+	// These errors should not be returned during production use but exist as aids
+	// during Nomad Development
+	if volumeID == "" {
+		return fmt.Errorf("missing volumeID")
+	}
+	if stagingTargetPath == "" {
+		return fmt.Errorf("missing stagingTargetPath")
+	}
+
+	req := &csipbv1.NodeStageVolumeRequest{
+		VolumeId:          volumeID,
+		PublishContext:    publishContext,
+		StagingTargetPath: stagingTargetPath,
+		VolumeCapability:  capabilities.ToCSIRepresentation(),
+	}
+
+	// NodeStageVolume's response contains no extra data. If err == nil, we were
+	// successful.
+	_, err := c.nodeClient.NodeStageVolume(ctx, req)
+	return err
+}
+
+func (c *client) NodeUnstageVolume(ctx context.Context, volumeID string, stagingTargetPath string) error {
+	// These errors should not be returned during production use but exist as aids
+	// during Nomad Development
+	if volumeID == "" {
+		return fmt.Errorf("missing volumeID")
+	}
+	if stagingTargetPath == "" {
+		return fmt.Errorf("missing stagingTargetPath")
+	}
+
+	req := &csipbv1.NodeUnstageVolumeRequest{
+		VolumeId:          volumeID,
+		StagingTargetPath: stagingTargetPath,
+	}
+
+	// NodeUnstageVolume's response contains no extra data. If err == nil, we were
+	// successful.
+	_, err := c.nodeClient.NodeUnstageVolume(ctx, req)
+	return err
+}
+
+func (c *client) NodePublishVolume(ctx context.Context, req *NodePublishVolumeRequest) error {
+	if err := req.Validate(); err != nil {
+		return fmt.Errorf("validation error: %v", err)
+	}
+
+	// NodePublishVolume's response contains no extra data. If err == nil, we were
+	// successful.
+	_, err := c.nodeClient.NodePublishVolume(ctx, req.ToCSIRepresentation())
+	return err
+}
+
+func (c *client) NodeUnpublishVolume(ctx context.Context, volumeID, targetPath string) error {
+	// These errors should not be returned during production use but exist as aids
+	// during Nomad Development
+	if volumeID == "" {
+		return fmt.Errorf("missing volumeID")
+	}
+	if targetPath == "" {
+		return fmt.Errorf("missing targetPath")
+	}
+
+	req := &csipbv1.NodeUnpublishVolumeRequest{
+		VolumeId:   volumeID,
+		TargetPath: targetPath,
+	}
+
+	// NodeUnpublishVolume's response contains no extra data. If err == nil, we were
+	// successful.
+	_, err := c.nodeClient.NodeUnpublishVolume(ctx, req)
+	return err
+}
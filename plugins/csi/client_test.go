@@ -0,0 +1,111 @@
+package csi_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/csi"
+	"github.com/hashicorp/nomad/plugins/csi/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient serves a mock plugin on a temporary socket and returns a
+// client connected to it.
+func newTestClient(t *testing.T, config *mock.Config) (csi.CSIPlugin, string, func()) {
+	dir, err := ioutil.TempDir("", "csi")
+	require.NoError(t, err)
+
+	config.Root = dir
+	l, err := mock.Listen(filepath.Join(dir, "csi.sock"))
+	require.NoError(t, err)
+	go mock.NewPlugin(config).Serve(l)
+
+	client, err := csi.NewClient(filepath.Join(dir, "csi.sock"))
+	require.NoError(t, err)
+
+	return client, dir, func() {
+		client.Close()
+		l.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestClient_Identity(t *testing.T) {
+	require := require.New(t)
+	client, _, cleanup := newTestClient(t, &mock.Config{NodeID: "node-1", Controller: true})
+	defer cleanup()
+
+	ctx := context.Background()
+	name, version, err := client.PluginGetInfo(ctx)
+	require.NoError(err)
+	require.Equal(mock.DefaultName, name)
+	require.Equal(mock.DefaultVersion, version)
+
+	ready, err := client.PluginProbe(ctx)
+	require.NoError(err)
+	require.True(ready)
+
+	caps, err := client.PluginGetCapabilities(ctx)
+	require.NoError(err)
+	require.True(caps.HasControllerService)
+
+	info, err := client.NodeGetInfo(ctx)
+	require.NoError(err)
+	require.Equal("node-1", info.NodeID)
+}
+
+func TestClient_VolumeLifecycle(t *testing.T) {
+	require := require.New(t)
+	client, dir, cleanup := newTestClient(t, &mock.Config{NodeID: "node-1", Controller: true, Stage: true})
+	defer cleanup()
+
+	ctx := context.Background()
+	nodeCaps, err := client.NodeGetCapabilities(ctx)
+	require.NoError(err)
+	require.True(nodeCaps.HasStageUnstageVolume)
+
+	capability, err := csi.VolumeCapabilityFromStructs(structs.CSIVolumeAttachmentModeFilesystem,
+		structs.CSIVolumeAccessModeSingleNodeWriter, nil)
+	require.NoError(err)
+
+	// Staging requires the volume to be attached first
+	staging := filepath.Join(dir, "staging", "vol")
+	require.Error(client.NodeStageVolume(ctx, "vol", nil, staging, capability))
+
+	resp, err := client.ControllerPublishVolume(ctx, &csi.ControllerPublishVolumeRequest{
+		VolumeID:         "vol",
+		NodeID:           "node-1",
+		VolumeCapability: capability,
+	})
+	require.NoError(err)
+	require.NoError(client.NodeStageVolume(ctx, "vol", resp.PublishContext, staging, capability))
+
+	// Files written through the published path are stored in the volume
+	target := filepath.Join(dir, "per-alloc", "alloc", "vol")
+	require.NoError(client.NodePublishVolume(ctx, &csi.NodePublishVolumeRequest{
+		VolumeID:          "vol",
+		PublishContext:    resp.PublishContext,
+		StagingTargetPath: staging,
+		TargetPath:        target,
+		VolumeCapability:  capability,
+	}))
+	require.NoError(ioutil.WriteFile(filepath.Join(target, "data"), []byte("hello"), 0644))
+	data, err := ioutil.ReadFile(filepath.Join(dir, "volumes", "vol", "data"))
+	require.NoError(err)
+	require.Equal("hello", string(data))
+
+	require.NoError(client.NodeUnpublishVolume(ctx, "vol", target))
+	_, err = os.Lstat(target)
+	require.True(os.IsNotExist(err))
+
+	require.NoError(client.NodeUnstageVolume(ctx, "vol", staging))
+	require.NoError(client.ControllerUnpublishVolume(ctx, "vol", "node-1"))
+
+	// The data outlives the publication
+	_, err = os.Stat(filepath.Join(dir, "volumes", "vol", "data"))
+	require.NoError(err)
+}
@@ -0,0 +1,194 @@
+// fake is a package that includes fake implementations of public interfaces
+// from the CSI package for testing.
+package fake
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/hashicorp/nomad/plugins/csi"
+)
+
+var _ csi.CSIPlugin = &Client{}
+
+// Client is a mock implementation of the csi.CSIPlugin interface for use in
+// testing external components. Responses and errors are set on the client's
+// fields and the number of calls of each method is recorded.
+type Client struct {
+	Mu sync.RWMutex
+
+	NextPluginGetInfoName  string
+	NextPluginGetInfoVer   string
+	NextPluginGetInfoErr   error
+	PluginGetInfoCallCount int64
+
+	NextPluginProbeResponse bool
+	NextPluginProbeErr      error
+	PluginProbeCallCount    int64
+
+	NextPluginGetCapabilitiesResponse *csi.PluginCapabilitySet
+	NextPluginGetCapabilitiesErr      error
+	PluginGetCapabilitiesCallCount    int64
+
+	NextControllerGetCapabilitiesResponse *csi.ControllerCapabilitySet
+	NextControllerGetCapabilitiesErr      error
+	ControllerGetCapabilitiesCallCount    int64
+
+	NextControllerPublishVolumeResponse *csi.ControllerPublishVolumeResponse
+	NextControllerPublishVolumeErr      error
+	ControllerPublishVolumeCallCount    int64
+
+	NextControllerUnpublishVolumeErr   error
+	ControllerUnpublishVolumeCallCount int64
+
+	NextNodeGetCapabilitiesResponse *csi.NodeCapabilitySet
+	NextNodeGetCapabilitiesErr      error
+	NodeGetCapabilitiesCallCount    int64
+
+	NextNodeGetInfoResponse *csi.NodeGetInfoResponse
+	NextNodeGetInfoErr      error
+	NodeGetInfoCallCount    int64
+
+	NextNodeStageVolumeErr   error
+	NodeStageVolumeCallCount int64
+
+	NextNodeUnstageVolumeErr   error
+	NodeUnstageVolumeCallCount int64
+
+	PrevVolumeCapability       *csi.VolumeCapability
+	NextNodePublishVolumeErr   error
+	NodePublishVolumeCallCount int64
+
+	NextNodeUnpublishVolumeErr   error
+	NodeUnpublishVolumeCallCount int64
+}
+
+// PluginProbe is used to verify that the plugin is in a healthy state
+func (c *Client) PluginProbe(ctx context.Context) (bool, error) {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.PluginProbeCallCount++
+
+	return c.NextPluginProbeResponse, c.NextPluginProbeErr
+}
+
+// PluginGetInfo is used to return semantic data about the plugin.
+func (c *Client) PluginGetInfo(ctx context.Context) (string, string, error) {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.PluginGetInfoCallCount++
+
+	return c.NextPluginGetInfoName, c.NextPluginGetInfoVer, c.NextPluginGetInfoErr
+}
+
+// PluginGetCapabilities is used to return the available capabilities from the
+// identity service.
+func (c *Client) PluginGetCapabilities(ctx context.Context) (*csi.PluginCapabilitySet, error) {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.PluginGetCapabilitiesCallCount++
+
+	return c.NextPluginGetCapabilitiesResponse, c.NextPluginGetCapabilitiesErr
+}
+
+func (c *Client) ControllerGetCapabilities(ctx context.Context) (*csi.ControllerCapabilitySet, error) {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.ControllerGetCapabilitiesCallCount++
+
+	return c.NextControllerGetCapabilitiesResponse, c.NextControllerGetCapabilitiesErr
+}
+
+// ControllerPublishVolume is used to attach a remote volume to a node
+func (c *Client) ControllerPublishVolume(ctx context.Context, req *csi.ControllerPublishVolumeRequest) (*csi.ControllerPublishVolumeResponse, error) {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.ControllerPublishVolumeCallCount++
+
+	return c.NextControllerPublishVolumeResponse, c.NextControllerPublishVolumeErr
+}
+
+// ControllerUnpublishVolume is used to attach a remote volume to a node
+func (c *Client) ControllerUnpublishVolume(ctx context.Context, volumeID, nodeID string) error {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.ControllerUnpublishVolumeCallCount++
+
+	return c.NextControllerUnpublishVolumeErr
+}
+
+func (c *Client) NodeGetCapabilities(ctx context.Context) (*csi.NodeCapabilitySet, error) {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.NodeGetCapabilitiesCallCount++
+
+	return c.NextNodeGetCapabilitiesResponse, c.NextNodeGetCapabilitiesErr
+}
+
+// NodeGetInfo is used to return semantic data about the current node in
+// respect to the SP.
+func (c *Client) NodeGetInfo(ctx context.Context) (*csi.NodeGetInfoResponse, error) {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.NodeGetInfoCallCount++
+
+	return c.NextNodeGetInfoResponse, c.NextNodeGetInfoErr
+}
+
+// NodeStageVolume is used when a plugin has the STAGE_UNSTAGE volume capability
+// to prepare a volume for usage on a host.
+func (c *Client) NodeStageVolume(ctx context.Context, volumeID string, publishContext map[string]string, stagingTargetPath string, capabilities *csi.VolumeCapability) error {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.NodeStageVolumeCallCount++
+
+	return c.NextNodeStageVolumeErr
+}
+
+// NodeUnstageVolume is used when a plugin has the STAGE_UNSTAGE volume capability
+// to undo the work performed by NodeStageVolume.
+func (c *Client) NodeUnstageVolume(ctx context.Context, volumeID string, stagingTargetPath string) error {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.NodeUnstageVolumeCallCount++
+
+	return c.NextNodeUnstageVolumeErr
+}
+
+// NodePublishVolume is used to prepare a volume for use by an allocation.
+func (c *Client) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) error {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.PrevVolumeCapability = req.VolumeCapability
+	c.NodePublishVolumeCallCount++
+
+	return c.NextNodePublishVolumeErr
+}
+
+// NodeUnpublishVolume is used to cleanup usage of a volume for an alloc.
+func (c *Client) NodeUnpublishVolume(ctx context.Context, volumeID, targetPath string) error {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.NodeUnpublishVolumeCallCount++
+
+	return c.NextNodeUnpublishVolumeErr
+}
+
+// Close the client and ensure any connections are cleaned up.
+func (c *Client) Close() error {
+	c.NextPluginGetInfoErr = errors.New("client closed")
+	return nil
+}
@@ -0,0 +1,71 @@
+// mock-csi-plugin runs the mock CSI plugin. It is meant to be run as a task
+// of a plugin job to test CSI volumes without a storage provider:
+//
+//	task "plugin" {
+//	  driver = "exec"
+//	  config {
+//	    command = "mock-csi-plugin"
+//	    args    = ["-endpoint", "unix:///csi/csi.sock", "-node-id", "${node.unique.id}"]
+//	  }
+//	  csi_plugin {
+//	    id        = "mock"
+//	    type      = "monolith"
+//	    mount_dir = "/csi"
+//	  }
+//	}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/nomad/plugins/csi/mock"
+)
+
+func main() {
+	var config mock.Config
+	endpoint := flag.String("endpoint", "unix:///csi/csi.sock", "unix socket the plugin listens on")
+	flag.StringVar(&config.Name, "name", mock.DefaultName, "name of the plugin")
+	flag.StringVar(&config.Version, "version", mock.DefaultVersion, "vendor version of the plugin")
+	flag.StringVar(&config.NodeID, "node-id", "", "identity of the node (defaults to the hostname)")
+	flag.StringVar(&config.Root, "root", "", "directory storing the volumes (defaults to the socket's directory)")
+	flag.BoolVar(&config.Controller, "controller", false, "enable the controller service")
+	flag.BoolVar(&config.Stage, "stage", false, "require volumes to be staged before they are published")
+	flag.Parse()
+
+	if config.NodeID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			log.Fatalf("failed to determine node ID: %v", err)
+		}
+		config.NodeID = hostname
+	}
+	if config.Root == "" {
+		config.Root = filepath.Dir(strings.TrimPrefix(*endpoint, "unix://"))
+	}
+
+	plugin := mock.NewPlugin(&config)
+	l, err := mock.Listen(*endpoint)
+	if err != nil {
+		log.Fatalf("failed to listen on %q: %v", *endpoint, err)
+	}
+
+	// Remove the socket on shutdown
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		l.Close()
+	}()
+
+	fmt.Printf("serving %s on %s\n", plugin, *endpoint)
+	if err := plugin.Serve(l); err != nil && !strings.Contains(err.Error(), "use of closed network connection") {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
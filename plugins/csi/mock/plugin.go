@@ -0,0 +1,371 @@
+// mock is a package that includes a minimal CSI plugin serving volumes from
+// directories. It is used to test the CSI integration without a storage
+// provider.
+package mock
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	csipbv1 "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultName is the name the plugin reports when none is configured
+	DefaultName = "mock.csi.nomadproject.io"
+
+	// DefaultVersion is the vendor version the plugin reports
+	DefaultVersion = "0.1.0"
+
+	// PublishContextKey is the key of the publish context returned when the
+	// controller attaches a volume. Its value is the node the volume is
+	// attached to.
+	PublishContextKey = "mock/attached-node"
+)
+
+// Config configures the mock plugin
+type Config struct {
+	// Name and Version are reported by the identity service
+	Name    string
+	Version string
+
+	// NodeID is the identity of the node reported by the node service
+	NodeID string
+
+	// Root is the directory the volumes are stored in. Each volume is a
+	// directory below Root/volumes.
+	Root string
+
+	// Controller enables the controller service and requires volumes to be
+	// attached before they are staged.
+	Controller bool
+
+	// Stage enables staging volumes before publishing them.
+	Stage bool
+}
+
+// Plugin implements the identity, controller and node services of a CSI
+// plugin. Volumes are directories below the plugin's root, published by
+// creating relative symlinks so that the published paths resolve both inside
+// and outside of the plugin's filesystem.
+type Plugin struct {
+	config *Config
+
+	// attached tracks the nodes each volume is attached to
+	attached     map[string]map[string]struct{}
+	attachedLock sync.Mutex
+}
+
+// NewPlugin returns a mock plugin
+func NewPlugin(config *Config) *Plugin {
+	if config.Name == "" {
+		config.Name = DefaultName
+	}
+	if config.Version == "" {
+		config.Version = DefaultVersion
+	}
+	return &Plugin{
+		config:   config,
+		attached: make(map[string]map[string]struct{}),
+	}
+}
+
+// Serve serves the plugin's services on the unix socket until the listener
+// is closed.
+func (p *Plugin) Serve(l net.Listener) error {
+	server := grpc.NewServer()
+	csipbv1.RegisterIdentityServer(server, p)
+	csipbv1.RegisterControllerServer(server, p)
+	csipbv1.RegisterNodeServer(server, p)
+	return server.Serve(l)
+}
+
+// Listen creates the unix socket of the given endpoint, removing any stale
+// socket. Endpoints are either paths or unix:// URLs.
+func Listen(endpoint string) (net.Listener, error) {
+	path := strings.TrimPrefix(endpoint, "unix://")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return net.Listen("unix", path)
+}
+
+// volumePath returns the directory storing the volume
+func (p *Plugin) volumePath(volumeID string) (string, error) {
+	if volumeID == "" || strings.ContainsAny(volumeID, `/\`) || volumeID == "." || volumeID == ".." {
+		return "", status.Errorf(codes.InvalidArgument, "invalid volume ID %q", volumeID)
+	}
+	return filepath.Join(p.config.Root, "volumes", volumeID), nil
+}
+
+//
+// Identity service
+//
+
+func (p *Plugin) GetPluginInfo(ctx context.Context, req *csipbv1.GetPluginInfoRequest) (*csipbv1.GetPluginInfoResponse, error) {
+	return &csipbv1.GetPluginInfoResponse{
+		Name:          p.config.Name,
+		VendorVersion: p.config.Version,
+	}, nil
+}
+
+func (p *Plugin) GetPluginCapabilities(ctx context.Context, req *csipbv1.GetPluginCapabilitiesRequest) (*csipbv1.GetPluginCapabilitiesResponse, error) {
+	resp := &csipbv1.GetPluginCapabilitiesResponse{}
+	if p.config.Controller {
+		resp.Capabilities = append(resp.Capabilities, &csipbv1.PluginCapability{
+			Type: &csipbv1.PluginCapability_Service_{
+				Service: &csipbv1.PluginCapability_Service{
+					Type: csipbv1.PluginCapability_Service_CONTROLLER_SERVICE,
+				},
+			},
+		})
+	}
+	return resp, nil
+}
+
+func (p *Plugin) Probe(ctx context.Context, req *csipbv1.ProbeRequest) (*csipbv1.ProbeResponse, error) {
+	_, err := os.Stat(p.config.Root)
+	return &csipbv1.ProbeResponse{Ready: &wrappers.BoolValue{Value: err == nil}}, nil
+}
+
+//
+// Controller service
+//
+
+func (p *Plugin) CreateVolume(ctx context.Context, req *csipbv1.CreateVolumeRequest) (*csipbv1.CreateVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "volumes are created when they are first staged")
+}
+
+func (p *Plugin) DeleteVolume(ctx context.Context, req *csipbv1.DeleteVolumeRequest) (*csipbv1.DeleteVolumeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "volumes can't be deleted")
+}
+
+func (p *Plugin) ControllerPublishVolume(ctx context.Context, req *csipbv1.ControllerPublishVolumeRequest) (*csipbv1.ControllerPublishVolumeResponse, error) {
+	if !p.config.Controller {
+		return nil, status.Error(codes.Unimplemented, "controller service is disabled")
+	}
+	if _, err := p.volumePath(req.GetVolumeId()); err != nil {
+		return nil, err
+	}
+	if req.GetNodeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing node ID")
+	}
+
+	p.attachedLock.Lock()
+	defer p.attachedLock.Unlock()
+	nodes, ok := p.attached[req.GetVolumeId()]
+	if !ok {
+		nodes = make(map[string]struct{})
+		p.attached[req.GetVolumeId()] = nodes
+	}
+	nodes[req.GetNodeId()] = struct{}{}
+
+	return &csipbv1.ControllerPublishVolumeResponse{
+		PublishContext: map[string]string{PublishContextKey: req.GetNodeId()},
+	}, nil
+}
+
+func (p *Plugin) ControllerUnpublishVolume(ctx context.Context, req *csipbv1.ControllerUnpublishVolumeRequest) (*csipbv1.ControllerUnpublishVolumeResponse, error) {
+	if !p.config.Controller {
+		return nil, status.Error(codes.Unimplemented, "controller service is disabled")
+	}
+
+	p.attachedLock.Lock()
+	defer p.attachedLock.Unlock()
+	if nodes, ok := p.attached[req.GetVolumeId()]; ok {
+		delete(nodes, req.GetNodeId())
+		if len(nodes) == 0 {
+			delete(p.attached, req.GetVolumeId())
+		}
+	}
+	return &csipbv1.ControllerUnpublishVolumeResponse{}, nil
+}
+
+func (p *Plugin) ValidateVolumeCapabilities(ctx context.Context, req *csipbv1.ValidateVolumeCapabilitiesRequest) (*csipbv1.ValidateVolumeCapabilitiesResponse, error) {
+	return &csipbv1.ValidateVolumeCapabilitiesResponse{
+		Confirmed: &csipbv1.ValidateVolumeCapabilitiesResponse_Confirmed{
+			VolumeContext:      req.GetVolumeContext(),
+			VolumeCapabilities: req.GetVolumeCapabilities(),
+			Parameters:         req.GetParameters(),
+		},
+	}, nil
+}
+
+func (p *Plugin) ListVolumes(ctx context.Context, req *csipbv1.ListVolumesRequest) (*csipbv1.ListVolumesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "listing volumes is not supported")
+}
+
+func (p *Plugin) GetCapacity(ctx context.Context, req *csipbv1.GetCapacityRequest) (*csipbv1.GetCapacityResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "capacity is not reported")
+}
+
+func (p *Plugin) ControllerGetCapabilities(ctx context.Context, req *csipbv1.ControllerGetCapabilitiesRequest) (*csipbv1.ControllerGetCapabilitiesResponse, error) {
+	resp := &csipbv1.ControllerGetCapabilitiesResponse{}
+	if p.config.Controller {
+		resp.Capabilities = append(resp.Capabilities, &csipbv1.ControllerServiceCapability{
+			Type: &csipbv1.ControllerServiceCapability_Rpc{
+				Rpc: &csipbv1.ControllerServiceCapability_RPC{
+					Type: csipbv1.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
+				},
+			},
+		})
+	}
+	return resp, nil
+}
+
+func (p *Plugin) CreateSnapshot(ctx context.Context, req *csipbv1.CreateSnapshotRequest) (*csipbv1.CreateSnapshotResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "snapshots are not supported")
+}
+
+func (p *Plugin) DeleteSnapshot(ctx context.Context, req *csipbv1.DeleteSnapshotRequest) (*csipbv1.DeleteSnapshotResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "snapshots are not supported")
+}
+
+func (p *Plugin) ListSnapshots(ctx context.Context, req *csipbv1.ListSnapshotsRequest) (*csipbv1.ListSnapshotsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "snapshots are not supported")
+}
+
+//
+// Node service
+//
+
+// checkAttached returns an error if the controller service is enabled and
+// the publish context doesn't show the volume was attached to this node.
+func (p *Plugin) checkAttached(publishContext map[string]string) error {
+	if !p.config.Controller {
+		return nil
+	}
+	if node := publishContext[PublishContextKey]; node != p.config.NodeID {
+		return status.Errorf(codes.FailedPrecondition, "volume is not attached to node %q", p.config.NodeID)
+	}
+	return nil
+}
+
+func (p *Plugin) NodeStageVolume(ctx context.Context, req *csipbv1.NodeStageVolumeRequest) (*csipbv1.NodeStageVolumeResponse, error) {
+	if !p.config.Stage {
+		return nil, status.Error(codes.Unimplemented, "staging is disabled")
+	}
+	if err := p.checkAttached(req.GetPublishContext()); err != nil {
+		return nil, err
+	}
+	volPath, err := p.volumePath(req.GetVolumeId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetStagingTargetPath() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing staging target path")
+	}
+
+	if err := os.MkdirAll(volPath, 0777); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create volume: %v", err)
+	}
+	if err := os.MkdirAll(req.GetStagingTargetPath(), 0755); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create staging path: %v", err)
+	}
+	return &csipbv1.NodeStageVolumeResponse{}, nil
+}
+
+func (p *Plugin) NodeUnstageVolume(ctx context.Context, req *csipbv1.NodeUnstageVolumeRequest) (*csipbv1.NodeUnstageVolumeResponse, error) {
+	if !p.config.Stage {
+		return nil, status.Error(codes.Unimplemented, "staging is disabled")
+	}
+	if err := os.RemoveAll(req.GetStagingTargetPath()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to remove staging path: %v", err)
+	}
+	return &csipbv1.NodeUnstageVolumeResponse{}, nil
+}
+
+func (p *Plugin) NodePublishVolume(ctx context.Context, req *csipbv1.NodePublishVolumeRequest) (*csipbv1.NodePublishVolumeResponse, error) {
+	if err := p.checkAttached(req.GetPublishContext()); err != nil {
+		return nil, err
+	}
+	volPath, err := p.volumePath(req.GetVolumeId())
+	if err != nil {
+		return nil, err
+	}
+	target := req.GetTargetPath()
+	if target == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing target path")
+	}
+	if p.config.Stage {
+		if _, err := os.Stat(req.GetStagingTargetPath()); err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "volume is not staged: %v", err)
+		}
+	}
+
+	if err := os.MkdirAll(volPath, 0777); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create volume: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create target directory: %v", err)
+	}
+
+	// Publishing is idempotent
+	rel, err := filepath.Rel(filepath.Dir(target), volPath)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to compute volume path: %v", err)
+	}
+	if existing, err := os.Readlink(target); err == nil && existing == rel {
+		return &csipbv1.NodePublishVolumeResponse{}, nil
+	}
+	if err := os.RemoveAll(target); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to remove target path: %v", err)
+	}
+	if err := os.Symlink(rel, target); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to publish volume: %v", err)
+	}
+	return &csipbv1.NodePublishVolumeResponse{}, nil
+}
+
+func (p *Plugin) NodeUnpublishVolume(ctx context.Context, req *csipbv1.NodeUnpublishVolumeRequest) (*csipbv1.NodeUnpublishVolumeResponse, error) {
+	if req.GetTargetPath() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing target path")
+	}
+	if err := os.Remove(req.GetTargetPath()); err != nil && !os.IsNotExist(err) {
+		return nil, status.Errorf(codes.Internal, "failed to unpublish volume: %v", err)
+	}
+	return &csipbv1.NodeUnpublishVolumeResponse{}, nil
+}
+
+func (p *Plugin) NodeGetVolumeStats(ctx context.Context, req *csipbv1.NodeGetVolumeStatsRequest) (*csipbv1.NodeGetVolumeStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "volume stats are not reported")
+}
+
+func (p *Plugin) NodeGetCapabilities(ctx context.Context, req *csipbv1.NodeGetCapabilitiesRequest) (*csipbv1.NodeGetCapabilitiesResponse, error) {
+	resp := &csipbv1.NodeGetCapabilitiesResponse{}
+	if p.config.Stage {
+		resp.Capabilities = append(resp.Capabilities, &csipbv1.NodeServiceCapability{
+			Type: &csipbv1.NodeServiceCapability_Rpc{
+				Rpc: &csipbv1.NodeServiceCapability_RPC{
+					Type: csipbv1.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+				},
+			},
+		})
+	}
+	return resp, nil
+}
+
+func (p *Plugin) NodeGetInfo(ctx context.Context, req *csipbv1.NodeGetInfoRequest) (*csipbv1.NodeGetInfoResponse, error) {
+	if p.config.NodeID == "" {
+		return nil, status.Error(codes.FailedPrecondition, "node ID is not configured")
+	}
+	return &csipbv1.NodeGetInfoResponse{NodeId: p.config.NodeID}, nil
+}
+
+// String returns a description of the plugin's configuration
+func (p *Plugin) String() string {
+	return fmt.Sprintf("%s %s (node=%q root=%q controller=%v stage=%v)",
+		p.config.Name, p.config.Version, p.config.NodeID, p.config.Root, p.config.Controller, p.config.Stage)
+}
@@ -0,0 +1,358 @@
+package csi
+
+import (
+	"context"
+	"fmt"
+
+	csipbv1 "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// CSIPlugin implements a lightweight abstraction layer around a CSI Plugin.
+// It validates that responses from storage providers (SP's), correctly conform
+// to the specification before returning response data or erroring.
+type CSIPlugin interface {
+	// PluginProbe is used to verify that the plugin is in a healthy state
+	PluginProbe(ctx context.Context) (bool, error)
+
+	// PluginGetInfo is used to return semantic data about the plugin.
+	// Response:
+	//  - string: name, the name of the plugin in domain notation format.
+	//  - string: version, the vendor version of the plugin
+	PluginGetInfo(ctx context.Context) (string, string, error)
+
+	// PluginGetCapabilities is used to return the available capabilities from the
+	// identity service. This currently only looks for the CONTROLLER_SERVICE.
+	PluginGetCapabilities(ctx context.Context) (*PluginCapabilitySet, error)
+
+	// ControllerGetCapabilities is used to get controller-specific capabilities
+	// for a plugin.
+	ControllerGetCapabilities(ctx context.Context) (*ControllerCapabilitySet, error)
+
+	// ControllerPublishVolume is used to attach a remote volume to a cluster node.
+	ControllerPublishVolume(ctx context.Context, req *ControllerPublishVolumeRequest) (*ControllerPublishVolumeResponse, error)
+
+	// ControllerUnpublishVolume is used to deattach a remote volume from a
+	// cluster node.
+	ControllerUnpublishVolume(ctx context.Context, volumeID, nodeID string) error
+
+	// NodeGetCapabilities is used to return the available capabilities from the
+	// Node Service.
+	NodeGetCapabilities(ctx context.Context) (*NodeCapabilitySet, error)
+
+	// NodeGetInfo is used to return semantic data about the current node in
+	// respect to the SP.
+	NodeGetInfo(ctx context.Context) (*NodeGetInfoResponse, error)
+
+	// NodeStageVolume is used when a plugin has the STAGE_UNSTAGE volume capability
+	// to prepare a volume for usage on a host. If a Volume Plugin does not support
+	// STAGE_UNSTAGE, this RPC should not be called.
+	NodeStageVolume(ctx context.Context, volumeID string, publishContext map[string]string, stagingTargetPath string, capabilities *VolumeCapability) error
+
+	// NodeUnstageVolume is used when a plugin has the STAGE_UNSTAGE volume capability
+	// to undo the work performed by NodeStageVolume. If a volume has been staged,
+	// this RPC must be called before freeing the volume.
+	NodeUnstageVolume(ctx context.Context, volumeID string, stagingTargetPath string) error
+
+	// NodePublishVolume is used to prepare a volume for use by an allocation.
+	// if err == nil the response should be assumed to be successful.
+	NodePublishVolume(ctx context.Context, req *NodePublishVolumeRequest) error
+
+	// NodeUnpublishVolume is used to cleanup usage of a volume for an alloc. This
+	// MUST be called before calling NodeUnstageVolume or ControllerUnpublishVolume
+	// for the given volume.
+	NodeUnpublishVolume(ctx context.Context, volumeID, targetPath string) error
+
+	// Close the connection to the plugin
+	Close() error
+}
+
+// PluginCapabilitySet is the capabilities of the identity service of a
+// plugin.
+type PluginCapabilitySet struct {
+	HasControllerService bool
+}
+
+// NewPluginCapabilitySet converts the capabilities returned by a plugin
+func NewPluginCapabilitySet(capabilities *csipbv1.GetPluginCapabilitiesResponse) *PluginCapabilitySet {
+	cs := &PluginCapabilitySet{}
+
+	for _, c := range capabilities.GetCapabilities() {
+		if c.GetService().GetType() == csipbv1.PluginCapability_Service_CONTROLLER_SERVICE {
+			cs.HasControllerService = true
+		}
+	}
+
+	return cs
+}
+
+// ControllerCapabilitySet is the capabilities of the controller service of a
+// plugin.
+type ControllerCapabilitySet struct {
+	HasPublishUnpublishVolume bool
+	HasPublishReadonly        bool
+}
+
+// NewControllerCapabilitySet converts the capabilities returned by a plugin
+func NewControllerCapabilitySet(resp *csipbv1.ControllerGetCapabilitiesResponse) *ControllerCapabilitySet {
+	cs := &ControllerCapabilitySet{}
+
+	for _, c := range resp.GetCapabilities() {
+		switch c.GetRpc().GetType() {
+		case csipbv1.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME:
+			cs.HasPublishUnpublishVolume = true
+		case csipbv1.ControllerServiceCapability_RPC_PUBLISH_READONLY:
+			cs.HasPublishReadonly = true
+		}
+	}
+
+	return cs
+}
+
+// NodeCapabilitySet is the capabilities of the node service of a plugin.
+type NodeCapabilitySet struct {
+	HasStageUnstageVolume bool
+}
+
+// NewNodeCapabilitySet converts the capabilities returned by a plugin
+func NewNodeCapabilitySet(resp *csipbv1.NodeGetCapabilitiesResponse) *NodeCapabilitySet {
+	cs := &NodeCapabilitySet{}
+
+	for _, c := range resp.GetCapabilities() {
+		if c.GetRpc().GetType() == csipbv1.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME {
+			cs.HasStageUnstageVolume = true
+		}
+	}
+
+	return cs
+}
+
+// NodeGetInfoResponse is the identity of the node as known by the plugin.
+type NodeGetInfoResponse struct {
+	NodeID     string
+	MaxVolumes int64
+}
+
+// VolumeAccessType is the way a volume is exposed to allocations.
+type VolumeAccessType int8
+
+const (
+	VolumeAccessTypeBlock VolumeAccessType = iota + 1
+	VolumeAccessTypeMount
+)
+
+// VolumeAccessMode represents the type of concurrent access that a volume
+// supports.
+type VolumeAccessMode int8
+
+const (
+	VolumeAccessModeUnknown VolumeAccessMode = iota
+	VolumeAccessModeSingleNodeWriter
+	VolumeAccessModeSingleNodeReaderOnly
+	VolumeAccessModeMultiNodeReaderOnly
+	VolumeAccessModeMultiNodeSingleWriter
+	VolumeAccessModeMultiNodeMultiWriter
+)
+
+func (a VolumeAccessMode) ToCSIRepresentation() csipbv1.VolumeCapability_AccessMode_Mode {
+	switch a {
+	case VolumeAccessModeSingleNodeWriter:
+		return csipbv1.VolumeCapability_AccessMode_SINGLE_NODE_WRITER
+	case VolumeAccessModeSingleNodeReaderOnly:
+		return csipbv1.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY
+	case VolumeAccessModeMultiNodeReaderOnly:
+		return csipbv1.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY
+	case VolumeAccessModeMultiNodeSingleWriter:
+		return csipbv1.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER
+	case VolumeAccessModeMultiNodeMultiWriter:
+		return csipbv1.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER
+	default:
+		return csipbv1.VolumeCapability_AccessMode_UNKNOWN
+	}
+}
+
+// VolumeCapability describes the storage capabilities a volume must support
+// for a given usage. A volume is either a block device or a mounted
+// filesystem.
+type VolumeCapability struct {
+	AccessType VolumeAccessType
+	AccessMode VolumeAccessMode
+
+	// MountVolume is used with VolumeAccessTypeMount
+	MountVolume *structs.CSIMountOptions
+}
+
+// VolumeCapabilityFromStructs converts the attachment and access modes of a
+// registered volume.
+func VolumeCapabilityFromStructs(sAccessType structs.CSIVolumeAttachmentMode, sAccessMode structs.CSIVolumeAccessMode, mountOptions *structs.CSIMountOptions) (*VolumeCapability, error) {
+	var accessType VolumeAccessType
+	switch sAccessType {
+	case structs.CSIVolumeAttachmentModeBlockDevice:
+		accessType = VolumeAccessTypeBlock
+	case structs.CSIVolumeAttachmentModeFilesystem:
+		accessType = VolumeAccessTypeMount
+	default:
+		// These fields are validated during job submission, but here we perform a
+		// final check during transformation into the requisite CSI Data type to
+		// defend against development bugs and corrupted state - and incompatible
+		// nomad versions in the future.
+		return nil, fmt.Errorf("Unknown volume attachment mode: %s", sAccessType)
+	}
+
+	var accessMode VolumeAccessMode
+	switch sAccessMode {
+	case structs.CSIVolumeAccessModeSingleNodeReader:
+		accessMode = VolumeAccessModeSingleNodeReaderOnly
+	case structs.CSIVolumeAccessModeSingleNodeWriter:
+		accessMode = VolumeAccessModeSingleNodeWriter
+	case structs.CSIVolumeAccessModeMultiNodeMultiWriter:
+		accessMode = VolumeAccessModeMultiNodeMultiWriter
+	case structs.CSIVolumeAccessModeMultiNodeSingleWriter:
+		accessMode = VolumeAccessModeMultiNodeSingleWriter
+	case structs.CSIVolumeAccessModeMultiNodeReader:
+		accessMode = VolumeAccessModeMultiNodeReaderOnly
+	default:
+		// These fields are validated during job submission, but here we perform a
+		// final check during transformation into the requisite CSI Data type to
+		// defend against development bugs and corrupted state - and incompatible
+		// nomad versions in the future.
+		return nil, fmt.Errorf("Unknown volume access mode: %v", sAccessMode)
+	}
+
+	return &VolumeCapability{
+		AccessType:  accessType,
+		AccessMode:  accessMode,
+		MountVolume: mountOptions,
+	}, nil
+}
+
+func (c *VolumeCapability) ToCSIRepresentation() *csipbv1.VolumeCapability {
+	if c == nil {
+		return nil
+	}
+
+	vc := &csipbv1.VolumeCapability{
+		AccessMode: &csipbv1.VolumeCapability_AccessMode{
+			Mode: c.AccessMode.ToCSIRepresentation(),
+		},
+	}
+
+	if c.AccessType == VolumeAccessTypeMount {
+		mount := &csipbv1.VolumeCapability_MountVolume{}
+		if c.MountVolume != nil {
+			mount.FsType = c.MountVolume.FSType
+			mount.MountFlags = c.MountVolume.MountFlags
+		}
+		vc.AccessType = &csipbv1.VolumeCapability_Mount{Mount: mount}
+	} else {
+		vc.AccessType = &csipbv1.VolumeCapability_Block{Block: &csipbv1.VolumeCapability_BlockVolume{}}
+	}
+
+	return vc
+}
+
+// ControllerPublishVolumeRequest is used to attach a volume to a node
+type ControllerPublishVolumeRequest struct {
+	VolumeID         string
+	NodeID           string
+	ReadOnly         bool
+	VolumeCapability *VolumeCapability
+	VolumeContext    map[string]string
+}
+
+func (r *ControllerPublishVolumeRequest) ToCSIRepresentation() *csipbv1.ControllerPublishVolumeRequest {
+	if r == nil {
+		return nil
+	}
+
+	return &csipbv1.ControllerPublishVolumeRequest{
+		VolumeId:         r.VolumeID,
+		NodeId:           r.NodeID,
+		Readonly:         r.ReadOnly,
+		VolumeCapability: r.VolumeCapability.ToCSIRepresentation(),
+		VolumeContext:    r.VolumeContext,
+	}
+}
+
+func (r *ControllerPublishVolumeRequest) Validate() error {
+	if r.VolumeID == "" {
+		return fmt.Errorf("missing VolumeID")
+	}
+	if r.NodeID == "" {
+		return fmt.Errorf("missing NodeID")
+	}
+	return nil
+}
+
+// ControllerPublishVolumeResponse is the result of attaching a volume
+type ControllerPublishVolumeResponse struct {
+	PublishContext map[string]string
+}
+
+// NodePublishVolumeRequest is used to publish a volume for an allocation
+type NodePublishVolumeRequest struct {
+	// The ID of the volume to publish.
+	VolumeID string
+
+	// If the volume was attached via a call to `ControllerPublishVolume` then
+	// we need to provide the returned PublishContext here.
+	PublishContext map[string]string
+
+	// The path to which the volume was staged by `NodeStageVolume`.
+	// It MUST be an absolute path in the root filesystem of the process
+	// serving this request.
+	// E.g {the plugins internal mount path}/staging/volumeid/...
+	//
+	// It MUST be set if the Node Plugin implements the
+	// `STAGE_UNSTAGE_VOLUME` node capability.
+	StagingTargetPath string
+
+	// The path to which the volume will be published.
+	// It MUST be an absolute path in the root filesystem of the process serving this
+	// request.
+	// E.g {the plugins internal mount path}/per-alloc/allocid/volumeid/...
+	//
+	// The CO SHALL ensure uniqueness of target_path per volume.
+	// The CO SHALL ensure that the parent directory of this path exists
+	// and that the process serving the request has `read` and `write`
+	// permissions to that parent directory.
+	TargetPath string
+
+	// Volume capability describing how the CO intends to use this volume.
+	VolumeCapability *VolumeCapability
+
+	Readonly bool
+
+	// Volume context as returned by the plugin when the volume was created,
+	// and given at registration.
+	VolumeContext map[string]string
+}
+
+func (r *NodePublishVolumeRequest) ToCSIRepresentation() *csipbv1.NodePublishVolumeRequest {
+	if r == nil {
+		return nil
+	}
+
+	return &csipbv1.NodePublishVolumeRequest{
+		VolumeId:          r.VolumeID,
+		PublishContext:    r.PublishContext,
+		StagingTargetPath: r.StagingTargetPath,
+		TargetPath:        r.TargetPath,
+		VolumeCapability:  r.VolumeCapability.ToCSIRepresentation(),
+		Readonly:          r.Readonly,
+		VolumeContext:     r.VolumeContext,
+	}
+}
+
+func (r *NodePublishVolumeRequest) Validate() error {
+	if r.VolumeID == "" {
+		return fmt.Errorf("missing VolumeID")
+	}
+	if r.TargetPath == "" {
+		return fmt.Errorf("missing TargetPath")
+	}
+	if r.VolumeCapability == nil {
+		return fmt.Errorf("missing VolumeCapabilities")
+	}
+	return nil
+}
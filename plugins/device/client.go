@@ -0,0 +1,116 @@
+package device
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/device/proto"
+)
+
+// devicePluginClient implements the client side of a remote device plugin,
+// using gRPC to communicate to the remote plugin.
+type devicePluginClient struct {
+	*base.BasePluginClient
+
+	client proto.DevicePluginClient
+}
+
+// Fingerprint streams the devices detected by the plugin. The protocol sends
+// a single device group per message, so each response contains one group and
+// consumers track the groups by ID. An error receiving from the stream is sent
+// as a response with Error set before the channel is closed.
+func (d *devicePluginClient) Fingerprint(ctx context.Context) (<-chan *FingerprintResponse, error) {
+	stream, err := d.client.Fingerprint(ctx, &empty.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *FingerprintResponse, 1)
+	go d.handleFingerprint(ctx, stream, ch)
+	return ch, nil
+}
+
+func (d *devicePluginClient) handleFingerprint(ctx context.Context, stream proto.DevicePlugin_FingerprintClient, ch chan *FingerprintResponse) {
+	defer close(ch)
+	for {
+		pb, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return
+		}
+
+		resp := &FingerprintResponse{}
+		if err != nil {
+			resp.Error = err
+		} else {
+			resp.Devices = []*DeviceGroup{deviceGroupFromProto(pb)}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- resp:
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (d *devicePluginClient) Reserve(deviceIDs []string) (*ContainerReservation, error) {
+	resp, err := d.client.Reserve(context.Background(), &proto.ReserveRequest{DeviceIds: deviceIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	return containerReservationFromProto(resp.GetContainerRes()), nil
+}
+
+// Stats streams the statistics of the devices until the context is done or
+// the stream fails. An error receiving from the stream is sent as a response
+// with Error set before the channel is closed.
+func (d *devicePluginClient) Stats(ctx context.Context, interval time.Duration) (<-chan *StatsResponse, error) {
+	req := &proto.StatsRequest{
+		CollectionInterval: ptypes.DurationProto(interval),
+	}
+
+	stream, err := d.client.Stats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *StatsResponse, 1)
+	go d.handleStats(ctx, stream, ch)
+	return ch, nil
+}
+
+func (d *devicePluginClient) handleStats(ctx context.Context, stream proto.DevicePlugin_StatsClient, ch chan *StatsResponse) {
+	defer close(ch)
+	for {
+		pb, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return
+		}
+
+		resp := &StatsResponse{}
+		if err != nil {
+			resp.Error = err
+		} else {
+			resp.Groups, resp.Error = deviceGroupsStatsFromProto(pb.GetGroups())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- resp:
+		}
+
+		if resp.Error != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/nomad/plugins/base"
+)
+
+const (
+	// ApiVersion010 is the initial API version for the device plugins
+	ApiVersion010 = "v0.1.0"
+)
+
+// DevicePlugin is the interface which device plugins implement. It is also
+// implemented by a plugin client which proxies the calls to device plugins
+// implemented out of process.
+type DevicePlugin interface {
+	base.BasePlugin
+
+	// Fingerprint returns a channel on which the plugin sends the devices it
+	// detected immediately and again whenever they change. The channel is
+	// closed when the context is done.
+	Fingerprint(context.Context) (<-chan *FingerprintResponse, error)
+
+	// Reserve is called before starting an allocation that requires access
+	// to the devices and returns how to expose them to the tasks.
+	Reserve(deviceIDs []string) (*ContainerReservation, error)
+
+	// Stats returns a channel on which the statistics of the detected devices
+	// are sent every interval. The channel is closed when the context is
+	// done.
+	Stats(ctx context.Context, interval time.Duration) (<-chan *StatsResponse, error)
+}
+
+// FingerprintResponse is the set of devices detected by a device plugin.
+type FingerprintResponse struct {
+	// Devices are the detected devices, grouped by vendor, type and name
+	Devices []*DeviceGroup
+
+	// Error is set by the plugin client if fingerprinting failed
+	Error error
+}
+
+// DeviceGroup is a group of devices sharing their vendor, type and name.
+type DeviceGroup struct {
+	// Vendor is the name of the vendor of the devices
+	Vendor string
+
+	// Type is the type of the devices, such as gpu or fpga
+	Type string
+
+	// Name is the name of the devices
+	Name string
+
+	// Devices are the instances of the devices
+	Devices []*Device
+
+	// Attributes are node attributes set by the plugin, to be used in
+	// constraints
+	Attributes map[string]string
+}
+
+// ID returns the identifier of the device group, vendor/type/name.
+func (g *DeviceGroup) ID() string {
+	return fmt.Sprintf("%s/%s/%s", g.Vendor, g.Type, g.Name)
+}
+
+// Device is a single detected device.
+type Device struct {
+	// ID is the identifier of the device
+	ID string
+
+	// Healthy is whether the device is healthy and HealthDesc describes its
+	// health
+	Healthy    bool
+	HealthDesc string
+
+	// PCIBusID is the optional PCI bus ID of the device
+	PCIBusID string
+}
+
+// ContainerReservation describes how to expose reserved devices to a task
+// isolated by containers sharing the host OS.
+type ContainerReservation struct {
+	// Envs are environment variables to set in the task
+	Envs map[string]string
+
+	// Mounts are host paths to mount in the task
+	Mounts []*Mount
+
+	// Devices are host devices to expose in the task
+	Devices []*DeviceSpec
+}
+
+// Mount is a host path mounted in a task.
+type Mount struct {
+	TaskPath string
+	HostPath string
+	ReadOnly bool
+}
+
+// DeviceSpec is a host device exposed in a task.
+type DeviceSpec struct {
+	TaskPath string
+	HostPath string
+
+	// CgroupPerms are the cgroup permissions of the device, a combination
+	// of r, w and m
+	CgroupPerms string
+}
+
+// StatsResponse contains the statistics of the devices of a device plugin.
+type StatsResponse struct {
+	// Groups are the statistics of each device group
+	Groups []*DeviceGroupStats
+
+	// Error is set by the plugin client if collecting statistics failed
+	Error error
+}
+
+// DeviceGroupStats contains the statistics of the devices of a device group.
+type DeviceGroupStats struct {
+	Vendor string
+	Type   string
+	Name   string
+
+	// InstanceStats maps the ID of each device to its statistics
+	InstanceStats map[string]*DeviceStats
+}
+
+// ID returns the identifier of the device group, vendor/type/name.
+func (g *DeviceGroupStats) ID() string {
+	return fmt.Sprintf("%s/%s/%s", g.Vendor, g.Type, g.Name)
+}
+
+// Copy returns a copy of the device group statistics. The statistics of the
+// devices are shared.
+func (g *DeviceGroupStats) Copy() *DeviceGroupStats {
+	if g == nil {
+		return nil
+	}
+
+	ng := *g
+	ng.InstanceStats = make(map[string]*DeviceStats, len(g.InstanceStats))
+	for id, s := range g.InstanceStats {
+		ng.InstanceStats[id] = s
+	}
+	return &ng
+}
+
+// DeviceStats is the statistics of a single device.
+type DeviceStats struct {
+	// Summary is the statistic that best describes the usage of the device,
+	// such as its utilisation
+	Summary *StatValue
+
+	// Stats are the detailed statistics of the device
+	Stats *StatObject
+
+	// Timestamp is the time the statistics were collected
+	Timestamp time.Time
+}
+
+// StatObject is a collection of statistics.
+type StatObject struct {
+	// Nested are nested collections of statistics keyed by name
+	Nested map[string]*StatObject
+
+	// Attributes are the statistics of the collection keyed by name
+	Attributes map[string]*StatValue
+}
+
+// StatValue is the typed value of a statistic. Exactly one of the float,
+// int, string or bool values is set. Numeric values have an optional
+// denominator, such as the total memory of used memory.
+type StatValue struct {
+	FloatNumeratorVal   *float64
+	FloatDenominatorVal *float64
+	IntNumeratorVal     *int64
+	IntDenominatorVal   *int64
+	StringVal           *string
+	BoolVal             *bool
+
+	// Unit is the unit of the value, such as MiB or %
+	Unit string
+
+	// Desc describes the statistic
+	Desc string
+}
+
+// Float returns the numerator of a numeric value as a float.
+func (v *StatValue) Float() (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+
+	switch {
+	case v.FloatNumeratorVal != nil:
+		return *v.FloatNumeratorVal, true
+	case v.IntNumeratorVal != nil:
+		return float64(*v.IntNumeratorVal), true
+	default:
+		return 0, false
+	}
+}
+
+// String returns a human readable representation of the value with its unit.
+func (v *StatValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	var s string
+	switch {
+	case v.FloatNumeratorVal != nil:
+		s = strconv.FormatFloat(*v.FloatNumeratorVal, 'f', 2, 64)
+		if v.FloatDenominatorVal != nil {
+			s += " / " + strconv.FormatFloat(*v.FloatDenominatorVal, 'f', 2, 64)
+		}
+	case v.IntNumeratorVal != nil:
+		s = strconv.FormatInt(*v.IntNumeratorVal, 10)
+		if v.IntDenominatorVal != nil {
+			s += " / " + strconv.FormatInt(*v.IntDenominatorVal, 10)
+		}
+	case v.StringVal != nil:
+		s = *v.StringVal
+	case v.BoolVal != nil:
+		s = strconv.FormatBool(*v.BoolVal)
+	}
+
+	if v.Unit != "" {
+		s += " " + v.Unit
+	}
+	return s
+}
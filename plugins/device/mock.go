@@ -0,0 +1,28 @@
+package device
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/nomad/plugins/base"
+)
+
+// MockDevicePlugin is used for testing.
+// Each function can be set as a closure to make assertions about how data
+// is passed through the device plugin layer.
+type MockDevicePlugin struct {
+	base.MockPlugin
+	FingerprintF func(context.Context) (<-chan *FingerprintResponse, error)
+	ReserveF     func([]string) (*ContainerReservation, error)
+	StatsF       func(context.Context, time.Duration) (<-chan *StatsResponse, error)
+}
+
+func (p *MockDevicePlugin) Fingerprint(ctx context.Context) (<-chan *FingerprintResponse, error) {
+	return p.FingerprintF(ctx)
+}
+func (p *MockDevicePlugin) Reserve(ids []string) (*ContainerReservation, error) {
+	return p.ReserveF(ids)
+}
+func (p *MockDevicePlugin) Stats(ctx context.Context, interval time.Duration) (<-chan *StatsResponse, error) {
+	return p.StatsF(ctx, interval)
+}
@@ -0,0 +1,52 @@
+package device
+
+import (
+	"context"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/plugins/base"
+	baseproto "github.com/hashicorp/nomad/plugins/base/proto"
+	"github.com/hashicorp/nomad/plugins/device/proto"
+	"google.golang.org/grpc"
+)
+
+// PluginDevice wraps a DevicePlugin and implements go-plugins GRPCPlugin
+// interface to expose the interface over gRPC.
+type PluginDevice struct {
+	plugin.NetRPCUnsupportedPlugin
+	impl DevicePlugin
+}
+
+// NewDevicePlugin returns the go-plugin plugin exposing the device plugin.
+func NewDevicePlugin(d DevicePlugin) *PluginDevice {
+	return &PluginDevice{impl: d}
+}
+
+func (p *PluginDevice) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterDevicePluginServer(s, &devicePluginServer{
+		impl:   p.impl,
+		broker: broker,
+	})
+	return nil
+}
+
+func (p *PluginDevice) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &devicePluginClient{
+		BasePluginClient: &base.BasePluginClient{Client: baseproto.NewBasePluginClient(c)},
+		client:           proto.NewDevicePluginClient(c),
+	}, nil
+}
+
+// Serve is used to serve a device plugin. It is called from the main
+// function of the plugin binary and blocks until the client kills the
+// plugin.
+func Serve(d DevicePlugin) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: base.Handshake,
+		Plugins: map[string]plugin.Plugin{
+			base.PluginTypeBase:   &base.PluginBase{Impl: d},
+			base.PluginTypeDevice: &PluginDevice{impl: d},
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+	})
+}
@@ -0,0 +1,225 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/stretchr/testify/require"
+)
+
+// dispenseMockDevicePlugin returns a gRPC client of the mock device plugin
+// and a function to stop the connection.
+func dispenseMockDevicePlugin(t *testing.T, mock *MockDevicePlugin) (DevicePlugin, func()) {
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		base.PluginTypeBase:   &base.PluginBase{Impl: mock},
+		base.PluginTypeDevice: &PluginDevice{impl: mock},
+	})
+
+	raw, err := client.Dispense(base.PluginTypeDevice)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	impl, ok := raw.(DevicePlugin)
+	if !ok {
+		t.Fatalf("bad: %#v", raw)
+	}
+
+	return impl, func() {
+		client.Close()
+		server.Stop()
+	}
+}
+
+func TestDevicePlugin_Fingerprint_GRPC(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	gpus := &DeviceGroup{
+		Vendor: "nvidia",
+		Type:   "gpu",
+		Name:   "1080ti",
+		Devices: []*Device{
+			{ID: "UUID1", Healthy: true, PCIBusID: "0000:00:1e.0"},
+			{ID: "UUID2", Healthy: false, HealthDesc: "overheating"},
+		},
+		Attributes: map[string]string{"driver.version": "390.30"},
+	}
+	fpgas := &DeviceGroup{
+		Vendor:  "xilinx",
+		Type:    "fpga",
+		Name:    "vu9p",
+		Devices: []*Device{{ID: "FPGA1", Healthy: true}},
+	}
+
+	mock := &MockDevicePlugin{
+		FingerprintF: func(ctx context.Context) (<-chan *FingerprintResponse, error) {
+			ch := make(chan *FingerprintResponse, 1)
+			ch <- &FingerprintResponse{Devices: []*DeviceGroup{gpus, fpgas}}
+			return ch, nil
+		},
+	}
+
+	impl, stop := dispenseMockDevicePlugin(t, mock)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := impl.Fingerprint(ctx)
+	require.NoError(err)
+
+	// Each device group is received as a separate response
+	for _, expected := range []*DeviceGroup{gpus, fpgas} {
+		select {
+		case resp := <-ch:
+			require.NoError(resp.Error)
+			require.Len(resp.Devices, 1)
+			require.Equal(expected.ID(), resp.Devices[0].ID())
+			require.Equal(expected.Devices, resp.Devices[0].Devices)
+			require.Equal(expected.Attributes, resp.Devices[0].Attributes)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for fingerprint")
+		}
+	}
+}
+
+func TestDevicePlugin_Fingerprint_Error_GRPC(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	mock := &MockDevicePlugin{
+		FingerprintF: func(ctx context.Context) (<-chan *FingerprintResponse, error) {
+			ch := make(chan *FingerprintResponse, 1)
+			ch <- &FingerprintResponse{Error: errors.New("no devices")}
+			return ch, nil
+		},
+	}
+
+	impl, stop := dispenseMockDevicePlugin(t, mock)
+	defer stop()
+
+	ch, err := impl.Fingerprint(context.Background())
+	require.NoError(err)
+
+	select {
+	case resp := <-ch:
+		require.Error(resp.Error)
+		require.Contains(resp.Error.Error(), "no devices")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for fingerprint")
+	}
+
+	_, ok := <-ch
+	require.False(ok)
+}
+
+func TestDevicePlugin_Reserve_GRPC(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var received []string
+	mock := &MockDevicePlugin{
+		ReserveF: func(ids []string) (*ContainerReservation, error) {
+			received = ids
+			return &ContainerReservation{
+				Envs:    map[string]string{"NVIDIA_VISIBLE_DEVICES": "UUID1"},
+				Mounts:  []*Mount{{TaskPath: "/usr/lib/nvidia", HostPath: "/usr/lib/nvidia", ReadOnly: true}},
+				Devices: []*DeviceSpec{{TaskPath: "/dev/nvidia0", HostPath: "/dev/nvidia0", CgroupPerms: "rw"}},
+			}, nil
+		},
+	}
+
+	impl, stop := dispenseMockDevicePlugin(t, mock)
+	defer stop()
+
+	res, err := impl.Reserve([]string{"UUID1"})
+	require.NoError(err)
+	require.Equal([]string{"UUID1"}, received)
+	require.Equal("UUID1", res.Envs["NVIDIA_VISIBLE_DEVICES"])
+	require.Len(res.Mounts, 1)
+	require.True(res.Mounts[0].ReadOnly)
+	require.Len(res.Devices, 1)
+	require.Equal("rw", res.Devices[0].CgroupPerms)
+}
+
+func TestDevicePlugin_Stats_GRPC(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	now := time.Now().Round(0)
+	stats := &DeviceGroupStats{
+		Vendor: "nvidia",
+		Type:   "gpu",
+		Name:   "1080ti",
+		InstanceStats: map[string]*DeviceStats{
+			"UUID1": {
+				Summary: &StatValue{
+					FloatNumeratorVal: helper.Float64ToPtr(42.5),
+					Unit:              "%",
+					Desc:              "GPU utilization",
+				},
+				Stats: &StatObject{
+					Attributes: map[string]*StatValue{
+						"memory": {
+							IntNumeratorVal:   helper.Int64ToPtr(2048),
+							IntDenominatorVal: helper.Int64ToPtr(11264),
+							Unit:              "MiB",
+						},
+						"power_capped": {BoolVal: helper.BoolToPtr(false)},
+					},
+					Nested: map[string]*StatObject{
+						"temperature": {
+							Attributes: map[string]*StatValue{
+								"core": {IntNumeratorVal: helper.Int64ToPtr(71), Unit: "C"},
+							},
+							Nested: map[string]*StatObject{},
+						},
+					},
+				},
+				Timestamp: now,
+			},
+		},
+	}
+
+	var interval time.Duration
+	mock := &MockDevicePlugin{
+		StatsF: func(ctx context.Context, i time.Duration) (<-chan *StatsResponse, error) {
+			interval = i
+			ch := make(chan *StatsResponse, 1)
+			ch <- &StatsResponse{Groups: []*DeviceGroupStats{stats}}
+			return ch, nil
+		},
+	}
+
+	impl, stop := dispenseMockDevicePlugin(t, mock)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := impl.Stats(ctx, 3*time.Second)
+	require.NoError(err)
+
+	select {
+	case resp := <-ch:
+		require.NoError(resp.Error)
+		require.Equal(3*time.Second, interval)
+		require.Len(resp.Groups, 1)
+
+		actual := resp.Groups[0].InstanceStats["UUID1"]
+		require.NotNil(actual)
+		require.True(now.Equal(actual.Timestamp))
+		require.Equal("42.50 %", actual.Summary.String())
+		require.Equal("2048 / 11264 MiB", actual.Stats.Attributes["memory"].String())
+		require.Equal("false", actual.Stats.Attributes["power_capped"].String())
+		require.Equal("71 C", actual.Stats.Nested["temperature"].Attributes["core"].String())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for stats")
+	}
+}
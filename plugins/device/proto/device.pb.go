@@ -0,0 +1,1109 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: github.com/hashicorp/nomad/plugins/device/proto/device.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import duration "github.com/golang/protobuf/ptypes/duration"
+import empty "github.com/golang/protobuf/ptypes/empty"
+import timestamp "github.com/golang/protobuf/ptypes/timestamp"
+import wrappers "github.com/golang/protobuf/ptypes/wrappers"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// DetectedDevices is the set of devices that the device plugin has
+// detected and is exposing
+type DetectedDevices struct {
+	// vendor is the name of the vendor of the device
+	Vendor string `protobuf:"bytes,1,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	// device_type is the type of the device (gpu, fpga, etc).
+	DeviceType string `protobuf:"bytes,2,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	// device_name is the name of the device.
+	DeviceName string `protobuf:"bytes,3,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	// devices is the set of devices detected by the plugin.
+	Devices []*DetectedDevice `protobuf:"bytes,4,rep,name=devices,proto3" json:"devices,omitempty"`
+	// node_attributes allows adding node attributes to be used for
+	// constraints or affinities.
+	NodeAttributes       map[string]string `protobuf:"bytes,5,rep,name=node_attributes,json=nodeAttributes,proto3" json:"node_attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DetectedDevices) Reset()         { *m = DetectedDevices{} }
+func (m *DetectedDevices) String() string { return proto.CompactTextString(m) }
+func (*DetectedDevices) ProtoMessage()    {}
+func (*DetectedDevices) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{0}
+}
+func (m *DetectedDevices) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DetectedDevices.Unmarshal(m, b)
+}
+func (m *DetectedDevices) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DetectedDevices.Marshal(b, m, deterministic)
+}
+func (dst *DetectedDevices) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DetectedDevices.Merge(dst, src)
+}
+func (m *DetectedDevices) XXX_Size() int {
+	return xxx_messageInfo_DetectedDevices.Size(m)
+}
+func (m *DetectedDevices) XXX_DiscardUnknown() {
+	xxx_messageInfo_DetectedDevices.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DetectedDevices proto.InternalMessageInfo
+
+func (m *DetectedDevices) GetVendor() string {
+	if m != nil {
+		return m.Vendor
+	}
+	return ""
+}
+
+func (m *DetectedDevices) GetDeviceType() string {
+	if m != nil {
+		return m.DeviceType
+	}
+	return ""
+}
+
+func (m *DetectedDevices) GetDeviceName() string {
+	if m != nil {
+		return m.DeviceName
+	}
+	return ""
+}
+
+func (m *DetectedDevices) GetDevices() []*DetectedDevice {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+func (m *DetectedDevices) GetNodeAttributes() map[string]string {
+	if m != nil {
+		return m.NodeAttributes
+	}
+	return nil
+}
+
+// DetectedDevice is a single detected device.
+type DetectedDevice struct {
+	// ID is the ID of the device. This ID is used during
+	// allocation.
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// Health of the device.
+	Healthy bool `protobuf:"varint,2,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	// health_description allows the device plugin to optionally
+	// annotate the health field with a human readable reason.
+	HealthDescription string `protobuf:"bytes,3,opt,name=health_description,json=healthDescription,proto3" json:"health_description,omitempty"`
+	// pci_bus_id is the PCI bus ID for the device. If reported, it
+	// allows Nomad to make NUMA aware optimizations.
+	PciBusId             string   `protobuf:"bytes,4,opt,name=pci_bus_id,json=pciBusId,proto3" json:"pci_bus_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DetectedDevice) Reset()         { *m = DetectedDevice{} }
+func (m *DetectedDevice) String() string { return proto.CompactTextString(m) }
+func (*DetectedDevice) ProtoMessage()    {}
+func (*DetectedDevice) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{1}
+}
+func (m *DetectedDevice) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DetectedDevice.Unmarshal(m, b)
+}
+func (m *DetectedDevice) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DetectedDevice.Marshal(b, m, deterministic)
+}
+func (dst *DetectedDevice) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DetectedDevice.Merge(dst, src)
+}
+func (m *DetectedDevice) XXX_Size() int {
+	return xxx_messageInfo_DetectedDevice.Size(m)
+}
+func (m *DetectedDevice) XXX_DiscardUnknown() {
+	xxx_messageInfo_DetectedDevice.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DetectedDevice proto.InternalMessageInfo
+
+func (m *DetectedDevice) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *DetectedDevice) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
+	}
+	return false
+}
+
+func (m *DetectedDevice) GetHealthDescription() string {
+	if m != nil {
+		return m.HealthDescription
+	}
+	return ""
+}
+
+func (m *DetectedDevice) GetPciBusId() string {
+	if m != nil {
+		return m.PciBusId
+	}
+	return ""
+}
+
+// ReserveRequest is used to ask the device driver for information on
+// how to allocate the requested devices.
+type ReserveRequest struct {
+	// device_ids are the requested devices.
+	DeviceIds            []string `protobuf:"bytes,1,rep,name=device_ids,json=deviceIds,proto3" json:"device_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReserveRequest) Reset()         { *m = ReserveRequest{} }
+func (m *ReserveRequest) String() string { return proto.CompactTextString(m) }
+func (*ReserveRequest) ProtoMessage()    {}
+func (*ReserveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{2}
+}
+func (m *ReserveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReserveRequest.Unmarshal(m, b)
+}
+func (m *ReserveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReserveRequest.Marshal(b, m, deterministic)
+}
+func (dst *ReserveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReserveRequest.Merge(dst, src)
+}
+func (m *ReserveRequest) XXX_Size() int {
+	return xxx_messageInfo_ReserveRequest.Size(m)
+}
+func (m *ReserveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReserveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReserveRequest proto.InternalMessageInfo
+
+func (m *ReserveRequest) GetDeviceIds() []string {
+	if m != nil {
+		return m.DeviceIds
+	}
+	return nil
+}
+
+// ReserveResponse informs Nomad how to expose the requested devices
+// to the the task.
+type ReserveResponse struct {
+	// container_res contains information on how to mount the device
+	// into a task isolated using container technologies (where the
+	// host is shared)
+	ContainerRes         *ContainerReservation `protobuf:"bytes,1,opt,name=container_res,json=containerRes,proto3" json:"container_res,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ReserveResponse) Reset()         { *m = ReserveResponse{} }
+func (m *ReserveResponse) String() string { return proto.CompactTextString(m) }
+func (*ReserveResponse) ProtoMessage()    {}
+func (*ReserveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{3}
+}
+func (m *ReserveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReserveResponse.Unmarshal(m, b)
+}
+func (m *ReserveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReserveResponse.Marshal(b, m, deterministic)
+}
+func (dst *ReserveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReserveResponse.Merge(dst, src)
+}
+func (m *ReserveResponse) XXX_Size() int {
+	return xxx_messageInfo_ReserveResponse.Size(m)
+}
+func (m *ReserveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReserveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReserveResponse proto.InternalMessageInfo
+
+func (m *ReserveResponse) GetContainerRes() *ContainerReservation {
+	if m != nil {
+		return m.ContainerRes
+	}
+	return nil
+}
+
+// ContainerReservation returns how to mount the device into a
+// container that shares the host OS.
+type ContainerReservation struct {
+	// List of environment variable to be set
+	Envs map[string]string `protobuf:"bytes,1,rep,name=envs,proto3" json:"envs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Mounts for the task.
+	Mounts []*Mount `protobuf:"bytes,2,rep,name=mounts,proto3" json:"mounts,omitempty"`
+	// Devices for the task.
+	Devices              []*DeviceSpec `protobuf:"bytes,3,rep,name=devices,proto3" json:"devices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ContainerReservation) Reset()         { *m = ContainerReservation{} }
+func (m *ContainerReservation) String() string { return proto.CompactTextString(m) }
+func (*ContainerReservation) ProtoMessage()    {}
+func (*ContainerReservation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{4}
+}
+func (m *ContainerReservation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContainerReservation.Unmarshal(m, b)
+}
+func (m *ContainerReservation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContainerReservation.Marshal(b, m, deterministic)
+}
+func (dst *ContainerReservation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContainerReservation.Merge(dst, src)
+}
+func (m *ContainerReservation) XXX_Size() int {
+	return xxx_messageInfo_ContainerReservation.Size(m)
+}
+func (m *ContainerReservation) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContainerReservation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContainerReservation proto.InternalMessageInfo
+
+func (m *ContainerReservation) GetEnvs() map[string]string {
+	if m != nil {
+		return m.Envs
+	}
+	return nil
+}
+
+func (m *ContainerReservation) GetMounts() []*Mount {
+	if m != nil {
+		return m.Mounts
+	}
+	return nil
+}
+
+func (m *ContainerReservation) GetDevices() []*DeviceSpec {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+// Mount specifies a host volume to mount into a task.
+// where device library or tools are installed on host and task
+type Mount struct {
+	// Path of the mount within the task.
+	TaskPath string `protobuf:"bytes,1,opt,name=task_path,json=taskPath,proto3" json:"task_path,omitempty"`
+	// Path of the mount on the host.
+	HostPath string `protobuf:"bytes,2,opt,name=host_path,json=hostPath,proto3" json:"host_path,omitempty"`
+	// If set, the mount is read-only.
+	ReadOnly             bool     `protobuf:"varint,3,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Mount) Reset()         { *m = Mount{} }
+func (m *Mount) String() string { return proto.CompactTextString(m) }
+func (*Mount) ProtoMessage()    {}
+func (*Mount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{5}
+}
+func (m *Mount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Mount.Unmarshal(m, b)
+}
+func (m *Mount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Mount.Marshal(b, m, deterministic)
+}
+func (dst *Mount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Mount.Merge(dst, src)
+}
+func (m *Mount) XXX_Size() int {
+	return xxx_messageInfo_Mount.Size(m)
+}
+func (m *Mount) XXX_DiscardUnknown() {
+	xxx_messageInfo_Mount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Mount proto.InternalMessageInfo
+
+func (m *Mount) GetTaskPath() string {
+	if m != nil {
+		return m.TaskPath
+	}
+	return ""
+}
+
+func (m *Mount) GetHostPath() string {
+	if m != nil {
+		return m.HostPath
+	}
+	return ""
+}
+
+func (m *Mount) GetReadOnly() bool {
+	if m != nil {
+		return m.ReadOnly
+	}
+	return false
+}
+
+// DeviceSpec specifies a host device to mount into a task.
+type DeviceSpec struct {
+	// Path of the device within the task.
+	TaskPath string `protobuf:"bytes,1,opt,name=task_path,json=taskPath,proto3" json:"task_path,omitempty"`
+	// Path of the device on the host.
+	HostPath string `protobuf:"bytes,2,opt,name=host_path,json=hostPath,proto3" json:"host_path,omitempty"`
+	// Cgroups permissions of the device, candidates are one or more of
+	// * r - allows task to read from the specified device.
+	// * w - allows task to write to the specified device.
+	// * m - allows task to create device files that do not yet exist
+	Permissions          string   `protobuf:"bytes,3,opt,name=permissions,proto3" json:"permissions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeviceSpec) Reset()         { *m = DeviceSpec{} }
+func (m *DeviceSpec) String() string { return proto.CompactTextString(m) }
+func (*DeviceSpec) ProtoMessage()    {}
+func (*DeviceSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{6}
+}
+func (m *DeviceSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeviceSpec.Unmarshal(m, b)
+}
+func (m *DeviceSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeviceSpec.Marshal(b, m, deterministic)
+}
+func (dst *DeviceSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeviceSpec.Merge(dst, src)
+}
+func (m *DeviceSpec) XXX_Size() int {
+	return xxx_messageInfo_DeviceSpec.Size(m)
+}
+func (m *DeviceSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeviceSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeviceSpec proto.InternalMessageInfo
+
+func (m *DeviceSpec) GetTaskPath() string {
+	if m != nil {
+		return m.TaskPath
+	}
+	return ""
+}
+
+func (m *DeviceSpec) GetHostPath() string {
+	if m != nil {
+		return m.HostPath
+	}
+	return ""
+}
+
+func (m *DeviceSpec) GetPermissions() string {
+	if m != nil {
+		return m.Permissions
+	}
+	return ""
+}
+
+// StatsRequest is used to parameterize the retrieval of statistics.
+type StatsRequest struct {
+	// collection_interval is the interval at which to collect statistics.
+	CollectionInterval   *duration.Duration `protobuf:"bytes,1,opt,name=collection_interval,json=collectionInterval,proto3" json:"collection_interval,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *StatsRequest) Reset()         { *m = StatsRequest{} }
+func (m *StatsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatsRequest) ProtoMessage()    {}
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{7}
+}
+func (m *StatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsRequest.Unmarshal(m, b)
+}
+func (m *StatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsRequest.Marshal(b, m, deterministic)
+}
+func (dst *StatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsRequest.Merge(dst, src)
+}
+func (m *StatsRequest) XXX_Size() int {
+	return xxx_messageInfo_StatsRequest.Size(m)
+}
+func (m *StatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsRequest proto.InternalMessageInfo
+
+func (m *StatsRequest) GetCollectionInterval() *duration.Duration {
+	if m != nil {
+		return m.CollectionInterval
+	}
+	return nil
+}
+
+// StatsResponse returns the statistics for each device group.
+type StatsResponse struct {
+	// groups contains the statistics of each device group.
+	Groups               []*DeviceGroupStats `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *StatsResponse) Reset()         { *m = StatsResponse{} }
+func (m *StatsResponse) String() string { return proto.CompactTextString(m) }
+func (*StatsResponse) ProtoMessage()    {}
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{8}
+}
+func (m *StatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsResponse.Unmarshal(m, b)
+}
+func (m *StatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsResponse.Marshal(b, m, deterministic)
+}
+func (dst *StatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsResponse.Merge(dst, src)
+}
+func (m *StatsResponse) XXX_Size() int {
+	return xxx_messageInfo_StatsResponse.Size(m)
+}
+func (m *StatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsResponse proto.InternalMessageInfo
+
+func (m *StatsResponse) GetGroups() []*DeviceGroupStats {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+// DeviceGroupStats contains the statistics of the devices of a device group,
+// identified by the vendor, type and name of the devices.
+type DeviceGroupStats struct {
+	// vendor is the name of the vendor of the devices
+	Vendor string `protobuf:"bytes,1,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	// device_type is the type of the devices (gpu, fpga, etc).
+	DeviceType string `protobuf:"bytes,2,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	// device_name is the name of the devices.
+	DeviceName string `protobuf:"bytes,3,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	// instance_stats maps the ID of each device to its statistics.
+	InstanceStats        map[string]*DeviceStats `protobuf:"bytes,4,rep,name=instance_stats,json=instanceStats,proto3" json:"instance_stats,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *DeviceGroupStats) Reset()         { *m = DeviceGroupStats{} }
+func (m *DeviceGroupStats) String() string { return proto.CompactTextString(m) }
+func (*DeviceGroupStats) ProtoMessage()    {}
+func (*DeviceGroupStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{9}
+}
+func (m *DeviceGroupStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeviceGroupStats.Unmarshal(m, b)
+}
+func (m *DeviceGroupStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeviceGroupStats.Marshal(b, m, deterministic)
+}
+func (dst *DeviceGroupStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeviceGroupStats.Merge(dst, src)
+}
+func (m *DeviceGroupStats) XXX_Size() int {
+	return xxx_messageInfo_DeviceGroupStats.Size(m)
+}
+func (m *DeviceGroupStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeviceGroupStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeviceGroupStats proto.InternalMessageInfo
+
+func (m *DeviceGroupStats) GetVendor() string {
+	if m != nil {
+		return m.Vendor
+	}
+	return ""
+}
+
+func (m *DeviceGroupStats) GetDeviceType() string {
+	if m != nil {
+		return m.DeviceType
+	}
+	return ""
+}
+
+func (m *DeviceGroupStats) GetDeviceName() string {
+	if m != nil {
+		return m.DeviceName
+	}
+	return ""
+}
+
+func (m *DeviceGroupStats) GetInstanceStats() map[string]*DeviceStats {
+	if m != nil {
+		return m.InstanceStats
+	}
+	return nil
+}
+
+// DeviceStats is the statistics of a single device.
+type DeviceStats struct {
+	// summary is the single statistic that best describes the usage of the
+	// device, such as its utilisation.
+	Summary *StatValue `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	// stats contains the detailed statistics of the device.
+	Stats *StatObject `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	// timestamp is the time the statistics were collected.
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *DeviceStats) Reset()         { *m = DeviceStats{} }
+func (m *DeviceStats) String() string { return proto.CompactTextString(m) }
+func (*DeviceStats) ProtoMessage()    {}
+func (*DeviceStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{10}
+}
+func (m *DeviceStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeviceStats.Unmarshal(m, b)
+}
+func (m *DeviceStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeviceStats.Marshal(b, m, deterministic)
+}
+func (dst *DeviceStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeviceStats.Merge(dst, src)
+}
+func (m *DeviceStats) XXX_Size() int {
+	return xxx_messageInfo_DeviceStats.Size(m)
+}
+func (m *DeviceStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeviceStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeviceStats proto.InternalMessageInfo
+
+func (m *DeviceStats) GetSummary() *StatValue {
+	if m != nil {
+		return m.Summary
+	}
+	return nil
+}
+
+func (m *DeviceStats) GetStats() *StatObject {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+func (m *DeviceStats) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+// StatObject is a collection of statistics, either attributes or nested
+// collections.
+type StatObject struct {
+	// nested maps a name to a nested collection of statistics.
+	Nested map[string]*StatObject `protobuf:"bytes,1,rep,name=nested,proto3" json:"nested,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// attributes maps the name of a statistic to its value.
+	Attributes           map[string]*StatValue `protobuf:"bytes,2,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *StatObject) Reset()         { *m = StatObject{} }
+func (m *StatObject) String() string { return proto.CompactTextString(m) }
+func (*StatObject) ProtoMessage()    {}
+func (*StatObject) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{11}
+}
+func (m *StatObject) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatObject.Unmarshal(m, b)
+}
+func (m *StatObject) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatObject.Marshal(b, m, deterministic)
+}
+func (dst *StatObject) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatObject.Merge(dst, src)
+}
+func (m *StatObject) XXX_Size() int {
+	return xxx_messageInfo_StatObject.Size(m)
+}
+func (m *StatObject) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatObject.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatObject proto.InternalMessageInfo
+
+func (m *StatObject) GetNested() map[string]*StatObject {
+	if m != nil {
+		return m.Nested
+	}
+	return nil
+}
+
+func (m *StatObject) GetAttributes() map[string]*StatValue {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+// StatValue is the value of a statistic. A numeric value may be given as a
+// numerator and an optional denominator, such as used and total memory.
+type StatValue struct {
+	// float_numerator_val and float_denominator_val are a floating point
+	// value.
+	FloatNumeratorVal   *wrappers.DoubleValue `protobuf:"bytes,1,opt,name=float_numerator_val,json=floatNumeratorVal,proto3" json:"float_numerator_val,omitempty"`
+	FloatDenominatorVal *wrappers.DoubleValue `protobuf:"bytes,2,opt,name=float_denominator_val,json=floatDenominatorVal,proto3" json:"float_denominator_val,omitempty"`
+	// int_numerator_val and int_denominator_val are an integer value.
+	IntNumeratorVal   *wrappers.Int64Value `protobuf:"bytes,3,opt,name=int_numerator_val,json=intNumeratorVal,proto3" json:"int_numerator_val,omitempty"`
+	IntDenominatorVal *wrappers.Int64Value `protobuf:"bytes,4,opt,name=int_denominator_val,json=intDenominatorVal,proto3" json:"int_denominator_val,omitempty"`
+	// string_val is a string value.
+	StringVal *wrappers.StringValue `protobuf:"bytes,5,opt,name=string_val,json=stringVal,proto3" json:"string_val,omitempty"`
+	// bool_val is a boolean value.
+	BoolVal *wrappers.BoolValue `protobuf:"bytes,6,opt,name=bool_val,json=boolVal,proto3" json:"bool_val,omitempty"`
+	// unit is the unit of the value, such as MiB or %.
+	Unit string `protobuf:"bytes,7,opt,name=unit,proto3" json:"unit,omitempty"`
+	// desc describes the statistic.
+	Desc                 string   `protobuf:"bytes,8,opt,name=desc,proto3" json:"desc,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatValue) Reset()         { *m = StatValue{} }
+func (m *StatValue) String() string { return proto.CompactTextString(m) }
+func (*StatValue) ProtoMessage()    {}
+func (*StatValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_3a4bb7804aa327bc, []int{12}
+}
+func (m *StatValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatValue.Unmarshal(m, b)
+}
+func (m *StatValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatValue.Marshal(b, m, deterministic)
+}
+func (dst *StatValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatValue.Merge(dst, src)
+}
+func (m *StatValue) XXX_Size() int {
+	return xxx_messageInfo_StatValue.Size(m)
+}
+func (m *StatValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatValue proto.InternalMessageInfo
+
+func (m *StatValue) GetFloatNumeratorVal() *wrappers.DoubleValue {
+	if m != nil {
+		return m.FloatNumeratorVal
+	}
+	return nil
+}
+
+func (m *StatValue) GetFloatDenominatorVal() *wrappers.DoubleValue {
+	if m != nil {
+		return m.FloatDenominatorVal
+	}
+	return nil
+}
+
+func (m *StatValue) GetIntNumeratorVal() *wrappers.Int64Value {
+	if m != nil {
+		return m.IntNumeratorVal
+	}
+	return nil
+}
+
+func (m *StatValue) GetIntDenominatorVal() *wrappers.Int64Value {
+	if m != nil {
+		return m.IntDenominatorVal
+	}
+	return nil
+}
+
+func (m *StatValue) GetStringVal() *wrappers.StringValue {
+	if m != nil {
+		return m.StringVal
+	}
+	return nil
+}
+
+func (m *StatValue) GetBoolVal() *wrappers.BoolValue {
+	if m != nil {
+		return m.BoolVal
+	}
+	return nil
+}
+
+func (m *StatValue) GetUnit() string {
+	if m != nil {
+		return m.Unit
+	}
+	return ""
+}
+
+func (m *StatValue) GetDesc() string {
+	if m != nil {
+		return m.Desc
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*DetectedDevices)(nil), "hashicorp.nomad.plugins.device.proto.DetectedDevices")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.device.proto.DetectedDevices.NodeAttributesEntry")
+	proto.RegisterType((*DetectedDevice)(nil), "hashicorp.nomad.plugins.device.proto.DetectedDevice")
+	proto.RegisterType((*ReserveRequest)(nil), "hashicorp.nomad.plugins.device.proto.ReserveRequest")
+	proto.RegisterType((*ReserveResponse)(nil), "hashicorp.nomad.plugins.device.proto.ReserveResponse")
+	proto.RegisterType((*ContainerReservation)(nil), "hashicorp.nomad.plugins.device.proto.ContainerReservation")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.device.proto.ContainerReservation.EnvsEntry")
+	proto.RegisterType((*Mount)(nil), "hashicorp.nomad.plugins.device.proto.Mount")
+	proto.RegisterType((*DeviceSpec)(nil), "hashicorp.nomad.plugins.device.proto.DeviceSpec")
+	proto.RegisterType((*StatsRequest)(nil), "hashicorp.nomad.plugins.device.proto.StatsRequest")
+	proto.RegisterType((*StatsResponse)(nil), "hashicorp.nomad.plugins.device.proto.StatsResponse")
+	proto.RegisterType((*DeviceGroupStats)(nil), "hashicorp.nomad.plugins.device.proto.DeviceGroupStats")
+	proto.RegisterMapType((map[string]*DeviceStats)(nil), "hashicorp.nomad.plugins.device.proto.DeviceGroupStats.InstanceStatsEntry")
+	proto.RegisterType((*DeviceStats)(nil), "hashicorp.nomad.plugins.device.proto.DeviceStats")
+	proto.RegisterType((*StatObject)(nil), "hashicorp.nomad.plugins.device.proto.StatObject")
+	proto.RegisterMapType((map[string]*StatObject)(nil), "hashicorp.nomad.plugins.device.proto.StatObject.NestedEntry")
+	proto.RegisterMapType((map[string]*StatValue)(nil), "hashicorp.nomad.plugins.device.proto.StatObject.AttributesEntry")
+	proto.RegisterType((*StatValue)(nil), "hashicorp.nomad.plugins.device.proto.StatValue")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// DevicePluginClient is the client API for DevicePlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DevicePluginClient interface {
+	// Fingerprint allows the device plugin to return a set of
+	// detected devices and provide a mechanism to update the state of
+	// the device.
+	Fingerprint(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (DevicePlugin_FingerprintClient, error)
+	// Reserve is called by the client before starting an allocation
+	// that requires access to the plugin’s devices. The plugin can use
+	// this to run any setup steps and provides the mounting details to
+	// the Nomad client
+	Reserve(ctx context.Context, in *ReserveRequest, opts ...grpc.CallOption) (*ReserveResponse, error)
+	// Stats returns a stream of statistics of the devices detected by the
+	// plugin, collected at the requested interval.
+	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (DevicePlugin_StatsClient, error)
+}
+
+type devicePluginClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDevicePluginClient(cc *grpc.ClientConn) DevicePluginClient {
+	return &devicePluginClient{cc}
+}
+
+func (c *devicePluginClient) Fingerprint(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (DevicePlugin_FingerprintClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DevicePlugin_serviceDesc.Streams[0], "/hashicorp.nomad.plugins.device.proto.DevicePlugin/Fingerprint", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &devicePluginFingerprintClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DevicePlugin_FingerprintClient interface {
+	Recv() (*DetectedDevices, error)
+	grpc.ClientStream
+}
+
+type devicePluginFingerprintClient struct {
+	grpc.ClientStream
+}
+
+func (x *devicePluginFingerprintClient) Recv() (*DetectedDevices, error) {
+	m := new(DetectedDevices)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *devicePluginClient) Reserve(ctx context.Context, in *ReserveRequest, opts ...grpc.CallOption) (*ReserveResponse, error) {
+	out := new(ReserveResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.device.proto.DevicePlugin/Reserve", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devicePluginClient) Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (DevicePlugin_StatsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DevicePlugin_serviceDesc.Streams[1], "/hashicorp.nomad.plugins.device.proto.DevicePlugin/Stats", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &devicePluginStatsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DevicePlugin_StatsClient interface {
+	Recv() (*StatsResponse, error)
+	grpc.ClientStream
+}
+
+type devicePluginStatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *devicePluginStatsClient) Recv() (*StatsResponse, error) {
+	m := new(StatsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DevicePluginServer is the server API for DevicePlugin service.
+type DevicePluginServer interface {
+	// Fingerprint allows the device plugin to return a set of
+	// detected devices and provide a mechanism to update the state of
+	// the device.
+	Fingerprint(*empty.Empty, DevicePlugin_FingerprintServer) error
+	// Reserve is called by the client before starting an allocation
+	// that requires access to the plugin’s devices. The plugin can use
+	// this to run any setup steps and provides the mounting details to
+	// the Nomad client
+	Reserve(context.Context, *ReserveRequest) (*ReserveResponse, error)
+	// Stats returns a stream of statistics of the devices detected by the
+	// plugin, collected at the requested interval.
+	Stats(*StatsRequest, DevicePlugin_StatsServer) error
+}
+
+func RegisterDevicePluginServer(s *grpc.Server, srv DevicePluginServer) {
+	s.RegisterService(&_DevicePlugin_serviceDesc, srv)
+}
+
+func _DevicePlugin_Fingerprint_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(empty.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DevicePluginServer).Fingerprint(m, &devicePluginFingerprintServer{stream})
+}
+
+type DevicePlugin_FingerprintServer interface {
+	Send(*DetectedDevices) error
+	grpc.ServerStream
+}
+
+type devicePluginFingerprintServer struct {
+	grpc.ServerStream
+}
+
+func (x *devicePluginFingerprintServer) Send(m *DetectedDevices) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DevicePlugin_Reserve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevicePluginServer).Reserve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.device.proto.DevicePlugin/Reserve",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevicePluginServer).Reserve(ctx, req.(*ReserveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DevicePlugin_Stats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DevicePluginServer).Stats(m, &devicePluginStatsServer{stream})
+}
+
+type DevicePlugin_StatsServer interface {
+	Send(*StatsResponse) error
+	grpc.ServerStream
+}
+
+type devicePluginStatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *devicePluginStatsServer) Send(m *StatsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _DevicePlugin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "hashicorp.nomad.plugins.device.proto.DevicePlugin",
+	HandlerType: (*DevicePluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Reserve",
+			Handler:    _DevicePlugin_Reserve_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Fingerprint",
+			Handler:       _DevicePlugin_Fingerprint_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Stats",
+			Handler:       _DevicePlugin_Stats_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "github.com/hashicorp/nomad/plugins/device/proto/device.proto",
+}
+
+func init() {
+	proto.RegisterFile("github.com/hashicorp/nomad/plugins/device/proto/device.proto", fileDescriptor_device_3a4bb7804aa327bc)
+}
+
+var fileDescriptor_device_3a4bb7804aa327bc = []byte{
+	// 1120 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0xdd, 0x6e, 0xdb, 0xb6,
+	0x17, 0xaf, 0xbf, 0xed, 0xe3, 0x7c, 0x34, 0x4c, 0xff, 0x85, 0xfe, 0x4e, 0xb7, 0x06, 0xc2, 0x2e,
+	0x02, 0x0c, 0x93, 0x33, 0xb7, 0xe9, 0x8a, 0xae, 0x17, 0x6b, 0xea, 0x24, 0x70, 0xb7, 0xb9, 0x81,
+	0x12, 0x14, 0x43, 0x2f, 0xa6, 0xca, 0x12, 0x6b, 0x73, 0x91, 0x48, 0x4d, 0xa4, 0xbc, 0xf9, 0x09,
+	0xf6, 0x1c, 0x7b, 0x8d, 0xbd, 0xc6, 0xee, 0x06, 0x0c, 0xd8, 0x33, 0xec, 0x09, 0x06, 0x91, 0x94,
+	0x2d, 0xdb, 0x09, 0x6a, 0x67, 0xd8, 0x95, 0x75, 0x3e, 0x7e, 0x3f, 0x1e, 0xf3, 0xfc, 0x78, 0x48,
+	0x78, 0x3e, 0x24, 0x62, 0x94, 0x0c, 0x2c, 0x8f, 0x85, 0xed, 0x91, 0xcb, 0x47, 0xc4, 0x63, 0x71,
+	0xd4, 0xa6, 0x2c, 0x74, 0xfd, 0x76, 0x14, 0x24, 0x43, 0x42, 0x79, 0xdb, 0xc7, 0x63, 0xe2, 0xe1,
+	0x76, 0x14, 0x33, 0xc1, 0xb4, 0x61, 0x49, 0x03, 0x7d, 0x32, 0x85, 0x58, 0x12, 0x62, 0x69, 0x88,
+	0x95, 0xcf, 0x6a, 0x7d, 0x3c, 0x64, 0x6c, 0x18, 0x68, 0x82, 0x41, 0xf2, 0xbe, 0xed, 0x27, 0xb1,
+	0x2b, 0x08, 0xa3, 0x3a, 0xbe, 0xb7, 0x18, 0xc7, 0x61, 0x24, 0x26, 0x3a, 0xf8, 0x70, 0x31, 0x28,
+	0x48, 0x88, 0xb9, 0x70, 0xc3, 0xe8, 0x26, 0xf6, 0x9f, 0x62, 0x37, 0x8a, 0x70, 0xcc, 0x55, 0xdc,
+	0xfc, 0xbb, 0x08, 0xdb, 0x5d, 0x2c, 0xb0, 0x27, 0xb0, 0xdf, 0x95, 0x65, 0x71, 0x74, 0x1f, 0xaa,
+	0x63, 0x4c, 0x7d, 0x16, 0x1b, 0x85, 0xfd, 0xc2, 0x41, 0xc3, 0xd6, 0x16, 0x7a, 0x08, 0x4d, 0x55,
+	0xb9, 0x23, 0x26, 0x11, 0x36, 0x8a, 0x32, 0x08, 0xca, 0x75, 0x39, 0x89, 0x70, 0x2e, 0x81, 0xba,
+	0x21, 0x36, 0x4a, 0xf9, 0x84, 0xbe, 0x1b, 0x62, 0xd4, 0x87, 0x9a, 0xb2, 0xb8, 0x51, 0xde, 0x2f,
+	0x1d, 0x34, 0x3b, 0x8f, 0xad, 0x55, 0xf6, 0xc8, 0x9a, 0xaf, 0xd0, 0xce, 0x48, 0x50, 0x0c, 0xdb,
+	0x94, 0xf9, 0xd8, 0x71, 0x85, 0x88, 0xc9, 0x20, 0x11, 0x98, 0x1b, 0x15, 0xc9, 0xdb, 0xbb, 0x0d,
+	0x2f, 0xb7, 0xfa, 0xcc, 0xc7, 0x2f, 0xa6, 0x5c, 0x27, 0x54, 0xc4, 0x13, 0x7b, 0x8b, 0xce, 0x39,
+	0x5b, 0x2f, 0x60, 0xf7, 0x9a, 0x34, 0x74, 0x17, 0x4a, 0x57, 0x78, 0xa2, 0x77, 0x2c, 0xfd, 0x44,
+	0xf7, 0xa0, 0x32, 0x76, 0x83, 0x24, 0xdb, 0x28, 0x65, 0x3c, 0x2b, 0x3e, 0x2d, 0x98, 0xbf, 0x14,
+	0x60, 0x6b, 0x7e, 0x69, 0xb4, 0x05, 0xc5, 0x5e, 0x57, 0xa3, 0x8b, 0xbd, 0x2e, 0x32, 0xa0, 0x36,
+	0xc2, 0x6e, 0x20, 0x46, 0x13, 0x09, 0xaf, 0xdb, 0x99, 0x89, 0x3e, 0x03, 0xa4, 0x3e, 0x1d, 0x1f,
+	0x73, 0x2f, 0x26, 0x51, 0xaa, 0x15, 0xbd, 0xd7, 0x3b, 0x2a, 0xd2, 0x9d, 0x05, 0xd0, 0x03, 0x80,
+	0xc8, 0x23, 0xce, 0x20, 0xe1, 0x0e, 0xf1, 0x8d, 0xb2, 0x4c, 0xab, 0x47, 0x1e, 0x39, 0x4e, 0x78,
+	0xcf, 0x37, 0xdb, 0xb0, 0x65, 0x63, 0x8e, 0xe3, 0x31, 0xb6, 0xf1, 0x8f, 0x09, 0xe6, 0x02, 0x7d,
+	0x04, 0xba, 0x61, 0x0e, 0xf1, 0xb9, 0x51, 0xd8, 0x2f, 0x1d, 0x34, 0xec, 0x86, 0xf2, 0xf4, 0x7c,
+	0x6e, 0xc6, 0xb0, 0x3d, 0x05, 0xf0, 0x88, 0x51, 0x8e, 0x91, 0x03, 0x9b, 0x1e, 0xa3, 0xc2, 0x25,
+	0x14, 0xc7, 0x4e, 0x8c, 0xb9, 0xfc, 0x17, 0xcd, 0xce, 0xb3, 0xd5, 0x5a, 0xf0, 0x32, 0x83, 0x2a,
+	0x5a, 0xa9, 0x7c, 0x7b, 0xc3, 0xcb, 0x79, 0xcd, 0xdf, 0x8a, 0x70, 0xef, 0xba, 0x34, 0xf4, 0x1d,
+	0x94, 0x31, 0x1d, 0xab, 0x2a, 0x9b, 0x9d, 0xee, 0xed, 0x17, 0xb4, 0x4e, 0xe8, 0x58, 0xb7, 0x5b,
+	0x32, 0xa2, 0x97, 0x50, 0x0d, 0x59, 0x42, 0x05, 0x37, 0x8a, 0x92, 0xfb, 0xd3, 0xd5, 0xb8, 0xbf,
+	0x4d, 0x31, 0xb6, 0x86, 0xa2, 0x57, 0x33, 0xb5, 0x97, 0x24, 0xcb, 0xe1, 0xaa, 0xaa, 0x4c, 0x8d,
+	0x8b, 0x08, 0x7b, 0x53, 0xa5, 0xb7, 0xbe, 0x80, 0xc6, 0xb4, 0xc6, 0xb5, 0xb4, 0xf6, 0x3d, 0x54,
+	0x64, 0x55, 0x68, 0x0f, 0x1a, 0xc2, 0xe5, 0x57, 0x4e, 0xe4, 0x8a, 0x91, 0x86, 0xd6, 0x53, 0xc7,
+	0xb9, 0x2b, 0x46, 0x69, 0x70, 0xc4, 0xb8, 0x50, 0x41, 0xc5, 0x51, 0x4f, 0x1d, 0x59, 0x30, 0xc6,
+	0xae, 0xef, 0x30, 0x1a, 0x4c, 0xa4, 0xd0, 0xea, 0x76, 0x3d, 0x75, 0xbc, 0xa6, 0xc1, 0xc4, 0x1c,
+	0x01, 0xcc, 0xea, 0xfd, 0x17, 0x8b, 0xec, 0x43, 0x33, 0xc2, 0x71, 0x48, 0x38, 0x27, 0x8c, 0x72,
+	0xad, 0xe7, 0xbc, 0xcb, 0x7c, 0x0b, 0x1b, 0x17, 0xc2, 0x15, 0x3c, 0x53, 0xea, 0x2b, 0xd8, 0xf5,
+	0x58, 0x10, 0x60, 0x2f, 0xed, 0xa0, 0x43, 0xa8, 0x48, 0xbb, 0x19, 0x68, 0xf5, 0xfd, 0xdf, 0x52,
+	0x83, 0xcf, 0xca, 0x06, 0x9f, 0xd5, 0xd5, 0x63, 0xd5, 0x46, 0x33, 0x54, 0x4f, 0x83, 0x4c, 0x07,
+	0x36, 0x35, 0xb7, 0x16, 0x75, 0x1f, 0xaa, 0xc3, 0x98, 0x25, 0x51, 0x26, 0xae, 0x27, 0xeb, 0xb4,
+	0xee, 0x2c, 0x45, 0x2a, 0x3e, 0xcd, 0x62, 0xfe, 0x5e, 0x84, 0xbb, 0x8b, 0xc1, 0xff, 0x70, 0xd0,
+	0x46, 0xb0, 0x45, 0x28, 0x17, 0x2e, 0xf5, 0xb0, 0xc3, 0xd3, 0xb5, 0x8c, 0xf2, 0x7a, 0x73, 0x71,
+	0xbe, 0x52, 0xab, 0xa7, 0xc9, 0xa4, 0xa5, 0x0e, 0xca, 0x26, 0xc9, 0xfb, 0x5a, 0x1c, 0xd0, 0x72,
+	0xd2, 0x35, 0x4a, 0x3d, 0xcb, 0x2b, 0xb5, 0xd9, 0xf9, 0x7c, 0xad, 0x23, 0x21, 0xb7, 0x34, 0x27,
+	0xee, 0x3f, 0x0a, 0xd0, 0xcc, 0x85, 0x50, 0x0f, 0x6a, 0x3c, 0x09, 0x43, 0x37, 0x9e, 0x68, 0x19,
+	0xb4, 0x57, 0xa3, 0x4f, 0xd1, 0x6f, 0x52, 0x56, 0x3b, 0xc3, 0xa3, 0x53, 0xa8, 0xa8, 0x8d, 0x53,
+	0x75, 0x1e, 0xae, 0x4e, 0xf4, 0x7a, 0xf0, 0x03, 0xf6, 0x84, 0xad, 0xe0, 0xe8, 0x29, 0x34, 0xa6,
+	0x77, 0xb2, 0x6c, 0x54, 0xb3, 0xd3, 0x5a, 0xd2, 0xe6, 0x65, 0x96, 0x61, 0xcf, 0x92, 0xcd, 0x5f,
+	0x4b, 0x00, 0x33, 0x3e, 0x74, 0x09, 0x55, 0x8a, 0xb9, 0xc0, 0xbe, 0x56, 0xe4, 0xf3, 0x75, 0x2b,
+	0xb2, 0xfa, 0x12, 0xae, 0xba, 0xa7, 0xb9, 0xd0, 0x3b, 0x80, 0xdc, 0xe5, 0xa9, 0x86, 0xdd, 0x57,
+	0x6b, 0x33, 0x2f, 0xde, 0x99, 0x39, 0xce, 0xd6, 0x15, 0x34, 0x73, 0x0b, 0x5f, 0xa3, 0x88, 0xd3,
+	0x79, 0x45, 0xdc, 0x62, 0xa7, 0xa7, 0x82, 0x68, 0x51, 0xd8, 0xfe, 0xf0, 0xc5, 0x7c, 0x32, 0xbf,
+	0xe0, 0xda, 0x1a, 0xc9, 0x09, 0xf0, 0xcf, 0x12, 0x34, 0xa6, 0x01, 0xf4, 0x0d, 0xec, 0xbe, 0x0f,
+	0x98, 0x2b, 0x1c, 0x9a, 0x84, 0x38, 0x76, 0x05, 0x8b, 0x9d, 0xd9, 0x44, 0x7a, 0xb0, 0x3c, 0x91,
+	0x58, 0x32, 0x08, 0xb0, 0xe2, 0xdc, 0x91, 0xc0, 0x7e, 0x86, 0x7b, 0xe3, 0x06, 0xe8, 0x1c, 0xfe,
+	0xa7, 0xd8, 0x7c, 0x4c, 0x59, 0x48, 0xe8, 0x94, 0xaf, 0xb8, 0x02, 0x9f, 0x2a, 0xa4, 0x3b, 0x43,
+	0xa6, 0x8c, 0x67, 0xb0, 0x43, 0xe8, 0x62, 0x75, 0x4a, 0x93, 0x7b, 0x4b, 0x6c, 0x3d, 0x2a, 0x9e,
+	0x3c, 0x56, 0x64, 0xdb, 0x84, 0xce, 0x97, 0xf6, 0x35, 0xec, 0x12, 0xba, 0x5c, 0x58, 0xf9, 0xc3,
+	0x54, 0x69, 0x01, 0x0b, 0x55, 0x7d, 0x09, 0xc0, 0x45, 0x4c, 0xe8, 0x50, 0x72, 0x54, 0x6e, 0xf8,
+	0x73, 0x17, 0x32, 0x45, 0x91, 0x34, 0x78, 0x66, 0xa0, 0x23, 0xa8, 0x0f, 0x18, 0x0b, 0x24, 0xb4,
+	0x7a, 0xc3, 0xe9, 0x3a, 0x66, 0x2c, 0xd0, 0xa7, 0x7b, 0xa0, 0x3e, 0x11, 0x82, 0x72, 0x42, 0x89,
+	0x30, 0x6a, 0x52, 0x15, 0xf2, 0x3b, 0xf5, 0xa5, 0x2f, 0x2a, 0xa3, 0xae, 0x7c, 0xe9, 0x77, 0xe7,
+	0xaf, 0x22, 0x6c, 0xa8, 0x01, 0x73, 0x2e, 0x35, 0x81, 0xde, 0x41, 0xf3, 0x94, 0xd0, 0x21, 0x8e,
+	0xa3, 0x98, 0x50, 0x81, 0xee, 0x2f, 0x2d, 0x76, 0x92, 0xbe, 0xce, 0x5b, 0x47, 0xb7, 0x7a, 0x7f,
+	0x9a, 0x77, 0x0e, 0x0b, 0xe8, 0x67, 0xa8, 0xe9, 0x17, 0x16, 0x5a, 0xf1, 0x75, 0x3c, 0xff, 0x82,
+	0x6b, 0x1d, 0xad, 0x89, 0x52, 0x37, 0x9e, 0x79, 0x07, 0x09, 0xa8, 0xa8, 0x31, 0xda, 0x59, 0xfd,
+	0x44, 0x64, 0xb7, 0x71, 0xeb, 0xd1, 0x5a, 0x98, 0x6c, 0xcd, 0xc3, 0xc2, 0x71, 0xed, 0x6d, 0x45,
+	0x6d, 0x5e, 0x55, 0xfe, 0x3c, 0xfa, 0x67, 0x00, 0x16, 0x17, 0xf8, 0xf0, 0x75, 0x0d, 0x00, 0x00,
+}
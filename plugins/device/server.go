@@ -0,0 +1,98 @@
+package device
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/plugins/device/proto"
+	"golang.org/x/net/context"
+)
+
+// devicePluginServer wraps a device plugin and exposes it via gRPC.
+type devicePluginServer struct {
+	broker *plugin.GRPCBroker
+	impl   DevicePlugin
+}
+
+// Fingerprint sends each device group of a fingerprint as a separate message,
+// as the protocol describes a single group per message.
+func (b *devicePluginServer) Fingerprint(req *empty.Empty, srv proto.DevicePlugin_FingerprintServer) error {
+	ctx := srv.Context()
+	ch, err := b.impl.Fingerprint(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case resp, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if resp.Error != nil {
+				return resp.Error
+			}
+
+			for _, group := range resp.Devices {
+				if err := srv.Send(deviceGroupToProto(group)); err == io.EOF {
+					return nil
+				} else if err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (b *devicePluginServer) Reserve(ctx context.Context, req *proto.ReserveRequest) (*proto.ReserveResponse, error) {
+	res, err := b.impl.Reserve(req.GetDeviceIds())
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ReserveResponse{
+		ContainerRes: containerReservationToProto(res),
+	}, nil
+}
+
+func (b *devicePluginServer) Stats(req *proto.StatsRequest, srv proto.DevicePlugin_StatsServer) error {
+	interval, err := durationFromProto(req.GetCollectionInterval())
+	if err != nil {
+		return err
+	}
+
+	ctx := srv.Context()
+	ch, err := b.impl.Stats(ctx, interval)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case resp, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if resp.Error != nil {
+				return resp.Error
+			}
+
+			groups, err := deviceGroupsStatsToProto(resp.Groups)
+			if err != nil {
+				return fmt.Errorf("failed to encode device stats: %v", err)
+			}
+
+			if err := srv.Send(&proto.StatsResponse{Groups: groups}); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+}
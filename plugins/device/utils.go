@@ -0,0 +1,274 @@
+package device
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	durpb "github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/nomad/plugins/device/proto"
+)
+
+// durationFromProto converts a duration, treating an unset duration as zero.
+func durationFromProto(pb *durpb.Duration) (time.Duration, error) {
+	if pb == nil {
+		return 0, nil
+	}
+	return ptypes.Duration(pb)
+}
+
+func deviceGroupToProto(g *DeviceGroup) *proto.DetectedDevices {
+	if g == nil {
+		return nil
+	}
+
+	devices := make([]*proto.DetectedDevice, len(g.Devices))
+	for i, d := range g.Devices {
+		devices[i] = &proto.DetectedDevice{
+			ID:                d.ID,
+			Healthy:           d.Healthy,
+			HealthDescription: d.HealthDesc,
+			PciBusId:          d.PCIBusID,
+		}
+	}
+
+	return &proto.DetectedDevices{
+		Vendor:         g.Vendor,
+		DeviceType:     g.Type,
+		DeviceName:     g.Name,
+		Devices:        devices,
+		NodeAttributes: g.Attributes,
+	}
+}
+
+func deviceGroupFromProto(pb *proto.DetectedDevices) *DeviceGroup {
+	if pb == nil {
+		return nil
+	}
+
+	devices := make([]*Device, len(pb.GetDevices()))
+	for i, d := range pb.GetDevices() {
+		devices[i] = &Device{
+			ID:         d.GetID(),
+			Healthy:    d.GetHealthy(),
+			HealthDesc: d.GetHealthDescription(),
+			PCIBusID:   d.GetPciBusId(),
+		}
+	}
+
+	return &DeviceGroup{
+		Vendor:     pb.GetVendor(),
+		Type:       pb.GetDeviceType(),
+		Name:       pb.GetDeviceName(),
+		Devices:    devices,
+		Attributes: pb.GetNodeAttributes(),
+	}
+}
+
+func containerReservationToProto(r *ContainerReservation) *proto.ContainerReservation {
+	if r == nil {
+		return nil
+	}
+
+	pb := &proto.ContainerReservation{
+		Envs:    r.Envs,
+		Mounts:  make([]*proto.Mount, len(r.Mounts)),
+		Devices: make([]*proto.DeviceSpec, len(r.Devices)),
+	}
+	for i, m := range r.Mounts {
+		pb.Mounts[i] = &proto.Mount{
+			TaskPath: m.TaskPath,
+			HostPath: m.HostPath,
+			ReadOnly: m.ReadOnly,
+		}
+	}
+	for i, d := range r.Devices {
+		pb.Devices[i] = &proto.DeviceSpec{
+			TaskPath:    d.TaskPath,
+			HostPath:    d.HostPath,
+			Permissions: d.CgroupPerms,
+		}
+	}
+	return pb
+}
+
+func containerReservationFromProto(pb *proto.ContainerReservation) *ContainerReservation {
+	if pb == nil {
+		return nil
+	}
+
+	r := &ContainerReservation{
+		Envs:    pb.GetEnvs(),
+		Mounts:  make([]*Mount, len(pb.GetMounts())),
+		Devices: make([]*DeviceSpec, len(pb.GetDevices())),
+	}
+	for i, m := range pb.GetMounts() {
+		r.Mounts[i] = &Mount{
+			TaskPath: m.GetTaskPath(),
+			HostPath: m.GetHostPath(),
+			ReadOnly: m.GetReadOnly(),
+		}
+	}
+	for i, d := range pb.GetDevices() {
+		r.Devices[i] = &DeviceSpec{
+			TaskPath:    d.GetTaskPath(),
+			HostPath:    d.GetHostPath(),
+			CgroupPerms: d.GetPermissions(),
+		}
+	}
+	return r
+}
+
+func deviceGroupsStatsToProto(groups []*DeviceGroupStats) ([]*proto.DeviceGroupStats, error) {
+	pbs := make([]*proto.DeviceGroupStats, 0, len(groups))
+	for _, g := range groups {
+		pb := &proto.DeviceGroupStats{
+			Vendor:        g.Vendor,
+			DeviceType:    g.Type,
+			DeviceName:    g.Name,
+			InstanceStats: make(map[string]*proto.DeviceStats, len(g.InstanceStats)),
+		}
+		for id, s := range g.InstanceStats {
+			ts, err := ptypes.TimestampProto(s.Timestamp)
+			if err != nil {
+				return nil, err
+			}
+
+			pb.InstanceStats[id] = &proto.DeviceStats{
+				Summary:   statValueToProto(s.Summary),
+				Stats:     statObjectToProto(s.Stats),
+				Timestamp: ts,
+			}
+		}
+		pbs = append(pbs, pb)
+	}
+	return pbs, nil
+}
+
+func deviceGroupsStatsFromProto(pbs []*proto.DeviceGroupStats) ([]*DeviceGroupStats, error) {
+	groups := make([]*DeviceGroupStats, 0, len(pbs))
+	for _, pb := range pbs {
+		g := &DeviceGroupStats{
+			Vendor:        pb.GetVendor(),
+			Type:          pb.GetDeviceType(),
+			Name:          pb.GetDeviceName(),
+			InstanceStats: make(map[string]*DeviceStats, len(pb.GetInstanceStats())),
+		}
+		for id, s := range pb.GetInstanceStats() {
+			ts, err := ptypes.Timestamp(s.GetTimestamp())
+			if err != nil {
+				return nil, err
+			}
+
+			g.InstanceStats[id] = &DeviceStats{
+				Summary:   statValueFromProto(s.GetSummary()),
+				Stats:     statObjectFromProto(s.GetStats()),
+				Timestamp: ts,
+			}
+		}
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
+
+func statObjectToProto(o *StatObject) *proto.StatObject {
+	if o == nil {
+		return nil
+	}
+
+	pb := &proto.StatObject{
+		Nested:     make(map[string]*proto.StatObject, len(o.Nested)),
+		Attributes: make(map[string]*proto.StatValue, len(o.Attributes)),
+	}
+	for k, n := range o.Nested {
+		pb.Nested[k] = statObjectToProto(n)
+	}
+	for k, v := range o.Attributes {
+		pb.Attributes[k] = statValueToProto(v)
+	}
+	return pb
+}
+
+func statObjectFromProto(pb *proto.StatObject) *StatObject {
+	if pb == nil {
+		return nil
+	}
+
+	o := &StatObject{
+		Nested:     make(map[string]*StatObject, len(pb.GetNested())),
+		Attributes: make(map[string]*StatValue, len(pb.GetAttributes())),
+	}
+	for k, n := range pb.GetNested() {
+		o.Nested[k] = statObjectFromProto(n)
+	}
+	for k, v := range pb.GetAttributes() {
+		o.Attributes[k] = statValueFromProto(v)
+	}
+	return o
+}
+
+func statValueToProto(v *StatValue) *proto.StatValue {
+	if v == nil {
+		return nil
+	}
+
+	pb := &proto.StatValue{
+		Unit: v.Unit,
+		Desc: v.Desc,
+	}
+	if v.FloatNumeratorVal != nil {
+		pb.FloatNumeratorVal = &wrappers.DoubleValue{Value: *v.FloatNumeratorVal}
+	}
+	if v.FloatDenominatorVal != nil {
+		pb.FloatDenominatorVal = &wrappers.DoubleValue{Value: *v.FloatDenominatorVal}
+	}
+	if v.IntNumeratorVal != nil {
+		pb.IntNumeratorVal = &wrappers.Int64Value{Value: *v.IntNumeratorVal}
+	}
+	if v.IntDenominatorVal != nil {
+		pb.IntDenominatorVal = &wrappers.Int64Value{Value: *v.IntDenominatorVal}
+	}
+	if v.StringVal != nil {
+		pb.StringVal = &wrappers.StringValue{Value: *v.StringVal}
+	}
+	if v.BoolVal != nil {
+		pb.BoolVal = &wrappers.BoolValue{Value: *v.BoolVal}
+	}
+	return pb
+}
+
+func statValueFromProto(pb *proto.StatValue) *StatValue {
+	if pb == nil {
+		return nil
+	}
+
+	v := &StatValue{
+		Unit: pb.GetUnit(),
+		Desc: pb.GetDesc(),
+	}
+	if w := pb.GetFloatNumeratorVal(); w != nil {
+		f := w.GetValue()
+		v.FloatNumeratorVal = &f
+	}
+	if w := pb.GetFloatDenominatorVal(); w != nil {
+		f := w.GetValue()
+		v.FloatDenominatorVal = &f
+	}
+	if w := pb.GetIntNumeratorVal(); w != nil {
+		i := w.GetValue()
+		v.IntNumeratorVal = &i
+	}
+	if w := pb.GetIntDenominatorVal(); w != nil {
+		i := w.GetValue()
+		v.IntDenominatorVal = &i
+	}
+	if w := pb.GetStringVal(); w != nil {
+		s := w.GetValue()
+		v.StringVal = &s
+	}
+	if w := pb.GetBoolVal(); w != nil {
+		b := w.GetValue()
+		v.BoolVal = &b
+	}
+	return v
+}
@@ -0,0 +1,251 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers/proto"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+// driverPluginClient implements the client side of a remote driver plugin,
+// using gRPC to communicate to the remote plugin.
+type driverPluginClient struct {
+	*base.BasePluginClient
+
+	client proto.DriverClient
+}
+
+func (d *driverPluginClient) TaskConfigSchema() (*hclspec.Spec, error) {
+	resp, err := d.client.TaskConfigSchema(context.Background(), &proto.TaskConfigSchemaRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.GetSpec(), nil
+}
+
+func (d *driverPluginClient) Capabilities() (*Capabilities, error) {
+	resp, err := d.client.Capabilities(context.Background(), &proto.CapabilitiesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return capabilitiesFromProto(resp.GetCapabilities()), nil
+}
+
+// Fingerprint streams the fingerprints of the driver. An error receiving
+// from the stream is sent as a fingerprint with Err set before the channel is
+// closed.
+func (d *driverPluginClient) Fingerprint(ctx context.Context) (<-chan *Fingerprint, error) {
+	stream, err := d.client.Fingerprint(ctx, &proto.FingerprintRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *Fingerprint, 1)
+	go d.handleFingerprint(ctx, stream, ch)
+	return ch, nil
+}
+
+func (d *driverPluginClient) handleFingerprint(ctx context.Context, stream proto.Driver_FingerprintClient, ch chan *Fingerprint) {
+	defer close(ch)
+	for {
+		pb, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return
+		}
+
+		f := &Fingerprint{}
+		if err != nil {
+			f.Err = err
+		} else {
+			f.Attributes = pb.GetAttributes()
+			f.Health = healthStateFromPB[pb.GetHealth()]
+			f.HealthDescription = pb.GetHealthDescription()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- f:
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (d *driverPluginClient) RecoverTask(h *TaskHandle) error {
+	req := &proto.RecoverTaskRequest{
+		TaskId: h.Config.ID,
+		Handle: taskHandleToProto(h),
+	}
+
+	_, err := d.client.RecoverTask(context.Background(), req)
+	return err
+}
+
+// StartTask starts the task. Errors returned by the driver are wrapped in a
+// recoverable error when the driver asked for the start to be retried.
+func (d *driverPluginClient) StartTask(c *TaskConfig) (*TaskHandle, *cstructs.DriverNetwork, error) {
+	req := &proto.StartTaskRequest{
+		Task: taskConfigToProto(c),
+	}
+
+	resp, err := d.client.StartTask(context.Background(), req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch resp.GetResult() {
+	case proto.StartTaskResponse_SUCCESS:
+	case proto.StartTaskResponse_RETRY:
+		return nil, nil, structs.NewRecoverableError(errors.New(resp.GetDriverErrorMsg()), true)
+	default:
+		return nil, nil, structs.NewRecoverableError(errors.New(resp.GetDriverErrorMsg()), false)
+	}
+
+	return taskHandleFromProto(resp.GetHandle()), networkOverrideFromProto(resp.GetNetworkOverride()), nil
+}
+
+// WaitTask waits for the task to exit in a goroutine and sends its result on
+// the returned channel. Failing to wait is reported through the Err field of
+// the result.
+func (d *driverPluginClient) WaitTask(ctx context.Context, id string) (<-chan *ExitResult, error) {
+	ch := make(chan *ExitResult, 1)
+	go d.handleWaitTask(ctx, id, ch)
+	return ch, nil
+}
+
+func (d *driverPluginClient) handleWaitTask(ctx context.Context, id string, ch chan *ExitResult) {
+	defer close(ch)
+
+	var result ExitResult
+	resp, err := d.client.WaitTask(ctx, &proto.WaitTaskRequest{TaskId: id})
+	if err != nil {
+		result.Err = err
+	} else {
+		result = *exitResultFromProto(resp.GetResult())
+		if resp.GetErr() != "" {
+			result.Err = errors.New(resp.GetErr())
+		}
+	}
+
+	ch <- &result
+}
+
+func (d *driverPluginClient) StopTask(id string, timeout time.Duration, signal string) error {
+	req := &proto.StopTaskRequest{
+		TaskId:  id,
+		Timeout: ptypes.DurationProto(timeout),
+		Signal:  signal,
+	}
+
+	_, err := d.client.StopTask(context.Background(), req)
+	return err
+}
+
+func (d *driverPluginClient) DestroyTask(id string, force bool) error {
+	req := &proto.DestroyTaskRequest{
+		TaskId: id,
+		Force:  force,
+	}
+
+	_, err := d.client.DestroyTask(context.Background(), req)
+	return err
+}
+
+func (d *driverPluginClient) InspectTask(id string) (*TaskStatus, error) {
+	resp, err := d.client.InspectTask(context.Background(), &proto.InspectTaskRequest{TaskId: id})
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := taskStatusFromProto(resp.GetTask())
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.GetDriver() != nil {
+		status.DriverAttributes = resp.GetDriver().GetAttributes()
+	}
+	status.NetworkOverride = networkOverrideFromProto(resp.GetNetworkOverride())
+
+	return status, nil
+}
+
+// TaskStats streams the resource usage of the task until the context is done
+// or the stream fails.
+func (d *driverPluginClient) TaskStats(ctx context.Context, id string, interval time.Duration) (<-chan *cstructs.TaskResourceUsage, error) {
+	req := &proto.TaskStatsRequest{
+		TaskId:             id,
+		CollectionInterval: ptypes.DurationProto(interval),
+	}
+
+	stream, err := d.client.TaskStats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *cstructs.TaskResourceUsage, 1)
+	go d.handleTaskStats(ctx, stream, ch)
+	return ch, nil
+}
+
+func (d *driverPluginClient) handleTaskStats(ctx context.Context, stream proto.Driver_TaskStatsClient, ch chan *cstructs.TaskResourceUsage) {
+	defer close(ch)
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return
+		}
+
+		usage, err := taskStatsFromProto(resp.GetStats())
+		if err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- usage:
+		}
+	}
+}
+
+func (d *driverPluginClient) SignalTask(id string, signal string) error {
+	req := &proto.SignalTaskRequest{
+		TaskId: id,
+		Signal: signal,
+	}
+
+	_, err := d.client.SignalTask(context.Background(), req)
+	return err
+}
+
+func (d *driverPluginClient) ExecTask(id string, cmd []string, timeout time.Duration) (*ExecTaskResult, error) {
+	req := &proto.ExecTaskRequest{
+		TaskId:  id,
+		Command: cmd,
+		Timeout: ptypes.DurationProto(timeout),
+	}
+
+	resp, err := d.client.ExecTask(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExecTaskResult{
+		Stdout:     resp.GetStdout(),
+		Stderr:     resp.GetStderr(),
+		ExitResult: exitResultFromProto(resp.GetResult()),
+	}, nil
+}
@@ -0,0 +1,281 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	"github.com/ugorji/go/codec"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/msgpack"
+)
+
+const (
+	// ApiVersion010 is the initial API version for the driver plugins
+	ApiVersion010 = "v0.1.0"
+)
+
+// DriverPlugin is the interface which drivers implement. It is also
+// implemented by a plugin client which proxies the calls to driver plugins
+// implemented out of process.
+type DriverPlugin interface {
+	base.BasePlugin
+
+	// TaskConfigSchema returns the schema for parsing the driver
+	// configuration of a task. A nil spec means the driver accepts any task
+	// configuration.
+	TaskConfigSchema() (*hclspec.Spec, error)
+
+	// Capabilities returns the optional features the driver implements.
+	Capabilities() (*Capabilities, error)
+
+	// Fingerprint returns a channel on which the driver sends its current
+	// fingerprint immediately and a new one whenever its state changes. The
+	// channel is closed when the context is done.
+	Fingerprint(context.Context) (<-chan *Fingerprint, error)
+
+	// RecoverTask reattaches the driver to a task it started before, using
+	// the handle returned by StartTask.
+	RecoverTask(*TaskHandle) error
+
+	// StartTask starts the task. The returned handle must be stored by the
+	// caller to recover the task. The network may be nil if the driver
+	// doesn't configure the network of the task.
+	StartTask(*TaskConfig) (*TaskHandle, *cstructs.DriverNetwork, error)
+
+	// WaitTask returns a channel on which the result of the task is sent
+	// once it exited.
+	WaitTask(ctx context.Context, taskID string) (<-chan *ExitResult, error)
+
+	// StopTask sends the signal to the task and forcefully kills it if it
+	// didn't exit within the timeout.
+	StopTask(taskID string, timeout time.Duration, signal string) error
+
+	// DestroyTask removes all the state of the task. Running tasks are only
+	// destroyed if force is set.
+	DestroyTask(taskID string, force bool) error
+
+	// InspectTask returns the status of the task.
+	InspectTask(taskID string) (*TaskStatus, error)
+
+	// TaskStats returns a channel on which the resource usage of the task is
+	// sent every interval. The channel is closed when the context is done.
+	TaskStats(ctx context.Context, taskID string, interval time.Duration) (<-chan *cstructs.TaskResourceUsage, error)
+
+	// SignalTask sends the named signal, such as SIGHUP, to the task.
+	SignalTask(taskID string, signal string) error
+
+	// ExecTask runs the command in the context of the task and returns its
+	// output once it exited.
+	ExecTask(taskID string, cmd []string, timeout time.Duration) (*ExecTaskResult, error)
+}
+
+// HealthState is the health of a driver as reported by its fingerprint.
+type HealthState string
+
+var (
+	// HealthStateUndetected means the dependencies of the driver are not met
+	HealthStateUndetected = HealthState("undetected")
+
+	// HealthStateUnhealthy means the driver is detected but can't run tasks
+	HealthStateUnhealthy = HealthState("unhealthy")
+
+	// HealthStateHealthy means the driver is able to run tasks
+	HealthStateHealthy = HealthState("healthy")
+)
+
+// Fingerprint describes the state of a driver on the node.
+type Fingerprint struct {
+	// Attributes are node attributes set by the driver
+	Attributes map[string]string
+
+	// Health is the health of the driver
+	Health HealthState
+
+	// HealthDescription is a human readable description of the health
+	HealthDescription string
+
+	// Err is set by the plugin client if fingerprinting failed
+	Err error
+}
+
+// Detected returns whether the driver was detected on the node.
+func (f *Fingerprint) Detected() bool {
+	return f.Health != HealthStateUndetected
+}
+
+// FSIsolation is the filesystem isolation a driver provides.
+type FSIsolation string
+
+var (
+	// FSIsolationNone means tasks share the filesystem of the host
+	FSIsolationNone = FSIsolation("none")
+
+	// FSIsolationChroot means tasks run in a chroot of their task directory
+	FSIsolationChroot = FSIsolation("chroot")
+
+	// FSIsolationImage means tasks run in the filesystem of an image
+	FSIsolationImage = FSIsolation("image")
+)
+
+// Capabilities are the optional features of a driver.
+type Capabilities struct {
+	// SendSignals marks the driver as being able to send signals
+	SendSignals bool
+
+	// Exec marks the driver as being able to execute arbitrary commands
+	// such as health checks.
+	Exec bool
+
+	// FSIsolation is the filesystem isolation provided by the driver
+	FSIsolation FSIsolation
+}
+
+// TaskConfig is the configuration of a task started by a driver.
+type TaskConfig struct {
+	// ID is the unique ID of the task
+	ID string
+
+	// JobName, TaskGroupName and Name identify the task in its job
+	JobName       string
+	TaskGroupName string
+	Name          string
+
+	// AllocID is the ID of the allocation of the task
+	AllocID string
+
+	// Env is the environment of the task
+	Env map[string]string
+
+	// Resources are the resources allocated to the task
+	Resources *structs.Resources
+
+	// User is the user the task should run as
+	User string
+
+	// AllocDir is the directory of the allocation on the host
+	AllocDir string
+
+	// LogConfig is how the driver should rotate the logs of the task
+	LogConfig *structs.LogConfig
+
+	rawDriverConfig []byte
+}
+
+// Copy returns a copy of the task config.
+func (tc *TaskConfig) Copy() *TaskConfig {
+	if tc == nil {
+		return nil
+	}
+	c := new(TaskConfig)
+	*c = *tc
+	c.Env = helper.CopyMapStringString(c.Env)
+	c.Resources = tc.Resources.Copy()
+	if tc.LogConfig != nil {
+		lc := *tc.LogConfig
+		c.LogConfig = &lc
+	}
+	if tc.rawDriverConfig != nil {
+		c.rawDriverConfig = make([]byte, len(tc.rawDriverConfig))
+		copy(c.rawDriverConfig, tc.rawDriverConfig)
+	}
+	return c
+}
+
+// TaskDir returns the directories of the task on the host.
+func (tc *TaskConfig) TaskDir() *allocdir.TaskDir {
+	taskDir := filepath.Join(tc.AllocDir, tc.Name)
+	return &allocdir.TaskDir{
+		Dir:            taskDir,
+		SharedAllocDir: filepath.Join(tc.AllocDir, allocdir.SharedAllocName),
+		SharedTaskDir:  filepath.Join(taskDir, allocdir.SharedAllocName),
+		LocalDir:       filepath.Join(taskDir, allocdir.TaskLocal),
+		LogDir:         filepath.Join(tc.AllocDir, allocdir.SharedAllocName, allocdir.LogDirName),
+		SecretsDir:     filepath.Join(taskDir, allocdir.TaskSecrets),
+	}
+}
+
+// DecodeDriverConfig decodes the driver configuration of the task into t.
+// The configuration was validated against the task config schema of the
+// driver.
+func (tc *TaskConfig) DecodeDriverConfig(t interface{}) error {
+	return structs.Decode(tc.rawDriverConfig, t)
+}
+
+// EncodeDriverConfig sets the driver configuration of the task from a value
+// decoded using the task config schema of the driver.
+func (tc *TaskConfig) EncodeDriverConfig(val cty.Value) error {
+	data, err := msgpack.Marshal(val, val.Type())
+	if err != nil {
+		return err
+	}
+
+	tc.rawDriverConfig = data
+	return nil
+}
+
+// EncodeConcreteDriverConfig sets the driver configuration of the task from
+// a Go value, such as the configuration map of drivers without a schema.
+func (tc *TaskConfig) EncodeConcreteDriverConfig(t interface{}) error {
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, structs.MsgpackHandle).Encode(t); err != nil {
+		return err
+	}
+
+	tc.rawDriverConfig = buf.Bytes()
+	return nil
+}
+
+// TaskState is the state of the execution of a task.
+type TaskState string
+
+const (
+	TaskStateUnknown TaskState = "unknown"
+	TaskStateRunning TaskState = "running"
+	TaskStateExited  TaskState = "exited"
+)
+
+// TaskStatus is the status of a task returned by InspectTask.
+type TaskStatus struct {
+	ID               string
+	Name             string
+	State            TaskState
+	StartedAt        time.Time
+	CompletedAt      time.Time
+	ExitResult       *ExitResult
+	DriverAttributes map[string]string
+	NetworkOverride  *cstructs.DriverNetwork
+}
+
+// ExitResult is the result of a task that exited.
+type ExitResult struct {
+	ExitCode  int
+	Signal    int
+	OOMKilled bool
+	Err       error
+}
+
+// Successful returns whether the task exited successfully.
+func (r *ExitResult) Successful() bool {
+	return r.ExitCode == 0 && r.Signal == 0 && r.Err == nil
+}
+
+func (r *ExitResult) String() string {
+	return fmt.Sprintf("Exit Code: %d, Signal: %d, OOM Killed: %t, Error: %v",
+		r.ExitCode, r.Signal, r.OOMKilled, r.Err)
+}
+
+// ExecTaskResult is the result of a command executed in a task.
+type ExecTaskResult struct {
+	Stdout     []byte
+	Stderr     []byte
+	ExitResult *ExitResult
+}
@@ -0,0 +1,54 @@
+package drivers
+
+import (
+	"context"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+// MockDriver is used for testing.
+// Each function can be set as a closure to make assertions about how data
+// is passed through the driver plugin layer.
+type MockDriver struct {
+	base.MockPlugin
+	TaskConfigSchemaF func() (*hclspec.Spec, error)
+	CapabilitiesF     func() (*Capabilities, error)
+	FingerprintF      func(context.Context) (<-chan *Fingerprint, error)
+	RecoverTaskF      func(*TaskHandle) error
+	StartTaskF        func(*TaskConfig) (*TaskHandle, *cstructs.DriverNetwork, error)
+	WaitTaskF         func(context.Context, string) (<-chan *ExitResult, error)
+	StopTaskF         func(string, time.Duration, string) error
+	DestroyTaskF      func(string, bool) error
+	InspectTaskF      func(string) (*TaskStatus, error)
+	TaskStatsF        func(context.Context, string, time.Duration) (<-chan *cstructs.TaskResourceUsage, error)
+	SignalTaskF       func(string, string) error
+	ExecTaskF         func(string, []string, time.Duration) (*ExecTaskResult, error)
+}
+
+func (d *MockDriver) TaskConfigSchema() (*hclspec.Spec, error) { return d.TaskConfigSchemaF() }
+func (d *MockDriver) Capabilities() (*Capabilities, error)     { return d.CapabilitiesF() }
+func (d *MockDriver) Fingerprint(ctx context.Context) (<-chan *Fingerprint, error) {
+	return d.FingerprintF(ctx)
+}
+func (d *MockDriver) RecoverTask(h *TaskHandle) error { return d.RecoverTaskF(h) }
+func (d *MockDriver) StartTask(c *TaskConfig) (*TaskHandle, *cstructs.DriverNetwork, error) {
+	return d.StartTaskF(c)
+}
+func (d *MockDriver) WaitTask(ctx context.Context, id string) (<-chan *ExitResult, error) {
+	return d.WaitTaskF(ctx, id)
+}
+func (d *MockDriver) StopTask(id string, timeout time.Duration, signal string) error {
+	return d.StopTaskF(id, timeout, signal)
+}
+func (d *MockDriver) DestroyTask(id string, force bool) error    { return d.DestroyTaskF(id, force) }
+func (d *MockDriver) InspectTask(id string) (*TaskStatus, error) { return d.InspectTaskF(id) }
+func (d *MockDriver) TaskStats(ctx context.Context, id string, interval time.Duration) (<-chan *cstructs.TaskResourceUsage, error) {
+	return d.TaskStatsF(ctx, id, interval)
+}
+func (d *MockDriver) SignalTask(id string, signal string) error { return d.SignalTaskF(id, signal) }
+func (d *MockDriver) ExecTask(id string, cmd []string, timeout time.Duration) (*ExecTaskResult, error) {
+	return d.ExecTaskF(id, cmd, timeout)
+}
@@ -0,0 +1,52 @@
+package drivers
+
+import (
+	"context"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/plugins/base"
+	baseproto "github.com/hashicorp/nomad/plugins/base/proto"
+	"github.com/hashicorp/nomad/plugins/drivers/proto"
+	"google.golang.org/grpc"
+)
+
+// PluginDriver wraps a DriverPlugin and implements go-plugins GRPCPlugin
+// interface to expose the interface over gRPC.
+type PluginDriver struct {
+	plugin.NetRPCUnsupportedPlugin
+	impl DriverPlugin
+}
+
+// NewDriverPlugin returns the go-plugin plugin exposing the driver.
+func NewDriverPlugin(d DriverPlugin) *PluginDriver {
+	return &PluginDriver{impl: d}
+}
+
+func (p *PluginDriver) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterDriverServer(s, &driverPluginServer{
+		impl:   p.impl,
+		broker: broker,
+	})
+	return nil
+}
+
+func (p *PluginDriver) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &driverPluginClient{
+		BasePluginClient: &base.BasePluginClient{Client: baseproto.NewBasePluginClient(c)},
+		client:           proto.NewDriverClient(c),
+	}, nil
+}
+
+// Serve is used to serve a driver plugin. It is called from the main
+// function of the plugin binary and blocks until the client kills the
+// plugin.
+func Serve(d DriverPlugin) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: base.Handshake,
+		Plugins: map[string]plugin.Plugin{
+			base.PluginTypeBase:   &base.PluginBase{Impl: d},
+			base.PluginTypeDriver: &PluginDriver{impl: d},
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+	})
+}
@@ -0,0 +1,248 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/stretchr/testify/require"
+)
+
+// dispenseMockDriver returns a gRPC client of the mock driver and a function
+// to stop the connection.
+func dispenseMockDriver(t *testing.T, mock *MockDriver) (DriverPlugin, func()) {
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		base.PluginTypeBase:   &base.PluginBase{Impl: mock},
+		base.PluginTypeDriver: &PluginDriver{impl: mock},
+	})
+
+	raw, err := client.Dispense(base.PluginTypeDriver)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	impl, ok := raw.(DriverPlugin)
+	if !ok {
+		t.Fatalf("bad: %#v", raw)
+	}
+
+	return impl, func() {
+		client.Close()
+		server.Stop()
+	}
+}
+
+func TestDriverPlugin_StartTask_GRPC(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	type driverConfig struct {
+		Command string   `codec:"command"`
+		Args    []string `codec:"args"`
+	}
+
+	var received *TaskConfig
+	mock := &MockDriver{
+		StartTaskF: func(c *TaskConfig) (*TaskHandle, *cstructs.DriverNetwork, error) {
+			received = c
+			if c.Name == "retry" {
+				return nil, nil, structs.NewRecoverableError(errors.New("try again"), true)
+			}
+			if c.Name == "fail" {
+				return nil, nil, errors.New("bad config")
+			}
+
+			h := NewTaskHandle(1)
+			h.Config = c
+			h.State = TaskStateRunning
+			if err := h.SetDriverState(map[string]string{"pid": "42"}); err != nil {
+				return nil, nil, err
+			}
+			return h, &cstructs.DriverNetwork{IP: "10.0.0.1", PortMap: map[string]int{"http": 80}}, nil
+		},
+	}
+
+	impl, stop := dispenseMockDriver(t, mock)
+	defer stop()
+
+	cfg := &TaskConfig{
+		ID:       "alloc/web/1234",
+		Name:     "web",
+		AllocID:  "alloc",
+		Env:      map[string]string{"FOO": "bar"},
+		AllocDir: "/tmp/alloc",
+		Resources: &structs.Resources{
+			CPU:      250,
+			MemoryMB: 256,
+			Networks: []*structs.NetworkResource{
+				{
+					IP:           "10.0.0.1",
+					MBits:        10,
+					DynamicPorts: []structs.Port{{Label: "http", Value: 20000}},
+				},
+			},
+		},
+		LogConfig: &structs.LogConfig{MaxFiles: 2, MaxFileSizeMB: 5},
+	}
+	require.NoError(cfg.EncodeConcreteDriverConfig(&driverConfig{
+		Command: "/bin/sleep",
+		Args:    []string{"10"},
+	}))
+
+	handle, net, err := impl.StartTask(cfg)
+	require.NoError(err)
+	require.Equal(cfg.Env, received.Env)
+	require.Equal(cfg.Resources, received.Resources)
+	require.Equal(cfg.LogConfig, received.LogConfig)
+
+	var decoded driverConfig
+	require.NoError(received.DecodeDriverConfig(&decoded))
+	require.Equal("/bin/sleep", decoded.Command)
+	require.Equal([]string{"10"}, decoded.Args)
+
+	require.Equal(1, handle.Version)
+	require.Equal(TaskStateRunning, handle.State)
+	require.Equal(cfg.ID, handle.Config.ID)
+	var state map[string]string
+	require.NoError(handle.GetDriverState(&state))
+	require.Equal("42", state["pid"])
+
+	require.Equal("10.0.0.1", net.IP)
+	require.Equal(80, net.PortMap["http"])
+
+	// Errors keep whether they are recoverable
+	cfg.Name = "retry"
+	_, _, err = impl.StartTask(cfg)
+	require.Error(err)
+	require.True(structs.IsRecoverable(err))
+
+	cfg.Name = "fail"
+	_, _, err = impl.StartTask(cfg)
+	require.Error(err)
+	require.False(structs.IsRecoverable(err))
+	require.Contains(err.Error(), "bad config")
+}
+
+func TestDriverPlugin_WaitTask_GRPC(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	mock := &MockDriver{
+		WaitTaskF: func(ctx context.Context, id string) (<-chan *ExitResult, error) {
+			ch := make(chan *ExitResult, 1)
+			ch <- &ExitResult{ExitCode: 2, Signal: 9, OOMKilled: true, Err: errors.New("killed")}
+			return ch, nil
+		},
+	}
+
+	impl, stop := dispenseMockDriver(t, mock)
+	defer stop()
+
+	ch, err := impl.WaitTask(context.Background(), "foo")
+	require.NoError(err)
+
+	select {
+	case result := <-ch:
+		require.Equal(2, result.ExitCode)
+		require.Equal(9, result.Signal)
+		require.True(result.OOMKilled)
+		require.EqualError(result.Err, "killed")
+		require.False(result.Successful())
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for the task")
+	}
+}
+
+func TestDriverPlugin_Fingerprint_GRPC(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	mock := &MockDriver{
+		FingerprintF: func(ctx context.Context) (<-chan *Fingerprint, error) {
+			ch := make(chan *Fingerprint)
+			go func() {
+				defer close(ch)
+				for _, health := range []HealthState{HealthStateHealthy, HealthStateUnhealthy} {
+					select {
+					case <-ctx.Done():
+						return
+					case ch <- &Fingerprint{
+						Attributes:        map[string]string{"driver.mock": "1"},
+						Health:            health,
+						HealthDescription: string(health),
+					}:
+					}
+				}
+			}()
+			return ch, nil
+		},
+	}
+
+	impl, stop := dispenseMockDriver(t, mock)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := impl.Fingerprint(ctx)
+	require.NoError(err)
+
+	var received []*Fingerprint
+	for f := range ch {
+		require.NoError(f.Err)
+		received = append(received, f)
+	}
+
+	require.Len(received, 2)
+	require.Equal(HealthStateHealthy, received[0].Health)
+	require.True(received[0].Detected())
+	require.Equal("1", received[0].Attributes["driver.mock"])
+	require.Equal(HealthStateUnhealthy, received[1].Health)
+}
+
+func TestDriverPlugin_TaskStats_GRPC(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	now := time.Now().UnixNano()
+	mock := &MockDriver{
+		TaskStatsF: func(ctx context.Context, id string, interval time.Duration) (<-chan *cstructs.TaskResourceUsage, error) {
+			require.Equal(time.Second, interval)
+			ch := make(chan *cstructs.TaskResourceUsage, 1)
+			ch <- &cstructs.TaskResourceUsage{
+				Timestamp: now,
+				ResourceUsage: &cstructs.ResourceUsage{
+					CpuStats: &cstructs.CpuStats{
+						Percent:  12.5,
+						Measured: []string{"Percent"},
+					},
+					MemoryStats: &cstructs.MemoryStats{
+						RSS:      1024,
+						Measured: []string{"RSS"},
+					},
+				},
+			}
+			close(ch)
+			return ch, nil
+		},
+	}
+
+	impl, stop := dispenseMockDriver(t, mock)
+	defer stop()
+
+	ch, err := impl.TaskStats(context.Background(), "foo", time.Second)
+	require.NoError(err)
+
+	usage, ok := <-ch
+	require.True(ok)
+	require.Equal(now, usage.Timestamp)
+	require.Equal(12.5, usage.ResourceUsage.CpuStats.Percent)
+	require.Equal([]string{"Percent"}, usage.ResourceUsage.CpuStats.Measured)
+	require.Equal(uint64(1024), usage.ResourceUsage.MemoryStats.RSS)
+	require.Equal([]string{"RSS"}, usage.ResourceUsage.MemoryStats.Measured)
+}
@@ -0,0 +1,231 @@
+package drivers
+
+import (
+	"fmt"
+	"io"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers/proto"
+	"golang.org/x/net/context"
+)
+
+// driverPluginServer wraps a driver plugin and exposes it via gRPC.
+type driverPluginServer struct {
+	broker *plugin.GRPCBroker
+	impl   DriverPlugin
+}
+
+func (b *driverPluginServer) TaskConfigSchema(ctx context.Context, req *proto.TaskConfigSchemaRequest) (*proto.TaskConfigSchemaResponse, error) {
+	spec, err := b.impl.TaskConfigSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.TaskConfigSchemaResponse{
+		Spec: spec,
+	}, nil
+}
+
+func (b *driverPluginServer) Capabilities(ctx context.Context, req *proto.CapabilitiesRequest) (*proto.CapabilitiesResponse, error) {
+	caps, err := b.impl.Capabilities()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.CapabilitiesResponse{
+		Capabilities: capabilitiesToProto(caps),
+	}, nil
+}
+
+func (b *driverPluginServer) Fingerprint(req *proto.FingerprintRequest, srv proto.Driver_FingerprintServer) error {
+	ctx := srv.Context()
+	ch, err := b.impl.Fingerprint(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case f, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if f.Err != nil {
+				return f.Err
+			}
+
+			resp := &proto.FingerprintResponse{
+				Attributes:        f.Attributes,
+				Health:            healthStateToPB[f.Health],
+				HealthDescription: f.HealthDescription,
+			}
+
+			if err := srv.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (b *driverPluginServer) RecoverTask(ctx context.Context, req *proto.RecoverTaskRequest) (*proto.RecoverTaskResponse, error) {
+	if err := b.impl.RecoverTask(taskHandleFromProto(req.Handle)); err != nil {
+		return nil, err
+	}
+
+	return &proto.RecoverTaskResponse{}, nil
+}
+
+func (b *driverPluginServer) StartTask(ctx context.Context, req *proto.StartTaskRequest) (*proto.StartTaskResponse, error) {
+	handle, net, err := b.impl.StartTask(taskConfigFromProto(req.Task))
+	if err != nil {
+		// Errors starting the task are returned as part of the response, so
+		// the client can tell recoverable errors apart.
+		result := proto.StartTaskResponse_FATAL
+		if structs.IsRecoverable(err) {
+			result = proto.StartTaskResponse_RETRY
+		}
+
+		return &proto.StartTaskResponse{
+			Result:         result,
+			DriverErrorMsg: err.Error(),
+		}, nil
+	}
+
+	return &proto.StartTaskResponse{
+		Result:          proto.StartTaskResponse_SUCCESS,
+		Handle:          taskHandleToProto(handle),
+		NetworkOverride: networkOverrideToProto(net),
+	}, nil
+}
+
+func (b *driverPluginServer) WaitTask(ctx context.Context, req *proto.WaitTaskRequest) (*proto.WaitTaskResponse, error) {
+	ch, err := b.impl.WaitTask(ctx, req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *ExitResult
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result = <-ch:
+	}
+
+	if result == nil {
+		return nil, fmt.Errorf("no result received for task %q", req.TaskId)
+	}
+
+	resp := &proto.WaitTaskResponse{
+		Result: exitResultToProto(result),
+	}
+	if result.Err != nil {
+		resp.Err = result.Err.Error()
+	}
+
+	return resp, nil
+}
+
+func (b *driverPluginServer) StopTask(ctx context.Context, req *proto.StopTaskRequest) (*proto.StopTaskResponse, error) {
+	timeout, err := durationFromProto(req.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.impl.StopTask(req.TaskId, timeout, req.Signal); err != nil {
+		return nil, err
+	}
+
+	return &proto.StopTaskResponse{}, nil
+}
+
+func (b *driverPluginServer) DestroyTask(ctx context.Context, req *proto.DestroyTaskRequest) (*proto.DestroyTaskResponse, error) {
+	if err := b.impl.DestroyTask(req.TaskId, req.Force); err != nil {
+		return nil, err
+	}
+
+	return &proto.DestroyTaskResponse{}, nil
+}
+
+func (b *driverPluginServer) InspectTask(ctx context.Context, req *proto.InspectTaskRequest) (*proto.InspectTaskResponse, error) {
+	status, err := b.impl.InspectTask(req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+
+	pbStatus, err := taskStatusToProto(status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.InspectTaskResponse{
+		Task: pbStatus,
+		Driver: &proto.TaskDriverStatus{
+			Attributes: status.DriverAttributes,
+		},
+		NetworkOverride: networkOverrideToProto(status.NetworkOverride),
+	}, nil
+}
+
+func (b *driverPluginServer) TaskStats(req *proto.TaskStatsRequest, srv proto.Driver_TaskStatsServer) error {
+	interval, err := durationFromProto(req.CollectionInterval)
+	if err != nil {
+		return err
+	}
+
+	ctx := srv.Context()
+	ch, err := b.impl.TaskStats(ctx, req.TaskId, interval)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case usage, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			stats, err := taskStatsToProto(req.TaskId, usage)
+			if err != nil {
+				return fmt.Errorf("failed to encode task stats: %v", err)
+			}
+
+			if err := srv.Send(&proto.TaskStatsResponse{Stats: stats}); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (b *driverPluginServer) SignalTask(ctx context.Context, req *proto.SignalTaskRequest) (*proto.SignalTaskResponse, error) {
+	if err := b.impl.SignalTask(req.TaskId, req.Signal); err != nil {
+		return nil, err
+	}
+
+	return &proto.SignalTaskResponse{}, nil
+}
+
+func (b *driverPluginServer) ExecTask(ctx context.Context, req *proto.ExecTaskRequest) (*proto.ExecTaskResponse, error) {
+	timeout, err := durationFromProto(req.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := b.impl.ExecTask(req.TaskId, req.Command, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ExecTaskResponse{
+		Stdout: result.Stdout,
+		Stderr: result.Stderr,
+		Result: exitResultToProto(result.ExitResult),
+	}, nil
+}
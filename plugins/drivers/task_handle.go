@@ -0,0 +1,82 @@
+package drivers
+
+import (
+	"bytes"
+
+	pb "github.com/golang/protobuf/proto"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers/proto"
+	"github.com/ugorji/go/codec"
+)
+
+// TaskHandle is the state shared between a driver and the client. It is
+// returned by StartTask, stored by the client and passed back to the driver
+// to recover the task.
+type TaskHandle struct {
+	// Version is used by the driver to version the DriverState schema.
+	// Version 0 is reserved by Nomad and should not be used.
+	Version int
+
+	// Config is the configuration of the task
+	Config *TaskConfig
+
+	// State is the state of the execution of the task
+	State TaskState
+
+	// DriverState is the encoded state of the driver for the task
+	DriverState []byte
+}
+
+// NewTaskHandle returns a handle of the task with the given driver state
+// version.
+func NewTaskHandle(version int) *TaskHandle {
+	return &TaskHandle{Version: version}
+}
+
+// SetDriverState encodes the driver state of the task into the handle.
+func (h *TaskHandle) SetDriverState(v interface{}) error {
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, structs.MsgpackHandle).Encode(v); err != nil {
+		return err
+	}
+
+	h.DriverState = buf.Bytes()
+	return nil
+}
+
+// GetDriverState decodes the driver state of the task from the handle.
+func (h *TaskHandle) GetDriverState(v interface{}) error {
+	return structs.Decode(h.DriverState, v)
+}
+
+// Copy returns a copy of the handle.
+func (h *TaskHandle) Copy() *TaskHandle {
+	if h == nil {
+		return nil
+	}
+
+	handle := new(TaskHandle)
+	*handle = *h
+	handle.Config = h.Config.Copy()
+	if h.DriverState != nil {
+		handle.DriverState = make([]byte, len(h.DriverState))
+		copy(handle.DriverState, h.DriverState)
+	}
+	return handle
+}
+
+// EncodeTaskHandle encodes the handle, including the driver configuration of
+// the task, so it can be persisted by the client.
+func EncodeTaskHandle(h *TaskHandle) ([]byte, error) {
+	return pb.Marshal(taskHandleToProto(h))
+}
+
+// DecodeTaskHandle decodes a handle encoded with EncodeTaskHandle.
+func DecodeTaskHandle(data []byte) (*TaskHandle, error) {
+	var handle proto.TaskHandle
+	if err := pb.Unmarshal(data, &handle); err != nil {
+		return nil, err
+	}
+
+	return taskHandleFromProto(&handle), nil
+}
@@ -0,0 +1,500 @@
+package drivers
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	durpb "github.com/golang/protobuf/ptypes/duration"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers/proto"
+)
+
+var healthStateToPB = map[HealthState]proto.FingerprintResponse_HealthState{
+	HealthStateUndetected: proto.FingerprintResponse_UNDETECTED,
+	HealthStateUnhealthy:  proto.FingerprintResponse_UNHEALTHY,
+	HealthStateHealthy:    proto.FingerprintResponse_HEALTHY,
+}
+
+var healthStateFromPB = map[proto.FingerprintResponse_HealthState]HealthState{
+	proto.FingerprintResponse_UNDETECTED: HealthStateUndetected,
+	proto.FingerprintResponse_UNHEALTHY:  HealthStateUnhealthy,
+	proto.FingerprintResponse_HEALTHY:    HealthStateHealthy,
+}
+
+var fsIsolationToPB = map[FSIsolation]proto.DriverCapabilities_FSIsolation{
+	FSIsolationNone:   proto.DriverCapabilities_NONE,
+	FSIsolationChroot: proto.DriverCapabilities_CHROOT,
+	FSIsolationImage:  proto.DriverCapabilities_IMAGE,
+}
+
+var fsIsolationFromPB = map[proto.DriverCapabilities_FSIsolation]FSIsolation{
+	proto.DriverCapabilities_NONE:   FSIsolationNone,
+	proto.DriverCapabilities_CHROOT: FSIsolationChroot,
+	proto.DriverCapabilities_IMAGE:  FSIsolationImage,
+}
+
+var taskStateToPB = map[TaskState]proto.TaskState{
+	TaskStateUnknown: proto.TaskState_UNKNOWN,
+	TaskStateRunning: proto.TaskState_RUNNING,
+	TaskStateExited:  proto.TaskState_EXITED,
+}
+
+var taskStateFromPB = map[proto.TaskState]TaskState{
+	proto.TaskState_UNKNOWN: TaskStateUnknown,
+	proto.TaskState_RUNNING: TaskStateRunning,
+	proto.TaskState_EXITED:  TaskStateExited,
+}
+
+// The measured fields of the resource usage are exchanged as enums and
+// stored using the names used by the executor.
+var cpuFieldsToPB = map[string]proto.CPUUsage_Fields{
+	"System Mode":       proto.CPUUsage_SYSTEM_MODE,
+	"User Mode":         proto.CPUUsage_USER_MODE,
+	"Total Ticks":       proto.CPUUsage_TOTAL_TICKS,
+	"Throttled Periods": proto.CPUUsage_THROTTLED_PERIODS,
+	"Throttled Time":    proto.CPUUsage_THROTTLED_TIME,
+	"Percent":           proto.CPUUsage_PERCENT,
+}
+
+var memoryFieldsToPB = map[string]proto.MemoryUsage_Fields{
+	"RSS":              proto.MemoryUsage_RSS,
+	"Cache":            proto.MemoryUsage_CACHE,
+	"Max Usage":        proto.MemoryUsage_MAX_USAGE,
+	"Kernel Usage":     proto.MemoryUsage_KERNEL_USAGE,
+	"Kernel Max Usage": proto.MemoryUsage_KERNEL_MAX_USAGE,
+	"Swap":             proto.MemoryUsage_SWAP,
+}
+
+// durationFromProto converts a duration, treating an unset duration as zero.
+func durationFromProto(pb *durpb.Duration) (time.Duration, error) {
+	if pb == nil {
+		return 0, nil
+	}
+	return ptypes.Duration(pb)
+}
+
+func capabilitiesToProto(c *Capabilities) *proto.DriverCapabilities {
+	if c == nil {
+		return nil
+	}
+
+	return &proto.DriverCapabilities{
+		SendSignals: c.SendSignals,
+		Exec:        c.Exec,
+		FsIsolation: fsIsolationToPB[c.FSIsolation],
+	}
+}
+
+func capabilitiesFromProto(pb *proto.DriverCapabilities) *Capabilities {
+	if pb == nil {
+		return &Capabilities{FSIsolation: FSIsolationNone}
+	}
+
+	return &Capabilities{
+		SendSignals: pb.SendSignals,
+		Exec:        pb.Exec,
+		FSIsolation: fsIsolationFromPB[pb.FsIsolation],
+	}
+}
+
+func taskConfigFromProto(pb *proto.TaskConfig) *TaskConfig {
+	if pb == nil {
+		return &TaskConfig{}
+	}
+
+	return &TaskConfig{
+		ID:              pb.Id,
+		JobName:         pb.JobName,
+		TaskGroupName:   pb.TaskGroupName,
+		Name:            pb.Name,
+		AllocID:         pb.AllocId,
+		Env:             pb.Env,
+		Resources:       resourcesFromProto(pb.Resources),
+		User:            pb.User,
+		AllocDir:        pb.AllocDir,
+		LogConfig:       logConfigFromProto(pb.LogConfig),
+		rawDriverConfig: pb.MsgpackDriverConfig,
+	}
+}
+
+func taskConfigToProto(cfg *TaskConfig) *proto.TaskConfig {
+	if cfg == nil {
+		return &proto.TaskConfig{}
+	}
+
+	return &proto.TaskConfig{
+		Id:                  cfg.ID,
+		JobName:             cfg.JobName,
+		TaskGroupName:       cfg.TaskGroupName,
+		Name:                cfg.Name,
+		AllocId:             cfg.AllocID,
+		Env:                 cfg.Env,
+		Resources:           resourcesToProto(cfg.Resources),
+		User:                cfg.User,
+		AllocDir:            cfg.AllocDir,
+		LogConfig:           logConfigToProto(cfg.LogConfig),
+		MsgpackDriverConfig: cfg.rawDriverConfig,
+	}
+}
+
+func resourcesFromProto(pb *proto.Resources) *structs.Resources {
+	if pb == nil || pb.RawResources == nil {
+		return nil
+	}
+
+	raw := pb.RawResources
+	r := &structs.Resources{
+		CPU:      int(raw.Cpu),
+		MemoryMB: int(raw.Memory),
+		DiskMB:   int(raw.Disk),
+		IOPS:     int(raw.Iops),
+	}
+
+	for _, network := range raw.Networks {
+		n := &structs.NetworkResource{
+			Device: network.Device,
+			CIDR:   network.Cidr,
+			IP:     network.Ip,
+			MBits:  int(network.Mbits),
+		}
+		for _, port := range network.ReservedPorts {
+			n.ReservedPorts = append(n.ReservedPorts, structs.Port{
+				Label: port.Label,
+				Value: int(port.Value),
+			})
+		}
+		for _, port := range network.DynamicPorts {
+			n.DynamicPorts = append(n.DynamicPorts, structs.Port{
+				Label: port.Label,
+				Value: int(port.Value),
+			})
+		}
+		r.Networks = append(r.Networks, n)
+	}
+
+	return r
+}
+
+func resourcesToProto(r *structs.Resources) *proto.Resources {
+	if r == nil {
+		return nil
+	}
+
+	raw := &proto.RawResources{
+		Cpu:    int64(r.CPU),
+		Memory: int64(r.MemoryMB),
+		Disk:   int64(r.DiskMB),
+		Iops:   int64(r.IOPS),
+	}
+
+	for _, network := range r.Networks {
+		n := &proto.NetworkResource{
+			Device: network.Device,
+			Cidr:   network.CIDR,
+			Ip:     network.IP,
+			Mbits:  int32(network.MBits),
+		}
+		for _, port := range network.ReservedPorts {
+			n.ReservedPorts = append(n.ReservedPorts, &proto.NetworkPort{
+				Label: port.Label,
+				Value: int32(port.Value),
+			})
+		}
+		for _, port := range network.DynamicPorts {
+			n.DynamicPorts = append(n.DynamicPorts, &proto.NetworkPort{
+				Label: port.Label,
+				Value: int32(port.Value),
+			})
+		}
+		raw.Networks = append(raw.Networks, n)
+	}
+
+	return &proto.Resources{RawResources: raw}
+}
+
+func logConfigFromProto(pb *proto.LogConfig) *structs.LogConfig {
+	if pb == nil {
+		return nil
+	}
+
+	return &structs.LogConfig{
+		MaxFiles:      int(pb.MaxFiles),
+		MaxFileSizeMB: int(pb.MaxFileSizeMb),
+	}
+}
+
+func logConfigToProto(c *structs.LogConfig) *proto.LogConfig {
+	if c == nil {
+		return nil
+	}
+
+	return &proto.LogConfig{
+		MaxFiles:      int32(c.MaxFiles),
+		MaxFileSizeMb: int32(c.MaxFileSizeMB),
+	}
+}
+
+func taskHandleFromProto(pb *proto.TaskHandle) *TaskHandle {
+	if pb == nil {
+		return &TaskHandle{}
+	}
+
+	return &TaskHandle{
+		Version:     int(pb.Version),
+		Config:      taskConfigFromProto(pb.Config),
+		State:       taskStateFromPB[pb.State],
+		DriverState: pb.DriverState,
+	}
+}
+
+func taskHandleToProto(handle *TaskHandle) *proto.TaskHandle {
+	return &proto.TaskHandle{
+		Version:     int32(handle.Version),
+		Config:      taskConfigToProto(handle.Config),
+		State:       taskStateToPB[handle.State],
+		DriverState: handle.DriverState,
+	}
+}
+
+func exitResultToProto(result *ExitResult) *proto.ExitResult {
+	if result == nil {
+		return &proto.ExitResult{}
+	}
+
+	return &proto.ExitResult{
+		ExitCode:  int32(result.ExitCode),
+		Signal:    int32(result.Signal),
+		OomKilled: result.OOMKilled,
+	}
+}
+
+func exitResultFromProto(pb *proto.ExitResult) *ExitResult {
+	if pb == nil {
+		return &ExitResult{}
+	}
+
+	return &ExitResult{
+		ExitCode:  int(pb.ExitCode),
+		Signal:    int(pb.Signal),
+		OOMKilled: pb.OomKilled,
+	}
+}
+
+func networkOverrideToProto(net *cstructs.DriverNetwork) *proto.NetworkOverride {
+	if net == nil {
+		return nil
+	}
+
+	pb := &proto.NetworkOverride{
+		PortMap:       make(map[string]int32, len(net.PortMap)),
+		Addr:          net.IP,
+		AutoAdvertise: net.AutoAdvertise,
+	}
+	for label, port := range net.PortMap {
+		pb.PortMap[label] = int32(port)
+	}
+
+	return pb
+}
+
+func networkOverrideFromProto(pb *proto.NetworkOverride) *cstructs.DriverNetwork {
+	if pb == nil {
+		return nil
+	}
+
+	net := &cstructs.DriverNetwork{
+		PortMap:       make(map[string]int, len(pb.PortMap)),
+		IP:            pb.Addr,
+		AutoAdvertise: pb.AutoAdvertise,
+	}
+	for label, port := range pb.PortMap {
+		net.PortMap[label] = int(port)
+	}
+
+	return net
+}
+
+func taskStatusToProto(status *TaskStatus) (*proto.TaskStatus, error) {
+	started, err := ptypes.TimestampProto(status.StartedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	pb := &proto.TaskStatus{
+		Id:        status.ID,
+		Name:      status.Name,
+		State:     taskStateToPB[status.State],
+		StartedAt: started,
+	}
+
+	if !status.CompletedAt.IsZero() {
+		completed, err := ptypes.TimestampProto(status.CompletedAt)
+		if err != nil {
+			return nil, err
+		}
+		pb.CompletedAt = completed
+	}
+
+	if status.ExitResult != nil {
+		pb.Result = exitResultToProto(status.ExitResult)
+	}
+
+	return pb, nil
+}
+
+func taskStatusFromProto(pb *proto.TaskStatus) (*TaskStatus, error) {
+	status := &TaskStatus{
+		ID:    pb.Id,
+		Name:  pb.Name,
+		State: taskStateFromPB[pb.State],
+	}
+
+	if pb.StartedAt != nil {
+		started, err := ptypes.Timestamp(pb.StartedAt)
+		if err != nil {
+			return nil, err
+		}
+		status.StartedAt = started
+	}
+
+	if pb.CompletedAt != nil {
+		completed, err := ptypes.Timestamp(pb.CompletedAt)
+		if err != nil {
+			return nil, err
+		}
+		status.CompletedAt = completed
+	}
+
+	if pb.Result != nil {
+		status.ExitResult = exitResultFromProto(pb.Result)
+	}
+
+	return status, nil
+}
+
+func taskStatsToProto(id string, usage *cstructs.TaskResourceUsage) (*proto.TaskStats, error) {
+	timestamp, err := ptypes.TimestampProto(time.Unix(0, usage.Timestamp))
+	if err != nil {
+		return nil, err
+	}
+
+	pb := &proto.TaskStats{
+		Id:                 id,
+		Timestamp:          timestamp,
+		AggResourceUsage:   resourceUsageToProto(usage.ResourceUsage),
+		ResourceUsageByPid: make(map[string]*proto.TaskResourceUsage, len(usage.Pids)),
+	}
+	for pid, ru := range usage.Pids {
+		pb.ResourceUsageByPid[pid] = resourceUsageToProto(ru)
+	}
+
+	return pb, nil
+}
+
+func taskStatsFromProto(pb *proto.TaskStats) (*cstructs.TaskResourceUsage, error) {
+	timestamp, err := ptypes.Timestamp(pb.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &cstructs.TaskResourceUsage{
+		Timestamp:     timestamp.UnixNano(),
+		ResourceUsage: resourceUsageFromProto(pb.AggResourceUsage),
+		Pids:          make(map[string]*cstructs.ResourceUsage, len(pb.ResourceUsageByPid)),
+	}
+	for pid, ru := range pb.ResourceUsageByPid {
+		usage.Pids[pid] = resourceUsageFromProto(ru)
+	}
+
+	return usage, nil
+}
+
+func resourceUsageToProto(ru *cstructs.ResourceUsage) *proto.TaskResourceUsage {
+	pb := &proto.TaskResourceUsage{}
+	if ru == nil {
+		return pb
+	}
+
+	if cpu := ru.CpuStats; cpu != nil {
+		pb.Cpu = &proto.CPUUsage{
+			SystemMode:       cpu.SystemMode,
+			UserMode:         cpu.UserMode,
+			TotalTicks:       cpu.TotalTicks,
+			ThrottledPeriods: cpu.ThrottledPeriods,
+			ThrottledTime:    cpu.ThrottledTime,
+			Percent:          cpu.Percent,
+		}
+		for _, field := range cpu.Measured {
+			if f, ok := cpuFieldsToPB[field]; ok {
+				pb.Cpu.MeasuredFields = append(pb.Cpu.MeasuredFields, f)
+			}
+		}
+	}
+
+	if memory := ru.MemoryStats; memory != nil {
+		pb.Memory = &proto.MemoryUsage{
+			Rss:            memory.RSS,
+			Cache:          memory.Cache,
+			MaxUsage:       memory.MaxUsage,
+			KernelUsage:    memory.KernelUsage,
+			KernelMaxUsage: memory.KernelMaxUsage,
+			Swap:           memory.Swap,
+		}
+		for _, field := range memory.Measured {
+			if f, ok := memoryFieldsToPB[field]; ok {
+				pb.Memory.MeasuredFields = append(pb.Memory.MeasuredFields, f)
+			}
+		}
+	}
+
+	return pb
+}
+
+func resourceUsageFromProto(pb *proto.TaskResourceUsage) *cstructs.ResourceUsage {
+	ru := &cstructs.ResourceUsage{
+		CpuStats:    &cstructs.CpuStats{},
+		MemoryStats: &cstructs.MemoryStats{},
+	}
+	if pb == nil {
+		return ru
+	}
+
+	if cpu := pb.Cpu; cpu != nil {
+		ru.CpuStats = &cstructs.CpuStats{
+			SystemMode:       cpu.SystemMode,
+			UserMode:         cpu.UserMode,
+			TotalTicks:       cpu.TotalTicks,
+			ThrottledPeriods: cpu.ThrottledPeriods,
+			ThrottledTime:    cpu.ThrottledTime,
+			Percent:          cpu.Percent,
+		}
+		for _, field := range cpu.MeasuredFields {
+			for name, f := range cpuFieldsToPB {
+				if f == field {
+					ru.CpuStats.Measured = append(ru.CpuStats.Measured, name)
+				}
+			}
+		}
+	}
+
+	if memory := pb.Memory; memory != nil {
+		ru.MemoryStats = &cstructs.MemoryStats{
+			RSS:            memory.Rss,
+			Cache:          memory.Cache,
+			MaxUsage:       memory.MaxUsage,
+			KernelUsage:    memory.KernelUsage,
+			KernelMaxUsage: memory.KernelMaxUsage,
+			Swap:           memory.Swap,
+		}
+		for _, field := range memory.MeasuredFields {
+			for name, f := range memoryFieldsToPB {
+				if f == field {
+					ru.MemoryStats.Measured = append(ru.MemoryStats.Measured, name)
+				}
+			}
+		}
+	}
+
+	return ru
+}
@@ -0,0 +1,53 @@
+package loader
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/device"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// supportedApiVersions is the set of plugin API versions the agent supports
+// for each type of plugin.
+var supportedApiVersions = map[string][]string{
+	base.PluginTypeDriver: {drivers.ApiVersion010},
+	base.PluginTypeDevice: {device.ApiVersion010},
+}
+
+// negotiateApiVersion returns the highest plugin API version supported by both
+// the agent and the plugin, given the versions the plugin supports.
+func negotiateApiVersion(pluginType string, pluginVersions []string) (string, error) {
+	supported, ok := supportedApiVersions[pluginType]
+	if !ok {
+		return "", fmt.Errorf("unsupported plugin type %q", pluginType)
+	}
+
+	var common []*version.Version
+	raw := make(map[*version.Version]string)
+	for _, pv := range pluginVersions {
+		for _, sv := range supported {
+			if pv != sv {
+				continue
+			}
+
+			v, err := version.NewVersion(pv)
+			if err != nil {
+				return "", fmt.Errorf("failed to parse plugin API version %q: %v", pv, err)
+			}
+			common = append(common, v)
+			raw[v] = pv
+		}
+	}
+
+	if len(common) == 0 {
+		return "", fmt.Errorf("no compatible %s plugin API version: plugin supports %q, agent supports %q",
+			pluginType, strings.Join(pluginVersions, ", "), strings.Join(supported, ", "))
+	}
+
+	sort.Sort(sort.Reverse(version.Collection(common)))
+	return raw[common[0]], nil
+}
@@ -0,0 +1,405 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	multierror "github.com/hashicorp/go-multierror"
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/shared"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	"github.com/zclconf/go-cty/cty/msgpack"
+
+	hcl2 "github.com/hashicorp/hcl2/hcl"
+)
+
+// validateConfig returns whether or not the configuration is valid
+func validateConfig(config *PluginLoaderConfig) error {
+	var mErr multierror.Error
+	if config == nil {
+		return fmt.Errorf("nil config passed")
+	} else if config.Logger == nil {
+		multierror.Append(&mErr, fmt.Errorf("nil logger passed"))
+	}
+
+	// Validate that all plugins have a binary name
+	for _, c := range config.Configs {
+		if c.Name == "" {
+			multierror.Append(&mErr, fmt.Errorf("plugin config passed without binary name"))
+		}
+	}
+
+	// Validate internal plugins
+	for k, config := range config.InternalPlugins {
+		// Validate config
+		if config == nil {
+			multierror.Append(&mErr, fmt.Errorf("nil config passed for internal plugin %s", k))
+			continue
+		} else if config.Factory == nil {
+			multierror.Append(&mErr, fmt.Errorf("nil factory passed for internal plugin %s", k))
+			continue
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// init initializes the plugin loader by compiling both internal and external
+// plugins and selecting the highest versioned version of any given plugin.
+func (l *PluginLoader) init(config *PluginLoaderConfig) error {
+	// Create a mapping of name to config
+	configMap := configMap(config.Configs)
+
+	// Initialize the internal plugins
+	internal, err := l.initInternal(config.InternalPlugins)
+	if err != nil {
+		return fmt.Errorf("failed to fingerprint internal plugins: %v", err)
+	}
+
+	// Scan for eligible binaries
+	plugins, err := l.scan()
+	if err != nil {
+		return fmt.Errorf("failed to scan plugin directory %q: %v", l.pluginDir, err)
+	}
+
+	// Validate that the configs refer to plugins
+	if err := validatePluginConfigs(configMap, plugins); err != nil {
+		return fmt.Errorf("invalid plugin configuration: %v", err)
+	}
+
+	// Fingerprint the passed plugins
+	external, err := l.fingerprintPlugins(plugins, configMap)
+	if err != nil {
+		return fmt.Errorf("failed to fingerprint plugins: %v", err)
+	}
+
+	// Merge external and internal plugins
+	l.plugins = l.mergePlugins(internal, external)
+	return nil
+}
+
+// initInternal initializes internal plugins.
+func (l *PluginLoader) initInternal(plugins map[PluginID]*InternalPluginConfig) (map[PluginID]*pluginInfo, error) {
+	var mErr multierror.Error
+	fingerprinted := make(map[PluginID]*pluginInfo, len(plugins))
+	for k, config := range plugins {
+		// Create an instance
+		raw := config.Factory(l.logger)
+		bplugin, ok := raw.(base.BasePlugin)
+		if !ok {
+			multierror.Append(&mErr, fmt.Errorf("internal plugin %s doesn't meet base plugin interface", k))
+			continue
+		}
+
+		info := &pluginInfo{
+			factory: config.Factory,
+		}
+
+		// Fingerprint base info
+		i, err := bplugin.PluginInfo()
+		if err != nil {
+			multierror.Append(&mErr, fmt.Errorf("PluginInfo info failed for internal plugin %s: %v", k, err))
+			continue
+		}
+		info.baseInfo = i
+
+		// Parse and set the plugin version
+		v, err := version.NewVersion(i.PluginVersion)
+		if err != nil {
+			multierror.Append(&mErr, fmt.Errorf("failed to parse version %q for internal plugin %s: %v", i.PluginVersion, k, err))
+			continue
+		}
+		info.version = v
+
+		// Negotiate the plugin API version
+		apiVersion, err := negotiateApiVersion(i.Type, i.PluginApiVersions)
+		if err != nil {
+			multierror.Append(&mErr, fmt.Errorf("internal plugin %s: %v", k, err))
+			continue
+		}
+		info.apiVersion = apiVersion
+
+		// Get the config schema and parse the config
+		if err := l.parseConfig(bplugin, info, config.Config); err != nil {
+			multierror.Append(&mErr, fmt.Errorf("internal plugin %s: %v", k, err))
+			continue
+		}
+
+		fingerprinted[k] = info
+	}
+
+	if err := mErr.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	return fingerprinted, nil
+}
+
+// scan scans the plugin directory and retrieves potentially eligible binaries
+func (l *PluginLoader) scan() ([]os.FileInfo, error) {
+	if l.pluginDir == "" {
+		return nil, nil
+	}
+
+	// Capture the list of binaries in the plugins folder
+	f, err := os.Open(l.pluginDir)
+	if err != nil {
+		// There are no plugins to scan
+		if os.IsNotExist(err) {
+			l.logger.Printf("[WARN] plugin_loader: skipping external plugins since plugin_dir %q doesn't exist", l.pluginDir)
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to open plugin directory %v: %v", l.pluginDir, err)
+	}
+	files, err := f.Readdirnames(-1)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin directory %v: %v", l.pluginDir, err)
+	}
+
+	var plugins []os.FileInfo
+	for _, f := range files {
+		f = filepath.Join(l.pluginDir, f)
+		s, err := os.Stat(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat file %q: %v", f, err)
+		}
+		if s.IsDir() {
+			l.logger.Printf("[DEBUG] plugin_loader: skipping subdir in plugin folder %q", f)
+			continue
+		}
+
+		if !executable(f, s) {
+			l.logger.Printf("[DEBUG] plugin_loader: skipping un-executable file in plugin folder %q", f)
+			continue
+		}
+		plugins = append(plugins, s)
+	}
+
+	return plugins, nil
+}
+
+// fingerprintPlugins fingerprints all external plugin binaries
+func (l *PluginLoader) fingerprintPlugins(plugins []os.FileInfo, configs map[string]*config.PluginConfig) (map[PluginID]*pluginInfo, error) {
+	var mErr multierror.Error
+	fingerprinted := make(map[PluginID]*pluginInfo, len(plugins))
+	for _, p := range plugins {
+		name := cleanPluginExecutable(p.Name())
+		c := configs[name]
+		info, err := l.fingerprintPlugin(p, c)
+		if err != nil {
+			l.logger.Printf("[ERR] plugin_loader: failed to fingerprint plugin %q: %v", p.Name(), err)
+			multierror.Append(&mErr, err)
+			continue
+		}
+		if info == nil {
+			// Plugin was skipped for validation reasons
+			continue
+		}
+
+		id := PluginID{
+			Name:       info.baseInfo.Name,
+			PluginType: info.baseInfo.Type,
+		}
+
+		// Detect if we already have seen a version of this plugin
+		if prev, ok := fingerprinted[id]; ok {
+			oldVersion := prev.version
+			selectedVersion := info.version
+			skip := false
+			if oldVersion.GreaterThan(selectedVersion) {
+				selectedVersion = oldVersion
+				skip = true
+			}
+			l.logger.Printf("[INFO] plugin_loader: multiple versions of plugin %s detected, selecting version %s", id, selectedVersion)
+
+			if skip {
+				continue
+			}
+		}
+
+		// Add the plugin
+		fingerprinted[id] = info
+	}
+
+	if err := mErr.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	return fingerprinted, nil
+}
+
+// fingerprintPlugin fingerprints the passed external plugin
+func (l *PluginLoader) fingerprintPlugin(pluginExe os.FileInfo, config *config.PluginConfig) (*pluginInfo, error) {
+	info := &pluginInfo{
+		exePath: filepath.Join(l.pluginDir, pluginExe.Name()),
+	}
+
+	// Build the command
+	if config != nil {
+		info.args = config.Args
+	}
+
+	// Launch the plugin
+	pluginClient := l.newPluginClient(info.exePath, info.args, nil)
+	defer pluginClient.Kill()
+
+	raw, err := dispense(pluginClient, base.PluginTypeBase)
+	if err != nil {
+		return nil, err
+	}
+
+	bplugin, ok := raw.(base.BasePlugin)
+	if !ok {
+		return nil, fmt.Errorf("unexpected base plugin type: %T", raw)
+	}
+
+	// Retrieve base plugin information
+	i, err := bplugin.PluginInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plugin info for plugin %q: %v", info.exePath, err)
+	}
+	info.baseInfo = i
+
+	// Parse and set the plugin version
+	v, err := version.NewVersion(i.PluginVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse plugin %q (%v) version %q: %v",
+			i.Name, info.exePath, i.PluginVersion, err)
+	}
+	info.version = v
+
+	// Negotiate the plugin API version. Plugins of a type the agent doesn't
+	// support are skipped rather than failing the agent.
+	if _, ok := supportedApiVersions[i.Type]; !ok {
+		l.logger.Printf("[WARN] plugin_loader: skipping plugin %q (%v) of unsupported type %q", i.Name, info.exePath, i.Type)
+		return nil, nil
+	}
+	apiVersion, err := negotiateApiVersion(i.Type, i.PluginApiVersions)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %q (%v): %v", i.Name, info.exePath, err)
+	}
+	info.apiVersion = apiVersion
+
+	// Get the config schema and parse the config
+	var c map[string]interface{}
+	if config != nil {
+		c = config.Config
+	}
+	if err := l.parseConfig(bplugin, info, c); err != nil {
+		return nil, fmt.Errorf("plugin %q (%v): %v", i.Name, info.exePath, err)
+	}
+
+	return info, nil
+}
+
+// parseConfig validates the configuration of a plugin against its schema and
+// stores its encoding in the plugin info, to be passed to SetConfig.
+func (l *PluginLoader) parseConfig(plugin base.BasePlugin, info *pluginInfo, config map[string]interface{}) error {
+	spec, err := plugin.ConfigSchema()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve config schema: %v", err)
+	}
+	if spec == nil {
+		if len(config) != 0 {
+			return fmt.Errorf("plugin doesn't accept a config but one was given")
+		}
+		return nil
+	}
+
+	data, err := encodeConfig(spec, config)
+	if err != nil {
+		return err
+	}
+	info.config = data
+	return nil
+}
+
+// encodeConfig validates the configuration of a plugin against its schema and
+// encodes it to be passed to SetConfig.
+func encodeConfig(spec *hclspec.Spec, config map[string]interface{}) ([]byte, error) {
+	// Convert the schema to hcl
+	hclSpec, diag := hclspec.Convert(spec)
+	if diag.HasErrors() {
+		return nil, multierror.Prefix(diag, "failed to convert config schema:")
+	}
+
+	// Parse the config, using the defaults of the schema for a missing
+	// config
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	ctx := &hcl2.EvalContext{
+		Functions: shared.GetStdlibFuncs(),
+	}
+	val, diag := shared.ParseHclInterface(config, hclSpec, ctx)
+	if diag.HasErrors() {
+		return nil, multierror.Prefix(diag, "failed to parse config:")
+	}
+
+	cdata, err := msgpack.Marshal(val, val.Type())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %v", err)
+	}
+	return cdata, nil
+}
+
+// mergePlugins merges internal and external plugins, preferring the highest
+// version. Internal plugins are preferred when the versions are equal.
+func (l *PluginLoader) mergePlugins(internal, external map[PluginID]*pluginInfo) map[PluginID]*pluginInfo {
+	finalized := make(map[PluginID]*pluginInfo, len(internal))
+
+	// Load the internal plugins
+	for k, v := range internal {
+		finalized[k] = v
+	}
+
+	for k, extPlugin := range external {
+		internal, ok := finalized[k]
+		if ok {
+			// We have overlapping plugins, determine if we should keep the
+			// internal version or override
+			if extPlugin.version.LessThan(internal.version) ||
+				extPlugin.version.Equal(internal.version) {
+				l.logger.Printf("[INFO] plugin_loader: preferring internal version of plugin %s (internal %s, external %s)",
+					k, internal.version, extPlugin.version)
+				continue
+			}
+		}
+
+		// Add external plugin
+		finalized[k] = extPlugin
+	}
+
+	return finalized
+}
+
+// validatePluginConfigs validates that every plugin block of the agent
+// configuration refers to a plugin binary of the plugin directory.
+func validatePluginConfigs(configs map[string]*config.PluginConfig, plugins []os.FileInfo) error {
+	found := make(map[string]struct{}, len(plugins))
+	for _, p := range plugins {
+		found[cleanPluginExecutable(p.Name())] = struct{}{}
+	}
+
+	var mErr multierror.Error
+	for name := range configs {
+		if _, ok := found[name]; !ok {
+			multierror.Append(&mErr, fmt.Errorf("plugin %q is configured but no executable was found in the plugin directory", name))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// configMap returns a mapping of plugin binary name to config.
+func configMap(configs []*config.PluginConfig) map[string]*config.PluginConfig {
+	pluginConfigs := make(map[string]*config.PluginConfig, len(configs))
+	for _, p := range configs {
+		pluginConfigs[p.Name] = p
+	}
+	return pluginConfigs
+}
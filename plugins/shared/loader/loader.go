@@ -0,0 +1,297 @@
+package loader
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"sort"
+
+	hclog "github.com/hashicorp/go-hclog"
+	plugin "github.com/hashicorp/go-plugin"
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/device"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// PluginCatalog is used to retrieve plugins, either external or internal
+type PluginCatalog interface {
+	// Dispense returns the plugin given its name and type. This will also
+	// configure the plugin with its agent configuration and the negotiated
+	// plugin API version.
+	Dispense(name, pluginType string, logger *log.Logger) (PluginInstance, error)
+
+	// Reattach is used to reattach to a previously launched external plugin.
+	// The plugin is not configured again.
+	Reattach(name, pluginType string, config *plugin.ReattachConfig) (PluginInstance, error)
+
+	// Catalog returns the catalog of all plugins keyed by plugin type
+	Catalog() map[string][]*base.PluginInfoResponse
+}
+
+// PluginID is a tuple identifying a plugin
+type PluginID struct {
+	// Name is the name of the plugin
+	Name string
+
+	// PluginType is the plugin's type
+	PluginType string
+}
+
+// String returns a friendly representation of the plugin.
+func (id PluginID) String() string {
+	return fmt.Sprintf("%q (%v)", id.Name, id.PluginType)
+}
+
+// InternalPluginConfig is used to configure launching an internal plugin.
+type InternalPluginConfig struct {
+	// Config is the configuration of the plugin, equivalent to the config
+	// of a plugin block.
+	Config map[string]interface{}
+
+	// Factory returns a new instance of the plugin.
+	Factory PluginFactory
+}
+
+// PluginFactory returns a new instance of an internal plugin.
+type PluginFactory func(logger *log.Logger) interface{}
+
+// PluginLoaderConfig configures a plugin loader.
+type PluginLoaderConfig struct {
+	// Logger is the logger used by the plugin loader
+	Logger *log.Logger
+
+	// LogOutput and LogLevel configure the logging of the plugin processes,
+	// whose output is forwarded to the agent's logs.
+	LogOutput io.Writer
+	LogLevel  string
+
+	// PluginDir is the directory scanned for plugins
+	PluginDir string
+
+	// Configs is an optional set of configs for plugins
+	Configs []*config.PluginConfig
+
+	// InternalPlugins allows registering internal plugins.
+	InternalPlugins map[PluginID]*InternalPluginConfig
+
+	// MinPort and MaxPort are the range of ports plugins may listen on.
+	MinPort uint
+	MaxPort uint
+}
+
+// PluginLoader is used to retrieve plugins either externally or from internal
+// factories.
+type PluginLoader struct {
+	// logger is the plugin loaders logger
+	logger *log.Logger
+
+	// pluginLogger is the logger given to go-plugin to forward the output of
+	// the plugin processes.
+	pluginLogger hclog.Logger
+
+	// pluginDir is the directory containing plugin binaries
+	pluginDir string
+
+	// minPort and maxPort are the ports plugins may listen on
+	minPort uint
+	maxPort uint
+
+	// plugins maps a plugin to information required to launch it
+	plugins map[PluginID]*pluginInfo
+}
+
+// pluginInfo captures the necessary information to launch and configure a
+// plugin.
+type pluginInfo struct {
+	baseInfo *base.PluginInfoResponse
+	version  *version.Version
+
+	// apiVersion is the plugin API version negotiated with the plugin
+	apiVersion string
+
+	// exePath and args are used to launch external plugins
+	exePath string
+	args    []string
+
+	// factory is used to create internal plugins
+	factory PluginFactory
+
+	// config is the plugin's configuration encoded using its schema
+	config []byte
+}
+
+// NewPluginLoader returns an instance of a plugin loader or an error if the
+// plugins could not be loaded.
+func NewPluginLoader(config *PluginLoaderConfig) (*PluginLoader, error) {
+	if err := validateConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid plugin loader configuration passed: %v", err)
+	}
+
+	l := &PluginLoader{
+		logger: config.Logger,
+		pluginLogger: hclog.New(&hclog.LoggerOptions{
+			Name:   "plugin",
+			Output: config.LogOutput,
+			Level:  hclog.LevelFromString(config.LogLevel),
+		}),
+		pluginDir: config.PluginDir,
+		minPort:   config.MinPort,
+		maxPort:   config.MaxPort,
+		plugins:   make(map[PluginID]*pluginInfo),
+	}
+
+	if err := l.init(config); err != nil {
+		return nil, fmt.Errorf("failed to initialize plugin loader: %v", err)
+	}
+
+	return l, nil
+}
+
+// Dispense returns a plugin instance, launching it if it is external and
+// configuring it.
+func (l *PluginLoader) Dispense(name, pluginType string, logger *log.Logger) (PluginInstance, error) {
+	id := PluginID{
+		Name:       name,
+		PluginType: pluginType,
+	}
+	pinfo, ok := l.plugins[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown plugin with name %q and type %q", name, pluginType)
+	}
+
+	// If the plugin is internal, launch via the factory
+	var instance PluginInstance
+	if pinfo.factory != nil {
+		instance = &internalPluginInstance{
+			instance:   pinfo.factory(logger),
+			apiVersion: pinfo.apiVersion,
+		}
+	} else {
+		var err error
+		instance, err = l.dispensePlugin(pinfo, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to launch plugin: %v", err)
+		}
+	}
+
+	// Cast to the base type and set the config
+	b, ok := instance.Plugin().(base.BasePlugin)
+	if !ok {
+		instance.Kill()
+		return nil, fmt.Errorf("plugin %s doesn't implement base plugin interface", id)
+	}
+
+	c := &base.Config{
+		PluginConfig: pinfo.config,
+		ApiVersion:   pinfo.apiVersion,
+	}
+	if err := b.SetConfig(c); err != nil {
+		instance.Kill()
+		return nil, fmt.Errorf("setting config for plugin %s failed: %v", id, err)
+	}
+
+	return instance, nil
+}
+
+// Reattach reattaches to a previously launched external plugin.
+func (l *PluginLoader) Reattach(name, pluginType string, config *plugin.ReattachConfig) (PluginInstance, error) {
+	id := PluginID{
+		Name:       name,
+		PluginType: pluginType,
+	}
+	pinfo, ok := l.plugins[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown plugin with name %q and type %q", name, pluginType)
+	}
+	if pinfo.factory != nil {
+		return nil, fmt.Errorf("plugin %s is internal and can not be reattached", id)
+	}
+
+	return l.dispensePlugin(pinfo, config)
+}
+
+// dispensePlugin is used to launch or reattach to an external plugin.
+func (l *PluginLoader) dispensePlugin(pinfo *pluginInfo, reattach *plugin.ReattachConfig) (PluginInstance, error) {
+	pluginType := pinfo.baseInfo.Type
+	pluginClient := l.newPluginClient(pinfo.exePath, pinfo.args, reattach)
+
+	raw, err := dispense(pluginClient, pluginType)
+	if err != nil {
+		// Don't kill a process we reattached to but failed to talk to, it
+		// may still be running tasks.
+		if reattach == nil {
+			pluginClient.Kill()
+		}
+		return nil, err
+	}
+
+	return &externalPluginInstance{
+		client:     pluginClient,
+		instance:   raw,
+		apiVersion: pinfo.apiVersion,
+	}, nil
+}
+
+// newPluginClient returns a go-plugin client that either launches the plugin
+// binary or reattaches to an already running plugin.
+func (l *PluginLoader) newPluginClient(path string, args []string, reattach *plugin.ReattachConfig) *plugin.Client {
+	config := &plugin.ClientConfig{
+		HandshakeConfig:  base.Handshake,
+		Plugins:          getPluginMap(),
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		MinPort:          l.minPort,
+		MaxPort:          l.maxPort,
+		Logger:           l.pluginLogger,
+	}
+
+	if reattach != nil {
+		config.Reattach = reattach
+	} else {
+		cmd := exec.Command(path, args...)
+		isolateCommand(cmd)
+		config.Cmd = cmd
+	}
+
+	return plugin.NewClient(config)
+}
+
+// dispense returns the plugin of the given type served by the plugin process.
+func dispense(pluginClient *plugin.Client, pluginType string) (interface{}, error) {
+	rpcClient, err := pluginClient.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := rpcClient.Dispense(pluginType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dispense %s plugin: %v", pluginType, err)
+	}
+
+	return raw, nil
+}
+
+// getPluginMap returns the set of plugins the agent can dispense.
+func getPluginMap() map[string]plugin.Plugin {
+	return map[string]plugin.Plugin{
+		base.PluginTypeBase:   &base.PluginBase{},
+		base.PluginTypeDriver: &drivers.PluginDriver{},
+		base.PluginTypeDevice: &device.PluginDevice{},
+	}
+}
+
+// Catalog returns the catalog of all plugins, sorted by name
+func (l *PluginLoader) Catalog() map[string][]*base.PluginInfoResponse {
+	c := make(map[string][]*base.PluginInfoResponse, 3)
+	for id, info := range l.plugins {
+		c[id.PluginType] = append(c[id.PluginType], info.baseInfo)
+	}
+	for _, infos := range c {
+		sort.Slice(infos, func(i, j int) bool {
+			return infos[i].Name < infos[j].Name
+		})
+	}
+	return c
+}
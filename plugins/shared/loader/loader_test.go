@@ -0,0 +1,193 @@
+package loader
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/msgpack"
+)
+
+var (
+	// testSpec is the config schema of the test plugins
+	testSpec = &hclspec.Spec{
+		Block: &hclspec.Spec_Object{
+			Object: &hclspec.Object{
+				Attributes: map[string]*hclspec.Spec{
+					"foo": {
+						Block: &hclspec.Spec_Attr{
+							Attr: &hclspec.Attr{
+								Type:     "string",
+								Required: false,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testSpecType = cty.Object(map[string]cty.Type{
+		"foo": cty.String,
+	})
+)
+
+// mockFactory returns a factory of mock driver plugins supporting the given
+// plugin API versions. The configs the plugins receive are sent on configCh.
+func mockFactory(name, pluginVersion string, apiVersions []string, configCh chan *base.Config) PluginFactory {
+	return func(*log.Logger) interface{} {
+		return &drivers.MockDriver{
+			MockPlugin: base.MockPlugin{
+				PluginInfoF: func() (*base.PluginInfoResponse, error) {
+					return &base.PluginInfoResponse{
+						Type:              base.PluginTypeDriver,
+						PluginApiVersions: apiVersions,
+						PluginVersion:     pluginVersion,
+						Name:              name,
+					}, nil
+				},
+				ConfigSchemaF: func() (*hclspec.Spec, error) {
+					return testSpec, nil
+				},
+				SetConfigF: func(c *base.Config) error {
+					if configCh != nil {
+						configCh <- c
+					}
+					return nil
+				},
+			},
+		}
+	}
+}
+
+func TestPluginLoader_Internal(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	configCh := make(chan *base.Config, 1)
+	id := PluginID{Name: "mock", PluginType: base.PluginTypeDriver}
+	l, err := NewPluginLoader(&PluginLoaderConfig{
+		Logger: testlog.Logger(t),
+		InternalPlugins: map[PluginID]*InternalPluginConfig{
+			id: {
+				Config: map[string]interface{}{
+					"foo": "bar",
+				},
+				Factory: mockFactory("mock", "v0.2.0", []string{"v0.0.1", drivers.ApiVersion010}, configCh),
+			},
+		},
+	})
+	require.NoError(err)
+
+	// Check the catalog
+	catalog := l.Catalog()
+	require.Len(catalog[base.PluginTypeDriver], 1)
+	require.Equal("mock", catalog[base.PluginTypeDriver][0].Name)
+	require.Equal("v0.2.0", catalog[base.PluginTypeDriver][0].PluginVersion)
+
+	// Dispensing configures the plugin with the negotiated version
+	instance, err := l.Dispense("mock", base.PluginTypeDriver, testlog.Logger(t))
+	require.NoError(err)
+	require.True(instance.Internal())
+	require.False(instance.Exited())
+	require.Equal(drivers.ApiVersion010, instance.ApiVersion())
+
+	_, ok := instance.Plugin().(drivers.DriverPlugin)
+	require.True(ok)
+
+	c := <-configCh
+	require.Equal(drivers.ApiVersion010, c.ApiVersion)
+	val, err := msgpack.Unmarshal(c.PluginConfig, testSpecType)
+	require.NoError(err)
+	require.Equal("bar", val.GetAttr("foo").AsString())
+
+	// Internal plugins can't be reattached
+	_, err = l.Reattach("mock", base.PluginTypeDriver, nil)
+	require.Error(err)
+
+	// Unknown plugins can't be dispensed
+	_, err = l.Dispense("foo", base.PluginTypeDriver, testlog.Logger(t))
+	require.Error(err)
+}
+
+func TestPluginLoader_Internal_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	id := PluginID{Name: "mock", PluginType: base.PluginTypeDriver}
+	_, err := NewPluginLoader(&PluginLoaderConfig{
+		Logger: testlog.Logger(t),
+		InternalPlugins: map[PluginID]*InternalPluginConfig{
+			id: {
+				Config: map[string]interface{}{
+					"bar": "baz",
+				},
+				Factory: mockFactory("mock", "v0.1.0", []string{drivers.ApiVersion010}, nil),
+			},
+		},
+	})
+	require.Error(t, err)
+}
+
+func TestPluginLoader_Internal_UnsupportedApiVersion(t *testing.T) {
+	t.Parallel()
+
+	id := PluginID{Name: "mock", PluginType: base.PluginTypeDriver}
+	_, err := NewPluginLoader(&PluginLoaderConfig{
+		Logger: testlog.Logger(t),
+		InternalPlugins: map[PluginID]*InternalPluginConfig{
+			id: {
+				Factory: mockFactory("mock", "v0.1.0", []string{"v0.0.1"}, nil),
+			},
+		},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no compatible")
+}
+
+func TestPluginLoader_ConfigWithoutPlugin(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-plugins")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// Non executable files are not plugins
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "foo"), []byte("foo"), 0644))
+
+	_, err = NewPluginLoader(&PluginLoaderConfig{
+		Logger:    testlog.Logger(t),
+		PluginDir: dir,
+		Configs: []*config.PluginConfig{
+			{
+				Name: "foo",
+			},
+		},
+	})
+	require.Error(err)
+	require.Contains(err.Error(), "no executable was found")
+}
+
+func TestNegotiateApiVersion(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	v, err := negotiateApiVersion(base.PluginTypeDriver, []string{"v0.0.1", drivers.ApiVersion010, "v9.0.0"})
+	require.NoError(err)
+	require.Equal(drivers.ApiVersion010, v)
+
+	_, err = negotiateApiVersion(base.PluginTypeDriver, []string{"v9.0.0"})
+	require.Error(err)
+
+	_, err = negotiateApiVersion("foo", []string{drivers.ApiVersion010})
+	require.Error(err)
+}
@@ -0,0 +1,60 @@
+package loader
+
+import (
+	"fmt"
+	"net"
+
+	plugin "github.com/hashicorp/go-plugin"
+)
+
+// ReattachConfig is the serializable form of go-plugin's ReattachConfig,
+// allowing the agent to persist how to reattach to a running plugin.
+type ReattachConfig struct {
+	Protocol string
+	Network  string
+	Addr     string
+	Pid      int
+}
+
+// NewReattachConfig returns the serializable form of a go-plugin
+// ReattachConfig.
+func NewReattachConfig(c *plugin.ReattachConfig) *ReattachConfig {
+	if c == nil {
+		return nil
+	}
+
+	return &ReattachConfig{
+		Protocol: string(c.Protocol),
+		Network:  c.Addr.Network(),
+		Addr:     c.Addr.String(),
+		Pid:      c.Pid,
+	}
+}
+
+// PluginConfig returns the go-plugin ReattachConfig.
+func (c *ReattachConfig) PluginConfig() (*plugin.ReattachConfig, error) {
+	var addr net.Addr
+	var err error
+	switch c.Network {
+	case "unix", "unixgram", "unixpacket":
+		addr, err = net.ResolveUnixAddr(c.Network, c.Addr)
+	case "tcp", "tcp4", "tcp6":
+		addr, err = net.ResolveTCPAddr(c.Network, c.Addr)
+	default:
+		return nil, fmt.Errorf("unknown network type %q", c.Network)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	protocol := plugin.Protocol(c.Protocol)
+	if protocol == "" {
+		protocol = plugin.ProtocolGRPC
+	}
+
+	return &plugin.ReattachConfig{
+		Protocol: protocol,
+		Addr:     addr,
+		Pid:      c.Pid,
+	}, nil
+}
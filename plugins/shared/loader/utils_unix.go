@@ -0,0 +1,29 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package loader
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// isolateCommand sets the setsid flag in exec.Cmd to true so that the plugin
+// becomes the process leader in a new session and doesn't receive signals that
+// are sent to the agent.
+func isolateCommand(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+}
+
+// executable returns whether the file can be launched as a plugin.
+func executable(path string, f os.FileInfo) bool {
+	return f.Mode().Perm()&0111 != 0
+}
+
+// cleanPluginExecutable returns the name of the plugin given its executable.
+func cleanPluginExecutable(name string) string {
+	return name
+}
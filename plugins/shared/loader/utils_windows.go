@@ -0,0 +1,22 @@
+package loader
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// isolateCommand is a noop on Windows
+func isolateCommand(cmd *exec.Cmd) {}
+
+// executable returns whether the file can be launched as a plugin.
+func executable(path string, f os.FileInfo) bool {
+	return strings.EqualFold(filepath.Ext(path), ".exe")
+}
+
+// cleanPluginExecutable returns the name of the plugin given its executable,
+// removing the .exe extension.
+func cleanPluginExecutable(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
@@ -165,6 +165,204 @@ func (c *DriverChecker) hasDrivers(option *structs.Node) bool {
 	return true
 }
 
+// HostVolumeChecker is a FeasibilityChecker which returns whether a node has
+// the host volumes necessary to schedule a task group.
+type HostVolumeChecker struct {
+	ctx     Context
+	volumes map[string]*structs.VolumeRequest
+}
+
+// NewHostVolumeChecker creates a HostVolumeChecker
+func NewHostVolumeChecker(ctx Context) *HostVolumeChecker {
+	return &HostVolumeChecker{
+		ctx: ctx,
+	}
+}
+
+// SetVolumes takes the volumes required by a task group and keeps the host
+// volumes.
+func (h *HostVolumeChecker) SetVolumes(volumes map[string]*structs.VolumeRequest) {
+	h.volumes = make(map[string]*structs.VolumeRequest, len(volumes))
+	for name, req := range volumes {
+		if req.Type == structs.VolumeTypeHost {
+			h.volumes[name] = req
+		}
+	}
+}
+
+func (h *HostVolumeChecker) Feasible(candidate *structs.Node) bool {
+	if h.hasVolumes(candidate) {
+		return true
+	}
+
+	h.ctx.Metrics().FilterNode(candidate, "missing compatible host volumes")
+	return false
+}
+
+// hasVolumes is used to check if the node has all the host volumes requested
+// by the task group. Writable volumes can't be placed on read only host
+// volumes.
+func (h *HostVolumeChecker) hasVolumes(n *structs.Node) bool {
+	for _, req := range h.volumes {
+		vol, ok := n.HostVolumes[req.Source]
+		if !ok || vol == nil {
+			return false
+		}
+		if vol.ReadOnly && !req.ReadOnly {
+			return false
+		}
+	}
+	return true
+}
+
+// CSIVolumeIterator is a FeasibleIterator which returns nodes that can run
+// the node plugin of the CSI volumes requested by a task group, where the
+// volumes can still be claimed. As claims change with every placement, it
+// can't be cached by computed node class.
+type CSIVolumeIterator struct {
+	ctx       Context
+	source    FeasibleIterator
+	namespace string
+	volumes   map[string]*structs.VolumeRequest
+}
+
+// NewCSIVolumeIterator creates a CSIVolumeIterator from a source.
+func NewCSIVolumeIterator(ctx Context, source FeasibleIterator) *CSIVolumeIterator {
+	return &CSIVolumeIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *CSIVolumeIterator) SetJob(job *structs.Job) {
+	iter.namespace = job.Namespace
+}
+
+// SetTaskGroup takes the volumes requested by the task group and keeps the
+// CSI volumes.
+func (iter *CSIVolumeIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.volumes = make(map[string]*structs.VolumeRequest)
+	for name, req := range tg.Volumes {
+		if req.Type == structs.VolumeTypeCSI {
+			iter.volumes[name] = req
+		}
+	}
+}
+
+func (iter *CSIVolumeIterator) Next() *structs.Node {
+	for {
+		// Get the next option from the source
+		option := iter.source.Next()
+
+		// Hot-path if the option is nil or there are no CSI volumes
+		if option == nil || len(iter.volumes) == 0 {
+			return option
+		}
+
+		if reason := iter.feasible(option); reason != "" {
+			iter.ctx.Metrics().FilterNode(option, reason)
+			continue
+		}
+
+		return option
+	}
+}
+
+// feasible returns the reason the node can't claim all the requested
+// volumes, or an empty string if it can.
+func (iter *CSIVolumeIterator) feasible(n *structs.Node) string {
+	for _, req := range iter.volumes {
+		vol, err := iter.ctx.State().CSIVolumeByID(nil, iter.namespace, req.Source)
+		if err != nil {
+			iter.ctx.Logger().Printf(
+				"[ERR] scheduler.csi_volumes: failed to lookup volume %q: %v", req.Source, err)
+			return "CSI volume lookup failed"
+		}
+		if vol == nil {
+			return fmt.Sprintf("missing CSI volume %s", req.Source)
+		}
+
+		mode := structs.CSIVolumeClaimRead
+		if !req.ReadOnly {
+			mode = structs.CSIVolumeClaimWrite
+		}
+		if mode == structs.CSIVolumeClaimWrite && !vol.WriteSchedulable() ||
+			mode == structs.CSIVolumeClaimRead && !vol.ReadSchedulable() {
+			return fmt.Sprintf("CSI volume %s is unschedulable", vol.ID)
+		}
+
+		plugin, ok := n.CSINodePlugins[vol.PluginID]
+		if !ok || !plugin.Healthy {
+			return fmt.Sprintf("CSI plugin %s is missing from client", vol.PluginID)
+		}
+
+		// Add the claims of the allocations already placed by the plan
+		vol = vol.Copy()
+		for nodeID, allocs := range iter.ctx.Plan().NodeAllocation {
+			for _, alloc := range allocs {
+				if claim := iter.plannedClaim(alloc, vol.ID); claim != nil {
+					claim.NodeID = nodeID
+					vol.Claim(claim)
+				}
+			}
+		}
+
+		claim := &structs.CSIVolumeClaim{NodeID: n.ID, Mode: mode}
+		if err := vol.ClaimAllowed(claim, iter.freeClaim); err != nil {
+			return fmt.Sprintf("CSI volume %s has exhausted its available claims", vol.ID)
+		}
+	}
+	return ""
+}
+
+// plannedClaim returns the claim the planned allocation will make on the
+// volume, if any.
+func (iter *CSIVolumeIterator) plannedClaim(alloc *structs.Allocation, volID string) *structs.CSIVolumeClaim {
+	job := alloc.Job
+	if job == nil {
+		job = iter.ctx.Plan().Job
+	}
+	if job == nil || job.Namespace != iter.namespace {
+		return nil
+	}
+	tg := job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return nil
+	}
+
+	for _, req := range tg.Volumes {
+		if req.Type != structs.VolumeTypeCSI || req.Source != volID {
+			continue
+		}
+		mode := structs.CSIVolumeClaimWrite
+		if req.ReadOnly {
+			mode = structs.CSIVolumeClaimRead
+		}
+		return &structs.CSIVolumeClaim{AllocationID: alloc.ID, Mode: mode}
+	}
+	return nil
+}
+
+// freeClaim returns whether the claim is held by an allocation that is gone,
+// stopped or being stopped by the plan.
+func (iter *CSIVolumeIterator) freeClaim(claim *structs.CSIVolumeClaim) bool {
+	for _, alloc := range iter.ctx.Plan().NodeUpdate[claim.NodeID] {
+		if alloc.ID == claim.AllocationID {
+			return true
+		}
+	}
+
+	alloc, err := iter.ctx.State().AllocByID(nil, claim.AllocationID)
+	if err != nil {
+		return false
+	}
+	return alloc == nil || alloc.TerminalStatus()
+}
+
+func (iter *CSIVolumeIterator) Reset() {
+	iter.source.Reset()
+}
+
 // DistinctHostsIterator is a FeasibleIterator which returns nodes that pass the
 // distinct_hosts constraint. The constraint ensures that multiple allocations
 // do not exist on the same node.
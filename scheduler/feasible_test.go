@@ -127,6 +127,152 @@ func TestDriverChecker(t *testing.T) {
 	}
 }
 
+func TestHostVolumeChecker(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+	}
+	nodes[1].HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"foo": {Name: "foo", Path: "/tmp/foo"},
+	}
+	nodes[2].HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"foo": {Name: "foo", Path: "/tmp/foo", ReadOnly: true},
+	}
+
+	writable := map[string]*structs.VolumeRequest{
+		"data": {Name: "data", Type: structs.VolumeTypeHost, Source: "foo"},
+	}
+	readOnly := map[string]*structs.VolumeRequest{
+		"data": {Name: "data", Type: structs.VolumeTypeHost, Source: "foo", ReadOnly: true},
+	}
+
+	checker := NewHostVolumeChecker(ctx)
+	cases := []struct {
+		Node    *structs.Node
+		Volumes map[string]*structs.VolumeRequest
+		Result  bool
+	}{
+		{
+			Node:    nodes[0],
+			Volumes: nil,
+			Result:  true,
+		},
+		{
+			Node:    nodes[0],
+			Volumes: writable,
+			Result:  false,
+		},
+		{
+			Node:    nodes[1],
+			Volumes: writable,
+			Result:  true,
+		},
+		{
+			Node:    nodes[2],
+			Volumes: writable,
+			Result:  false,
+		},
+		{
+			Node:    nodes[2],
+			Volumes: readOnly,
+			Result:  true,
+		},
+	}
+
+	for i, c := range cases {
+		checker.SetVolumes(c.Volumes)
+		if act := checker.Feasible(c.Node); act != c.Result {
+			t.Fatalf("case(%d) failed: got %v; want %v", i, act, c.Result)
+		}
+	}
+}
+
+func TestCSIVolumeIterator(t *testing.T) {
+	require := require.New(t)
+	state, ctx := testContext(t)
+
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.CSINode(),
+		mock.CSINode(),
+	}
+	for i, node := range nodes {
+		require.NoError(state.UpsertNode(uint64(100+i), node))
+	}
+
+	vol := mock.CSIVolume()
+	require.NoError(state.CSIVolumeRegister(200, []*structs.CSIVolume{vol}))
+
+	// The volume is claimed for writing by an allocation on the last node
+	alloc := mock.Alloc()
+	alloc.NodeID = nodes[2].ID
+	require.NoError(state.UpsertJob(201, alloc.Job))
+	require.NoError(state.UpsertAllocs(202, []*structs.Allocation{alloc}))
+	claim := &structs.CSIVolumeClaim{AllocationID: alloc.ID, NodeID: nodes[2].ID, Mode: structs.CSIVolumeClaimWrite}
+	require.NoError(state.CSIVolumeClaim(203, vol.Namespace, vol.ID, claim))
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+
+	cases := []struct {
+		Name    string
+		Volumes map[string]*structs.VolumeRequest
+		Result  []*structs.Node
+	}{
+		{
+			Name:   "no volumes",
+			Result: nodes,
+		},
+		{
+			Name: "missing volume",
+			Volumes: map[string]*structs.VolumeRequest{
+				"data": {Name: "data", Type: structs.VolumeTypeCSI, Source: "missing"},
+			},
+			Result: nil,
+		},
+		{
+			// Only the writer's node can read a single node volume
+			Name: "read",
+			Volumes: map[string]*structs.VolumeRequest{
+				"data": {Name: "data", Type: structs.VolumeTypeCSI, Source: vol.ID, ReadOnly: true},
+			},
+			Result: []*structs.Node{nodes[2]},
+		},
+		{
+			Name: "write",
+			Volumes: map[string]*structs.VolumeRequest{
+				"data": {Name: "data", Type: structs.VolumeTypeCSI, Source: vol.ID},
+			},
+			Result: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			tg.Volumes = c.Volumes
+			iter := NewCSIVolumeIterator(ctx, NewStaticIterator(ctx, nodes))
+			iter.SetJob(job)
+			iter.SetTaskGroup(tg)
+			require.Equal(c.Result, collectFeasible(iter))
+		})
+	}
+
+	// Once the claim is released the volume can be written from any node
+	// running the plugin
+	release := &structs.CSIVolumeClaim{AllocationID: alloc.ID, Mode: structs.CSIVolumeClaimRelease}
+	require.NoError(state.CSIVolumeClaim(204, vol.Namespace, vol.ID, release))
+
+	tg.Volumes = map[string]*structs.VolumeRequest{
+		"data": {Name: "data", Type: structs.VolumeTypeCSI, Source: vol.ID},
+	}
+	iter := NewCSIVolumeIterator(ctx, NewStaticIterator(ctx, nodes))
+	iter.SetJob(job)
+	iter.SetTaskGroup(tg)
+	require.Equal([]*structs.Node{nodes[1], nodes[2]}, collectFeasible(iter))
+}
+
 func Test_HealthChecks(t *testing.T) {
 	require := require.New(t)
 	_, ctx := testContext(t)
@@ -87,6 +87,13 @@ type State interface {
 	// LatestDeploymentByJobID returns the latest deployment matching the given
 	// job ID
 	LatestDeploymentByJobID(ws memdb.WatchSet, namespace, jobID string) (*structs.Deployment, error)
+
+	// AllocByID is used to lookup an allocation by ID
+	AllocByID(ws memdb.WatchSet, id string) (*structs.Allocation, error)
+
+	// CSIVolumeByID is used to lookup a CSI volume along with the health of
+	// its plugin
+	CSIVolumeByID(ws memdb.WatchSet, namespace, id string) (*structs.CSIVolume, error)
 }
 
 // Planner interface is used to submit a task allocation plan.